@@ -159,6 +159,7 @@ const docTemplate = `{
         },
         "/api/v1/auth/account": {
             "patch": {
+                "description": "Changing the password ends every session of the account, including the current one.",
                 "produces": [
                     "application/json"
                 ],
@@ -189,163 +190,167 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/auth/login": {
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/auth/feed-token": {
+            "get": {
+                "description": "Tell whether the logged in account has a private feed token. Its value is never returned.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Auth"
                 ],
-                "summary": "Login to an account using username and password",
-                "parameters": [
-                    {
-                        "description": "Login data",
-                        "name": "payload",
-                        "in": "body",
-                        "schema": {
-                            "$ref": "#/definitions/api_v1.loginRequestPayload"
-                        }
-                    }
-                ],
+                "summary": "Get the private feed token",
                 "responses": {
                     "200": {
-                        "description": "Login successful",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_v1.loginResponseMessage"
+                            "$ref": "#/definitions/model.FeedToken"
                         }
                     },
-                    "400": {
-                        "description": "Invalid login data"
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "No feed token"
                     }
                 }
-            }
-        },
-        "/api/v1/auth/logout": {
+            },
             "post": {
+                "description": "Create the secret token of the feeds of every bookmark of the account, replacing the previous one.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Auth"
                 ],
-                "summary": "Logout from the current session",
+                "summary": "Create the private feed token",
                 "responses": {
-                    "200": {
-                        "description": "Logout successful"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.createFeedTokenResponse"
+                        }
                     },
-                    "403": {
-                        "description": "Token not provided/invalid"
+                    "401": {
+                        "description": "Authentication required"
                     }
                 }
-            }
-        },
-        "/api/v1/auth/me": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
+            },
+            "delete": {
                 "tags": [
                     "Auth"
                 ],
-                "summary": "Get information for the current logged in user",
+                "summary": "Revoke the private feed token",
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/model.Account"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
-                    "403": {
-                        "description": "Token not provided/invalid"
+                    "401": {
+                        "description": "Authentication required"
                     }
                 }
             }
         },
-        "/api/v1/auth/refresh": {
+        "/api/v1/auth/login": {
             "post": {
+                "description": "Accounts with two-factor authentication get a challenge instead of a token, see /api/v1/auth/login/totp.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Auth"
                 ],
-                "summary": "Refresh a token for an account",
+                "summary": "Login to an account using username and password",
+                "parameters": [
+                    {
+                        "description": "Login data",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.loginRequestPayload"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "Refresh successful",
+                        "description": "Login successful",
                         "schema": {
                             "$ref": "#/definitions/api_v1.loginResponseMessage"
                         }
                     },
-                    "403": {
-                        "description": "Token not provided/invalid"
+                    "400": {
+                        "description": "Invalid login data"
+                    },
+                    "429": {
+                        "description": "Too many login attempts"
                     }
                 }
             }
         },
-        "/api/v1/bookmarks/bulk/tags": {
-            "put": {
+        "/api/v1/auth/login/totp": {
+            "post": {
+                "description": "Second login step of accounts with two-factor authentication. Owners who must set it up send the\nfirst code of their new secret, from /api/v1/auth/login/totp/setup, and get their recovery codes too.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Auth"
                 ],
-                "summary": "Bulk update tags for multiple bookmarks.",
+                "summary": "Complete a login with a two-factor authentication code",
                 "parameters": [
                     {
-                        "description": "Bulk Update Bookmark Tags Payload",
+                        "description": "Login challenge and code",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api_v1.bulkUpdateBookmarkTagsPayload"
+                            "$ref": "#/definitions/api_v1.loginTOTPPayload"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Login successful",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.BookmarkDTO"
-                            }
+                            "$ref": "#/definitions/api_v1.loginResponseMessage"
                         }
                     },
                     "400": {
-                        "description": "Invalid request payload"
+                        "description": "Invalid challenge or code"
                     },
-                    "403": {
-                        "description": "Token not provided/invalid"
-                    },
-                    "404": {
-                        "description": "No bookmarks found"
+                    "429": {
+                        "description": "Too many login attempts"
                     }
                 }
             }
         },
-        "/api/v1/bookmarks/cache": {
-            "put": {
+        "/api/v1/auth/login/totp/setup": {
+            "post": {
+                "description": "Owners who must use two-factor authentication get their secret here when the login asks them to set it up.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Auth"
                 ],
-                "summary": "Update Cache and Ebook on server.",
+                "summary": "Set up two-factor authentication during a login",
                 "parameters": [
                     {
-                        "description": "Update Cache Payload",
+                        "description": "Login challenge",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api_v1.updateCachePayload"
+                            "$ref": "#/definitions/api_v1.loginTOTPPayload"
                         }
                     }
                 ],
@@ -353,30 +358,28 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.BookmarkDTO"
+                            "$ref": "#/definitions/model.TOTPEnrollment"
                         }
                     },
-                    "403": {
-                        "description": "Token not provided/invalid"
+                    "400": {
+                        "description": "Invalid challenge or already set up"
                     }
                 }
             }
         },
-        "/api/v1/bookmarks/id/readable": {
-            "get": {
+        "/api/v1/auth/logout": {
+            "post": {
+                "description": "End the session the request was made with, its token can't be used anymore.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Auth"
                 ],
-                "summary": "Get readable version of bookmark.",
+                "summary": "Logout from the current session",
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/api_v1.readableResponseMessage"
-                        }
+                        "description": "Logout successful"
                     },
                     "403": {
                         "description": "Token not provided/invalid"
@@ -384,7 +387,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/bookmarks/{id}/tags": {
+        "/api/v1/auth/me": {
             "get": {
                 "produces": [
                     "application/json"
@@ -392,175 +395,272 @@ const docTemplate = `{
                 "tags": [
                     "Auth"
                 ],
-                "summary": "Get tags for a bookmark.",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Bookmark ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
+                "summary": "Get information for the current logged in user",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.TagDTO"
-                            }
+                            "$ref": "#/definitions/model.Account"
                         }
                     },
                     "403": {
                         "description": "Token not provided/invalid"
-                    },
-                    "404": {
-                        "description": "Bookmark not found"
                     }
                 }
-            },
-            "post": {
-                "produces": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/auth/oidc/callback": {
+            "get": {
+                "description": "Callback the provider sends the user back to. The account is created on its first login, then the user is\nredirected to the web interface with the session token in the URL fragment. Accounts are matched by the\nidentity of the user at the provider, never by username. When the login was started by the link endpoint,\nthe identity is linked to the account that started it and no session is created. Accounts with two-factor\nauthentication, or owners when it's required for them, get the challenge of the second login step in the\nfragment instead of the token, to complete with the TOTP login endpoint.",
                 "tags": [
                     "Auth"
                 ],
-                "summary": "Add a tag to a bookmark.",
+                "summary": "Complete a login with the OpenID Connect provider",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Bookmark ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Authorization code",
+                        "name": "code",
+                        "in": "query",
                         "required": true
                     },
                     {
-                        "description": "Add Tag Payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api_v1.bookmarkTagPayload"
-                        }
+                        "type": "string",
+                        "description": "Login state",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK"
+                    "302": {
+                        "description": "Redirect to the web interface"
                     },
-                    "403": {
-                        "description": "Token not provided/invalid"
+                    "400": {
+                        "description": "Login failed"
                     },
                     "404": {
-                        "description": "Bookmark or tag not found"
+                        "description": "OIDC login is disabled"
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/auth/oidc/identities": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Auth"
                 ],
-                "summary": "Remove a tag from a bookmark.",
+                "summary": "List linked OpenID Connect identities",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.AccountIdentity"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "OIDC login is disabled"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/oidc/identities/{id}": {
+            "delete": {
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Unlink an OpenID Connect identity",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Bookmark ID",
+                        "description": "Identity ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Remove Tag Payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api_v1.bookmarkTagPayload"
-                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK"
+                    "204": {
+                        "description": "No Content"
                     },
-                    "403": {
-                        "description": "Token not provided/invalid"
+                    "400": {
+                        "description": "Invalid identity ID"
+                    },
+                    "401": {
+                        "description": "Authentication required"
                     },
                     "404": {
-                        "description": "Bookmark not found"
+                        "description": "Identity not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
                     }
                 }
             }
         },
-        "/api/v1/system/info": {
-            "get": {
-                "description": "Get general system information like Shiori version, database, and OS",
+        "/api/v1/auth/oidc/link": {
+            "post": {
+                "description": "Start a login with the provider that links the identity of the user to the logged in account, so it\ncan be used to log in afterwards. The browser has to be sent to the returned URL.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "System"
+                    "Auth"
                 ],
-                "summary": "Get general system information",
+                "summary": "Link an OpenID Connect identity",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_v1.infoResponse"
+                            "$ref": "#/definitions/api_v1.oidcLinkResponse"
                         }
                     },
-                    "403": {
-                        "description": "Only owners can access this endpoint"
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "OIDC login is disabled"
+                    },
+                    "500": {
+                        "description": "Internal server error"
                     }
                 }
             }
         },
-        "/api/v1/tags": {
+        "/api/v1/auth/oidc/login": {
             "get": {
-                "description": "List all tags",
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Redirect to the provider configured with SHIORI_OIDC_*, which sends the user back to the callback.",
                 "tags": [
-                    "Tags"
+                    "Auth"
                 ],
-                "summary": "List tags",
-                "parameters": [
-                    {
-                        "type": "boolean",
-                        "description": "Include bookmark count for each tag",
-                        "name": "with_bookmark_count",
-                        "in": "query"
+                "summary": "Login with the OpenID Connect provider",
+                "responses": {
+                    "302": {
+                        "description": "Redirect to the provider"
                     },
-                    {
-                        "type": "integer",
-                        "description": "Filter tags by bookmark ID",
-                        "name": "bookmark_id",
-                        "in": "query"
+                    "404": {
+                        "description": "OIDC login is disabled"
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/refresh": {
+            "post": {
+                "description": "Start a new session and end the one the request was made with.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Refresh a token for an account",
+                "responses": {
+                    "200": {
+                        "description": "Refresh successful",
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.loginResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Token not provided/invalid"
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/sessions": {
+            "get": {
+                "description": "List the sessions of the logged in account that haven't expired, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "List active sessions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api_v1.sessionResponse"
+                            }
+                        }
                     },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/sessions/{id}": {
+            "delete": {
+                "description": "End a session of the logged in account, its token can't be used anymore.",
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Revoke a session",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "Search tags by name",
-                        "name": "search",
-                        "in": "query"
+                        "description": "Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Session not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
                     }
+                }
+            }
+        },
+        "/api/v1/auth/tokens": {
+            "get": {
+                "description": "List the personal API tokens of the logged in account. Token values are never returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
                 ],
+                "summary": "List personal API tokens",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/model.TagDTO"
+                                "$ref": "#/definitions/model.APIToken"
                             }
                         }
                     },
-                    "403": {
+                    "401": {
                         "description": "Authentication required"
                     },
                     "500": {
@@ -569,7 +669,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new tag",
+                "description": "Create a long lived token for scripts and extensions, sent as a bearer token like the session one.\nScopes are read (GET requests only), write (any request) and admin (owner requests, owners only).",
                 "consumes": [
                     "application/json"
                 ],
@@ -577,17 +677,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tags"
+                    "Auth"
                 ],
-                "summary": "Create tag",
+                "summary": "Create a personal API token",
                 "parameters": [
                     {
-                        "description": "Tag data",
-                        "name": "tag",
+                        "description": "Token data",
+                        "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.TagDTO"
+                            "$ref": "#/definitions/api_v1.createAPITokenPayload"
                         }
                     }
                 ],
@@ -595,13 +695,13 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/model.TagDTO"
+                            "$ref": "#/definitions/api_v1.createAPITokenResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request"
+                        "description": "Invalid token data"
                     },
-                    "403": {
+                    "401": {
                         "description": "Authentication required"
                     },
                     "500": {
@@ -610,70 +710,141 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/tags/{id}": {
-            "get": {
-                "description": "Get a tag by ID",
-                "produces": [
-                    "application/json"
-                ],
+        "/api/v1/auth/tokens/{id}": {
+            "delete": {
                 "tags": [
-                    "Tags"
+                    "Auth"
                 ],
-                "summary": "Get tag",
+                "summary": "Revoke a personal API token",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Tag ID",
+                        "description": "Token ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/model.TagDTO"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
-                    "403": {
+                    "400": {
+                        "description": "Invalid token ID"
+                    },
+                    "401": {
                         "description": "Authentication required"
                     },
                     "404": {
-                        "description": "Tag not found"
+                        "description": "Token not found"
                     },
                     "500": {
                         "description": "Internal server error"
                     }
                 }
-            },
-            "put": {
-                "description": "Update an existing tag",
-                "consumes": [
+            }
+        },
+        "/api/v1/auth/totp": {
+            "get": {
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Get the two-factor authentication status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.TOTPStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    }
+                }
+            },
+            "post": {
+                "description": "Generate a new secret for the account, to add to an authenticator app with the URI. It is only\nused to log in once enabled with /api/v1/auth/totp/enable.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tags"
+                    "Auth"
                 ],
-                "summary": "Update tag",
+                "summary": "Set up two-factor authentication",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.TOTPEnrollment"
+                        }
+                    },
+                    "400": {
+                        "description": "Already enabled"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/totp/disable": {
+            "post": {
+                "description": "Owners can't disable it while it is required for them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Disable two-factor authentication",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Tag ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Code from the authenticator app or recovery code",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.totpCodePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid code"
                     },
+                    "401": {
+                        "description": "Authentication required"
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/totp/enable": {
+            "post": {
+                "description": "Enable the secret set up with POST /api/v1/auth/totp by sending a code generated from it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Enable two-factor authentication",
+                "parameters": [
                     {
-                        "description": "Tag data",
-                        "name": "tag",
+                        "description": "Code from the authenticator app",
+                        "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.TagDTO"
+                            "$ref": "#/definitions/api_v1.totpCodePayload"
                         }
                     }
                 ],
@@ -681,287 +852,3878 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.TagDTO"
+                            "$ref": "#/definitions/api_v1.totpRecoveryCodesResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request"
+                        "description": "Invalid code"
                     },
-                    "403": {
+                    "401": {
                         "description": "Authentication required"
-                    },
-                    "404": {
-                        "description": "Tag not found"
-                    },
-                    "500": {
-                        "description": "Internal server error"
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a tag",
+            }
+        },
+        "/api/v1/auth/totp/policy": {
+            "put": {
+                "description": "Owners without it have to set it up on their next login. The owner requiring it must have enabled it.",
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Tags"
+                    "Auth"
                 ],
-                "summary": "Delete tag",
+                "summary": "Require two-factor authentication for owners",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Tag ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Policy",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.totpPolicyPayload"
+                        }
                     }
                 ],
                 "responses": {
                     "204": {
                         "description": "No Content"
                     },
-                    "403": {
-                        "description": "Authentication required"
-                    },
-                    "404": {
-                        "description": "Tag not found"
+                    "400": {
+                        "description": "Two-factor authentication not enabled"
                     },
-                    "500": {
-                        "description": "Internal server error"
+                    "403": {
+                        "description": "Only owners can change the policy"
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "api_v1.bookmarkTagPayload": {
-            "type": "object",
-            "required": [
-                "tag_id"
-            ],
+        },
+        "/api/v1/bookmarks": {
+            "get": {
+                "description": "List and search the bookmarks of the current account, newest first or by relevance to the text searched. When text is searched, the bookmarks have a snippet of their matching text, escaped as HTML with the matches between \u003cmark\u003e and \u003c/mark\u003e.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bookmarks"
+                ],
+                "summary": "List bookmarks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query, like site:github.com tag:go -tag:old is:public has:archive before:2024-01-01 \\",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated list of tags the bookmarks must have",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated list of tags the bookmarks must not have",
+                        "name": "exclude",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status of the latest link check: ok, broken, redirected or unchecked",
+                        "name": "link_status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only list the bookmarks directly in this collection, 0 for the ones in none",
+                        "name": "collection_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only list the bookmarks matching this saved search",
+                        "name": "search_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "newest (default) or relevance, the best matches of the text searched first",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "List the bookmarks of every account, owners only",
+                        "name": "all_accounts",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.listBookmarksResponseMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid page, search query, order, link status, collection or saved search"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Saved search not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new bookmark. Its content is downloaded by a background job unless async is false. The rules of the account can add tags, change its options or reject it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bookmarks"
+                ],
+                "summary": "Create bookmark",
+                "parameters": [
+                    {
+                        "description": "Bookmark data",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.createBookmarkPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.BookmarkDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "422": {
+                        "description": "Bookmark rejected by a rule"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/bookmarks/bulk/collection": {
+            "put": {
+                "description": "Put bookmarks of the logged in account in a collection, or in none if the collection is empty.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Move bookmarks to a collection",
+                "parameters": [
+                    {
+                        "description": "Bookmarks and their collection",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.moveBookmarksPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid payload"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Collection or bookmarks not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/bookmarks/bulk/tags": {
+            "put": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Bulk update tags for multiple bookmarks.",
+                "parameters": [
+                    {
+                        "description": "Bulk Update Bookmark Tags Payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.bulkUpdateBookmarkTagsPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.BookmarkDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload"
+                    },
+                    "403": {
+                        "description": "Token not provided/invalid"
+                    },
+                    "404": {
+                        "description": "No bookmarks found"
+                    }
+                }
+            }
+        },
+        "/api/v1/bookmarks/cache": {
+            "put": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Update Cache and Ebook on server.",
+                "parameters": [
+                    {
+                        "description": "Update Cache Payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.updateCachePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.BookmarkDTO"
+                        }
+                    },
+                    "403": {
+                        "description": "Token not provided/invalid"
+                    }
+                }
+            }
+        },
+        "/api/v1/bookmarks/check": {
+            "post": {
+                "description": "Queue a check of the bookmark URLs. Without ids every bookmark of the account is checked.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bookmarks"
+                ],
+                "summary": "Check bookmark links",
+                "parameters": [
+                    {
+                        "description": "Bookmarks to check",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.checkLinksPayload"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Check bookmarks of every account, owners only",
+                        "name": "all_accounts",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "No bookmarks found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/bookmarks/id/readable": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Get readable version of bookmark.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.readableResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Token not provided/invalid"
+                    }
+                }
+            }
+        },
+        "/api/v1/bookmarks/reindex": {
+            "post": {
+                "description": "Queue the extraction of the text of the bookmark archives, from their HTML pages and PDFs, so it can be searched with in:archive. The saved archives are read without downloading the pages again. Without ids every bookmark of the account is indexed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bookmarks"
+                ],
+                "summary": "Reindex bookmark archives",
+                "parameters": [
+                    {
+                        "description": "Bookmarks to index",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.reindexArchivesPayload"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Index the bookmarks of every account, owners only",
+                        "name": "all_accounts",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "No bookmarks found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/bookmarks/{id}": {
+            "get": {
+                "description": "Get a bookmark by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bookmarks"
+                ],
+                "summary": "Get bookmark",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bookmark ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Look up bookmarks of every account, owners only",
+                        "name": "all_accounts",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.BookmarkDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid bookmark ID"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Bookmark not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a bookmark and its thumbnail, archive and ebook",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bookmarks"
+                ],
+                "summary": "Delete bookmark",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bookmark ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No content"
+                    },
+                    "400": {
+                        "description": "Invalid bookmark ID"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Bookmark not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update the provided fields of a bookmark. Tags, if provided, replace the current ones.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bookmarks"
+                ],
+                "summary": "Update bookmark",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bookmark ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Bookmark data",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.updateBookmarkPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.BookmarkDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid bookmark ID or request payload"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Bookmark not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/bookmarks/{id}/archives": {
+            "get": {
+                "description": "List the archive snapshots of a bookmark, newest first. The current archive is flagged.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bookmarks"
+                ],
+                "summary": "List bookmark archives",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bookmark ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Look up bookmarks of every account, owners only",
+                        "name": "all_accounts",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ArchiveSnapshot"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid bookmark ID"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Bookmark not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/bookmarks/{id}/archives/{snapshot_id}": {
+            "delete": {
+                "description": "Delete an archive snapshot of a bookmark. Deleting the current archive makes the newest remaining snapshot current.",
+                "tags": [
+                    "Bookmarks"
+                ],
+                "summary": "Delete bookmark archive",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bookmark ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Archive snapshot ID",
+                        "name": "snapshot_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Look up bookmarks of every account, owners only",
+                        "name": "all_accounts",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Invalid bookmark or snapshot ID"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Bookmark or snapshot not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/bookmarks/{id}/checks": {
+            "get": {
+                "description": "List the results of checking the URL of a bookmark, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bookmarks"
+                ],
+                "summary": "List bookmark link checks",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bookmark ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Look up bookmarks of every account, owners only",
+                        "name": "all_accounts",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.LinkCheck"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid bookmark ID"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Bookmark not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/bookmarks/{id}/highlights": {
+            "get": {
+                "description": "List the highlights of the logged in account on a bookmark, in the order of its content. Positions are located in the current content of the bookmark.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Highlights"
+                ],
+                "summary": "List bookmark highlights",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bookmark ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Look up bookmarks of every account, owners only",
+                        "name": "all_accounts",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Highlight"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid bookmark ID"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Bookmark not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            },
+            "post": {
+                "description": "Highlight a passage of the readable content of a bookmark, with an optional note. The passage is selected by its text quote, with some of the text before and after it, and optionally its position. It must be found in the content.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Highlights"
+                ],
+                "summary": "Highlight a passage of a bookmark",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bookmark ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Look up bookmarks of every account, owners only",
+                        "name": "all_accounts",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Highlight data",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.highlightPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Highlight"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid bookmark ID or highlight data"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Bookmark not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/bookmarks/{id}/highlights/{highlight_id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Highlights"
+                ],
+                "summary": "Get a bookmark highlight",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bookmark ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Highlight ID",
+                        "name": "highlight_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Look up bookmarks of every account, owners only",
+                        "name": "all_accounts",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Highlight"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid bookmark or highlight ID"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Bookmark or highlight not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace the selector and note of a highlight.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Highlights"
+                ],
+                "summary": "Update a bookmark highlight",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bookmark ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Highlight ID",
+                        "name": "highlight_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Look up bookmarks of every account, owners only",
+                        "name": "all_accounts",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Highlight data",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.highlightPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Highlight"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid bookmark or highlight ID or data"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Bookmark or highlight not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Highlights"
+                ],
+                "summary": "Delete a bookmark highlight",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bookmark ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Highlight ID",
+                        "name": "highlight_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Look up bookmarks of every account, owners only",
+                        "name": "all_accounts",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid bookmark or highlight ID"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Bookmark or highlight not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/bookmarks/{id}/tags": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Get tags for a bookmark.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bookmark ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.TagDTO"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Token not provided/invalid"
+                    },
+                    "404": {
+                        "description": "Bookmark not found"
+                    }
+                }
+            },
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Add a tag to a bookmark.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bookmark ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Add Tag Payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.bookmarkTagPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "403": {
+                        "description": "Token not provided/invalid"
+                    },
+                    "404": {
+                        "description": "Bookmark or tag not found"
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Remove a tag from a bookmark.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bookmark ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Remove Tag Payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.bookmarkTagPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "403": {
+                        "description": "Token not provided/invalid"
+                    },
+                    "404": {
+                        "description": "Bookmark not found"
+                    }
+                }
+            }
+        },
+        "/api/v1/collections": {
+            "get": {
+                "description": "List every collection of the logged in account, ordered by position. The tree is built from their parent IDs.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "List collections",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Collection"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a collection after the other children of its parent.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Create a collection",
+                "parameters": [
+                    {
+                        "description": "Collection data",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.createCollectionPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Collection"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid collection data"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/collections/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Get a collection",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Collection"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid collection ID"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Collection not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a collection. Its bookmarks and child collections are moved to its parent.",
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Delete a collection",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid collection ID"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Collection not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            },
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Rename a collection",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Collection data",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.updateCollectionPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Collection"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid collection ID or data"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Collection not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/collections/{id}/move": {
+            "post": {
+                "description": "Move a collection under another parent, or to the top level, at the given position among its new siblings.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Move a collection",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New place of the collection",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.moveCollectionPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Collection"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid collection ID, parent or position"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Collection not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/export": {
+            "get": {
+                "description": "Download the bookmarks of the account. The netscape format can be imported by browsers, json keeps every field including the content, csv suits spreadsheets and markdown is a zip archive with one file per bookmark.",
+                "produces": [
+                    "text/html",
+                    "application/json",
+                    "text/csv",
+                    "application/zip"
+                ],
+                "tags": [
+                    "Bookmarks"
+                ],
+                "summary": "Export bookmarks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Export format: netscape (default), json, csv or markdown",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only export the bookmarks matching this saved search",
+                        "name": "search_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Export bookmarks of every account, owners only",
+                        "name": "all_accounts",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid format or saved search"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Saved search not found"
+                    }
+                }
+            }
+        },
+        "/api/v1/highlights/export": {
+            "get": {
+                "description": "Download every highlight of the logged in account, grouped by bookmark. The markdown format quotes the highlighted passages followed by their notes, json keeps the selectors.",
+                "produces": [
+                    "application/json",
+                    "text/markdown"
+                ],
+                "tags": [
+                    "Highlights"
+                ],
+                "summary": "Export highlights",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Export format: markdown (default) or json",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid format"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/jobs": {
+            "get": {
+                "description": "List the background jobs, newest first. Without a status filter completed jobs are left out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Jobs"
+                ],
+                "summary": "List jobs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated list of statuses: pending, running, completed, failed",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of jobs to return, 100 by default",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Job"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid status or limit"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "403": {
+                        "description": "Only owners can list jobs"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/rules": {
+            "get": {
+                "description": "List the rules of the logged in account, in the order they are applied.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "List rules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.BookmarkRule"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a rule checked when a bookmark of the logged in account is saved or its content downloaded. Its actions are applied when every condition matches.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Create a rule",
+                "parameters": [
+                    {
+                        "description": "Rule data",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.rulePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.BookmarkRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid rule data"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/rules/dry-run": {
+            "post": {
+                "description": "List the existing bookmarks of the logged in account matched by the conditions, without saving the rule. The content type of existing bookmarks isn't known, a content type condition matches none of them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Dry run a rule",
+                "parameters": [
+                    {
+                        "description": "Rule conditions",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.ruleDryRunPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.BookmarkDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid conditions"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/rules/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Get a rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.BookmarkRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid rule ID"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Rule not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace the name, state, conditions and actions of a rule.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Update a rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rule data",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.rulePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.BookmarkRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid rule ID or data"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Rule not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Delete a rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid rule ID"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Rule not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/searches": {
+            "get": {
+                "description": "List the saved searches of the logged in account, ordered by name.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Saved searches"
+                ],
+                "summary": "List saved searches",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.SavedSearch"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            },
+            "post": {
+                "description": "Save a bookmark search under a name unique in the account. Its bookmarks are listed with the search_id parameter of the bookmark listing.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Saved searches"
+                ],
+                "summary": "Save a search",
+                "parameters": [
+                    {
+                        "description": "Saved search data",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.savedSearchPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.SavedSearch"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid saved search data"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/searches/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Saved searches"
+                ],
+                "summary": "Get a saved search",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Saved search ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SavedSearch"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid saved search ID"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Saved search not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace the name and filter of a saved search.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Saved searches"
+                ],
+                "summary": "Update a saved search",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Saved search ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Saved search data",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.savedSearchPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SavedSearch"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid saved search ID or data"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Saved search not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Saved searches"
+                ],
+                "summary": "Delete a saved search",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Saved search ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid saved search ID"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Saved search not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions": {
+            "get": {
+                "description": "List the feeds the logged in account is subscribed to.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "List feed subscriptions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Subscription"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            },
+            "post": {
+                "description": "Subscribe to a RSS, Atom or JSON feed. Its items are saved as bookmarks when it is polled, skipping the URLs bookmarked already.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Subscribe to a feed",
+                "parameters": [
+                    {
+                        "description": "Subscription data",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.createSubscriptionPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Subscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid subscription data"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Get a feed subscription",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Subscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid subscription ID"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Subscription not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a feed subscription. The bookmarks saved from it are kept.",
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Unsubscribe from a feed",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid subscription ID"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Subscription not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/{id}/poll": {
+            "post": {
+                "description": "Fetch the feed now and save its new items as bookmarks. Their content is downloaded by background jobs.\nA feed that can't be fetched is reported in the error field of the result.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Poll a feed subscription",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PollResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid subscription ID"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Subscription not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/system/info": {
+            "get": {
+                "description": "Get general system information like Shiori version, database, and OS",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Get general system information",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.infoResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Only owners can access this endpoint"
+                    }
+                }
+            }
+        },
+        "/api/v1/tags": {
+            "get": {
+                "description": "List the tags used by the current account and the unused ones",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "List tags",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Include bookmark count for each tag",
+                        "name": "with_bookmark_count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter tags by bookmark ID",
+                        "name": "bookmark_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search tags by name",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include tags of every account, owners only",
+                        "name": "all_accounts",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.TagDTO"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Authentication required"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new tag",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Create tag",
+                "parameters": [
+                    {
+                        "description": "Tag data",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TagDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.TagDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request, name already used or invalid parent"
+                    },
+                    "403": {
+                        "description": "Authentication required"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/tags/{id}": {
+            "get": {
+                "description": "Get a tag by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Get tag",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.TagDTO"
+                        }
+                    },
+                    "403": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Tag not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            },
+            "put": {
+                "description": "Update the name and parent of an existing tag. The parent is only changed when parent_id is sent, a null parent moves the tag to the top level",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Update tag",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tag data",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TagDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.TagDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "403": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Tag not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a tag",
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Delete tag",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Tag not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/tags/{id}/aliases": {
+            "get": {
+                "description": "List the other names resolving to a tag",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "List tag aliases",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.TagAlias"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid tag ID"
+                    },
+                    "403": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Tag not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            },
+            "post": {
+                "description": "Add another name to a tag. Bookmarks tagged or searched with the alias use the tag instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Create a tag alias",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Alias data",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.createTagAliasPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.TagAlias"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid tag ID or name already used"
+                    },
+                    "403": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Tag not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/tags/{id}/aliases/{alias_id}": {
+            "delete": {
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Delete a tag alias",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Alias ID",
+                        "name": "alias_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid tag or alias ID"
+                    },
+                    "403": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Tag or alias not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/tags/{id}/merge": {
+            "post": {
+                "description": "Merge tags into the tag in the path. Their bookmarks, aliases and child tags are moved to it, their names become its aliases and they are deleted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Merge tags",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Merged tags",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.mergeTagsPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.TagDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid tag ID or merged tags"
+                    },
+                    "403": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Tag not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks": {
+            "get": {
+                "description": "List the webhooks of the logged in account. Owners also get the instance webhooks.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "List webhooks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Webhook"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a webhook notified of the bookmark and tag events. The returned secret signs the deliveries and is not shown again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Create a webhook",
+                "parameters": [
+                    {
+                        "description": "Webhook data",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_v1.createWebhookPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid webhook data"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "403": {
+                        "description": "Only owners can create instance webhooks"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Get a webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid webhook ID"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Webhook not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a webhook and its delivery log. Pending deliveries are not sent.",
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Delete a webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid webhook ID"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Webhook not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "List the latest deliveries of a webhook, newest first, with the answer to their last attempt.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "List webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.WebhookDelivery"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid webhook ID"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Webhook not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}/deliveries/{delivery_id}/replay": {
+            "post": {
+                "description": "Queue a new delivery with the payload of a previous one.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Replay a webhook delivery",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Delivery ID",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookDelivery"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid webhook or delivery ID"
+                    },
+                    "401": {
+                        "description": "Authentication required"
+                    },
+                    "404": {
+                        "description": "Webhook or delivery not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api_v1.bookmarkTagPayload": {
+            "type": "object",
+            "required": [
+                "tag_id"
+            ],
+            "properties": {
+                "tag_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api_v1.bulkUpdateBookmarkTagsPayload": {
+            "type": "object",
+            "required": [
+                "bookmark_ids",
+                "tag_ids"
+            ],
+            "properties": {
+                "bookmark_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "tag_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "api_v1.checkLinksPayload": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "description": "Bookmarks to check, empty checks every bookmark of the account",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "api_v1.createAPITokenPayload": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "Expiration date in RFC3339 format, tokens without one don't expire",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.APITokenScope"
+                    }
+                }
+            }
+        },
+        "api_v1.createAPITokenResponse": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.APITokenScope"
+                    }
+                },
+                "token": {
+                    "description": "The token value, only returned once",
+                    "type": "string"
+                }
+            }
+        },
+        "api_v1.createBookmarkPayload": {
+            "type": "object",
+            "properties": {
+                "async": {
+                    "type": "boolean"
+                },
+                "create_archive": {
+                    "type": "boolean"
+                },
+                "create_ebook": {
+                    "type": "boolean"
+                },
+                "excerpt": {
+                    "type": "string"
+                },
+                "public": {
+                    "type": "integer"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_v1.createCollectionPayload": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "description": "Parent collection, top level if empty",
+                    "type": "integer"
+                }
+            }
+        },
+        "api_v1.createFeedTokenResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "description": "The token value, only returned once. Private feeds are read from /feeds/{format}?token={token}",
+                    "type": "string"
+                }
+            }
+        },
+        "api_v1.createSubscriptionPayload": {
+            "type": "object",
+            "properties": {
+                "create_archive": {
+                    "type": "boolean"
+                },
+                "create_ebook": {
+                    "type": "boolean"
+                },
+                "tags": {
+                    "description": "Tags given to the bookmarks saved from the feed",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "description": "Title of the subscription, the feed title is used if empty",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_v1.createTagAliasPayload": {
+            "type": "object",
             "properties": {
-                "tag_id": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_v1.createWebhookPayload": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "description": "Events the webhook is notified of, every event if empty",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.WebhookEvent"
+                    }
+                },
+                "instance": {
+                    "description": "Instance webhooks are notified of the events of every account, only owners can create them",
+                    "type": "boolean"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_v1.highlightPayload": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string"
+                },
+                "selector": {
+                    "$ref": "#/definitions/model.HighlightSelector"
+                }
+            }
+        },
+        "api_v1.infoResponse": {
+            "type": "object",
+            "properties": {
+                "database": {
+                    "type": "string"
+                },
+                "os": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "object",
+                    "properties": {
+                        "commit": {
+                            "type": "string"
+                        },
+                        "date": {
+                            "type": "string"
+                        },
+                        "tag": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "api_v1.listBookmarksResponseMessage": {
+            "type": "object",
+            "properties": {
+                "bookmarks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BookmarkDTO"
+                    }
+                },
+                "max_page": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api_v1.loginRequestPayload": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "remember_me": {
+                    "type": "boolean"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_v1.loginResponseMessage": {
+            "type": "object",
+            "properties": {
+                "challenge": {
+                    "type": "string"
+                },
+                "expires": {
+                    "type": "integer"
+                },
+                "recovery_codes": {
+                    "description": "Recovery codes of the second factor set up during the login, only returned once",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "token": {
+                    "type": "string"
+                },
+                "totp_required": {
+                    "description": "Set instead of the token when the account uses a second factor, the login is completed\nby sending the challenge and a code to /api/v1/auth/login/totp",
+                    "type": "boolean"
+                },
+                "totp_setup_required": {
+                    "description": "Set when the owner account must set up a second factor before logging in",
+                    "type": "boolean"
+                }
+            }
+        },
+        "api_v1.loginTOTPPayload": {
+            "type": "object",
+            "properties": {
+                "challenge": {
+                    "description": "Challenge returned by /api/v1/auth/login",
+                    "type": "string"
+                },
+                "code": {
+                    "description": "Code from the authenticator app or a recovery code",
+                    "type": "string"
+                }
+            }
+        },
+        "api_v1.mergeTagsPayload": {
+            "type": "object",
+            "properties": {
+                "tag_ids": {
+                    "description": "Tags merged into the tag in the path",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "api_v1.moveBookmarksPayload": {
+            "type": "object",
+            "properties": {
+                "bookmark_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "collection_id": {
+                    "description": "Collection the bookmarks are moved to, none if empty",
+                    "type": "integer"
+                }
+            }
+        },
+        "api_v1.moveCollectionPayload": {
+            "type": "object",
+            "properties": {
+                "parent_id": {
+                    "description": "New parent collection, top level if empty",
+                    "type": "integer"
+                },
+                "position": {
+                    "description": "Position among the children of the new parent, starting at 0",
+                    "type": "integer"
+                }
+            }
+        },
+        "api_v1.oidcLinkResponse": {
+            "type": "object",
+            "properties": {
+                "url": {
+                    "description": "Provider URL to send the user to",
+                    "type": "string"
+                }
+            }
+        },
+        "api_v1.readableResponseMessage": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "html": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_v1.reindexArchivesPayload": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "description": "Bookmarks whose archive is indexed, empty indexes every bookmark of the account",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "api_v1.ruleDryRunPayload": {
+            "type": "object",
+            "properties": {
+                "conditions": {
+                    "$ref": "#/definitions/model.RuleConditions"
+                }
+            }
+        },
+        "api_v1.rulePayload": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "$ref": "#/definitions/model.RuleActions"
+                },
+                "conditions": {
+                    "$ref": "#/definitions/model.RuleConditions"
+                },
+                "enabled": {
+                    "description": "Disabled rules are kept but not applied, enabled if empty",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_v1.savedSearchPayload": {
+            "type": "object",
+            "properties": {
+                "filter": {
+                    "$ref": "#/definitions/model.SearchFilter"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_v1.sessionResponse": {
+            "type": "object",
+            "properties": {
+                "account_id": {
                     "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "current": {
+                    "description": "Whether the request was made with this session",
+                    "type": "boolean"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_v1.totpCodePayload": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_v1.totpPolicyPayload": {
+            "type": "object",
+            "properties": {
+                "owners_required": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "api_v1.totpRecoveryCodesResponse": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "description": "Recovery codes, only returned once",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api_v1.updateAccountPayload": {
+            "type": "object",
+            "properties": {
+                "config": {
+                    "$ref": "#/definitions/model.UserConfig"
+                },
+                "new_password": {
+                    "type": "string"
+                },
+                "old_password": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "boolean"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_v1.updateBookmarkPayload": {
+            "type": "object",
+            "properties": {
+                "excerpt": {
+                    "type": "string"
+                },
+                "public": {
+                    "type": "integer"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         },
-        "api_v1.bulkUpdateBookmarkTagsPayload": {
+        "api_v1.updateCachePayload": {
             "type": "object",
             "required": [
-                "bookmark_ids",
-                "tag_ids"
+                "ids"
             ],
             "properties": {
-                "bookmark_ids": {
+                "create_archive": {
+                    "type": "boolean"
+                },
+                "create_ebook": {
+                    "type": "boolean"
+                },
+                "ids": {
                     "type": "array",
                     "items": {
                         "type": "integer"
                     }
                 },
-                "tag_ids": {
+                "keep_metadata": {
+                    "type": "boolean"
+                },
+                "skip_exist": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "api_v1.updateCollectionPayload": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.APIToken": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/model.APITokenScope"
                     }
                 }
             }
         },
-        "api_v1.infoResponse": {
+        "model.APITokenScope": {
+            "type": "string",
+            "enum": [
+                "read",
+                "write",
+                "admin"
+            ],
+            "x-enum-varnames": [
+                "APITokenScopeRead",
+                "APITokenScopeWrite",
+                "APITokenScopeAdmin"
+            ]
+        },
+        "model.Account": {
             "type": "object",
             "properties": {
-                "database": {
+                "config": {
+                    "$ref": "#/definitions/model.UserConfig"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "owner": {
+                    "type": "boolean"
+                },
+                "password": {
                     "type": "string"
                 },
-                "os": {
+                "username": {
                     "type": "string"
+                }
+            }
+        },
+        "model.AccountDTO": {
+            "type": "object",
+            "properties": {
+                "config": {
+                    "$ref": "#/definitions/model.UserConfig"
                 },
-                "version": {
-                    "type": "object",
-                    "properties": {
-                        "commit": {
-                            "type": "string"
-                        },
-                        "date": {
-                            "type": "string"
-                        },
-                        "tag": {
-                            "type": "string"
-                        }
+                "id": {
+                    "type": "integer"
+                },
+                "owner": {
+                    "type": "boolean"
+                },
+                "passowrd": {
+                    "description": "Used only to store, not to retrieve",
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AccountIdentity": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "issuer": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ArchiveSnapshot": {
+            "type": "object",
+            "properties": {
+                "bookmark_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "current": {
+                    "description": "Current is set for the snapshot served as the bookmark archive",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.BookmarkDTO": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "author": {
+                    "type": "string"
+                },
+                "collection_id": {
+                    "description": "nil if the bookmark is in no collection",
+                    "type": "integer"
+                },
+                "create_archive": {
+                    "description": "TODO: migrate outside the DTO",
+                    "type": "boolean"
+                },
+                "create_ebook": {
+                    "description": "TODO: migrate outside the DTO",
+                    "type": "boolean"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "excerpt": {
+                    "type": "string"
+                },
+                "hasArchive": {
+                    "type": "boolean"
+                },
+                "hasContent": {
+                    "type": "boolean"
+                },
+                "hasEbook": {
+                    "type": "boolean"
+                },
+                "html": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "imageURL": {
+                    "type": "string"
+                },
+                "modifiedAt": {
+                    "type": "string"
+                },
+                "public": {
+                    "type": "integer"
+                },
+                "snippet": {
+                    "description": "extract matching the text searched, see SearchQuery.Snippet",
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TagDTO"
                     }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.BookmarkRule": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "actions": {
+                    "$ref": "#/definitions/model.RuleActions"
+                },
+                "conditions": {
+                    "$ref": "#/definitions/model.RuleConditions"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "modified_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Collection": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "modified_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "description": "ParentID is nil for the top level collections",
+                    "type": "integer"
+                },
+                "position": {
+                    "description": "Position orders the collections sharing a parent, lowest first",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.FeedToken": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Highlight": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "bookmark_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "modified_at": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "selector": {
+                    "$ref": "#/definitions/model.HighlightSelector"
                 }
             }
         },
-        "api_v1.loginRequestPayload": {
+        "model.HighlightSelector": {
             "type": "object",
             "properties": {
-                "password": {
-                    "type": "string"
-                },
-                "remember_me": {
-                    "type": "boolean"
+                "position": {
+                    "$ref": "#/definitions/model.TextPositionSelector"
                 },
-                "username": {
-                    "type": "string"
+                "quote": {
+                    "$ref": "#/definitions/model.TextQuoteSelector"
                 }
             }
         },
-        "api_v1.loginResponseMessage": {
+        "model.Job": {
             "type": "object",
             "properties": {
-                "expires": {
+                "attempts": {
                     "type": "integer"
                 },
-                "token": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "max_attempts": {
+                    "type": "integer"
+                },
+                "payload": {
+                    "type": "string"
+                },
+                "run_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.JobStatus"
+                },
+                "type": {
+                    "$ref": "#/definitions/model.JobType"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "api_v1.readableResponseMessage": {
+        "model.JobStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "running",
+                "completed",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "JobStatusPending",
+                "JobStatusRunning",
+                "JobStatusCompleted",
+                "JobStatusFailed"
+            ]
+        },
+        "model.JobType": {
+            "type": "string",
+            "enum": [
+                "process_bookmark",
+                "check_links",
+                "poll_subscriptions",
+                "deliver_webhook",
+                "index_archives"
+            ],
+            "x-enum-varnames": [
+                "JobTypeProcessBookmark",
+                "JobTypeCheckLinks",
+                "JobTypePollSubscriptions",
+                "JobTypeDeliverWebhook",
+                "JobTypeIndexArchives"
+            ]
+        },
+        "model.LinkCheck": {
             "type": "object",
             "properties": {
-                "content": {
+                "bookmark_id": {
+                    "type": "integer"
+                },
+                "checked_at": {
                     "type": "string"
                 },
-                "html": {
+                "error": {
+                    "type": "string"
+                },
+                "final_url": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "latency_ms": {
+                    "type": "integer"
+                },
+                "status_code": {
+                    "description": "StatusCode is zero when no response was received",
+                    "type": "integer"
+                },
+                "url": {
                     "type": "string"
                 }
             }
         },
-        "api_v1.updateAccountPayload": {
+        "model.LinkStatus": {
+            "type": "string",
+            "enum": [
+                "ok",
+                "broken",
+                "redirected",
+                "unchecked"
+            ],
+            "x-enum-varnames": [
+                "LinkStatusOK",
+                "LinkStatusBroken",
+                "LinkStatusRedirected",
+                "LinkStatusUnchecked"
+            ]
+        },
+        "model.PollResult": {
             "type": "object",
             "properties": {
-                "config": {
-                    "$ref": "#/definitions/model.UserConfig"
+                "created": {
+                    "description": "Bookmarks created from new items",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "new_password": {
+                "error": {
                     "type": "string"
                 },
-                "old_password": {
-                    "type": "string"
+                "failed": {
+                    "description": "Items that couldn't be saved, they are tried again on the next poll",
+                    "type": "integer"
                 },
-                "owner": {
-                    "type": "boolean"
+                "skipped": {
+                    "description": "Items skipped because the account had their URL bookmarked already",
+                    "type": "integer"
                 },
-                "username": {
-                    "type": "string"
+                "subscription_id": {
+                    "type": "integer"
                 }
             }
         },
-        "api_v1.updateCachePayload": {
+        "model.RuleActions": {
             "type": "object",
-            "required": [
-                "ids"
-            ],
             "properties": {
+                "add_tags": {
+                    "description": "AddTags are added to the tags of the bookmark",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "create_archive": {
                     "type": "boolean"
                 },
                 "create_ebook": {
                     "type": "boolean"
                 },
-                "ids": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
-                },
-                "keep_metadata": {
+                "public": {
                     "type": "boolean"
                 },
-                "skip_exist": {
+                "reject": {
+                    "description": "Reject refuses to save the bookmark",
                     "type": "boolean"
                 }
             }
         },
-        "model.Account": {
+        "model.RuleConditions": {
             "type": "object",
             "properties": {
-                "config": {
-                    "$ref": "#/definitions/model.UserConfig"
+                "content_type": {
+                    "description": "ContentType is the prefix of the media type, like application/pdf or image/",
+                    "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "host": {
+                    "description": "Host of the URL, its subdomains match too",
+                    "type": "string"
                 },
-                "owner": {
-                    "type": "boolean"
+                "keywords": {
+                    "description": "Keywords should all appear in the title, excerpt or content",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "password": {
+                "title": {
+                    "description": "Title should contain this text",
                     "type": "string"
                 },
-                "username": {
+                "url_pattern": {
+                    "description": "URLPattern is a regular expression matched against the whole URL",
                     "type": "string"
                 }
             }
         },
-        "model.AccountDTO": {
+        "model.SavedSearch": {
             "type": "object",
             "properties": {
-                "config": {
-                    "$ref": "#/definitions/model.UserConfig"
+                "account_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "filter": {
+                    "$ref": "#/definitions/model.SearchFilter"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "owner": {
-                    "type": "boolean"
-                },
-                "passowrd": {
-                    "description": "Used only to store, not to retrieve",
+                "modified_at": {
                     "type": "string"
                 },
-                "username": {
+                "name": {
                     "type": "string"
                 }
             }
         },
-        "model.BookmarkDTO": {
+        "model.SearchFilter": {
             "type": "object",
             "properties": {
-                "author": {
-                    "type": "string"
+                "added_within_days": {
+                    "description": "Bookmarks added in the last days, counted from the time the search is run",
+                    "type": "integer"
                 },
-                "create_archive": {
-                    "description": "TODO: migrate outside the DTO",
-                    "type": "boolean"
+                "collection_id": {
+                    "description": "Bookmarks directly in this collection, zero for the ones in none",
+                    "type": "integer"
                 },
-                "create_ebook": {
-                    "description": "TODO: migrate outside the DTO",
-                    "type": "boolean"
+                "excluded_tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "createdAt": {
+                "keyword": {
                     "type": "string"
                 },
-                "excerpt": {
-                    "type": "string"
+                "link_status": {
+                    "$ref": "#/definitions/model.LinkStatus"
                 },
-                "hasArchive": {
-                    "type": "boolean"
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.Subscription": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
                 },
-                "hasContent": {
+                "create_archive": {
                     "type": "boolean"
                 },
-                "hasEbook": {
+                "create_ebook": {
                     "type": "boolean"
                 },
-                "html": {
+                "created_at": {
                     "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "imageURL": {
+                "last_error": {
+                    "description": "LastError is the reason the last poll failed, empty if it succeeded",
                     "type": "string"
                 },
-                "modifiedAt": {
+                "last_polled_at": {
                     "type": "string"
                 },
-                "public": {
-                    "type": "integer"
-                },
                 "tags": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.TagDTO"
+                        "type": "string"
                     }
                 },
                 "title": {
+                    "description": "Title of the feed, taken from the feed itself when it is polled if left empty",
                     "type": "string"
                 },
                 "url": {
@@ -969,6 +4731,46 @@ const docTemplate = `{
                 }
             }
         },
+        "model.TOTPEnrollment": {
+            "type": "object",
+            "properties": {
+                "secret": {
+                    "type": "string"
+                },
+                "uri": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.TOTPStatus": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "recovery_codes_left": {
+                    "type": "integer"
+                },
+                "required": {
+                    "description": "Required is set for owners once owners must use a second factor",
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.TagAlias": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "tag_id": {
+                    "type": "integer"
+                }
+            }
+        },
         "model.TagDTO": {
             "type": "object",
             "properties": {
@@ -985,6 +4787,35 @@ const docTemplate = `{
                 },
                 "name": {
                     "type": "string"
+                },
+                "parent_id": {
+                    "description": "ParentID is set for child tags, searching a tag includes the bookmarks of its children",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.TextPositionSelector": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "integer"
+                },
+                "start": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.TextQuoteSelector": {
+            "type": "object",
+            "properties": {
+                "exact": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "suffix": {
+                    "type": "string"
                 }
             }
         },
@@ -1019,6 +4850,106 @@ const docTemplate = `{
                     "type": "boolean"
                 }
             }
+        },
+        "model.Webhook": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "description": "AccountID is zero for instance webhooks, which only owners manage",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.WebhookEvent"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "secret": {
+                    "description": "Secret signs the deliveries, it is only returned when the webhook is created",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "event": {
+                    "$ref": "#/definitions/model.WebhookEvent"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "payload": {
+                    "description": "Payload is the JSON body sent to the webhook",
+                    "type": "string"
+                },
+                "response_body": {
+                    "type": "string"
+                },
+                "response_status": {
+                    "description": "ResponseStatus and ResponseBody are the answer to the last attempt, the body is truncated",
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.WebhookDeliveryStatus"
+                },
+                "webhook_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.WebhookDeliveryStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "succeeded",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "WebhookDeliveryStatusPending",
+                "WebhookDeliveryStatusSucceeded",
+                "WebhookDeliveryStatusFailed"
+            ]
+        },
+        "model.WebhookEvent": {
+            "type": "string",
+            "enum": [
+                "bookmark.created",
+                "bookmark.updated",
+                "bookmark.deleted",
+                "bookmark.archived",
+                "tag.created",
+                "tag.deleted"
+            ],
+            "x-enum-varnames": [
+                "WebhookEventBookmarkCreated",
+                "WebhookEventBookmarkUpdated",
+                "WebhookEventBookmarkDeleted",
+                "WebhookEventBookmarkArchived",
+                "WebhookEventTagCreated",
+                "WebhookEventTagDeleted"
+            ]
         }
     }
 }`
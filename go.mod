@@ -9,6 +9,7 @@ require (
 	github.com/blang/semver v3.5.1+incompatible
 	github.com/disintegration/imaging v1.6.2
 	github.com/fatih/color v1.18.0
+	github.com/go-pdf/fpdf v0.9.0
 	github.com/go-shiori/go-epub v1.2.2-0.20241010194245-bd691046db94
 	github.com/go-shiori/go-readability v0.0.0-20250217085726-9f5bf5ca7612
 	github.com/go-shiori/warc v0.0.0-20200621032813-359908319d1d
@@ -18,6 +19,7 @@ require (
 	github.com/huandu/go-sqlbuilder v1.37.0
 	github.com/jmoiron/sqlx v1.4.0
 	github.com/julienschmidt/httprouter v1.3.0
+	github.com/ledongthuc/pdf v0.0.0-20260907135840-6c8c28e0e8a0
 	github.com/lib/pq v1.10.9
 	github.com/mattn/go-sqlite3 v1.14.32
 	github.com/muesli/go-app-paths v0.2.2
@@ -46,6 +46,7 @@ func addHandler(cmd *cobra.Command, args []string) {
 
 	// Create bookmark item
 	book := model.BookmarkDTO{
+		AccountID:     bookmarkOwnerID(cmd.Context(), deps),
 		URL:           url,
 		Title:         title,
 		Excerpt:       excerpt,
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	fp "path/filepath"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/spf13/cobra"
+)
+
+// backupPassphraseEnv is the environment variable holding the passphrase of encrypted backups
+const backupPassphraseEnv = "SHIORI_BACKUP_PASSPHRASE"
+
+func backupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup target-file",
+		Short: "Back up the data and files of this instance into a single archive",
+		Long: "Back up the accounts, tags, collections and bookmarks, with their content, the rules, saved searches, " +
+			"webhooks, subscriptions and highlights, and the thumbnails, archives and ebooks into a single archive " +
+			"that can be restored into any supported database. API tokens, sessions, feed tokens and " +
+			"two-factor authentication are not backed up. " +
+			"The backup is encrypted when --encrypt is set or " + backupPassphraseEnv + " holds a passphrase.",
+		Args: cobra.ExactArgs(1),
+		Run:  backupHandler,
+	}
+
+	cmd.Flags().BoolP("encrypt", "e", false, "Encrypt the backup with a passphrase asked in the terminal")
+
+	return cmd
+}
+
+func backupHandler(cmd *cobra.Command, args []string) {
+	_, deps := initShiori(cmd.Context(), cmd)
+
+	encrypt, _ := cmd.Flags().GetBool("encrypt")
+	passphrase := os.Getenv(backupPassphraseEnv)
+
+	if encrypt && passphrase == "" {
+		var err error
+		passphrase, err = readPassphrase("Passphrase: ")
+		if err != nil {
+			cError.Printf("Failed to read passphrase: %v\n", err)
+			os.Exit(1)
+		}
+
+		confirmation, err := readPassphrase("Repeat passphrase: ")
+		if err != nil {
+			cError.Printf("Failed to read passphrase: %v\n", err)
+			os.Exit(1)
+		}
+
+		if passphrase == "" || passphrase != confirmation {
+			cError.Println("Passphrases are empty or don't match")
+			os.Exit(1)
+		}
+	}
+
+	// Make sure destination directory exist
+	if err := os.MkdirAll(fp.Dir(args[0]), model.DataDirPerm); err != nil {
+		cError.Printf("Failed to create destination directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	dstFile, err := os.Create(args[0])
+	if err != nil {
+		cError.Printf("Failed to create destination file: %v\n", err)
+		os.Exit(1)
+	}
+	defer dstFile.Close()
+
+	manifest, err := deps.Domains().Backup().Backup(cmd.Context(), dstFile, passphrase)
+	if err == nil {
+		err = dstFile.Sync()
+	}
+
+	if err != nil {
+		dstFile.Close()
+		os.Remove(args[0])
+		cError.Printf("Failed to back up: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Backup finished: %d accounts, %d tags, %d collections, %d bookmarks and %d files\n",
+		manifest.Accounts, manifest.Tags, manifest.Collections, manifest.Bookmarks, manifest.StorageFiles)
+}
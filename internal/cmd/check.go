@@ -2,11 +2,9 @@ package cmd
 
 import (
 	"fmt"
-	"net/http"
 	"os"
 	"sort"
 	"sync"
-	"time"
 
 	"github.com/go-shiori/shiori/internal/model"
 	"github.com/spf13/cobra"
@@ -17,6 +15,8 @@ func checkCmd() *cobra.Command {
 		Use:   "check",
 		Short: "Find bookmarked sites that no longer exists on the internet",
 		Long: "Check all bookmarks and find bookmarked sites that no longer exists on the internet. " +
+			"Sites answering with an error status are reported as well, and every result is stored " +
+			"so it can be queried from the API. " +
 			"It might take a long time depending on how many bookmarks that you have and want to check. " +
 			"If there are no arguments, it will check ALL of your bookmarks.",
 		Run: checkHandler,
@@ -60,22 +60,34 @@ func checkHandler(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	// Create HTTP client
-	httpClient := &http.Client{Timeout: time.Minute}
-
 	// Test each bookmark item
 	unreachableIDs := []int{}
+	nBookmark := len(bookmarks)
+	logIndex := 0
 
+	mx := sync.Mutex{}
 	wg := sync.WaitGroup{}
-	chDone := make(chan struct{})
-	chProblem := make(chan int, 10)
-	chMessage := make(chan interface{}, 10)
-	semaphore := make(chan struct{}, 10)
+	semaphore := make(chan struct{}, deps.Config().LinkCheck.Concurrency)
+
+	// report prints the progress of the check, collecting the unreachable bookmarks
+	report := func(id int, msg interface{}) {
+		mx.Lock()
+		defer mx.Unlock()
+
+		logIndex++
+		switch msg.(type) {
+		case error:
+			unreachableIDs = append(unreachableIDs, id)
+			cError.Printf("[%d/%d] %v\n", logIndex, nBookmark, msg)
+		case string:
+			cInfo.Printf("[%d/%d] %s\n", logIndex, nBookmark, msg)
+		}
+	}
 
-	for i, book := range bookmarks {
+	for _, book := range bookmarks {
 		wg.Add(1)
 
-		go func(i int, book model.BookmarkDTO) {
+		go func(book model.BookmarkDTO) {
 			// Make sure to finish the WG
 			defer wg.Done()
 
@@ -85,46 +97,27 @@ func checkHandler(cmd *cobra.Command, args []string) {
 				<-semaphore
 			}()
 
-			// Ping bookmark's URL
-			_, err := httpClient.Get(book.URL)
+			// Request bookmark's URL and store the result
+			check, err := deps.Domains().LinkChecker().CheckBookmark(cmd.Context(), &book)
 			if err != nil {
-				chProblem <- book.ID
-				chMessage <- fmt.Errorf("failed to reach %s: %v", book.URL, err)
+				report(book.ID, fmt.Errorf("failed to check %s: %v", book.URL, err))
 				return
 			}
 
-			// Send success message
-			chMessage <- fmt.Sprintf("Reached %s", book.URL)
-		}(i, book)
-	}
-
-	// Watch messages from channels
-	go func(nBookmark int) {
-		logIndex := 0
-
-		for {
-			select {
-			case <-chDone:
-				cInfo.Println("Check finished")
-				return
-			case id := <-chProblem:
-				unreachableIDs = append(unreachableIDs, id)
-			case msg := <-chMessage:
-				logIndex++
-
-				switch msg.(type) {
-				case error:
-					cError.Printf("[%d/%d] %v\n", logIndex, nBookmark, msg)
-				case string:
-					cInfo.Printf("[%d/%d] %s\n", logIndex, nBookmark, msg)
-				}
+			switch check.Status() {
+			case model.LinkStatusBroken:
+				report(book.ID, fmt.Errorf("failed to reach %s: %s", book.URL, check.Error))
+			case model.LinkStatusRedirected:
+				report(book.ID, fmt.Sprintf("Reached %s, redirected to %s", book.URL, check.FinalURL))
+			default:
+				report(book.ID, fmt.Sprintf("Reached %s", book.URL))
 			}
-		}
-	}(len(bookmarks))
+		}(book)
+	}
 
-	// Wait until all download finished
+	// Wait until all checks finished
 	wg.Wait()
-	close(chDone)
+	cInfo.Println("Check finished")
 
 	// Print the unreachable bookmarks
 	fmt.Println()
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/go-shiori/shiori/internal/database"
+	"github.com/spf13/cobra"
+)
+
+func dbCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "db",
+		Short: "Manage the Shiori database",
+	}
+
+	cmd.AddCommand(dbCopyCmd())
+
+	return cmd
+}
+
+func dbCopyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "copy",
+		Short: "Copy all data from a database into another one",
+		Long: "Copy the accounts, tags and bookmarks, with their content, archive snapshots, link checks and highlights, " +
+			"from a database into another one, which can use a different engine. Both are given as database URLs, " +
+			"like SHIORI_DATABASE_URL. The target database is migrated first and must be empty. " +
+			"IDs are kept, so the storage directory can be used as is with the new database. " +
+			"The text indexed from the archives isn't copied, run the reindex command with the new database.",
+		Args: cobra.NoArgs,
+		Run:  dbCopyHandler,
+	}
+
+	cmd.Flags().String("from", "", "URL of the database to copy from")
+	cmd.Flags().String("to", "", "URL of the database to copy into")
+	cmd.Flags().Int("batch-size", 100, "Number of rows written at once")
+	cmd.MarkFlagRequired("from")
+	cmd.MarkFlagRequired("to")
+
+	return cmd
+}
+
+func dbCopyHandler(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
+
+	fromURL, _ := cmd.Flags().GetString("from")
+	toURL, _ := cmd.Flags().GetString("to")
+	batchSize, _ := cmd.Flags().GetInt("batch-size")
+
+	if fromURL == toURL {
+		cError.Println("Source and target databases must be different")
+		os.Exit(1)
+	}
+
+	src, err := database.Connect(ctx, fromURL)
+	if err != nil {
+		cError.Printf("Failed to open source database: %v\n", err)
+		os.Exit(1)
+	}
+
+	dst, err := database.Connect(ctx, toURL)
+	if err != nil {
+		cError.Printf("Failed to open target database: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Both schemas must be up to date so they have the same tables
+	if err := src.Migrate(ctx); err != nil {
+		cError.Printf("Failed to migrate source database: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := dst.Migrate(ctx); err != nil {
+		cError.Printf("Failed to migrate target database: %v\n", err)
+		os.Exit(1)
+	}
+
+	counts, err := database.Copy(ctx, src, dst, database.CopyOptions{
+		BatchSize: batchSize,
+		Progress: func(table string, copied int, total int) {
+			cInfo.Printf("Copied %d of %d %s rows\n", copied, total, table)
+		},
+	})
+	if err != nil {
+		cError.Printf("Failed to copy database: %v\n", err)
+		os.Exit(1)
+	}
+
+	mismatch := false
+	fmt.Printf("%-18s %10s %10s\n", "Table", "Source", "Target")
+	for _, count := range counts {
+		fmt.Printf("%-18s %10d %10d\n", count.Table, count.Source, count.Target)
+		mismatch = mismatch || count.Source != count.Target
+	}
+
+	if mismatch {
+		cError.Println("Row counts don't match, the copy is incomplete")
+		os.Exit(1)
+	}
+
+	fmt.Println("Copy finished")
+}
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/go-shiori/shiori/internal/model"
 	"github.com/spf13/cobra"
 )
 
@@ -54,7 +55,7 @@ func deleteHandler(cmd *cobra.Command, args []string) {
 	}
 
 	// Delete bookmarks from database
-	err = deps.Database().DeleteBookmarks(cmd.Context(), ids...)
+	err = deps.Database().DeleteBookmarks(cmd.Context(), 0, ids...)
 	if err != nil {
 		cError.Printf("Failed to delete bookmarks: %v\n", err)
 		os.Exit(1)
@@ -71,9 +72,11 @@ func deleteHandler(cmd *cobra.Command, args []string) {
 			strID := strconv.Itoa(id)
 			imgPath := fp.Join(cfg.Storage.DataDir, "thumb", strID)
 			archivePath := fp.Join(cfg.Storage.DataDir, "archive", strID)
+			snapshotsDir := fp.Join(cfg.Storage.DataDir, model.GetArchiveSnapshotsDir(&model.BookmarkDTO{ID: id}))
 
 			os.Remove(imgPath)
 			os.Remove(archivePath)
+			os.RemoveAll(snapshotsDir)
 		}
 	}
 
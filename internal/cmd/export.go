@@ -4,93 +4,95 @@ import (
 	"fmt"
 	"os"
 	fp "path/filepath"
-	"strings"
-	"time"
 
+	"github.com/go-shiori/shiori/internal/core"
 	"github.com/go-shiori/shiori/internal/model"
 	"github.com/spf13/cobra"
 )
 
 func exportCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "export target-file",
-		Short: "Export bookmarks into HTML file in Netscape Bookmark format",
-		Args:  cobra.ExactArgs(1),
-		Run:   exportHandler,
+		Use:   "export target",
+		Short: "Export bookmarks into Netscape Bookmark HTML, JSON, CSV or Markdown files",
+		Long: "Export bookmarks into a file. The netscape format (default) can be imported by browsers, " +
+			"json keeps every field of the bookmarks including their content and csv suits spreadsheets. " +
+			"The markdown format writes one file per bookmark into the target directory, " +
+			"with its metadata in the front matter followed by its readable content.",
+		Args: cobra.ExactArgs(1),
+		Run:  exportHandler,
 	}
 
+	cmd.Flags().StringP("format", "f", string(model.ExportFormatNetscape), "Format of the export: netscape, json, csv or markdown")
+
 	return cmd
 }
 
 func exportHandler(cmd *cobra.Command, args []string) {
 	_, deps := initShiori(cmd.Context(), cmd)
 
-	// Fetch bookmarks from database
-	bookmarks, err := deps.Database().GetBookmarks(cmd.Context(), model.DBGetBookmarksOptions{})
+	formatFlag, _ := cmd.Flags().GetString("format")
+	format := model.ExportFormat(formatFlag)
+	if err := format.IsValid(); err != nil {
+		cError.Println(err)
+		os.Exit(1)
+	}
+
+	// Check there is something to export
+	count, err := deps.Domains().Bookmarks().CountBookmarks(cmd.Context(), model.ListBookmarksOptions{})
 	if err != nil {
 		cError.Printf("Failed to get bookmarks: %v\n", err)
 		os.Exit(1)
 	}
 
-	if len(bookmarks) == 0 {
+	if count == 0 {
 		cError.Println("No saved bookmarks yet")
 		return
 	}
 
-	// Make sure destination directory exist
-	dstDir := fp.Dir(args[0])
-	if err := os.MkdirAll(dstDir, model.DataDirPerm); err != nil {
-		cError.Printf("Error crating destination directory: %s", err)
-	}
+	var exporter model.BookmarkExporter
+	var dstFile *os.File
 
-	// Create destination file
-	dstFile, err := os.Create(args[0])
-	if err != nil {
-		cError.Printf("Failed to create destination file: %v\n", err)
-		os.Exit(1)
-	}
-	defer dstFile.Close()
-
-	// Write exported bookmark to file
-	fmt.Fprintln(dstFile, ``+
-		`<!DOCTYPE NETSCAPE-Bookmark-file-1>`+
-		`<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">`+
-		`<TITLE>Bookmarks</TITLE>`+
-		`<H1>Bookmarks</H1>`+
-		`<DL>`)
-
-	for _, book := range bookmarks {
-		// Create Unix timestamp for bookmark
-		modifiedTime, err := time.Parse(model.DatabaseDateFormat, book.ModifiedAt)
+	if format == model.ExportFormatMarkdown {
+		exporter, err = core.NewMarkdownDirExporter(args[0])
 		if err != nil {
-			modifiedTime = time.Now()
+			cError.Printf("Failed to create destination directory: %v\n", err)
+			os.Exit(1)
 		}
-		unixTimestamp := modifiedTime.Unix()
-
-		// Create tags for bookmarks
-		tags := []string{}
-		for _, tag := range book.Tags {
-			tags = append(tags, tag.Name)
+	} else {
+		// Make sure destination directory exist
+		dstDir := fp.Dir(args[0])
+		if err := os.MkdirAll(dstDir, model.DataDirPerm); err != nil {
+			cError.Printf("Error crating destination directory: %s", err)
 		}
-		strTags := strings.Join(tags, ",")
 
-		// Make sure title is valid
-		book.Title = validateTitle(book.Title, book.URL)
+		// Create destination file
+		dstFile, err = os.Create(args[0])
+		if err != nil {
+			cError.Printf("Failed to create destination file: %v\n", err)
+			os.Exit(1)
+		}
+		defer dstFile.Close()
 
-		// Write to file
-		exportLine := fmt.Sprintf(`<DT><A HREF="%s" ADD_DATE="%d" LAST_MODIFIED="%d" TAGS="%s">%s</A>`,
-			book.URL, unixTimestamp, unixTimestamp, strTags, book.Title)
-		fmt.Fprintln(dstFile, exportLine)
+		exporter, err = core.NewBookmarkExporter(dstFile, format)
+		if err != nil {
+			cError.Printf("Failed to export the bookmarks: %v\n", err)
+			os.Exit(1)
+		}
 	}
 
-	fmt.Fprintln(dstFile, "</DL>")
-
-	// Flush data to storage
-	err = dstFile.Sync()
-	if err != nil {
+	// Write exported bookmarks
+	if err := deps.Domains().Bookmarks().ExportBookmarks(cmd.Context(), exporter, model.ListBookmarksOptions{}); err != nil {
 		cError.Printf("Failed to export the bookmarks: %v\n", err)
 		os.Exit(1)
 	}
 
+	// Flush data to storage
+	if dstFile != nil {
+		if err := dstFile.Sync(); err != nil {
+			cError.Printf("Failed to export the bookmarks: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	fmt.Println("Export finished")
 }
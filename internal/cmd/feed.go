@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/spf13/cobra"
+)
+
+func feedCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "feed",
+		Short: "Manage the feeds whose new items are saved as bookmarks",
+	}
+
+	cmd.AddCommand(
+		feedAddCmd(),
+		feedListCmd(),
+		feedRemoveCmd(),
+		feedPollCmd(),
+	)
+
+	return cmd
+}
+
+func feedAddCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add url",
+		Short: "Subscribe to a RSS, Atom or JSON feed",
+		Long: "Subscribe to a RSS, Atom or JSON feed. Its new items are saved as bookmarks each time " +
+			"it is polled, either by the server on SHIORI_SUBSCRIPTIONS_POLL_INTERVAL or with the poll command. " +
+			"Items whose URL is bookmarked already are skipped.",
+		Args: cobra.ExactArgs(1),
+		Run:  feedAddHandler,
+	}
+
+	cmd.Flags().StringP("title", "i", "", "Custom title for this feed, its own title is used if empty")
+	cmd.Flags().StringSliceP("tags", "t", []string{}, "Comma-separated tags for the bookmarks saved from this feed")
+	cmd.Flags().BoolP("no-archival", "a", false, "Save bookmarks without creating offline archive")
+	cmd.Flags().Bool("ebook", false, "Create an ebook of the saved bookmarks")
+
+	return cmd
+}
+
+func feedAddHandler(cmd *cobra.Command, args []string) {
+	_, deps := initShiori(cmd.Context(), cmd)
+
+	title, _ := cmd.Flags().GetString("title")
+	tags, _ := cmd.Flags().GetStringSlice("tags")
+	noArchival, _ := cmd.Flags().GetBool("no-archival")
+	ebook, _ := cmd.Flags().GetBool("ebook")
+
+	subscription, err := deps.Domains().Subscriptions().CreateSubscription(cmd.Context(), model.Subscription{
+		AccountID:     bookmarkOwnerID(cmd.Context(), deps),
+		URL:           args[0],
+		Title:         title,
+		Tags:          tags,
+		CreateArchive: !noArchival,
+		CreateEbook:   ebook,
+	})
+	if err != nil {
+		cError.Printf("Failed to subscribe: %v\n", err)
+		os.Exit(1)
+	}
+
+	cInfo.Printf("Subscribed to %s with ID %d\n", subscription.URL, subscription.ID)
+}
+
+func feedListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the subscribed feeds",
+		Args:  cobra.NoArgs,
+		Run:   feedListHandler,
+	}
+}
+
+func feedListHandler(cmd *cobra.Command, args []string) {
+	_, deps := initShiori(cmd.Context(), cmd)
+
+	subscriptions, err := deps.Domains().Subscriptions().ListSubscriptions(cmd.Context(), 0)
+	if err != nil {
+		cError.Printf("Failed to list subscriptions: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(subscriptions) == 0 {
+		fmt.Println("No feed subscriptions")
+		return
+	}
+
+	for _, subscription := range subscriptions {
+		cIndex.Printf("%d. ", subscription.ID)
+		cTitle.Println(subscription.Title)
+		cURL.Printf("   %s\n", subscription.URL)
+
+		if len(subscription.Tags) > 0 {
+			fmt.Print("   ")
+			for _, tag := range subscription.Tags {
+				cTag.Printf("#%s ", tag)
+			}
+			fmt.Println()
+		}
+
+		if subscription.LastPolledAt != nil {
+			fmt.Printf("   Last polled at %s\n", *subscription.LastPolledAt)
+		}
+
+		if subscription.LastError != "" {
+			cError.Printf("   %s\n", subscription.LastError)
+		}
+	}
+}
+
+func feedRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove id...",
+		Short: "Unsubscribe from feeds, keeping the bookmarks saved from them",
+		Args:  cobra.MinimumNArgs(1),
+		Run:   feedRemoveHandler,
+	}
+}
+
+func feedRemoveHandler(cmd *cobra.Command, args []string) {
+	_, deps := initShiori(cmd.Context(), cmd)
+
+	ids, err := parseStrIndices(args)
+	if err != nil {
+		cError.Printf("Failed to parse args: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, id := range ids {
+		if err := deps.Domains().Subscriptions().DeleteSubscription(cmd.Context(), model.DBID(id), 0); err != nil {
+			cError.Printf("Failed to remove subscription %d: %v\n", id, err)
+			os.Exit(1)
+		}
+	}
+
+	fmt.Println("Subscription(s) have been removed")
+}
+
+func feedPollCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "poll [id...]",
+		Short: "Save the new items of the subscribed feeds",
+		Long: "Fetch the subscribed feeds and save their new items as bookmarks. " +
+			"If there are no arguments, every feed is polled.",
+		Run: feedPollHandler,
+	}
+}
+
+func feedPollHandler(cmd *cobra.Command, args []string) {
+	_, deps := initShiori(cmd.Context(), cmd)
+
+	ids, err := parseStrIndices(args)
+	if err != nil {
+		cError.Printf("Failed to parse args: %v\n", err)
+		os.Exit(1)
+	}
+
+	dbIDs := make([]model.DBID, len(ids))
+	for i, id := range ids {
+		dbIDs[i] = model.DBID(id)
+	}
+
+	results, err := deps.Domains().Subscriptions().PollSubscriptions(cmd.Context(), dbIDs)
+	for _, result := range results {
+		if result.Error != "" {
+			cError.Printf("Feed %d: %s\n", result.SubscriptionID, result.Error)
+			continue
+		}
+
+		if result.Failed > 0 {
+			cError.Printf("Feed %d: %d saved, %d skipped, %d failed\n", result.SubscriptionID, len(result.Created), result.Skipped, result.Failed)
+			continue
+		}
+
+		cInfo.Printf("Feed %d: %d saved, %d skipped\n", result.SubscriptionID, len(result.Created), result.Skipped)
+	}
+
+	if err != nil {
+		cError.Printf("Failed to poll feeds: %v\n", err)
+		os.Exit(1)
+	}
+
+	// The content of the new bookmarks is downloaded by the server job workers
+	if len(results) > 0 {
+		fmt.Println("The content of the saved bookmarks is downloaded by the server")
+	}
+}
@@ -1,9 +1,10 @@
 package cmd
 
 import (
-	"database/sql"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
@@ -15,15 +16,38 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// Tags given to imported bookmarks to keep the reading state of services that have one
+const (
+	importUnreadTag   = "unread"
+	importArchivedTag = "archived"
+)
+
+// importFormats are the export files that can be imported, with the parser of each one
+var importFormats = map[string]func(imp *bookmarkImporter, src io.Reader) error{
+	"netscape":   parseNetscapeExport,
+	"pocket":     parsePocketExport,
+	"pinboard":   parsePinboardExport,
+	"raindrop":   parseRaindropExport,
+	"instapaper": parseInstapaperExport,
+	"wallabag":   parseWallabagExport,
+	"linkding":   parseLinkdingExport,
+}
+
 func importCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "import source-file",
-		Short: "Import bookmarks from HTML file in Netscape Bookmark format",
-		Args:  cobra.ExactArgs(1),
-		Run:   importHandler,
+		Short: "Import bookmarks from the export file of a browser or bookmarking service",
+		Long: "Import bookmarks from the export file of a browser or bookmarking service. " +
+			"Supported formats are netscape (HTML exported by browsers), pocket (HTML or CSV), " +
+			"pinboard (JSON), raindrop (CSV), instapaper (CSV), wallabag (JSON) and linkding (JSON). " +
+			"Creation dates, tags, descriptions and notes are kept when the export has them, " +
+			"unread or archived bookmarks are tagged as such and netscape folders become collections.",
+		Args: cobra.ExactArgs(1),
+		Run:  importHandler,
 	}
 
-	cmd.Flags().BoolP("generate-tag", "t", false, "Auto generate tag from bookmark's category")
+	cmd.Flags().StringP("format", "f", "netscape", "Format of the export file: netscape, pocket, pinboard, raindrop, instapaper, wallabag or linkding")
+	cmd.Flags().BoolP("generate-tag", "t", false, "Auto generate tag from bookmark's category or folder")
 
 	return cmd
 }
@@ -32,10 +56,17 @@ func importHandler(cmd *cobra.Command, args []string) {
 	_, deps := initShiori(cmd.Context(), cmd)
 
 	// Parse flags
+	format, _ := cmd.Flags().GetString("format")
 	generateTag := cmd.Flags().Changed("generate-tag")
 
+	parse, ok := importFormats[format]
+	if !ok {
+		cError.Printf("Unknown import format %s\n", format)
+		os.Exit(1)
+	}
+
 	// If user doesn't specify, ask if tag need to be generated
-	if !generateTag {
+	if !generateTag && format == "netscape" {
 		var submit string
 		fmt.Print("Add parents folder as tag? (y/N): ")
 		fmt.Scanln(&submit)
@@ -52,112 +83,304 @@ func importHandler(cmd *cobra.Command, args []string) {
 	defer srcFile.Close()
 
 	// Parse bookmark's file
-	bookmarks := []model.BookmarkDTO{}
-	mapURL := make(map[string]struct{})
+	imp := newBookmarkImporter(cmd.Context(), deps.Database(), bookmarkOwnerID(cmd.Context(), deps))
+	imp.generateTag = generateTag
+	imp.fileName = args[0]
 
-	doc, err := goquery.NewDocumentFromReader(srcFile)
-	if err != nil {
+	if err := parse(imp, srcFile); err != nil {
 		cError.Printf("Failed to parse bookmark: %v\n", err)
 		os.Exit(1)
 	}
 
-	doc.Find("dt>a").Each(func(_ int, a *goquery.Selection) {
-		// Get related elements
-		dt := a.Parent()
-		dl := dt.Parent()
-		h3 := dl.Parent().Find("h3").First()
+	// Save bookmark to database
+	bookmarks, err := deps.Database().SaveBookmarks(cmd.Context(), true, imp.bookmarks...)
+	if err != nil {
+		cError.Printf("Failed to save bookmarks: %v\n", err)
+		os.Exit(1)
+	}
 
-		// Get metadata
-		title := a.Text()
-		url, _ := a.Attr("href")
-		strTags, _ := a.Attr("tags")
+	// Print imported bookmark
+	fmt.Println()
+	printBookmarks(bookmarks...)
+}
+
+// importedBookmark is a bookmark read from an export file
+type importedBookmark struct {
+	URL     string
+	Title   string
+	Excerpt string
+	// Notes are added after the excerpt, bookmarks have no field of their own for them
+	Notes string
+	Tags  []string
+	// Folders are the names of the folders holding the bookmark, top level first. They are
+	// imported as nested collections.
+	Folders    []string
+	Public     bool
+	Unread     bool
+	Archived   bool
+	CreatedAt  time.Time
+	ModifiedAt time.Time
+}
+
+// bookmarkImporter collects the bookmarks of an export file, skipping the ones with an
+// invalid URL or already present in the file or in the database.
+type bookmarkImporter struct {
+	ctx       context.Context
+	db        model.DB
+	accountID model.DBID
+	// Add the category or folder of the bookmarks as a tag
+	generateTag bool
+	// Name of the export file, some formats are told apart by the extension
+	fileName string
+
+	mapURL    map[string]struct{}
+	bookmarks []model.BookmarkDTO
+
+	// collections maps the parent and name of the collections of the account to their ID,
+	// loaded when the first folder is imported
+	collections map[importedCollection]model.DBID
+	// positions is the next position among the children of each collection, zero for the top level
+	positions map[model.DBID]int
+}
+
+// importedCollection identifies a collection by its parent, zero for the top level, and its name
+type importedCollection struct {
+	parentID model.DBID
+	name     string
+}
+
+func newBookmarkImporter(ctx context.Context, db model.DB, accountID model.DBID) *bookmarkImporter {
+	return &bookmarkImporter{
+		ctx:       ctx,
+		db:        db,
+		accountID: accountID,
+		mapURL:    make(map[string]struct{}),
+		bookmarks: []model.BookmarkDTO{},
+	}
+}
 
-		dateStr, fieldExists := a.Attr("last_modified")
-		if !fieldExists {
-			dateStr, _ = a.Attr("add_date")
+// add converts the imported bookmark and queues it to be saved.
+func (imp *bookmarkImporter) add(item importedBookmark) {
+	url, err := core.RemoveUTMParams(item.URL)
+	if err != nil {
+		cError.Printf("Skip %s: URL is not valid\n", item.URL)
+		return
+	}
+
+	excerpt := strings.TrimSpace(item.Excerpt)
+	if notes := strings.TrimSpace(item.Notes); notes != "" && notes != excerpt {
+		if excerpt != "" {
+			excerpt += "\n\n"
 		}
+		excerpt += notes
+	}
 
-		// Using now as default date in case no last_modified nor add_date are present
-		modifiedDate := time.Now()
-		if dateStr != "" {
-			modifiedTsInt, err := strconv.Atoi(dateStr)
-			if err != nil {
-				cError.Printf("Skip %s: date field is not valid: %s", url, err)
-				return
-			}
+	tagNames := item.Tags
+	if item.Unread {
+		tagNames = append(tagNames, importUnreadTag)
+	}
+	if item.Archived {
+		tagNames = append(tagNames, importArchivedTag)
+	}
 
-			modifiedDate = time.Unix(int64(modifiedTsInt), 0)
+	tags := []model.TagDTO{}
+	seenTags := make(map[string]struct{})
+	for _, name := range tagNames {
+		name = normalizeSpace(name)
+		if _, seen := seenTags[name]; name == "" || seen {
+			continue
 		}
+		seenTags[name] = struct{}{}
+		tags = append(tags, model.TagDTO{Tag: model.Tag{Name: name}})
+	}
 
-		// Clean up URL
-		url, err = core.RemoveUTMParams(url)
+	bookmark := model.BookmarkDTO{
+		AccountID: imp.accountID,
+		URL:       url,
+		Title:     validateTitle(item.Title, url),
+		Excerpt:   excerpt,
+		Tags:      tags,
+	}
+
+	if item.Public {
+		bookmark.Public = 1
+	}
+
+	if !item.CreatedAt.IsZero() {
+		bookmark.CreatedAt = item.CreatedAt.UTC().Format(model.DatabaseDateFormat)
+		bookmark.ModifiedAt = bookmark.CreatedAt
+	}
+
+	if !item.ModifiedAt.IsZero() {
+		bookmark.ModifiedAt = item.ModifiedAt.UTC().Format(model.DatabaseDateFormat)
+	}
+
+	if !imp.addBookmark(bookmark) || len(item.Folders) == 0 {
+		return
+	}
+
+	// Folders are only created for the bookmarks to save, the bookmark is kept out of them on error
+	collectionID, err := imp.collection(item.Folders)
+	if err != nil {
+		cError.Printf("Failed to import the folders of %s: %v\n", url, err)
+		return
+	}
+	imp.bookmarks[len(imp.bookmarks)-1].CollectionID = collectionID
+}
+
+// collection returns the ID of the collection at the path of folder names, creating the
+// missing ones after the existing collections of the account.
+func (imp *bookmarkImporter) collection(folders []string) (*model.DBID, error) {
+	if imp.collections == nil {
+		existing, err := imp.db.ListCollections(imp.ctx, imp.accountID)
 		if err != nil {
-			cError.Printf("Skip %s: URL is not valid\n", url)
-			return
+			return nil, fmt.Errorf("failed getting collections, %w", err)
 		}
 
-		// Make sure title is valid Utf-8
-		title = validateTitle(title, url)
+		imp.collections = make(map[importedCollection]model.DBID)
+		imp.positions = make(map[model.DBID]int)
+		for _, collection := range existing {
+			if collection.AccountID != imp.accountID {
+				continue
+			}
+
+			key := importedCollection{name: collection.Name}
+			if collection.ParentID != nil {
+				key.parentID = *collection.ParentID
+			}
+			if _, exists := imp.collections[key]; !exists {
+				imp.collections[key] = collection.ID
+			}
+			imp.positions[key.parentID] = max(imp.positions[key.parentID], collection.Position+1)
+		}
+	}
 
-		// Check if the URL already exist before, both in bookmark
-		// file or in database
-		if _, exist := mapURL[url]; exist {
-			cError.Printf("Skip %s: URL already exists\n", url)
-			return
+	var parentID *model.DBID
+	for _, name := range folders {
+		key := importedCollection{name: name}
+		if parentID != nil {
+			key.parentID = *parentID
 		}
 
-		_, exist, err := deps.Database().GetBookmark(cmd.Context(), 0, url)
-		if err != nil && !errors.Is(err, sql.ErrNoRows) {
-			cError.Printf("Skip %s: Get Bookmark fail, %v", url, err)
-			return
+		id, exists := imp.collections[key]
+		if !exists {
+			collection, err := imp.db.CreateCollection(imp.ctx, model.Collection{
+				AccountID: imp.accountID,
+				ParentID:  parentID,
+				Name:      name,
+				Position:  imp.positions[key.parentID],
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed creating collection %s, %w", name, err)
+			}
+
+			id = collection.ID
+			imp.collections[key] = id
+			imp.positions[key.parentID]++
 		}
 
-		if exist {
-			cError.Printf("Skip %s: URL already exists\n", url)
-			mapURL[url] = struct{}{}
-			return
+		parentID = &id
+	}
+
+	return parentID, nil
+}
+
+// addBookmark queues a bookmark to be saved unless its URL was already imported,
+// reporting whether it was queued.
+func (imp *bookmarkImporter) addBookmark(bookmark model.BookmarkDTO) bool {
+	if err := imp.checkDuplicate(bookmark.URL); err != nil {
+		cError.Printf("Skip %s: %v\n", bookmark.URL, err)
+		return false
+	}
+
+	imp.mapURL[bookmark.URL] = struct{}{}
+	imp.bookmarks = append(imp.bookmarks, bookmark)
+	return true
+}
+
+// checkDuplicate checks if the URL already exist, both in bookmark file or in database
+func (imp *bookmarkImporter) checkDuplicate(url string) error {
+	if _, exists := imp.mapURL[url]; exists {
+		return errors.New("URL already exists")
+	}
+
+	_, exists, err := imp.db.GetBookmark(imp.ctx, 0, url, imp.accountID)
+	if err != nil {
+		return fmt.Errorf("failed getting bookmark, %w", err)
+	}
+
+	if exists {
+		imp.mapURL[url] = struct{}{}
+		return errors.New("URL already exists")
+	}
+
+	return nil
+}
+
+// Parse bookmarks from HTML file in Netscape Bookmark format
+func parseNetscapeExport(imp *bookmarkImporter, src io.Reader) error {
+	doc, err := goquery.NewDocumentFromReader(src)
+	if err != nil {
+		return err
+	}
+
+	doc.Find("dt>a").Each(func(_ int, a *goquery.Selection) {
+		// Walk up the folders holding the bookmark: each folder is a DT with its name
+		// in a H3 followed by the DL of its content
+		folders := []string{}
+		for dl := a.Parent().Parent(); dl.Is("dl"); {
+			folder := dl.Parent()
+			h3 := folder.ChildrenFiltered("h3").First()
+			if !folder.Is("dt") || h3.Length() == 0 {
+				break
+			}
+
+			if name := normalizeSpace(h3.Text()); name != "" {
+				folders = append([]string{name}, folders...)
+			}
+			dl = folder.Parent()
 		}
 
-		// Get bookmark tags
-		tags := []model.TagDTO{}
-		for _, strTag := range strings.Split(strTags, ",") {
-			strTag = normalizeSpace(strTag)
-			if strTag != "" {
-				tags = append(tags, model.TagDTO{
-					Tag: model.Tag{Name: strTag},
-				})
+		// Get metadata
+		title := a.Text()
+		url, _ := a.Attr("href")
+		strTags, _ := a.Attr("tags")
+
+		// Dates are unix timestamps, bookmarks without them are dated when saved
+		var dates [2]time.Time
+		for i, attr := range []string{"add_date", "last_modified"} {
+			dateStr, _ := a.Attr(attr)
+			if dateStr == "" {
+				continue
 			}
+
+			timestamp, err := strconv.ParseInt(dateStr, 10, 64)
+			if err != nil {
+				cError.Printf("Skip %s: date field is not valid: %s\n", url, err)
+				return
+			}
+
+			dates[i] = time.Unix(timestamp, 0)
 		}
 
+		// Get bookmark tags
+		tags := strings.Split(strTags, ",")
+
 		// Get category name for this bookmark
 		// and add it as tags (if necessary)
-		category := normalizeSpace(h3.Text())
-		if category != "" && generateTag {
-			tags = append(tags, model.TagDTO{
-				Tag: model.Tag{Name: category},
-			})
+		if imp.generateTag && len(folders) > 0 {
+			tags = append(tags, folders[len(folders)-1])
 		}
 
-		// Add item to list
-		bookmark := model.BookmarkDTO{
+		imp.add(importedBookmark{
 			URL:        url,
 			Title:      title,
 			Tags:       tags,
-			ModifiedAt: modifiedDate.Format(model.DatabaseDateFormat),
-		}
-
-		mapURL[url] = struct{}{}
-		bookmarks = append(bookmarks, bookmark)
+			Folders:    folders,
+			CreatedAt:  dates[0],
+			ModifiedAt: dates[1],
+		})
 	})
 
-	// Save bookmark to database
-	bookmarks, err = deps.Database().SaveBookmarks(cmd.Context(), true, bookmarks...)
-	if err != nil {
-		cError.Printf("Failed to save bookmarks: %v\n", err)
-		os.Exit(1)
-	}
-
-	// Print imported bookmark
-	fmt.Println()
-	printBookmarks(bookmarks...)
+	return nil
 }
@@ -0,0 +1,307 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// importBool reads the booleans of JSON exports, which depending on the service and its
+// version are written as booleans, numbers or strings like "yes".
+type importBool bool
+
+func (b *importBool) UnmarshalJSON(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case bool:
+		*b = importBool(v)
+	case float64:
+		*b = v != 0
+	case string:
+		*b = v == "yes" || v == "true" || v == "1"
+	case nil:
+		*b = false
+	default:
+		return fmt.Errorf("invalid boolean %s", data)
+	}
+
+	return nil
+}
+
+// parseImportTime reads the dates of the exports, a zero time is returned for empty or
+// unknown values so the bookmark is dated when saved.
+func parseImportTime(value string) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
+	}
+
+	if timestamp, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(timestamp, 0)
+	}
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02T15:04:05"} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date
+		}
+	}
+
+	return time.Time{}
+}
+
+// readImportCSV reads a CSV export, returning its records by column name. The header must
+// have the required columns, compared case insensitively.
+func readImportCSV(src io.Reader, required ...string) ([]map[string]string, error) {
+	reader := csv.NewReader(src)
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, fmt.Errorf("CSV file is empty")
+	}
+
+	header := make([]string, len(records[0]))
+	for i, name := range records[0] {
+		header[i] = strings.ToLower(strings.TrimSpace(name))
+	}
+
+	for _, name := range required {
+		found := false
+		for _, column := range header {
+			found = found || column == name
+		}
+		if !found {
+			return nil, fmt.Errorf("invalid CSV format, header must contain: %s", strings.Join(required, ", "))
+		}
+	}
+
+	rows := make([]map[string]string, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(header))
+		for i, value := range record {
+			if i < len(header) {
+				row[header[i]] = value
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// Parse bookmarks from Pinboard's JSON export
+func parsePinboardExport(imp *bookmarkImporter, src io.Reader) error {
+	var posts []struct {
+		Href        string     `json:"href"`
+		Description string     `json:"description"`
+		Extended    string     `json:"extended"`
+		Time        string     `json:"time"`
+		Shared      importBool `json:"shared"`
+		ToRead      importBool `json:"toread"`
+		Tags        string     `json:"tags"`
+	}
+
+	if err := json.NewDecoder(src).Decode(&posts); err != nil {
+		return err
+	}
+
+	for _, post := range posts {
+		imp.add(importedBookmark{
+			URL:       post.Href,
+			Title:     post.Description,
+			Excerpt:   post.Extended,
+			Tags:      strings.Fields(post.Tags),
+			Public:    bool(post.Shared),
+			Unread:    bool(post.ToRead),
+			CreatedAt: parseImportTime(post.Time),
+		})
+	}
+
+	return nil
+}
+
+// Parse bookmarks from Raindrop.io's CSV export
+func parseRaindropExport(imp *bookmarkImporter, src io.Reader) error {
+	rows, err := readImportCSV(src, "url", "title")
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		tags := strings.Split(row["tags"], ",")
+		if imp.generateTag {
+			tags = append(tags, row["folder"])
+		}
+
+		imp.add(importedBookmark{
+			URL:       row["url"],
+			Title:     row["title"],
+			Excerpt:   row["excerpt"],
+			Notes:     row["note"],
+			Tags:      tags,
+			CreatedAt: parseImportTime(row["created"]),
+		})
+	}
+
+	return nil
+}
+
+// Parse bookmarks from Instapaper's CSV export. Unread and archived bookmarks are kept in
+// folders with those names, next to the folders created by the user.
+func parseInstapaperExport(imp *bookmarkImporter, src io.Reader) error {
+	rows, err := readImportCSV(src, "url", "title", "folder")
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		item := importedBookmark{
+			URL:       row["url"],
+			Title:     row["title"],
+			Excerpt:   row["selection"],
+			CreatedAt: parseImportTime(row["timestamp"]),
+		}
+
+		// Newer exports have a tags column holding a JSON list
+		if tags := strings.TrimSpace(row["tags"]); tags != "" {
+			if err := json.Unmarshal([]byte(tags), &item.Tags); err != nil {
+				item.Tags = strings.Split(tags, ",")
+			}
+		}
+
+		switch folder := strings.TrimSpace(row["folder"]); folder {
+		case "Unread":
+			item.Unread = true
+		case "Archive":
+			item.Archived = true
+		default:
+			if imp.generateTag {
+				item.Tags = append(item.Tags, folder)
+			}
+		}
+
+		imp.add(item)
+	}
+
+	return nil
+}
+
+// Parse bookmarks from wallabag's JSON export
+func parseWallabagExport(imp *bookmarkImporter, src io.Reader) error {
+	var entries []struct {
+		URL         string     `json:"url"`
+		Title       string     `json:"title"`
+		IsArchived  importBool `json:"is_archived"`
+		IsPublic    importBool `json:"is_public"`
+		Tags        []string   `json:"tags"`
+		CreatedAt   string     `json:"created_at"`
+		UpdatedAt   string     `json:"updated_at"`
+		Annotations []struct {
+			Text string `json:"text"`
+		} `json:"annotations"`
+	}
+
+	if err := json.NewDecoder(src).Decode(&entries); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		notes := []string{}
+		for _, annotation := range entry.Annotations {
+			if text := strings.TrimSpace(annotation.Text); text != "" {
+				notes = append(notes, text)
+			}
+		}
+
+		imp.add(importedBookmark{
+			URL:        entry.URL,
+			Title:      entry.Title,
+			Notes:      strings.Join(notes, "\n"),
+			Tags:       entry.Tags,
+			Public:     bool(entry.IsPublic),
+			Archived:   bool(entry.IsArchived),
+			CreatedAt:  parseImportTime(entry.CreatedAt),
+			ModifiedAt: parseImportTime(entry.UpdatedAt),
+		})
+	}
+
+	return nil
+}
+
+// Parse bookmarks from linkding's JSON export, either a list of bookmarks or a response
+// of its bookmarks API.
+func parseLinkdingExport(imp *bookmarkImporter, src io.Reader) error {
+	type linkdingBookmark struct {
+		URL                string     `json:"url"`
+		Title              string     `json:"title"`
+		Description        string     `json:"description"`
+		Notes              string     `json:"notes"`
+		WebsiteTitle       string     `json:"website_title"`
+		WebsiteDescription string     `json:"website_description"`
+		IsArchived         importBool `json:"is_archived"`
+		Unread             importBool `json:"unread"`
+		Shared             importBool `json:"shared"`
+		TagNames           []string   `json:"tag_names"`
+		DateAdded          string     `json:"date_added"`
+		DateModified       string     `json:"date_modified"`
+	}
+
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return err
+	}
+
+	var bookmarks []linkdingBookmark
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var response struct {
+			Results []linkdingBookmark `json:"results"`
+		}
+		err = json.Unmarshal(data, &response)
+		bookmarks = response.Results
+	} else {
+		err = json.Unmarshal(data, &bookmarks)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, bookmark := range bookmarks {
+		title := bookmark.Title
+		if title == "" {
+			title = bookmark.WebsiteTitle
+		}
+
+		excerpt := bookmark.Description
+		if excerpt == "" {
+			excerpt = bookmark.WebsiteDescription
+		}
+
+		imp.add(importedBookmark{
+			URL:        bookmark.URL,
+			Title:      title,
+			Excerpt:    excerpt,
+			Notes:      bookmark.Notes,
+			Tags:       bookmark.TagNames,
+			Public:     bool(bookmark.Shared),
+			Unread:     bool(bookmark.Unread),
+			Archived:   bool(bookmark.IsArchived),
+			CreatedAt:  parseImportTime(bookmark.DateAdded),
+			ModifiedAt: parseImportTime(bookmark.DateModified),
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/database"
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestImporter(t *testing.T) *bookmarkImporter {
+	ctx := context.TODO()
+
+	db, err := database.OpenSQLiteDatabase(ctx, filepath.Join(t.TempDir(), "shiori.db"))
+	require.NoError(t, err)
+	require.NoError(t, db.Migrate(ctx))
+
+	return newBookmarkImporter(ctx, db, 0)
+}
+
+func importTagNames(bookmark model.BookmarkDTO) []string {
+	names := []string{}
+	for _, tag := range bookmark.Tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+func Test_importFormats(t *testing.T) {
+	tests := []struct {
+		format      string
+		fileName    string
+		generateTag bool
+		assertFn    func(t *testing.T, bookmarks []model.BookmarkDTO)
+	}{
+		{
+			format:      "netscape",
+			fileName:    "netscape.html",
+			generateTag: true,
+			assertFn: func(t *testing.T, bookmarks []model.BookmarkDTO) {
+				require.Len(t, bookmarks, 1)
+				require.Equal(t, "Shiori", bookmarks[0].Title)
+				require.ElementsMatch(t, []string{"shiori", "go", "Tools"}, importTagNames(bookmarks[0]))
+				require.Equal(t, "2019-01-01 10:00:00", bookmarks[0].CreatedAt)
+				require.Equal(t, "2019-02-01 10:00:00", bookmarks[0].ModifiedAt)
+			},
+		},
+		{
+			format:   "pinboard",
+			fileName: "pinboard.json",
+			assertFn: func(t *testing.T, bookmarks []model.BookmarkDTO) {
+				require.Len(t, bookmarks, 1)
+				require.Equal(t, "https://github.com/go-shiori/shiori", bookmarks[0].URL)
+				require.Equal(t, "Simple bookmark manager built with Go", bookmarks[0].Excerpt)
+				require.Equal(t, 1, bookmarks[0].Public)
+				require.ElementsMatch(t, []string{"shiori", "go", importUnreadTag}, importTagNames(bookmarks[0]))
+				require.Equal(t, "2019-01-01 10:00:00", bookmarks[0].CreatedAt)
+			},
+		},
+		{
+			format:   "raindrop",
+			fileName: "raindrop.csv",
+			assertFn: func(t *testing.T, bookmarks []model.BookmarkDTO) {
+				require.Len(t, bookmarks, 1)
+				require.Equal(t, "Simple bookmark manager built with Go\n\nMy notes", bookmarks[0].Excerpt)
+				require.ElementsMatch(t, []string{"shiori", "go"}, importTagNames(bookmarks[0]))
+				require.Equal(t, "2019-01-01 10:00:00", bookmarks[0].CreatedAt)
+			},
+		},
+		{
+			format:   "instapaper",
+			fileName: "instapaper.csv",
+			assertFn: func(t *testing.T, bookmarks []model.BookmarkDTO) {
+				require.Len(t, bookmarks, 2)
+				require.Equal(t, "Simple bookmark manager built with Go", bookmarks[0].Excerpt)
+				require.ElementsMatch(t, []string{"shiori", "go", importArchivedTag}, importTagNames(bookmarks[0]))
+				require.Equal(t, "2019-01-01 10:00:00", bookmarks[0].CreatedAt)
+				require.ElementsMatch(t, []string{importUnreadTag}, importTagNames(bookmarks[1]))
+			},
+		},
+		{
+			format:   "wallabag",
+			fileName: "wallabag.json",
+			assertFn: func(t *testing.T, bookmarks []model.BookmarkDTO) {
+				require.Len(t, bookmarks, 1)
+				require.Equal(t, "My notes", bookmarks[0].Excerpt)
+				require.Equal(t, 0, bookmarks[0].Public)
+				require.ElementsMatch(t, []string{"shiori", "go", importArchivedTag}, importTagNames(bookmarks[0]))
+				require.Equal(t, "2019-01-01 10:00:00", bookmarks[0].CreatedAt)
+				require.Equal(t, "2019-02-01 10:00:00", bookmarks[0].ModifiedAt)
+			},
+		},
+		{
+			format:   "linkding",
+			fileName: "linkding.json",
+			assertFn: func(t *testing.T, bookmarks []model.BookmarkDTO) {
+				require.Len(t, bookmarks, 1)
+				require.Equal(t, "Shiori", bookmarks[0].Title)
+				require.Equal(t, "Simple bookmark manager built with Go\n\nMy notes", bookmarks[0].Excerpt)
+				require.Equal(t, 1, bookmarks[0].Public)
+				require.ElementsMatch(t, []string{"shiori", "go", importArchivedTag}, importTagNames(bookmarks[0]))
+				require.Equal(t, "2019-01-01 10:00:00", bookmarks[0].CreatedAt)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			file, err := os.Open("../../testdata/" + tt.fileName)
+			require.NoError(t, err)
+			defer file.Close()
+
+			imp := newTestImporter(t)
+			imp.generateTag = tt.generateTag
+			imp.fileName = tt.fileName
+
+			require.NoError(t, importFormats[tt.format](imp, file))
+			tt.assertFn(t, imp.bookmarks)
+		})
+	}
+}
+
+func Test_bookmarkImporter_skipsSavedBookmarks(t *testing.T) {
+	imp := newTestImporter(t)
+
+	_, err := imp.db.SaveBookmarks(imp.ctx, true, model.BookmarkDTO{
+		URL:   "https://github.com/go-shiori/shiori",
+		Title: "Shiori",
+	})
+	require.NoError(t, err)
+
+	imp.add(importedBookmark{URL: "https://github.com/go-shiori/shiori", Title: "Shiori"})
+	imp.add(importedBookmark{URL: "https://go.dev", Title: "Go"})
+
+	require.Len(t, imp.bookmarks, 1)
+	require.Equal(t, "https://go.dev", imp.bookmarks[0].URL)
+}
+
+func Test_parseNetscapeExport_folders(t *testing.T) {
+	imp := newTestImporter(t)
+
+	existing, err := imp.db.CreateCollection(imp.ctx, model.Collection{Name: "Tools"})
+	require.NoError(t, err)
+
+	src := `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<DL><p>
+    <DT><A HREF="https://example.com/top">Top</A>
+    <DT><H3>Tools</H3>
+    <DL><p>
+        <DT><A HREF="https://github.com/go-shiori/shiori">Shiori</A>
+        <DT><H3>Go</H3>
+        <DL><p>
+            <DT><A HREF="https://go.dev">Go</A>
+        </DL><p>
+    </DL><p>
+    <DT><H3>Empty</H3>
+    <DL><p>
+    </DL><p>
+</DL><p>`
+
+	require.NoError(t, parseNetscapeExport(imp, strings.NewReader(src)))
+	require.Len(t, imp.bookmarks, 3)
+
+	require.Nil(t, imp.bookmarks[0].CollectionID)
+	require.Equal(t, &existing.ID, imp.bookmarks[1].CollectionID)
+
+	collections, err := imp.db.ListCollections(imp.ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, collections, 2)
+
+	golang, _, err := imp.db.GetCollection(imp.ctx, *imp.bookmarks[2].CollectionID)
+	require.NoError(t, err)
+	require.Equal(t, "Go", golang.Name)
+	require.Equal(t, &existing.ID, golang.ParentID)
+}
+
+func Test_importFormats_invalidFile(t *testing.T) {
+	for _, format := range []string{"pinboard", "wallabag", "linkding"} {
+		t.Run(format, func(t *testing.T) {
+			imp := newTestImporter(t)
+			require.Error(t, importFormats[format](imp, strings.NewReader("not json")))
+		})
+	}
+
+	t.Run("csv without required columns", func(t *testing.T) {
+		imp := newTestImporter(t)
+		require.Error(t, parseRaindropExport(imp, strings.NewReader("id,name\n1,Shiori\n")))
+	})
+}
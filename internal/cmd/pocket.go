@@ -1,10 +1,10 @@
 package cmd
 
 import (
-	"context"
 	"encoding/csv"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -42,19 +42,16 @@ func pocketHandler(cmd *cobra.Command, args []string) {
 	}
 	defer srcFile.Close()
 
-	var bookmarks []model.BookmarkDTO
-	switch filepath.Ext(filePath) {
-	case ".html":
-		bookmarks = parseHtmlExport(ctx, deps.Database(), srcFile)
-	case ".csv":
-		bookmarks = parseCsvExport(ctx, deps.Database(), srcFile)
-	default:
-		cError.Println("Invalid file format. Only HTML and CSV are supported.")
+	imp := newBookmarkImporter(ctx, deps.Database(), bookmarkOwnerID(ctx, deps))
+	imp.fileName = filePath
+
+	if err := parsePocketExport(imp, srcFile); err != nil {
+		cError.Println(err)
 		os.Exit(1)
 	}
 
 	// Save bookmark to database
-	bookmarks, err = deps.Database().SaveBookmarks(ctx, true, bookmarks...)
+	bookmarks, err := deps.Database().SaveBookmarks(ctx, true, imp.bookmarks...)
 	if err != nil {
 		cError.Printf("Failed to save bookmarks: %v\n", err)
 		os.Exit(1)
@@ -65,15 +62,23 @@ func pocketHandler(cmd *cobra.Command, args []string) {
 	printBookmarks(bookmarks...)
 }
 
-// Parse bookmarks from HTML file
-func parseHtmlExport(ctx context.Context, db model.DB, srcFile *os.File) []model.BookmarkDTO {
-	bookmarks := []model.BookmarkDTO{}
-	mapURL := make(map[string]struct{})
+// Parse bookmarks from Pocket's export, which can be an HTML or a CSV file
+func parsePocketExport(imp *bookmarkImporter, src io.Reader) error {
+	switch filepath.Ext(imp.fileName) {
+	case ".html":
+		return parseHtmlExport(imp, src)
+	case ".csv":
+		return parseCsvExport(imp, src)
+	default:
+		return errors.New("invalid file format, only HTML and CSV are supported")
+	}
+}
 
-	doc, err := goquery.NewDocumentFromReader(srcFile)
+// Parse bookmarks from HTML file
+func parseHtmlExport(imp *bookmarkImporter, src io.Reader) error {
+	doc, err := goquery.NewDocumentFromReader(src)
 	if err != nil {
-		cError.Println(err)
-		os.Exit(1)
+		return err
 	}
 
 	doc.Find("a").Each(func(_ int, a *goquery.Selection) {
@@ -89,37 +94,26 @@ func parseHtmlExport(ctx context.Context, db model.DB, srcFile *os.File) []model
 			return
 		}
 
-		if err = handleDuplicates(ctx, db, mapURL, url); err != nil {
-			cError.Printf("Skip %s: %v\n", url, err)
-			return
-		}
-
 		// Add item to list
-		bookmark := model.BookmarkDTO{
+		imp.addBookmark(model.BookmarkDTO{
+			AccountID:  imp.accountID,
 			URL:        url,
 			Title:      title,
 			ModifiedAt: timeAdded.Format(model.DatabaseDateFormat),
 			CreatedAt:  timeAdded.Format(model.DatabaseDateFormat),
 			Tags:       tags,
-		}
-
-		mapURL[url] = struct{}{}
-		bookmarks = append(bookmarks, bookmark)
+		})
 	})
 
-	return bookmarks
+	return nil
 }
 
 // Parse bookmarks from CSV file
-func parseCsvExport(ctx context.Context, db model.DB, srcFile *os.File) []model.BookmarkDTO {
-	bookmarks := []model.BookmarkDTO{}
-	mapURL := make(map[string]struct{})
-
-	reader := csv.NewReader(srcFile)
+func parseCsvExport(imp *bookmarkImporter, src io.Reader) error {
+	reader := csv.NewReader(src)
 	records, err := reader.ReadAll()
 	if err != nil {
-		cError.Println(err)
-		os.Exit(1)
+		return err
 	}
 
 	var titleIdx, urlIdx, timeAddedIdx, tagsIdx int
@@ -131,8 +125,7 @@ func parseCsvExport(ctx context.Context, db model.DB, srcFile *os.File) []model.
 			timeAddedIdx = slices.Index(cols, "time_added")
 			tagsIdx = slices.Index(cols, "tags")
 			if titleIdx == -1 || urlIdx == -1 || timeAddedIdx == -1 || tagsIdx == -1 {
-				cError.Printf("Invalid CSV format. Header must contain: title, url, time_added, tags\n")
-				os.Exit(1)
+				return errors.New("invalid CSV format, header must contain: title, url, time_added, tags")
 			}
 			continue
 		}
@@ -144,25 +137,18 @@ func parseCsvExport(ctx context.Context, db model.DB, srcFile *os.File) []model.
 			continue
 		}
 
-		if err = handleDuplicates(ctx, db, mapURL, url); err != nil {
-			cError.Printf("Skip %s: %v\n", url, err)
-			continue
-		}
-
 		// Add item to list
-		bookmark := model.BookmarkDTO{
+		imp.addBookmark(model.BookmarkDTO{
+			AccountID:  imp.accountID,
 			URL:        url,
 			Title:      title,
 			ModifiedAt: timeAdded.Format(model.DatabaseDateFormat),
 			CreatedAt:  timeAdded.Format(model.DatabaseDateFormat),
 			Tags:       tags,
-		}
-
-		mapURL[url] = struct{}{}
-		bookmarks = append(bookmarks, bookmark)
+		})
 	}
 
-	return bookmarks
+	return nil
 }
 
 // Parse metadata and verify it's validity
@@ -201,22 +187,3 @@ func verifyMetadata(title, url, timeAddedStr, tags string) (string, string, time
 
 	return title, url, timeAdded, tagsList, nil
 }
-
-// Checks if the URL already exist, both in bookmark
-// file or in database
-func handleDuplicates(ctx context.Context, db model.DB, mapURL map[string]struct{}, url string) error {
-	if _, exists := mapURL[url]; exists {
-		return errors.New("URL already exists")
-	}
-
-	_, exists, err := db.GetBookmark(ctx, 0, url)
-	if err != nil {
-		return fmt.Errorf("failed getting bookmark, %w", err)
-	}
-
-	if exists {
-		return errors.New("URL already exists")
-	}
-
-	return nil
-}
@@ -48,7 +48,11 @@ func Test_parseCsvExport_old_format(t *testing.T) {
 				t.Fatalf("failed to migrate sqlite database: %v", err)
 			}
 
-			bookmarks := parseCsvExport(ctx, db, file)
+			imp := newBookmarkImporter(ctx, db, 0)
+			if err := parseCsvExport(imp, file); err != nil {
+				t.Fatalf("failed to parse export: %v", err)
+			}
+			bookmarks := imp.bookmarks
 			if len(bookmarks) != 1 {
 				t.Errorf("Expected 1 bookmarks, got %d", len(bookmarks))
 			}
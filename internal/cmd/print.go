@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/go-shiori/shiori/internal/model"
 	"github.com/spf13/cobra"
@@ -23,10 +26,12 @@ func printCmd() *cobra.Command {
 
 	cmd.Flags().BoolP("json", "j", false, "Output data in JSON format")
 	cmd.Flags().BoolP("latest", "l", false, "Sort bookmark by latest instead of ID")
+	cmd.Flags().BoolP("relevance", "r", false, "Sort bookmark by relevance to the text searched, then by latest")
 	cmd.Flags().BoolP("index-only", "i", false, "Only print the index of bookmarks")
-	cmd.Flags().StringP("search", "s", "", "Search bookmark with specified keyword")
+	cmd.Flags().StringP("search", "s", "", "Search bookmark with specified query, like \"site:github.com tag:go -tag:old\"")
 	cmd.Flags().StringSliceP("tags", "t", []string{}, "Print bookmarks with matching tag(s)")
 	cmd.Flags().StringSliceP("exclude-tags", "e", []string{}, "Print bookmarks without these tag(s)")
+	cmd.Flags().String("saved", "", "Print bookmarks matching the saved search with this name")
 
 	return cmd
 }
@@ -40,7 +45,9 @@ func printHandler(cmd *cobra.Command, args []string) {
 	useJSON, _ := cmd.Flags().GetBool("json")
 	indexOnly, _ := cmd.Flags().GetBool("index-only")
 	orderLatest, _ := cmd.Flags().GetBool("latest")
+	orderRelevance, _ := cmd.Flags().GetBool("relevance")
 	excludedTags, _ := cmd.Flags().GetStringSlice("exclude-tags")
+	savedName, _ := cmd.Flags().GetString("saved")
 
 	// Convert args to ids
 	ids, err := parseStrIndices(args)
@@ -54,15 +61,31 @@ func printHandler(cmd *cobra.Command, args []string) {
 	if orderLatest {
 		orderMethod = model.ByLastModified
 	}
+	if orderRelevance {
+		orderMethod = model.ByRelevance
+	}
 
-	searchOptions := model.DBGetBookmarksOptions{
-		IDs:          ids,
+	listOptions := model.ListBookmarksOptions{
 		Tags:         tags,
 		ExcludedTags: excludedTags,
 		Keyword:      keyword,
 		OrderMethod:  orderMethod,
 	}
 
+	if savedName != "" {
+		search, err := findSavedSearch(cmd.Context(), deps, savedName)
+		if err != nil {
+			cError.Printf("Failed to get saved search: %v\n", err)
+			return
+		}
+
+		listOptions.AccountID = search.AccountID
+		search.Filter.ApplyTo(&listOptions, time.Now())
+	}
+
+	searchOptions := listOptions.ToDBGetBookmarksOptions()
+	searchOptions.IDs = ids
+
 	bookmarks, err := deps.Database().GetBookmarks(cmd.Context(), searchOptions)
 	if err != nil {
 		cError.Printf("Failed to get bookmarks: %v\n", err)
@@ -73,7 +96,7 @@ func printHandler(cmd *cobra.Command, args []string) {
 		switch {
 		case len(ids) > 0:
 			cError.Println("No matching index found")
-		case keyword != "", len(tags) > 0:
+		case keyword != "", len(tags) > 0, savedName != "":
 			cError.Println("No matching bookmarks found")
 		default:
 			cError.Println("No bookmarks saved yet")
@@ -104,3 +127,29 @@ func printHandler(cmd *cobra.Command, args []string) {
 
 	printBookmarks(bookmarks...)
 }
+
+// findSavedSearch returns the saved search with this name. The name is looked up in every
+// account, it must only be used by one of them.
+func findSavedSearch(ctx context.Context, deps model.Dependencies, name string) (*model.SavedSearch, error) {
+	searches, err := deps.Database().ListSavedSearches(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var found *model.SavedSearch
+	for i, search := range searches {
+		if !strings.EqualFold(search.Name, name) {
+			continue
+		}
+		if found != nil && found.AccountID != search.AccountID {
+			return nil, fmt.Errorf("several accounts have a saved search named %q", name)
+		}
+		found = &searches[i]
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("no saved search named %q", name)
+	}
+
+	return found, nil
+}
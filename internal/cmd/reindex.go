@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/spf13/cobra"
+)
+
+func reindexCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reindex [indices]",
+		Short: "Index the text of the offline archives for search",
+		Long: "Extract the text of the offline archives of bookmarks, from their HTML pages and PDFs, " +
+			"and index it so it can be searched with in:archive. The saved archives are read, " +
+			"the pages are not downloaded again. " +
+			"Accepted indices are the same as in the print command. " +
+			"If there are no arguments, it will reindex ALL of your bookmarks.",
+		Run: reindexHandler,
+	}
+
+	cmd.Flags().BoolP("yes", "y", false, "Skip confirmation prompt and reindex ALL bookmarks")
+
+	return cmd
+}
+
+func reindexHandler(cmd *cobra.Command, args []string) {
+	_, deps := initShiori(cmd.Context(), cmd)
+
+	// Parse flags
+	skipConfirm, _ := cmd.Flags().GetBool("yes")
+
+	// If no arguments (i.e all bookmarks going to be reindexed), confirm to user
+	if len(args) == 0 && !skipConfirm {
+		confirmReindex := ""
+		fmt.Print("Reindex ALL bookmarks? (y/N): ")
+		fmt.Scanln(&confirmReindex)
+
+		if confirmReindex != "y" {
+			fmt.Println("No bookmarks reindexed")
+			return
+		}
+	}
+
+	// Convert args to ids
+	ids, err := parseStrIndices(args)
+	if err != nil {
+		cError.Printf("Failed to parse args: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Fetch bookmarks from database
+	filterOptions := model.DBGetBookmarksOptions{IDs: ids}
+	bookmarks, err := deps.Database().GetBookmarks(cmd.Context(), filterOptions)
+	if err != nil {
+		cError.Printf("Failed to get bookmarks: %v\n", err)
+		os.Exit(1)
+	}
+
+	nBookmark := len(bookmarks)
+	nFailed := 0
+	for i, book := range bookmarks {
+		// Bookmarks without archive only lose their stale text
+		hasArchive := deps.Domains().Storage().FileExists(model.GetArchivePath(&book))
+
+		if err := deps.Domains().Archiver().IndexArchive(cmd.Context(), &book); err != nil {
+			nFailed++
+			cError.Printf("[%d/%d] Failed to index archive of %s: %v\n", i+1, nBookmark, book.URL, err)
+			continue
+		}
+
+		if hasArchive {
+			cInfo.Printf("[%d/%d] Indexed archive of %s\n", i+1, nBookmark, book.URL)
+		}
+	}
+
+	if nFailed > 0 {
+		cError.Printf("Reindex finished, %d archives failed\n", nFailed)
+		os.Exit(1)
+	}
+
+	cInfo.Println("Reindex finished")
+}
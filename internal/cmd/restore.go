@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/go-shiori/shiori/internal/core"
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/spf13/cobra"
+)
+
+func restoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore source-file",
+		Short: "Restore a backup made with the backup command",
+		Long: "Restore a backup made with the backup command into the configured database, " +
+			"which can be a different one from where the backup was made. " +
+			"The checksums of the backup are verified before anything is restored, " +
+			"and the instance must not have bookmarks yet. A failed restore is undone. " +
+			"The passphrase of encrypted backups is read from " + backupPassphraseEnv + " or asked in the terminal.",
+		Args: cobra.ExactArgs(1),
+		Run:  restoreHandler,
+	}
+
+	cmd.Flags().Bool("verify", false, "Only verify the backup, without restoring it")
+
+	return cmd
+}
+
+func restoreHandler(cmd *cobra.Command, args []string) {
+	_, deps := initShiori(cmd.Context(), cmd)
+
+	verifyOnly, _ := cmd.Flags().GetBool("verify")
+	passphrase := os.Getenv(backupPassphraseEnv)
+
+	srcFile, err := os.Open(args[0])
+	if err != nil {
+		cError.Printf("Failed to open %s: %v\n", args[0], err)
+		os.Exit(1)
+	}
+	defer srcFile.Close()
+
+	run := func() (*model.BackupManifest, error) {
+		if verifyOnly {
+			return deps.Domains().Backup().Verify(cmd.Context(), srcFile, passphrase)
+		}
+		return deps.Domains().Backup().Restore(cmd.Context(), srcFile, passphrase)
+	}
+
+	manifest, err := run()
+	if errors.Is(err, core.ErrEncrypted) {
+		passphrase, err = readPassphrase("Passphrase: ")
+		if err == nil {
+			_, err = srcFile.Seek(0, io.SeekStart)
+		}
+		if err == nil {
+			manifest, err = run()
+		}
+	}
+
+	if err != nil {
+		cError.Printf("Failed to restore backup: %v\n", err)
+		os.Exit(1)
+	}
+
+	if verifyOnly {
+		fmt.Printf("Backup is valid: %d accounts, %d tags, %d collections, %d bookmarks and %d files, made on %s\n",
+			manifest.Accounts, manifest.Tags, manifest.Collections, manifest.Bookmarks, manifest.StorageFiles, manifest.CreatedAt)
+		return
+	}
+
+	fmt.Printf("Restore finished: %d accounts, %d tags, %d collections, %d bookmarks and %d files\n",
+		manifest.Accounts, manifest.Tags, manifest.Collections, manifest.Bookmarks, manifest.StorageFiles)
+}
@@ -40,8 +40,13 @@ func ShioriCmd() *cobra.Command {
 		importCmd(),
 		exportCmd(),
 		pocketCmd(),
+		backupCmd(),
+		restoreCmd(),
+		dbCmd(),
 		serveCmd(),
 		checkCmd(),
+		reindexCmd(),
+		feedCmd(),
 		newVersionCommand(),
 		newServerCommand(),
 	)
@@ -105,11 +110,22 @@ func initShiori(ctx context.Context, cmd *cobra.Command) (*config.Config, *depen
 
 	dependencies := dependencies.NewDependencies(logger, db, cfg)
 	dependencies.Domains().SetAuth(domains.NewAuthDomain(dependencies))
+	dependencies.Domains().SetOIDC(domains.NewOIDCDomain(dependencies))
+	dependencies.Domains().SetTOTP(domains.NewTOTPDomain(dependencies))
 	dependencies.Domains().SetAccounts(domains.NewAccountsDomain(dependencies))
 	dependencies.Domains().SetArchiver(domains.NewArchiverDomain(dependencies))
 	dependencies.Domains().SetBookmarks(domains.NewBookmarksDomain(dependencies))
 	dependencies.Domains().SetStorage(domains.NewStorageDomain(dependencies, afero.NewBasePathFs(afero.NewOsFs(), cfg.Storage.DataDir)))
 	dependencies.Domains().SetTags(domains.NewTagsDomain(dependencies))
+	dependencies.Domains().SetJobs(domains.NewJobsDomain(dependencies))
+	dependencies.Domains().SetLinkChecker(domains.NewLinkCheckerDomain(dependencies))
+	dependencies.Domains().SetSubscriptions(domains.NewSubscriptionsDomain(dependencies))
+	dependencies.Domains().SetWebhooks(domains.NewWebhooksDomain(dependencies))
+	dependencies.Domains().SetCollections(domains.NewCollectionsDomain(dependencies))
+	dependencies.Domains().SetRules(domains.NewRulesDomain(dependencies))
+	dependencies.Domains().SetSavedSearches(domains.NewSavedSearchesDomain(dependencies))
+	dependencies.Domains().SetHighlights(domains.NewHighlightsDomain(dependencies))
+	dependencies.Domains().SetBackup(domains.NewBackupDomain(dependencies))
 
 	// Workaround: Get accounts to make sure at least one is present in the database.
 	// If there's no accounts in the database, create the shiori/gopher account the legacy api
@@ -127,9 +143,15 @@ func initShiori(ctx context.Context, cmd *cobra.Command) (*config.Config, *depen
 			Owner:    model.Ptr(true),
 		}
 
-		if _, err := dependencies.Domains().Accounts().CreateAccount(cmd.Context(), account); err != nil {
+		created, err := dependencies.Domains().Accounts().CreateAccount(cmd.Context(), account)
+		if err != nil {
 			logger.WithError(err).Fatal("error ensuring owner account")
 		}
+
+		// Bookmarks saved before any account existed belong to nobody, hand them to the new owner
+		if err := db.TransferBookmarks(cmd.Context(), 0, created.ID); err != nil {
+			logger.WithError(err).Fatal("error assigning bookmarks to owner account")
+		}
 	}
 
 	cfg.DebugConfiguration(logger)
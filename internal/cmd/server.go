@@ -26,6 +26,7 @@ func newServerCommand() *cobra.Command {
 	cmd.Flags().Bool("serve-web-ui", true, "Serve static files from the webroot path")
 	cmd.Flags().Bool("experimental-serve-web-ui-v2", false, "Serve static files from the webapp path")
 	cmd.Flags().String("secret-key", "", "Secret key used for encrypting session data")
+	cmd.Flags().Int("jobs-concurrency", 2, "Number of background jobs processed at the same time")
 
 	return cmd
 }
@@ -48,6 +49,7 @@ func newServerCommandHandler() func(cmd *cobra.Command, args []string) {
 		serveWebUI, _ := cmd.Flags().GetBool("serve-web-ui")
 		serveWebUIV2, _ := cmd.Flags().GetBool("experimental-serve-web-ui-v2")
 		secretKey, _ := cmd.Flags().GetBytesHex("secret-key")
+		jobsConcurrency, _ := cmd.Flags().GetInt("jobs-concurrency")
 
 		cfg, dependencies := initShiori(ctx, cmd)
 
@@ -82,10 +84,14 @@ func newServerCommandHandler() func(cmd *cobra.Command, args []string) {
 		})
 		setIfFlagChanged("secret-key", cmd.Flags(), cfg, func(cfg *config.Config) {
 			cfg.Http.SecretKey = secretKey
+			cfg.Http.SecretKeyRandom = false
 		})
 		setIfFlagChanged("experimental-serve-web-ui-v2", cmd.Flags(), cfg, func(cfg *config.Config) {
 			cfg.Http.ServeWebUIV2 = serveWebUIV2
 		})
+		setIfFlagChanged("jobs-concurrency", cmd.Flags(), cfg, func(cfg *config.Config) {
+			cfg.Jobs.Concurrency = jobsConcurrency
+		})
 
 		dependencies.Logger().Infof("Starting Shiori v%s", model.BuildVersion)
 
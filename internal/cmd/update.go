@@ -229,8 +229,18 @@ func updateHandler(cmd *cobra.Command, args []string) {
 		tagName := strings.ToLower(tag)
 		tagName = strings.TrimSpace(tagName)
 
-		if strings.HasPrefix(tagName, "-") {
-			tagName = strings.TrimPrefix(tagName, "-")
+		isDeleted := strings.HasPrefix(tagName, "-")
+		tagName = strings.TrimPrefix(tagName, "-")
+
+		// Aliases stand for the tag they belong to
+		if existing, exists, err := deps.Database().GetTagByName(cmd.Context(), model.NormalizeTagName(tagName)); err != nil {
+			cError.Printf("Failed to get tag %s: %v\n", tagName, err)
+			os.Exit(1)
+		} else if exists {
+			tagName = existing.Name
+		}
+
+		if isDeleted {
 			deletedTags[tagName] = struct{}{}
 		} else {
 			addedTags[tagName] = struct{}{}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	nurl "net/url"
@@ -31,6 +32,25 @@ var (
 	errInvalidIndex = errors.New("index is not valid")
 )
 
+// bookmarkOwnerID returns the account new bookmarks created from the CLI are assigned to,
+// which is the first owner account. Zero is returned if there are no owners.
+func bookmarkOwnerID(ctx context.Context, deps model.Dependencies) model.DBID {
+	owners, err := deps.Database().ListAccounts(ctx, model.DBListAccountsOptions{Owner: true})
+	if err != nil {
+		cError.Printf("Failed to get owner account: %v\n", err)
+		os.Exit(1)
+	}
+
+	var ownerID model.DBID
+	for _, owner := range owners {
+		if ownerID == 0 || owner.ID < ownerID {
+			ownerID = owner.ID
+		}
+	}
+
+	return ownerID
+}
+
 func normalizeSpace(str string) string {
 	str = strings.TrimSpace(str)
 	return strings.Join(strings.Fields(str), " ")
@@ -133,6 +153,14 @@ func getTerminalWidth() int {
 	return width
 }
 
+// readPassphrase asks for a passphrase in the terminal without echoing it.
+func readPassphrase(prompt string) (string, error) {
+	fmt.Print(prompt)
+	passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Println()
+	return string(passphrase), err
+}
+
 func validateTitle(title, fallback string) string {
 	// Normalize spaces before we begin
 	title = normalizeSpace(title)
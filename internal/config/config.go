@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -49,15 +50,20 @@ func readDotEnv(logger *logrus.Logger) map[string]string {
 }
 
 type HttpConfig struct {
-	Enabled      bool   `env:"HTTP_ENABLED,default=True"`
-	Port         int    `env:"HTTP_PORT,default=8080"`
-	Address      string `env:"HTTP_ADDRESS,default=:"`
-	RootPath     string `env:"HTTP_ROOT_PATH,default=/"`
-	AccessLog    bool   `env:"HTTP_ACCESS_LOG,default=True"`
-	ServeWebUI   bool   `env:"HTTP_SERVE_WEB_UI,default=True"`
-	ServeWebUIV2 bool   `env:"HTTP_SERVE_WEB_UI_V2,default=False"`
-	ServeSwagger bool   `env:"HTTP_SERVE_SWAGGER,default=False"`
-	SecretKey    []byte `env:"HTTP_SECRET_KEY"`
+	Enabled           bool   `env:"HTTP_ENABLED,default=True"`
+	Port              int    `env:"HTTP_PORT,default=8080"`
+	Address           string `env:"HTTP_ADDRESS,default=:"`
+	RootPath          string `env:"HTTP_ROOT_PATH,default=/"`
+	AccessLog         bool   `env:"HTTP_ACCESS_LOG,default=True"`
+	ServeWebUI        bool   `env:"HTTP_SERVE_WEB_UI,default=True"`
+	ServeWebUIV2      bool   `env:"HTTP_SERVE_WEB_UI_V2,default=False"`
+	ServeSwagger      bool   `env:"HTTP_SERVE_SWAGGER,default=False"`
+	SecretKey         []byte `env:"HTTP_SECRET_KEY"`
+	BookmarksPageSize int    `env:"HTTP_BOOKMARKS_PAGE_SIZE,default=30"`
+	// SecretKeyRandom is set when no secret key is configured, a random one is then used
+	// until the server stops
+	SecretKeyRandom bool
+
 	// Fiber Specific
 	BodyLimit                    int           `env:"HTTP_BODY_LIMIT,default=1024"`
 	ReadTimeout                  time.Duration `env:"HTTP_READ_TIMEOUT,default=10s"`
@@ -69,6 +75,30 @@ type HttpConfig struct {
 	SSOProxyAuth           bool     `env:"SSO_PROXY_AUTH_ENABLED,default=false"`
 	SSOProxyAuthHeaderName string   `env:"SSO_PROXY_AUTH_HEADER_NAME,default=Remote-User"`
 	SSOProxyAuthTrusted    []string `env:"SSO_PROXY_AUTH_TRUSTED,default=10.0.0.0/8, 172.16.0.0/12, 192.168.0.0/16, fc00::/7"`
+
+	// OpenID Connect login, accounts are created on their first login
+	OIDCEnabled      bool     `env:"OIDC_ENABLED,default=false"`
+	OIDCIssuer       string   `env:"OIDC_ISSUER"`
+	OIDCClientID     string   `env:"OIDC_CLIENT_ID"`
+	OIDCClientSecret string   `env:"OIDC_CLIENT_SECRET"`
+	OIDCRedirectURL  string   `env:"OIDC_REDIRECT_URL"`
+	OIDCScopes       []string `env:"OIDC_SCOPES,default=openid,profile,email"`
+	// Claim holding the username of the account
+	OIDCUsernameClaim string `env:"OIDC_USERNAME_CLAIM,default=preferred_username"`
+	// Accounts are owners when OIDCOwnerClaim is OIDCOwnerValue or a list containing it,
+	// the owner flag isn't changed by OIDC logins when OIDCOwnerValue is empty
+	OIDCOwnerClaim string `env:"OIDC_OWNER_CLAIM,default=groups"`
+	OIDCOwnerValue string `env:"OIDC_OWNER_VALUE"`
+
+	// Requests allowed by client in each rate limit window, zero disables the limit. Login
+	// attempts are limited by client IP, other API calls by account or client IP.
+	RateLimitEnabled bool          `env:"HTTP_RATE_LIMIT_ENABLED,default=true"`
+	RateLimitWindow  time.Duration `env:"HTTP_RATE_LIMIT_WINDOW,default=1m"`
+	RateLimitLogin   int           `env:"HTTP_RATE_LIMIT_LOGIN,default=10"`
+	RateLimitAPI     int           `env:"HTTP_RATE_LIMIT_API,default=600"`
+	// Clients are locked out of logins, or of an account, after this many failed attempts in a window
+	RateLimitLockoutFailures int           `env:"HTTP_RATE_LIMIT_LOCKOUT_FAILURES,default=5"`
+	RateLimitLockoutDuration time.Duration `env:"HTTP_RATE_LIMIT_LOCKOUT_DURATION,default=15m"`
 }
 
 // SetDefaults sets the default values for the configuration
@@ -81,6 +111,7 @@ func (c *HttpConfig) SetDefaults(logger *logrus.Logger) {
 			logger.WithError(err).Fatal("couldn't generate a random UUID")
 		}
 		c.SecretKey = []byte(randomUUID.String())
+		c.SecretKeyRandom = true
 	}
 }
 
@@ -93,6 +124,30 @@ func (c *HttpConfig) IsValid() error {
 		return fmt.Errorf("you need to enable serving the Web UI to use the experimental Web UI v2")
 	}
 
+	if c.BookmarksPageSize <= 0 {
+		return fmt.Errorf("bookmarks page size should be greater than zero")
+	}
+
+	if c.RateLimitEnabled {
+		if c.RateLimitWindow <= 0 {
+			return fmt.Errorf("rate limit window should be greater than zero")
+		}
+
+		if c.RateLimitLogin < 0 || c.RateLimitAPI < 0 || c.RateLimitLockoutFailures < 0 {
+			return fmt.Errorf("rate limits can't be negative")
+		}
+	}
+
+	if c.OIDCEnabled {
+		if c.OIDCIssuer == "" || c.OIDCClientID == "" || c.OIDCRedirectURL == "" {
+			return fmt.Errorf("OIDC login needs an issuer, a client ID and a redirect URL")
+		}
+
+		if !slices.Contains(c.OIDCScopes, "openid") {
+			return fmt.Errorf("OIDC scopes should include openid")
+		}
+	}
+
 	return nil
 }
 
@@ -104,15 +159,117 @@ type DatabaseConfig struct {
 
 type StorageConfig struct {
 	DataDir string `env:"DIR"` // Using DIR to be backwards compatible with the old config
+	// Number of archive snapshots kept for each bookmark, zero keeps all of them
+	ArchiveSnapshotsKeep int `env:"ARCHIVE_SNAPSHOTS_KEEP,default=5"`
+}
+
+func (c *StorageConfig) IsValid() error {
+	if c.ArchiveSnapshotsKeep < 0 {
+		return fmt.Errorf("archive snapshots to keep should not be negative")
+	}
+
+	return nil
+}
+
+type JobsConfig struct {
+	Concurrency  int           `env:"JOBS_CONCURRENCY,default=2"`
+	MaxAttempts  int           `env:"JOBS_MAX_ATTEMPTS,default=3"`
+	RetryBackoff time.Duration `env:"JOBS_RETRY_BACKOFF,default=1m"`
+	PollInterval time.Duration `env:"JOBS_POLL_INTERVAL,default=5s"`
+}
+
+func (c *JobsConfig) IsValid() error {
+	if c.Concurrency <= 0 {
+		return fmt.Errorf("jobs concurrency should be greater than zero")
+	}
+
+	if c.MaxAttempts <= 0 {
+		return fmt.Errorf("jobs max attempts should be greater than zero")
+	}
+
+	if c.PollInterval <= 0 {
+		return fmt.Errorf("jobs poll interval should be greater than zero")
+	}
+
+	return nil
+}
+
+type LinkCheckConfig struct {
+	// How often every bookmark is checked, zero disables the periodic checks
+	Interval    time.Duration `env:"LINK_CHECK_INTERVAL,default=0"`
+	Timeout     time.Duration `env:"LINK_CHECK_TIMEOUT,default=30s"`
+	Concurrency int           `env:"LINK_CHECK_CONCURRENCY,default=5"`
+	// Number of checks kept for each bookmark
+	HistoryKeep int `env:"LINK_CHECK_HISTORY_KEEP,default=10"`
+}
+
+func (c *LinkCheckConfig) IsValid() error {
+	if c.Interval < 0 {
+		return fmt.Errorf("link check interval should not be negative")
+	}
+
+	if c.Timeout <= 0 {
+		return fmt.Errorf("link check timeout should be greater than zero")
+	}
+
+	if c.Concurrency <= 0 {
+		return fmt.Errorf("link check concurrency should be greater than zero")
+	}
+
+	if c.HistoryKeep <= 0 {
+		return fmt.Errorf("link check history to keep should be greater than zero")
+	}
+
+	return nil
+}
+
+type SubscriptionsConfig struct {
+	// How often the feed subscriptions are polled, zero disables the periodic polls
+	PollInterval time.Duration `env:"SUBSCRIPTIONS_POLL_INTERVAL,default=1h"`
+	Timeout      time.Duration `env:"SUBSCRIPTIONS_TIMEOUT,default=30s"`
+}
+
+func (c *SubscriptionsConfig) IsValid() error {
+	if c.PollInterval < 0 {
+		return fmt.Errorf("subscriptions poll interval should not be negative")
+	}
+
+	if c.Timeout <= 0 {
+		return fmt.Errorf("subscriptions timeout should be greater than zero")
+	}
+
+	return nil
+}
+
+type WebhooksConfig struct {
+	Timeout time.Duration `env:"WEBHOOKS_TIMEOUT,default=10s"`
+	// Number of deliveries kept for each webhook, older ones are removed
+	DeliveriesKeep int `env:"WEBHOOKS_DELIVERIES_KEEP,default=100"`
+}
+
+func (c *WebhooksConfig) IsValid() error {
+	if c.Timeout <= 0 {
+		return fmt.Errorf("webhooks timeout should be greater than zero")
+	}
+
+	if c.DeliveriesKeep < 1 {
+		return fmt.Errorf("webhooks deliveries keep should be at least 1")
+	}
+
+	return nil
 }
 
 type Config struct {
-	Hostname    string `env:"HOSTNAME,required"`
-	Development bool   `env:"DEVELOPMENT,default=False"`
-	LogLevel    string // Set only from the CLI flag
-	Database    *DatabaseConfig
-	Storage     *StorageConfig
-	Http        *HttpConfig
+	Hostname      string `env:"HOSTNAME,required"`
+	Development   bool   `env:"DEVELOPMENT,default=False"`
+	LogLevel      string // Set only from the CLI flag
+	Database      *DatabaseConfig
+	Storage       *StorageConfig
+	Http          *HttpConfig
+	Jobs          *JobsConfig
+	LinkCheck     *LinkCheckConfig
+	Subscriptions *SubscriptionsConfig
+	Webhooks      *WebhooksConfig
 }
 
 // SetDefaults sets the default values for the configuration
@@ -142,6 +299,7 @@ func (c *Config) DebugConfiguration(logger *logrus.Logger) {
 	logger.Debugf(" SHIORI_DATABASE_URL: %s", c.Database.URL)
 	logger.Debugf(" SHIORI_DBMS: %s", c.Database.DBMS)
 	logger.Debugf(" SHIORI_DIR: %s", c.Storage.DataDir)
+	logger.Debugf(" SHIORI_ARCHIVE_SNAPSHOTS_KEEP: %d", c.Storage.ArchiveSnapshotsKeep)
 	logger.Debugf(" SHIORI_HTTP_ENABLED: %t", c.Http.Enabled)
 	logger.Debugf(" SHIORI_HTTP_PORT: %d", c.Http.Port)
 	logger.Debugf(" SHIORI_HTTP_ADDRESS: %s", c.Http.Address)
@@ -150,6 +308,7 @@ func (c *Config) DebugConfiguration(logger *logrus.Logger) {
 	logger.Debugf(" SHIORI_HTTP_SERVE_WEB_UI: %t", c.Http.ServeWebUI)
 	logger.Debugf(" SHIORI_HTTP_SERVE_WEB_UI_V2: %t", c.Http.ServeWebUIV2)
 	logger.Debugf(" SHIORI_HTTP_SECRET_KEY: %d characters", len(c.Http.SecretKey))
+	logger.Debugf(" SHIORI_HTTP_BOOKMARKS_PAGE_SIZE: %d", c.Http.BookmarksPageSize)
 	logger.Debugf(" SHIORI_HTTP_BODY_LIMIT: %d", c.Http.BodyLimit)
 	logger.Debugf(" SHIORI_HTTP_READ_TIMEOUT: %s", c.Http.ReadTimeout)
 	logger.Debugf(" SHIORI_HTTP_WRITE_TIMEOUT: %s", c.Http.WriteTimeout)
@@ -159,6 +318,24 @@ func (c *Config) DebugConfiguration(logger *logrus.Logger) {
 	logger.Debugf(" SHIORI_SSO_PROXY_AUTH_ENABLED: %t", c.Http.SSOProxyAuth)
 	logger.Debugf(" SHIORI_SSO_PROXY_AUTH_HEADER_NAME: %s", c.Http.SSOProxyAuthHeaderName)
 	logger.Debugf(" SHIORI_SSO_PROXY_AUTH_TRUSTED: %v", c.Http.SSOProxyAuthTrusted)
+	logger.Debugf(" SHIORI_HTTP_RATE_LIMIT_ENABLED: %t", c.Http.RateLimitEnabled)
+	logger.Debugf(" SHIORI_HTTP_RATE_LIMIT_WINDOW: %s", c.Http.RateLimitWindow)
+	logger.Debugf(" SHIORI_HTTP_RATE_LIMIT_LOGIN: %d", c.Http.RateLimitLogin)
+	logger.Debugf(" SHIORI_HTTP_RATE_LIMIT_API: %d", c.Http.RateLimitAPI)
+	logger.Debugf(" SHIORI_HTTP_RATE_LIMIT_LOCKOUT_FAILURES: %d", c.Http.RateLimitLockoutFailures)
+	logger.Debugf(" SHIORI_HTTP_RATE_LIMIT_LOCKOUT_DURATION: %s", c.Http.RateLimitLockoutDuration)
+	logger.Debugf(" SHIORI_JOBS_CONCURRENCY: %d", c.Jobs.Concurrency)
+	logger.Debugf(" SHIORI_JOBS_MAX_ATTEMPTS: %d", c.Jobs.MaxAttempts)
+	logger.Debugf(" SHIORI_JOBS_RETRY_BACKOFF: %s", c.Jobs.RetryBackoff)
+	logger.Debugf(" SHIORI_JOBS_POLL_INTERVAL: %s", c.Jobs.PollInterval)
+	logger.Debugf(" SHIORI_LINK_CHECK_INTERVAL: %s", c.LinkCheck.Interval)
+	logger.Debugf(" SHIORI_LINK_CHECK_TIMEOUT: %s", c.LinkCheck.Timeout)
+	logger.Debugf(" SHIORI_LINK_CHECK_CONCURRENCY: %d", c.LinkCheck.Concurrency)
+	logger.Debugf(" SHIORI_LINK_CHECK_HISTORY_KEEP: %d", c.LinkCheck.HistoryKeep)
+	logger.Debugf(" SHIORI_SUBSCRIPTIONS_POLL_INTERVAL: %s", c.Subscriptions.PollInterval)
+	logger.Debugf(" SHIORI_SUBSCRIPTIONS_TIMEOUT: %s", c.Subscriptions.Timeout)
+	logger.Debugf(" SHIORI_WEBHOOKS_TIMEOUT: %s", c.Webhooks.Timeout)
+	logger.Debugf(" SHIORI_WEBHOOKS_DELIVERIES_KEEP: %d", c.Webhooks.DeliveriesKeep)
 }
 
 func (c *Config) IsValid() error {
@@ -166,6 +343,26 @@ func (c *Config) IsValid() error {
 		return fmt.Errorf("http configuration is invalid: %w", err)
 	}
 
+	if err := c.Storage.IsValid(); err != nil {
+		return fmt.Errorf("storage configuration is invalid: %w", err)
+	}
+
+	if err := c.Jobs.IsValid(); err != nil {
+		return fmt.Errorf("jobs configuration is invalid: %w", err)
+	}
+
+	if err := c.LinkCheck.IsValid(); err != nil {
+		return fmt.Errorf("link check configuration is invalid: %w", err)
+	}
+
+	if err := c.Subscriptions.IsValid(); err != nil {
+		return fmt.Errorf("subscriptions configuration is invalid: %w", err)
+	}
+
+	if err := c.Webhooks.IsValid(); err != nil {
+		return fmt.Errorf("webhooks configuration is invalid: %w", err)
+	}
+
 	return nil
 }
 
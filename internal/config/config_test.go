@@ -98,6 +98,7 @@ func TestConfigSetDefaults(t *testing.T) {
 	cfg.SetDefaults(log, false)
 
 	require.NotEmpty(t, cfg.Http.SecretKey)
+	require.True(t, cfg.Http.SecretKeyRandom)
 	require.NotEmpty(t, cfg.Storage.DataDir)
 	require.NotEmpty(t, cfg.Database.URL)
 }
@@ -116,4 +117,39 @@ func TestConfigIsValid(t *testing.T) {
 		cfg.Http.RootPath = "/invalid"
 		require.Error(t, cfg.IsValid())
 	})
+
+	t.Run("invalid bookmarks page size", func(t *testing.T) {
+		cfg := ParseServerConfiguration(context.TODO(), log)
+		cfg.Http.BookmarksPageSize = 0
+		require.Error(t, cfg.IsValid())
+	})
+
+	t.Run("invalid link check timeout", func(t *testing.T) {
+		cfg := ParseServerConfiguration(context.TODO(), log)
+		cfg.LinkCheck.Timeout = 0
+		require.Error(t, cfg.IsValid())
+	})
+
+	t.Run("incomplete oidc configuration", func(t *testing.T) {
+		cfg := ParseServerConfiguration(context.TODO(), log)
+		cfg.Http.OIDCEnabled = true
+		cfg.Http.OIDCIssuer = "https://id.example.com"
+		require.Error(t, cfg.IsValid())
+
+		cfg.Http.OIDCClientID = "shiori"
+		cfg.Http.OIDCRedirectURL = "https://shiori.example.com/api/v1/auth/oidc/callback"
+		require.NoError(t, cfg.IsValid())
+
+		cfg.Http.OIDCScopes = []string{"profile"}
+		require.Error(t, cfg.IsValid())
+	})
+
+	t.Run("invalid rate limit window", func(t *testing.T) {
+		cfg := ParseServerConfiguration(context.TODO(), log)
+		cfg.Http.RateLimitWindow = 0
+		require.Error(t, cfg.IsValid())
+
+		cfg.Http.RateLimitEnabled = false
+		require.NoError(t, cfg.IsValid())
+	})
 }
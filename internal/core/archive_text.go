@@ -0,0 +1,129 @@
+package core
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/warc"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// archiveTextMaxFrames limits the frames read from an archive, pages full of embeds aren't
+// worth opening them all
+const archiveTextMaxFrames = 20
+
+// archiveResourceMaxSize limits the bytes read from a resource of an archive once
+// uncompressed, markup and PDF structure make them larger than the text they hold
+const archiveResourceMaxSize = 20 * model.ArchiveTextMaxLength
+
+// ExtractArchiveText returns the text of the page saved in an archive: the text of its HTML
+// and of the frames it embeds, or the text of the PDF. It is cut to model.ArchiveTextMaxLength.
+func ExtractArchiveText(archive *warc.Archive) (string, error) {
+	content, contentType, err := readArchiveResource(archive, "")
+	if err != nil {
+		return "", err
+	}
+
+	text := strings.Builder{}
+	switch {
+	case strings.Contains(contentType, "text/html"):
+		frames := archiveHTMLText(&text, content)
+		for i, frame := range frames {
+			if i == archiveTextMaxFrames {
+				break
+			}
+
+			// Frames missing from the archive were left out when it was saved
+			content, contentType, err := readArchiveResource(archive, frame)
+			if err != nil || !strings.Contains(contentType, "text/html") {
+				continue
+			}
+			archiveHTMLText(&text, content)
+		}
+	case strings.Contains(contentType, "application/pdf"):
+		text.WriteString(ExtractPDFText(content))
+	case strings.Contains(contentType, "text/plain"):
+		text.Write(content)
+	}
+
+	return truncateArchiveText(strings.Join(strings.Fields(text.String()), " ")), nil
+}
+
+// readArchiveResource reads a resource of the archive, which stores them compressed. Only the
+// first archiveResourceMaxSize bytes of the resource are read.
+func readArchiveResource(archive *warc.Archive, name string) ([]byte, string, error) {
+	content, contentType, err := archive.Read(name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read archive resource %s: %w", name, err)
+	}
+	defer gz.Close()
+
+	content, err = io.ReadAll(io.LimitReader(gz, archiveResourceMaxSize))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read archive resource %s: %w", name, err)
+	}
+
+	return content, contentType, nil
+}
+
+// archiveHTMLText writes the visible text of the HTML document, and returns the archive names
+// of its frames.
+func archiveHTMLText(text *strings.Builder, content []byte) []string {
+	doc, err := html.Parse(bytes.NewReader(content))
+	if err != nil {
+		return nil
+	}
+
+	frames := []string{}
+	var walk func(node *html.Node)
+	walk = func(node *html.Node) {
+		switch node.Type {
+		case html.TextNode:
+			text.WriteString(node.Data)
+			text.WriteByte(' ')
+			return
+		case html.ElementNode:
+			switch node.DataAtom {
+			case atom.Script, atom.Style, atom.Noscript, atom.Template, atom.Svg:
+				return
+			case atom.Iframe, atom.Frame:
+				for _, attr := range node.Attr {
+					if attr.Key == "src" && attr.Val != "" {
+						frames = append(frames, attr.Val)
+					}
+				}
+			}
+		}
+
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+
+	return frames
+}
+
+// truncateArchiveText cuts the text to model.ArchiveTextMaxLength at a rune boundary.
+func truncateArchiveText(text string) string {
+	if len(text) <= model.ArchiveTextMaxLength {
+		return text
+	}
+
+	end := model.ArchiveTextMaxLength
+	for end > 0 && !utf8.RuneStart(text[end]) {
+		end--
+	}
+	return text[:end]
+}
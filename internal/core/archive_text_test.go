@@ -0,0 +1,122 @@
+package core_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/go-shiori/shiori/internal/core"
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/warc"
+	"github.com/stretchr/testify/require"
+)
+
+// testPDF returns a PDF of two pages written with the standard fonts, whose text is encoded
+// with WinAnsiEncoding and compressed.
+func testPDF(t *testing.T) []byte {
+	doc := fpdf.New("P", "mm", "A4", "")
+	tr := doc.UnicodeTranslatorFromDescriptor("")
+	doc.SetFont("Helvetica", "", 12)
+	doc.AddPage()
+	doc.Cell(40, 10, tr("Hello (PDF) café"))
+	doc.AddPage()
+	doc.Cell(40, 10, "Second page")
+
+	pdf := bytes.Buffer{}
+	require.NoError(t, doc.Output(&pdf))
+	return pdf.Bytes()
+}
+
+func TestExtractPDFText(t *testing.T) {
+	text := func(data []byte) string {
+		return strings.Join(strings.Fields(core.ExtractPDFText(data)), " ")
+	}
+
+	require.Equal(t, "Hello (PDF) café Second page", text(testPDF(t)))
+	require.Empty(t, text([]byte("not a pdf")))
+
+	t.Run("truncated documents have no text", func(t *testing.T) {
+		pdf := testPDF(t)
+		require.Empty(t, text(pdf[:len(pdf)/2]))
+	})
+
+	t.Run("text is collected up to the archive limit", func(t *testing.T) {
+		doc := fpdf.New("P", "mm", "A4", "")
+		doc.SetFont("Helvetica", "", 1)
+		line := strings.Repeat("x", 1000)
+		for range model.ArchiveTextMaxLength/(10*len(line)) + 10 {
+			doc.AddPage()
+			for range 10 {
+				doc.Cell(0, 1, line)
+				doc.Ln(1)
+			}
+		}
+		doc.AddPage()
+		doc.Cell(40, 10, "last")
+
+		pdf := bytes.Buffer{}
+		require.NoError(t, doc.Output(&pdf))
+
+		text := core.ExtractPDFText(pdf.Bytes())
+		require.GreaterOrEqual(t, len(text), model.ArchiveTextMaxLength)
+		require.NotContains(t, text, "last")
+	})
+}
+
+func TestExtractArchiveText(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`<html><head><title>Page title</title><style>body { color: red; }</style></head>
+			<body><p>Visible <b>text</b></p><script>var hidden = true;</script>
+			<iframe src="/frame"></iframe></body></html>`))
+	})
+	mux.HandleFunc("/frame", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`<html><body><p>Framed text</p></body></html>`))
+	})
+	mux.HandleFunc("/large", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`<html><body><p>Start</p><!--`))
+		w.Write(bytes.Repeat([]byte(" "), 20*model.ArchiveTextMaxLength))
+		w.Write([]byte(`--><p>End</p></body></html>`))
+	})
+	mux.HandleFunc("/document.pdf", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Write(testPDF(t))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	extract := func(t *testing.T, url string) string {
+		archivePath := filepath.Join(t.TempDir(), "archive")
+		require.NoError(t, warc.NewArchive(warc.ArchivalRequest{URL: url}, archivePath))
+
+		archive, err := warc.Open(archivePath)
+		require.NoError(t, err)
+		defer archive.Close()
+
+		text, err := core.ExtractArchiveText(archive)
+		require.NoError(t, err)
+		return text
+	}
+
+	t.Run("html page and its frames", func(t *testing.T) {
+		text := extract(t, server.URL+"/page")
+		require.Equal(t, "Page title Visible text Framed text", text)
+	})
+
+	t.Run("pdf", func(t *testing.T) {
+		text := extract(t, server.URL+"/document.pdf")
+		require.Equal(t, "Hello (PDF) café Second page", text)
+	})
+
+	t.Run("large resources are only read up to a limit", func(t *testing.T) {
+		text := extract(t, server.URL+"/large")
+		require.Equal(t, "Start", text)
+	})
+}
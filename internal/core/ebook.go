@@ -1,6 +1,8 @@
 package core
 
 import (
+	"html"
+	"io"
 	"os"
 	fp "path/filepath"
 	"strconv"
@@ -50,17 +52,61 @@ func GenerateEbook(deps model.Dependencies, req ProcessRequest, dstPath string)
 	}
 	defer os.Remove(tmpFile.Name())
 
+	ebook, err := newEbook(deps, book, book.HTML)
+	if err != nil {
+		return book, err
+	}
+
+	err = ebook.Write(tmpFile.Name())
+	if err != nil {
+		return book, errors.Wrap(err, "can't create ebook file")
+	}
+
+	defer tmpFile.Close()
+
+	// If everything go well we move ebook to dstPath
+	err = deps.Domains().Storage().WriteFile(dstPath, tmpFile)
+	if err != nil {
+		return book, errors.Wrap(err, "failed move ebook to destination")
+	}
+
+	book.HasEbook = true
+	return book, nil
+}
+
+// WriteHighlightsEbook writes the ebook of a bookmark with the highlights and notes of an
+// account. The stored ebook is shared with everyone who can read the bookmark, this copy is
+// made for the account on request and never stored.
+func WriteHighlightsEbook(deps model.Dependencies, book model.BookmarkDTO, highlights []model.Highlight, w io.Writer) error {
+	SortHighlights(highlights)
+	content := RenderHighlights(book.HTML, highlights) + ebookHighlightsHTML(highlights)
+
+	ebook, err := newEbook(deps, book, content)
+	if err != nil {
+		return err
+	}
+
+	if _, err := ebook.WriteTo(w); err != nil {
+		return errors.Wrap(err, "can't write ebook")
+	}
+
+	return nil
+}
+
+// newEbook creates the ebook of a bookmark, with its cover and the content as its only section.
+func newEbook(deps model.Dependencies, book model.BookmarkDTO, content string) (*epub.Epub, error) {
 	// Create last line of ebook
 	lastline := `<hr/><p style="text-align:center">Generated By <a href="https://github.com/go-shiori/shiori">Shiori</a> From <a href="` + book.URL + `">This Page</a></p>`
 
 	// Create ebook
 	ebook, err := epub.NewEpub(book.Title)
 	if err != nil {
-		return book, errors.Wrap(err, "can't create EPUB")
+		return nil, errors.Wrap(err, "can't create EPUB")
 	}
 
 	ebook.SetTitle(book.Title)
 	ebook.SetAuthor(book.Author)
+	bookmarkThumbnailPath := model.GetThumbnailPath(&book)
 	if deps.Domains().Storage().FileExists(bookmarkThumbnailPath) {
 		// TODO: Use `deps.Domains.Storage` to retrieve the file.
 		absoluteCoverPath := fp.Join(deps.Config().Storage.DataDir, bookmarkThumbnailPath)
@@ -68,24 +114,25 @@ func GenerateEbook(deps model.Dependencies, req ProcessRequest, dstPath string)
 		ebook.SetCover(coverPath, "")
 	}
 	ebook.SetDescription(book.Excerpt)
-	_, err = ebook.AddSection(`<h1 style="text-align:center"> `+book.Title+` </h1>`+book.HTML+lastline, book.Title, "", "")
+	_, err = ebook.AddSection(`<h1 style="text-align:center"> `+book.Title+` </h1>`+content+lastline, book.Title, "", "")
 	if err != nil {
-		return book, errors.Wrap(err, "can't add ebook Section")
+		return nil, errors.Wrap(err, "can't add ebook Section")
 	}
 	ebook.EmbedImages()
-	err = ebook.Write(tmpFile.Name())
-	if err != nil {
-		return book, errors.Wrap(err, "can't create ebook file")
-	}
 
-	defer tmpFile.Close()
+	return ebook, nil
+}
 
-	// If everything go well we move ebook to dstPath
-	err = deps.Domains().Storage().WriteFile(dstPath, tmpFile)
-	if err != nil {
-		return book, errors.Wrap(err, "failed move ebook to destination")
+// ebookHighlightsHTML lists the highlighted passages with their notes, for the end of the
+// ebook.
+func ebookHighlightsHTML(highlights []model.Highlight) string {
+	buf := strings.Builder{}
+	buf.WriteString(`<hr/><h2>Highlights</h2>`)
+	for _, highlight := range highlights {
+		buf.WriteString(`<blockquote>` + html.EscapeString(highlight.Selector.Quote.Exact) + `</blockquote>`)
+		if note := strings.TrimSpace(highlight.Note); note != "" {
+			buf.WriteString(`<p>` + html.EscapeString(note) + `</p>`)
+		}
 	}
-
-	book.HasEbook = true
-	return book, nil
+	return buf.String()
 }
@@ -0,0 +1,212 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Encrypted streams start with this magic followed by the salt of the key. The data is then
+// split into chunks sealed with AES-GCM, each one prefixed by its length. The nonce of a chunk
+// is its position and the last chunk is flagged through the additional data, so reordered or
+// truncated streams are detected.
+var encryptionMagic = []byte("SHIORIENC1")
+
+const (
+	encryptionSaltSize  = 16
+	encryptionChunkSize = 64 * 1024
+)
+
+var (
+	// ErrEncrypted is returned when reading an encrypted stream without a passphrase
+	ErrEncrypted = errors.New("data is encrypted, a passphrase is required")
+	// ErrDecryption is returned when the passphrase is wrong or the data was modified
+	ErrDecryption = errors.New("failed to decrypt data, wrong passphrase or corrupted data")
+)
+
+func encryptionKey(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func encryptionNonce(aead cipher.AEAD, index uint64) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint64(nonce[len(nonce)-8:], index)
+	return nonce
+}
+
+func encryptionAdditionalData(last bool) []byte {
+	if last {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+type encryptWriter struct {
+	w     io.Writer
+	aead  cipher.AEAD
+	buf   []byte
+	index uint64
+}
+
+// NewEncryptWriter returns a writer encrypting the data written to it with a key derived from
+// the passphrase. Close must be called to write the last chunk, it doesn't close w.
+func NewEncryptWriter(w io.Writer, passphrase string) (io.WriteCloser, error) {
+	salt := make([]byte, encryptionSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	aead, err := encryptionKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := w.Write(append(append([]byte{}, encryptionMagic...), salt...)); err != nil {
+		return nil, err
+	}
+
+	return &encryptWriter{w: w, aead: aead, buf: make([]byte, 0, encryptionChunkSize)}, nil
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		if len(e.buf) == encryptionChunkSize {
+			if err := e.flush(false); err != nil {
+				return written, err
+			}
+		}
+
+		n := min(encryptionChunkSize-len(e.buf), len(p))
+		e.buf = append(e.buf, p[:n]...)
+		p = p[n:]
+		written += n
+	}
+
+	return written, nil
+}
+
+func (e *encryptWriter) flush(last bool) error {
+	sealed := e.aead.Seal(nil, encryptionNonce(e.aead, e.index), e.buf, encryptionAdditionalData(last))
+	e.index++
+	e.buf = e.buf[:0]
+
+	header := make([]byte, 4)
+	binary.BigEndian.PutUint32(header, uint32(len(sealed)))
+	if _, err := e.w.Write(header); err != nil {
+		return err
+	}
+
+	_, err := e.w.Write(sealed)
+	return err
+}
+
+func (e *encryptWriter) Close() error {
+	return e.flush(true)
+}
+
+type decryptReader struct {
+	r     io.Reader
+	aead  cipher.AEAD
+	buf   []byte
+	index uint64
+	done  bool
+}
+
+// NewDecryptReader returns a reader for data that may have been encrypted by NewEncryptWriter.
+// Data that isn't encrypted is returned as is, ErrEncrypted is returned for encrypted data
+// when the passphrase is empty.
+func NewDecryptReader(r io.Reader, passphrase string) (io.Reader, error) {
+	br := bufio.NewReader(r)
+
+	magic, err := br.Peek(len(encryptionMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	if !bytes.Equal(magic, encryptionMagic) {
+		return br, nil
+	}
+
+	if passphrase == "" {
+		return nil, ErrEncrypted
+	}
+
+	header := make([]byte, len(encryptionMagic)+encryptionSaltSize)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, ErrDecryption
+	}
+
+	aead, err := encryptionKey(passphrase, header[len(encryptionMagic):])
+	if err != nil {
+		return nil, err
+	}
+
+	return &decryptReader{r: br, aead: aead}, nil
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.buf) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+
+		if err := d.next(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, d.buf)
+	d.buf = d.buf[n:]
+	return n, nil
+}
+
+// next reads and opens the following chunk.
+func (d *decryptReader) next() error {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(d.r, header); err != nil {
+		// The stream ended before its last chunk
+		return ErrDecryption
+	}
+
+	size := binary.BigEndian.Uint32(header)
+	if size > encryptionChunkSize+uint32(d.aead.Overhead()) {
+		return ErrDecryption
+	}
+
+	sealed := make([]byte, size)
+	if _, err := io.ReadFull(d.r, sealed); err != nil {
+		return ErrDecryption
+	}
+
+	nonce := encryptionNonce(d.aead, d.index)
+	d.index++
+
+	for _, last := range []bool{false, true} {
+		if plain, err := d.aead.Open(nil, nonce, sealed, encryptionAdditionalData(last)); err == nil {
+			d.buf = plain
+			d.done = last
+			return nil
+		}
+	}
+
+	return ErrDecryption
+}
@@ -0,0 +1,85 @@
+package core_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/core"
+	"github.com/stretchr/testify/require"
+)
+
+func encrypt(t *testing.T, data []byte, passphrase string) []byte {
+	buf := bytes.Buffer{}
+	w, err := core.NewEncryptWriter(&buf, passphrase)
+	require.NoError(t, err)
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestEncryption(t *testing.T) {
+	// Spans several chunks
+	data := make([]byte, 200*1024)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+
+	encrypted := encrypt(t, data, "secret")
+
+	t.Run("round trip", func(t *testing.T) {
+		require.NotContains(t, string(encrypted), string(data[:64]))
+
+		r, err := core.NewDecryptReader(bytes.NewReader(encrypted), "secret")
+		require.NoError(t, err)
+		decrypted, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, data, decrypted)
+	})
+
+	t.Run("empty data", func(t *testing.T) {
+		r, err := core.NewDecryptReader(bytes.NewReader(encrypt(t, nil, "secret")), "secret")
+		require.NoError(t, err)
+		decrypted, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Empty(t, decrypted)
+	})
+
+	t.Run("missing passphrase", func(t *testing.T) {
+		_, err := core.NewDecryptReader(bytes.NewReader(encrypted), "")
+		require.ErrorIs(t, err, core.ErrEncrypted)
+	})
+
+	t.Run("wrong passphrase", func(t *testing.T) {
+		r, err := core.NewDecryptReader(bytes.NewReader(encrypted), "wrong")
+		require.NoError(t, err)
+		_, err = io.ReadAll(r)
+		require.ErrorIs(t, err, core.ErrDecryption)
+	})
+
+	t.Run("truncated data", func(t *testing.T) {
+		r, err := core.NewDecryptReader(bytes.NewReader(encrypted[:len(encrypted)/2]), "secret")
+		require.NoError(t, err)
+		_, err = io.ReadAll(r)
+		require.ErrorIs(t, err, core.ErrDecryption)
+	})
+
+	t.Run("modified data", func(t *testing.T) {
+		modified := bytes.Clone(encrypted)
+		modified[len(modified)-1] ^= 1
+
+		r, err := core.NewDecryptReader(bytes.NewReader(modified), "secret")
+		require.NoError(t, err)
+		_, err = io.ReadAll(r)
+		require.ErrorIs(t, err, core.ErrDecryption)
+	})
+
+	t.Run("plain data is read as is", func(t *testing.T) {
+		r, err := core.NewDecryptReader(bytes.NewReader([]byte("plain")), "secret")
+		require.NoError(t, err)
+		result, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, "plain", string(result))
+	})
+}
@@ -0,0 +1,434 @@
+package core
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	fp "path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/go-shiori/shiori/internal/model"
+)
+
+// NewBookmarkExporter creates the exporter writing bookmarks to w in the specified format.
+// Markdown files are written into a zip archive, NewMarkdownDirExporter writes them into
+// a directory instead.
+func NewBookmarkExporter(w io.Writer, format model.ExportFormat) (model.BookmarkExporter, error) {
+	switch format {
+	case model.ExportFormatNetscape:
+		return newNetscapeExporter(w)
+	case model.ExportFormatJSON:
+		return newJSONExporter(w)
+	case model.ExportFormatCSV:
+		return newCSVExporter(w)
+	case model.ExportFormatMarkdown:
+		return &markdownZipExporter{zw: zip.NewWriter(w)}, nil
+	default:
+		return nil, format.IsValid()
+	}
+}
+
+// ExportFileType returns the content type and the file extension of an export.
+func ExportFileType(format model.ExportFormat) (contentType string, extension string) {
+	switch format {
+	case model.ExportFormatJSON:
+		return "application/json", ".json"
+	case model.ExportFormatCSV:
+		return "text/csv; charset=utf-8", ".csv"
+	case model.ExportFormatMarkdown:
+		return "application/zip", ".zip"
+	default:
+		return "text/html; charset=utf-8", ".html"
+	}
+}
+
+// exportTagNames returns the names of the tags of a bookmark.
+func exportTagNames(bookmark model.BookmarkDTO) []string {
+	names := make([]string, 0, len(bookmark.Tags))
+	for _, tag := range bookmark.Tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+// exportTitle returns the title of a bookmark, or its URL if it has none.
+func exportTitle(bookmark model.BookmarkDTO) string {
+	if title := strings.TrimSpace(bookmark.Title); title != "" {
+		return title
+	}
+	return bookmark.URL
+}
+
+// exportTimestamp returns the unix timestamp of a database date, now if it is invalid.
+func exportTimestamp(date string) int64 {
+	parsed, err := time.Parse(model.DatabaseDateFormat, date)
+	if err != nil {
+		parsed = time.Now()
+	}
+	return parsed.Unix()
+}
+
+// netscapeExporter writes the HTML bookmark file understood by browsers. When it is given
+// the collections, the bookmarks in them are kept until the export is closed and written
+// in nested folders.
+type netscapeExporter struct {
+	w io.Writer
+
+	// children are the collections of each parent, zero for the top level
+	children map[model.DBID][]model.Collection
+	// collected are the bookmarks of each collection
+	collected map[model.DBID][]model.BookmarkDTO
+}
+
+func newNetscapeExporter(w io.Writer) (*netscapeExporter, error) {
+	_, err := fmt.Fprintln(w, ``+
+		`<!DOCTYPE NETSCAPE-Bookmark-file-1>`+
+		`<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">`+
+		`<TITLE>Bookmarks</TITLE>`+
+		`<H1>Bookmarks</H1>`+
+		`<DL>`)
+	return &netscapeExporter{w: w}, err
+}
+
+// SetCollections sets the folders of the export. Collections are expected ordered by position,
+// the ones whose parent is missing are written at the top level.
+func (e *netscapeExporter) SetCollections(collections []model.Collection) {
+	known := make(map[model.DBID]struct{}, len(collections))
+	for _, collection := range collections {
+		known[collection.ID] = struct{}{}
+	}
+
+	e.children = make(map[model.DBID][]model.Collection)
+	e.collected = make(map[model.DBID][]model.BookmarkDTO)
+	for _, collection := range collections {
+		var parentID model.DBID
+		if collection.ParentID != nil {
+			if _, exists := known[*collection.ParentID]; exists {
+				parentID = *collection.ParentID
+			}
+		}
+		e.children[parentID] = append(e.children[parentID], collection)
+		e.collected[collection.ID] = []model.BookmarkDTO{}
+	}
+}
+
+func (e *netscapeExporter) WriteBookmark(bookmark model.BookmarkDTO) error {
+	if bookmark.CollectionID != nil {
+		if bookmarks, exists := e.collected[*bookmark.CollectionID]; exists {
+			e.collected[*bookmark.CollectionID] = append(bookmarks, bookmark)
+			return nil
+		}
+	}
+
+	return e.writeBookmark(bookmark)
+}
+
+func (e *netscapeExporter) writeBookmark(bookmark model.BookmarkDTO) error {
+	// Dates are unix timestamps, bookmarks with invalid dates are dated now
+	_, err := fmt.Fprintf(e.w, `<DT><A HREF="%s" ADD_DATE="%d" LAST_MODIFIED="%d" TAGS="%s">%s</A>`+"\n",
+		html.EscapeString(bookmark.URL),
+		exportTimestamp(bookmark.CreatedAt),
+		exportTimestamp(bookmark.ModifiedAt),
+		html.EscapeString(strings.Join(exportTagNames(bookmark), ",")),
+		html.EscapeString(exportTitle(bookmark)))
+	if err != nil {
+		return err
+	}
+
+	if excerpt := strings.TrimSpace(bookmark.Excerpt); excerpt != "" {
+		_, err = fmt.Fprintf(e.w, "<DD>%s\n", html.EscapeString(excerpt))
+	}
+
+	return err
+}
+
+// writeFolders writes the collections of the parent with their bookmarks and children.
+func (e *netscapeExporter) writeFolders(parentID model.DBID) error {
+	for _, collection := range e.children[parentID] {
+		_, err := fmt.Fprintf(e.w, `<DT><H3 ADD_DATE="%d" LAST_MODIFIED="%d">%s</H3>`+"\n<DL><p>\n",
+			exportTimestamp(collection.CreatedAt),
+			exportTimestamp(collection.ModifiedAt),
+			html.EscapeString(collection.Name))
+		if err != nil {
+			return err
+		}
+
+		for _, bookmark := range e.collected[collection.ID] {
+			if err := e.writeBookmark(bookmark); err != nil {
+				return err
+			}
+		}
+
+		if err := e.writeFolders(collection.ID); err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintln(e.w, "</DL><p>"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *netscapeExporter) Close() error {
+	if err := e.writeFolders(0); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintln(e.w, "</DL>")
+	return err
+}
+
+// jsonExportBookmark adds the text content, left out of the bookmark JSON, to the export.
+type jsonExportBookmark struct {
+	model.BookmarkDTO
+	Content string `json:"content,omitempty"`
+}
+
+// jsonExporter writes a JSON list of the bookmarks.
+type jsonExporter struct {
+	w     io.Writer
+	count int
+}
+
+func newJSONExporter(w io.Writer) (*jsonExporter, error) {
+	_, err := io.WriteString(w, "[")
+	return &jsonExporter{w: w}, err
+}
+
+func (e *jsonExporter) WriteBookmark(bookmark model.BookmarkDTO) error {
+	data, err := json.MarshalIndent(jsonExportBookmark{
+		BookmarkDTO: bookmark,
+		Content:     bookmark.Content,
+	}, "  ", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode bookmark %d: %w", bookmark.ID, err)
+	}
+
+	separator := "\n  "
+	if e.count > 0 {
+		separator = ",\n  "
+	}
+	e.count++
+
+	if _, err := io.WriteString(e.w, separator); err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonExporter) Close() error {
+	closing := "\n]\n"
+	if e.count == 0 {
+		closing = "]\n"
+	}
+	_, err := io.WriteString(e.w, closing)
+	return err
+}
+
+// csvExporter writes the metadata of the bookmarks, one per row.
+type csvExporter struct {
+	w *csv.Writer
+}
+
+func newCSVExporter(w io.Writer) (*csvExporter, error) {
+	cw := csv.NewWriter(w)
+	err := cw.Write([]string{"id", "url", "title", "excerpt", "author", "public", "tags", "created_at", "modified_at"})
+	return &csvExporter{w: cw}, err
+}
+
+func (e *csvExporter) WriteBookmark(bookmark model.BookmarkDTO) error {
+	return e.w.Write([]string{
+		strconv.Itoa(bookmark.ID),
+		bookmark.URL,
+		bookmark.Title,
+		bookmark.Excerpt,
+		bookmark.Author,
+		strconv.FormatBool(bookmark.Public == 1),
+		strings.Join(exportTagNames(bookmark), ","),
+		bookmark.CreatedAt,
+		bookmark.ModifiedAt,
+	})
+}
+
+func (e *csvExporter) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// markdownZipExporter writes a zip archive with the Markdown file of every bookmark.
+type markdownZipExporter struct {
+	zw *zip.Writer
+}
+
+func (e *markdownZipExporter) WriteBookmark(bookmark model.BookmarkDTO) error {
+	w, err := e.zw.Create(MarkdownFileName(bookmark))
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(BookmarkMarkdown(bookmark))
+	return err
+}
+
+func (e *markdownZipExporter) Close() error {
+	return e.zw.Close()
+}
+
+// markdownDirExporter writes the Markdown file of every bookmark into a directory.
+type markdownDirExporter struct {
+	dir string
+}
+
+// NewMarkdownDirExporter creates an exporter writing one Markdown file per bookmark into dir,
+// creating it when needed.
+func NewMarkdownDirExporter(dir string) (model.BookmarkExporter, error) {
+	if err := os.MkdirAll(dir, model.DataDirPerm); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	return &markdownDirExporter{dir: dir}, nil
+}
+
+func (e *markdownDirExporter) WriteBookmark(bookmark model.BookmarkDTO) error {
+	return os.WriteFile(fp.Join(e.dir, MarkdownFileName(bookmark)), BookmarkMarkdown(bookmark), 0644)
+}
+
+func (e *markdownDirExporter) Close() error {
+	return nil
+}
+
+// MarkdownFileName returns the name of the Markdown file of a bookmark, made of its ID and
+// a slug of its title.
+func MarkdownFileName(bookmark model.BookmarkDTO) string {
+	slug := strings.Builder{}
+	dash := false
+	for _, r := range strings.ToLower(exportTitle(bookmark)) {
+		if slug.Len() >= 60 {
+			break
+		}
+
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			slug.WriteRune(r)
+			dash = false
+		} else if !dash && slug.Len() > 0 {
+			slug.WriteRune('-')
+			dash = true
+		}
+	}
+
+	name := strconv.Itoa(bookmark.ID)
+	if s := strings.TrimSuffix(slug.String(), "-"); s != "" {
+		name += "-" + s
+	}
+
+	return name + ".md"
+}
+
+// BookmarkMarkdown renders a bookmark as Markdown, with its metadata in the front matter
+// followed by its readable content.
+func BookmarkMarkdown(bookmark model.BookmarkDTO) []byte {
+	// JSON strings and lists are valid YAML, so they are used to quote the values
+	quote := func(value any) string {
+		data := bytes.Buffer{}
+		encoder := json.NewEncoder(&data)
+		encoder.SetEscapeHTML(false)
+		encoder.Encode(value)
+		return strings.TrimSuffix(data.String(), "\n")
+	}
+
+	buf := bytes.Buffer{}
+	buf.WriteString("---\n")
+	fmt.Fprintf(&buf, "title: %s\n", quote(exportTitle(bookmark)))
+	fmt.Fprintf(&buf, "url: %s\n", quote(bookmark.URL))
+	if bookmark.Author != "" {
+		fmt.Fprintf(&buf, "author: %s\n", quote(bookmark.Author))
+	}
+	if bookmark.Excerpt != "" {
+		fmt.Fprintf(&buf, "excerpt: %s\n", quote(bookmark.Excerpt))
+	}
+	fmt.Fprintf(&buf, "tags: %s\n", quote(exportTagNames(bookmark)))
+	fmt.Fprintf(&buf, "public: %t\n", bookmark.Public == 1)
+	fmt.Fprintf(&buf, "created_at: %s\n", quote(bookmark.CreatedAt))
+	fmt.Fprintf(&buf, "modified_at: %s\n", quote(bookmark.ModifiedAt))
+	buf.WriteString("---\n\n")
+
+	fmt.Fprintf(&buf, "# %s\n", exportTitle(bookmark))
+
+	content := strings.TrimSpace(bookmark.Content)
+	if bookmark.HTML != "" {
+		if markdown, err := HTMLToMarkdown(bookmark.HTML); err == nil {
+			content = markdown
+		}
+	}
+
+	if content = strings.TrimSpace(content); content != "" {
+		buf.WriteString("\n" + content + "\n")
+	}
+
+	return buf.Bytes()
+}
+
+// ExportHighlights writes the highlights of the bookmarks as a Markdown document or as JSON.
+func ExportHighlights(w io.Writer, format model.ExportFormat, bookmarks []model.BookmarkHighlights) error {
+	if err := model.ValidateHighlightsExportFormat(format); err != nil {
+		return err
+	}
+
+	if format == model.ExportFormatJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(bookmarks)
+	}
+
+	_, err := w.Write(HighlightsMarkdown(bookmarks))
+	return err
+}
+
+// HighlightsExportFileType returns the content type and the file extension of a highlights
+// export.
+func HighlightsExportFileType(format model.ExportFormat) (contentType string, extension string) {
+	if format == model.ExportFormatJSON {
+		return "application/json", ".json"
+	}
+	return "text/markdown; charset=utf-8", ".md"
+}
+
+// HighlightsMarkdown renders the highlights of the bookmarks as a Markdown document, with a
+// section per bookmark where the highlighted passages are quoted and followed by their notes.
+func HighlightsMarkdown(bookmarks []model.BookmarkHighlights) []byte {
+	linkText := strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`)
+
+	buf := bytes.Buffer{}
+	buf.WriteString("# Highlights\n")
+	for _, bookmark := range bookmarks {
+		title := strings.TrimSpace(bookmark.Title)
+		if title == "" {
+			title = bookmark.URL
+		}
+		fmt.Fprintf(&buf, "\n## [%s](<%s>)\n", linkText.Replace(title), bookmark.URL)
+
+		for _, highlight := range bookmark.Highlights {
+			buf.WriteString("\n")
+			for _, line := range strings.Split(strings.TrimSpace(highlight.Selector.Quote.Exact), "\n") {
+				buf.WriteString(strings.TrimRight("> "+strings.TrimSpace(line), " ") + "\n")
+			}
+
+			if note := strings.TrimSpace(highlight.Note); note != "" {
+				buf.WriteString("\n" + note + "\n")
+			}
+		}
+	}
+
+	return buf.Bytes()
+}
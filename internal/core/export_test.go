@@ -0,0 +1,193 @@
+package core_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/core"
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func exportTestBookmarks() []model.BookmarkDTO {
+	return []model.BookmarkDTO{
+		{
+			ID:         1,
+			URL:        "https://github.com/go-shiori/shiori",
+			Title:      "Shiori: a <simple> bookmark manager",
+			Excerpt:    "Simple bookmark manager built with Go",
+			Author:     "go-shiori",
+			Public:     1,
+			CreatedAt:  "2019-01-01 10:00:00",
+			ModifiedAt: "2019-02-01 10:00:00",
+			Content:    "Shiori is a simple bookmarks manager",
+			HTML:       "<p>Shiori is a <strong>simple</strong> bookmarks manager</p>",
+			Tags:       []model.TagDTO{{Tag: model.Tag{Name: "go"}}, {Tag: model.Tag{Name: "tools"}}},
+		},
+		{
+			ID:         2,
+			URL:        "https://go.dev",
+			CreatedAt:  "2019-03-01 10:00:00",
+			ModifiedAt: "2019-03-01 10:00:00",
+			Tags:       []model.TagDTO{},
+		},
+	}
+}
+
+func exportBookmarks(t *testing.T, format model.ExportFormat, bookmarks []model.BookmarkDTO) []byte {
+	buf := bytes.Buffer{}
+	exporter, err := core.NewBookmarkExporter(&buf, format)
+	require.NoError(t, err)
+
+	for _, bookmark := range bookmarks {
+		require.NoError(t, exporter.WriteBookmark(bookmark))
+	}
+	require.NoError(t, exporter.Close())
+
+	return buf.Bytes()
+}
+
+func TestNewBookmarkExporter(t *testing.T) {
+	t.Run("invalid format", func(t *testing.T) {
+		_, err := core.NewBookmarkExporter(io.Discard, "xml")
+		require.Error(t, err)
+	})
+
+	t.Run("netscape", func(t *testing.T) {
+		result := string(exportBookmarks(t, model.ExportFormatNetscape, exportTestBookmarks()))
+		require.Contains(t, result, `<!DOCTYPE NETSCAPE-Bookmark-file-1>`)
+		require.Contains(t, result, `<DT><A HREF="https://github.com/go-shiori/shiori" ADD_DATE="1546336800" LAST_MODIFIED="1549015200" TAGS="go,tools">Shiori: a &lt;simple&gt; bookmark manager</A>`)
+		require.Contains(t, result, `<DD>Simple bookmark manager built with Go`)
+		require.Contains(t, result, `>https://go.dev</A>`)
+	})
+
+	t.Run("netscape folders", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		exporter, err := core.NewBookmarkExporter(&buf, model.ExportFormatNetscape)
+		require.NoError(t, err)
+
+		parentID := model.DBID(10)
+		exporter.(model.CollectionsExporter).SetCollections([]model.Collection{
+			{ID: parentID, Name: "Tools & libraries"},
+			{ID: 11, ParentID: &parentID, Name: "Go"},
+		})
+
+		bookmarks := exportTestBookmarks()
+		bookmarks[1].CollectionID = model.Ptr(model.DBID(11))
+		for _, bookmark := range bookmarks {
+			require.NoError(t, exporter.WriteBookmark(bookmark))
+		}
+		require.NoError(t, exporter.Close())
+
+		result := buf.String()
+		parent := strings.Index(result, `>Tools &amp; libraries</H3>`)
+		child := strings.Index(result, `>Go</H3>`)
+		inChild := strings.Index(result, `>https://go.dev</A>`)
+		require.Greater(t, parent, strings.Index(result, `>Shiori: a &lt;simple&gt; bookmark manager</A>`))
+		require.Greater(t, child, parent)
+		require.Greater(t, inChild, child)
+		require.Equal(t, 3, strings.Count(result, "</DL>"))
+	})
+
+	t.Run("json is lossless", func(t *testing.T) {
+		bookmarks := exportTestBookmarks()
+		result := exportBookmarks(t, model.ExportFormatJSON, bookmarks)
+
+		var decoded []struct {
+			model.BookmarkDTO
+			Content string `json:"content"`
+		}
+		require.NoError(t, json.Unmarshal(result, &decoded))
+		require.Len(t, decoded, 2)
+		require.Equal(t, bookmarks[0].Content, decoded[0].Content)
+
+		decoded[0].BookmarkDTO.Content = decoded[0].Content
+		require.Equal(t, bookmarks[0], decoded[0].BookmarkDTO)
+	})
+
+	t.Run("json without bookmarks", func(t *testing.T) {
+		result := exportBookmarks(t, model.ExportFormatJSON, nil)
+		require.JSONEq(t, "[]", string(result))
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		result := exportBookmarks(t, model.ExportFormatCSV, exportTestBookmarks())
+
+		records, err := csv.NewReader(bytes.NewReader(result)).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+		require.Equal(t, []string{"id", "url", "title", "excerpt", "author", "public", "tags", "created_at", "modified_at"}, records[0])
+		require.Equal(t, []string{
+			"1",
+			"https://github.com/go-shiori/shiori",
+			"Shiori: a <simple> bookmark manager",
+			"Simple bookmark manager built with Go",
+			"go-shiori",
+			"true",
+			"go,tools",
+			"2019-01-01 10:00:00",
+			"2019-02-01 10:00:00",
+		}, records[1])
+	})
+
+	t.Run("markdown zip", func(t *testing.T) {
+		result := exportBookmarks(t, model.ExportFormatMarkdown, exportTestBookmarks())
+
+		archive, err := zip.NewReader(bytes.NewReader(result), int64(len(result)))
+		require.NoError(t, err)
+		require.Len(t, archive.File, 2)
+		require.Equal(t, "1-shiori-a-simple-bookmark-manager.md", archive.File[0].Name)
+		require.Equal(t, "2-https-go-dev.md", archive.File[1].Name)
+	})
+}
+
+func TestNewMarkdownDirExporter(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "export")
+
+	exporter, err := core.NewMarkdownDirExporter(dir)
+	require.NoError(t, err)
+	for _, bookmark := range exportTestBookmarks() {
+		require.NoError(t, exporter.WriteBookmark(bookmark))
+	}
+	require.NoError(t, exporter.Close())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+}
+
+func TestBookmarkMarkdown(t *testing.T) {
+	bookmarks := exportTestBookmarks()
+
+	t.Run("front matter and content", func(t *testing.T) {
+		result := string(core.BookmarkMarkdown(bookmarks[0]))
+		require.Equal(t, `---
+title: "Shiori: a <simple> bookmark manager"
+url: "https://github.com/go-shiori/shiori"
+author: "go-shiori"
+excerpt: "Simple bookmark manager built with Go"
+tags: ["go","tools"]
+public: true
+created_at: "2019-01-01 10:00:00"
+modified_at: "2019-02-01 10:00:00"
+---
+
+# Shiori: a <simple> bookmark manager
+
+Shiori is a **simple** bookmarks manager
+`, result)
+	})
+
+	t.Run("without content", func(t *testing.T) {
+		result := string(core.BookmarkMarkdown(bookmarks[1]))
+		require.Contains(t, result, "tags: []\npublic: false\n")
+		require.True(t, bytes.HasSuffix([]byte(result), []byte("---\n\n# https://go.dev\n")))
+	})
+}
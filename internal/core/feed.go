@@ -0,0 +1,281 @@
+package core
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/model"
+)
+
+// feedGenerator names Shiori as the generator of the feeds
+const feedGenerator = "Shiori"
+
+// FeedContentType returns the content type of a feed format.
+func FeedContentType(format model.FeedFormat) string {
+	if format == model.FeedFormatRSS {
+		return "application/rss+xml; charset=utf-8"
+	}
+	return "application/atom+xml; charset=utf-8"
+}
+
+// FeedUpdated returns when the latest of the bookmarks was modified, the zero time if there
+// are none.
+func FeedUpdated(bookmarks []model.BookmarkDTO) time.Time {
+	updated := time.Time{}
+	for _, bookmark := range bookmarks {
+		if modified := feedBookmarkModified(bookmark); modified.After(updated) {
+			updated = modified
+		}
+	}
+	return updated
+}
+
+// WriteFeed writes the bookmarks, in the order given, as a feed in the specified format.
+func WriteFeed(w io.Writer, format model.FeedFormat, feed model.Feed, bookmarks []model.BookmarkDTO) error {
+	var document any
+	switch format {
+	case model.FeedFormatAtom:
+		document = newAtomFeed(feed, bookmarks)
+	case model.FeedFormatRSS:
+		document = newRSSFeed(feed, bookmarks)
+	default:
+		return format.IsValid()
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return fmt.Errorf("failed to encode feed: %w", err)
+	}
+	return encoder.Close()
+}
+
+// parseFeedDate parses the dates of a bookmark as returned by any of the databases.
+func parseFeedDate(value string) time.Time {
+	for _, layout := range []string{model.DatabaseDateFormat, time.RFC3339Nano} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed.UTC()
+		}
+	}
+	return time.Time{}
+}
+
+// feedBookmarkModified returns when a bookmark was last modified, or created if unknown.
+func feedBookmarkModified(bookmark model.BookmarkDTO) time.Time {
+	if modified := parseFeedDate(bookmark.ModifiedAt); !modified.IsZero() {
+		return modified
+	}
+	return parseFeedDate(bookmark.CreatedAt)
+}
+
+// feedEntryID returns the permanent ID of a bookmark in the feeds, the URL of its page.
+func feedEntryID(feed model.Feed, bookmark model.BookmarkDTO) string {
+	return feed.BaseURL + "bookmark/" + strconv.Itoa(bookmark.ID) + "/content"
+}
+
+// feedEntryContent returns the content of a bookmark in the feeds and whether it is HTML.
+func feedEntryContent(feed model.Feed, bookmark model.BookmarkDTO) (string, bool) {
+	if feed.FullContent && strings.TrimSpace(bookmark.HTML) != "" {
+		return bookmark.HTML, true
+	}
+	return strings.TrimSpace(bookmark.Excerpt), false
+}
+
+// feedThumbnailURL returns the absolute URL of the thumbnail of a bookmark, empty if it
+// has none. The image URL of bookmarks is already prefixed with the root path.
+func feedThumbnailURL(feed model.Feed, bookmark model.BookmarkDTO) string {
+	if bookmark.ImageURL == "" {
+		return ""
+	}
+
+	base, err := url.Parse(feed.BaseURL)
+	if err != nil {
+		return bookmark.ImageURL
+	}
+
+	image, err := url.Parse(bookmark.ImageURL)
+	if err != nil {
+		return ""
+	}
+
+	return base.ResolveReference(image).String()
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published,omitempty"`
+	Author     *atomPerson    `xml:"author,omitempty"`
+	Links      []atomLink     `xml:"link"`
+	Categories []atomCategory `xml:"category"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+	Content    *atomText      `xml:"content,omitempty"`
+}
+
+type atomFeed struct {
+	XMLName   xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title     string      `xml:"title"`
+	ID        string      `xml:"id"`
+	Updated   string      `xml:"updated"`
+	Author    atomPerson  `xml:"author"`
+	Generator string      `xml:"generator"`
+	Links     []atomLink  `xml:"link"`
+	Entries   []atomEntry `xml:"entry"`
+}
+
+func newAtomFeed(feed model.Feed, bookmarks []model.BookmarkDTO) atomFeed {
+	document := atomFeed{
+		Title:     feed.Title,
+		ID:        feed.SelfURL,
+		Updated:   FeedUpdated(bookmarks).Format(time.RFC3339),
+		Author:    atomPerson{Name: feedGenerator},
+		Generator: feedGenerator,
+		Links: []atomLink{
+			{Rel: "self", Type: "application/atom+xml", Href: feed.SelfURL},
+			{Rel: "alternate", Type: "text/html", Href: feed.BaseURL},
+		},
+	}
+
+	for _, bookmark := range bookmarks {
+		entry := atomEntry{
+			Title:   exportTitle(bookmark),
+			ID:      feedEntryID(feed, bookmark),
+			Updated: feedBookmarkModified(bookmark).Format(time.RFC3339),
+			Links:   []atomLink{{Rel: "alternate", Href: bookmark.URL}},
+		}
+
+		if created := parseFeedDate(bookmark.CreatedAt); !created.IsZero() {
+			entry.Published = created.Format(time.RFC3339)
+		}
+
+		if bookmark.Author != "" {
+			entry.Author = &atomPerson{Name: bookmark.Author}
+		}
+
+		if thumbnail := feedThumbnailURL(feed, bookmark); thumbnail != "" {
+			entry.Links = append(entry.Links, atomLink{Rel: "enclosure", Type: "image/jpeg", Href: thumbnail})
+		}
+
+		for _, tag := range exportTagNames(bookmark) {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+
+		if content, isHTML := feedEntryContent(feed, bookmark); isHTML {
+			entry.Content = &atomText{Type: "html", Body: content}
+		} else if content != "" {
+			entry.Summary = &atomText{Type: "text", Body: content}
+		}
+
+		document.Entries = append(document.Entries, entry)
+	}
+
+	return document
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int    `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	Description string        `xml:"description,omitempty"`
+	Categories  []string      `xml:"category"`
+	GUID        rssGUID       `xml:"guid"`
+	PubDate     string        `xml:"pubDate,omitempty"`
+	Enclosure   *rssEnclosure `xml:"enclosure,omitempty"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Generator     string    `xml:"generator"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+func newRSSFeed(feed model.Feed, bookmarks []model.BookmarkDTO) rssFeed {
+	channel := rssChannel{
+		Title:       feed.Title,
+		Link:        feed.BaseURL,
+		Description: feed.Title,
+		Generator:   feedGenerator,
+	}
+
+	if updated := FeedUpdated(bookmarks); !updated.IsZero() {
+		channel.LastBuildDate = updated.Format(time.RFC1123Z)
+	}
+
+	for _, bookmark := range bookmarks {
+		// Descriptions are HTML in RSS, plain excerpts are escaped to show as they are
+		content, isHTML := feedEntryContent(feed, bookmark)
+		if !isHTML {
+			content = strings.ReplaceAll(html.EscapeString(content), "\n", "<br>")
+		}
+
+		item := rssItem{
+			Title:       exportTitle(bookmark),
+			Link:        bookmark.URL,
+			Description: content,
+			Categories:  exportTagNames(bookmark),
+			GUID:        rssGUID{Value: feedEntryID(feed, bookmark)},
+		}
+
+		if created := parseFeedDate(bookmark.CreatedAt); !created.IsZero() {
+			item.PubDate = created.Format(time.RFC1123Z)
+		}
+
+		// The size of thumbnails isn't known without reading them, zero is the usual placeholder
+		if thumbnail := feedThumbnailURL(feed, bookmark); thumbnail != "" {
+			item.Enclosure = &rssEnclosure{URL: thumbnail, Type: "image/jpeg"}
+		}
+
+		channel.Items = append(channel.Items, item)
+	}
+
+	return rssFeed{Version: "2.0", Channel: channel}
+}
@@ -0,0 +1,124 @@
+package core_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/core"
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+var testFeed = model.Feed{
+	Title:   "Shiori public bookmarks",
+	SelfURL: "https://shiori.example.com/feeds/atom",
+	BaseURL: "https://shiori.example.com/",
+}
+
+func feedTestBookmarks() []model.BookmarkDTO {
+	bookmarks := exportTestBookmarks()
+	bookmarks[0].ImageURL = "/bookmark/1/thumb"
+	return bookmarks
+}
+
+func TestWriteFeedAtom(t *testing.T) {
+	buf := bytes.Buffer{}
+	require.NoError(t, core.WriteFeed(&buf, model.FeedFormatAtom, testFeed, feedTestBookmarks()))
+
+	var feed struct {
+		Title   string `xml:"title"`
+		Updated string `xml:"updated"`
+		Entries []struct {
+			Title string `xml:"title"`
+			ID    string `xml:"id"`
+			Links []struct {
+				Rel  string `xml:"rel,attr"`
+				Href string `xml:"href,attr"`
+			} `xml:"link"`
+			Categories []struct {
+				Term string `xml:"term,attr"`
+			} `xml:"category"`
+			Summary string `xml:"summary"`
+			Content string `xml:"content"`
+		} `xml:"entry"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &feed))
+
+	require.Equal(t, "Shiori public bookmarks", feed.Title)
+	require.Equal(t, "2019-03-01T10:00:00Z", feed.Updated)
+	require.Len(t, feed.Entries, 2)
+
+	entry := feed.Entries[0]
+	require.Equal(t, "Shiori: a <simple> bookmark manager", entry.Title)
+	require.Equal(t, "https://shiori.example.com/bookmark/1/content", entry.ID)
+	require.Equal(t, "https://github.com/go-shiori/shiori", entry.Links[0].Href)
+	require.Equal(t, "enclosure", entry.Links[1].Rel)
+	require.Equal(t, "https://shiori.example.com/bookmark/1/thumb", entry.Links[1].Href)
+	require.Len(t, entry.Categories, 2)
+	require.Equal(t, "go", entry.Categories[0].Term)
+	require.Equal(t, "Simple bookmark manager built with Go", entry.Summary)
+	require.Empty(t, entry.Content)
+
+	// Bookmarks without a title use their URL
+	require.Equal(t, "https://go.dev", feed.Entries[1].Title)
+
+	t.Run("full content", func(t *testing.T) {
+		feed := testFeed
+		feed.FullContent = true
+
+		buf := bytes.Buffer{}
+		require.NoError(t, core.WriteFeed(&buf, model.FeedFormatAtom, feed, feedTestBookmarks()))
+		require.Contains(t, buf.String(), `<content type="html">&lt;p&gt;Shiori is a &lt;strong&gt;simple&lt;/strong&gt; bookmarks manager&lt;/p&gt;</content>`)
+	})
+}
+
+func TestWriteFeedRSS(t *testing.T) {
+	buf := bytes.Buffer{}
+	require.NoError(t, core.WriteFeed(&buf, model.FeedFormatRSS, testFeed, feedTestBookmarks()))
+
+	var feed struct {
+		Version string `xml:"version,attr"`
+		Channel struct {
+			Title         string `xml:"title"`
+			LastBuildDate string `xml:"lastBuildDate"`
+			Items         []struct {
+				Title       string   `xml:"title"`
+				Link        string   `xml:"link"`
+				Description string   `xml:"description"`
+				Categories  []string `xml:"category"`
+				GUID        string   `xml:"guid"`
+				PubDate     string   `xml:"pubDate"`
+				Enclosure   *struct {
+					URL  string `xml:"url,attr"`
+					Type string `xml:"type,attr"`
+				} `xml:"enclosure"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &feed))
+
+	require.Equal(t, "2.0", feed.Version)
+	require.Equal(t, "Shiori public bookmarks", feed.Channel.Title)
+	require.Len(t, feed.Channel.Items, 2)
+
+	item := feed.Channel.Items[0]
+	require.Equal(t, "https://github.com/go-shiori/shiori", item.Link)
+	require.Equal(t, "Simple bookmark manager built with Go", item.Description)
+	require.Equal(t, []string{"go", "tools"}, item.Categories)
+	require.Equal(t, "https://shiori.example.com/bookmark/1/content", item.GUID)
+	require.NotNil(t, item.Enclosure)
+	require.Equal(t, "image/jpeg", item.Enclosure.Type)
+
+	pubDate, err := time.Parse(time.RFC1123Z, item.PubDate)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2019, 1, 1, 10, 0, 0, 0, time.UTC), pubDate.UTC())
+
+	require.Nil(t, feed.Channel.Items[1].Enclosure)
+}
+
+func TestWriteFeedInvalidFormat(t *testing.T) {
+	buf := bytes.Buffer{}
+	require.Error(t, core.WriteFeed(&buf, model.FeedFormat("json"), testFeed, feedTestBookmarks()))
+}
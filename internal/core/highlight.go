@@ -0,0 +1,257 @@
+package core
+
+import (
+	"bytes"
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// parseReadableHTML parses the readable HTML of a bookmark the way the reader view does, into
+// the children of a div.
+func parseReadableHTML(content string) (*html.Node, error) {
+	root := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	nodes, err := html.ParseFragment(strings.NewReader(content), root)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, node := range nodes {
+		root.AppendChild(node)
+	}
+	return root, nil
+}
+
+// textNodes returns the text nodes under the node, in document order.
+func textNodes(root *html.Node) []*html.Node {
+	texts := []*html.Node{}
+	var walk func(node *html.Node)
+	walk = func(node *html.Node) {
+		if node.Type == html.TextNode {
+			texts = append(texts, node)
+			return
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(root)
+	return texts
+}
+
+// HighlightText returns the text the highlight selectors refer to: the text nodes of the
+// readable HTML joined as they are, like the text content of the reader view.
+func HighlightText(content string) string {
+	root, err := parseReadableHTML(content)
+	if err != nil {
+		return ""
+	}
+
+	text := strings.Builder{}
+	for _, node := range textNodes(root) {
+		text.WriteString(node.Data)
+	}
+	return text.String()
+}
+
+// LocateHighlight finds the passage of the text selected by a highlight. The position is used
+// when it still quotes the exact text. Otherwise the occurrence of the quote whose surrounding
+// text best matches its prefix and suffix is picked, the closest to the position on a tie.
+func LocateHighlight(text string, selector model.HighlightSelector) (model.TextPositionSelector, bool) {
+	quote := selector.Quote
+	if quote.Exact == "" {
+		return model.TextPositionSelector{}, false
+	}
+
+	position := selector.Position
+	if position != nil && position.Start >= 0 && position.End > position.Start {
+		runes := []rune(text)
+		if position.End <= len(runes) && string(runes[position.Start:position.End]) == quote.Exact {
+			return *position, true
+		}
+	}
+
+	found := false
+	best := model.TextPositionSelector{}
+	bestScore, bestDistance := -1, 0
+	exactLength := utf8.RuneCountInString(quote.Exact)
+
+	// Offsets of the search in bytes and in characters
+	offset, runeOffset := 0, 0
+	for {
+		idx := strings.Index(text[offset:], quote.Exact)
+		if idx < 0 {
+			break
+		}
+		runeOffset += utf8.RuneCountInString(text[offset : offset+idx])
+		offset += idx
+
+		score := commonSuffixLength(text[:offset], quote.Prefix) +
+			commonPrefixLength(text[offset+len(quote.Exact):], quote.Suffix)
+		distance := 0
+		if position != nil {
+			distance = max(runeOffset-position.Start, position.Start-runeOffset)
+		}
+
+		if score > bestScore || (score == bestScore && distance < bestDistance) {
+			found = true
+			best = model.TextPositionSelector{Start: runeOffset, End: runeOffset + exactLength}
+			bestScore, bestDistance = score, distance
+		}
+
+		// Quotes may overlap, the search goes on from the next character
+		_, size := utf8.DecodeRuneInString(text[offset:])
+		offset += size
+		runeOffset++
+	}
+
+	return best, found
+}
+
+func commonPrefixLength(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+func commonSuffixLength(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[len(a)-1-n] == b[len(b)-1-n] {
+		n++
+	}
+	return n
+}
+
+// SortHighlights orders the highlights by their position in the content, the ones without
+// position come last.
+func SortHighlights(highlights []model.Highlight) {
+	slices.SortStableFunc(highlights, func(a, b model.Highlight) int {
+		switch pa, pb := a.Selector.Position, b.Selector.Position; {
+		case pa != nil && pb != nil:
+			return cmp.Compare(pa.Start, pb.Start)
+		case pa != nil:
+			return -1
+		case pb != nil:
+			return 1
+		}
+		return 0
+	})
+}
+
+// highlightRange is a highlight located in the text
+type highlightRange struct {
+	start, end int
+	highlight  model.Highlight
+}
+
+// RenderHighlights wraps the passages of the readable HTML selected by the highlights in mark
+// elements, titled with the note of the highlight. The highlights that can't be located in
+// the content are left out. Where highlights overlap, the one starting last is marked.
+func RenderHighlights(content string, highlights []model.Highlight) string {
+	root, err := parseReadableHTML(content)
+	if err != nil {
+		return content
+	}
+
+	texts := textNodes(root)
+	text := strings.Builder{}
+	for _, node := range texts {
+		text.WriteString(node.Data)
+	}
+
+	ranges := []highlightRange{}
+	for _, highlight := range highlights {
+		if position, ok := LocateHighlight(text.String(), highlight.Selector); ok {
+			ranges = append(ranges, highlightRange{start: position.Start, end: position.End, highlight: highlight})
+		}
+	}
+	if len(ranges) == 0 {
+		return content
+	}
+
+	nodeStart := 0
+	for _, node := range texts {
+		runes := []rune(node.Data)
+		nodeEnd := nodeStart + len(runes)
+		start := nodeStart
+		nodeStart = nodeEnd
+
+		parent := node.Parent
+		switch parent.DataAtom {
+		case atom.Script, atom.Style, atom.Textarea, atom.Title:
+			continue
+		}
+
+		// Split the node where highlights start or end
+		cuts := []int{start, nodeEnd}
+		for _, r := range ranges {
+			if r.start < nodeEnd && r.end > start {
+				cuts = append(cuts, max(r.start, start), min(r.end, nodeEnd))
+			}
+		}
+		if len(cuts) == 2 {
+			continue
+		}
+		slices.Sort(cuts)
+		cuts = slices.Compact(cuts)
+
+		// Consecutive segments of the same highlight share their mark
+		var lastMark *html.Node
+		var lastCover *highlightRange
+		for i := 0; i+1 < len(cuts); i++ {
+			segment := &html.Node{Type: html.TextNode, Data: string(runes[cuts[i]-start : cuts[i+1]-start])}
+
+			var cover *highlightRange
+			for j := range ranges {
+				r := &ranges[j]
+				if r.start <= cuts[i] && r.end >= cuts[i+1] && (cover == nil || r.start >= cover.start) {
+					cover = r
+				}
+			}
+
+			if cover == nil {
+				parent.InsertBefore(segment, node)
+				lastMark, lastCover = nil, nil
+				continue
+			}
+
+			if cover == lastCover {
+				lastMark.FirstChild.Data += segment.Data
+				continue
+			}
+
+			mark := &html.Node{
+				Type:     html.ElementNode,
+				Data:     "mark",
+				DataAtom: atom.Mark,
+				Attr: []html.Attribute{
+					{Key: "class", Val: "highlight"},
+					{Key: "data-highlight-id", Val: strconv.Itoa(int(cover.highlight.ID))},
+				},
+			}
+			if note := strings.TrimSpace(cover.highlight.Note); note != "" {
+				mark.Attr = append(mark.Attr, html.Attribute{Key: "title", Val: note})
+			}
+			mark.AppendChild(segment)
+			parent.InsertBefore(mark, node)
+			lastMark, lastCover = mark, cover
+		}
+		parent.RemoveChild(node)
+	}
+
+	buf := bytes.Buffer{}
+	for node := root.FirstChild; node != nil; node = node.NextSibling {
+		if err := html.Render(&buf, node); err != nil {
+			return content
+		}
+	}
+	return buf.String()
+}
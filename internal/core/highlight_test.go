@@ -0,0 +1,121 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/core"
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+const highlightTestHTML = `<p>Gophers dig. The <b>gopher</b> is a rodent.</p><p>Every gopher digs tunnels, café gopher too.</p>`
+
+func quoteSelector(exact, prefix, suffix string) model.HighlightSelector {
+	return model.HighlightSelector{Quote: model.TextQuoteSelector{Exact: exact, Prefix: prefix, Suffix: suffix}}
+}
+
+func TestHighlightText(t *testing.T) {
+	require.Equal(t, "Gophers dig. The gopher is a rodent.Every gopher digs tunnels, café gopher too.", core.HighlightText(highlightTestHTML))
+	require.Equal(t, "plain text", core.HighlightText("plain text"))
+}
+
+func TestLocateHighlight(t *testing.T) {
+	text := core.HighlightText(highlightTestHTML)
+
+	t.Run("prefix and suffix pick the occurrence", func(t *testing.T) {
+		position, ok := core.LocateHighlight(text, quoteSelector("gopher", "Every ", " digs"))
+		require.True(t, ok)
+		require.Equal(t, model.TextPositionSelector{Start: 42, End: 48}, position)
+	})
+
+	t.Run("positions count characters", func(t *testing.T) {
+		position, ok := core.LocateHighlight(text, quoteSelector("gopher", "café ", ""))
+		require.True(t, ok)
+		require.Equal(t, model.TextPositionSelector{Start: 68, End: 74}, position)
+	})
+
+	t.Run("position used when it quotes the text", func(t *testing.T) {
+		selector := quoteSelector("gopher", "", "")
+		selector.Position = &model.TextPositionSelector{Start: 42, End: 48}
+		position, ok := core.LocateHighlight(text, selector)
+		require.True(t, ok)
+		require.Equal(t, *selector.Position, position)
+	})
+
+	t.Run("closest to a stale position", func(t *testing.T) {
+		selector := quoteSelector("gopher", "", "")
+		selector.Position = &model.TextPositionSelector{Start: 40, End: 46}
+		position, ok := core.LocateHighlight(text, selector)
+		require.True(t, ok)
+		require.Equal(t, model.TextPositionSelector{Start: 42, End: 48}, position)
+	})
+
+	t.Run("missing quote", func(t *testing.T) {
+		_, ok := core.LocateHighlight(text, quoteSelector("beaver", "", ""))
+		require.False(t, ok)
+	})
+}
+
+func TestRenderHighlights(t *testing.T) {
+	t.Run("across elements", func(t *testing.T) {
+		result := core.RenderHighlights(highlightTestHTML, []model.Highlight{
+			{ID: 1, Selector: quoteSelector("The gopher is", "", ""), Note: "a <note>"},
+			{ID: 2, Selector: quoteSelector("gopher", "café ", "")},
+		})
+		require.Equal(t, `<p>Gophers dig. <mark class="highlight" data-highlight-id="1" title="a &lt;note&gt;">The </mark>`+
+			`<b><mark class="highlight" data-highlight-id="1" title="a &lt;note&gt;">gopher</mark></b>`+
+			`<mark class="highlight" data-highlight-id="1" title="a &lt;note&gt;"> is</mark> a rodent.</p>`+
+			`<p>Every gopher digs tunnels, café <mark class="highlight" data-highlight-id="2">gopher</mark> too.</p>`, result)
+	})
+
+	t.Run("overlapping highlights", func(t *testing.T) {
+		result := core.RenderHighlights("<p>one two three</p>", []model.Highlight{
+			{ID: 1, Selector: quoteSelector("one two", "", "")},
+			{ID: 2, Selector: quoteSelector("two three", "", "")},
+		})
+		require.Equal(t, `<p><mark class="highlight" data-highlight-id="1">one </mark>`+
+			`<mark class="highlight" data-highlight-id="2">two three</mark></p>`, result)
+	})
+
+	t.Run("highlights not found are left out", func(t *testing.T) {
+		result := core.RenderHighlights(highlightTestHTML, []model.Highlight{
+			{ID: 1, Selector: quoteSelector("beaver", "", "")},
+		})
+		require.Equal(t, highlightTestHTML, result)
+	})
+}
+
+func TestHighlightsMarkdown(t *testing.T) {
+	result := core.HighlightsMarkdown([]model.BookmarkHighlights{
+		{
+			ID:    1,
+			URL:   "https://github.com/go-shiori/shiori",
+			Title: "Shiori [docs]",
+			Highlights: []model.Highlight{
+				{Selector: quoteSelector("simple bookmarks manager", "", ""), Note: "what it is"},
+				{Selector: quoteSelector("first line\n  second line", "", "")},
+			},
+		},
+		{
+			ID:         2,
+			URL:        "https://go.dev",
+			Highlights: []model.Highlight{{Selector: quoteSelector("Go", "", "")}},
+		},
+	})
+
+	require.Equal(t, `# Highlights
+
+## [Shiori \[docs\]](<https://github.com/go-shiori/shiori>)
+
+> simple bookmarks manager
+
+what it is
+
+> first line
+> second line
+
+## [https://go.dev](<https://go.dev>)
+
+> Go
+`, string(result))
+}
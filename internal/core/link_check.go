@@ -0,0 +1,47 @@
+package core
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/model"
+)
+
+// maxLinkCheckBodySize is how much of the response body is read so the connection can be reused
+const maxLinkCheckBodySize = 64 * 1024
+
+// CheckLink requests the URL and returns the result of the check. Failing to reach the URL
+// is not an error, it's recorded in the check.
+func CheckLink(ctx context.Context, url string, timeout time.Duration) model.LinkCheck {
+	check := model.LinkCheck{URL: url}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	start := time.Now()
+	resp, err := httpClient.Do(req)
+	check.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxLinkCheckBodySize))
+
+	check.StatusCode = resp.StatusCode
+	check.FinalURL = resp.Request.URL.String()
+	if resp.StatusCode >= 400 {
+		check.Error = resp.Status
+	}
+
+	return check
+}
@@ -0,0 +1,59 @@
+package core_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/core"
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckLink(t *testing.T) {
+	ctx := context.Background()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/ok", http.StatusMovedPermanently)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	t.Run("reachable", func(t *testing.T) {
+		check := core.CheckLink(ctx, server.URL+"/ok", time.Second)
+		require.Equal(t, http.StatusOK, check.StatusCode)
+		require.Equal(t, server.URL+"/ok", check.FinalURL)
+		require.Empty(t, check.Error)
+		require.Equal(t, model.LinkStatusOK, check.Status())
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		check := core.CheckLink(ctx, server.URL+"/missing", time.Second)
+		require.Equal(t, http.StatusNotFound, check.StatusCode)
+		require.NotEmpty(t, check.Error)
+		require.Equal(t, model.LinkStatusBroken, check.Status())
+	})
+
+	t.Run("redirect", func(t *testing.T) {
+		check := core.CheckLink(ctx, server.URL+"/moved", time.Second)
+		require.Equal(t, http.StatusOK, check.StatusCode)
+		require.Equal(t, server.URL+"/ok", check.FinalURL)
+		require.Equal(t, model.LinkStatusRedirected, check.Status())
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		check := core.CheckLink(ctx, "http://127.0.0.1:1", time.Second)
+		require.Zero(t, check.StatusCode)
+		require.NotEmpty(t, check.Error)
+		require.Equal(t, model.LinkStatusBroken, check.Status())
+	})
+}
@@ -0,0 +1,210 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var rxMarkdownSpaces = regexp.MustCompile(`[ \t\r\n]+`)
+
+// HTMLToMarkdown converts the readable content of a bookmark into Markdown. Only the elements
+// kept by readability are handled, anything else is reduced to its text.
+func HTMLToMarkdown(content string) (string, error) {
+	doc, err := html.Parse(strings.NewReader(content))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	conv := markdownConverter{}
+	conv.children(doc)
+
+	return cleanMarkdown(conv.String()) + "\n", nil
+}
+
+// cleanMarkdown removes the trailing spaces and repeated blank lines left by the blocks.
+// A line holding only a quote prefix counts as blank, the shortest of repeated ones is kept
+// so the blank line after a quote ends it.
+func cleanMarkdown(content string) string {
+	lines := []string{}
+	blank := true
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, " ")
+		lineBlank := strings.Trim(line, "> ") == ""
+		if lineBlank && blank {
+			if last := len(lines) - 1; last >= 0 && len(line) < len(lines[last]) {
+				lines[last] = line
+			}
+			continue
+		}
+		blank = lineBlank
+		lines = append(lines, line)
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+type markdownConverter struct {
+	strings.Builder
+	// Prefix of every line in the current block, used by quotes and lists
+	prefix string
+	// Inside a pre element, where whitespace is kept
+	pre bool
+}
+
+// block starts a new paragraph.
+func (c *markdownConverter) block() {
+	c.WriteString("\n" + c.prefix + "\n" + c.prefix)
+}
+
+// newline starts a new line inside the current block.
+func (c *markdownConverter) newline() {
+	c.WriteString("\n" + c.prefix)
+}
+
+func (c *markdownConverter) children(n *html.Node) {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		c.node(child)
+	}
+}
+
+func (c *markdownConverter) node(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		if c.pre {
+			c.WriteString(strings.ReplaceAll(n.Data, "\n", "\n"+c.prefix))
+			return
+		}
+		text := rxMarkdownSpaces.ReplaceAllString(n.Data, " ")
+		if c.Len() == 0 || strings.HasSuffix(c.String(), "\n"+c.prefix) {
+			text = strings.TrimLeft(text, " ")
+		}
+		c.WriteString(text)
+		return
+	case html.DocumentNode:
+		c.children(n)
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.Script, atom.Style, atom.Noscript, atom.Head:
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		level := int(n.Data[1] - '0')
+		c.block()
+		c.WriteString(strings.Repeat("#", level) + " ")
+		c.WriteString(c.inline(n))
+		c.block()
+	case atom.P, atom.Div, atom.Section, atom.Article, atom.Figure, atom.Table:
+		c.block()
+		c.children(n)
+		c.block()
+	case atom.Tr:
+		c.newline()
+		c.children(n)
+	case atom.Td, atom.Th:
+		c.children(n)
+		c.WriteString(" ")
+	case atom.Br:
+		c.WriteString("\\")
+		c.newline()
+	case atom.Hr:
+		c.block()
+		c.WriteString("---")
+		c.block()
+	case atom.Strong, atom.B:
+		c.wrap(n, "**")
+	case atom.Em, atom.I:
+		c.wrap(n, "_")
+	case atom.Code:
+		if c.pre {
+			c.children(n)
+			return
+		}
+		c.wrap(n, "`")
+	case atom.Pre:
+		c.block()
+		c.WriteString("```")
+		c.newline()
+		c.pre = true
+		c.children(n)
+		c.pre = false
+		c.newline()
+		c.WriteString("```")
+		c.block()
+	case atom.A:
+		text := c.inline(n)
+		href := markdownAttr(n, "href")
+		if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(href, "javascript:") {
+			c.WriteString(text)
+			return
+		}
+		if text == "" {
+			text = href
+		}
+		c.WriteString("[" + text + "](" + href + ")")
+	case atom.Img:
+		if src := markdownAttr(n, "src"); src != "" {
+			c.WriteString("![" + markdownAttr(n, "alt") + "](" + src + ")")
+		}
+	case atom.Blockquote:
+		prefix := c.prefix
+		c.prefix += "> "
+		c.block()
+		c.children(n)
+		c.prefix = prefix
+		c.block()
+	case atom.Ul, atom.Ol:
+		c.block()
+		index := 1
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode || child.DataAtom != atom.Li {
+				continue
+			}
+
+			marker := "- "
+			if n.DataAtom == atom.Ol {
+				marker = fmt.Sprintf("%d. ", index)
+				index++
+			}
+
+			c.WriteString(marker)
+			prefix := c.prefix
+			c.prefix += strings.Repeat(" ", len(marker))
+			c.WriteString(strings.TrimSpace(c.inline(child)))
+			c.prefix = prefix
+			c.newline()
+		}
+		c.block()
+	default:
+		c.children(n)
+	}
+}
+
+// inline converts the children of a node on their own, so the result can be trimmed.
+func (c *markdownConverter) inline(n *html.Node) string {
+	conv := markdownConverter{prefix: c.prefix, pre: c.pre}
+	conv.children(n)
+
+	return cleanMarkdown(conv.String())
+}
+
+func (c *markdownConverter) wrap(n *html.Node, marker string) {
+	if text := c.inline(n); text != "" {
+		c.WriteString(marker + text + marker)
+	}
+}
+
+func markdownAttr(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return strings.TrimSpace(attr.Val)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,60 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/core"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTMLToMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{
+			name:     "paragraphs and inline elements",
+			html:     `<p>Hello <strong>bold</strong>, <em>italic</em> and <code>code</code>.</p><p>Second<br>line</p>`,
+			expected: "Hello **bold**, _italic_ and `code`.\n\nSecond\\\nline\n",
+		},
+		{
+			name:     "headings",
+			html:     `<h1>Title</h1><h3>Section</h3><p>Text</p>`,
+			expected: "# Title\n\n### Section\n\nText\n",
+		},
+		{
+			name:     "links and images",
+			html:     `<p><a href="https://example.com">Example</a> <a href="#top">top</a> <img src="image.png" alt="An image"></p>`,
+			expected: "[Example](https://example.com) top ![An image](image.png)\n",
+		},
+		{
+			name:     "lists",
+			html:     `<ul><li>one</li><li>two</li></ul><ol><li>first</li><li>second</li></ol>`,
+			expected: "- one\n- two\n\n1. first\n2. second\n",
+		},
+		{
+			name:     "quotes",
+			html:     `<blockquote><p>one</p><p>two</p></blockquote><p>after</p>`,
+			expected: "> one\n>\n> two\n\nafter\n",
+		},
+		{
+			name:     "code blocks keep whitespace",
+			html:     "<pre><code>func main() {\n\treturn\n}</code></pre>",
+			expected: "```\nfunc main() {\n\treturn\n}\n```\n",
+		},
+		{
+			name:     "scripts are dropped",
+			html:     `<div><script>alert(1)</script><p>Text</p></div>`,
+			expected: "Text\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := core.HTMLToMarkdown(tt.html)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, result)
+		})
+	}
+}
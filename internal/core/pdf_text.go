@@ -0,0 +1,44 @@
+package core
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/ledongthuc/pdf"
+)
+
+// ExtractPDFText returns the text of the pages of a PDF, decoded with the encodings and
+// Unicode maps of their fonts. Scanned pages have no text to extract and encrypted documents
+// aren't read. The pages after the first model.ArchiveTextMaxLength bytes of text are not read.
+func ExtractPDFText(data []byte) (extracted string) {
+	text := strings.Builder{}
+
+	// The reader panics on some malformed documents, the text of their first pages is kept
+	defer func() {
+		if recover() != nil {
+			extracted = text.String()
+		}
+	}()
+
+	reader, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return ""
+	}
+
+	for i := 1; i <= reader.NumPage() && text.Len() < model.ArchiveTextMaxLength; i++ {
+		page := reader.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+
+		content, err := page.GetPlainText(nil)
+		if err != nil {
+			continue
+		}
+		text.WriteString(content)
+		text.WriteByte(' ')
+	}
+
+	return text.String()
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"image"
 	"image/color"
@@ -180,12 +181,30 @@ func ProcessBookmark(deps model.Dependencies, req ProcessRequest) (book model.Bo
 			return book, false, fmt.Errorf("failed to create archive: %v", err)
 		}
 
+		// Keep the previous archive as a snapshot instead of overwriting it
+		ctx := context.Background()
+		err = deps.Domains().Archiver().PreserveArchive(ctx, &book)
+		if err != nil {
+			return book, false, fmt.Errorf("failed to preserve previous archive: %v", err)
+		}
+
 		dstPath := model.GetArchivePath(&book)
 		err = deps.Domains().Storage().WriteFile(dstPath, tmpFile)
 		if err != nil {
 			return book, false, fmt.Errorf("failed move archive to destination `: %v", err)
 		}
 
+		err = deps.Domains().Archiver().RecordArchive(ctx, &book)
+		if err != nil {
+			return book, false, fmt.Errorf("failed to record archive snapshot: %v", err)
+		}
+
+		// The archive is kept even if its text can't be indexed for search
+		err = deps.Domains().Archiver().IndexArchive(ctx, &book)
+		if err != nil {
+			log.Printf("failed to index archive text of bookmark %d: %v", book.ID, err)
+		}
+
 		book.HasArchive = true
 		book.ModifiedAt = ""
 	}
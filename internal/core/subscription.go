@@ -0,0 +1,330 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+const (
+	// maxFeedSize is the largest feed document read, bigger ones are rejected
+	maxFeedSize = 10 * 1024 * 1024
+	// maxFeedExcerptLength is the length excerpts are cut to, as some feeds have the whole
+	// content of their items as description
+	maxFeedExcerptLength = 500
+)
+
+// FetchFeed downloads and parses the RSS, Atom or JSON feed at the URL.
+func FetchFeed(ctx context.Context, feedURL string, timeout time.Duration) (*model.ParsedFeed, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/feed+json, application/xml;q=0.9, application/json;q=0.9, */*;q=0.8")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feed: %w", err)
+	}
+	if len(data) > maxFeedSize {
+		return nil, fmt.Errorf("feed is larger than %d bytes", maxFeedSize)
+	}
+
+	// Relative links are resolved against the final URL, after any redirect
+	return ParseFeed(data, resp.Request.URL.String())
+}
+
+// ParseFeed parses a RSS 2.0, RSS 1.0, Atom or JSON feed. Relative item links are resolved
+// against the feed URL, and items without a link are left out.
+func ParseFeed(data []byte, feedURL string) (*model.ParsedFeed, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("feed is empty")
+	}
+
+	var feed *model.ParsedFeed
+	var err error
+	if trimmed[0] == '{' {
+		feed, err = parseJSONFeed(trimmed)
+	} else {
+		feed, err = parseXMLFeed(trimmed)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	base, _ := url.Parse(feedURL)
+	items := make([]model.ParsedFeedItem, 0, len(feed.Items))
+	for _, item := range feed.Items {
+		item.URL = resolveFeedLink(base, strings.TrimSpace(item.URL))
+		if item.URL == "" {
+			continue
+		}
+
+		item.GUID = strings.TrimSpace(item.GUID)
+		if item.GUID == "" {
+			item.GUID = item.URL
+		}
+
+		item.Title = strings.TrimSpace(feedText(item.Title))
+		item.Excerpt = strings.TrimSpace(feedText(item.Excerpt))
+		if excerpt := []rune(item.Excerpt); len(excerpt) > maxFeedExcerptLength {
+			item.Excerpt = strings.TrimSpace(string(excerpt[:maxFeedExcerptLength])) + "…"
+		}
+		items = append(items, item)
+	}
+
+	feed.Title = strings.TrimSpace(feedText(feed.Title))
+	feed.Items = items
+	return feed, nil
+}
+
+// resolveFeedLink returns the absolute http(s) URL of a link, empty if it isn't one
+func resolveFeedLink(base *url.URL, link string) string {
+	parsed, err := url.Parse(link)
+	if err != nil || link == "" {
+		return ""
+	}
+
+	if base != nil {
+		parsed = base.ResolveReference(parsed)
+	}
+
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return ""
+	}
+
+	return parsed.String()
+}
+
+// feedText returns the text of a feed field, which may hold HTML markup
+func feedText(value string) string {
+	if !strings.ContainsAny(value, "<&") {
+		return strings.Join(strings.Fields(value), " ")
+	}
+
+	doc, err := html.Parse(strings.NewReader(value))
+	if err != nil {
+		return strings.Join(strings.Fields(value), " ")
+	}
+
+	var text strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			text.WriteString(n.Data)
+			text.WriteString(" ")
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+
+	return strings.Join(strings.Fields(text.String()), " ")
+}
+
+type xmlFeedLink struct {
+	Rel  string `xml:"rel,attr"`
+	Href string `xml:"href,attr"`
+	// RSS links have the URL as their text
+	Value string `xml:",chardata"`
+}
+
+type xmlFeedItem struct {
+	// RSS 2.0 and RSS 1.0
+	Title       string        `xml:"title"`
+	Links       []xmlFeedLink `xml:"link"`
+	GUID        string        `xml:"guid"`
+	About       string        `xml:"about,attr"`
+	Description string        `xml:"description"`
+	// Atom
+	ID      string `xml:"id"`
+	Summary string `xml:"summary"`
+	Content string `xml:"content"`
+}
+
+type xmlFeed struct {
+	XMLName xml.Name
+	// Atom title, entries and RSS 1.0 items are children of the root element
+	Title   string        `xml:"title"`
+	Entries []xmlFeedItem `xml:"entry"`
+	Items   []xmlFeedItem `xml:"item"`
+	Channel struct {
+		Title string        `xml:"title"`
+		Items []xmlFeedItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+func parseXMLFeed(data []byte) (*model.ParsedFeed, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.CharsetReader = charset.NewReaderLabel
+	// Feeds in the wild often use HTML entities and sloppy markup
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+
+	var doc xmlFeed
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid feed: %w", err)
+	}
+
+	feed := model.ParsedFeed{}
+	switch strings.ToLower(doc.XMLName.Local) {
+	case "rss":
+		feed.Title = doc.Channel.Title
+		for _, item := range doc.Channel.Items {
+			feed.Items = append(feed.Items, model.ParsedFeedItem{
+				GUID:    item.GUID,
+				URL:     rssItemLink(item),
+				Title:   item.Title,
+				Excerpt: item.Description,
+			})
+		}
+	case "rdf":
+		feed.Title = doc.Channel.Title
+		for _, item := range doc.Items {
+			feed.Items = append(feed.Items, model.ParsedFeedItem{
+				GUID:    item.About,
+				URL:     rssItemLink(item),
+				Title:   item.Title,
+				Excerpt: item.Description,
+			})
+		}
+	case "feed":
+		feed.Title = doc.Title
+		for _, entry := range doc.Entries {
+			excerpt := entry.Summary
+			if excerpt == "" {
+				excerpt = entry.Content
+			}
+
+			feed.Items = append(feed.Items, model.ParsedFeedItem{
+				GUID:    entry.ID,
+				URL:     atomEntryLink(entry),
+				Title:   entry.Title,
+				Excerpt: excerpt,
+			})
+		}
+	default:
+		return nil, fmt.Errorf("unsupported feed format: %s", doc.XMLName.Local)
+	}
+
+	return &feed, nil
+}
+
+// rssItemLink returns the link of a RSS item, or its GUID when it is a permalink
+func rssItemLink(item xmlFeedItem) string {
+	for _, link := range item.Links {
+		// Atom links are sometimes mixed into RSS items
+		if value := strings.TrimSpace(link.Value); value != "" {
+			return value
+		}
+		if link.Href != "" && (link.Rel == "" || link.Rel == "alternate") {
+			return link.Href
+		}
+	}
+
+	if strings.HasPrefix(item.GUID, "http://") || strings.HasPrefix(item.GUID, "https://") {
+		return item.GUID
+	}
+
+	return ""
+}
+
+// atomEntryLink returns the alternate link of an Atom entry
+func atomEntryLink(entry xmlFeedItem) string {
+	for _, link := range entry.Links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return link.Href
+		}
+	}
+	return ""
+}
+
+type jsonFeedItem struct {
+	ID          json.RawMessage `json:"id"`
+	URL         string          `json:"url"`
+	ExternalURL string          `json:"external_url"`
+	Title       string          `json:"title"`
+	Summary     string          `json:"summary"`
+	ContentText string          `json:"content_text"`
+	ContentHTML string          `json:"content_html"`
+}
+
+type jsonFeed struct {
+	Version string         `json:"version"`
+	Title   string         `json:"title"`
+	Items   []jsonFeedItem `json:"items"`
+}
+
+func parseJSONFeed(data []byte) (*model.ParsedFeed, error) {
+	var doc jsonFeed
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid feed: %w", err)
+	}
+
+	if !strings.HasPrefix(doc.Version, "https://jsonfeed.org/version/") {
+		return nil, fmt.Errorf("unsupported feed format: JSON without a JSON Feed version")
+	}
+
+	feed := model.ParsedFeed{Title: doc.Title}
+	for _, item := range doc.Items {
+		link := item.URL
+		if link == "" {
+			link = item.ExternalURL
+		}
+
+		excerpt := item.Summary
+		if excerpt == "" {
+			excerpt = item.ContentText
+		}
+		if excerpt == "" {
+			excerpt = item.ContentHTML
+		}
+
+		feed.Items = append(feed.Items, model.ParsedFeedItem{
+			GUID:    jsonFeedItemID(item.ID),
+			URL:     link,
+			Title:   item.Title,
+			Excerpt: excerpt,
+		})
+	}
+
+	return &feed, nil
+}
+
+// jsonFeedItemID returns the ID of a JSON feed item, which should be a string but is a number
+// in some feeds
+func jsonFeedItemID(raw json.RawMessage) string {
+	var id string
+	if err := json.Unmarshal(raw, &id); err == nil {
+		return id
+	}
+	return strings.Trim(string(raw), `"`)
+}
@@ -0,0 +1,150 @@
+package core_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/core"
+	"github.com/stretchr/testify/require"
+)
+
+const testRSSFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
+  <channel>
+    <title>Example blog</title>
+    <atom:link href="https://blog.example.com/feed.xml" rel="self" type="application/rss+xml"/>
+    <item>
+      <title>First post</title>
+      <link>https://blog.example.com/first</link>
+      <guid isPermaLink="false">post-1</guid>
+      <description>&lt;p&gt;Hello &lt;b&gt;world&lt;/b&gt;&lt;/p&gt;</description>
+    </item>
+    <item>
+      <title>Relative post</title>
+      <link>/second</link>
+    </item>
+    <item>
+      <title>Permalink post</title>
+      <guid>https://blog.example.com/third</guid>
+    </item>
+    <item>
+      <title>No link</title>
+    </item>
+  </channel>
+</rss>`
+
+const testAtomFeed = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Example journal</title>
+  <entry>
+    <title type="html">Atom &amp;amp; entries</title>
+    <id>urn:uuid:1225c695</id>
+    <link rel="self" href="https://journal.example.com/entries/1.atom"/>
+    <link rel="alternate" href="https://journal.example.com/entries/1"/>
+    <summary>Short summary</summary>
+    <content type="html">Full content</content>
+  </entry>
+</feed>`
+
+const testRDFFeed = `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/">
+  <channel rdf:about="https://old.example.com/">
+    <title>Old school</title>
+  </channel>
+  <item rdf:about="https://old.example.com/item">
+    <title>RDF item</title>
+    <link>https://old.example.com/item</link>
+  </item>
+</rdf:RDF>`
+
+const testJSONFeed = `{
+  "version": "https://jsonfeed.org/version/1.1",
+  "title": "JSON blog",
+  "items": [
+    {"id": "1", "url": "https://json.example.com/1", "title": "JSON item", "content_text": "Text content"},
+    {"id": 2, "external_url": "https://elsewhere.example.com/", "title": "Linked item"}
+  ]
+}`
+
+func TestParseFeed(t *testing.T) {
+	t.Run("rss", func(t *testing.T) {
+		feed, err := core.ParseFeed([]byte(testRSSFeed), "https://blog.example.com/feed.xml")
+		require.NoError(t, err)
+		require.Equal(t, "Example blog", feed.Title)
+		require.Len(t, feed.Items, 3)
+
+		require.Equal(t, "post-1", feed.Items[0].GUID)
+		require.Equal(t, "https://blog.example.com/first", feed.Items[0].URL)
+		require.Equal(t, "Hello world", feed.Items[0].Excerpt)
+
+		// Items without GUID use their URL, resolved against the feed URL
+		require.Equal(t, "https://blog.example.com/second", feed.Items[1].URL)
+		require.Equal(t, "https://blog.example.com/second", feed.Items[1].GUID)
+
+		require.Equal(t, "https://blog.example.com/third", feed.Items[2].URL)
+	})
+
+	t.Run("atom", func(t *testing.T) {
+		feed, err := core.ParseFeed([]byte(testAtomFeed), "https://journal.example.com/feed.atom")
+		require.NoError(t, err)
+		require.Equal(t, "Example journal", feed.Title)
+		require.Len(t, feed.Items, 1)
+		require.Equal(t, "urn:uuid:1225c695", feed.Items[0].GUID)
+		require.Equal(t, "https://journal.example.com/entries/1", feed.Items[0].URL)
+		require.Equal(t, "Atom & entries", feed.Items[0].Title)
+		require.Equal(t, "Short summary", feed.Items[0].Excerpt)
+	})
+
+	t.Run("rss 1.0", func(t *testing.T) {
+		feed, err := core.ParseFeed([]byte(testRDFFeed), "https://old.example.com/index.rdf")
+		require.NoError(t, err)
+		require.Equal(t, "Old school", feed.Title)
+		require.Len(t, feed.Items, 1)
+		require.Equal(t, "https://old.example.com/item", feed.Items[0].GUID)
+	})
+
+	t.Run("json feed", func(t *testing.T) {
+		feed, err := core.ParseFeed([]byte(testJSONFeed), "https://json.example.com/feed.json")
+		require.NoError(t, err)
+		require.Equal(t, "JSON blog", feed.Title)
+		require.Len(t, feed.Items, 2)
+		require.Equal(t, "1", feed.Items[0].GUID)
+		require.Equal(t, "Text content", feed.Items[0].Excerpt)
+		require.Equal(t, "2", feed.Items[1].GUID)
+		require.Equal(t, "https://elsewhere.example.com/", feed.Items[1].URL)
+	})
+
+	t.Run("not a feed", func(t *testing.T) {
+		_, err := core.ParseFeed([]byte(`<html><body>Hello</body></html>`), "https://example.com")
+		require.Error(t, err)
+
+		_, err = core.ParseFeed([]byte(`{"hello": "world"}`), "https://example.com")
+		require.Error(t, err)
+
+		_, err = core.ParseFeed([]byte(" "), "https://example.com")
+		require.Error(t, err)
+	})
+}
+
+func TestFetchFeed(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/feed.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(testRSSFeed))
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	feed, err := core.FetchFeed(context.Background(), server.URL+"/feed.xml", time.Second)
+	require.NoError(t, err)
+	require.Len(t, feed.Items, 3)
+
+	_, err = core.FetchFeed(context.Background(), server.URL+"/missing", time.Second)
+	require.ErrorContains(t, err, "404")
+}
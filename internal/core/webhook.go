@@ -0,0 +1,88 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/model"
+)
+
+// maxWebhookResponseSize is the part of the webhook answers kept in the delivery log
+const maxWebhookResponseSize = 1024
+
+// WebhookRequest is a delivery to send to a webhook
+type WebhookRequest struct {
+	URL        string
+	Secret     string
+	Event      model.WebhookEvent
+	DeliveryID model.DBID
+	Payload    []byte
+}
+
+// WebhookResponse is the answer of a webhook, its body truncated
+type WebhookResponse struct {
+	StatusCode int
+	Body       string
+}
+
+// SignWebhookPayload returns the signature sent in WebhookSignatureHeader, of the timestamp
+// sent in WebhookTimestampHeader followed by a dot and the webhook body.
+func SignWebhookPayload(secret string, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SendWebhook posts a delivery to its webhook. Answers with an error status are returned
+// along with an error, so they can be logged and retried.
+func SendWebhook(ctx context.Context, request WebhookRequest, timeout time.Duration) (*WebhookResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, request.URL, bytes.NewReader(request.Payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("X-Shiori-Event", string(request.Event))
+	req.Header.Set("X-Shiori-Delivery", strconv.Itoa(int(request.DeliveryID)))
+	// Each attempt is signed with its own time, retries are not rejected as replays
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set(model.WebhookTimestampHeader, timestamp)
+	req.Header.Set(model.WebhookSignatureHeader, SignWebhookPayload(request.Secret, timestamp, request.Payload))
+
+	// Redirects would turn the POST into a GET, they are reported instead
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxWebhookResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	response := &WebhookResponse{StatusCode: resp.StatusCode, Body: string(bytes.ToValidUTF8(body, nil))}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return response, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return response, nil
+}
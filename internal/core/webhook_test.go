@@ -0,0 +1,88 @@
+package core_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/core"
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignWebhookPayload(t *testing.T) {
+	payload := []byte(`{"event":"bookmark.created"}`)
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("1700000000."))
+	mac.Write(payload)
+	require.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), core.SignWebhookPayload("secret", "1700000000", payload))
+	require.NotEqual(t, core.SignWebhookPayload("secret", "1700000000", payload), core.SignWebhookPayload("other", "1700000000", payload))
+	require.NotEqual(t, core.SignWebhookPayload("secret", "1700000000", payload), core.SignWebhookPayload("secret", "1700000001", payload))
+}
+
+func TestSendWebhook(t *testing.T) {
+	ctx := context.Background()
+	payload := []byte(`{"event":"bookmark.created"}`)
+
+	t.Run("signed request", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			require.Equal(t, http.MethodPost, r.Method)
+			require.Equal(t, payload, body)
+			require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			require.Equal(t, "bookmark.created", r.Header.Get("X-Shiori-Event"))
+			require.Equal(t, "7", r.Header.Get("X-Shiori-Delivery"))
+			timestamp := r.Header.Get(model.WebhookTimestampHeader)
+			sentAt, err := strconv.ParseInt(timestamp, 10, 64)
+			require.NoError(t, err)
+			require.WithinDuration(t, time.Now(), time.Unix(sentAt, 0), time.Minute)
+			require.Equal(t, core.SignWebhookPayload("secret", timestamp, payload), r.Header.Get(model.WebhookSignatureHeader))
+			w.Write([]byte("ok"))
+		}))
+		defer server.Close()
+
+		response, err := core.SendWebhook(ctx, core.WebhookRequest{
+			URL:        server.URL,
+			Secret:     "secret",
+			Event:      model.WebhookEventBookmarkCreated,
+			DeliveryID: 7,
+			Payload:    payload,
+		}, time.Second)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		require.Equal(t, "ok", response.Body)
+	})
+
+	t.Run("error status truncates the body", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte(strings.Repeat("a", 4096)))
+		}))
+		defer server.Close()
+
+		response, err := core.SendWebhook(ctx, core.WebhookRequest{URL: server.URL, Payload: payload}, time.Second)
+		require.Error(t, err)
+		require.Equal(t, http.StatusBadGateway, response.StatusCode)
+		require.Len(t, response.Body, 1024)
+	})
+
+	t.Run("redirects are not followed", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, "/elsewhere", http.StatusFound)
+		}))
+		defer server.Close()
+
+		response, err := core.SendWebhook(ctx, core.WebhookRequest{URL: server.URL, Payload: payload}, time.Second)
+		require.Error(t, err)
+		require.Equal(t, http.StatusFound, response.StatusCode)
+	})
+}
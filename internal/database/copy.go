@@ -0,0 +1,562 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// copyTables are the tables copied by Copy, in the order they are written.
+var copyTables = []string{"account", "account_identity", "api_token", "account_totp", "feed_token", "tag", "tag_alias", "collection", "bookmark", "bookmark_tag", "archive_snapshot", "link_check", "subscription", "subscription_item", "webhook", "bookmark_rule", "saved_search", "bookmark_highlight"}
+
+// CopyOptions is options for copying a database into another one.
+type CopyOptions struct {
+	// Number of rows written per transaction, defaults to 100
+	BatchSize int
+	// Progress is called after every batch with the rows copied so far from a table
+	Progress func(table string, copied int, total int)
+}
+
+// CopyCount is the number of rows of a table in both databases once copied.
+type CopyCount struct {
+	Table  string
+	Source int
+	Target int
+}
+
+// Copy copies the accounts with their linked identities, API tokens, second factors, feed subscriptions,
+// webhooks, bookmark rules and saved searches, the tags with their aliases, the collections and the bookmarks with their content, tags, archive
+// snapshots, link checks and highlights from src into dst, which must be migrated and empty. The rows keep their IDs so the
+// files in the storage directory still match them. The text indexed from the archives isn't
+// copied, it is extracted again from the files. The row counts of both databases are
+// returned so the copy can be verified.
+func Copy(ctx context.Context, src, dst model.DB, opts CopyOptions) ([]CopyCount, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.Progress == nil {
+		opts.Progress = func(string, int, int) {}
+	}
+
+	for _, table := range []string{"account", "tag", "bookmark"} {
+		count, err := countRows(ctx, dst, table)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, fmt.Errorf("target database is not empty, table %s has %d rows", table, count)
+		}
+	}
+
+	if err := copyAccounts(ctx, src, dst, opts); err != nil {
+		return nil, err
+	}
+
+	if err := copyTags(ctx, src, dst, opts); err != nil {
+		return nil, err
+	}
+
+	if err := copyCollections(ctx, src, dst); err != nil {
+		return nil, err
+	}
+
+	if err := copyBookmarks(ctx, src, dst, opts); err != nil {
+		return nil, err
+	}
+
+	if err := copySubscriptions(ctx, src, dst); err != nil {
+		return nil, err
+	}
+
+	if err := copyWebhooks(ctx, src, dst); err != nil {
+		return nil, err
+	}
+
+	if err := copyBookmarkRules(ctx, src, dst); err != nil {
+		return nil, err
+	}
+
+	if err := copySavedSearches(ctx, src, dst); err != nil {
+		return nil, err
+	}
+
+	if err := copyHighlights(ctx, src, dst); err != nil {
+		return nil, err
+	}
+
+	// Postgres sequences don't move when IDs are given, new rows would reuse the copied IDs
+	if _, isPG := dst.(*PGDatabase); isPG {
+		for _, table := range []string{"account", "account_identity", "api_token", "tag", "tag_alias", "collection", "bookmark", "archive_snapshot", "link_check", "subscription", "webhook", "bookmark_rule", "saved_search", "bookmark_highlight"} {
+			query := fmt.Sprintf(`SELECT setval(pg_get_serial_sequence('%s', 'id'), MAX(id)) FROM %s HAVING MAX(id) IS NOT NULL`, table, table)
+			if _, err := dst.WriterDB().ExecContext(ctx, query); err != nil {
+				return nil, fmt.Errorf("failed to update %s sequence: %w", table, err)
+			}
+		}
+	}
+
+	counts := make([]CopyCount, 0, len(copyTables))
+	for _, table := range copyTables {
+		count := CopyCount{Table: table}
+
+		var err error
+		if count.Source, err = countRows(ctx, src, table); err != nil {
+			return nil, err
+		}
+		if count.Target, err = countRows(ctx, dst, table); err != nil {
+			return nil, err
+		}
+
+		counts = append(counts, count)
+	}
+
+	return counts, nil
+}
+
+func countRows(ctx context.Context, db model.DB, table string) (int, error) {
+	var count int
+	if err := db.ReaderDB().GetContext(ctx, &count, "SELECT COUNT(*) FROM "+table); err != nil {
+		return 0, fmt.Errorf("failed to count %s rows: %w", table, err)
+	}
+	return count, nil
+}
+
+// copyDate formats the dates read from any database the way they are stored by shiori.
+func copyDate(date string) string {
+	for _, layout := range []string{model.DatabaseDateFormat, time.RFC3339Nano} {
+		if parsed, err := time.Parse(layout, date); err == nil {
+			return parsed.UTC().Format(model.DatabaseDateFormat)
+		}
+	}
+	return date
+}
+
+func copyNullDate(date *string) *string {
+	if date == nil {
+		return nil
+	}
+	return model.Ptr(copyDate(*date))
+}
+
+// copyBatch runs fn in a transaction of dst.
+func copyBatch(ctx context.Context, dst model.DB, fn func(tx *sqlx.Tx) error) error {
+	tx, err := dst.WriterDB().BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func copyAccounts(ctx context.Context, src, dst model.DB, opts CopyOptions) error {
+	accounts, err := src.ListAccounts(ctx, model.DBListAccountsOptions{WithPassword: true})
+	if err != nil {
+		return fmt.Errorf("failed to read accounts: %w", err)
+	}
+
+	for start := 0; start < len(accounts); start += opts.BatchSize {
+		batch := accounts[start:min(start+opts.BatchSize, len(accounts))]
+		if err := copyBatch(ctx, dst, func(tx *sqlx.Tx) error {
+			query := tx.Rebind(`INSERT INTO account (id, username, password, owner, config) VALUES (?, ?, ?, ?, ?)`)
+			for _, account := range batch {
+				if _, err := tx.ExecContext(ctx, query,
+					account.ID, account.Username, account.Password, account.Owner, account.Config); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to write accounts: %w", err)
+		}
+
+		opts.Progress("account", start+len(batch), len(accounts))
+	}
+
+	identities, err := src.ListAccountIdentities(ctx, 0)
+	if err != nil {
+		return fmt.Errorf("failed to read account identities: %w", err)
+	}
+
+	if err := copyBatch(ctx, dst, func(tx *sqlx.Tx) error {
+		query := tx.Rebind(`INSERT INTO account_identity (id, account_id, issuer, subject, created_at) VALUES (?, ?, ?, ?, ?)`)
+		for _, identity := range identities {
+			if _, err := tx.ExecContext(ctx, query,
+				identity.ID, identity.AccountID, identity.Issuer, identity.Subject, copyDate(identity.CreatedAt)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to write account identities: %w", err)
+	}
+
+	for _, account := range accounts {
+		tokens, err := src.ListAPITokens(ctx, account.ID)
+		if err != nil {
+			return fmt.Errorf("failed to read api tokens: %w", err)
+		}
+
+		if err := copyBatch(ctx, dst, func(tx *sqlx.Tx) error {
+			query := tx.Rebind(`INSERT INTO api_token
+				(id, account_id, name, token_hash, scopes, expires_at, last_used_at, created_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+			for _, token := range tokens {
+				if _, err := tx.ExecContext(ctx, query,
+					token.ID, token.AccountID, token.Name, token.TokenHash, token.Scopes,
+					copyNullDate(token.ExpiresAt), copyNullDate(token.LastUsedAt), copyDate(token.CreatedAt)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to write api tokens: %w", err)
+		}
+
+		totp, exists, err := src.GetAccountTOTP(ctx, account.ID)
+		if err != nil {
+			return fmt.Errorf("failed to read account totp: %w", err)
+		}
+
+		if exists {
+			totp.CreatedAt = copyDate(totp.CreatedAt)
+			if err := dst.SaveAccountTOTP(ctx, *totp); err != nil {
+				return fmt.Errorf("failed to write account totp: %w", err)
+			}
+		}
+
+		feedToken, exists, err := src.GetFeedToken(ctx, account.ID)
+		if err != nil {
+			return fmt.Errorf("failed to read feed token: %w", err)
+		}
+
+		if exists {
+			feedToken.CreatedAt = copyDate(feedToken.CreatedAt)
+			if err := dst.SaveFeedToken(ctx, *feedToken); err != nil {
+				return fmt.Errorf("failed to write feed token: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func copyTags(ctx context.Context, src, dst model.DB, opts CopyOptions) error {
+	tags, err := src.GetTags(ctx, model.DBListTagsOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to read tags: %w", err)
+	}
+
+	for start := 0; start < len(tags); start += opts.BatchSize {
+		batch := tags[start:min(start+opts.BatchSize, len(tags))]
+		if err := copyBatch(ctx, dst, func(tx *sqlx.Tx) error {
+			query := tx.Rebind(`INSERT INTO tag (id, name, parent_id) VALUES (?, ?, ?)`)
+			for _, tag := range batch {
+				if _, err := tx.ExecContext(ctx, query, tag.ID, tag.Name, tag.ParentID); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to write tags: %w", err)
+		}
+
+		opts.Progress("tag", start+len(batch), len(tags))
+	}
+
+	aliases, err := src.GetTagAliases(ctx, 0)
+	if err != nil {
+		return fmt.Errorf("failed to read tag aliases: %w", err)
+	}
+
+	if err := copyBatch(ctx, dst, func(tx *sqlx.Tx) error {
+		query := tx.Rebind(`INSERT INTO tag_alias (id, name, tag_id) VALUES (?, ?, ?)`)
+		for _, alias := range aliases {
+			if _, err := tx.ExecContext(ctx, query, alias.ID, alias.Name, alias.TagID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to write tag aliases: %w", err)
+	}
+
+	return nil
+}
+
+func copyCollections(ctx context.Context, src, dst model.DB) error {
+	collections, err := src.ListCollections(ctx, 0)
+	if err != nil {
+		return fmt.Errorf("failed to read collections: %w", err)
+	}
+
+	if err := copyBatch(ctx, dst, func(tx *sqlx.Tx) error {
+		query := tx.Rebind(`INSERT INTO collection
+			(id, account_id, parent_id, name, position, created_at, modified_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`)
+		for _, collection := range collections {
+			if _, err := tx.ExecContext(ctx, query,
+				collection.ID, collection.AccountID, collection.ParentID, collection.Name, collection.Position,
+				copyDate(collection.CreatedAt), copyDate(collection.ModifiedAt)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to write collections: %w", err)
+	}
+
+	return nil
+}
+
+// copyBookmarks streams the bookmarks in batches. Their rows are inserted with their ID, then
+// saved again through dst so each database stores the content in its own full text index.
+func copyBookmarks(ctx context.Context, src, dst model.DB, opts CopyOptions) error {
+	total, err := src.GetBookmarksCount(ctx, model.DBGetBookmarksOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to count bookmarks: %w", err)
+	}
+
+	copied := 0
+	for {
+		bookmarks, err := src.GetBookmarks(ctx, model.DBGetBookmarksOptions{
+			WithContent: true,
+			OrderMethod: model.DefaultOrder,
+			Limit:       opts.BatchSize,
+			Offset:      copied,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to read bookmarks: %w", err)
+		}
+
+		if len(bookmarks) == 0 {
+			break
+		}
+
+		snapshots := []model.ArchiveSnapshot{}
+		checks := []model.LinkCheck{}
+		for _, book := range bookmarks {
+			bookSnapshots, err := src.GetArchiveSnapshots(ctx, book.ID)
+			if err != nil {
+				return fmt.Errorf("failed to read archive snapshots: %w", err)
+			}
+			snapshots = append(snapshots, bookSnapshots...)
+
+			bookChecks, err := src.GetLinkChecks(ctx, book.ID, 0)
+			if err != nil {
+				return fmt.Errorf("failed to read link checks: %w", err)
+			}
+			checks = append(checks, bookChecks...)
+		}
+
+		if err := copyBatch(ctx, dst, func(tx *sqlx.Tx) error {
+			insertBookmark := tx.Rebind(`INSERT INTO bookmark
+				(id, account_id, url, title, excerpt, author, public, created_at, modified_at, has_content, collection_id)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+			insertBookmarkTag := tx.Rebind(`INSERT INTO bookmark_tag (bookmark_id, tag_id) VALUES (?, ?)`)
+			insertSnapshot := tx.Rebind(`INSERT INTO archive_snapshot (id, bookmark_id, path, created_at) VALUES (?, ?, ?, ?)`)
+			insertCheck := tx.Rebind(`INSERT INTO link_check
+				(id, bookmark_id, url, status_code, final_url, latency_ms, error, checked_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+
+			for i, book := range bookmarks {
+				bookmarks[i].CreatedAt = copyDate(book.CreatedAt)
+				bookmarks[i].ModifiedAt = copyDate(book.ModifiedAt)
+				if bookmarks[i].ModifiedAt == "" {
+					bookmarks[i].ModifiedAt = bookmarks[i].CreatedAt
+				}
+
+				if _, err := tx.ExecContext(ctx, insertBookmark,
+					book.ID, book.AccountID, book.URL, book.Title, book.Excerpt, book.Author, book.Public,
+					bookmarks[i].CreatedAt, bookmarks[i].ModifiedAt, book.Content != "", book.CollectionID); err != nil {
+					return err
+				}
+
+				for _, tag := range book.Tags {
+					if _, err := tx.ExecContext(ctx, insertBookmarkTag, book.ID, tag.ID); err != nil {
+						return err
+					}
+				}
+			}
+
+			for _, snapshot := range snapshots {
+				if _, err := tx.ExecContext(ctx, insertSnapshot,
+					snapshot.ID, snapshot.BookmarkID, snapshot.Path, copyDate(snapshot.CreatedAt)); err != nil {
+					return err
+				}
+			}
+
+			for _, check := range checks {
+				if _, err := tx.ExecContext(ctx, insertCheck,
+					check.ID, check.BookmarkID, check.URL, check.StatusCode, check.FinalURL,
+					check.LatencyMs, check.Error, copyDate(check.CheckedAt)); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to write bookmarks: %w", err)
+		}
+
+		// The tags are already linked, saving them again would only look them up
+		for i := range bookmarks {
+			bookmarks[i].Tags = nil
+		}
+
+		if _, err := dst.SaveBookmarks(ctx, false, bookmarks...); err != nil {
+			return fmt.Errorf("failed to write bookmarks content: %w", err)
+		}
+
+		copied += len(bookmarks)
+		opts.Progress("bookmark", copied, total)
+	}
+
+	return nil
+}
+
+// copySubscriptions copies the feed subscriptions with the items already seen, once the
+// bookmarks created from them exist.
+func copySubscriptions(ctx context.Context, src, dst model.DB) error {
+	subscriptions, err := src.ListSubscriptions(ctx, 0)
+	if err != nil {
+		return fmt.Errorf("failed to read subscriptions: %w", err)
+	}
+
+	for _, subscription := range subscriptions {
+		items, err := src.GetSubscriptionItems(ctx, subscription.ID)
+		if err != nil {
+			return fmt.Errorf("failed to read subscription items: %w", err)
+		}
+
+		if err := copyBatch(ctx, dst, func(tx *sqlx.Tx) error {
+			query := tx.Rebind(`INSERT INTO subscription
+				(id, account_id, url, title, tags, create_archive, create_ebook, last_error, last_polled_at, created_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+			if _, err := tx.ExecContext(ctx, query,
+				subscription.ID, subscription.AccountID, subscription.URL, subscription.Title, subscription.Tags,
+				subscription.CreateArchive, subscription.CreateEbook, subscription.LastError,
+				copyNullDate(subscription.LastPolledAt), copyDate(subscription.CreatedAt)); err != nil {
+				return err
+			}
+
+			insertItem := tx.Rebind(`INSERT INTO subscription_item (subscription_id, guid, bookmark_id, created_at) VALUES (?, ?, ?, ?)`)
+			for _, item := range items {
+				if _, err := tx.ExecContext(ctx, insertItem,
+					item.SubscriptionID, item.GUID, item.BookmarkID, copyDate(item.CreatedAt)); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to write subscriptions: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// copyWebhooks copies the webhooks, their delivery log is left behind.
+func copyWebhooks(ctx context.Context, src, dst model.DB) error {
+	webhooks, err := src.ListWebhooks(ctx, model.DBListWebhooksOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to read webhooks: %w", err)
+	}
+
+	if err := copyBatch(ctx, dst, func(tx *sqlx.Tx) error {
+		query := tx.Rebind(`INSERT INTO webhook (id, account_id, url, events, secret, created_at) VALUES (?, ?, ?, ?, ?, ?)`)
+		for _, webhook := range webhooks {
+			if _, err := tx.ExecContext(ctx, query,
+				webhook.ID, webhook.AccountID, webhook.URL, webhook.Events, webhook.Secret, copyDate(webhook.CreatedAt)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to write webhooks: %w", err)
+	}
+
+	return nil
+}
+
+func copyBookmarkRules(ctx context.Context, src, dst model.DB) error {
+	rules, err := src.ListBookmarkRules(ctx, 0)
+	if err != nil {
+		return fmt.Errorf("failed to read bookmark rules: %w", err)
+	}
+
+	if err := copyBatch(ctx, dst, func(tx *sqlx.Tx) error {
+		query := tx.Rebind(`INSERT INTO bookmark_rule
+			(id, account_id, name, enabled, conditions, actions, created_at, modified_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+		for _, rule := range rules {
+			if _, err := tx.ExecContext(ctx, query,
+				rule.ID, rule.AccountID, rule.Name, rule.Enabled, rule.Conditions, rule.Actions,
+				copyDate(rule.CreatedAt), copyDate(rule.ModifiedAt)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to write bookmark rules: %w", err)
+	}
+
+	return nil
+}
+
+func copySavedSearches(ctx context.Context, src, dst model.DB) error {
+	searches, err := src.ListSavedSearches(ctx, 0)
+	if err != nil {
+		return fmt.Errorf("failed to read saved searches: %w", err)
+	}
+
+	if err := copyBatch(ctx, dst, func(tx *sqlx.Tx) error {
+		query := tx.Rebind(`INSERT INTO saved_search
+			(id, account_id, name, filter, created_at, modified_at)
+			VALUES (?, ?, ?, ?, ?, ?)`)
+		for _, search := range searches {
+			if _, err := tx.ExecContext(ctx, query,
+				search.ID, search.AccountID, search.Name, search.Filter,
+				copyDate(search.CreatedAt), copyDate(search.ModifiedAt)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to write saved searches: %w", err)
+	}
+
+	return nil
+}
+
+func copyHighlights(ctx context.Context, src, dst model.DB) error {
+	highlights, err := src.ListHighlights(ctx, model.ListHighlightsOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to read highlights: %w", err)
+	}
+
+	if err := copyBatch(ctx, dst, func(tx *sqlx.Tx) error {
+		query := tx.Rebind(`INSERT INTO bookmark_highlight
+			(id, bookmark_id, account_id, selector, note, created_at, modified_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`)
+		for _, highlight := range highlights {
+			if _, err := tx.ExecContext(ctx, query,
+				highlight.ID, highlight.BookmarkID, highlight.AccountID, highlight.Selector, highlight.Note,
+				copyDate(highlight.CreatedAt), copyDate(highlight.ModifiedAt)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to write highlights: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,235 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// testCopyDatabase copies a SQLite database into the database under test.
+func testCopyDatabase(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	src, err := sqliteTestDatabaseFactory(t, ctx)
+	require.NoError(t, err)
+
+	account, err := src.CreateAccount(ctx, model.Account{Username: "reader", Password: "hash", Owner: true})
+	require.NoError(t, err)
+
+	token, err := src.CreateAPIToken(ctx, model.APIToken{AccountID: account.ID, Name: "script", TokenHash: "hash", Scopes: model.APITokenScopes{model.APITokenScopeRead}})
+	require.NoError(t, err)
+
+	require.NoError(t, src.SaveAccountTOTP(ctx, model.AccountTOTP{AccountID: account.ID, Secret: "encrypted", Enabled: true}))
+	require.NoError(t, src.SaveFeedToken(ctx, model.FeedToken{AccountID: account.ID, TokenHash: "feed"}))
+
+	identity, err := src.CreateAccountIdentity(ctx, model.AccountIdentity{AccountID: account.ID, Issuer: "https://id.example.com", Subject: "user-1"})
+	require.NoError(t, err)
+
+	collection, err := src.CreateCollection(ctx, model.Collection{AccountID: account.ID, Name: "Projects"})
+	require.NoError(t, err)
+
+	// The first bookmark is deleted so the copied IDs don't start at one
+	bookmarks := []model.BookmarkDTO{}
+	for i, url := range []string{"https://example.com/deleted", "https://github.com/go-shiori/shiori", "https://github.com/go-shiori/obelisk"} {
+		bookmarks = append(bookmarks, model.BookmarkDTO{
+			AccountID: account.ID,
+			URL:       url,
+			Title:     "bookmark",
+			Content:   "content of the bookmark",
+			HTML:      "<p>content of the bookmark</p>",
+			CreatedAt: fmt.Sprintf("2019-01-0%d 10:00:00", i+1),
+			Tags:      []model.TagDTO{{Tag: model.Tag{Name: "go"}}},
+		})
+	}
+	bookmarks[2].Content = "obelisk archives web pages"
+	bookmarks[2].CollectionID = &collection.ID
+	saved, err := src.SaveBookmarks(ctx, true, bookmarks...)
+	require.NoError(t, err)
+	require.NoError(t, src.DeleteBookmarks(ctx, 0, saved[0].ID))
+
+	goTagID := saved[0].Tags[0].ID
+	unused, err := src.CreateTag(ctx, model.Tag{Name: "unused", ParentID: &goTagID})
+	require.NoError(t, err)
+	_, err = src.CreateTagAlias(ctx, model.TagAlias{Name: "golang", TagID: goTagID})
+	require.NoError(t, err)
+
+	snapshot, err := src.CreateArchiveSnapshot(ctx, model.ArchiveSnapshot{BookmarkID: saved[1].ID, Path: model.GetArchivePath(&saved[1])})
+	require.NoError(t, err)
+
+	check, err := src.CreateLinkCheck(ctx, model.LinkCheck{BookmarkID: saved[2].ID, URL: saved[2].URL, StatusCode: 404})
+	require.NoError(t, err)
+
+	subscription, err := src.CreateSubscription(ctx, model.Subscription{AccountID: account.ID, URL: "https://blog.example.com/feed.xml", Tags: model.SubscriptionTags{"blog"}})
+	require.NoError(t, err)
+	require.NoError(t, src.CreateSubscriptionItems(ctx, model.SubscriptionItem{SubscriptionID: subscription.ID, GUID: "post-1", BookmarkID: saved[1].ID}))
+
+	webhook, err := src.CreateWebhook(ctx, model.Webhook{AccountID: account.ID, URL: "https://chat.example.com/hook", Secret: "secret"})
+	require.NoError(t, err)
+
+	search, err := src.CreateSavedSearch(ctx, model.SavedSearch{AccountID: account.ID, Name: "Recent Go", Filter: model.SearchFilter{Tags: []string{"golang"}, AddedWithinDays: 30}})
+	require.NoError(t, err)
+
+	highlight, err := src.CreateHighlight(ctx, model.Highlight{
+		BookmarkID: saved[1].ID,
+		AccountID:  account.ID,
+		Selector:   model.HighlightSelector{Quote: model.TextQuoteSelector{Exact: "gopher"}, Position: &model.TextPositionSelector{Start: 4, End: 10}},
+		Note:       "mascot",
+	})
+	require.NoError(t, err)
+
+	rule, err := src.CreateBookmarkRule(ctx, model.BookmarkRule{
+		AccountID:  account.ID,
+		Name:       "Videos",
+		Conditions: model.RuleConditions{Host: "youtube.com"},
+		Actions:    model.RuleActions{AddTags: []string{"video"}},
+	})
+	require.NoError(t, err)
+
+	progress := map[string]int{}
+	counts, err := Copy(ctx, src, db, CopyOptions{
+		BatchSize: 1,
+		Progress: func(table string, copied int, total int) {
+			progress[table] = copied
+			require.LessOrEqual(t, copied, total)
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"account": 1, "tag": 2, "bookmark": 2}, progress)
+
+	for _, count := range counts {
+		require.Equal(t, count.Source, count.Target, "rows of %s", count.Table)
+	}
+
+	copiedAccount, exists, err := db.GetAccount(ctx, account.ID)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, "reader", copiedAccount.Username)
+
+	copiedToken, exists, err := db.GetAPIToken(ctx, "hash")
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, token.ID, copiedToken.ID)
+	require.Equal(t, account.ID, copiedToken.AccountID)
+
+	copiedTOTP, exists, err := db.GetAccountTOTP(ctx, account.ID)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, "encrypted", copiedTOTP.Secret)
+
+	copiedFeedToken, exists, err := db.GetFeedTokenByHash(ctx, "feed")
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, account.ID, copiedFeedToken.AccountID)
+
+	copiedIdentity, exists, err := db.GetAccountIdentity(ctx, "https://id.example.com", "user-1")
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, identity.ID, copiedIdentity.ID)
+	require.Equal(t, account.ID, copiedIdentity.AccountID)
+
+	copiedTag, exists, err := db.GetTagByName(ctx, "golang")
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, goTagID, copiedTag.ID)
+
+	copiedChild, exists, err := db.GetTag(ctx, unused.ID)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, &goTagID, copiedChild.ParentID)
+
+	copiedSubscription, exists, err := db.GetSubscription(ctx, subscription.ID)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, model.SubscriptionTags{"blog"}, copiedSubscription.Tags)
+
+	copiedItems, err := db.GetSubscriptionItems(ctx, subscription.ID)
+	require.NoError(t, err)
+	require.Len(t, copiedItems, 1)
+	require.Equal(t, saved[1].ID, copiedItems[0].BookmarkID)
+
+	copiedWebhook, exists, err := db.GetWebhook(ctx, webhook.ID)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, "secret", copiedWebhook.Secret)
+
+	copiedRule, exists, err := db.GetBookmarkRule(ctx, rule.ID)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, rule.Conditions, copiedRule.Conditions)
+	require.Equal(t, rule.Actions, copiedRule.Actions)
+
+	copiedSearch, exists, err := db.GetSavedSearch(ctx, search.ID)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, search.Filter, copiedSearch.Filter)
+
+	copiedHighlight, exists, err := db.GetHighlight(ctx, highlight.ID)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, highlight.Selector, copiedHighlight.Selector)
+	require.Equal(t, "mascot", copiedHighlight.Note)
+
+	copiedCollection, exists, err := db.GetCollection(ctx, collection.ID)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, "Projects", copiedCollection.Name)
+
+	copiedBookmark, _, err := db.GetBookmark(ctx, saved[2].ID, "", 0)
+	require.NoError(t, err)
+	require.Equal(t, &collection.ID, copiedBookmark.CollectionID)
+
+	for _, original := range saved[1:] {
+		book, exists, err := db.GetBookmark(ctx, original.ID, "", 0)
+		require.NoError(t, err)
+		require.True(t, exists)
+		require.Equal(t, original.URL, book.URL)
+		require.Equal(t, account.ID, book.AccountID)
+		require.Equal(t, original.CreatedAt, book.CreatedAt)
+		require.Len(t, book.Tags, 1)
+		require.Equal(t, "go", book.Tags[0].Name)
+	}
+
+	// The content is indexed by the target database
+	found, err := db.GetBookmarks(ctx, model.DBGetBookmarksOptions{Keyword: "obelisk", WithContent: true})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	require.Equal(t, saved[2].ID, found[0].ID)
+	require.Equal(t, "obelisk archives web pages", found[0].Content)
+	require.Equal(t, "<p>content of the bookmark</p>", found[0].HTML)
+
+	copiedSnapshot, exists, err := db.GetArchiveSnapshot(ctx, snapshot.ID)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, snapshot.Path, copiedSnapshot.Path)
+
+	checks, err := db.GetLinkChecks(ctx, saved[2].ID, 0)
+	require.NoError(t, err)
+	require.Len(t, checks, 1)
+	require.Equal(t, check.ID, checks[0].ID)
+
+	t.Run("new rows get new IDs", func(t *testing.T) {
+		result, err := db.SaveBookmarks(ctx, true, model.BookmarkDTO{URL: "https://example.com/new", Title: "new"})
+		require.NoError(t, err)
+		require.Greater(t, result[0].ID, saved[2].ID)
+
+		created, err := db.CreateAccount(ctx, model.Account{Username: "writer", Password: "hash"})
+		require.NoError(t, err)
+		require.Greater(t, created.ID, account.ID)
+	})
+}
+
+func testCopyDatabaseNotEmpty(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	src, err := sqliteTestDatabaseFactory(t, ctx)
+	require.NoError(t, err)
+
+	_, err = db.SaveBookmarks(ctx, true, model.BookmarkDTO{URL: "https://github.com/go-shiori/shiori", Title: "shiori"})
+	require.NoError(t, err)
+
+	_, err = Copy(ctx, src, db, CopyOptions{})
+	require.ErrorContains(t, err, "target database is not empty")
+}
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+var accountIdentityColumns = []string{"id", "account_id", "issuer", "subject", "created_at"}
+
+// GetAccountIdentity fetch the link of a user of an OpenID Connect provider.
+func (db *dbbase) GetAccountIdentity(ctx context.Context, issuer, subject string) (*model.AccountIdentity, bool, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select(accountIdentityColumns...)
+	sb.From("account_identity")
+	sb.Where(sb.Equal("issuer", issuer), sb.Equal("subject", subject))
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	identity := model.AccountIdentity{}
+	if err := db.ReaderDB().GetContext(ctx, &identity, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to get account identity: %w", err)
+	}
+
+	return &identity, true, nil
+}
+
+// ListAccountIdentities fetch the identities linked to an account, or to any account if zero.
+func (db *dbbase) ListAccountIdentities(ctx context.Context, accountID model.DBID) ([]model.AccountIdentity, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select(accountIdentityColumns...)
+	sb.From("account_identity")
+	if accountID > 0 {
+		sb.Where(sb.Equal("account_id", accountID))
+	}
+	sb.OrderBy("id ASC")
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	identities := []model.AccountIdentity{}
+	if err := db.ReaderDB().SelectContext(ctx, &identities, query, args...); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to list account identities: %w", err)
+	}
+
+	return identities, nil
+}
+
+// DeleteAccountIdentity removes an identity linked to an account, ErrNotFound if it doesn't exist.
+func (db *dbbase) DeleteAccountIdentity(ctx context.Context, accountID model.DBID, id model.DBID) error {
+	dlb := db.Flavor().NewDeleteBuilder()
+	dlb.DeleteFrom("account_identity")
+	dlb.Where(dlb.Equal("id", id), dlb.Equal("account_id", accountID))
+
+	query, args := dlb.Build()
+	query = db.WriterDB().Rebind(query)
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to delete account identity: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rows == 0 {
+			return ErrNotFound
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,75 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func testAccountIdentity(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	account, err := db.CreateAccount(ctx, model.Account{Username: "linked", Password: "hash"})
+	require.NoError(t, err)
+
+	_, exists, err := db.GetAccountIdentity(ctx, "https://idp.example.com", "user-1")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	identity, err := db.CreateAccountIdentity(ctx, model.AccountIdentity{
+		AccountID: account.ID,
+		Issuer:    "https://idp.example.com",
+		Subject:   "user-1",
+	})
+	require.NoError(t, err)
+	require.NotZero(t, identity.ID)
+
+	found, exists, err := db.GetAccountIdentity(ctx, "https://idp.example.com", "user-1")
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, account.ID, found.AccountID)
+	require.NotEmpty(t, found.CreatedAt)
+
+	// The subject is only unique for its issuer
+	_, exists, err = db.GetAccountIdentity(ctx, "https://other.example.com", "user-1")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	t.Run("subject linked twice", func(t *testing.T) {
+		_, err := db.CreateAccountIdentity(ctx, model.AccountIdentity{
+			AccountID: account.ID + 1,
+			Issuer:    "https://idp.example.com",
+			Subject:   "user-1",
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("list and delete", func(t *testing.T) {
+		identities, err := db.ListAccountIdentities(ctx, account.ID)
+		require.NoError(t, err)
+		require.Len(t, identities, 1)
+
+		require.ErrorIs(t, db.DeleteAccountIdentity(ctx, account.ID+1, identity.ID), ErrNotFound)
+		require.NoError(t, db.DeleteAccountIdentity(ctx, account.ID, identity.ID))
+
+		identities, err = db.ListAccountIdentities(ctx, account.ID)
+		require.NoError(t, err)
+		require.Empty(t, identities)
+	})
+
+	t.Run("removed with the account", func(t *testing.T) {
+		_, err := db.CreateAccountIdentity(ctx, model.AccountIdentity{
+			AccountID: account.ID,
+			Issuer:    "https://idp.example.com",
+			Subject:   "user-2",
+		})
+		require.NoError(t, err)
+		require.NoError(t, db.DeleteAccount(ctx, account.ID))
+
+		_, exists, err := db.GetAccountIdentity(ctx, "https://idp.example.com", "user-2")
+		require.NoError(t, err)
+		require.False(t, exists)
+	})
+}
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+var apiTokenColumns = []string{"id", "account_id", "name", "token_hash", "scopes", "expires_at", "last_used_at", "created_at"}
+
+// GetAPIToken fetch a personal API token by the hash of its value.
+func (db *dbbase) GetAPIToken(ctx context.Context, tokenHash string) (*model.APIToken, bool, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select(apiTokenColumns...)
+	sb.From("api_token")
+	sb.Where(sb.Equal("token_hash", tokenHash))
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	token := model.APIToken{}
+	if err := db.ReaderDB().GetContext(ctx, &token, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to get api token: %w", err)
+	}
+
+	return &token, true, nil
+}
+
+// ListAPITokens fetch the personal API tokens of an account, newest first.
+func (db *dbbase) ListAPITokens(ctx context.Context, accountID model.DBID) ([]model.APIToken, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select(apiTokenColumns...)
+	sb.From("api_token")
+	sb.Where(sb.Equal("account_id", accountID))
+	sb.OrderBy("id DESC")
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	tokens := []model.APIToken{}
+	if err := db.ReaderDB().SelectContext(ctx, &tokens, query, args...); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to list api tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// UpdateAPITokenLastUsed records when a personal API token was last used.
+func (db *dbbase) UpdateAPITokenLastUsed(ctx context.Context, id model.DBID, lastUsedAt string) error {
+	ub := db.Flavor().NewUpdateBuilder()
+	ub.Update("api_token")
+	ub.Set(ub.Assign("last_used_at", lastUsedAt))
+	ub.Where(ub.Equal("id", id))
+
+	query, args := ub.Build()
+	query = db.WriterDB().Rebind(query)
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to update api token: %w", err)
+		}
+		return nil
+	})
+}
+
+// DeleteAPIToken removes a personal API token of an account, ErrNotFound if it doesn't exist.
+func (db *dbbase) DeleteAPIToken(ctx context.Context, accountID model.DBID, id model.DBID) error {
+	dlb := db.Flavor().NewDeleteBuilder()
+	dlb.DeleteFrom("api_token")
+	dlb.Where(dlb.Equal("id", id), dlb.Equal("account_id", accountID))
+
+	query, args := dlb.Build()
+	query = db.WriterDB().Rebind(query)
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to delete api token: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rows == 0 {
+			return ErrNotFound
+		}
+
+		return nil
+	})
+}
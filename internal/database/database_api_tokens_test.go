@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func testCreateAPIToken(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	account, err := db.CreateAccount(ctx, model.Account{Username: "shiori", Password: "hash"})
+	require.NoError(t, err)
+
+	token, err := db.CreateAPIToken(ctx, model.APIToken{
+		AccountID: account.ID,
+		Name:      "script",
+		TokenHash: "hash-1",
+		Scopes:    model.APITokenScopes{model.APITokenScopeRead, model.APITokenScopeWrite},
+		ExpiresAt: model.Ptr("2030-01-01 00:00:00"),
+	})
+	require.NoError(t, err)
+	require.NotZero(t, token.ID)
+	require.NotEmpty(t, token.CreatedAt)
+
+	saved, exists, err := db.GetAPIToken(ctx, "hash-1")
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, token.ID, saved.ID)
+	require.Equal(t, "script", saved.Name)
+	require.Equal(t, model.APITokenScopes{model.APITokenScopeRead, model.APITokenScopeWrite}, saved.Scopes)
+	require.NotNil(t, saved.ExpiresAt)
+	require.Contains(t, *saved.ExpiresAt, "2030-01-01")
+	require.Nil(t, saved.LastUsedAt)
+
+	_, exists, err = db.GetAPIToken(ctx, "hash-2")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	require.NoError(t, db.UpdateAPITokenLastUsed(ctx, token.ID, "2025-01-01 10:00:00"))
+	saved, _, err = db.GetAPIToken(ctx, "hash-1")
+	require.NoError(t, err)
+	require.NotNil(t, saved.LastUsedAt)
+	require.Contains(t, *saved.LastUsedAt, "2025-01-01")
+}
+
+func testListAPITokens(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	first, err := db.CreateAccount(ctx, model.Account{Username: "first", Password: "hash"})
+	require.NoError(t, err)
+	second, err := db.CreateAccount(ctx, model.Account{Username: "second", Password: "hash"})
+	require.NoError(t, err)
+
+	for i, accountID := range []model.DBID{first.ID, first.ID, second.ID} {
+		_, err := db.CreateAPIToken(ctx, model.APIToken{
+			AccountID: accountID,
+			Name:      "token",
+			TokenHash: fmt.Sprintf("hash-%d", i),
+			Scopes:    model.APITokenScopes{model.APITokenScopeRead},
+		})
+		require.NoError(t, err)
+	}
+
+	tokens, err := db.ListAPITokens(ctx, first.ID)
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+	require.Greater(t, tokens[0].ID, tokens[1].ID)
+
+	t.Run("removed with the account", func(t *testing.T) {
+		require.NoError(t, db.DeleteAccount(ctx, first.ID))
+
+		tokens, err := db.ListAPITokens(ctx, first.ID)
+		require.NoError(t, err)
+		require.Empty(t, tokens)
+
+		tokens, err = db.ListAPITokens(ctx, second.ID)
+		require.NoError(t, err)
+		require.Len(t, tokens, 1)
+	})
+}
+
+func testDeleteAPIToken(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	token, err := db.CreateAPIToken(ctx, model.APIToken{AccountID: 1, Name: "token", TokenHash: "hash"})
+	require.NoError(t, err)
+
+	// Tokens of other accounts are not found
+	err = db.DeleteAPIToken(ctx, 2, token.ID)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, db.DeleteAPIToken(ctx, 1, token.ID))
+
+	_, exists, err := db.GetAPIToken(ctx, "hash")
+	require.NoError(t, err)
+	require.False(t, exists)
+}
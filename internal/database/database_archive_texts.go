@@ -0,0 +1,106 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// SaveArchiveText replaces the text indexed from the archive of a bookmark, an empty text
+// removes it.
+func (db *dbbase) SaveArchiveText(ctx context.Context, bookmarkID int, text string) error {
+	dlb := db.Flavor().NewDeleteBuilder()
+	dlb.DeleteFrom("bookmark_archive_text")
+	dlb.Where(dlb.Equal("bookmark_id", bookmarkID))
+
+	deleteQuery, deleteArgs := dlb.Build()
+	deleteQuery = db.WriterDB().Rebind(deleteQuery)
+
+	ib := db.Flavor().NewInsertBuilder()
+	ib.InsertInto("bookmark_archive_text")
+	ib.Cols("bookmark_id", "content", "modified_at")
+	ib.Values(bookmarkID, text, time.Now().UTC().Format(model.DatabaseDateFormat))
+
+	insertQuery, insertArgs := ib.Build()
+	insertQuery = db.WriterDB().Rebind(insertQuery)
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, deleteQuery, deleteArgs...); err != nil {
+			return fmt.Errorf("failed to delete archive text: %w", err)
+		}
+
+		if text == "" {
+			return nil
+		}
+
+		if _, err := tx.ExecContext(ctx, insertQuery, insertArgs...); err != nil {
+			return fmt.Errorf("failed to insert archive text: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetArchiveTexts fetch the texts indexed from the archives of bookmarks, by bookmark ID.
+func (db *dbbase) GetArchiveTexts(ctx context.Context, bookmarkIDs []int) (map[int]string, error) {
+	texts := map[int]string{}
+	if len(bookmarkIDs) == 0 {
+		return texts, nil
+	}
+
+	values := make([]interface{}, len(bookmarkIDs))
+	for i, id := range bookmarkIDs {
+		values[i] = id
+	}
+
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select("bookmark_id", "content")
+	sb.From("bookmark_archive_text")
+	sb.Where(sb.In("bookmark_id", values...))
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	rows := []struct {
+		BookmarkID int    `db:"bookmark_id"`
+		Content    string `db:"content"`
+	}{}
+	if err := db.ReaderDB().SelectContext(ctx, &rows, query, args...); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get archive texts: %w", err)
+	}
+
+	for _, row := range rows {
+		texts[row.BookmarkID] = row.Content
+	}
+
+	return texts, nil
+}
+
+// DeleteArchiveTexts removes the texts indexed from the archives of bookmarks.
+func (db *dbbase) DeleteArchiveTexts(ctx context.Context, bookmarkIDs ...int) error {
+	if len(bookmarkIDs) == 0 {
+		return nil
+	}
+
+	values := make([]interface{}, len(bookmarkIDs))
+	for i, id := range bookmarkIDs {
+		values[i] = id
+	}
+
+	dlb := db.Flavor().NewDeleteBuilder()
+	dlb.DeleteFrom("bookmark_archive_text")
+	dlb.Where(dlb.In("bookmark_id", values...))
+
+	query, args := dlb.Build()
+	query = db.WriterDB().Rebind(query)
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to delete archive texts: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,46 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func testArchiveTexts(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	result, err := db.SaveBookmarks(ctx, true,
+		model.BookmarkDTO{URL: "https://github.com/go-shiori/shiori", Title: "shiori"},
+		model.BookmarkDTO{URL: "https://github.com/go-shiori/warc", Title: "warc"},
+	)
+	require.NoError(t, err)
+	first, second := result[0], result[1]
+
+	t.Run("save and replace", func(t *testing.T) {
+		require.NoError(t, db.SaveArchiveText(ctx, first.ID, "first text"))
+		require.NoError(t, db.SaveArchiveText(ctx, first.ID, "replaced text"))
+		require.NoError(t, db.SaveArchiveText(ctx, second.ID, "second text"))
+
+		texts, err := db.GetArchiveTexts(ctx, []int{first.ID, second.ID})
+		require.NoError(t, err)
+		require.Equal(t, map[int]string{first.ID: "replaced text", second.ID: "second text"}, texts)
+	})
+
+	t.Run("empty text removes it", func(t *testing.T) {
+		require.NoError(t, db.SaveArchiveText(ctx, second.ID, ""))
+
+		texts, err := db.GetArchiveTexts(ctx, []int{second.ID})
+		require.NoError(t, err)
+		require.Empty(t, texts)
+	})
+
+	t.Run("with the bookmark", func(t *testing.T) {
+		require.NoError(t, db.DeleteBookmarks(ctx, 0, first.ID))
+
+		texts, err := db.GetArchiveTexts(ctx, []int{first.ID})
+		require.NoError(t, err)
+		require.Empty(t, texts)
+	})
+}
@@ -0,0 +1,95 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// GetArchiveSnapshots fetch the archive snapshots of a bookmark, newest first.
+func (db *dbbase) GetArchiveSnapshots(ctx context.Context, bookmarkID int) ([]model.ArchiveSnapshot, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select("id", "bookmark_id", "path", "created_at")
+	sb.From("archive_snapshot")
+	sb.Where(sb.Equal("bookmark_id", bookmarkID))
+	sb.OrderBy("created_at DESC", "id DESC")
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	snapshots := []model.ArchiveSnapshot{}
+	if err := db.ReaderDB().SelectContext(ctx, &snapshots, query, args...); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get archive snapshots: %w", err)
+	}
+
+	return snapshots, nil
+}
+
+// GetArchiveSnapshot fetch an archive snapshot by its ID.
+func (db *dbbase) GetArchiveSnapshot(ctx context.Context, id model.DBID) (*model.ArchiveSnapshot, bool, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select("id", "bookmark_id", "path", "created_at")
+	sb.From("archive_snapshot")
+	sb.Where(sb.Equal("id", id))
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	snapshot := model.ArchiveSnapshot{}
+	if err := db.ReaderDB().GetContext(ctx, &snapshot, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to get archive snapshot: %w", err)
+	}
+
+	return &snapshot, true, nil
+}
+
+// UpdateArchiveSnapshot updates the file path of an archive snapshot.
+func (db *dbbase) UpdateArchiveSnapshot(ctx context.Context, snapshot model.ArchiveSnapshot) error {
+	ub := db.Flavor().NewUpdateBuilder()
+	ub.Update("archive_snapshot")
+	ub.Set(ub.Assign("path", snapshot.Path))
+	ub.Where(ub.Equal("id", snapshot.ID))
+
+	query, args := ub.Build()
+	query = db.WriterDB().Rebind(query)
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to update archive snapshot: %w", err)
+		}
+		return nil
+	})
+}
+
+// DeleteArchiveSnapshots removes the archive snapshots with matching ids.
+func (db *dbbase) DeleteArchiveSnapshots(ctx context.Context, ids ...model.DBID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	values := make([]interface{}, len(ids))
+	for i, id := range ids {
+		values[i] = id
+	}
+
+	dlb := db.Flavor().NewDeleteBuilder()
+	dlb.DeleteFrom("archive_snapshot")
+	dlb.Where(dlb.In("id", values...))
+
+	query, args := dlb.Build()
+	query = db.WriterDB().Rebind(query)
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to delete archive snapshots: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,111 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func testCreateArchiveSnapshot(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	result, err := db.SaveBookmarks(ctx, true, model.BookmarkDTO{URL: "https://github.com/go-shiori/shiori", Title: "shiori"})
+	require.NoError(t, err)
+	book := result[0]
+
+	snapshot, err := db.CreateArchiveSnapshot(ctx, model.ArchiveSnapshot{
+		BookmarkID: book.ID,
+		Path:       model.GetArchivePath(&book),
+	})
+	require.NoError(t, err)
+	require.NotZero(t, snapshot.ID)
+	require.NotEmpty(t, snapshot.CreatedAt)
+
+	saved, exists, err := db.GetArchiveSnapshot(ctx, snapshot.ID)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, book.ID, saved.BookmarkID)
+	require.Equal(t, model.GetArchivePath(&book), saved.Path)
+
+	_, exists, err = db.GetArchiveSnapshot(ctx, snapshot.ID+1)
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+func testGetArchiveSnapshots(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	result, err := db.SaveBookmarks(ctx, true,
+		model.BookmarkDTO{URL: "https://github.com/go-shiori/shiori", Title: "shiori"},
+		model.BookmarkDTO{URL: "https://github.com/go-shiori/obelisk", Title: "obelisk"},
+	)
+	require.NoError(t, err)
+	book, other := result[0], result[1]
+
+	for _, createdAt := range []string{"2024-01-01 00:00:00", "2024-03-01 00:00:00", "2024-02-01 00:00:00"} {
+		_, err := db.CreateArchiveSnapshot(ctx, model.ArchiveSnapshot{BookmarkID: book.ID, Path: "archive/" + createdAt, CreatedAt: createdAt})
+		require.NoError(t, err)
+	}
+	_, err = db.CreateArchiveSnapshot(ctx, model.ArchiveSnapshot{BookmarkID: other.ID, Path: model.GetArchivePath(&other)})
+	require.NoError(t, err)
+
+	snapshots, err := db.GetArchiveSnapshots(ctx, book.ID)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 3)
+	require.Equal(t, "archive/2024-03-01 00:00:00", snapshots[0].Path)
+	require.Equal(t, "archive/2024-01-01 00:00:00", snapshots[2].Path)
+
+	snapshots, err = db.GetArchiveSnapshots(ctx, 999)
+	require.NoError(t, err)
+	require.Empty(t, snapshots)
+}
+
+func testUpdateArchiveSnapshot(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	result, err := db.SaveBookmarks(ctx, true, model.BookmarkDTO{URL: "https://github.com/go-shiori/shiori", Title: "shiori"})
+	require.NoError(t, err)
+	book := result[0]
+
+	snapshot, err := db.CreateArchiveSnapshot(ctx, model.ArchiveSnapshot{BookmarkID: book.ID, Path: model.GetArchivePath(&book)})
+	require.NoError(t, err)
+
+	snapshot.Path = model.GetArchiveSnapshotPath(&book, snapshot.ID)
+	require.NoError(t, db.UpdateArchiveSnapshot(ctx, *snapshot))
+
+	saved, _, err := db.GetArchiveSnapshot(ctx, snapshot.ID)
+	require.NoError(t, err)
+	require.Equal(t, model.GetArchiveSnapshotPath(&book, snapshot.ID), saved.Path)
+}
+
+func testDeleteArchiveSnapshots(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	result, err := db.SaveBookmarks(ctx, true, model.BookmarkDTO{URL: "https://github.com/go-shiori/shiori", Title: "shiori"})
+	require.NoError(t, err)
+	book := result[0]
+
+	first, err := db.CreateArchiveSnapshot(ctx, model.ArchiveSnapshot{BookmarkID: book.ID, Path: "archive/first"})
+	require.NoError(t, err)
+	_, err = db.CreateArchiveSnapshot(ctx, model.ArchiveSnapshot{BookmarkID: book.ID, Path: "archive/second"})
+	require.NoError(t, err)
+
+	t.Run("by id", func(t *testing.T) {
+		require.NoError(t, db.DeleteArchiveSnapshots(ctx, first.ID))
+
+		snapshots, err := db.GetArchiveSnapshots(ctx, book.ID)
+		require.NoError(t, err)
+		require.Len(t, snapshots, 1)
+		require.Equal(t, "archive/second", snapshots[0].Path)
+	})
+
+	t.Run("with the bookmark", func(t *testing.T) {
+		require.NoError(t, db.DeleteBookmarks(ctx, 0, book.ID))
+
+		snapshots, err := db.GetArchiveSnapshots(ctx, book.ID)
+		require.NoError(t, err)
+		require.Empty(t, snapshots)
+	})
+}
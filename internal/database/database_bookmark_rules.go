@@ -0,0 +1,108 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+var bookmarkRuleColumns = []string{"id", "account_id", "name", "enabled", "conditions", "actions", "created_at", "modified_at"}
+
+// GetBookmarkRule fetch a bookmark rule by its ID.
+func (db *dbbase) GetBookmarkRule(ctx context.Context, id model.DBID) (*model.BookmarkRule, bool, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select(bookmarkRuleColumns...)
+	sb.From("bookmark_rule")
+	sb.Where(sb.Equal("id", id))
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	rule := model.BookmarkRule{}
+	if err := db.ReaderDB().GetContext(ctx, &rule, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to get bookmark rule: %w", err)
+	}
+
+	return &rule, true, nil
+}
+
+// ListBookmarkRules fetch the bookmark rules of an account, or of every account if zero, in
+// the order they were created.
+func (db *dbbase) ListBookmarkRules(ctx context.Context, accountID model.DBID) ([]model.BookmarkRule, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select(bookmarkRuleColumns...)
+	sb.From("bookmark_rule")
+	if accountID > 0 {
+		sb.Where(sb.Equal("account_id", accountID))
+	}
+	sb.OrderBy("id ASC")
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	rules := []model.BookmarkRule{}
+	if err := db.ReaderDB().SelectContext(ctx, &rules, query, args...); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to list bookmark rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// UpdateBookmarkRule saves the name, state, conditions and actions of a bookmark rule.
+func (db *dbbase) UpdateBookmarkRule(ctx context.Context, rule model.BookmarkRule) error {
+	ub := db.Flavor().NewUpdateBuilder()
+	ub.Update("bookmark_rule")
+	ub.Set(
+		ub.Assign("name", rule.Name),
+		ub.Assign("enabled", rule.Enabled),
+		ub.Assign("conditions", rule.Conditions),
+		ub.Assign("actions", rule.Actions),
+		ub.Assign("modified_at", time.Now().UTC().Format(model.DatabaseDateFormat)),
+	)
+	ub.Where(ub.Equal("id", rule.ID))
+
+	query, args := ub.Build()
+	query = db.WriterDB().Rebind(query)
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to update bookmark rule: %w", err)
+		}
+		return nil
+	})
+}
+
+// DeleteBookmarkRule removes a bookmark rule. ErrNotFound if it doesn't exist.
+func (db *dbbase) DeleteBookmarkRule(ctx context.Context, id model.DBID) error {
+	dlb := db.Flavor().NewDeleteBuilder()
+	dlb.DeleteFrom("bookmark_rule")
+	dlb.Where(dlb.Equal("id", id))
+
+	query, args := dlb.Build()
+	query = db.WriterDB().Rebind(query)
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to delete bookmark rule: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rows == 0 {
+			return ErrNotFound
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func testBookmarkRules(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	account, err := db.CreateAccount(ctx, model.Account{Username: "tagger", Password: "hash"})
+	require.NoError(t, err)
+
+	public := true
+	rule, err := db.CreateBookmarkRule(ctx, model.BookmarkRule{
+		AccountID: account.ID,
+		Name:      "Go articles",
+		Enabled:   true,
+		Conditions: model.RuleConditions{
+			Host:     "go.dev",
+			Keywords: []string{"generics", "iterators"},
+		},
+		Actions: model.RuleActions{AddTags: []string{"golang"}, Public: &public},
+	})
+	require.NoError(t, err)
+	require.NotZero(t, rule.ID)
+
+	_, err = db.CreateBookmarkRule(ctx, model.BookmarkRule{
+		AccountID:  account.ID + 1,
+		Name:       "Other account",
+		Conditions: model.RuleConditions{ContentType: "application/pdf"},
+		Actions:    model.RuleActions{Reject: true},
+	})
+	require.NoError(t, err)
+
+	saved, exists, err := db.GetBookmarkRule(ctx, rule.ID)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, "Go articles", saved.Name)
+	require.True(t, saved.Enabled)
+	require.Equal(t, rule.Conditions, saved.Conditions)
+	require.Equal(t, []string{"golang"}, saved.Actions.AddTags)
+	require.NotNil(t, saved.Actions.Public)
+	require.True(t, *saved.Actions.Public)
+	require.Nil(t, saved.Actions.CreateArchive)
+
+	t.Run("list by account", func(t *testing.T) {
+		rules, err := db.ListBookmarkRules(ctx, account.ID)
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+		require.Equal(t, rule.ID, rules[0].ID)
+
+		rules, err = db.ListBookmarkRules(ctx, 0)
+		require.NoError(t, err)
+		require.Len(t, rules, 2)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		saved.Enabled = false
+		saved.Conditions = model.RuleConditions{URLPattern: `^https://go\.dev/blog/`}
+		saved.Actions = model.RuleActions{Reject: true}
+		require.NoError(t, db.UpdateBookmarkRule(ctx, *saved))
+
+		updated, _, err := db.GetBookmarkRule(ctx, rule.ID)
+		require.NoError(t, err)
+		require.False(t, updated.Enabled)
+		require.Equal(t, saved.Conditions, updated.Conditions)
+		require.True(t, updated.Actions.Reject)
+		require.Empty(t, updated.Actions.AddTags)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, db.DeleteBookmarkRule(ctx, rule.ID))
+		require.ErrorIs(t, db.DeleteBookmarkRule(ctx, rule.ID), ErrNotFound)
+
+		_, exists, err := db.GetBookmarkRule(ctx, rule.ID)
+		require.NoError(t, err)
+		require.False(t, exists)
+	})
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// addedAfterCondition returns the condition matching the bookmarks whose creation column is
+// after the given time, empty if it is zero.
+func addedAfterCondition(column string, after time.Time, bind func(value interface{}) string) string {
+	if after.IsZero() {
+		return ""
+	}
+	return column + ` > ` + bind(after.UTC().Format(model.DatabaseDateFormat))
+}
+
+// TransferBookmarks moves every bookmark owned by fromAccountID to toAccountID.
+// Bookmarks whose URL is already saved by the target account stay with the source account.
+func (db *dbbase) TransferBookmarks(ctx context.Context, fromAccountID, toAccountID model.DBID) error {
+	if fromAccountID == toAccountID {
+		return nil
+	}
+
+	urlsSb := db.Flavor().NewSelectBuilder()
+	urlsSb.Select("b2.url")
+	urlsSb.From("bookmark b2")
+	urlsSb.Where(urlsSb.Equal("b2.account_id", toAccountID))
+
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select("b.id")
+	sb.From("bookmark b")
+	sb.Where(
+		sb.Equal("b.account_id", fromAccountID),
+		sb.NotIn("b.url", urlsSb),
+	)
+
+	selectQuery, selectArgs := sb.Build()
+	selectQuery = db.WriterDB().Rebind(selectQuery)
+
+	ub := db.Flavor().NewUpdateBuilder()
+	ub.Update("bookmark")
+	ub.Set(ub.Assign("account_id", toAccountID))
+	ub.Where(ub.Equal("id", 0))
+
+	updateQuery, _ := ub.Build()
+	updateQuery = db.WriterDB().Rebind(updateQuery)
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ids := []int{}
+		if err := tx.SelectContext(ctx, &ids, selectQuery, selectArgs...); err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("failed to get bookmarks to transfer: %w", err)
+		}
+
+		stmt, err := tx.PreparexContext(ctx, updateQuery)
+		if err != nil {
+			return fmt.Errorf("failed to prepare transfer statement: %w", err)
+		}
+		defer stmt.Close()
+
+		for _, id := range ids {
+			if _, err := stmt.ExecContext(ctx, toAccountID, id); err != nil {
+				return fmt.Errorf("failed to transfer bookmark %d: %w", id, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// accountBookmarkIDs narrows ids down to the bookmarks owned by accountID.
+// When ids is empty every bookmark owned by the account is returned.
+func (db *dbbase) accountBookmarkIDs(ctx context.Context, accountID model.DBID, ids []int) ([]int, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select("id")
+	sb.From("bookmark")
+	sb.Where(sb.Equal("account_id", accountID))
+	if len(ids) > 0 {
+		values := make([]interface{}, len(ids))
+		for i, id := range ids {
+			values[i] = id
+		}
+		sb.Where(sb.In("id", values...))
+	}
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	result := []int{}
+	if err := db.ReaderDB().SelectContext(ctx, &result, query, args...); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get account bookmarks: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,181 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+var collectionColumns = []string{"id", "account_id", "parent_id", "name", "position", "created_at", "modified_at"}
+
+// collectionCondition returns the condition matching the bookmarks whose collection column is
+// the given collection, zero matching the bookmarks in none. Empty if there is nothing to filter.
+func collectionCondition(column string, collectionID *model.DBID) string {
+	if collectionID == nil {
+		return ""
+	}
+	if *collectionID == 0 {
+		return column + ` IS NULL`
+	}
+	return fmt.Sprintf(`%s = %d`, column, *collectionID)
+}
+
+// GetCollection fetch a collection by its ID.
+func (db *dbbase) GetCollection(ctx context.Context, id model.DBID) (*model.Collection, bool, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select(collectionColumns...)
+	sb.From("collection")
+	sb.Where(sb.Equal("id", id))
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	collection := model.Collection{}
+	if err := db.ReaderDB().GetContext(ctx, &collection, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to get collection: %w", err)
+	}
+
+	return &collection, true, nil
+}
+
+// ListCollections fetch the collections of an account, or of every account if zero, ordered
+// by position.
+func (db *dbbase) ListCollections(ctx context.Context, accountID model.DBID) ([]model.Collection, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select(collectionColumns...)
+	sb.From("collection")
+	if accountID > 0 {
+		sb.Where(sb.Equal("account_id", accountID))
+	}
+	sb.OrderBy("position ASC", "id ASC")
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	collections := []model.Collection{}
+	if err := db.ReaderDB().SelectContext(ctx, &collections, query, args...); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+
+	return collections, nil
+}
+
+// UpdateCollection saves the name, parent and position of a collection.
+func (db *dbbase) UpdateCollection(ctx context.Context, collection model.Collection) error {
+	return db.UpdateCollections(ctx, collection)
+}
+
+// UpdateCollections saves the name, parent and position of several collections in a single
+// transaction.
+func (db *dbbase) UpdateCollections(ctx context.Context, collections ...model.Collection) error {
+	modifiedAt := time.Now().UTC().Format(model.DatabaseDateFormat)
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		for _, collection := range collections {
+			ub := db.Flavor().NewUpdateBuilder()
+			ub.Update("collection")
+			ub.Set(
+				ub.Assign("parent_id", collection.ParentID),
+				ub.Assign("name", collection.Name),
+				ub.Assign("position", collection.Position),
+				ub.Assign("modified_at", modifiedAt),
+			)
+			ub.Where(ub.Equal("id", collection.ID))
+
+			query, args := ub.Build()
+			if _, err := tx.ExecContext(ctx, tx.Rebind(query), args...); err != nil {
+				return fmt.Errorf("failed to update collection: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteCollection removes a collection, moving its bookmarks and child collections to its
+// parent. ErrNotFound if it doesn't exist.
+func (db *dbbase) DeleteCollection(ctx context.Context, id model.DBID) error {
+	collection, exists, err := db.GetCollection(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrNotFound
+	}
+
+	ubChildren := db.Flavor().NewUpdateBuilder()
+	ubChildren.Update("collection")
+	ubChildren.Set(ubChildren.Assign("parent_id", collection.ParentID))
+	ubChildren.Where(ubChildren.Equal("parent_id", id))
+	childrenQuery, childrenArgs := ubChildren.Build()
+
+	ubBookmarks := db.Flavor().NewUpdateBuilder()
+	ubBookmarks.Update("bookmark")
+	ubBookmarks.Set(ubBookmarks.Assign("collection_id", collection.ParentID))
+	ubBookmarks.Where(ubBookmarks.Equal("collection_id", id))
+	bookmarksQuery, bookmarksArgs := ubBookmarks.Build()
+
+	dlb := db.Flavor().NewDeleteBuilder()
+	dlb.DeleteFrom("collection")
+	dlb.Where(dlb.Equal("id", id))
+	deleteQuery, deleteArgs := dlb.Build()
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, tx.Rebind(childrenQuery), childrenArgs...); err != nil {
+			return fmt.Errorf("failed to move child collections: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, tx.Rebind(bookmarksQuery), bookmarksArgs...); err != nil {
+			return fmt.Errorf("failed to move collection bookmarks: %w", err)
+		}
+
+		result, err := tx.ExecContext(ctx, tx.Rebind(deleteQuery), deleteArgs...)
+		if err != nil {
+			return fmt.Errorf("failed to delete collection: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rows == 0 {
+			return ErrNotFound
+		}
+
+		return nil
+	})
+}
+
+// SetBookmarksCollection moves bookmarks into a collection, or out of any if nil.
+func (db *dbbase) SetBookmarksCollection(ctx context.Context, collectionID *model.DBID, bookmarkIDs ...int) error {
+	if len(bookmarkIDs) == 0 {
+		return nil
+	}
+
+	ids := make([]any, len(bookmarkIDs))
+	for i, id := range bookmarkIDs {
+		ids[i] = id
+	}
+
+	ub := db.Flavor().NewUpdateBuilder()
+	ub.Update("bookmark")
+	ub.Set(ub.Assign("collection_id", collectionID))
+	ub.Where(ub.In("id", ids...))
+
+	query, args := ub.Build()
+	query = db.WriterDB().Rebind(query)
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to move bookmarks: %w", err)
+		}
+		return nil
+	})
+}
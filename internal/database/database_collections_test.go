@@ -0,0 +1,117 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func testCollections(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	account, err := db.CreateAccount(ctx, model.Account{Username: "organizer", Password: "hash"})
+	require.NoError(t, err)
+
+	parent, err := db.CreateCollection(ctx, model.Collection{AccountID: account.ID, Name: "Work"})
+	require.NoError(t, err)
+	require.NotZero(t, parent.ID)
+
+	child, err := db.CreateCollection(ctx, model.Collection{AccountID: account.ID, ParentID: &parent.ID, Name: "Reports", Position: 1})
+	require.NoError(t, err)
+
+	_, err = db.CreateCollection(ctx, model.Collection{AccountID: account.ID + 1, Name: "Other account"})
+	require.NoError(t, err)
+
+	saved, exists, err := db.GetCollection(ctx, child.ID)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, "Reports", saved.Name)
+	require.Equal(t, &parent.ID, saved.ParentID)
+	require.Equal(t, 1, saved.Position)
+
+	t.Run("list by account", func(t *testing.T) {
+		collections, err := db.ListCollections(ctx, account.ID)
+		require.NoError(t, err)
+		require.Len(t, collections, 2)
+		require.Equal(t, parent.ID, collections[0].ID)
+		require.Nil(t, collections[0].ParentID)
+
+		collections, err = db.ListCollections(ctx, 0)
+		require.NoError(t, err)
+		require.Len(t, collections, 3)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		saved.Name = "Quarterly reports"
+		saved.ParentID = nil
+		saved.Position = 2
+		require.NoError(t, db.UpdateCollection(ctx, *saved))
+
+		updated, _, err := db.GetCollection(ctx, child.ID)
+		require.NoError(t, err)
+		require.Equal(t, "Quarterly reports", updated.Name)
+		require.Nil(t, updated.ParentID)
+		require.Equal(t, 2, updated.Position)
+
+		updated.ParentID = &parent.ID
+		require.NoError(t, db.UpdateCollection(ctx, *updated))
+	})
+
+	bookmarks, err := db.SaveBookmarks(ctx, true,
+		model.BookmarkDTO{AccountID: account.ID, URL: "https://example.com/report", Title: "Report", CollectionID: &child.ID},
+		model.BookmarkDTO{AccountID: account.ID, URL: "https://example.com/unfiled", Title: "Unfiled"},
+	)
+	require.NoError(t, err)
+
+	t.Run("filter bookmarks", func(t *testing.T) {
+		inChild, err := db.GetBookmarks(ctx, model.DBGetBookmarksOptions{CollectionID: &child.ID})
+		require.NoError(t, err)
+		require.Len(t, inChild, 1)
+		require.Equal(t, bookmarks[0].ID, inChild[0].ID)
+		require.Equal(t, &child.ID, inChild[0].CollectionID)
+
+		count, err := db.GetBookmarksCount(ctx, model.DBGetBookmarksOptions{CollectionID: model.Ptr(model.DBID(0))})
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+	})
+
+	t.Run("move bookmarks", func(t *testing.T) {
+		require.NoError(t, db.SetBookmarksCollection(ctx, &parent.ID, bookmarks[1].ID))
+
+		book, _, err := db.GetBookmark(ctx, bookmarks[1].ID, "", 0)
+		require.NoError(t, err)
+		require.Equal(t, &parent.ID, book.CollectionID)
+
+		require.NoError(t, db.SetBookmarksCollection(ctx, nil, bookmarks[1].ID))
+
+		book, _, err = db.GetBookmark(ctx, bookmarks[1].ID, "", 0)
+		require.NoError(t, err)
+		require.Nil(t, book.CollectionID)
+	})
+
+	t.Run("delete moves the content to the parent", func(t *testing.T) {
+		grandchild, err := db.CreateCollection(ctx, model.Collection{AccountID: account.ID, ParentID: &child.ID, Name: "2024"})
+		require.NoError(t, err)
+
+		require.NoError(t, db.DeleteCollection(ctx, child.ID))
+		require.ErrorIs(t, db.DeleteCollection(ctx, child.ID), ErrNotFound)
+
+		moved, _, err := db.GetCollection(ctx, grandchild.ID)
+		require.NoError(t, err)
+		require.Equal(t, &parent.ID, moved.ParentID)
+
+		book, _, err := db.GetBookmark(ctx, bookmarks[0].ID, "", 0)
+		require.NoError(t, err)
+		require.Equal(t, &parent.ID, book.CollectionID)
+	})
+
+	t.Run("deleted with the account", func(t *testing.T) {
+		require.NoError(t, db.DeleteAccount(ctx, account.ID))
+
+		collections, err := db.ListCollections(ctx, account.ID)
+		require.NoError(t, err)
+		require.Empty(t, collections)
+	})
+}
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// getFeedToken fetch the feed token matching the column value.
+func (db *dbbase) getFeedToken(ctx context.Context, column string, value any) (*model.FeedToken, bool, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select("account_id", "token_hash", "created_at")
+	sb.From("feed_token")
+	sb.Where(sb.Equal(column, value))
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	token := model.FeedToken{}
+	if err := db.ReaderDB().GetContext(ctx, &token, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to get feed token: %w", err)
+	}
+
+	return &token, true, nil
+}
+
+// GetFeedToken fetch the feed token of an account.
+func (db *dbbase) GetFeedToken(ctx context.Context, accountID model.DBID) (*model.FeedToken, bool, error) {
+	return db.getFeedToken(ctx, "account_id", accountID)
+}
+
+// GetFeedTokenByHash fetch a feed token by the hash of its value.
+func (db *dbbase) GetFeedTokenByHash(ctx context.Context, tokenHash string) (*model.FeedToken, bool, error) {
+	return db.getFeedToken(ctx, "token_hash", tokenHash)
+}
+
+// SaveFeedToken stores the feed token of an account, replacing the existing one.
+func (db *dbbase) SaveFeedToken(ctx context.Context, token model.FeedToken) error {
+	if token.CreatedAt == "" {
+		token.CreatedAt = time.Now().UTC().Format(model.DatabaseDateFormat)
+	}
+
+	dlb := db.Flavor().NewDeleteBuilder()
+	dlb.DeleteFrom("feed_token")
+	dlb.Where(dlb.Equal("account_id", token.AccountID))
+	deleteQuery, deleteArgs := dlb.Build()
+
+	ib := db.Flavor().NewInsertBuilder()
+	ib.InsertInto("feed_token")
+	ib.Cols("account_id", "token_hash", "created_at")
+	ib.Values(token.AccountID, token.TokenHash, token.CreatedAt)
+	insertQuery, insertArgs := ib.Build()
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, tx.Rebind(deleteQuery), deleteArgs...); err != nil {
+			return fmt.Errorf("failed to delete feed token: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, tx.Rebind(insertQuery), insertArgs...); err != nil {
+			return fmt.Errorf("failed to insert feed token: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// DeleteFeedToken removes the feed token of an account.
+func (db *dbbase) DeleteFeedToken(ctx context.Context, accountID model.DBID) error {
+	dlb := db.Flavor().NewDeleteBuilder()
+	dlb.DeleteFrom("feed_token")
+	dlb.Where(dlb.Equal("account_id", accountID))
+
+	query, args := dlb.Build()
+	query = db.WriterDB().Rebind(query)
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to delete feed token: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func testFeedToken(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	account, err := db.CreateAccount(ctx, model.Account{Username: "feed", Password: "hash"})
+	require.NoError(t, err)
+
+	_, exists, err := db.GetFeedToken(ctx, account.ID)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	require.NoError(t, db.SaveFeedToken(ctx, model.FeedToken{AccountID: account.ID, TokenHash: "first"}))
+	require.NoError(t, db.SaveFeedToken(ctx, model.FeedToken{AccountID: account.ID, TokenHash: "second"}))
+
+	token, exists, err := db.GetFeedToken(ctx, account.ID)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, "second", token.TokenHash)
+	require.NotEmpty(t, token.CreatedAt)
+
+	_, exists, err = db.GetFeedTokenByHash(ctx, "first")
+	require.NoError(t, err)
+	require.False(t, exists, "replaced token must not be found")
+
+	token, exists, err = db.GetFeedTokenByHash(ctx, "second")
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, account.ID, token.AccountID)
+
+	t.Run("deleted", func(t *testing.T) {
+		require.NoError(t, db.DeleteFeedToken(ctx, account.ID))
+
+		_, exists, err := db.GetFeedToken(ctx, account.ID)
+		require.NoError(t, err)
+		require.False(t, exists)
+	})
+
+	t.Run("removed with the account", func(t *testing.T) {
+		require.NoError(t, db.SaveFeedToken(ctx, model.FeedToken{AccountID: account.ID, TokenHash: "third"}))
+		require.NoError(t, db.DeleteAccount(ctx, account.ID))
+
+		_, exists, err := db.GetFeedTokenByHash(ctx, "third")
+		require.NoError(t, err)
+		require.False(t, exists)
+	})
+}
+
+func testGetBookmarksPublicOnly(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	_, err := db.SaveBookmarks(ctx, true,
+		model.BookmarkDTO{AccountID: 1, URL: "https://github.com/go-shiori/shiori", Title: "public", Public: 1},
+		model.BookmarkDTO{AccountID: 1, URL: "https://github.com/go-shiori/go-readability", Title: "private"},
+	)
+	require.NoError(t, err)
+
+	bookmarks, err := db.GetBookmarks(ctx, model.DBGetBookmarksOptions{PublicOnly: true})
+	require.NoError(t, err)
+	require.Len(t, bookmarks, 1)
+	require.Equal(t, "public", bookmarks[0].Title)
+
+	count, err := db.GetBookmarksCount(ctx, model.DBGetBookmarksOptions{PublicOnly: true})
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
@@ -0,0 +1,109 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+var highlightColumns = []string{"id", "bookmark_id", "account_id", "selector", "note", "created_at", "modified_at"}
+
+// GetHighlight fetch a highlight by its ID.
+func (db *dbbase) GetHighlight(ctx context.Context, id model.DBID) (*model.Highlight, bool, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select(highlightColumns...)
+	sb.From("bookmark_highlight")
+	sb.Where(sb.Equal("id", id))
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	highlight := model.Highlight{}
+	if err := db.ReaderDB().GetContext(ctx, &highlight, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to get highlight: %w", err)
+	}
+
+	return &highlight, true, nil
+}
+
+// ListHighlights fetch the highlights matching the options, ordered by bookmark and then by
+// creation.
+func (db *dbbase) ListHighlights(ctx context.Context, opts model.ListHighlightsOptions) ([]model.Highlight, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select(highlightColumns...)
+	sb.From("bookmark_highlight")
+	if opts.BookmarkID > 0 {
+		sb.Where(sb.Equal("bookmark_id", opts.BookmarkID))
+	}
+	if opts.AccountID > 0 {
+		sb.Where(sb.Equal("account_id", opts.AccountID))
+	}
+	sb.OrderBy("bookmark_id ASC", "id ASC")
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	highlights := []model.Highlight{}
+	if err := db.ReaderDB().SelectContext(ctx, &highlights, query, args...); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to list highlights: %w", err)
+	}
+
+	return highlights, nil
+}
+
+// UpdateHighlight saves the selector and note of a highlight.
+func (db *dbbase) UpdateHighlight(ctx context.Context, highlight model.Highlight) error {
+	ub := db.Flavor().NewUpdateBuilder()
+	ub.Update("bookmark_highlight")
+	ub.Set(
+		ub.Assign("selector", highlight.Selector),
+		ub.Assign("note", highlight.Note),
+		ub.Assign("modified_at", time.Now().UTC().Format(model.DatabaseDateFormat)),
+	)
+	ub.Where(ub.Equal("id", highlight.ID))
+
+	query, args := ub.Build()
+	query = db.WriterDB().Rebind(query)
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to update highlight: %w", err)
+		}
+		return nil
+	})
+}
+
+// DeleteHighlight removes a highlight. ErrNotFound if it doesn't exist.
+func (db *dbbase) DeleteHighlight(ctx context.Context, id model.DBID) error {
+	dlb := db.Flavor().NewDeleteBuilder()
+	dlb.DeleteFrom("bookmark_highlight")
+	dlb.Where(dlb.Equal("id", id))
+
+	query, args := dlb.Build()
+	query = db.WriterDB().Rebind(query)
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to delete highlight: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rows == 0 {
+			return ErrNotFound
+		}
+
+		return nil
+	})
+}
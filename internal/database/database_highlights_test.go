@@ -0,0 +1,84 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func testHighlights(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	result, err := db.SaveBookmarks(ctx, true,
+		model.BookmarkDTO{URL: "https://github.com/go-shiori/shiori", Title: "shiori"},
+		model.BookmarkDTO{URL: "https://github.com/go-shiori/warc", Title: "warc"},
+	)
+	require.NoError(t, err)
+	first, second := result[0], result[1]
+
+	selector := model.HighlightSelector{
+		Quote:    model.TextQuoteSelector{Exact: "bookmarks", Prefix: "simple ", Suffix: " manager"},
+		Position: &model.TextPositionSelector{Start: 9, End: 18},
+	}
+
+	highlight, err := db.CreateHighlight(ctx, model.Highlight{BookmarkID: first.ID, AccountID: 1, Selector: selector, Note: "what it does"})
+	require.NoError(t, err)
+	require.NotZero(t, highlight.ID)
+
+	_, err = db.CreateHighlight(ctx, model.Highlight{BookmarkID: first.ID, AccountID: 2, Selector: selector})
+	require.NoError(t, err)
+
+	_, err = db.CreateHighlight(ctx, model.Highlight{BookmarkID: second.ID, AccountID: 1, Selector: selector})
+	require.NoError(t, err)
+
+	saved, exists, err := db.GetHighlight(ctx, highlight.ID)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, selector, saved.Selector)
+	require.Equal(t, "what it does", saved.Note)
+
+	t.Run("list", func(t *testing.T) {
+		highlights, err := db.ListHighlights(ctx, model.ListHighlightsOptions{BookmarkID: first.ID, AccountID: 1})
+		require.NoError(t, err)
+		require.Len(t, highlights, 1)
+		require.Equal(t, highlight.ID, highlights[0].ID)
+
+		highlights, err = db.ListHighlights(ctx, model.ListHighlightsOptions{AccountID: 1})
+		require.NoError(t, err)
+		require.Len(t, highlights, 2)
+
+		highlights, err = db.ListHighlights(ctx, model.ListHighlightsOptions{})
+		require.NoError(t, err)
+		require.Len(t, highlights, 3)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		saved.Note = "a bookmarks manager"
+		saved.Selector.Position = nil
+		require.NoError(t, db.UpdateHighlight(ctx, *saved))
+
+		updated, _, err := db.GetHighlight(ctx, highlight.ID)
+		require.NoError(t, err)
+		require.Equal(t, "a bookmarks manager", updated.Note)
+		require.Nil(t, updated.Selector.Position)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, db.DeleteHighlight(ctx, highlight.ID))
+		require.ErrorIs(t, db.DeleteHighlight(ctx, highlight.ID), ErrNotFound)
+
+		_, exists, err := db.GetHighlight(ctx, highlight.ID)
+		require.NoError(t, err)
+		require.False(t, exists)
+	})
+
+	t.Run("with the bookmark", func(t *testing.T) {
+		require.NoError(t, db.DeleteBookmarks(ctx, 0, second.ID))
+
+		highlights, err := db.ListHighlights(ctx, model.ListHighlightsOptions{BookmarkID: second.ID})
+		require.NoError(t, err)
+		require.Empty(t, highlights)
+	})
+}
@@ -0,0 +1,161 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+var jobColumns = []string{
+	"id", "type", "payload", "status", "attempts", "max_attempts",
+	"last_error", "run_at", "created_at", "updated_at",
+}
+
+// ClaimJob marks the oldest due pending job as running and returns it.
+// It returns nil when there is no job ready to run or another worker claimed it first.
+func (db *dbbase) ClaimJob(ctx context.Context) (*model.Job, error) {
+	now := time.Now().UTC().Format(model.DatabaseDateFormat)
+
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select(jobColumns...)
+	sb.From("job")
+	sb.Where(
+		sb.Equal("status", model.JobStatusPending),
+		sb.LessEqualThan("run_at", now),
+	)
+	sb.OrderBy("run_at", "id")
+	sb.Limit(1)
+
+	selectQuery, selectArgs := sb.Build()
+	selectQuery = db.WriterDB().Rebind(selectQuery)
+
+	var claimed *model.Job
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		job := model.Job{}
+		if err := tx.GetContext(ctx, &job, selectQuery, selectArgs...); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil
+			}
+			return fmt.Errorf("failed to get pending job: %w", err)
+		}
+
+		ub := db.Flavor().NewUpdateBuilder()
+		ub.Update("job")
+		ub.Set(
+			ub.Assign("status", model.JobStatusRunning),
+			ub.Assign("updated_at", now),
+		)
+		ub.Where(
+			ub.Equal("id", job.ID),
+			ub.Equal("status", model.JobStatusPending),
+		)
+
+		updateQuery, updateArgs := ub.Build()
+		updateQuery = db.WriterDB().Rebind(updateQuery)
+
+		result, err := tx.ExecContext(ctx, updateQuery, updateArgs...)
+		if err != nil {
+			return fmt.Errorf("failed to claim job: %w", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to claim job: %w", err)
+		}
+
+		if affected == 0 {
+			return nil
+		}
+
+		job.Status = model.JobStatusRunning
+		job.UpdatedAt = now
+		claimed = &job
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return claimed, nil
+}
+
+// UpdateJob saves the status, attempts, error and schedule of a job.
+func (db *dbbase) UpdateJob(ctx context.Context, job model.Job) error {
+	ub := db.Flavor().NewUpdateBuilder()
+	ub.Update("job")
+	ub.Set(
+		ub.Assign("status", job.Status),
+		ub.Assign("attempts", job.Attempts),
+		ub.Assign("last_error", job.LastError),
+		ub.Assign("run_at", job.RunAt),
+		ub.Assign("updated_at", time.Now().UTC().Format(model.DatabaseDateFormat)),
+	)
+	ub.Where(ub.Equal("id", job.ID))
+
+	query, args := ub.Build()
+	query = db.WriterDB().Rebind(query)
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to update job: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListJobs fetch jobs from the queue, newest first.
+func (db *dbbase) ListJobs(ctx context.Context, opts model.DBListJobsOptions) ([]model.Job, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select(jobColumns...)
+	sb.From("job")
+
+	if len(opts.Status) > 0 {
+		statuses := make([]interface{}, len(opts.Status))
+		for i, status := range opts.Status {
+			statuses[i] = status
+		}
+		sb.Where(sb.In("status", statuses...))
+	}
+
+	sb.OrderBy("id").Desc()
+
+	if opts.Limit > 0 {
+		sb.Limit(opts.Limit)
+	}
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	jobs := []model.Job{}
+	if err := db.ReaderDB().SelectContext(ctx, &jobs, query, args...); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// RequeueRunningJobs moves the jobs left running by a stopped process back to pending.
+func (db *dbbase) RequeueRunningJobs(ctx context.Context) error {
+	ub := db.Flavor().NewUpdateBuilder()
+	ub.Update("job")
+	ub.Set(
+		ub.Assign("status", model.JobStatusPending),
+		ub.Assign("updated_at", time.Now().UTC().Format(model.DatabaseDateFormat)),
+	)
+	ub.Where(ub.Equal("status", model.JobStatusRunning))
+
+	query, args := ub.Build()
+	query = db.WriterDB().Rebind(query)
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to requeue running jobs: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,139 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func testCreateJob(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	job, err := db.CreateJob(ctx, model.Job{
+		Type:        model.JobTypeProcessBookmark,
+		Payload:     `{"bookmark_id":1}`,
+		MaxAttempts: 3,
+	})
+	require.NoError(t, err)
+	require.NotZero(t, job.ID)
+	require.Equal(t, model.JobStatusPending, job.Status)
+	require.NotEmpty(t, job.RunAt)
+
+	jobs, err := db.ListJobs(ctx, model.DBListJobsOptions{})
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.Equal(t, job.ID, jobs[0].ID)
+	require.Equal(t, model.JobTypeProcessBookmark, jobs[0].Type)
+	require.Equal(t, `{"bookmark_id":1}`, jobs[0].Payload)
+	require.Equal(t, 3, jobs[0].MaxAttempts)
+}
+
+func testClaimJob(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	t.Run("empty queue", func(t *testing.T) {
+		job, err := db.ClaimJob(ctx)
+		require.NoError(t, err)
+		require.Nil(t, job)
+	})
+
+	first, err := db.CreateJob(ctx, model.Job{Type: model.JobTypeProcessBookmark, Payload: "{}", MaxAttempts: 1})
+	require.NoError(t, err)
+	second, err := db.CreateJob(ctx, model.Job{Type: model.JobTypeProcessBookmark, Payload: "{}", MaxAttempts: 1})
+	require.NoError(t, err)
+	_, err = db.CreateJob(ctx, model.Job{
+		Type:        model.JobTypeProcessBookmark,
+		Payload:     "{}",
+		MaxAttempts: 1,
+		RunAt:       time.Now().UTC().Add(time.Hour).Format(model.DatabaseDateFormat),
+	})
+	require.NoError(t, err)
+
+	t.Run("oldest job first", func(t *testing.T) {
+		job, err := db.ClaimJob(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, job)
+		require.Equal(t, first.ID, job.ID)
+		require.Equal(t, model.JobStatusRunning, job.Status)
+
+		job, err = db.ClaimJob(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, job)
+		require.Equal(t, second.ID, job.ID)
+	})
+
+	t.Run("jobs scheduled later are skipped", func(t *testing.T) {
+		job, err := db.ClaimJob(ctx)
+		require.NoError(t, err)
+		require.Nil(t, job)
+	})
+
+	t.Run("running jobs are requeued", func(t *testing.T) {
+		require.NoError(t, db.RequeueRunningJobs(ctx))
+
+		pending, err := db.ListJobs(ctx, model.DBListJobsOptions{
+			Status: []model.JobStatus{model.JobStatusPending},
+		})
+		require.NoError(t, err)
+		require.Len(t, pending, 3)
+
+		job, err := db.ClaimJob(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, job)
+		require.Equal(t, first.ID, job.ID)
+	})
+}
+
+func testUpdateJob(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	job, err := db.CreateJob(ctx, model.Job{Type: model.JobTypeProcessBookmark, Payload: "{}", MaxAttempts: 3})
+	require.NoError(t, err)
+
+	job.Status = model.JobStatusFailed
+	job.Attempts = 3
+	job.LastError = "connection refused"
+	require.NoError(t, db.UpdateJob(ctx, *job))
+
+	jobs, err := db.ListJobs(ctx, model.DBListJobsOptions{
+		Status: []model.JobStatus{model.JobStatusFailed},
+	})
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.Equal(t, 3, jobs[0].Attempts)
+	require.Equal(t, "connection refused", jobs[0].LastError)
+}
+
+func testListJobs(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	for _, status := range []model.JobStatus{model.JobStatusPending, model.JobStatusCompleted, model.JobStatusFailed} {
+		_, err := db.CreateJob(ctx, model.Job{Type: model.JobTypeProcessBookmark, Payload: "{}", Status: status, MaxAttempts: 1})
+		require.NoError(t, err)
+	}
+
+	t.Run("newest first", func(t *testing.T) {
+		jobs, err := db.ListJobs(ctx, model.DBListJobsOptions{})
+		require.NoError(t, err)
+		require.Len(t, jobs, 3)
+		require.Equal(t, model.JobStatusFailed, jobs[0].Status)
+		require.Equal(t, model.JobStatusPending, jobs[2].Status)
+	})
+
+	t.Run("filter by status", func(t *testing.T) {
+		jobs, err := db.ListJobs(ctx, model.DBListJobsOptions{
+			Status: []model.JobStatus{model.JobStatusPending, model.JobStatusFailed},
+		})
+		require.NoError(t, err)
+		require.Len(t, jobs, 2)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		jobs, err := db.ListJobs(ctx, model.DBListJobsOptions{Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+	})
+}
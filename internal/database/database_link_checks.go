@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// latestLinkChecks selects the bookmarks through their most recent link check, aliased lc
+const latestLinkChecks = `SELECT lc.bookmark_id FROM link_check lc
+	WHERE lc.id IN (SELECT MAX(id) FROM link_check GROUP BY bookmark_id)`
+
+// linkStatusCondition returns the condition matching the bookmarks whose id column has the
+// given link status, empty if there is nothing to filter.
+func linkStatusCondition(idColumn string, status model.LinkStatus) string {
+	switch status {
+	case model.LinkStatusBroken:
+		return idColumn + ` IN (` + latestLinkChecks + `
+			AND (lc.status_code = 0 OR lc.status_code >= 400))`
+	case model.LinkStatusRedirected:
+		return idColumn + ` IN (` + latestLinkChecks + `
+			AND lc.status_code > 0 AND lc.status_code < 400
+			AND lc.final_url <> '' AND lc.final_url <> lc.url)`
+	case model.LinkStatusOK:
+		return idColumn + ` IN (` + latestLinkChecks + `
+			AND lc.status_code > 0 AND lc.status_code < 400
+			AND (lc.final_url = '' OR lc.final_url = lc.url))`
+	case model.LinkStatusUnchecked:
+		return idColumn + ` NOT IN (SELECT bookmark_id FROM link_check)`
+	}
+	return ""
+}
+
+// GetLinkChecks fetch the link checks of a bookmark, newest first.
+func (db *dbbase) GetLinkChecks(ctx context.Context, bookmarkID int, limit int) ([]model.LinkCheck, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select("id", "bookmark_id", "url", "status_code", "final_url", "latency_ms", "error", "checked_at")
+	sb.From("link_check")
+	sb.Where(sb.Equal("bookmark_id", bookmarkID))
+	sb.OrderBy("id DESC")
+	if limit > 0 {
+		sb.Limit(limit)
+	}
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	checks := []model.LinkCheck{}
+	if err := db.ReaderDB().SelectContext(ctx, &checks, query, args...); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get link checks: %w", err)
+	}
+
+	return checks, nil
+}
+
+// PruneLinkChecks removes the link checks of a bookmark except the newest keep ones.
+func (db *dbbase) PruneLinkChecks(ctx context.Context, bookmarkID int, keep int) error {
+	checks, err := db.GetLinkChecks(ctx, bookmarkID, 0)
+	if err != nil {
+		return err
+	}
+
+	if len(checks) <= keep {
+		return nil
+	}
+
+	ids := make([]interface{}, 0, len(checks)-keep)
+	for _, check := range checks[keep:] {
+		ids = append(ids, check.ID)
+	}
+
+	dlb := db.Flavor().NewDeleteBuilder()
+	dlb.DeleteFrom("link_check")
+	dlb.Where(dlb.In("id", ids...))
+
+	query, args := dlb.Build()
+	query = db.WriterDB().Rebind(query)
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to prune link checks: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,115 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func testCreateLinkCheck(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	result, err := db.SaveBookmarks(ctx, true, model.BookmarkDTO{URL: "https://github.com/go-shiori/shiori", Title: "shiori"})
+	require.NoError(t, err)
+	book := result[0]
+
+	check, err := db.CreateLinkCheck(ctx, model.LinkCheck{
+		BookmarkID: book.ID,
+		URL:        book.URL,
+		StatusCode: 200,
+		FinalURL:   book.URL,
+		LatencyMs:  120,
+	})
+	require.NoError(t, err)
+	require.NotZero(t, check.ID)
+	require.NotEmpty(t, check.CheckedAt)
+
+	checks, err := db.GetLinkChecks(ctx, book.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, checks, 1)
+	require.Equal(t, 200, checks[0].StatusCode)
+	require.Equal(t, int64(120), checks[0].LatencyMs)
+}
+
+func testPruneLinkChecks(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	result, err := db.SaveBookmarks(ctx, true, model.BookmarkDTO{URL: "https://github.com/go-shiori/shiori", Title: "shiori"})
+	require.NoError(t, err)
+	book := result[0]
+
+	for _, status := range []int{500, 404, 200} {
+		_, err := db.CreateLinkCheck(ctx, model.LinkCheck{BookmarkID: book.ID, URL: book.URL, StatusCode: status})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, db.PruneLinkChecks(ctx, book.ID, 2))
+
+	checks, err := db.GetLinkChecks(ctx, book.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, checks, 2)
+	require.Equal(t, 200, checks[0].StatusCode)
+	require.Equal(t, 404, checks[1].StatusCode)
+
+	t.Run("removed with the bookmark", func(t *testing.T) {
+		require.NoError(t, db.DeleteBookmarks(ctx, 0, book.ID))
+
+		checks, err := db.GetLinkChecks(ctx, book.ID, 0)
+		require.NoError(t, err)
+		require.Empty(t, checks)
+	})
+}
+
+func testGetBookmarksByLinkStatus(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	result, err := db.SaveBookmarks(ctx, true,
+		model.BookmarkDTO{URL: "https://example.com/ok", Title: "ok"},
+		model.BookmarkDTO{URL: "https://example.com/broken", Title: "broken"},
+		model.BookmarkDTO{URL: "https://example.com/redirected", Title: "redirected"},
+		model.BookmarkDTO{URL: "https://example.com/unchecked", Title: "unchecked"},
+		model.BookmarkDTO{URL: "https://example.com/fixed", Title: "fixed"},
+	)
+	require.NoError(t, err)
+
+	checks := []model.LinkCheck{
+		{BookmarkID: result[0].ID, URL: result[0].URL, StatusCode: 200, FinalURL: result[0].URL},
+		{BookmarkID: result[1].ID, URL: result[1].URL, Error: "connection refused"},
+		{BookmarkID: result[2].ID, URL: result[2].URL, StatusCode: 200, FinalURL: "https://example.org/"},
+		// Only the latest check counts
+		{BookmarkID: result[4].ID, URL: result[4].URL, StatusCode: 500},
+		{BookmarkID: result[4].ID, URL: result[4].URL, StatusCode: 200, FinalURL: result[4].URL},
+	}
+	for _, check := range checks {
+		_, err := db.CreateLinkCheck(ctx, check)
+		require.NoError(t, err)
+	}
+
+	cases := map[model.LinkStatus][]string{
+		model.LinkStatusOK:         {"ok", "fixed"},
+		model.LinkStatusBroken:     {"broken"},
+		model.LinkStatusRedirected: {"redirected"},
+		model.LinkStatusUnchecked:  {"unchecked"},
+	}
+
+	for status, titles := range cases {
+		t.Run(string(status), func(t *testing.T) {
+			opts := model.DBGetBookmarksOptions{LinkStatus: status}
+
+			bookmarks, err := db.GetBookmarks(ctx, opts)
+			require.NoError(t, err)
+
+			found := []string{}
+			for _, bookmark := range bookmarks {
+				found = append(found, bookmark.Title)
+			}
+			require.ElementsMatch(t, titles, found)
+
+			count, err := db.GetBookmarksCount(ctx, opts)
+			require.NoError(t, err)
+			require.Equal(t, len(titles), count)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+var savedSearchColumns = []string{"id", "account_id", "name", "filter", "created_at", "modified_at"}
+
+// GetSavedSearch fetch a saved search by its ID.
+func (db *dbbase) GetSavedSearch(ctx context.Context, id model.DBID) (*model.SavedSearch, bool, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select(savedSearchColumns...)
+	sb.From("saved_search")
+	sb.Where(sb.Equal("id", id))
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	search := model.SavedSearch{}
+	if err := db.ReaderDB().GetContext(ctx, &search, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to get saved search: %w", err)
+	}
+
+	return &search, true, nil
+}
+
+// ListSavedSearches fetch the saved searches of an account, or of every account if zero,
+// ordered by name.
+func (db *dbbase) ListSavedSearches(ctx context.Context, accountID model.DBID) ([]model.SavedSearch, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select(savedSearchColumns...)
+	sb.From("saved_search")
+	if accountID > 0 {
+		sb.Where(sb.Equal("account_id", accountID))
+	}
+	sb.OrderBy("name ASC", "id ASC")
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	searches := []model.SavedSearch{}
+	if err := db.ReaderDB().SelectContext(ctx, &searches, query, args...); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to list saved searches: %w", err)
+	}
+
+	return searches, nil
+}
+
+// UpdateSavedSearch saves the name and filter of a saved search.
+func (db *dbbase) UpdateSavedSearch(ctx context.Context, search model.SavedSearch) error {
+	ub := db.Flavor().NewUpdateBuilder()
+	ub.Update("saved_search")
+	ub.Set(
+		ub.Assign("name", search.Name),
+		ub.Assign("filter", search.Filter),
+		ub.Assign("modified_at", time.Now().UTC().Format(model.DatabaseDateFormat)),
+	)
+	ub.Where(ub.Equal("id", search.ID))
+
+	query, args := ub.Build()
+	query = db.WriterDB().Rebind(query)
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to update saved search: %w", err)
+		}
+		return nil
+	})
+}
+
+// DeleteSavedSearch removes a saved search. ErrNotFound if it doesn't exist.
+func (db *dbbase) DeleteSavedSearch(ctx context.Context, id model.DBID) error {
+	dlb := db.Flavor().NewDeleteBuilder()
+	dlb.DeleteFrom("saved_search")
+	dlb.Where(dlb.Equal("id", id))
+
+	query, args := dlb.Build()
+	query = db.WriterDB().Rebind(query)
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to delete saved search: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rows == 0 {
+			return ErrNotFound
+		}
+
+		return nil
+	})
+}
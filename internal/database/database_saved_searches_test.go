@@ -0,0 +1,94 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func testSavedSearches(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	account, err := db.CreateAccount(ctx, model.Account{Username: "searcher", Password: "hash"})
+	require.NoError(t, err)
+
+	search, err := db.CreateSavedSearch(ctx, model.SavedSearch{
+		AccountID: account.ID,
+		Name:      "Recent Go",
+		Filter: model.SearchFilter{
+			Tags:            []string{"go"},
+			ExcludedTags:    []string{"video"},
+			CollectionID:    model.Ptr(model.DBID(0)),
+			AddedWithinDays: 30,
+		},
+	})
+	require.NoError(t, err)
+	require.NotZero(t, search.ID)
+
+	_, err = db.CreateSavedSearch(ctx, model.SavedSearch{AccountID: account.ID, Name: "Recent Go"})
+	require.Error(t, err, "names are unique per account")
+
+	_, err = db.CreateSavedSearch(ctx, model.SavedSearch{AccountID: account.ID + 1, Name: "Recent Go"})
+	require.NoError(t, err)
+
+	saved, exists, err := db.GetSavedSearch(ctx, search.ID)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, "Recent Go", saved.Name)
+	require.Equal(t, search.Filter, saved.Filter)
+
+	t.Run("list by account", func(t *testing.T) {
+		searches, err := db.ListSavedSearches(ctx, account.ID)
+		require.NoError(t, err)
+		require.Len(t, searches, 1)
+
+		searches, err = db.ListSavedSearches(ctx, 0)
+		require.NoError(t, err)
+		require.Len(t, searches, 2)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		saved.Name = "Broken links"
+		saved.Filter = model.SearchFilter{LinkStatus: model.LinkStatusBroken}
+		require.NoError(t, db.UpdateSavedSearch(ctx, *saved))
+
+		updated, _, err := db.GetSavedSearch(ctx, search.ID)
+		require.NoError(t, err)
+		require.Equal(t, "Broken links", updated.Name)
+		require.Equal(t, saved.Filter, updated.Filter)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, db.DeleteSavedSearch(ctx, search.ID))
+		require.ErrorIs(t, db.DeleteSavedSearch(ctx, search.ID), ErrNotFound)
+
+		_, exists, err := db.GetSavedSearch(ctx, search.ID)
+		require.NoError(t, err)
+		require.False(t, exists)
+	})
+}
+
+func testGetBookmarksAddedAfter(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	now := time.Now().UTC()
+	_, err := db.SaveBookmarks(ctx, true,
+		model.BookmarkDTO{URL: "https://example.com/old", Title: "Old", CreatedAt: now.AddDate(0, 0, -60).Format(model.DatabaseDateFormat)},
+		model.BookmarkDTO{URL: "https://example.com/new", Title: "New", CreatedAt: now.AddDate(0, 0, -2).Format(model.DatabaseDateFormat)},
+	)
+	require.NoError(t, err)
+
+	opts := model.DBGetBookmarksOptions{AddedAfter: now.AddDate(0, 0, -30)}
+
+	bookmarks, err := db.GetBookmarks(ctx, opts)
+	require.NoError(t, err)
+	require.Len(t, bookmarks, 1)
+	require.Equal(t, "https://example.com/new", bookmarks[0].URL)
+
+	count, err := db.GetBookmarksCount(ctx, opts)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
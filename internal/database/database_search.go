@@ -0,0 +1,180 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-shiori/shiori/internal/model"
+)
+
+// searchDialect describes how each database compiles a search query: the bookmark columns and
+// the condition matching text, which uses the full text index of the database if it has one.
+type searchDialect struct {
+	ID        string
+	URL       string
+	Public    string
+	CreatedAt string
+	// HasContent is the condition matching the bookmarks whose content was downloaded
+	HasContent string
+	// Text returns the condition matching the bookmarks containing the text, bind adds an
+	// argument to the query and returns its placeholder
+	Text func(term model.SearchTerm, bind func(value interface{}) string) string
+	// ArchiveText returns the condition matching the bookmarks whose archive text contains the
+	// text, searched with in:archive
+	ArchiveText func(term model.SearchTerm, bind func(value interface{}) string) string
+	// Rank returns the order of the bookmarks from the best match of the text terms to the worst
+	Rank func(terms []model.SearchTerm, bind func(value interface{}) string) string
+}
+
+// searchLikeEscape escapes the wildcards of LIKE patterns, with `ESCAPE '!'` since the
+// databases don't agree on a default escape character.
+var searchLikeEscape = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// bindArg returns a bind function of positional arguments appended to args.
+func bindArg(args *[]interface{}) func(value interface{}) string {
+	return func(value interface{}) string {
+		*args = append(*args, value)
+		return "?"
+	}
+}
+
+// bindNamedArg returns a bind function of named arguments added to arg.
+func bindNamedArg(arg map[string]interface{}) func(value interface{}) string {
+	return func(value interface{}) string {
+		name := fmt.Sprintf("search%d", len(arg))
+		arg[name] = value
+		return ":" + name
+	}
+}
+
+// searchCondition parses the keyword as a search query and returns the condition matching the
+// bookmarks every one of its terms matches, empty if there is nothing to filter.
+func (db *dbbase) searchCondition(ctx context.Context, keyword string, dialect searchDialect, bind func(value interface{}) string) (string, error) {
+	query, err := model.ParseSearchQuery(keyword)
+	if err != nil {
+		return "", err
+	}
+
+	conditions := []string{}
+	includedTags, excludedTags := []string{}, []string{}
+	inArchive := query.InArchive()
+	for _, term := range query.Terms {
+		if term.Field == model.SearchFieldIn {
+			continue
+		}
+
+		if term.Field == model.SearchFieldTag && term.Value != "*" {
+			if term.Negated {
+				excludedTags = append(excludedTags, term.Value)
+			} else {
+				includedTags = append(includedTags, term.Value)
+			}
+			continue
+		}
+
+		condition := searchTermCondition(term, dialect, bind)
+		if term.Field == model.SearchFieldText && inArchive {
+			condition = `(` + condition + ` OR ` + dialect.ArchiveText(term, bind) + `)`
+		}
+		if term.Negated {
+			condition = `NOT (` + condition + `)`
+		}
+		conditions = append(conditions, condition)
+	}
+
+	// Tags are matched with their aliases and children
+	tagsCondition, err := db.tagsCondition(ctx, dialect.ID, includedTags, excludedTags)
+	if err != nil {
+		return "", err
+	}
+	if tagsCondition != "" {
+		conditions = append(conditions, tagsCondition)
+	}
+
+	return strings.Join(conditions, ` AND `), nil
+}
+
+// searchTermCondition returns the condition matching the bookmarks a term matches, ignoring
+// its negation.
+func searchTermCondition(term model.SearchTerm, dialect searchDialect, bind func(value interface{}) string) string {
+	switch term.Field {
+	case model.SearchFieldSite:
+		// The host is followed by the end of the URL, its path or its port
+		host := searchLikeEscape.Replace(term.Value)
+		patterns := []string{}
+		for _, prefix := range []string{"%://", "%."} {
+			for _, suffix := range []string{"", "/%", ":%"} {
+				patterns = append(patterns, fmt.Sprintf(`LOWER(%s) LIKE %s ESCAPE '!'`, dialect.URL, bind(prefix+host+suffix)))
+			}
+		}
+		return `(` + strings.Join(patterns, ` OR `) + `)`
+	case model.SearchFieldTag:
+		// Only tag:* is left to compile, matching the bookmarks with any tag
+		return dialect.ID + ` IN (SELECT DISTINCT bookmark_id FROM bookmark_tag)`
+	case model.SearchFieldIs:
+		if term.Value == "private" {
+			return dialect.Public + ` = 0`
+		}
+		return dialect.Public + ` = 1`
+	case model.SearchFieldHas:
+		switch term.Value {
+		case "archive":
+			return dialect.ID + ` IN (SELECT bookmark_id FROM archive_snapshot)`
+		case "tags":
+			return dialect.ID + ` IN (SELECT DISTINCT bookmark_id FROM bookmark_tag)`
+		}
+		return dialect.HasContent
+	case model.SearchFieldBefore:
+		return fmt.Sprintf(`%s < '%s'`, dialect.CreatedAt, term.Date.Format(model.DatabaseDateFormat))
+	case model.SearchFieldAfter:
+		return fmt.Sprintf(`%s >= '%s'`, dialect.CreatedAt, term.Date.Format(model.DatabaseDateFormat))
+	}
+
+	return `(` + dialect.Text(term, bind) + `)`
+}
+
+// searchOrder returns the order of the bookmarks by relevance to the text of the keyword, then
+// by the fallback order, which is the whole order if there is no text to search.
+func searchOrder(keyword string, dialect searchDialect, bind func(value interface{}) string, fallback string) string {
+	// An invalid keyword fails the search condition before
+	query, _ := model.ParseSearchQuery(keyword)
+	terms := query.TextTerms()
+	if len(terms) == 0 {
+		return fallback
+	}
+
+	return dialect.Rank(terms, bind) + `, ` + fallback
+}
+
+// searchSnippetQuery parses the keyword, and returns whether the bookmarks found get snippets
+// of the text searched.
+func searchSnippetQuery(keyword string) (model.SearchQuery, bool) {
+	query, err := model.ParseSearchQuery(keyword)
+	if err != nil {
+		return query, false
+	}
+	return query, len(query.TextTerms()) > 0
+}
+
+// setSearchSnippets sets the snippets of the bookmarks from their content, excerpt or title,
+// which need to be loaded. With in:archive the text of their archive comes after them.
+func (db *dbbase) setSearchSnippets(ctx context.Context, bookmarks []model.BookmarkDTO, query model.SearchQuery) error {
+	archiveTexts := map[int]string{}
+	if query.InArchive() {
+		ids := make([]int, len(bookmarks))
+		for i, book := range bookmarks {
+			ids[i] = book.ID
+		}
+
+		var err error
+		if archiveTexts, err = db.GetArchiveTexts(ctx, ids); err != nil {
+			return err
+		}
+	}
+
+	for i := range bookmarks {
+		bookmarks[i].Snippet = query.Snippet(bookmarks[i].Content, bookmarks[i].Excerpt, bookmarks[i].Title, archiveTexts[bookmarks[i].ID])
+	}
+	return nil
+}
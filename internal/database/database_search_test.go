@@ -0,0 +1,178 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func testGetBookmarksWithSearchQuery(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	saved, err := db.SaveBookmarks(ctx, true,
+		model.BookmarkDTO{
+			URL:       "https://github.com/go-shiori/shiori",
+			Title:     "Shiori",
+			Excerpt:   "Simple bookmark manager",
+			Public:    1,
+			CreatedAt: "2024-03-01 10:00:00",
+			Tags:      []model.TagDTO{{Tag: model.Tag{Name: "go"}}},
+		},
+		model.BookmarkDTO{
+			URL:       "https://gist.github.com/someone/1",
+			Title:     "Old gist",
+			Excerpt:   "An exact phrase in the excerpt",
+			CreatedAt: "2023-06-01 10:00:00",
+			Tags:      []model.TagDTO{{Tag: model.Tag{Name: "go"}}, {Tag: model.Tag{Name: "old"}}},
+		},
+		model.BookmarkDTO{
+			URL:       "https://notgithub.com/page",
+			Title:     "Lookalike",
+			Excerpt:   "Phrase exact, in another order",
+			CreatedAt: "2024-05-01 10:00:00",
+		},
+	)
+	require.NoError(t, err)
+
+	_, err = db.CreateArchiveSnapshot(ctx, model.ArchiveSnapshot{
+		BookmarkID: saved[1].ID,
+		Path:       model.GetArchivePath(&saved[1]),
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "site:github.com", want: []string{"Shiori", "Old gist"}},
+		{query: "site:GITHUB.com -site:gist.github.com", want: []string{"Shiori"}},
+		{query: "tag:go -tag:old", want: []string{"Shiori"}},
+		{query: "-tag:*", want: []string{"Lookalike"}},
+		{query: "is:public", want: []string{"Shiori"}},
+		{query: "is:private has:tags", want: []string{"Old gist"}},
+		{query: "has:archive", want: []string{"Old gist"}},
+		{query: "before:2024-01-01", want: []string{"Old gist"}},
+		{query: "after:2024-03-01 before:2024-05-01", want: []string{"Shiori"}},
+		{query: `"exact phrase"`, want: []string{"Old gist"}},
+		{query: "tag:go before:2024-01-01 has:archive", want: []string{"Old gist"}},
+		{query: "tag:unknown", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			opts := model.DBGetBookmarksOptions{Keyword: tt.query}
+
+			bookmarks, err := db.GetBookmarks(ctx, opts)
+			require.NoError(t, err)
+
+			titles := []string{}
+			for _, bookmark := range bookmarks {
+				titles = append(titles, bookmark.Title)
+			}
+			require.ElementsMatch(t, tt.want, titles)
+
+			count, err := db.GetBookmarksCount(ctx, opts)
+			require.NoError(t, err)
+			require.Equal(t, len(tt.want), count)
+		})
+	}
+
+	_, err = db.GetBookmarks(ctx, model.DBGetBookmarksOptions{Keyword: "before:yesterday"})
+	require.ErrorAs(t, err, &model.ValidationError{})
+}
+
+func testGetBookmarksByRelevance(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	_, err := db.SaveBookmarks(ctx, true,
+		model.BookmarkDTO{
+			URL:     "https://example.com/gophers",
+			Title:   "Gophers",
+			Content: "Gophers everywhere: gophers digging, gophers eating, gophers sleeping.",
+		},
+		model.BookmarkDTO{
+			URL:     "https://example.com/mention",
+			Title:   "Weekly news",
+			Content: "Among other things, a short note about gophers.",
+		},
+		model.BookmarkDTO{
+			URL:     "https://example.com/other",
+			Title:   "Unrelated",
+			Content: "Nothing to see here.",
+		},
+	)
+	require.NoError(t, err)
+
+	// The best match is the oldest bookmark
+	bookmarks, err := db.GetBookmarks(ctx, model.DBGetBookmarksOptions{
+		Keyword:     "gophers",
+		OrderMethod: model.ByRelevance,
+	})
+	require.NoError(t, err)
+	require.Len(t, bookmarks, 2)
+	require.Equal(t, "Gophers", bookmarks[0].Title)
+	require.Equal(t, "Weekly news", bookmarks[1].Title)
+	require.Equal(t, "Among other things, a short note about <mark>gophers</mark>.", bookmarks[1].Snippet)
+	require.Empty(t, bookmarks[1].Content, "content is only loaded for the snippets")
+
+	bookmarks, err = db.GetBookmarks(ctx, model.DBGetBookmarksOptions{
+		Keyword:     "gophers",
+		OrderMethod: model.ByRelevance,
+		WithContent: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, bookmarks, 2)
+	require.NotEmpty(t, bookmarks[0].Content)
+
+	// Without text to search, the newest bookmarks come first
+	bookmarks, err = db.GetBookmarks(ctx, model.DBGetBookmarksOptions{
+		Keyword:     "site:example.com",
+		OrderMethod: model.ByRelevance,
+	})
+	require.NoError(t, err)
+	require.Len(t, bookmarks, 3)
+	require.Equal(t, "Unrelated", bookmarks[0].Title)
+	require.Empty(t, bookmarks[0].Snippet)
+}
+
+func testGetBookmarksInArchive(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	result, err := db.SaveBookmarks(ctx, true,
+		model.BookmarkDTO{
+			URL:   "https://example.com/unreadable",
+			Title: "Unreadable page",
+		},
+		model.BookmarkDTO{
+			URL:     "https://example.com/readable",
+			Title:   "Readable page",
+			Content: "An article about gopher habits.",
+		},
+	)
+	require.NoError(t, err)
+	require.NoError(t, db.SaveArchiveText(ctx, result[0].ID, "Menu Home A table of gopher species Footer"))
+
+	titles := func(keyword string) []string {
+		bookmarks, err := db.GetBookmarks(ctx, model.DBGetBookmarksOptions{Keyword: keyword})
+		require.NoError(t, err)
+
+		titles := []string{}
+		for _, book := range bookmarks {
+			titles = append(titles, book.Title)
+		}
+		return titles
+	}
+
+	require.Empty(t, titles("species"), "archives are only searched with in:archive")
+	require.Equal(t, []string{"Unreadable page"}, titles("species in:archive"))
+	require.Equal(t, []string{"Readable page"}, titles("gopher"))
+	require.ElementsMatch(t, []string{"Unreadable page", "Readable page"}, titles("in:archive gopher"))
+	require.Equal(t, []string{"Readable page"}, titles("-species in:archive"))
+
+	bookmarks, err := db.GetBookmarks(ctx, model.DBGetBookmarksOptions{Keyword: `"gopher species" in:archive`})
+	require.NoError(t, err)
+	require.Len(t, bookmarks, 1)
+	require.Equal(t, "Menu Home A table of <mark>gopher species</mark> Footer", bookmarks[0].Snippet)
+}
@@ -0,0 +1,142 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+var sessionColumns = []string{"id", "account_id", "user_agent", "expires_at", "last_used_at", "created_at"}
+
+// CreateSession stores a new login session. Its ID is generated by the caller.
+func (db *dbbase) CreateSession(ctx context.Context, session model.Session) (*model.Session, error) {
+	if session.CreatedAt == "" {
+		session.CreatedAt = time.Now().UTC().Format(model.DatabaseDateFormat)
+	}
+
+	ib := db.Flavor().NewInsertBuilder()
+	ib.InsertInto("session")
+	ib.Cols("id", "account_id", "user_agent", "expires_at", "created_at")
+	ib.Values(session.ID, session.AccountID, session.UserAgent, session.ExpiresAt, session.CreatedAt)
+
+	query, args := ib.Build()
+	query = db.WriterDB().Rebind(query)
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to insert session: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// GetSession fetch a login session by its ID.
+func (db *dbbase) GetSession(ctx context.Context, id string) (*model.Session, bool, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select(sessionColumns...)
+	sb.From("session")
+	sb.Where(sb.Equal("id", id))
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	session := model.Session{}
+	if err := db.ReaderDB().GetContext(ctx, &session, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	return &session, true, nil
+}
+
+// ListSessions fetch the login sessions of an account, newest first.
+func (db *dbbase) ListSessions(ctx context.Context, accountID model.DBID) ([]model.Session, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select(sessionColumns...)
+	sb.From("session")
+	sb.Where(sb.Equal("account_id", accountID))
+	sb.OrderBy("created_at DESC", "id")
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	sessions := []model.Session{}
+	if err := db.ReaderDB().SelectContext(ctx, &sessions, query, args...); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// UpdateSessionLastUsed records when a login session was last used.
+func (db *dbbase) UpdateSessionLastUsed(ctx context.Context, id string, lastUsedAt string) error {
+	ub := db.Flavor().NewUpdateBuilder()
+	ub.Update("session")
+	ub.Set(ub.Assign("last_used_at", lastUsedAt))
+	ub.Where(ub.Equal("id", id))
+
+	query, args := ub.Build()
+	query = db.WriterDB().Rebind(query)
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to update session: %w", err)
+		}
+		return nil
+	})
+}
+
+// DeleteSession removes a login session of an account, ErrNotFound if it doesn't exist.
+func (db *dbbase) DeleteSession(ctx context.Context, accountID model.DBID, id string) error {
+	dlb := db.Flavor().NewDeleteBuilder()
+	dlb.DeleteFrom("session")
+	dlb.Where(dlb.Equal("id", id), dlb.Equal("account_id", accountID))
+
+	query, args := dlb.Build()
+	query = db.WriterDB().Rebind(query)
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to delete session: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rows == 0 {
+			return ErrNotFound
+		}
+
+		return nil
+	})
+}
+
+// DeleteAccountSessions removes all the login sessions of an account.
+func (db *dbbase) DeleteAccountSessions(ctx context.Context, accountID model.DBID) error {
+	dlb := db.Flavor().NewDeleteBuilder()
+	dlb.DeleteFrom("session")
+	dlb.Where(dlb.Equal("account_id", accountID))
+
+	query, args := dlb.Build()
+	query = db.WriterDB().Rebind(query)
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to delete account sessions: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,111 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func testCreateSession(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	session, err := db.CreateSession(ctx, model.Session{
+		ID:        "5f0c2f4e-9d2a-4a55-8f0e-1b2c3d4e5f60",
+		AccountID: 1,
+		UserAgent: "Firefox",
+		ExpiresAt: "2030-01-01 00:00:00",
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, session.CreatedAt)
+
+	saved, exists, err := db.GetSession(ctx, session.ID)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, model.DBID(1), saved.AccountID)
+	require.Equal(t, "Firefox", saved.UserAgent)
+	require.Contains(t, saved.ExpiresAt, "2030-01-01")
+	require.Nil(t, saved.LastUsedAt)
+
+	_, exists, err = db.GetSession(ctx, "unknown")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	require.NoError(t, db.UpdateSessionLastUsed(ctx, session.ID, "2025-01-01 10:00:00"))
+	saved, _, err = db.GetSession(ctx, session.ID)
+	require.NoError(t, err)
+	require.NotNil(t, saved.LastUsedAt)
+	require.Contains(t, *saved.LastUsedAt, "2025-01-01")
+}
+
+func testListSessions(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	first, err := db.CreateAccount(ctx, model.Account{Username: "first", Password: "hash"})
+	require.NoError(t, err)
+	second, err := db.CreateAccount(ctx, model.Account{Username: "second", Password: "hash"})
+	require.NoError(t, err)
+
+	for i, accountID := range []model.DBID{first.ID, first.ID, second.ID} {
+		_, err := db.CreateSession(ctx, model.Session{
+			ID:        fmt.Sprintf("session-%d", i),
+			AccountID: accountID,
+			ExpiresAt: "2030-01-01 00:00:00",
+			CreatedAt: fmt.Sprintf("2025-01-0%d 10:00:00", i+1),
+		})
+		require.NoError(t, err)
+	}
+
+	sessions, err := db.ListSessions(ctx, first.ID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	require.Equal(t, "session-1", sessions[0].ID)
+	require.Equal(t, "session-0", sessions[1].ID)
+
+	t.Run("removed with the account", func(t *testing.T) {
+		require.NoError(t, db.DeleteAccount(ctx, first.ID))
+
+		sessions, err := db.ListSessions(ctx, first.ID)
+		require.NoError(t, err)
+		require.Empty(t, sessions)
+
+		sessions, err = db.ListSessions(ctx, second.ID)
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+	})
+}
+
+func testDeleteSession(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	for _, id := range []string{"session-1", "session-2", "session-3"} {
+		_, err := db.CreateSession(ctx, model.Session{ID: id, AccountID: 1, ExpiresAt: "2030-01-01 00:00:00"})
+		require.NoError(t, err)
+	}
+	_, err := db.CreateSession(ctx, model.Session{ID: "other", AccountID: 2, ExpiresAt: "2030-01-01 00:00:00"})
+	require.NoError(t, err)
+
+	// Sessions of other accounts are not found
+	err = db.DeleteSession(ctx, 2, "session-1")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, db.DeleteSession(ctx, 1, "session-1"))
+
+	_, exists, err := db.GetSession(ctx, "session-1")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	t.Run("all sessions of an account", func(t *testing.T) {
+		require.NoError(t, db.DeleteAccountSessions(ctx, 1))
+
+		sessions, err := db.ListSessions(ctx, 1)
+		require.NoError(t, err)
+		require.Empty(t, sessions)
+
+		_, exists, err := db.GetSession(ctx, "other")
+		require.NoError(t, err)
+		require.True(t, exists)
+	})
+}
@@ -0,0 +1,167 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+var subscriptionColumns = []string{"id", "account_id", "url", "title", "tags", "create_archive", "create_ebook", "last_error", "last_polled_at", "created_at"}
+
+// GetSubscription fetch a feed subscription by its ID.
+func (db *dbbase) GetSubscription(ctx context.Context, id model.DBID) (*model.Subscription, bool, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select(subscriptionColumns...)
+	sb.From("subscription")
+	sb.Where(sb.Equal("id", id))
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	subscription := model.Subscription{}
+	if err := db.ReaderDB().GetContext(ctx, &subscription, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to get subscription: %w", err)
+	}
+
+	return &subscription, true, nil
+}
+
+// ListSubscriptions fetch the feed subscriptions of an account, or of every account if zero,
+// oldest first.
+func (db *dbbase) ListSubscriptions(ctx context.Context, accountID model.DBID) ([]model.Subscription, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select(subscriptionColumns...)
+	sb.From("subscription")
+	if accountID > 0 {
+		sb.Where(sb.Equal("account_id", accountID))
+	}
+	sb.OrderBy("id ASC")
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	subscriptions := []model.Subscription{}
+	if err := db.ReaderDB().SelectContext(ctx, &subscriptions, query, args...); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// UpdateSubscription saves the title, options and poll status of a feed subscription.
+func (db *dbbase) UpdateSubscription(ctx context.Context, subscription model.Subscription) error {
+	ub := db.Flavor().NewUpdateBuilder()
+	ub.Update("subscription")
+	ub.Set(
+		ub.Assign("title", subscription.Title),
+		ub.Assign("tags", subscription.Tags),
+		ub.Assign("create_archive", subscription.CreateArchive),
+		ub.Assign("create_ebook", subscription.CreateEbook),
+		ub.Assign("last_error", subscription.LastError),
+		ub.Assign("last_polled_at", subscription.LastPolledAt),
+	)
+	ub.Where(ub.Equal("id", subscription.ID))
+
+	query, args := ub.Build()
+	query = db.WriterDB().Rebind(query)
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to update subscription: %w", err)
+		}
+		return nil
+	})
+}
+
+// DeleteSubscription removes a feed subscription of an account, or of any account if zero,
+// with its seen items. ErrNotFound if it doesn't exist.
+func (db *dbbase) DeleteSubscription(ctx context.Context, accountID model.DBID, id model.DBID) error {
+	dlb := db.Flavor().NewDeleteBuilder()
+	dlb.DeleteFrom("subscription")
+	dlb.Where(dlb.Equal("id", id))
+	if accountID > 0 {
+		dlb.Where(dlb.Equal("account_id", accountID))
+	}
+	deleteQuery, deleteArgs := dlb.Build()
+
+	dlbItems := db.Flavor().NewDeleteBuilder()
+	dlbItems.DeleteFrom("subscription_item")
+	dlbItems.Where(dlbItems.Equal("subscription_id", id))
+	deleteItemsQuery, deleteItemsArgs := dlbItems.Build()
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, tx.Rebind(deleteQuery), deleteArgs...)
+		if err != nil {
+			return fmt.Errorf("failed to delete subscription: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rows == 0 {
+			return ErrNotFound
+		}
+
+		if _, err := tx.ExecContext(ctx, tx.Rebind(deleteItemsQuery), deleteItemsArgs...); err != nil {
+			return fmt.Errorf("failed to delete subscription items: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// GetSubscriptionItems fetch the items already seen of a feed subscription.
+func (db *dbbase) GetSubscriptionItems(ctx context.Context, subscriptionID model.DBID) ([]model.SubscriptionItem, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select("subscription_id", "guid", "bookmark_id", "created_at")
+	sb.From("subscription_item")
+	sb.Where(sb.Equal("subscription_id", subscriptionID))
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	items := []model.SubscriptionItem{}
+	if err := db.ReaderDB().SelectContext(ctx, &items, query, args...); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get subscription items: %w", err)
+	}
+
+	return items, nil
+}
+
+// CreateSubscriptionItems records items of a feed subscription as seen.
+func (db *dbbase) CreateSubscriptionItems(ctx context.Context, items ...model.SubscriptionItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC().Format(model.DatabaseDateFormat)
+
+	ib := db.Flavor().NewInsertBuilder()
+	ib.InsertInto("subscription_item")
+	ib.Cols("subscription_id", "guid", "bookmark_id", "created_at")
+	for _, item := range items {
+		if item.CreatedAt == "" {
+			item.CreatedAt = now
+		}
+		ib.Values(item.SubscriptionID, item.GUID, item.BookmarkID, item.CreatedAt)
+	}
+
+	query, args := ib.Build()
+	query = db.WriterDB().Rebind(query)
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to insert subscription items: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,100 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func testSubscriptions(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	account, err := db.CreateAccount(ctx, model.Account{Username: "subscriber", Password: "hash"})
+	require.NoError(t, err)
+
+	subscription, err := db.CreateSubscription(ctx, model.Subscription{
+		AccountID:     account.ID,
+		URL:           "https://blog.example.com/feed.xml",
+		Tags:          model.SubscriptionTags{"blog", "go"},
+		CreateArchive: true,
+	})
+	require.NoError(t, err)
+	require.NotZero(t, subscription.ID)
+
+	_, err = db.CreateSubscription(ctx, model.Subscription{AccountID: account.ID, URL: "https://other.example.com/atom"})
+	require.NoError(t, err)
+
+	saved, exists, err := db.GetSubscription(ctx, subscription.ID)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, model.SubscriptionTags{"blog", "go"}, saved.Tags)
+	require.True(t, saved.CreateArchive)
+	require.False(t, saved.CreateEbook)
+	require.Nil(t, saved.LastPolledAt)
+
+	saved.Title = "Example blog"
+	saved.LastError = "unexpected status 500"
+	saved.LastPolledAt = model.Ptr("2024-01-01 10:00:00")
+	require.NoError(t, db.UpdateSubscription(ctx, *saved))
+
+	saved, _, err = db.GetSubscription(ctx, subscription.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Example blog", saved.Title)
+	require.Equal(t, "unexpected status 500", saved.LastError)
+	require.NotNil(t, saved.LastPolledAt)
+
+	subscriptions, err := db.ListSubscriptions(ctx, account.ID)
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 2)
+	require.Equal(t, subscription.ID, subscriptions[0].ID)
+
+	t.Run("other accounts can't delete it", func(t *testing.T) {
+		require.ErrorIs(t, db.DeleteSubscription(ctx, account.ID+1, subscription.ID), ErrNotFound)
+	})
+
+	t.Run("deleted", func(t *testing.T) {
+		require.NoError(t, db.DeleteSubscription(ctx, account.ID, subscription.ID))
+
+		_, exists, err := db.GetSubscription(ctx, subscription.ID)
+		require.NoError(t, err)
+		require.False(t, exists)
+
+		require.ErrorIs(t, db.DeleteSubscription(ctx, 0, subscription.ID), ErrNotFound)
+	})
+
+	t.Run("removed with the account", func(t *testing.T) {
+		require.NoError(t, db.DeleteAccount(ctx, account.ID))
+
+		subscriptions, err := db.ListSubscriptions(ctx, account.ID)
+		require.NoError(t, err)
+		require.Empty(t, subscriptions)
+	})
+}
+
+func testSubscriptionItems(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	subscription, err := db.CreateSubscription(ctx, model.Subscription{AccountID: 1, URL: "https://blog.example.com/feed.json"})
+	require.NoError(t, err)
+
+	items, err := db.GetSubscriptionItems(ctx, subscription.ID)
+	require.NoError(t, err)
+	require.Empty(t, items)
+
+	require.NoError(t, db.CreateSubscriptionItems(ctx,
+		model.SubscriptionItem{SubscriptionID: subscription.ID, GUID: "post-1", BookmarkID: 10},
+		model.SubscriptionItem{SubscriptionID: subscription.ID, GUID: "post-2"},
+	))
+
+	items, err = db.GetSubscriptionItems(ctx, subscription.ID)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+
+	// Deleting the subscription forgets its items
+	require.NoError(t, db.DeleteSubscription(ctx, 0, subscription.ID))
+	items, err = db.GetSubscriptionItems(ctx, subscription.ID)
+	require.NoError(t, err)
+	require.Empty(t, items)
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// GetTagAliases fetch the aliases of a tag, or of every tag if zero, ordered by name.
+func (db *dbbase) GetTagAliases(ctx context.Context, tagID int) ([]model.TagAlias, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select("id", "name", "tag_id")
+	sb.From("tag_alias")
+	if tagID > 0 {
+		sb.Where(sb.Equal("tag_id", tagID))
+	}
+	sb.OrderBy("name ASC")
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	aliases := []model.TagAlias{}
+	if err := db.ReaderDB().SelectContext(ctx, &aliases, query, args...); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get tag aliases: %w", err)
+	}
+
+	return aliases, nil
+}
+
+// DeleteTagAlias removes a tag alias. ErrNotFound if it doesn't exist.
+func (db *dbbase) DeleteTagAlias(ctx context.Context, id int) error {
+	dlb := db.Flavor().NewDeleteBuilder()
+	dlb.DeleteFrom("tag_alias")
+	dlb.Where(dlb.Equal("id", id))
+
+	query, args := dlb.Build()
+	query = db.WriterDB().Rebind(query)
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to delete tag alias: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rows == 0 {
+			return ErrNotFound
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,145 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func testTagAliases(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	tag, err := db.CreateTag(ctx, model.Tag{Name: "kubernetes"})
+	require.NoError(t, err)
+
+	alias, err := db.CreateTagAlias(ctx, model.TagAlias{Name: "k8s", TagID: tag.ID})
+	require.NoError(t, err)
+	require.NotZero(t, alias.ID)
+
+	found, exists, err := db.GetTagByName(ctx, "k8s")
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, tag.ID, found.ID)
+
+	_, exists, err = db.GetTagByName(ctx, "docker")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	t.Run("resolved when saving bookmarks", func(t *testing.T) {
+		saved, err := db.SaveBookmarks(ctx, true, model.BookmarkDTO{
+			URL:   "https://kubernetes.io",
+			Title: "Kubernetes",
+			Tags:  []model.TagDTO{{Tag: model.Tag{Name: "K8s"}}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "kubernetes", saved[0].Tags[0].Name)
+
+		bookmarks, err := db.GetBookmarks(ctx, model.DBGetBookmarksOptions{Tags: []string{"kubernetes"}})
+		require.NoError(t, err)
+		require.Len(t, bookmarks, 1)
+
+		count, err := db.GetBookmarksCount(ctx, model.DBGetBookmarksOptions{Tags: []string{"k8s"}})
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+	})
+
+	t.Run("deleted with the tag", func(t *testing.T) {
+		aliases, err := db.GetTagAliases(ctx, tag.ID)
+		require.NoError(t, err)
+		require.Len(t, aliases, 1)
+
+		require.NoError(t, db.DeleteTag(ctx, tag.ID))
+
+		aliases, err = db.GetTagAliases(ctx, 0)
+		require.NoError(t, err)
+		require.Empty(t, aliases)
+		require.ErrorIs(t, db.DeleteTagAlias(ctx, alias.ID), ErrNotFound)
+	})
+}
+
+func testMergeTags(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	saved, err := db.SaveBookmarks(ctx, true,
+		model.BookmarkDTO{URL: "https://go.dev", Title: "Go", Tags: []model.TagDTO{{Tag: model.Tag{Name: "go"}}, {Tag: model.Tag{Name: "golang"}}}},
+		model.BookmarkDTO{URL: "https://pkg.go.dev", Title: "Packages", Tags: []model.TagDTO{{Tag: model.Tag{Name: "golang"}}}},
+		model.BookmarkDTO{URL: "https://gobyexample.com", Title: "Examples", Tags: []model.TagDTO{{Tag: model.Tag{Name: "go-lang"}}}},
+	)
+	require.NoError(t, err)
+
+	target, _, err := db.GetTagByName(ctx, "go")
+	require.NoError(t, err)
+	golang, _, err := db.GetTagByName(ctx, "golang")
+	require.NoError(t, err)
+	goLang, _, err := db.GetTagByName(ctx, "go-lang")
+	require.NoError(t, err)
+
+	child, err := db.CreateTag(ctx, model.Tag{Name: "generics", ParentID: &golang.ID})
+	require.NoError(t, err)
+
+	require.NoError(t, db.MergeTags(ctx, target.ID, golang.ID, goLang.ID))
+
+	merged, exists, err := db.GetTag(ctx, target.ID)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.EqualValues(t, 3, merged.BookmarkCount)
+
+	_, exists, err = db.GetTag(ctx, golang.ID)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	aliases, err := db.GetTagAliases(ctx, target.ID)
+	require.NoError(t, err)
+	require.Len(t, aliases, 2)
+
+	moved, _, err := db.GetTag(ctx, child.ID)
+	require.NoError(t, err)
+	require.Equal(t, &target.ID, moved.ParentID)
+
+	tags, err := db.GetTags(ctx, model.DBListTagsOptions{BookmarkID: saved[0].ID})
+	require.NoError(t, err)
+	require.Len(t, tags, 1)
+
+	// Merged names keep working on input
+	resaved, err := db.SaveBookmarks(ctx, true, model.BookmarkDTO{URL: "https://go.dev/blog", Title: "Blog", Tags: []model.TagDTO{{Tag: model.Tag{Name: "golang"}}}})
+	require.NoError(t, err)
+	require.Equal(t, target.ID, resaved[0].Tags[0].ID)
+}
+
+func testGetBookmarksWithChildTags(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	parent, err := db.CreateTag(ctx, model.Tag{Name: "programming"})
+	require.NoError(t, err)
+	child, err := db.CreateTag(ctx, model.Tag{Name: "go", ParentID: &parent.ID})
+	require.NoError(t, err)
+	_, err = db.CreateTag(ctx, model.Tag{Name: "generics", ParentID: &child.ID})
+	require.NoError(t, err)
+
+	_, err = db.SaveBookmarks(ctx, true,
+		model.BookmarkDTO{URL: "https://go.dev/doc/tutorial/generics", Title: "Generics", Tags: []model.TagDTO{{Tag: model.Tag{Name: "generics"}}}},
+		model.BookmarkDTO{URL: "https://go.dev", Title: "Go", Tags: []model.TagDTO{{Tag: model.Tag{Name: "go"}}}},
+		model.BookmarkDTO{URL: "https://example.com", Title: "Example", Tags: []model.TagDTO{{Tag: model.Tag{Name: "misc"}}}},
+	)
+	require.NoError(t, err)
+
+	count, err := db.GetBookmarksCount(ctx, model.DBGetBookmarksOptions{Tags: []string{"programming"}})
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	bookmarks, err := db.GetBookmarks(ctx, model.DBGetBookmarksOptions{Tags: []string{"programming"}, ExcludedTags: []string{"generics"}})
+	require.NoError(t, err)
+	require.Len(t, bookmarks, 1)
+	require.Equal(t, "Go", bookmarks[0].Title)
+
+	bookmarks, err = db.GetBookmarks(ctx, model.DBGetBookmarksOptions{ExcludedTags: []string{"go"}})
+	require.NoError(t, err)
+	require.Len(t, bookmarks, 1)
+	require.Equal(t, "Example", bookmarks[0].Title)
+
+	count, err = db.GetBookmarksCount(ctx, model.DBGetBookmarksOptions{Tags: []string{"unknown"}})
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/go-shiori/shiori/internal/model"
 	"github.com/huandu/go-sqlbuilder"
@@ -13,31 +15,47 @@ import (
 // GetTags returns a list of tags from the database.
 // If opts.WithBookmarkCount is true, the result will include the number of bookmarks for each tag.
 // If opts.BookmarkID is not 0, the result will include only the tags for the specified bookmark.
+// If opts.AccountID is not 0, tags used only by bookmarks of other accounts are left out and
+// the bookmark count only includes bookmarks of that account.
 // If opts.OrderBy is set, the result will be ordered by the specified column.
 func (db *dbbase) GetTags(ctx context.Context, opts model.DBListTagsOptions) ([]model.TagDTO, error) {
 	sb := db.Flavor().NewSelectBuilder()
 
-	sb.Select("t.id", "t.name")
+	sb.Select("t.id", "t.name", "t.parent_id")
 	sb.From("tag t")
 
 	// Treat the case where we want the bookmark count and filter by bookmark ID as a special case:
 	// If we only want one of them, we can use a JOIN and GROUP BY.
 	// If we want both, we need to use a subquery to get the count of bookmarks for each tag filtered
 	// by bookmark ID.
-	if opts.WithBookmarkCount && opts.BookmarkID == 0 {
+	if opts.AccountID > 0 && opts.BookmarkID == 0 {
+		// Keep the tags used by the account bookmarks and the ones not used by any bookmark yet,
+		// so a freshly created tag can still be found by its creator.
+		sb.JoinWithOption(sqlbuilder.LeftJoin, "bookmark_tag bt", "bt.tag_id = t.id")
+		sb.JoinWithOption(sqlbuilder.LeftJoin, "bookmark b", "b.id = bt.bookmark_id")
+		sb.Where(sb.Or(
+			sb.IsNull("bt.tag_id"),
+			sb.Equal("b.account_id", opts.AccountID),
+		))
+		if opts.WithBookmarkCount {
+			sb.SelectMore("COUNT(bt.tag_id) AS bookmark_count")
+		}
+		sb.GroupBy("t.id", "t.name", "t.parent_id")
+	} else if opts.WithBookmarkCount && opts.BookmarkID == 0 {
 		// Join with bookmark_tag and group by tag ID to get the count of bookmarks for each tag
 		sb.JoinWithOption(sqlbuilder.LeftJoin, "bookmark_tag bt", "bt.tag_id = t.id")
 		sb.SelectMore("COUNT(bt.tag_id) AS bookmark_count")
-		sb.GroupBy("t.id")
+		sb.GroupBy("t.id", "t.name", "t.parent_id")
 	} else if opts.BookmarkID > 0 {
 		// If we want the bookmark count, we need to use a subquery to get the count of bookmarks for each tag
 		if opts.WithBookmarkCount {
-			sb.SelectMore(
-				sb.BuilderAs(
-					db.Flavor().NewSelectBuilder().Select("COUNT(bt2.tag_id)").From("bookmark_tag bt2").Where("bt2.tag_id = t.id"),
-					"bookmark_count",
-				),
-			)
+			countSb := db.Flavor().NewSelectBuilder().Select("COUNT(bt2.tag_id)").From("bookmark_tag bt2")
+			countSb.Where("bt2.tag_id = t.id")
+			if opts.AccountID > 0 {
+				countSb.Join("bookmark b2", "b2.id = bt2.bookmark_id")
+				countSb.Where(countSb.Equal("b2.account_id", opts.AccountID))
+			}
+			sb.SelectMore(sb.BuilderAs(countSb, "bookmark_count"))
 		}
 
 		// Join with bookmark_tag and filter by bookmark ID to get the tags for a specific bookmark
@@ -47,6 +65,12 @@ func (db *dbbase) GetTags(ctx context.Context, opts model.DBListTagsOptions) ([]
 				sb.Equal("bt.bookmark_id", opts.BookmarkID),
 			),
 		)
+		if opts.AccountID > 0 {
+			sb.Join("bookmark b", sb.And(
+				"b.id = bt.bookmark_id",
+				sb.Equal("b.account_id", opts.AccountID),
+			))
+		}
 		sb.Where(sb.IsNotNull("t.id"))
 	}
 
@@ -194,3 +218,176 @@ func (db *dbbase) BookmarkExists(ctx context.Context, bookmarkID int) (bool, err
 
 	return true, nil
 }
+
+// GetTagByName fetch the tag with the given name, or the tag the name is an alias of.
+func (db *dbbase) GetTagByName(ctx context.Context, name string) (model.Tag, bool, error) {
+	query := db.ReaderDB().Rebind(`SELECT t.id, t.name, t.parent_id FROM tag t WHERE t.name = ?
+		UNION
+		SELECT t.id, t.name, t.parent_id FROM tag t JOIN tag_alias a ON a.tag_id = t.id WHERE a.name = ?`)
+
+	tags := []model.Tag{}
+	if err := db.ReaderDB().SelectContext(ctx, &tags, query, name, name); err != nil {
+		return model.Tag{}, false, fmt.Errorf("failed to get tag: %w", err)
+	}
+
+	if len(tags) == 0 {
+		return model.Tag{}, false, nil
+	}
+
+	return tags[0], true, nil
+}
+
+// MergeTags moves the bookmarks, aliases and child tags of the source tags to the target
+// tag, then removes the source tags keeping their names as aliases of the target.
+func (db *dbbase) MergeTags(ctx context.Context, targetID int, sourceIDs ...int) error {
+	if len(sourceIDs) == 0 {
+		return nil
+	}
+
+	sources := make([]any, len(sourceIDs))
+	for i, id := range sourceIDs {
+		sources[i] = id
+	}
+
+	// The target ID is written in the queries, an untyped parameter in a select list is
+	// rejected by some databases
+	copyQuery, copyArgs, err := sqlx.In(fmt.Sprintf(`INSERT INTO bookmark_tag (bookmark_id, tag_id)
+		SELECT DISTINCT bookmark_id, %d FROM bookmark_tag
+		WHERE tag_id IN (?) AND bookmark_id NOT IN (
+			SELECT bookmark_id FROM bookmark_tag WHERE tag_id = ?)`, targetID), sourceIDs, targetID)
+	if err != nil {
+		return fmt.Errorf("failed to build merge query: %w", err)
+	}
+
+	aliasQuery, aliasArgs, err := sqlx.In(fmt.Sprintf(`INSERT INTO tag_alias (name, tag_id)
+		SELECT name, %d FROM tag WHERE id IN (?)`, targetID), sourceIDs)
+	if err != nil {
+		return fmt.Errorf("failed to build merge query: %w", err)
+	}
+
+	dlbBookmarks := db.Flavor().NewDeleteBuilder()
+	dlbBookmarks.DeleteFrom("bookmark_tag")
+	dlbBookmarks.Where(dlbBookmarks.In("tag_id", sources...))
+	bookmarksQuery, bookmarksArgs := dlbBookmarks.Build()
+
+	ubAliases := db.Flavor().NewUpdateBuilder()
+	ubAliases.Update("tag_alias")
+	ubAliases.Set(ubAliases.Assign("tag_id", targetID))
+	ubAliases.Where(ubAliases.In("tag_id", sources...))
+	aliasesQuery, aliasesArgs := ubAliases.Build()
+
+	ubChildren := db.Flavor().NewUpdateBuilder()
+	ubChildren.Update("tag")
+	ubChildren.Set(ubChildren.Assign("parent_id", targetID))
+	ubChildren.Where(ubChildren.In("parent_id", sources...), ubChildren.NotEqual("id", targetID))
+	childrenQuery, childrenArgs := ubChildren.Build()
+
+	// The target is moved to the top level if it was the child of a merged tag
+	ubTarget := db.Flavor().NewUpdateBuilder()
+	ubTarget.Update("tag")
+	ubTarget.Set(ubTarget.Assign("parent_id", nil))
+	ubTarget.Where(ubTarget.Equal("id", targetID), ubTarget.In("parent_id", sources...))
+	targetQuery, targetArgs := ubTarget.Build()
+
+	dlbTags := db.Flavor().NewDeleteBuilder()
+	dlbTags.DeleteFrom("tag")
+	dlbTags.Where(dlbTags.In("id", sources...))
+	tagsQuery, tagsArgs := dlbTags.Build()
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		for _, step := range []struct {
+			query string
+			args  []any
+			err   string
+		}{
+			{copyQuery, copyArgs, "failed to move bookmarks to merged tag"},
+			{bookmarksQuery, bookmarksArgs, "failed to remove bookmarks of merged tags"},
+			{aliasesQuery, aliasesArgs, "failed to move aliases to merged tag"},
+			{aliasQuery, aliasArgs, "failed to keep names of merged tags"},
+			{childrenQuery, childrenArgs, "failed to move child tags to merged tag"},
+			{targetQuery, targetArgs, "failed to move merged tag"},
+			{tagsQuery, tagsArgs, "failed to delete merged tags"},
+		} {
+			if _, err := tx.ExecContext(ctx, tx.Rebind(step.query), step.args...); err != nil {
+				return fmt.Errorf("%s: %w", step.err, err)
+			}
+		}
+		return nil
+	})
+}
+
+// tagsCondition returns the condition matching the bookmarks having every included tag and
+// none of the excluded ones. Tags are found by name or alias, and a tag also matches the
+// bookmarks of its descendants. Empty if there is nothing to filter.
+func (db *dbbase) tagsCondition(ctx context.Context, column string, included, excluded []string) (string, error) {
+	if len(included) == 0 && len(excluded) == 0 {
+		return "", nil
+	}
+
+	tags := []model.Tag{}
+	if err := db.ReaderDB().SelectContext(ctx, &tags, `SELECT id, name, parent_id FROM tag`); err != nil {
+		return "", fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	aliases := []model.TagAlias{}
+	if err := db.ReaderDB().SelectContext(ctx, &aliases, `SELECT id, name, tag_id FROM tag_alias`); err != nil {
+		return "", fmt.Errorf("failed to get tag aliases: %w", err)
+	}
+
+	ids := make(map[string]int, len(tags)+len(aliases))
+	children := map[int][]int{}
+	for _, alias := range aliases {
+		ids[alias.Name] = alias.TagID
+	}
+	for _, tag := range tags {
+		ids[tag.Name] = tag.ID
+		if tag.ParentID != nil {
+			children[*tag.ParentID] = append(children[*tag.ParentID], tag.ID)
+		}
+	}
+
+	// match returns the IDs of the tag with the name and of its descendants
+	match := func(name string) []string {
+		id, exists := ids[name]
+		if !exists {
+			id, exists = ids[model.NormalizeTagName(name)]
+		}
+		if !exists {
+			return nil
+		}
+
+		matched := []string{}
+		seen := map[int]struct{}{}
+		for queue := []int{id}; len(queue) > 0; queue = queue[1:] {
+			if _, done := seen[queue[0]]; done {
+				continue
+			}
+			seen[queue[0]] = struct{}{}
+			matched = append(matched, strconv.Itoa(queue[0]))
+			queue = append(queue, children[queue[0]]...)
+		}
+		return matched
+	}
+
+	conditions := []string{}
+	for _, name := range included {
+		matched := match(name)
+		if len(matched) == 0 {
+			// No bookmark has an unknown tag
+			return "1 = 0", nil
+		}
+		conditions = append(conditions, fmt.Sprintf(`%s IN (SELECT bookmark_id FROM bookmark_tag WHERE tag_id IN (%s))`,
+			column, strings.Join(matched, ", ")))
+	}
+
+	excludedIDs := []string{}
+	for _, name := range excluded {
+		excludedIDs = append(excludedIDs, match(name)...)
+	}
+	if len(excludedIDs) > 0 {
+		conditions = append(conditions, fmt.Sprintf(`%s NOT IN (SELECT bookmark_id FROM bookmark_tag WHERE tag_id IN (%s))`,
+			column, strings.Join(excludedIDs, ", ")))
+	}
+
+	return strings.Join(conditions, " AND "), nil
+}
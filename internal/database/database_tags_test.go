@@ -630,3 +630,45 @@ func testTagBookmarkEdgeCases(t *testing.T, db model.DB) {
 		t.Logf("Adding non-existent tag to bookmark failed as expected: %v", err)
 	}
 }
+
+func testGetTagsByAccount(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	_, err := db.SaveBookmarks(ctx, true,
+		model.BookmarkDTO{
+			AccountID: 1,
+			URL:       "https://golang.org",
+			Title:     "Go Language",
+			Tags:      []model.TagDTO{{Tag: model.Tag{Name: "golang"}}, {Tag: model.Tag{Name: "shared"}}},
+		},
+		model.BookmarkDTO{
+			AccountID: 2,
+			URL:       "https://sqlite.org",
+			Title:     "SQLite",
+			Tags:      []model.TagDTO{{Tag: model.Tag{Name: "database"}}, {Tag: model.Tag{Name: "shared"}}},
+		},
+		model.BookmarkDTO{
+			AccountID: 2,
+			URL:       "https://postgresql.org",
+			Title:     "PostgreSQL",
+			Tags:      []model.TagDTO{{Tag: model.Tag{Name: "shared"}}},
+		},
+	)
+	require.NoError(t, err)
+
+	_, err = db.CreateTags(ctx, model.Tag{Name: "unused"})
+	require.NoError(t, err)
+
+	tags, err := db.GetTags(ctx, model.DBListTagsOptions{
+		AccountID:         2,
+		WithBookmarkCount: true,
+		OrderBy:           model.DBTagOrderByTagName,
+	})
+	require.NoError(t, err)
+
+	counts := map[string]int64{}
+	for _, tag := range tags {
+		counts[tag.Name] = tag.BookmarkCount
+	}
+	require.Equal(t, map[string]int64{"database": 1, "shared": 2, "unused": 0}, counts)
+}
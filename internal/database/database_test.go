@@ -19,11 +19,13 @@ func testDatabase(t *testing.T, dbFactory testDatabaseFactory) {
 		"testBookmarkAutoIncrement":             testBookmarkAutoIncrement,
 		"testCreateBookmark":                    testCreateBookmark,
 		"testCreateBookmarkWithContent":         testCreateBookmarkWithContent,
+		"testCreateBookmarkKeepsCreatedAt":      testCreateBookmarkKeepsCreatedAt,
 		"testCreateBookmarkTwice":               testCreateBookmarkTwice,
 		"testCreateBookmarkWithTag":             testCreateBookmarkWithTag,
 		"testCreateTwoDifferentBookmarks":       testCreateTwoDifferentBookmarks,
 		"testUpdateBookmark":                    testUpdateBookmark,
 		"testUpdateBookmarkUpdatesModifiedTime": testUpdateBookmarkUpdatesModifiedTime,
+		"testUpdateBookmarkRemovesDeletedTags":  testUpdateBookmarkRemovesDeletedTags,
 		"testGetBoomarksWithTimeFilters":        testGetBoomarksWithTimeFilters,
 		"testUpdateBookmarkWithContent":         testUpdateBookmarkWithContent,
 		"testGetBookmark":                       testGetBookmark,
@@ -35,22 +37,30 @@ func testDatabase(t *testing.T, dbFactory testDatabaseFactory) {
 		"testSaveBookmark":                      testSaveBookmark,
 		"testBulkUpdateBookmarkTags":            testBulkUpdateBookmarkTags,
 		"testBookmarkExists":                    testBookmarkExists,
+		"testBookmarksAccountIsolation":         testBookmarksAccountIsolation,
+		"testDeleteBookmarksByAccount":          testDeleteBookmarksByAccount,
+		"testTransferBookmarks":                 testTransferBookmarks,
+		"testGetBookmarksPublicOnly":            testGetBookmarksPublicOnly,
 		// Tags
-		"testCreateTag":             testCreateTag,
-		"testCreateTags":            testCreateTags,
-		"testTagExists":             testTagExists,
-		"testGetTags":               testGetTags,
-		"testGetTagsFunction":       testGetTagsFunction,
-		"testGetTag":                testGetTag,
-		"testGetTagNotExistent":     testGetTagNotExistent,
-		"testUpdateTag":             testUpdateTag,
-		"testRenameTag":             testRenameTag,
-		"testDeleteTag":             testDeleteTag,
-		"testDeleteTagNotExistent":  testDeleteTagNotExistent,
-		"testAddTagToBookmark":      testAddTagToBookmark,
-		"testRemoveTagFromBookmark": testRemoveTagFromBookmark,
-		"testTagBookmarkEdgeCases":  testTagBookmarkEdgeCases,
-		"testTagBookmarkOperations": testTagBookmarkOperations,
+		"testCreateTag":                 testCreateTag,
+		"testCreateTags":                testCreateTags,
+		"testTagExists":                 testTagExists,
+		"testGetTags":                   testGetTags,
+		"testGetTagsFunction":           testGetTagsFunction,
+		"testGetTag":                    testGetTag,
+		"testGetTagNotExistent":         testGetTagNotExistent,
+		"testUpdateTag":                 testUpdateTag,
+		"testRenameTag":                 testRenameTag,
+		"testDeleteTag":                 testDeleteTag,
+		"testDeleteTagNotExistent":      testDeleteTagNotExistent,
+		"testAddTagToBookmark":          testAddTagToBookmark,
+		"testRemoveTagFromBookmark":     testRemoveTagFromBookmark,
+		"testTagBookmarkEdgeCases":      testTagBookmarkEdgeCases,
+		"testTagBookmarkOperations":     testTagBookmarkOperations,
+		"testGetTagsByAccount":          testGetTagsByAccount,
+		"testTagAliases":                testTagAliases,
+		"testMergeTags":                 testMergeTags,
+		"testGetBookmarksWithChildTags": testGetBookmarksWithChildTags,
 		// Accounts
 		"testCreateAccount":              testCreateAccount,
 		"testCreateDuplicateAccount":     testCreateDuplicateAccount,
@@ -61,6 +71,54 @@ func testDatabase(t *testing.T, dbFactory testDatabaseFactory) {
 		"testGetAccount":                 testGetAccount,
 		"testListAccounts":               testListAccounts,
 		"testListAccountsWithPassword":   testListAccountsWithPassword,
+		// Jobs
+		"testCreateJob": testCreateJob,
+		"testClaimJob":  testClaimJob,
+		"testUpdateJob": testUpdateJob,
+		"testListJobs":  testListJobs,
+		// Archive snapshots
+		"testCreateArchiveSnapshot":  testCreateArchiveSnapshot,
+		"testGetArchiveSnapshots":    testGetArchiveSnapshots,
+		"testUpdateArchiveSnapshot":  testUpdateArchiveSnapshot,
+		"testDeleteArchiveSnapshots": testDeleteArchiveSnapshots,
+		"testArchiveTexts":           testArchiveTexts,
+		// Link checks
+		"testCreateLinkCheck":          testCreateLinkCheck,
+		"testPruneLinkChecks":          testPruneLinkChecks,
+		"testGetBookmarksByLinkStatus": testGetBookmarksByLinkStatus,
+		// API tokens
+		"testCreateAPIToken":  testCreateAPIToken,
+		"testListAPITokens":   testListAPITokens,
+		"testDeleteAPIToken":  testDeleteAPIToken,
+		"testCreateSession":   testCreateSession,
+		"testListSessions":    testListSessions,
+		"testDeleteSession":   testDeleteSession,
+		"testAccountTOTP":     testAccountTOTP,
+		"testSettings":        testSettings,
+		"testFeedToken":       testFeedToken,
+		"testAccountIdentity": testAccountIdentity,
+		// Subscriptions
+		"testSubscriptions":     testSubscriptions,
+		"testSubscriptionItems": testSubscriptionItems,
+		// Webhooks
+		"testWebhooks":          testWebhooks,
+		"testWebhookDeliveries": testWebhookDeliveries,
+		// Collections
+		"testCollections": testCollections,
+		// Bookmark rules
+		"testBookmarkRules": testBookmarkRules,
+		// Saved searches
+		"testSavedSearches":          testSavedSearches,
+		"testGetBookmarksAddedAfter": testGetBookmarksAddedAfter,
+		// Highlights
+		"testHighlights": testHighlights,
+		// Search queries
+		"testGetBookmarksWithSearchQuery": testGetBookmarksWithSearchQuery,
+		"testGetBookmarksByRelevance":     testGetBookmarksByRelevance,
+		"testGetBookmarksInArchive":       testGetBookmarksInArchive,
+		// Copy
+		"testCopyDatabase":         testCopyDatabase,
+		"testCopyDatabaseNotEmpty": testCopyDatabaseNotEmpty,
 	}
 
 	for testName, testCase := range tests {
@@ -109,6 +167,25 @@ func testCreateBookmark(t *testing.T, db model.DB) {
 	assert.Equal(t, 1, result[0].ID, "Saved bookmark must have an ID set")
 }
 
+func testCreateBookmarkKeepsCreatedAt(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	book := model.BookmarkDTO{
+		URL:       "https://github.com/go-shiori/obelisk",
+		Title:     "shiori",
+		CreatedAt: "2019-01-01 10:00:00",
+	}
+
+	result, err := db.SaveBookmarks(ctx, true, book)
+	require.NoError(t, err, "Save bookmarks must not fail")
+
+	saved, exists, err := db.GetBookmark(ctx, result[0].ID, "", 0)
+	require.NoError(t, err)
+	require.True(t, exists)
+	assert.Contains(t, saved.CreatedAt, "2019-01-01")
+	assert.NotContains(t, saved.ModifiedAt, "2019-01-01")
+}
+
 func testCreateBookmarkWithContent(t *testing.T, db model.DB) {
 	ctx := context.TODO()
 
@@ -214,6 +291,38 @@ func testUpdateBookmark(t *testing.T, db model.DB) {
 	assert.Equal(t, savedBookmark.ID, result[0].ID)
 }
 
+func testUpdateBookmarkRemovesDeletedTags(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	book := model.BookmarkDTO{
+		URL:   "https://github.com/go-shiori/shiori",
+		Title: "shiori",
+		Tags: []model.TagDTO{
+			{Tag: model.Tag{Name: "keep"}},
+			{Tag: model.Tag{Name: "remove"}},
+		},
+	}
+
+	result, err := db.SaveBookmarks(ctx, true, book)
+	require.NoError(t, err, "Save bookmarks must not fail")
+	require.Len(t, result[0].Tags, 2)
+
+	savedBookmark := result[0]
+	for i := range savedBookmark.Tags {
+		if savedBookmark.Tags[i].Name == "remove" {
+			savedBookmark.Tags[i].Deleted = true
+		}
+	}
+
+	_, err = db.SaveBookmarks(ctx, false, savedBookmark)
+	require.NoError(t, err, "Save bookmarks must not fail")
+
+	tags, err := db.GetTags(ctx, model.DBListTagsOptions{BookmarkID: savedBookmark.ID})
+	require.NoError(t, err)
+	require.Len(t, tags, 1)
+	assert.Equal(t, "keep", tags[0].Name)
+}
+
 func testUpdateBookmarkWithContent(t *testing.T, db model.DB) {
 	ctx := context.TODO()
 
@@ -257,7 +366,7 @@ func testGetBookmark(t *testing.T, db model.DB) {
 	result, err := db.SaveBookmarks(ctx, true, book)
 	assert.NoError(t, err, "Save bookmarks must not fail")
 
-	savedBookmark, exists, err := db.GetBookmark(ctx, result[0].ID, "")
+	savedBookmark, exists, err := db.GetBookmark(ctx, result[0].ID, "", 0)
 	assert.NoError(t, err, "Get bookmark should not fail")
 	assert.True(t, exists, "Bookmark should exist")
 	assert.Equal(t, result[0].ID, savedBookmark.ID, "Retrieved bookmark should be the same")
@@ -267,7 +376,7 @@ func testGetBookmark(t *testing.T, db model.DB) {
 func testGetBookmarkNotExistent(t *testing.T, db model.DB) {
 	ctx := context.TODO()
 
-	savedBookmark, exists, err := db.GetBookmark(ctx, 1, "")
+	savedBookmark, exists, err := db.GetBookmark(ctx, 1, "", 0)
 	assert.NoError(t, err, "Get bookmark should not fail")
 	assert.False(t, exists, "Bookmark should not exist")
 	assert.Equal(t, model.BookmarkDTO{}, savedBookmark)
@@ -493,6 +602,95 @@ func testGetBookmarksCount(t *testing.T, db model.DB) {
 	assert.Equal(t, count, expectedCount, "count should be %d", expectedCount)
 }
 
+func testBookmarksAccountIsolation(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	// The same URL can be saved once per account
+	result, err := db.SaveBookmarks(ctx, true,
+		model.BookmarkDTO{AccountID: 1, URL: "https://github.com/go-shiori/shiori", Title: "first"},
+		model.BookmarkDTO{AccountID: 2, URL: "https://github.com/go-shiori/shiori", Title: "second"},
+	)
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+
+	_, err = db.SaveBookmarks(ctx, true, model.BookmarkDTO{AccountID: 1, URL: "https://github.com/go-shiori/shiori", Title: "again"})
+	require.Error(t, err, "URL must be unique per account")
+
+	bookmarks, err := db.GetBookmarks(ctx, model.DBGetBookmarksOptions{AccountID: 2})
+	require.NoError(t, err)
+	require.Len(t, bookmarks, 1)
+	require.Equal(t, "second", bookmarks[0].Title)
+	require.Equal(t, model.DBID(2), bookmarks[0].AccountID)
+
+	count, err := db.GetBookmarksCount(ctx, model.DBGetBookmarksOptions{AccountID: 1})
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	count, err = db.GetBookmarksCount(ctx, model.DBGetBookmarksOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	bookmark, exists, err := db.GetBookmark(ctx, 0, "https://github.com/go-shiori/shiori", 2)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, result[1].ID, bookmark.ID)
+
+	_, exists, err = db.GetBookmark(ctx, result[0].ID, "", 2)
+	require.NoError(t, err)
+	require.False(t, exists, "bookmark of another account must not be found")
+}
+
+func testDeleteBookmarksByAccount(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	result, err := db.SaveBookmarks(ctx, true,
+		model.BookmarkDTO{AccountID: 1, URL: "https://github.com/go-shiori/shiori", Title: "shiori"},
+		model.BookmarkDTO{AccountID: 2, URL: "https://github.com/go-shiori/go-readability", Title: "readability"},
+	)
+	require.NoError(t, err)
+	own, other := result[0], result[1]
+
+	// Bookmarks of other accounts are ignored
+	require.NoError(t, db.DeleteBookmarks(ctx, 1, other.ID))
+	_, exists, err := db.GetBookmark(ctx, other.ID, "", 0)
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	// Without IDs only the account bookmarks are removed
+	require.NoError(t, db.DeleteBookmarks(ctx, 1))
+	_, exists, err = db.GetBookmark(ctx, own.ID, "", 0)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	_, exists, err = db.GetBookmark(ctx, other.ID, "", 0)
+	require.NoError(t, err)
+	require.True(t, exists)
+}
+
+func testTransferBookmarks(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	result, err := db.SaveBookmarks(ctx, true,
+		model.BookmarkDTO{URL: "https://github.com/go-shiori/shiori", Title: "shiori"},
+		model.BookmarkDTO{URL: "https://github.com/go-shiori/go-readability", Title: "readability"},
+		model.BookmarkDTO{AccountID: 3, URL: "https://github.com/go-shiori/go-readability", Title: "readability"},
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, db.TransferBookmarks(ctx, 0, 3))
+
+	moved, exists, err := db.GetBookmark(ctx, result[0].ID, "", 0)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, model.DBID(3), moved.AccountID)
+
+	// The target account already saved this URL, so it stays where it was
+	kept, exists, err := db.GetBookmark(ctx, result[1].ID, "", 0)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, model.DBID(0), kept.AccountID)
+}
+
 func testCreateTag(t *testing.T, db model.DB) {
 	ctx := context.TODO()
 	tag := model.Tag{Name: "shiori"}
@@ -1015,7 +1213,7 @@ func testSaveBookmark(t *testing.T, db model.DB) {
 		require.NoError(t, err)
 
 		// Verify the bookmark was updated
-		retrievedBookmark, exists, err := db.GetBookmark(ctx, bookmarkID, "")
+		retrievedBookmark, exists, err := db.GetBookmark(ctx, bookmarkID, "", 0)
 		require.NoError(t, err)
 		require.True(t, exists)
 		assert.Equal(t, updatedBookmark.URL, retrievedBookmark.URL)
@@ -1080,7 +1278,7 @@ func testBulkUpdateBookmarkTags(t *testing.T, db model.DB) {
 		require.NoError(t, err, "Empty tag IDs should not cause an error")
 
 		// Verify tags were removed
-		bookmark, exists, err := db.GetBookmark(ctx, bookmark1ID, "")
+		bookmark, exists, err := db.GetBookmark(ctx, bookmark1ID, "", 0)
 		require.NoError(t, err)
 		require.True(t, exists)
 		assert.Empty(t, bookmark.Tags, "Tags should be empty after update with empty tag IDs")
@@ -1118,13 +1316,13 @@ func testBulkUpdateBookmarkTags(t *testing.T, db model.DB) {
 		require.NoError(t, err, "Bulk update should succeed")
 
 		// Verify bookmark1 has both tags
-		bookmark1, exists, err := db.GetBookmark(ctx, bookmark1ID, "")
+		bookmark1, exists, err := db.GetBookmark(ctx, bookmark1ID, "", 0)
 		require.NoError(t, err)
 		require.True(t, exists)
 		assert.Len(t, bookmark1.Tags, 2, "Bookmark 1 should have 2 tags")
 
 		// Verify bookmark2 has both tags
-		bookmark2, exists, err := db.GetBookmark(ctx, bookmark2ID, "")
+		bookmark2, exists, err := db.GetBookmark(ctx, bookmark2ID, "", 0)
 		require.NoError(t, err)
 		require.True(t, exists)
 		assert.Len(t, bookmark2.Tags, 2, "Bookmark 2 should have 2 tags")
@@ -1142,14 +1340,14 @@ func testBulkUpdateBookmarkTags(t *testing.T, db model.DB) {
 		require.NoError(t, err, "Update with single tag should succeed")
 
 		// Verify bookmark1 now has only one tag
-		bookmark1, exists, err = db.GetBookmark(ctx, bookmark1ID, "")
+		bookmark1, exists, err = db.GetBookmark(ctx, bookmark1ID, "", 0)
 		require.NoError(t, err)
 		require.True(t, exists)
 		assert.Len(t, bookmark1.Tags, 1, "Bookmark 1 should have 1 tag after update")
 		assert.Equal(t, tag1.Name, bookmark1.Tags[0].Name, "Bookmark 1 should have tag1")
 
 		// Verify bookmark2 still has both tags
-		bookmark2, exists, err = db.GetBookmark(ctx, bookmark2ID, "")
+		bookmark2, exists, err = db.GetBookmark(ctx, bookmark2ID, "", 0)
 		require.NoError(t, err)
 		require.True(t, exists)
 		assert.Len(t, bookmark2.Tags, 2, "Bookmark 2 should still have 2 tags")
@@ -1161,7 +1359,7 @@ func testBulkUpdateBookmarkTags(t *testing.T, db model.DB) {
 		require.NoError(t, err, "First update should succeed")
 
 		// Verify bookmark3 has both tags
-		bookmark3, exists, err := db.GetBookmark(ctx, bookmark3ID, "")
+		bookmark3, exists, err := db.GetBookmark(ctx, bookmark3ID, "", 0)
 		require.NoError(t, err)
 		require.True(t, exists)
 		assert.Len(t, bookmark3.Tags, 2, "Bookmark 3 should have 2 tags after first update")
@@ -1171,7 +1369,7 @@ func testBulkUpdateBookmarkTags(t *testing.T, db model.DB) {
 		require.NoError(t, err, "Second update should succeed")
 
 		// Verify bookmark3 now has the new tags and not the old ones
-		bookmark3, exists, err = db.GetBookmark(ctx, bookmark3ID, "")
+		bookmark3, exists, err = db.GetBookmark(ctx, bookmark3ID, "", 0)
 		require.NoError(t, err)
 		require.True(t, exists)
 		assert.Len(t, bookmark3.Tags, 2, "Bookmark 3 should have 2 tags after second update")
@@ -1196,12 +1394,12 @@ func testBulkUpdateBookmarkTags(t *testing.T, db model.DB) {
 		require.NoError(t, err)
 
 		// Verify initial state
-		bookmark1, exists, err := db.GetBookmark(ctx, bookmark1ID, "")
+		bookmark1, exists, err := db.GetBookmark(ctx, bookmark1ID, "", 0)
 		require.NoError(t, err)
 		require.True(t, exists)
 		assert.Len(t, bookmark1.Tags, 1, "Bookmark 1 should have 1 tag initially")
 
-		bookmark2, exists, err := db.GetBookmark(ctx, bookmark2ID, "")
+		bookmark2, exists, err := db.GetBookmark(ctx, bookmark2ID, "", 0)
 		require.NoError(t, err)
 		require.True(t, exists)
 		assert.Len(t, bookmark2.Tags, 2, "Bookmark 2 should have 2 tags initially")
@@ -1211,12 +1409,12 @@ func testBulkUpdateBookmarkTags(t *testing.T, db model.DB) {
 		require.NoError(t, err, "Bulk update should succeed")
 
 		// Verify both bookmarks now have tag3 and tag4 only
-		bookmark1, exists, err = db.GetBookmark(ctx, bookmark1ID, "")
+		bookmark1, exists, err = db.GetBookmark(ctx, bookmark1ID, "", 0)
 		require.NoError(t, err)
 		require.True(t, exists)
 		assert.Len(t, bookmark1.Tags, 2, "Bookmark 1 should have 2 tags after update")
 
-		bookmark2, exists, err = db.GetBookmark(ctx, bookmark2ID, "")
+		bookmark2, exists, err = db.GetBookmark(ctx, bookmark2ID, "", 0)
 		require.NoError(t, err)
 		require.True(t, exists)
 		assert.Len(t, bookmark2.Tags, 2, "Bookmark 2 should have 2 tags after update")
@@ -0,0 +1,186 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// GetAccountTOTP fetch the TOTP second factor of an account.
+func (db *dbbase) GetAccountTOTP(ctx context.Context, accountID model.DBID) (*model.AccountTOTP, bool, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select("account_id", "secret", "enabled", "last_used_step", "recovery_codes", "created_at")
+	sb.From("account_totp")
+	sb.Where(sb.Equal("account_id", accountID))
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	totp := model.AccountTOTP{}
+	if err := db.ReaderDB().GetContext(ctx, &totp, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to get account totp: %w", err)
+	}
+
+	return &totp, true, nil
+}
+
+// SaveAccountTOTP stores the TOTP second factor of an account, replacing the existing one.
+func (db *dbbase) SaveAccountTOTP(ctx context.Context, totp model.AccountTOTP) error {
+	if totp.CreatedAt == "" {
+		totp.CreatedAt = time.Now().UTC().Format(model.DatabaseDateFormat)
+	}
+
+	dlb := db.Flavor().NewDeleteBuilder()
+	dlb.DeleteFrom("account_totp")
+	dlb.Where(dlb.Equal("account_id", totp.AccountID))
+	deleteQuery, deleteArgs := dlb.Build()
+
+	ib := db.Flavor().NewInsertBuilder()
+	ib.InsertInto("account_totp")
+	ib.Cols("account_id", "secret", "enabled", "last_used_step", "recovery_codes", "created_at")
+	ib.Values(totp.AccountID, totp.Secret, totp.Enabled, totp.LastUsedStep, totp.RecoveryCodes, totp.CreatedAt)
+	insertQuery, insertArgs := ib.Build()
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, tx.Rebind(deleteQuery), deleteArgs...); err != nil {
+			return fmt.Errorf("failed to delete account totp: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, tx.Rebind(insertQuery), insertArgs...); err != nil {
+			return fmt.Errorf("failed to insert account totp: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// UpdateAccountTOTPStep records the time step of the last code used, returning false if a
+// code of that step or a later one was already used.
+func (db *dbbase) UpdateAccountTOTPStep(ctx context.Context, accountID model.DBID, step int64) (bool, error) {
+	ub := db.Flavor().NewUpdateBuilder()
+	ub.Update("account_totp")
+	ub.Set(ub.Assign("last_used_step", step))
+	ub.Where(ub.Equal("account_id", accountID), ub.LessThan("last_used_step", step))
+
+	query, args := ub.Build()
+	query = db.WriterDB().Rebind(query)
+
+	updated := false
+	err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to update account totp: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		updated = rows > 0
+		return nil
+	})
+
+	return updated, err
+}
+
+// UpdateAccountTOTPRecoveryCodes replaces the recovery codes if they are still the previous
+// ones, returning false if they were changed meanwhile.
+func (db *dbbase) UpdateAccountTOTPRecoveryCodes(ctx context.Context, accountID model.DBID, previous, codes model.TOTPRecoveryCodes) (bool, error) {
+	ub := db.Flavor().NewUpdateBuilder()
+	ub.Update("account_totp")
+	ub.Set(ub.Assign("recovery_codes", codes))
+	ub.Where(ub.Equal("account_id", accountID), ub.Equal("recovery_codes", previous))
+
+	query, args := ub.Build()
+	query = db.WriterDB().Rebind(query)
+
+	updated := false
+	err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to update account totp: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		updated = rows > 0
+		return nil
+	})
+
+	return updated, err
+}
+
+// DeleteAccountTOTP removes the TOTP second factor of an account.
+func (db *dbbase) DeleteAccountTOTP(ctx context.Context, accountID model.DBID) error {
+	dlb := db.Flavor().NewDeleteBuilder()
+	dlb.DeleteFrom("account_totp")
+	dlb.Where(dlb.Equal("account_id", accountID))
+
+	query, args := dlb.Build()
+	query = db.WriterDB().Rebind(query)
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to delete account totp: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetSetting fetch an instance wide setting.
+func (db *dbbase) GetSetting(ctx context.Context, name string) (string, bool, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select("value")
+	sb.From("setting")
+	sb.Where(sb.Equal("name", name))
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	var value string
+	if err := db.ReaderDB().GetContext(ctx, &value, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("failed to get setting: %w", err)
+	}
+
+	return value, true, nil
+}
+
+// SetSetting stores an instance wide setting.
+func (db *dbbase) SetSetting(ctx context.Context, name, value string) error {
+	dlb := db.Flavor().NewDeleteBuilder()
+	dlb.DeleteFrom("setting")
+	dlb.Where(dlb.Equal("name", name))
+	deleteQuery, deleteArgs := dlb.Build()
+
+	ib := db.Flavor().NewInsertBuilder()
+	ib.InsertInto("setting")
+	ib.Cols("name", "value")
+	ib.Values(name, value)
+	insertQuery, insertArgs := ib.Build()
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, tx.Rebind(deleteQuery), deleteArgs...); err != nil {
+			return fmt.Errorf("failed to delete setting: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, tx.Rebind(insertQuery), insertArgs...); err != nil {
+			return fmt.Errorf("failed to insert setting: %w", err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,87 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func testAccountTOTP(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	account, err := db.CreateAccount(ctx, model.Account{Username: "totp", Password: "hash"})
+	require.NoError(t, err)
+
+	_, exists, err := db.GetAccountTOTP(ctx, account.ID)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	require.NoError(t, db.SaveAccountTOTP(ctx, model.AccountTOTP{AccountID: account.ID, Secret: "pending"}))
+	require.NoError(t, db.SaveAccountTOTP(ctx, model.AccountTOTP{
+		AccountID:     account.ID,
+		Secret:        "encrypted",
+		Enabled:       true,
+		RecoveryCodes: model.TOTPRecoveryCodes{"first", "second"},
+	}))
+
+	totp, exists, err := db.GetAccountTOTP(ctx, account.ID)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, "encrypted", totp.Secret)
+	require.True(t, totp.Enabled)
+	require.Equal(t, model.TOTPRecoveryCodes{"first", "second"}, totp.RecoveryCodes)
+
+	t.Run("steps are only used once", func(t *testing.T) {
+		updated, err := db.UpdateAccountTOTPStep(ctx, account.ID, 100)
+		require.NoError(t, err)
+		require.True(t, updated)
+
+		for _, step := range []int64{100, 99} {
+			updated, err = db.UpdateAccountTOTPStep(ctx, account.ID, step)
+			require.NoError(t, err)
+			require.False(t, updated)
+		}
+	})
+
+	t.Run("recovery codes are only replaced once", func(t *testing.T) {
+		previous := model.TOTPRecoveryCodes{"first", "second"}
+
+		updated, err := db.UpdateAccountTOTPRecoveryCodes(ctx, account.ID, previous, model.TOTPRecoveryCodes{"second"})
+		require.NoError(t, err)
+		require.True(t, updated)
+
+		updated, err = db.UpdateAccountTOTPRecoveryCodes(ctx, account.ID, previous, model.TOTPRecoveryCodes{"first"})
+		require.NoError(t, err)
+		require.False(t, updated)
+
+		totp, _, err := db.GetAccountTOTP(ctx, account.ID)
+		require.NoError(t, err)
+		require.Equal(t, model.TOTPRecoveryCodes{"second"}, totp.RecoveryCodes)
+	})
+
+	t.Run("removed with the account", func(t *testing.T) {
+		require.NoError(t, db.DeleteAccount(ctx, account.ID))
+
+		_, exists, err := db.GetAccountTOTP(ctx, account.ID)
+		require.NoError(t, err)
+		require.False(t, exists)
+	})
+}
+
+func testSettings(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	_, exists, err := db.GetSetting(ctx, "name")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	require.NoError(t, db.SetSetting(ctx, "name", "first"))
+	require.NoError(t, db.SetSetting(ctx, "name", "second"))
+
+	value, exists, err := db.GetSetting(ctx, "name")
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, "second", value)
+}
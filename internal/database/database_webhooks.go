@@ -0,0 +1,184 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	webhookColumns         = []string{"id", "account_id", "url", "events", "secret", "created_at"}
+	webhookDeliveryColumns = []string{"id", "webhook_id", "event", "payload", "status", "attempts", "response_status", "response_body", "last_error", "created_at", "delivered_at"}
+)
+
+// GetWebhook fetch a webhook by its ID.
+func (db *dbbase) GetWebhook(ctx context.Context, id model.DBID) (*model.Webhook, bool, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select(webhookColumns...)
+	sb.From("webhook")
+	sb.Where(sb.Equal("id", id))
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	webhook := model.Webhook{}
+	if err := db.ReaderDB().GetContext(ctx, &webhook, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return &webhook, true, nil
+}
+
+// ListWebhooks fetch the webhooks matching the options, oldest first.
+func (db *dbbase) ListWebhooks(ctx context.Context, opts model.DBListWebhooksOptions) ([]model.Webhook, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select(webhookColumns...)
+	sb.From("webhook")
+	if len(opts.AccountIDs) > 0 {
+		accountIDs := make([]any, len(opts.AccountIDs))
+		for i, id := range opts.AccountIDs {
+			accountIDs[i] = id
+		}
+		sb.Where(sb.In("account_id", accountIDs...))
+	}
+	sb.OrderBy("id ASC")
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	webhooks := []model.Webhook{}
+	if err := db.ReaderDB().SelectContext(ctx, &webhooks, query, args...); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+// DeleteWebhook removes a webhook with its deliveries. ErrNotFound if it doesn't exist.
+func (db *dbbase) DeleteWebhook(ctx context.Context, id model.DBID) error {
+	dlb := db.Flavor().NewDeleteBuilder()
+	dlb.DeleteFrom("webhook")
+	dlb.Where(dlb.Equal("id", id))
+	deleteQuery, deleteArgs := dlb.Build()
+
+	dlbDeliveries := db.Flavor().NewDeleteBuilder()
+	dlbDeliveries.DeleteFrom("webhook_delivery")
+	dlbDeliveries.Where(dlbDeliveries.Equal("webhook_id", id))
+	deleteDeliveriesQuery, deleteDeliveriesArgs := dlbDeliveries.Build()
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, tx.Rebind(deleteQuery), deleteArgs...)
+		if err != nil {
+			return fmt.Errorf("failed to delete webhook: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rows == 0 {
+			return ErrNotFound
+		}
+
+		if _, err := tx.ExecContext(ctx, tx.Rebind(deleteDeliveriesQuery), deleteDeliveriesArgs...); err != nil {
+			return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// GetWebhookDelivery fetch a webhook delivery by its ID.
+func (db *dbbase) GetWebhookDelivery(ctx context.Context, id model.DBID) (*model.WebhookDelivery, bool, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select(webhookDeliveryColumns...)
+	sb.From("webhook_delivery")
+	sb.Where(sb.Equal("id", id))
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	delivery := model.WebhookDelivery{}
+	if err := db.ReaderDB().GetContext(ctx, &delivery, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+
+	return &delivery, true, nil
+}
+
+// ListWebhookDeliveries fetch the latest deliveries of a webhook, newest first.
+func (db *dbbase) ListWebhookDeliveries(ctx context.Context, webhookID model.DBID, limit int) ([]model.WebhookDelivery, error) {
+	sb := db.Flavor().NewSelectBuilder()
+	sb.Select(webhookDeliveryColumns...)
+	sb.From("webhook_delivery")
+	sb.Where(sb.Equal("webhook_id", webhookID))
+	sb.OrderBy("id DESC")
+	if limit > 0 {
+		sb.Limit(limit)
+	}
+
+	query, args := sb.Build()
+	query = db.ReaderDB().Rebind(query)
+
+	deliveries := []model.WebhookDelivery{}
+	if err := db.ReaderDB().SelectContext(ctx, &deliveries, query, args...); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// UpdateWebhookDelivery saves the status and last attempt of a webhook delivery.
+func (db *dbbase) UpdateWebhookDelivery(ctx context.Context, delivery model.WebhookDelivery) error {
+	ub := db.Flavor().NewUpdateBuilder()
+	ub.Update("webhook_delivery")
+	ub.Set(
+		ub.Assign("status", delivery.Status),
+		ub.Assign("attempts", delivery.Attempts),
+		ub.Assign("response_status", delivery.ResponseStatus),
+		ub.Assign("response_body", delivery.ResponseBody),
+		ub.Assign("last_error", delivery.LastError),
+		ub.Assign("delivered_at", delivery.DeliveredAt),
+	)
+	ub.Where(ub.Equal("id", delivery.ID))
+
+	query, args := ub.Build()
+	query = db.WriterDB().Rebind(query)
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to update webhook delivery: %w", err)
+		}
+		return nil
+	})
+}
+
+// DeleteWebhookDeliveries removes the deliveries of a webhook older than the given one.
+func (db *dbbase) DeleteWebhookDeliveries(ctx context.Context, webhookID model.DBID, beforeID model.DBID) error {
+	dlb := db.Flavor().NewDeleteBuilder()
+	dlb.DeleteFrom("webhook_delivery")
+	dlb.Where(
+		dlb.Equal("webhook_id", webhookID),
+		dlb.LessThan("id", beforeID),
+	)
+
+	query, args := dlb.Build()
+	query = db.WriterDB().Rebind(query)
+
+	return db.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+		}
+		return nil
+	})
+}
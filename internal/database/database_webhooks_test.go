@@ -0,0 +1,124 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func testWebhooks(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	account, err := db.CreateAccount(ctx, model.Account{Username: "integrator", Password: "hash"})
+	require.NoError(t, err)
+
+	webhook, err := db.CreateWebhook(ctx, model.Webhook{
+		AccountID: account.ID,
+		URL:       "https://chat.example.com/hook",
+		Events:    model.WebhookEvents{model.WebhookEventBookmarkCreated, model.WebhookEventBookmarkDeleted},
+		Secret:    "shiori_whsec_test",
+	})
+	require.NoError(t, err)
+	require.NotZero(t, webhook.ID)
+
+	instanceWebhook, err := db.CreateWebhook(ctx, model.Webhook{URL: "https://wiki.example.com/hook", Secret: "shiori_whsec_instance"})
+	require.NoError(t, err)
+
+	saved, exists, err := db.GetWebhook(ctx, webhook.ID)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, account.ID, saved.AccountID)
+	require.Equal(t, model.WebhookEvents{model.WebhookEventBookmarkCreated, model.WebhookEventBookmarkDeleted}, saved.Events)
+	require.Equal(t, "shiori_whsec_test", saved.Secret)
+
+	t.Run("list by account", func(t *testing.T) {
+		webhooks, err := db.ListWebhooks(ctx, model.DBListWebhooksOptions{AccountIDs: []model.DBID{account.ID}})
+		require.NoError(t, err)
+		require.Len(t, webhooks, 1)
+		require.Equal(t, webhook.ID, webhooks[0].ID)
+
+		webhooks, err = db.ListWebhooks(ctx, model.DBListWebhooksOptions{AccountIDs: []model.DBID{account.ID, 0}})
+		require.NoError(t, err)
+		require.Len(t, webhooks, 2)
+		require.Empty(t, webhooks[1].Events)
+
+		webhooks, err = db.ListWebhooks(ctx, model.DBListWebhooksOptions{})
+		require.NoError(t, err)
+		require.Len(t, webhooks, 2)
+	})
+
+	t.Run("deleted with its deliveries", func(t *testing.T) {
+		delivery, err := db.CreateWebhookDelivery(ctx, model.WebhookDelivery{
+			WebhookID: instanceWebhook.ID,
+			Event:     model.WebhookEventTagCreated,
+			Payload:   `{"event":"tag.created"}`,
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, db.DeleteWebhook(ctx, instanceWebhook.ID))
+		require.ErrorIs(t, db.DeleteWebhook(ctx, instanceWebhook.ID), ErrNotFound)
+
+		_, exists, err := db.GetWebhookDelivery(ctx, delivery.ID)
+		require.NoError(t, err)
+		require.False(t, exists)
+	})
+
+	t.Run("deleted with the account", func(t *testing.T) {
+		require.NoError(t, db.DeleteAccount(ctx, account.ID))
+
+		_, exists, err := db.GetWebhook(ctx, webhook.ID)
+		require.NoError(t, err)
+		require.False(t, exists)
+	})
+}
+
+func testWebhookDeliveries(t *testing.T, db model.DB) {
+	ctx := context.TODO()
+
+	webhook, err := db.CreateWebhook(ctx, model.Webhook{URL: "https://n8n.example.com/hook", Secret: "shiori_whsec_test"})
+	require.NoError(t, err)
+
+	var deliveries []model.WebhookDelivery
+	for _, event := range []model.WebhookEvent{model.WebhookEventBookmarkCreated, model.WebhookEventBookmarkUpdated, model.WebhookEventBookmarkDeleted} {
+		delivery, err := db.CreateWebhookDelivery(ctx, model.WebhookDelivery{
+			WebhookID: webhook.ID,
+			Event:     event,
+			Payload:   `{"event":"` + string(event) + `"}`,
+		})
+		require.NoError(t, err)
+		require.Equal(t, model.WebhookDeliveryStatusPending, delivery.Status)
+		deliveries = append(deliveries, *delivery)
+	}
+
+	delivery := deliveries[0]
+	delivery.Status = model.WebhookDeliveryStatusFailed
+	delivery.Attempts = 2
+	delivery.ResponseStatus = 502
+	delivery.ResponseBody = "bad gateway"
+	delivery.LastError = "unexpected status 502 Bad Gateway"
+	delivery.DeliveredAt = model.Ptr("2024-01-01 10:00:00")
+	require.NoError(t, db.UpdateWebhookDelivery(ctx, delivery))
+
+	saved, exists, err := db.GetWebhookDelivery(ctx, delivery.ID)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, model.WebhookDeliveryStatusFailed, saved.Status)
+	require.Equal(t, 2, saved.Attempts)
+	require.Equal(t, 502, saved.ResponseStatus)
+	require.Equal(t, "bad gateway", saved.ResponseBody)
+	require.NotNil(t, saved.DeliveredAt)
+	require.Equal(t, `{"event":"bookmark.created"}`, saved.Payload)
+
+	listed, err := db.ListWebhookDeliveries(ctx, webhook.ID, 2)
+	require.NoError(t, err)
+	require.Len(t, listed, 2)
+	require.Equal(t, deliveries[2].ID, listed[0].ID)
+
+	require.NoError(t, db.DeleteWebhookDeliveries(ctx, webhook.ID, deliveries[2].ID))
+	listed, err = db.ListWebhookDeliveries(ctx, webhook.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	require.Equal(t, deliveries[2].ID, listed[0].ID)
+}
@@ -0,0 +1 @@
+ALTER TABLE bookmark ADD COLUMN account_id INT(11) NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+UPDATE bookmark SET account_id = COALESCE((SELECT MIN(id) FROM account WHERE owner = 1), 0);
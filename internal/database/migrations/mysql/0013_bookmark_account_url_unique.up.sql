@@ -0,0 +1 @@
+ALTER TABLE bookmark DROP INDEX bookmark_url_UNIQUE, ADD UNIQUE KEY bookmark_account_url_UNIQUE (account_id, url(255));
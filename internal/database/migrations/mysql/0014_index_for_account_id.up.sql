@@ -0,0 +1 @@
+CREATE INDEX idx_account_id ON bookmark (account_id);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS job(
+    id          INT(11)      NOT NULL AUTO_INCREMENT,
+    type        VARCHAR(50)  NOT NULL,
+    payload     TEXT         NOT NULL,
+    status      VARCHAR(20)  NOT NULL DEFAULT 'pending',
+    attempts    INT(11)      NOT NULL DEFAULT 0,
+    max_attempts INT(11)     NOT NULL DEFAULT 1,
+    last_error  TEXT         NOT NULL,
+    run_at      TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at  TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    INDEX idx_job_status_run_at (status, run_at))
+    CHARACTER SET utf8mb4;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS archive_snapshot(
+    id          INT(11)      NOT NULL AUTO_INCREMENT,
+    bookmark_id INT(11)      NOT NULL,
+    path        VARCHAR(250) NOT NULL,
+    created_at  TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    INDEX idx_archive_snapshot_bookmark_id (bookmark_id))
+    CHARACTER SET utf8mb4;
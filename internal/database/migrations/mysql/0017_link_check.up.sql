@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS link_check(
+    id          INT(11)   NOT NULL AUTO_INCREMENT,
+    bookmark_id INT(11)   NOT NULL,
+    url         TEXT      NOT NULL,
+    status_code INT(11)   NOT NULL DEFAULT 0,
+    final_url   TEXT      NOT NULL,
+    latency_ms  INT(11)   NOT NULL DEFAULT 0,
+    error       TEXT      NOT NULL,
+    checked_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    INDEX idx_link_check_bookmark_id (bookmark_id))
+    CHARACTER SET utf8mb4;
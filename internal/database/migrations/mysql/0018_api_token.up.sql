@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS api_token(
+    id           INT(11)      NOT NULL AUTO_INCREMENT,
+    account_id   INT(11)      NOT NULL,
+    name         VARCHAR(250) NOT NULL,
+    token_hash   VARCHAR(64)  NOT NULL,
+    scopes       VARCHAR(250) NOT NULL DEFAULT '',
+    expires_at   TIMESTAMP    NULL,
+    last_used_at TIMESTAMP    NULL,
+    created_at   TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE KEY api_token_hash_UNIQUE (token_hash),
+    INDEX idx_api_token_account_id (account_id))
+    CHARACTER SET utf8mb4;
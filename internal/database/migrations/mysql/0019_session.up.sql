@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS session(
+    id           VARCHAR(36)  NOT NULL,
+    account_id   INT(11)      NOT NULL,
+    user_agent   VARCHAR(512) NOT NULL DEFAULT '',
+    expires_at   TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP    NULL,
+    created_at   TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    INDEX idx_session_account_id (account_id))
+    CHARACTER SET utf8mb4;
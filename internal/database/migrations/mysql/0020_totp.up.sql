@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS account_totp(
+    account_id     INT(11)       NOT NULL,
+    secret         VARCHAR(255)  NOT NULL,
+    enabled        BOOLEAN       NOT NULL DEFAULT FALSE,
+    last_used_step BIGINT        NOT NULL DEFAULT 0,
+    recovery_codes VARCHAR(1024) NOT NULL DEFAULT '',
+    created_at     TIMESTAMP     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (account_id))
+    CHARACTER SET utf8mb4;
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS setting(
+    name  VARCHAR(64)   NOT NULL,
+    value VARCHAR(1024) NOT NULL DEFAULT '',
+    PRIMARY KEY (name))
+    CHARACTER SET utf8mb4;
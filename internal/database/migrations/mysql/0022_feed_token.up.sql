@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS feed_token(
+    account_id INT(11)     NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (account_id),
+    UNIQUE KEY feed_token_hash_UNIQUE (token_hash))
+    CHARACTER SET utf8mb4;
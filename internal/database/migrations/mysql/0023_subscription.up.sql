@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS subscription(
+    id             INT(11)    NOT NULL AUTO_INCREMENT,
+    account_id     INT(11)    NOT NULL,
+    url            TEXT       NOT NULL,
+    title          TEXT       NOT NULL,
+    tags           TEXT       NOT NULL,
+    create_archive TINYINT(1) NOT NULL DEFAULT 0,
+    create_ebook   TINYINT(1) NOT NULL DEFAULT 0,
+    last_error     TEXT       NOT NULL,
+    last_polled_at TIMESTAMP  NULL,
+    created_at     TIMESTAMP  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE KEY subscription_account_url_UNIQUE (account_id, url(255)))
+    CHARACTER SET utf8mb4;
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS subscription_item(
+    subscription_id INT(11)      NOT NULL,
+    guid            VARCHAR(191) NOT NULL,
+    bookmark_id     INT(11)      NOT NULL DEFAULT 0,
+    created_at      TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (subscription_id, guid))
+    CHARACTER SET utf8mb4;
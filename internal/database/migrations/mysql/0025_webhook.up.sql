@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS webhook(
+    id         INT(11)   NOT NULL AUTO_INCREMENT,
+    account_id INT(11)   NOT NULL DEFAULT 0,
+    url        TEXT      NOT NULL,
+    events     TEXT      NOT NULL,
+    secret     TEXT      NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    INDEX idx_webhook_account_id (account_id))
+    CHARACTER SET utf8mb4;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS webhook_delivery(
+    id              INT(11)     NOT NULL AUTO_INCREMENT,
+    webhook_id      INT(11)     NOT NULL,
+    event           VARCHAR(50) NOT NULL,
+    payload         TEXT        NOT NULL,
+    status          VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts        INT(11)     NOT NULL DEFAULT 0,
+    response_status INT(11)     NOT NULL DEFAULT 0,
+    response_body   TEXT        NOT NULL,
+    last_error      TEXT        NOT NULL,
+    created_at      TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivered_at    TIMESTAMP   NULL,
+    PRIMARY KEY (id),
+    INDEX idx_webhook_delivery_webhook_id (webhook_id))
+    CHARACTER SET utf8mb4;
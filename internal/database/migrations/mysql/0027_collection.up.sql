@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS collection(
+    id          INT(11)   NOT NULL AUTO_INCREMENT,
+    account_id  INT(11)   NOT NULL DEFAULT 0,
+    parent_id   INT(11)   NULL,
+    name        TEXT      NOT NULL,
+    position    INT(11)   NOT NULL DEFAULT 0,
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    INDEX idx_collection_account_id (account_id),
+    INDEX idx_collection_parent_id (parent_id))
+    CHARACTER SET utf8mb4;
@@ -0,0 +1 @@
+ALTER TABLE bookmark ADD COLUMN collection_id INT(11) NULL, ADD INDEX idx_bookmark_collection_id (collection_id);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS tag_alias(
+    id     INT(11)      NOT NULL AUTO_INCREMENT,
+    name   VARCHAR(250) NOT NULL,
+    tag_id INT(11)      NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY tag_alias_name_UNIQUE (name),
+    INDEX idx_tag_alias_tag_id (tag_id))
+    CHARACTER SET utf8mb4;
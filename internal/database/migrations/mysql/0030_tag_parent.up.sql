@@ -0,0 +1 @@
+ALTER TABLE tag ADD COLUMN parent_id INT(11) NULL, ADD INDEX idx_tag_parent_id (parent_id);
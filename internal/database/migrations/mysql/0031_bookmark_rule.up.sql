@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS bookmark_rule(
+    id          INT(11)    NOT NULL AUTO_INCREMENT,
+    account_id  INT(11)    NOT NULL,
+    name        TEXT       NOT NULL,
+    enabled     TINYINT(1) NOT NULL DEFAULT 1,
+    conditions  TEXT       NOT NULL,
+    actions     TEXT       NOT NULL,
+    created_at  TIMESTAMP  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    INDEX idx_bookmark_rule_account_id (account_id))
+    CHARACTER SET utf8mb4;
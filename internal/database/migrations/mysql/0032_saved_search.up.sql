@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS saved_search(
+    id          INT(11)      NOT NULL AUTO_INCREMENT,
+    account_id  INT(11)      NOT NULL,
+    name        VARCHAR(250) NOT NULL,
+    filter      TEXT         NOT NULL,
+    created_at  TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE KEY saved_search_account_id_name_UNIQUE (account_id, name))
+    CHARACTER SET utf8mb4;
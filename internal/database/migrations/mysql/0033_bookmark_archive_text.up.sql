@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS bookmark_archive_text(
+    bookmark_id INT(11)    NOT NULL,
+    content     MEDIUMTEXT NOT NULL,
+    modified_at TIMESTAMP  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (bookmark_id),
+    FULLTEXT (content))
+    CHARACTER SET utf8mb4;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS bookmark_highlight(
+    id          INT(11)   NOT NULL AUTO_INCREMENT,
+    bookmark_id INT(11)   NOT NULL,
+    account_id  INT(11)   NOT NULL,
+    selector    TEXT      NOT NULL,
+    note        TEXT      NOT NULL,
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    KEY bookmark_highlight_bookmark_id_account_id (bookmark_id, account_id))
+    CHARACTER SET utf8mb4;
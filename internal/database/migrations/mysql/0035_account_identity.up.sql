@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS account_identity(
+    id         INT(11)      NOT NULL AUTO_INCREMENT,
+    account_id INT(11)      NOT NULL,
+    issuer     VARCHAR(255) NOT NULL,
+    subject    VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE KEY account_identity_issuer_subject_UNIQUE (issuer, subject),
+    INDEX idx_account_identity_account_id (account_id))
+    CHARACTER SET utf8mb4;
@@ -0,0 +1,12 @@
+ALTER TABLE bookmark
+ADD COLUMN account_id INTEGER NOT NULL DEFAULT 0;
+
+-- Existing bookmarks are assigned to the first owner account, if any
+UPDATE bookmark
+SET account_id = COALESCE((SELECT MIN(id) FROM account WHERE owner = TRUE), 0);
+
+-- URLs are unique per account instead of globally
+ALTER TABLE bookmark DROP CONSTRAINT IF EXISTS bookmark_url_UNIQUE;
+ALTER TABLE bookmark ADD CONSTRAINT bookmark_account_url_UNIQUE UNIQUE (account_id, url);
+
+CREATE INDEX idx_account_id ON bookmark(account_id);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS job(
+    id SERIAL PRIMARY KEY,
+    type VARCHAR(50) NOT NULL,
+    payload TEXT NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 1,
+    last_error TEXT NOT NULL DEFAULT '',
+    run_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_job_status_run_at ON job(status, run_at);
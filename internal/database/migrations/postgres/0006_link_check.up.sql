@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS link_check(
+    id SERIAL PRIMARY KEY,
+    bookmark_id INTEGER NOT NULL,
+    url TEXT NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    final_url TEXT NOT NULL DEFAULT '',
+    latency_ms INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    checked_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_link_check_bookmark_id ON link_check(bookmark_id);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS api_token(
+    id SERIAL PRIMARY KEY,
+    account_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    scopes TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMP(0) NULL,
+    last_used_at TIMESTAMP(0) NULL,
+    created_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT api_token_hash_UNIQUE UNIQUE (token_hash)
+);
+
+CREATE INDEX idx_api_token_account_id ON api_token(account_id);
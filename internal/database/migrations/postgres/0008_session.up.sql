@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS session(
+    id VARCHAR(36) PRIMARY KEY,
+    account_id INTEGER NOT NULL,
+    user_agent TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMP(0) NOT NULL,
+    last_used_at TIMESTAMP(0) NULL,
+    created_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_session_account_id ON session(account_id);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS account_totp(
+    account_id INTEGER PRIMARY KEY,
+    secret TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    recovery_codes TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS setting(
+    name VARCHAR(64) PRIMARY KEY,
+    value TEXT NOT NULL DEFAULT ''
+);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS feed_token(
+    account_id INTEGER NOT NULL PRIMARY KEY,
+    token_hash VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT feed_token_hash_UNIQUE UNIQUE (token_hash)
+);
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS subscription(
+    id SERIAL PRIMARY KEY,
+    account_id INTEGER NOT NULL,
+    url TEXT NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    tags TEXT NOT NULL DEFAULT '',
+    create_archive BOOLEAN NOT NULL DEFAULT FALSE,
+    create_ebook BOOLEAN NOT NULL DEFAULT FALSE,
+    last_error TEXT NOT NULL DEFAULT '',
+    last_polled_at TIMESTAMP(0) NULL,
+    created_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT subscription_account_url_UNIQUE UNIQUE (account_id, url)
+);
+
+CREATE TABLE IF NOT EXISTS subscription_item(
+    subscription_id INTEGER NOT NULL,
+    guid TEXT NOT NULL,
+    bookmark_id INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (subscription_id, guid)
+);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS collection(
+    id SERIAL PRIMARY KEY,
+    account_id INTEGER NOT NULL DEFAULT 0,
+    parent_id INTEGER NULL,
+    name TEXT NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_collection_account_id ON collection(account_id);
+CREATE INDEX IF NOT EXISTS idx_collection_parent_id ON collection(parent_id);
+
+ALTER TABLE bookmark ADD COLUMN collection_id INTEGER NULL;
+
+CREATE INDEX IF NOT EXISTS idx_bookmark_collection_id ON bookmark(collection_id);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS tag_alias(
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(250) NOT NULL,
+    tag_id INTEGER NOT NULL,
+    CONSTRAINT tag_alias_name_UNIQUE UNIQUE (name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_tag_alias_tag_id ON tag_alias(tag_id);
+
+ALTER TABLE tag ADD COLUMN parent_id INTEGER NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tag_parent_id ON tag(parent_id);
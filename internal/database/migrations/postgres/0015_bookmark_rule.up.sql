@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS bookmark_rule(
+    id SERIAL PRIMARY KEY,
+    account_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    conditions TEXT NOT NULL DEFAULT '{}',
+    actions TEXT NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_bookmark_rule_account_id ON bookmark_rule(account_id);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS saved_search(
+    id SERIAL PRIMARY KEY,
+    account_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    filter TEXT NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_saved_search_account_id_name ON saved_search(account_id, name);
@@ -0,0 +1,10 @@
+-- A tsvector is limited to 1MB, only the beginning of long contents is indexed for the
+-- ranking. The content is still searched whole with LIKE.
+ALTER TABLE bookmark ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', title), 'A') ||
+        setweight(to_tsvector('simple', excerpt), 'B') ||
+        setweight(to_tsvector('simple', left(content, 500000)), 'C')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_bookmark_search_vector ON bookmark USING GIN (search_vector);
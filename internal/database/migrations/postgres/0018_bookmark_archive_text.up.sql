@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS bookmark_archive_text(
+    bookmark_id INTEGER PRIMARY KEY,
+    content TEXT NOT NULL,
+    modified_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    search_vector tsvector GENERATED ALWAYS AS (to_tsvector('simple', content)) STORED
+);
+
+CREATE INDEX IF NOT EXISTS idx_bookmark_archive_text_search_vector ON bookmark_archive_text USING GIN (search_vector);
@@ -0,0 +1,36 @@
+-- The URL is now unique per account instead of globally, which requires rebuilding the
+-- table. Bookmark tags reference the bookmark table, so they are set aside meanwhile.
+CREATE TEMPORARY TABLE bookmark_tag_backup AS SELECT bookmark_id, tag_id FROM bookmark_tag;
+
+DELETE FROM bookmark_tag;
+
+CREATE TABLE IF NOT EXISTS bookmark_temp(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_id INTEGER NOT NULL DEFAULT 0,
+    url TEXT NOT NULL,
+    title TEXT NOT NULL,
+    excerpt TEXT NOT NULL DEFAULT "",
+    author TEXT NOT NULL DEFAULT "",
+    public INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    modified_at TEXT NULL,
+    has_content BOOLEAN DEFAULT FALSE NOT NULL,
+    CONSTRAINT bookmark_account_url_UNIQUE UNIQUE(account_id, url)
+);
+
+-- Existing bookmarks are assigned to the first owner account, if any
+INSERT INTO bookmark_temp (id, account_id, url, title, excerpt, author, public, created_at, modified_at, has_content)
+SELECT id, COALESCE((SELECT MIN(id) FROM account WHERE owner = 1), 0), url, title, excerpt, author, public, created_at, modified_at, has_content
+FROM bookmark;
+
+DROP TABLE bookmark;
+
+ALTER TABLE bookmark_temp RENAME TO bookmark;
+
+CREATE INDEX idx_created_at ON bookmark(created_at);
+CREATE INDEX idx_modified_at ON bookmark(modified_at);
+CREATE INDEX idx_account_id ON bookmark(account_id);
+
+INSERT INTO bookmark_tag (bookmark_id, tag_id) SELECT bookmark_id, tag_id FROM bookmark_tag_backup;
+
+DROP TABLE bookmark_tag_backup;
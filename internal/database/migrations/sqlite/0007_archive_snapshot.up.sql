@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS archive_snapshot(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    bookmark_id INTEGER NOT NULL,
+    path TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_archive_snapshot_bookmark_id ON archive_snapshot(bookmark_id);
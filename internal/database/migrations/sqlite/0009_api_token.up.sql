@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS api_token(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    token_hash TEXT NOT NULL,
+    scopes TEXT NOT NULL DEFAULT '',
+    expires_at TEXT NULL,
+    last_used_at TEXT NULL,
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT api_token_hash_UNIQUE UNIQUE(token_hash)
+);
+
+CREATE INDEX idx_api_token_account_id ON api_token(account_id);
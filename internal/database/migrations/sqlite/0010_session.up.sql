@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS session(
+    id TEXT NOT NULL PRIMARY KEY,
+    account_id INTEGER NOT NULL,
+    user_agent TEXT NOT NULL DEFAULT '',
+    expires_at TEXT NOT NULL,
+    last_used_at TEXT NULL,
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_session_account_id ON session(account_id);
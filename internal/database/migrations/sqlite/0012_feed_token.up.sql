@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS feed_token(
+    account_id INTEGER NOT NULL PRIMARY KEY,
+    token_hash TEXT NOT NULL UNIQUE,
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
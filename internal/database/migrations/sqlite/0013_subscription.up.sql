@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS subscription(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_id INTEGER NOT NULL,
+    url TEXT NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    tags TEXT NOT NULL DEFAULT '',
+    create_archive INTEGER NOT NULL DEFAULT 0,
+    create_ebook INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    last_polled_at TEXT NULL,
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT subscription_account_url_UNIQUE UNIQUE(account_id, url)
+);
+
+CREATE TABLE IF NOT EXISTS subscription_item(
+    subscription_id INTEGER NOT NULL,
+    guid TEXT NOT NULL,
+    bookmark_id INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(subscription_id, guid)
+);
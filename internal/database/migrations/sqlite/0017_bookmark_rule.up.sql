@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS bookmark_rule(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    enabled INTEGER NOT NULL DEFAULT 1,
+    conditions TEXT NOT NULL DEFAULT '{}',
+    actions TEXT NOT NULL DEFAULT '{}',
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    modified_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_bookmark_rule_account_id ON bookmark_rule(account_id);
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS bookmark_archive_text(
+    bookmark_id INTEGER PRIMARY KEY,
+    content TEXT NOT NULL,
+    modified_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE VIRTUAL TABLE IF NOT EXISTS bookmark_archive_text_fts
+    USING fts5(content, content='bookmark_archive_text', content_rowid='bookmark_id');
+
+CREATE TRIGGER IF NOT EXISTS bookmark_archive_text_ai AFTER INSERT ON bookmark_archive_text BEGIN
+    INSERT INTO bookmark_archive_text_fts(rowid, content) VALUES (new.bookmark_id, new.content);
+END;
+
+CREATE TRIGGER IF NOT EXISTS bookmark_archive_text_ad AFTER DELETE ON bookmark_archive_text BEGIN
+    INSERT INTO bookmark_archive_text_fts(bookmark_archive_text_fts, rowid, content) VALUES ('delete', old.bookmark_id, old.content);
+END;
+
+CREATE TRIGGER IF NOT EXISTS bookmark_archive_text_au AFTER UPDATE ON bookmark_archive_text BEGIN
+    INSERT INTO bookmark_archive_text_fts(bookmark_archive_text_fts, rowid, content) VALUES ('delete', old.bookmark_id, old.content);
+    INSERT INTO bookmark_archive_text_fts(rowid, content) VALUES (new.bookmark_id, new.content);
+END;
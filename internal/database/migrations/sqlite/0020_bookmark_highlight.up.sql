@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS bookmark_highlight(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    bookmark_id INTEGER NOT NULL,
+    account_id INTEGER NOT NULL,
+    selector TEXT NOT NULL DEFAULT '{}',
+    note TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    modified_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_bookmark_highlight_bookmark_id_account_id ON bookmark_highlight(bookmark_id, account_id);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS account_identity(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_id INTEGER NOT NULL,
+    issuer TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT account_identity_issuer_subject_UNIQUE UNIQUE(issuer, subject)
+);
+
+CREATE INDEX idx_account_identity_account_id ON account_identity(account_id);
@@ -68,6 +68,31 @@ var mysqlMigrations = []migration{
 	newFileMigration("0.8.2", "0.8.3", "mysql/0008_set_modified_at_equal_created_at"),
 	newFileMigration("0.8.3", "0.8.4", "mysql/0009_index_for_created_at"),
 	newFileMigration("0.8.4", "0.8.5", "mysql/0010_index_for_modified_at"),
+	newFileMigration("0.8.5", "0.9.0", "mysql/0011_bookmark_account"),
+	newFileMigration("0.9.0", "0.9.1", "mysql/0012_bookmark_account_owner"),
+	newFileMigration("0.9.1", "0.9.2", "mysql/0013_bookmark_account_url_unique"),
+	newFileMigration("0.9.2", "0.9.3", "mysql/0014_index_for_account_id"),
+	newFileMigration("0.9.3", "0.10.0", "mysql/0015_job"),
+	newFileMigration("0.10.0", "0.11.0", "mysql/0016_archive_snapshot"),
+	newFileMigration("0.11.0", "0.12.0", "mysql/0017_link_check"),
+	newFileMigration("0.12.0", "0.13.0", "mysql/0018_api_token"),
+	newFileMigration("0.13.0", "0.14.0", "mysql/0019_session"),
+	newFileMigration("0.14.0", "0.14.1", "mysql/0020_totp"),
+	newFileMigration("0.14.1", "0.15.0", "mysql/0021_setting"),
+	newFileMigration("0.15.0", "0.16.0", "mysql/0022_feed_token"),
+	newFileMigration("0.16.0", "0.16.1", "mysql/0023_subscription"),
+	newFileMigration("0.16.1", "0.17.0", "mysql/0024_subscription_item"),
+	newFileMigration("0.17.0", "0.17.1", "mysql/0025_webhook"),
+	newFileMigration("0.17.1", "0.18.0", "mysql/0026_webhook_delivery"),
+	newFileMigration("0.18.0", "0.18.1", "mysql/0027_collection"),
+	newFileMigration("0.18.1", "0.19.0", "mysql/0028_bookmark_collection"),
+	newFileMigration("0.19.0", "0.19.1", "mysql/0029_tag_alias"),
+	newFileMigration("0.19.1", "0.20.0", "mysql/0030_tag_parent"),
+	newFileMigration("0.20.0", "0.21.0", "mysql/0031_bookmark_rule"),
+	newFileMigration("0.21.0", "0.22.0", "mysql/0032_saved_search"),
+	newFileMigration("0.22.0", "0.23.0", "mysql/0033_bookmark_archive_text"),
+	newFileMigration("0.23.0", "0.24.0", "mysql/0034_bookmark_highlight"),
+	newFileMigration("0.24.0", "0.25.0", "mysql/0035_account_identity"),
 }
 
 // MySQLDatabase is implementation of Database interface
@@ -138,8 +163,8 @@ func (db *MySQLDatabase) SaveBookmarks(ctx context.Context, create bool, bookmar
 	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
 		// Prepare statement
 		stmtInsertBook, err := tx.Preparex(`INSERT INTO bookmark
-			(url, title, excerpt, author, public, content, html, modified_at, created_at)
-			VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+			(account_id, url, title, excerpt, author, public, content, html, modified_at, created_at, collection_id)
+			VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
 		if err != nil {
 			return errors.WithStack(err)
 		}
@@ -163,6 +188,12 @@ func (db *MySQLDatabase) SaveBookmarks(ctx context.Context, create bool, bookmar
 			return errors.WithStack(err)
 		}
 
+		stmtGetTagAlias, err := tx.Preparex(`SELECT t.id, t.name FROM tag_alias a
+			JOIN tag t ON t.id = a.tag_id WHERE a.name = ?`)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
 		stmtInsertTag, err := tx.Preparex(`INSERT INTO tag (name) VALUES (?)`)
 		if err != nil {
 			return errors.WithStack(err)
@@ -203,11 +234,13 @@ func (db *MySQLDatabase) SaveBookmarks(ctx context.Context, create bool, bookmar
 			// Save bookmark
 			var err error
 			if create {
-				book.CreatedAt = modifiedTime
+				if book.CreatedAt == "" {
+					book.CreatedAt = modifiedTime
+				}
 				var res sql.Result
 				res, err = stmtInsertBook.ExecContext(ctx,
-					book.URL, book.Title, book.Excerpt, book.Author,
-					book.Public, book.Content, book.HTML, book.ModifiedAt, book.CreatedAt)
+					book.AccountID, book.URL, book.Title, book.Excerpt, book.Author,
+					book.Public, book.Content, book.HTML, book.ModifiedAt, book.CreatedAt, book.CollectionID)
 				if err != nil {
 					return errors.WithStack(err)
 				}
@@ -228,7 +261,9 @@ func (db *MySQLDatabase) SaveBookmarks(ctx context.Context, create bool, bookmar
 			// Save book tags
 			newTags := []model.TagDTO{}
 			for _, tag := range book.Tags {
-				t := tag.ToDTO()
+				t := tag
+				// Only the tags inserted below are reported as created
+				t.Created = false
 				// If it's deleted tag, delete and continue
 				if t.Deleted {
 					_, err = stmtDeleteBookTag.ExecContext(ctx, book.ID, t.ID)
@@ -249,6 +284,18 @@ func (db *MySQLDatabase) SaveBookmarks(ctx context.Context, create bool, bookmar
 						return errors.WithStack(err)
 					}
 
+					// The name may be an alias, the bookmark gets the tag it stands for
+					if tag.ID == 0 {
+						var aliased model.Tag
+						if err := stmtGetTagAlias.GetContext(ctx, &aliased, tagName); err != nil && err != sql.ErrNoRows {
+							return errors.WithStack(err)
+						}
+						tag.ID, t.ID = aliased.ID, aliased.ID
+						if aliased.ID != 0 {
+							t.Name = aliased.Name
+						}
+					}
+
 					// If tag doesn't exist in database, save it
 					if tag.ID == 0 {
 						res, err := stmtInsertTag.ExecContext(ctx, tagName)
@@ -263,6 +310,7 @@ func (db *MySQLDatabase) SaveBookmarks(ctx context.Context, create bool, bookmar
 
 						tag.ID = int(tagID64)
 						t.ID = int(tagID64)
+						t.Created = true
 					}
 				}
 
@@ -286,11 +334,40 @@ func (db *MySQLDatabase) SaveBookmarks(ctx context.Context, create bool, bookmar
 	return result, nil
 }
 
+// mysqlSearch compiles search queries, the title, excerpt and content of the bookmarks are
+// searched in their full text index.
+var mysqlSearch = searchDialect{
+	ID:         `id`,
+	URL:        `url`,
+	Public:     `public`,
+	CreatedAt:  `created_at`,
+	HasContent: `content <> ''`,
+	Text: func(term model.SearchTerm, bind func(value interface{}) string) string {
+		// Quote the text so its words are searched as a phrase, without boolean operators
+		phrase := `"` + strings.ReplaceAll(term.Value, `"`, ``) + `"`
+		return `url LIKE ` + bind("%"+term.Value+"%") + ` OR
+			MATCH(title, excerpt, content) AGAINST (` + bind(phrase) + ` IN BOOLEAN MODE)`
+	},
+	ArchiveText: func(term model.SearchTerm, bind func(value interface{}) string) string {
+		phrase := `"` + strings.ReplaceAll(term.Value, `"`, ``) + `"`
+		return `id IN (SELECT bookmark_id FROM bookmark_archive_text
+			WHERE MATCH(content) AGAINST (` + bind(phrase) + ` IN BOOLEAN MODE))`
+	},
+	Rank: func(terms []model.SearchTerm, bind func(value interface{}) string) string {
+		words := make([]string, len(terms))
+		for i, term := range terms {
+			words[i] = term.Value
+		}
+		return `MATCH(title, excerpt, content) AGAINST (` + bind(strings.Join(words, " ")) + ` IN NATURAL LANGUAGE MODE) DESC`
+	},
+}
+
 // GetBookmarks fetch list of bookmarks based on submitted options.
 func (db *MySQLDatabase) GetBookmarks(ctx context.Context, opts model.DBGetBookmarksOptions) ([]model.BookmarkDTO, error) {
 	// Create initial query
 	columns := []string{
 		`id`,
+		`account_id`,
 		`url`,
 		`title`,
 		`excerpt`,
@@ -298,10 +375,15 @@ func (db *MySQLDatabase) GetBookmarks(ctx context.Context, opts model.DBGetBookm
 		`public`,
 		`created_at`,
 		`modified_at`,
-		`content <> "" as has_content`}
+		`content <> "" as has_content`,
+		`collection_id`}
 
+	// Snippets of the text searched are taken from the content
+	snippetQuery, withSnippets := searchSnippetQuery(opts.Keyword)
 	if opts.WithContent {
 		columns = append(columns, `content`, `html`)
+	} else if withSnippets {
+		columns = append(columns, `content`)
 	}
 
 	query := `SELECT ` + strings.Join(columns, ",") + `
@@ -310,20 +392,43 @@ func (db *MySQLDatabase) GetBookmarks(ctx context.Context, opts model.DBGetBookm
 	// Add where clause
 	args := []interface{}{}
 
+	// Add where clause for owner account
+	if opts.AccountID > 0 {
+		query += ` AND account_id = ?`
+		args = append(args, opts.AccountID)
+	}
+
+	// Add where clause for public bookmarks
+	if opts.PublicOnly {
+		query += ` AND public = 1`
+	}
+
+	// Add where clause for link status
+	if condition := linkStatusCondition(`id`, opts.LinkStatus); condition != "" {
+		query += ` AND ` + condition
+	}
+
+	// Add where clause for collection
+	if condition := collectionCondition(`collection_id`, opts.CollectionID); condition != "" {
+		query += ` AND ` + condition
+	}
+
+	// Add where clause for creation time
+	if condition := addedAfterCondition(`created_at`, opts.AddedAfter, bindArg(&args)); condition != "" {
+		query += ` AND ` + condition
+	}
+
 	// Add where clause for IDs
 	if len(opts.IDs) > 0 {
 		query += ` AND id IN (?)`
 		args = append(args, opts.IDs)
 	}
 
-	// Add where clause for search keyword
-	if opts.Keyword != "" {
-		query += ` AND (
-			url LIKE ? OR
-			MATCH(title, excerpt, content) AGAINST (? IN BOOLEAN MODE)
-		)`
-
-		args = append(args, "%"+opts.Keyword+"%", opts.Keyword)
+	// Add where clause for search query
+	if condition, err := db.searchCondition(ctx, opts.Keyword, mysqlSearch, bindArg(&args)); err != nil {
+		return nil, err
+	} else if condition != "" {
+		query += ` AND ` + condition
 	}
 
 	// Add where clause for tags.
@@ -349,27 +454,11 @@ func (db *MySQLDatabase) GetBookmarks(ctx context.Context, opts model.DBGetBookm
 		query += ` AND id IN (SELECT DISTINCT bookmark_id FROM bookmark_tag)`
 	}
 
-	// Now we only need to find the normal tags
-	if len(opts.Tags) > 0 {
-		query += ` AND id IN (
-			SELECT bt.bookmark_id
-			FROM bookmark_tag bt
-			LEFT JOIN tag t ON bt.tag_id = t.id
-			WHERE t.name IN(?)
-			GROUP BY bt.bookmark_id
-			HAVING COUNT(bt.bookmark_id) = ?)`
-
-		args = append(args, opts.Tags, len(opts.Tags))
-	}
-
-	if len(opts.ExcludedTags) > 0 {
-		query += ` AND id NOT IN (
-			SELECT DISTINCT bt.bookmark_id
-			FROM bookmark_tag bt
-			LEFT JOIN tag t ON bt.tag_id = t.id
-			WHERE t.name IN(?))`
-
-		args = append(args, opts.ExcludedTags)
+	// Now we only need to find the normal tags, with their aliases and children
+	if condition, err := db.tagsCondition(ctx, "id", opts.Tags, opts.ExcludedTags); err != nil {
+		return nil, err
+	} else if condition != "" {
+		query += " AND " + condition
 	}
 
 	// Add order clause
@@ -378,6 +467,8 @@ func (db *MySQLDatabase) GetBookmarks(ctx context.Context, opts model.DBGetBookm
 		query += ` ORDER BY id DESC`
 	case model.ByLastModified:
 		query += ` ORDER BY modified_at DESC`
+	case model.ByRelevance:
+		query += ` ORDER BY ` + searchOrder(opts.Keyword, mysqlSearch, bindArg(&args), `id DESC`)
 	default:
 		query += ` ORDER BY id`
 	}
@@ -400,6 +491,17 @@ func (db *MySQLDatabase) GetBookmarks(ctx context.Context, opts model.DBGetBookm
 		return nil, errors.WithStack(err)
 	}
 
+	if withSnippets {
+		if err := db.setSearchSnippets(ctx, bookmarks, snippetQuery); err != nil {
+			return nil, err
+		}
+		if !opts.WithContent {
+			for i := range bookmarks {
+				bookmarks[i].Content = ""
+			}
+		}
+	}
+
 	// Fetch tags for each bookmark
 	for i, book := range bookmarks {
 		tags, err := db.getTagsForBookmark(ctx, book.ID)
@@ -440,22 +542,43 @@ func (db *MySQLDatabase) GetBookmarksCount(ctx context.Context, opts model.DBGet
 	// Add where clause
 	args := []interface{}{}
 
+	// Add where clause for owner account
+	if opts.AccountID > 0 {
+		query += ` AND account_id = ?`
+		args = append(args, opts.AccountID)
+	}
+
+	// Add where clause for public bookmarks
+	if opts.PublicOnly {
+		query += ` AND public = 1`
+	}
+
+	// Add where clause for link status
+	if condition := linkStatusCondition(`id`, opts.LinkStatus); condition != "" {
+		query += ` AND ` + condition
+	}
+
+	// Add where clause for collection
+	if condition := collectionCondition(`collection_id`, opts.CollectionID); condition != "" {
+		query += ` AND ` + condition
+	}
+
+	// Add where clause for creation time
+	if condition := addedAfterCondition(`created_at`, opts.AddedAfter, bindArg(&args)); condition != "" {
+		query += ` AND ` + condition
+	}
+
 	// Add where clause for IDs
 	if len(opts.IDs) > 0 {
 		query += ` AND id IN (?)`
 		args = append(args, opts.IDs)
 	}
 
-	// Add where clause for search keyword
-	if opts.Keyword != "" {
-		query += ` AND (
-			url LIKE ? OR
-			MATCH(title, excerpt, content) AGAINST (? IN BOOLEAN MODE)
-		)`
-
-		args = append(args,
-			"%"+opts.Keyword+"%",
-			opts.Keyword)
+	// Add where clause for search query
+	if condition, err := db.searchCondition(ctx, opts.Keyword, mysqlSearch, bindArg(&args)); err != nil {
+		return 0, err
+	} else if condition != "" {
+		query += ` AND ` + condition
 	}
 
 	// Add where clause for tags.
@@ -481,27 +604,11 @@ func (db *MySQLDatabase) GetBookmarksCount(ctx context.Context, opts model.DBGet
 		query += ` AND id IN (SELECT DISTINCT bookmark_id FROM bookmark_tag)`
 	}
 
-	// Now we only need to find the normal tags
-	if len(opts.Tags) > 0 {
-		query += ` AND id IN (
-			SELECT bt.bookmark_id
-			FROM bookmark_tag bt
-			LEFT JOIN tag t ON bt.tag_id = t.id
-			WHERE t.name IN(?)
-			GROUP BY bt.bookmark_id
-			HAVING COUNT(bt.bookmark_id) = ?)`
-
-		args = append(args, opts.Tags, len(opts.Tags))
-	}
-
-	if len(opts.ExcludedTags) > 0 {
-		query += ` AND id NOT IN (
-			SELECT DISTINCT bt.bookmark_id
-			FROM bookmark_tag bt
-			LEFT JOIN tag t ON bt.tag_id = t.id
-			WHERE t.name IN(?))`
-
-		args = append(args, opts.ExcludedTags)
+	// Now we only need to find the normal tags, with their aliases and children
+	if condition, err := db.tagsCondition(ctx, "id", opts.Tags, opts.ExcludedTags); err != nil {
+		return 0, err
+	} else if condition != "" {
+		query += " AND " + condition
 	}
 
 	// Expand query, because some of the args might be an array
@@ -521,11 +628,25 @@ func (db *MySQLDatabase) GetBookmarksCount(ctx context.Context, opts model.DBGet
 }
 
 // DeleteBookmarks removes all record with matching ids from database.
-func (db *MySQLDatabase) DeleteBookmarks(ctx context.Context, ids ...int) (err error) {
+func (db *MySQLDatabase) DeleteBookmarks(ctx context.Context, accountID model.DBID, ids ...int) (err error) {
+	if accountID > 0 {
+		if ids, err = db.accountBookmarkIDs(ctx, accountID, ids); err != nil {
+			return err
+		}
+
+		if len(ids) == 0 {
+			return nil
+		}
+	}
+
 	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
 		// Prepare queries
 		delBookmark := `DELETE FROM bookmark`
 		delBookmarkTag := `DELETE FROM bookmark_tag`
+		delArchiveSnapshot := `DELETE FROM archive_snapshot`
+		delLinkCheck := `DELETE FROM link_check`
+		delArchiveText := `DELETE FROM bookmark_archive_text`
+		delHighlight := `DELETE FROM bookmark_highlight`
 
 		// Delete bookmark(s)
 		if len(ids) == 0 {
@@ -534,6 +655,26 @@ func (db *MySQLDatabase) DeleteBookmarks(ctx context.Context, ids ...int) (err e
 				return errors.WithStack(err)
 			}
 
+			_, err = tx.ExecContext(ctx, delArchiveSnapshot)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			_, err = tx.ExecContext(ctx, delLinkCheck)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			_, err = tx.ExecContext(ctx, delArchiveText)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			_, err = tx.ExecContext(ctx, delHighlight)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
 			_, err = tx.ExecContext(ctx, delBookmark)
 			if err != nil {
 				return errors.WithStack(err)
@@ -541,9 +682,17 @@ func (db *MySQLDatabase) DeleteBookmarks(ctx context.Context, ids ...int) (err e
 		} else {
 			delBookmark += ` WHERE id = ?`
 			delBookmarkTag += ` WHERE bookmark_id = ?`
+			delArchiveSnapshot += ` WHERE bookmark_id = ?`
+			delLinkCheck += ` WHERE bookmark_id = ?`
+			delArchiveText += ` WHERE bookmark_id = ?`
+			delHighlight += ` WHERE bookmark_id = ?`
 
 			stmtDelBookmark, _ := tx.Preparex(delBookmark)
 			stmtDelBookmarkTag, _ := tx.Preparex(delBookmarkTag)
+			stmtDelArchiveSnapshot, _ := tx.Preparex(delArchiveSnapshot)
+			stmtDelLinkCheck, _ := tx.Preparex(delLinkCheck)
+			stmtDelArchiveText, _ := tx.Preparex(delArchiveText)
+			stmtDelHighlight, _ := tx.Preparex(delHighlight)
 
 			for _, id := range ids {
 				_, err := stmtDelBookmarkTag.ExecContext(ctx, id)
@@ -551,6 +700,26 @@ func (db *MySQLDatabase) DeleteBookmarks(ctx context.Context, ids ...int) (err e
 					return errors.WithStack(err)
 				}
 
+				_, err = stmtDelArchiveSnapshot.ExecContext(ctx, id)
+				if err != nil {
+					return errors.WithStack(err)
+				}
+
+				_, err = stmtDelLinkCheck.ExecContext(ctx, id)
+				if err != nil {
+					return errors.WithStack(err)
+				}
+
+				_, err = stmtDelArchiveText.ExecContext(ctx, id)
+				if err != nil {
+					return errors.WithStack(err)
+				}
+
+				_, err = stmtDelHighlight.ExecContext(ctx, id)
+				if err != nil {
+					return errors.WithStack(err)
+				}
+
 				_, err = stmtDelBookmark.ExecContext(ctx, id)
 				if err != nil {
 					return errors.WithStack(err)
@@ -568,12 +737,12 @@ func (db *MySQLDatabase) DeleteBookmarks(ctx context.Context, ids ...int) (err e
 
 // GetBookmark fetches bookmark based on its ID or URL.
 // Returns the bookmark and boolean whether it's exist or not.
-func (db *MySQLDatabase) GetBookmark(ctx context.Context, id int, url string) (model.BookmarkDTO, bool, error) {
+func (db *MySQLDatabase) GetBookmark(ctx context.Context, id int, url string, accountID model.DBID) (model.BookmarkDTO, bool, error) {
 	// Create the main query builder for bookmark data
 	sb := sqlbuilder.NewSelectBuilder()
 	sb.Select(
-		"id", "url", "title", "excerpt", "author", `public`, "modified_at",
-		"content", "html", "created_at", "has_content")
+		"id", "account_id", "url", "title", "excerpt", "author", `public`, "modified_at",
+		"content", "html", "created_at", "has_content", "collection_id")
 	sb.From("bookmark")
 
 	// Add conditions
@@ -585,6 +754,10 @@ func (db *MySQLDatabase) GetBookmark(ctx context.Context, id int, url string) (m
 		return model.BookmarkDTO{}, false, fmt.Errorf("id or url is required")
 	}
 
+	if accountID > 0 {
+		sb.Where(sb.Equal("account_id", accountID))
+	}
+
 	// Build the query
 	query, args := sb.Build()
 	query = db.ReaderDB().Rebind(query)
@@ -593,7 +766,7 @@ func (db *MySQLDatabase) GetBookmark(ctx context.Context, id int, url string) (m
 	err := db.ReaderDB().GetContext(ctx, &book, query, args...)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return book, false, nil
+			return model.BookmarkDTO{}, false, nil
 		}
 		return book, false, fmt.Errorf("failed to get bookmark: %w", err)
 	}
@@ -774,6 +947,46 @@ func (db *MySQLDatabase) DeleteAccount(ctx context.Context, id model.DBID) error
 			return ErrNotFound
 		}
 
+		if _, err := tx.ExecContext(ctx, `DELETE FROM api_token WHERE account_id = ?`, id); err != nil {
+			return fmt.Errorf("error deleting account tokens: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM session WHERE account_id = ?`, id); err != nil {
+			return fmt.Errorf("error deleting account sessions: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM account_totp WHERE account_id = ?`, id); err != nil {
+			return fmt.Errorf("error deleting account totp: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM account_identity WHERE account_id = ?`, id); err != nil {
+			return fmt.Errorf("error deleting account identities: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM feed_token WHERE account_id = ?`, id); err != nil {
+			return fmt.Errorf("error deleting feed token: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM subscription_item WHERE subscription_id IN (SELECT id FROM subscription WHERE account_id = ?)`, id); err != nil {
+			return fmt.Errorf("error deleting subscription items: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM subscription WHERE account_id = ?`, id); err != nil {
+			return fmt.Errorf("error deleting subscriptions: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM webhook_delivery WHERE webhook_id IN (SELECT id FROM webhook WHERE account_id = ?)`, id); err != nil {
+			return fmt.Errorf("error deleting webhook deliveries: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM webhook WHERE account_id = ?`, id); err != nil {
+			return fmt.Errorf("error deleting webhooks: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM collection WHERE account_id = ?`, id); err != nil {
+			return fmt.Errorf("error deleting collections: %w", err)
+		}
+
 		return nil
 	}); err != nil {
 		return fmt.Errorf("error running transaction: %w", err)
@@ -794,7 +1007,7 @@ func (db *MySQLDatabase) CreateTags(ctx context.Context, tags ...model.Tag) ([]m
 
 	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
 		// For MySQL, we need to insert tags one by one to get their IDs
-		stmtInsertTag, err := tx.PrepareContext(ctx, "INSERT INTO tag (name) VALUES (?)")
+		stmtInsertTag, err := tx.PrepareContext(ctx, "INSERT INTO tag (name, parent_id) VALUES (?, ?)")
 		if err != nil {
 			return fmt.Errorf("failed to prepare tag insertion statement: %w", err)
 		}
@@ -802,7 +1015,7 @@ func (db *MySQLDatabase) CreateTags(ctx context.Context, tags ...model.Tag) ([]m
 
 		// Insert each tag and get its ID
 		for i, tag := range createdTags {
-			result, err := stmtInsertTag.ExecContext(ctx, tag.Name)
+			result, err := stmtInsertTag.ExecContext(ctx, tag.Name, tag.ParentID)
 			if err != nil {
 				return fmt.Errorf("failed to insert tag: %w", err)
 			}
@@ -865,7 +1078,7 @@ func (db *MySQLDatabase) RenameTag(ctx context.Context, id int, newName string)
 // GetTag fetch a tag by its ID.
 func (db *MySQLDatabase) GetTag(ctx context.Context, id int) (model.TagDTO, bool, error) {
 	sb := sqlbuilder.MySQL.NewSelectBuilder()
-	sb.Select("t.id", "t.name", "COUNT(bt.tag_id) bookmark_count")
+	sb.Select("t.id", "t.name", "t.parent_id", "COUNT(bt.tag_id) bookmark_count")
 	sb.From("tag t")
 	sb.JoinWithOption(sqlbuilder.LeftJoin, "bookmark_tag bt", "bt.tag_id = t.id")
 	sb.Where(sb.Equal("t.id", id))
@@ -891,7 +1104,7 @@ func (db *MySQLDatabase) GetTag(ctx context.Context, id int) (model.TagDTO, bool
 func (db *MySQLDatabase) UpdateTag(ctx context.Context, tag model.Tag) error {
 	sb := sqlbuilder.NewUpdateBuilder()
 	sb.Update("tag")
-	sb.Set(sb.Assign("name", tag.Name))
+	sb.Set(sb.Assign("name", tag.Name), sb.Assign("parent_id", tag.ParentID))
 	sb.Where(sb.Equal("id", tag.ID))
 
 	query, args := sb.Build()
@@ -929,6 +1142,22 @@ func (db *MySQLDatabase) DeleteTag(ctx context.Context, id int) error {
 	deleteAssocQuery, deleteAssocArgs := deleteAssocSb.Build()
 	deleteAssocQuery = db.WriterDB().Rebind(deleteAssocQuery)
 
+	// Remove its aliases and move its children to the top level
+	deleteAliasesSb := sqlbuilder.NewDeleteBuilder()
+	deleteAliasesSb.DeleteFrom("tag_alias")
+	deleteAliasesSb.Where(deleteAliasesSb.Equal("tag_id", id))
+
+	deleteAliasesQuery, deleteAliasesArgs := deleteAliasesSb.Build()
+	deleteAliasesQuery = db.WriterDB().Rebind(deleteAliasesQuery)
+
+	orphanChildrenSb := sqlbuilder.NewUpdateBuilder()
+	orphanChildrenSb.Update("tag")
+	orphanChildrenSb.Set(orphanChildrenSb.Assign("parent_id", nil))
+	orphanChildrenSb.Where(orphanChildrenSb.Equal("parent_id", id))
+
+	orphanChildrenQuery, orphanChildrenArgs := orphanChildrenSb.Build()
+	orphanChildrenQuery = db.WriterDB().Rebind(orphanChildrenQuery)
+
 	// Then, delete the tag itself
 	deleteTagSb := sqlbuilder.NewDeleteBuilder()
 	deleteTagSb.DeleteFrom("tag")
@@ -944,6 +1173,14 @@ func (db *MySQLDatabase) DeleteTag(ctx context.Context, id int) error {
 			return fmt.Errorf("failed to delete tag associations: %w", err)
 		}
 
+		if _, err := tx.ExecContext(ctx, deleteAliasesQuery, deleteAliasesArgs...); err != nil {
+			return fmt.Errorf("failed to delete tag aliases: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, orphanChildrenQuery, orphanChildrenArgs...); err != nil {
+			return fmt.Errorf("failed to move child tags: %w", err)
+		}
+
 		// Delete the tag
 		_, err = tx.ExecContext(ctx, deleteTagQuery, deleteTagArgs...)
 		if err != nil {
@@ -1142,3 +1379,468 @@ func (db *MySQLDatabase) BulkUpdateBookmarkTags(ctx context.Context, bookmarkIDs
 		return nil
 	})
 }
+
+// CreateJob adds a new job to the queue.
+func (db *MySQLDatabase) CreateJob(ctx context.Context, job model.Job) (*model.Job, error) {
+	now := time.Now().UTC().Format(model.DatabaseDateFormat)
+	if job.Status == "" {
+		job.Status = model.JobStatusPending
+	}
+	if job.RunAt == "" {
+		job.RunAt = now
+	}
+	job.CreatedAt = now
+	job.UpdatedAt = now
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.MySQL.NewInsertBuilder()
+		ib.InsertInto("job")
+		ib.Cols("type", "payload", "status", "attempts", "max_attempts", "last_error", "run_at", "created_at", "updated_at")
+		ib.Values(job.Type, job.Payload, job.Status, job.Attempts, job.MaxAttempts, job.LastError, job.RunAt, job.CreatedAt, job.UpdatedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query)
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert job: %w", err)
+		}
+
+		jobID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+
+		job.ID = model.DBID(jobID)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// CreateArchiveSnapshot records a new archive snapshot of a bookmark.
+func (db *MySQLDatabase) CreateArchiveSnapshot(ctx context.Context, snapshot model.ArchiveSnapshot) (*model.ArchiveSnapshot, error) {
+	if snapshot.CreatedAt == "" {
+		snapshot.CreatedAt = time.Now().UTC().Format(model.DatabaseDateFormat)
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.MySQL.NewInsertBuilder()
+		ib.InsertInto("archive_snapshot")
+		ib.Cols("bookmark_id", "path", "created_at")
+		ib.Values(snapshot.BookmarkID, snapshot.Path, snapshot.CreatedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query)
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert archive snapshot: %w", err)
+		}
+
+		snapshotID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+
+		snapshot.ID = model.DBID(snapshotID)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+// CreateLinkCheck stores the result of checking a bookmark URL.
+func (db *MySQLDatabase) CreateLinkCheck(ctx context.Context, check model.LinkCheck) (*model.LinkCheck, error) {
+	if check.CheckedAt == "" {
+		check.CheckedAt = time.Now().UTC().Format(model.DatabaseDateFormat)
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.MySQL.NewInsertBuilder()
+		ib.InsertInto("link_check")
+		ib.Cols("bookmark_id", "url", "status_code", "final_url", "latency_ms", "error", "checked_at")
+		ib.Values(check.BookmarkID, check.URL, check.StatusCode, check.FinalURL, check.LatencyMs, check.Error, check.CheckedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query)
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert link check: %w", err)
+		}
+
+		checkID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+
+		check.ID = model.DBID(checkID)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &check, nil
+}
+
+// CreateAPIToken stores a new personal API token.
+func (db *MySQLDatabase) CreateAPIToken(ctx context.Context, token model.APIToken) (*model.APIToken, error) {
+	if token.CreatedAt == "" {
+		token.CreatedAt = time.Now().UTC().Format(model.DatabaseDateFormat)
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.MySQL.NewInsertBuilder()
+		ib.InsertInto("api_token")
+		ib.Cols("account_id", "name", "token_hash", "scopes", "expires_at", "created_at")
+		ib.Values(token.AccountID, token.Name, token.TokenHash, token.Scopes, token.ExpiresAt, token.CreatedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query)
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert api token: %w", err)
+		}
+
+		tokenID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+
+		token.ID = model.DBID(tokenID)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// CreateAccountIdentity links an account to a user of an OpenID Connect provider.
+func (db *MySQLDatabase) CreateAccountIdentity(ctx context.Context, identity model.AccountIdentity) (*model.AccountIdentity, error) {
+	if identity.CreatedAt == "" {
+		identity.CreatedAt = time.Now().UTC().Format(model.DatabaseDateFormat)
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.MySQL.NewInsertBuilder()
+		ib.InsertInto("account_identity")
+		ib.Cols("account_id", "issuer", "subject", "created_at")
+		ib.Values(identity.AccountID, identity.Issuer, identity.Subject, identity.CreatedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query)
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert account identity: %w", err)
+		}
+
+		identityID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+
+		identity.ID = model.DBID(identityID)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &identity, nil
+}
+
+// CreateSubscription stores a new feed subscription.
+func (db *MySQLDatabase) CreateSubscription(ctx context.Context, subscription model.Subscription) (*model.Subscription, error) {
+	if subscription.CreatedAt == "" {
+		subscription.CreatedAt = time.Now().UTC().Format(model.DatabaseDateFormat)
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.MySQL.NewInsertBuilder()
+		ib.InsertInto("subscription")
+		ib.Cols("account_id", "url", "title", "tags", "create_archive", "create_ebook", "created_at")
+		ib.Values(subscription.AccountID, subscription.URL, subscription.Title, subscription.Tags,
+			subscription.CreateArchive, subscription.CreateEbook, subscription.CreatedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query)
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert subscription: %w", err)
+		}
+
+		subscriptionID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+
+		subscription.ID = model.DBID(subscriptionID)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+// CreateWebhook stores a new webhook.
+func (db *MySQLDatabase) CreateWebhook(ctx context.Context, webhook model.Webhook) (*model.Webhook, error) {
+	if webhook.CreatedAt == "" {
+		webhook.CreatedAt = time.Now().UTC().Format(model.DatabaseDateFormat)
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.MySQL.NewInsertBuilder()
+		ib.InsertInto("webhook")
+		ib.Cols("account_id", "url", "events", "secret", "created_at")
+		ib.Values(webhook.AccountID, webhook.URL, webhook.Events, webhook.Secret, webhook.CreatedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query)
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert webhook: %w", err)
+		}
+
+		webhookID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+
+		webhook.ID = model.DBID(webhookID)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+// CreateWebhookDelivery stores a new webhook delivery.
+func (db *MySQLDatabase) CreateWebhookDelivery(ctx context.Context, delivery model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	if delivery.CreatedAt == "" {
+		delivery.CreatedAt = time.Now().UTC().Format(model.DatabaseDateFormat)
+	}
+	if delivery.Status == "" {
+		delivery.Status = model.WebhookDeliveryStatusPending
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.MySQL.NewInsertBuilder()
+		ib.InsertInto("webhook_delivery")
+		ib.Cols("webhook_id", "event", "payload", "status", "response_body", "last_error", "created_at")
+		ib.Values(delivery.WebhookID, delivery.Event, delivery.Payload, delivery.Status, delivery.ResponseBody,
+			delivery.LastError, delivery.CreatedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query)
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert webhook delivery: %w", err)
+		}
+
+		deliveryID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+
+		delivery.ID = model.DBID(deliveryID)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+// CreateCollection stores a new collection.
+func (db *MySQLDatabase) CreateCollection(ctx context.Context, collection model.Collection) (*model.Collection, error) {
+	now := time.Now().UTC().Format(model.DatabaseDateFormat)
+	if collection.CreatedAt == "" {
+		collection.CreatedAt = now
+	}
+	if collection.ModifiedAt == "" {
+		collection.ModifiedAt = collection.CreatedAt
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.MySQL.NewInsertBuilder()
+		ib.InsertInto("collection")
+		ib.Cols("account_id", "parent_id", "name", "position", "created_at", "modified_at")
+		ib.Values(collection.AccountID, collection.ParentID, collection.Name, collection.Position, collection.CreatedAt, collection.ModifiedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query)
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert collection: %w", err)
+		}
+
+		collectionID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+
+		collection.ID = model.DBID(collectionID)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &collection, nil
+}
+
+// CreateTagAlias saves a new name resolving to an existing tag.
+func (db *MySQLDatabase) CreateTagAlias(ctx context.Context, alias model.TagAlias) (model.TagAlias, error) {
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.MySQL.NewInsertBuilder()
+		ib.InsertInto("tag_alias")
+		ib.Cols("name", "tag_id")
+		ib.Values(alias.Name, alias.TagID)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query)
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert tag alias: %w", err)
+		}
+
+		aliasID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+
+		alias.ID = int(aliasID)
+		return nil
+	}); err != nil {
+		return model.TagAlias{}, err
+	}
+
+	return alias, nil
+}
+
+// CreateBookmarkRule stores a new bookmark rule.
+func (db *MySQLDatabase) CreateBookmarkRule(ctx context.Context, rule model.BookmarkRule) (*model.BookmarkRule, error) {
+	now := time.Now().UTC().Format(model.DatabaseDateFormat)
+	if rule.CreatedAt == "" {
+		rule.CreatedAt = now
+	}
+	if rule.ModifiedAt == "" {
+		rule.ModifiedAt = rule.CreatedAt
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.MySQL.NewInsertBuilder()
+		ib.InsertInto("bookmark_rule")
+		ib.Cols("account_id", "name", "enabled", "conditions", "actions", "created_at", "modified_at")
+		ib.Values(rule.AccountID, rule.Name, rule.Enabled, rule.Conditions, rule.Actions, rule.CreatedAt, rule.ModifiedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query)
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert bookmark rule: %w", err)
+		}
+
+		ruleID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+
+		rule.ID = model.DBID(ruleID)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+// CreateSavedSearch stores a new saved search.
+func (db *MySQLDatabase) CreateSavedSearch(ctx context.Context, search model.SavedSearch) (*model.SavedSearch, error) {
+	now := time.Now().UTC().Format(model.DatabaseDateFormat)
+	if search.CreatedAt == "" {
+		search.CreatedAt = now
+	}
+	if search.ModifiedAt == "" {
+		search.ModifiedAt = search.CreatedAt
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.MySQL.NewInsertBuilder()
+		ib.InsertInto("saved_search")
+		ib.Cols("account_id", "name", "filter", "created_at", "modified_at")
+		ib.Values(search.AccountID, search.Name, search.Filter, search.CreatedAt, search.ModifiedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query)
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert saved search: %w", err)
+		}
+
+		searchID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+
+		search.ID = model.DBID(searchID)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &search, nil
+}
+
+// CreateHighlight stores a new highlight.
+func (db *MySQLDatabase) CreateHighlight(ctx context.Context, highlight model.Highlight) (*model.Highlight, error) {
+	now := time.Now().UTC().Format(model.DatabaseDateFormat)
+	if highlight.CreatedAt == "" {
+		highlight.CreatedAt = now
+	}
+	if highlight.ModifiedAt == "" {
+		highlight.ModifiedAt = highlight.CreatedAt
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.MySQL.NewInsertBuilder()
+		ib.InsertInto("bookmark_highlight")
+		ib.Cols("bookmark_id", "account_id", "selector", "note", "created_at", "modified_at")
+		ib.Values(highlight.BookmarkID, highlight.AccountID, highlight.Selector, highlight.Note, highlight.CreatedAt, highlight.ModifiedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query)
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert highlight: %w", err)
+		}
+
+		highlightID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+
+		highlight.ID = model.DBID(highlightID)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &highlight, nil
+}
@@ -70,6 +70,24 @@ var postgresMigrations = []migration{
 		return nil
 	}),
 	newFileMigration("0.3.0", "0.4.0", "postgres/0002_created_time"),
+	newFileMigration("0.4.0", "0.5.0", "postgres/0003_bookmark_account"),
+	newFileMigration("0.5.0", "0.6.0", "postgres/0004_job"),
+	newFileMigration("0.6.0", "0.7.0", "postgres/0005_archive_snapshot"),
+	newFileMigration("0.7.0", "0.8.0", "postgres/0006_link_check"),
+	newFileMigration("0.8.0", "0.9.0", "postgres/0007_api_token"),
+	newFileMigration("0.9.0", "0.10.0", "postgres/0008_session"),
+	newFileMigration("0.10.0", "0.11.0", "postgres/0009_totp"),
+	newFileMigration("0.11.0", "0.12.0", "postgres/0010_feed_token"),
+	newFileMigration("0.12.0", "0.13.0", "postgres/0011_subscription"),
+	newFileMigration("0.13.0", "0.14.0", "postgres/0012_webhook"),
+	newFileMigration("0.14.0", "0.15.0", "postgres/0013_collection"),
+	newFileMigration("0.15.0", "0.16.0", "postgres/0014_tag_alias"),
+	newFileMigration("0.16.0", "0.17.0", "postgres/0015_bookmark_rule"),
+	newFileMigration("0.17.0", "0.18.0", "postgres/0016_saved_search"),
+	newFileMigration("0.18.0", "0.19.0", "postgres/0017_bookmark_search"),
+	newFileMigration("0.19.0", "0.20.0", "postgres/0018_bookmark_archive_text"),
+	newFileMigration("0.20.0", "0.21.0", "postgres/0019_bookmark_highlight"),
+	newFileMigration("0.21.0", "0.22.0", "postgres/0020_account_identity"),
 }
 
 // PGDatabase is implementation of Database interface
@@ -141,8 +159,8 @@ func (db *PGDatabase) SaveBookmarks(ctx context.Context, create bool, bookmarks
 	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
 		// Prepare statement
 		stmtInsertBook, err := tx.Preparex(`INSERT INTO bookmark
-			(url, title, excerpt, author, public, content, html, modified_at, created_at)
-			VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			(account_id, url, title, excerpt, author, public, content, html, modified_at, created_at, collection_id)
+			VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		RETURNING id`)
 		if err != nil {
 			return errors.WithStack(err)
@@ -167,6 +185,12 @@ func (db *PGDatabase) SaveBookmarks(ctx context.Context, create bool, bookmarks
 			return errors.WithStack(err)
 		}
 
+		stmtGetTagAlias, err := tx.Preparex(`SELECT t.id, t.name FROM tag_alias a
+			JOIN tag t ON t.id = a.tag_id WHERE a.name = $1`)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
 		stmtInsertTag, err := tx.Preparex(`INSERT INTO tag (name) VALUES ($1) RETURNING id`)
 		if err != nil {
 			return errors.WithStack(err)
@@ -207,10 +231,12 @@ func (db *PGDatabase) SaveBookmarks(ctx context.Context, create bool, bookmarks
 			// Save bookmark
 			var err error
 			if create {
-				book.CreatedAt = modifiedTime
+				if book.CreatedAt == "" {
+					book.CreatedAt = modifiedTime
+				}
 				err = stmtInsertBook.QueryRowContext(ctx,
-					book.URL, book.Title, book.Excerpt, book.Author,
-					book.Public, book.Content, book.HTML, book.ModifiedAt, book.CreatedAt).Scan(&book.ID)
+					book.AccountID, book.URL, book.Title, book.Excerpt, book.Author,
+					book.Public, book.Content, book.HTML, book.ModifiedAt, book.CreatedAt, book.CollectionID).Scan(&book.ID)
 			} else {
 				_, err = stmtUpdateBook.ExecContext(ctx,
 					book.URL, book.Title, book.Excerpt, book.Author,
@@ -223,7 +249,9 @@ func (db *PGDatabase) SaveBookmarks(ctx context.Context, create bool, bookmarks
 			// Save book tags
 			newTags := []model.TagDTO{}
 			for _, tag := range book.Tags {
-				t := tag.ToDTO()
+				t := tag
+				// Only the tags inserted below are reported as created
+				t.Created = false
 				// If it's deleted tag, delete and continue
 				if t.Deleted {
 					_, err = stmtDeleteBookTag.ExecContext(ctx, book.ID, t.ID)
@@ -244,6 +272,19 @@ func (db *PGDatabase) SaveBookmarks(ctx context.Context, create bool, bookmarks
 						return errors.WithStack(err)
 					}
 
+					// The name may be an alias, the bookmark gets the tag it stands for
+					if tag.ID == 0 {
+						var aliased model.Tag
+						err = stmtGetTagAlias.GetContext(ctx, &aliased, tagName)
+						if err != nil && !errors.Is(err, sql.ErrNoRows) {
+							return errors.WithStack(err)
+						}
+						tag.ID, t.ID = aliased.ID, aliased.ID
+						if aliased.ID != 0 {
+							t.Name = aliased.Name
+						}
+					}
+
 					// If tag doesn't exist in database, save it
 					if tag.ID == 0 {
 						var tagID64 int64
@@ -254,6 +295,7 @@ func (db *PGDatabase) SaveBookmarks(ctx context.Context, create bool, bookmarks
 
 						tag.ID = int(tagID64)
 						t.ID = int(tagID64)
+						t.Created = true
 					}
 
 					if _, err := stmtInsertBookTag.ExecContext(ctx, tag.ID, book.ID); err != nil {
@@ -276,11 +318,43 @@ func (db *PGDatabase) SaveBookmarks(ctx context.Context, create bool, bookmarks
 	return result, nil
 }
 
+// pgSearch compiles search queries. Text is matched anywhere in the url, title, excerpt
+// and content, or by whole words in the text search vector of the bookmarks, which weighs
+// the title, excerpt and the first 500000 characters of the content in this order for
+// the ranking.
+var pgSearch = searchDialect{
+	ID:         `id`,
+	URL:        `url`,
+	Public:     `public`,
+	CreatedAt:  `created_at`,
+	HasContent: `content <> ''`,
+	Text: func(term model.SearchTerm, bind func(value interface{}) string) string {
+		kw := bind(term.Value)
+		return `url LIKE '%' || ` + kw + ` || '%' OR
+			title LIKE '%' || ` + kw + ` || '%' OR
+			excerpt LIKE '%' || ` + kw + ` || '%' OR
+			content LIKE '%' || ` + kw + ` || '%' OR
+			search_vector @@ phraseto_tsquery('simple', ` + kw + `)`
+	},
+	ArchiveText: func(term model.SearchTerm, bind func(value interface{}) string) string {
+		return `id IN (SELECT bookmark_id FROM bookmark_archive_text
+			WHERE search_vector @@ phraseto_tsquery('simple', ` + bind(term.Value) + `))`
+	},
+	Rank: func(terms []model.SearchTerm, bind func(value interface{}) string) string {
+		queries := make([]string, len(terms))
+		for i, term := range terms {
+			queries[i] = `phraseto_tsquery('simple', ` + bind(term.Value) + `)`
+		}
+		return `ts_rank(search_vector, ` + strings.Join(queries, ` || `) + `) DESC`
+	},
+}
+
 // GetBookmarks fetch list of bookmarks based on submitted options.
 func (db *PGDatabase) GetBookmarks(ctx context.Context, opts model.DBGetBookmarksOptions) ([]model.BookmarkDTO, error) {
 	// Create initial query
 	columns := []string{
 		`id`,
+		`account_id`,
 		`url`,
 		`title`,
 		`excerpt`,
@@ -288,10 +362,15 @@ func (db *PGDatabase) GetBookmarks(ctx context.Context, opts model.DBGetBookmark
 		`public`,
 		`created_at`,
 		`modified_at`,
-		`content <> '' has_content`}
+		`content <> '' has_content`,
+		`collection_id`}
 
+	// Snippets of the text searched are taken from the content
+	snippetQuery, withSnippets := searchSnippetQuery(opts.Keyword)
 	if opts.WithContent {
 		columns = append(columns, `content`, `html`)
+	} else if withSnippets {
+		columns = append(columns, `content`)
 	}
 
 	query := `SELECT ` + strings.Join(columns, ",") + `
@@ -300,22 +379,43 @@ func (db *PGDatabase) GetBookmarks(ctx context.Context, opts model.DBGetBookmark
 	// Add where clause
 	arg := map[string]interface{}{}
 
+	// Add where clause for owner account
+	if opts.AccountID > 0 {
+		query += ` AND account_id = :account_id`
+		arg["account_id"] = opts.AccountID
+	}
+
+	// Add where clause for public bookmarks
+	if opts.PublicOnly {
+		query += ` AND public = 1`
+	}
+
+	// Add where clause for link status
+	if condition := linkStatusCondition(`id`, opts.LinkStatus); condition != "" {
+		query += ` AND ` + condition
+	}
+
+	// Add where clause for collection
+	if condition := collectionCondition(`collection_id`, opts.CollectionID); condition != "" {
+		query += ` AND ` + condition
+	}
+
+	// Add where clause for creation time
+	if condition := addedAfterCondition(`created_at`, opts.AddedAfter, bindNamedArg(arg)); condition != "" {
+		query += ` AND ` + condition
+	}
+
 	// Add where clause for IDs
 	if len(opts.IDs) > 0 {
 		query += ` AND id IN (:ids)`
 		arg["ids"] = opts.IDs
 	}
 
-	// Add where clause for search keyword
-	if opts.Keyword != "" {
-		query += ` AND (
-			url LIKE '%' || :kw || '%' OR
-			title LIKE '%' || :kw || '%' OR
-			excerpt LIKE '%' || :kw || '%' OR
-			content LIKE '%' || :kw || '%'
-		)`
-
-		arg["kw"] = opts.Keyword
+	// Add where clause for search query
+	if condition, err := db.searchCondition(ctx, opts.Keyword, pgSearch, bindNamedArg(arg)); err != nil {
+		return nil, err
+	} else if condition != "" {
+		query += ` AND ` + condition
 	}
 
 	// Add where clause for tags.
@@ -341,28 +441,11 @@ func (db *PGDatabase) GetBookmarks(ctx context.Context, opts model.DBGetBookmark
 		query += ` AND id IN (SELECT DISTINCT bookmark_id FROM bookmark_tag)`
 	}
 
-	// Now we only need to find the normal tags
-	if len(opts.Tags) > 0 {
-		query += ` AND id IN (
-			SELECT bt.bookmark_id
-			FROM bookmark_tag bt
-			LEFT JOIN tag t ON bt.tag_id = t.id
-			WHERE t.name IN(:tags)
-			GROUP BY bt.bookmark_id
-			HAVING COUNT(bt.bookmark_id) = :ltags)`
-
-		arg["tags"] = opts.Tags
-		arg["ltags"] = len(opts.Tags)
-	}
-
-	if len(opts.ExcludedTags) > 0 {
-		query += ` AND id NOT IN (
-			SELECT DISTINCT bt.bookmark_id
-			FROM bookmark_tag bt
-			LEFT JOIN tag t ON bt.tag_id = t.id
-			WHERE t.name IN(:extags))`
-
-		arg["extags"] = opts.ExcludedTags
+	// Now we only need to find the normal tags, with their aliases and children
+	if condition, err := db.tagsCondition(ctx, "id", opts.Tags, opts.ExcludedTags); err != nil {
+		return nil, err
+	} else if condition != "" {
+		query += " AND " + condition
 	}
 
 	// Add order clause
@@ -371,6 +454,8 @@ func (db *PGDatabase) GetBookmarks(ctx context.Context, opts model.DBGetBookmark
 		query += ` ORDER BY id DESC`
 	case model.ByLastModified:
 		query += ` ORDER BY modified_at DESC`
+	case model.ByRelevance:
+		query += ` ORDER BY ` + searchOrder(opts.Keyword, pgSearch, bindNamedArg(arg), `id DESC`)
 	default:
 		query += ` ORDER BY id`
 	}
@@ -397,6 +482,17 @@ func (db *PGDatabase) GetBookmarks(ctx context.Context, opts model.DBGetBookmark
 		return nil, fmt.Errorf("failed to fetch data: %v", err)
 	}
 
+	if withSnippets {
+		if err := db.setSearchSnippets(ctx, bookmarks, snippetQuery); err != nil {
+			return nil, err
+		}
+		if !opts.WithContent {
+			for i := range bookmarks {
+				bookmarks[i].Content = ""
+			}
+		}
+	}
+
 	// Fetch tags for each bookmarks
 	stmtGetTags, err := db.ReaderDB().PreparexContext(ctx, `SELECT t.id, t.name
 		FROM bookmark_tag bt
@@ -428,23 +524,43 @@ func (db *PGDatabase) GetBookmarksCount(ctx context.Context, opts model.DBGetBoo
 
 	arg := map[string]interface{}{}
 
+	// Add where clause for owner account
+	if opts.AccountID > 0 {
+		query += ` AND account_id = :account_id`
+		arg["account_id"] = opts.AccountID
+	}
+
+	// Add where clause for public bookmarks
+	if opts.PublicOnly {
+		query += ` AND public = 1`
+	}
+
+	// Add where clause for link status
+	if condition := linkStatusCondition(`id`, opts.LinkStatus); condition != "" {
+		query += ` AND ` + condition
+	}
+
+	// Add where clause for collection
+	if condition := collectionCondition(`collection_id`, opts.CollectionID); condition != "" {
+		query += ` AND ` + condition
+	}
+
+	// Add where clause for creation time
+	if condition := addedAfterCondition(`created_at`, opts.AddedAfter, bindNamedArg(arg)); condition != "" {
+		query += ` AND ` + condition
+	}
+
 	// Add where clause for IDs
 	if len(opts.IDs) > 0 {
 		query += ` AND id IN (:ids)`
 		arg["ids"] = opts.IDs
 	}
 
-	// Add where clause for search keyword
-	if opts.Keyword != "" {
-		query += ` AND (
-			url LIKE '%' || :kw || '%' OR
-			title LIKE '%' || :kw || '%' OR
-			excerpt LIKE '%' || :kw || '%' OR
-			content LIKE '%' || :kw || '%'
-		)`
-
-		arg["lurl"] = "%" + opts.Keyword + "%"
-		arg["kw"] = opts.Keyword
+	// Add where clause for search query
+	if condition, err := db.searchCondition(ctx, opts.Keyword, pgSearch, bindNamedArg(arg)); err != nil {
+		return 0, err
+	} else if condition != "" {
+		query += ` AND ` + condition
 	}
 
 	// Add where clause for tags.
@@ -476,28 +592,11 @@ func (db *PGDatabase) GetBookmarksCount(ctx context.Context, opts model.DBGetBoo
 		query += ` AND id IN (SELECT DISTINCT bookmark_id FROM bookmark_tag)`
 	}
 
-	// Now we only need to find the normal tags
-	if len(opts.Tags) > 0 {
-		query += ` AND id IN (
-			SELECT bt.bookmark_id
-			FROM bookmark_tag bt
-			LEFT JOIN tag t ON bt.tag_id = t.id
-			WHERE t.name IN(:tags)
-			GROUP BY bt.bookmark_id
-			HAVING COUNT(bt.bookmark_id) = :ltags)`
-
-		arg["tags"] = opts.Tags
-		arg["ltags"] = len(opts.Tags)
-	}
-
-	if len(opts.ExcludedTags) > 0 {
-		query += ` AND id NOT IN (
-			SELECT DISTINCT bt.bookmark_id
-			FROM bookmark_tag bt
-			LEFT JOIN tag t ON bt.tag_id = t.id
-			WHERE t.name IN(:etags))`
-
-		arg["etags"] = opts.ExcludedTags
+	// Now we only need to find the normal tags, with their aliases and children
+	if condition, err := db.tagsCondition(ctx, "id", opts.Tags, opts.ExcludedTags); err != nil {
+		return 0, err
+	} else if condition != "" {
+		query += " AND " + condition
 	}
 
 	// Expand query, because some of the args might be an array
@@ -524,11 +623,25 @@ func (db *PGDatabase) GetBookmarksCount(ctx context.Context, opts model.DBGetBoo
 }
 
 // DeleteBookmarks removes all record with matching ids from database.
-func (db *PGDatabase) DeleteBookmarks(ctx context.Context, ids ...int) (err error) {
+func (db *PGDatabase) DeleteBookmarks(ctx context.Context, accountID model.DBID, ids ...int) (err error) {
+	if accountID > 0 {
+		if ids, err = db.accountBookmarkIDs(ctx, accountID, ids); err != nil {
+			return err
+		}
+
+		if len(ids) == 0 {
+			return nil
+		}
+	}
+
 	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
 		// Prepare queries
 		delBookmark := `DELETE FROM bookmark`
 		delBookmarkTag := `DELETE FROM bookmark_tag`
+		delArchiveSnapshot := `DELETE FROM archive_snapshot`
+		delLinkCheck := `DELETE FROM link_check`
+		delArchiveText := `DELETE FROM bookmark_archive_text`
+		delHighlight := `DELETE FROM bookmark_highlight`
 
 		// Delete bookmark(s)
 		if len(ids) == 0 {
@@ -537,6 +650,26 @@ func (db *PGDatabase) DeleteBookmarks(ctx context.Context, ids ...int) (err erro
 				return errors.WithStack(err)
 			}
 
+			_, err = tx.ExecContext(ctx, delArchiveSnapshot)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			_, err = tx.ExecContext(ctx, delLinkCheck)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			_, err = tx.ExecContext(ctx, delArchiveText)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			_, err = tx.ExecContext(ctx, delHighlight)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
 			_, err = tx.ExecContext(ctx, delBookmark)
 			if err != nil {
 				return errors.WithStack(err)
@@ -544,6 +677,10 @@ func (db *PGDatabase) DeleteBookmarks(ctx context.Context, ids ...int) (err erro
 		} else {
 			delBookmark += ` WHERE id = $1`
 			delBookmarkTag += ` WHERE bookmark_id = $1`
+			delArchiveSnapshot += ` WHERE bookmark_id = $1`
+			delLinkCheck += ` WHERE bookmark_id = $1`
+			delArchiveText += ` WHERE bookmark_id = $1`
+			delHighlight += ` WHERE bookmark_id = $1`
 
 			stmtDelBookmark, err := tx.Preparex(delBookmark)
 			if err != nil {
@@ -553,6 +690,22 @@ func (db *PGDatabase) DeleteBookmarks(ctx context.Context, ids ...int) (err erro
 			if err != nil {
 				return errors.WithStack(err)
 			}
+			stmtDelArchiveSnapshot, err := tx.Preparex(delArchiveSnapshot)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			stmtDelLinkCheck, err := tx.Preparex(delLinkCheck)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			stmtDelArchiveText, err := tx.Preparex(delArchiveText)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			stmtDelHighlight, err := tx.Preparex(delHighlight)
+			if err != nil {
+				return errors.WithStack(err)
+			}
 
 			for _, id := range ids {
 				_, err = stmtDelBookmarkTag.ExecContext(ctx, id)
@@ -560,6 +713,26 @@ func (db *PGDatabase) DeleteBookmarks(ctx context.Context, ids ...int) (err erro
 					return errors.WithStack(err)
 				}
 
+				_, err = stmtDelArchiveSnapshot.ExecContext(ctx, id)
+				if err != nil {
+					return errors.WithStack(err)
+				}
+
+				_, err = stmtDelLinkCheck.ExecContext(ctx, id)
+				if err != nil {
+					return errors.WithStack(err)
+				}
+
+				_, err = stmtDelArchiveText.ExecContext(ctx, id)
+				if err != nil {
+					return errors.WithStack(err)
+				}
+
+				_, err = stmtDelHighlight.ExecContext(ctx, id)
+				if err != nil {
+					return errors.WithStack(err)
+				}
+
 				_, err = stmtDelBookmark.ExecContext(ctx, id)
 				if err != nil {
 					return errors.WithStack(err)
@@ -577,12 +750,12 @@ func (db *PGDatabase) DeleteBookmarks(ctx context.Context, ids ...int) (err erro
 
 // GetBookmark fetches bookmark based on its ID or URL.
 // Returns the bookmark and boolean whether it's exist or not.
-func (db *PGDatabase) GetBookmark(ctx context.Context, id int, url string) (model.BookmarkDTO, bool, error) {
+func (db *PGDatabase) GetBookmark(ctx context.Context, id int, url string, accountID model.DBID) (model.BookmarkDTO, bool, error) {
 	// Create the main query builder for bookmark data
 	sb := sqlbuilder.PostgreSQL.NewSelectBuilder()
 	sb.Select(
-		"id", "url", "title", "excerpt", "author", `"public"`, "modified_at",
-		"content", "html", "created_at", "has_content")
+		"id", "account_id", "url", "title", "excerpt", "author", `"public"`, "modified_at",
+		"content", "html", "created_at", "has_content", "collection_id")
 	sb.From("bookmark")
 
 	// Add conditions
@@ -594,6 +767,10 @@ func (db *PGDatabase) GetBookmark(ctx context.Context, id int, url string) (mode
 		return model.BookmarkDTO{}, false, fmt.Errorf("id or url is required")
 	}
 
+	if accountID > 0 {
+		sb.Where(sb.Equal("account_id", accountID))
+	}
+
 	// Build the query
 	query, args := sb.Build()
 
@@ -604,7 +781,7 @@ func (db *PGDatabase) GetBookmark(ctx context.Context, id int, url string) (mode
 	err := db.ReaderDB().GetContext(ctx, &book, query, args...)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return book, false, nil
+			return model.BookmarkDTO{}, false, nil
 		}
 		return book, false, fmt.Errorf("failed to get bookmark: %w", err)
 	}
@@ -786,6 +963,46 @@ func (db *PGDatabase) DeleteAccount(ctx context.Context, id model.DBID) error {
 			return ErrNotFound
 		}
 
+		if _, err := tx.ExecContext(ctx, `DELETE FROM api_token WHERE account_id = $1`, id); err != nil {
+			return fmt.Errorf("error deleting account tokens: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM session WHERE account_id = $1`, id); err != nil {
+			return fmt.Errorf("error deleting account sessions: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM account_totp WHERE account_id = $1`, id); err != nil {
+			return fmt.Errorf("error deleting account totp: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM account_identity WHERE account_id = $1`, id); err != nil {
+			return fmt.Errorf("error deleting account identities: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM feed_token WHERE account_id = $1`, id); err != nil {
+			return fmt.Errorf("error deleting feed token: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM subscription_item WHERE subscription_id IN (SELECT id FROM subscription WHERE account_id = $1)`, id); err != nil {
+			return fmt.Errorf("error deleting subscription items: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM subscription WHERE account_id = $1`, id); err != nil {
+			return fmt.Errorf("error deleting subscriptions: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM webhook_delivery WHERE webhook_id IN (SELECT id FROM webhook WHERE account_id = $1)`, id); err != nil {
+			return fmt.Errorf("error deleting webhook deliveries: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM webhook WHERE account_id = $1`, id); err != nil {
+			return fmt.Errorf("error deleting webhooks: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM collection WHERE account_id = $1`, id); err != nil {
+			return fmt.Errorf("error deleting collections: %w", err)
+		}
+
 		return nil
 	}); err != nil {
 		return fmt.Errorf("error running transaction: %w", err)
@@ -803,11 +1020,11 @@ func (db *PGDatabase) CreateTags(ctx context.Context, tags ...model.Tag) ([]mode
 	// Create insert builder with RETURNING clause
 	sb := sqlbuilder.NewInsertBuilder()
 	sb.InsertInto("tag")
-	sb.Cols("name")
+	sb.Cols("name", "parent_id")
 
 	// Add values for each tag
 	for _, tag := range tags {
-		sb.Values(tag.Name)
+		sb.Values(tag.Name, tag.ParentID)
 	}
 
 	// Build query with RETURNING id
@@ -892,7 +1109,7 @@ func (db *PGDatabase) RenameTag(ctx context.Context, id int, newName string) err
 // GetTag fetch a tag by its ID.
 func (db *PGDatabase) GetTag(ctx context.Context, id int) (model.TagDTO, bool, error) {
 	sb := sqlbuilder.NewSelectBuilder()
-	sb.Select("t.id", "t.name", "COUNT(bt.tag_id) bookmark_count")
+	sb.Select("t.id", "t.name", "t.parent_id", "COUNT(bt.tag_id) bookmark_count")
 	sb.From("tag t")
 	sb.JoinWithOption(sqlbuilder.LeftJoin, "bookmark_tag bt", "bt.tag_id = t.id")
 	sb.Where(sb.Equal("t.id", id))
@@ -918,7 +1135,7 @@ func (db *PGDatabase) GetTag(ctx context.Context, id int) (model.TagDTO, bool, e
 func (db *PGDatabase) UpdateTag(ctx context.Context, tag model.Tag) error {
 	sb := sqlbuilder.NewUpdateBuilder()
 	sb.Update("tag")
-	sb.Set(sb.Assign("name", tag.Name))
+	sb.Set(sb.Assign("name", tag.Name), sb.Assign("parent_id", tag.ParentID))
 	sb.Where(sb.Equal("id", tag.ID))
 
 	query, args := sb.Build()
@@ -956,6 +1173,22 @@ func (db *PGDatabase) DeleteTag(ctx context.Context, id int) error {
 	deleteAssocQuery, deleteAssocArgs := deleteAssocSb.Build()
 	deleteAssocQuery = db.WriterDB().Rebind(deleteAssocQuery)
 
+	// Remove its aliases and move its children to the top level
+	deleteAliasesSb := sqlbuilder.NewDeleteBuilder()
+	deleteAliasesSb.DeleteFrom("tag_alias")
+	deleteAliasesSb.Where(deleteAliasesSb.Equal("tag_id", id))
+
+	deleteAliasesQuery, deleteAliasesArgs := deleteAliasesSb.Build()
+	deleteAliasesQuery = db.WriterDB().Rebind(deleteAliasesQuery)
+
+	orphanChildrenSb := sqlbuilder.NewUpdateBuilder()
+	orphanChildrenSb.Update("tag")
+	orphanChildrenSb.Set(orphanChildrenSb.Assign("parent_id", nil))
+	orphanChildrenSb.Where(orphanChildrenSb.Equal("parent_id", id))
+
+	orphanChildrenQuery, orphanChildrenArgs := orphanChildrenSb.Build()
+	orphanChildrenQuery = db.WriterDB().Rebind(orphanChildrenQuery)
+
 	// Then, delete the tag itself
 	deleteTagSb := sqlbuilder.NewDeleteBuilder()
 	deleteTagSb.DeleteFrom("tag")
@@ -971,6 +1204,14 @@ func (db *PGDatabase) DeleteTag(ctx context.Context, id int) error {
 			return fmt.Errorf("failed to delete tag associations: %w", err)
 		}
 
+		if _, err := tx.ExecContext(ctx, deleteAliasesQuery, deleteAliasesArgs...); err != nil {
+			return fmt.Errorf("failed to delete tag aliases: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, orphanChildrenQuery, orphanChildrenArgs...); err != nil {
+			return fmt.Errorf("failed to move child tags: %w", err)
+		}
+
 		// Delete the tag
 		_, err = tx.ExecContext(ctx, deleteTagQuery, deleteTagArgs...)
 		if err != nil {
@@ -1130,3 +1371,377 @@ func (db *PGDatabase) BulkUpdateBookmarkTags(ctx context.Context, bookmarkIDs []
 		return nil
 	})
 }
+
+// CreateJob adds a new job to the queue.
+func (db *PGDatabase) CreateJob(ctx context.Context, job model.Job) (*model.Job, error) {
+	now := time.Now().UTC().Format(model.DatabaseDateFormat)
+	if job.Status == "" {
+		job.Status = model.JobStatusPending
+	}
+	if job.RunAt == "" {
+		job.RunAt = now
+	}
+	job.CreatedAt = now
+	job.UpdatedAt = now
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.PostgreSQL.NewInsertBuilder()
+		ib.InsertInto("job")
+		ib.Cols("type", "payload", "status", "attempts", "max_attempts", "last_error", "run_at", "created_at", "updated_at")
+		ib.Values(job.Type, job.Payload, job.Status, job.Attempts, job.MaxAttempts, job.LastError, job.RunAt, job.CreatedAt, job.UpdatedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query) + " RETURNING id"
+
+		if err := tx.QueryRowContext(ctx, query, args...).Scan(&job.ID); err != nil {
+			return fmt.Errorf("failed to insert job: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// CreateArchiveSnapshot records a new archive snapshot of a bookmark.
+func (db *PGDatabase) CreateArchiveSnapshot(ctx context.Context, snapshot model.ArchiveSnapshot) (*model.ArchiveSnapshot, error) {
+	if snapshot.CreatedAt == "" {
+		snapshot.CreatedAt = time.Now().UTC().Format(model.DatabaseDateFormat)
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.PostgreSQL.NewInsertBuilder()
+		ib.InsertInto("archive_snapshot")
+		ib.Cols("bookmark_id", "path", "created_at")
+		ib.Values(snapshot.BookmarkID, snapshot.Path, snapshot.CreatedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query) + " RETURNING id"
+
+		if err := tx.QueryRowContext(ctx, query, args...).Scan(&snapshot.ID); err != nil {
+			return fmt.Errorf("failed to insert archive snapshot: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+// CreateLinkCheck stores the result of checking a bookmark URL.
+func (db *PGDatabase) CreateLinkCheck(ctx context.Context, check model.LinkCheck) (*model.LinkCheck, error) {
+	if check.CheckedAt == "" {
+		check.CheckedAt = time.Now().UTC().Format(model.DatabaseDateFormat)
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.PostgreSQL.NewInsertBuilder()
+		ib.InsertInto("link_check")
+		ib.Cols("bookmark_id", "url", "status_code", "final_url", "latency_ms", "error", "checked_at")
+		ib.Values(check.BookmarkID, check.URL, check.StatusCode, check.FinalURL, check.LatencyMs, check.Error, check.CheckedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query) + " RETURNING id"
+
+		if err := tx.QueryRowContext(ctx, query, args...).Scan(&check.ID); err != nil {
+			return fmt.Errorf("failed to insert link check: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &check, nil
+}
+
+// CreateAPIToken stores a new personal API token.
+func (db *PGDatabase) CreateAPIToken(ctx context.Context, token model.APIToken) (*model.APIToken, error) {
+	if token.CreatedAt == "" {
+		token.CreatedAt = time.Now().UTC().Format(model.DatabaseDateFormat)
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.PostgreSQL.NewInsertBuilder()
+		ib.InsertInto("api_token")
+		ib.Cols("account_id", "name", "token_hash", "scopes", "expires_at", "created_at")
+		ib.Values(token.AccountID, token.Name, token.TokenHash, token.Scopes, token.ExpiresAt, token.CreatedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query) + " RETURNING id"
+
+		if err := tx.QueryRowContext(ctx, query, args...).Scan(&token.ID); err != nil {
+			return fmt.Errorf("failed to insert api token: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// CreateAccountIdentity links an account to a user of an OpenID Connect provider.
+func (db *PGDatabase) CreateAccountIdentity(ctx context.Context, identity model.AccountIdentity) (*model.AccountIdentity, error) {
+	if identity.CreatedAt == "" {
+		identity.CreatedAt = time.Now().UTC().Format(model.DatabaseDateFormat)
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.PostgreSQL.NewInsertBuilder()
+		ib.InsertInto("account_identity")
+		ib.Cols("account_id", "issuer", "subject", "created_at")
+		ib.Values(identity.AccountID, identity.Issuer, identity.Subject, identity.CreatedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query) + " RETURNING id"
+
+		if err := tx.QueryRowContext(ctx, query, args...).Scan(&identity.ID); err != nil {
+			return fmt.Errorf("failed to insert account identity: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &identity, nil
+}
+
+// CreateSubscription stores a new feed subscription.
+func (db *PGDatabase) CreateSubscription(ctx context.Context, subscription model.Subscription) (*model.Subscription, error) {
+	if subscription.CreatedAt == "" {
+		subscription.CreatedAt = time.Now().UTC().Format(model.DatabaseDateFormat)
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.PostgreSQL.NewInsertBuilder()
+		ib.InsertInto("subscription")
+		ib.Cols("account_id", "url", "title", "tags", "create_archive", "create_ebook", "created_at")
+		ib.Values(subscription.AccountID, subscription.URL, subscription.Title, subscription.Tags,
+			subscription.CreateArchive, subscription.CreateEbook, subscription.CreatedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query) + " RETURNING id"
+
+		if err := tx.QueryRowContext(ctx, query, args...).Scan(&subscription.ID); err != nil {
+			return fmt.Errorf("failed to insert subscription: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+// CreateWebhook stores a new webhook.
+func (db *PGDatabase) CreateWebhook(ctx context.Context, webhook model.Webhook) (*model.Webhook, error) {
+	if webhook.CreatedAt == "" {
+		webhook.CreatedAt = time.Now().UTC().Format(model.DatabaseDateFormat)
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.PostgreSQL.NewInsertBuilder()
+		ib.InsertInto("webhook")
+		ib.Cols("account_id", "url", "events", "secret", "created_at")
+		ib.Values(webhook.AccountID, webhook.URL, webhook.Events, webhook.Secret, webhook.CreatedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query) + " RETURNING id"
+
+		if err := tx.QueryRowContext(ctx, query, args...).Scan(&webhook.ID); err != nil {
+			return fmt.Errorf("failed to insert webhook: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+// CreateWebhookDelivery stores a new webhook delivery.
+func (db *PGDatabase) CreateWebhookDelivery(ctx context.Context, delivery model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	if delivery.CreatedAt == "" {
+		delivery.CreatedAt = time.Now().UTC().Format(model.DatabaseDateFormat)
+	}
+	if delivery.Status == "" {
+		delivery.Status = model.WebhookDeliveryStatusPending
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.PostgreSQL.NewInsertBuilder()
+		ib.InsertInto("webhook_delivery")
+		ib.Cols("webhook_id", "event", "payload", "status", "response_body", "last_error", "created_at")
+		ib.Values(delivery.WebhookID, delivery.Event, delivery.Payload, delivery.Status, delivery.ResponseBody,
+			delivery.LastError, delivery.CreatedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query) + " RETURNING id"
+
+		if err := tx.QueryRowContext(ctx, query, args...).Scan(&delivery.ID); err != nil {
+			return fmt.Errorf("failed to insert webhook delivery: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+// CreateCollection stores a new collection.
+func (db *PGDatabase) CreateCollection(ctx context.Context, collection model.Collection) (*model.Collection, error) {
+	now := time.Now().UTC().Format(model.DatabaseDateFormat)
+	if collection.CreatedAt == "" {
+		collection.CreatedAt = now
+	}
+	if collection.ModifiedAt == "" {
+		collection.ModifiedAt = collection.CreatedAt
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.PostgreSQL.NewInsertBuilder()
+		ib.InsertInto("collection")
+		ib.Cols("account_id", "parent_id", "name", "position", "created_at", "modified_at")
+		ib.Values(collection.AccountID, collection.ParentID, collection.Name, collection.Position, collection.CreatedAt, collection.ModifiedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query) + " RETURNING id"
+
+		if err := tx.QueryRowContext(ctx, query, args...).Scan(&collection.ID); err != nil {
+			return fmt.Errorf("failed to insert collection: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &collection, nil
+}
+
+// CreateTagAlias saves a new name resolving to an existing tag.
+func (db *PGDatabase) CreateTagAlias(ctx context.Context, alias model.TagAlias) (model.TagAlias, error) {
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.PostgreSQL.NewInsertBuilder()
+		ib.InsertInto("tag_alias")
+		ib.Cols("name", "tag_id")
+		ib.Values(alias.Name, alias.TagID)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query) + " RETURNING id"
+
+		if err := tx.QueryRowContext(ctx, query, args...).Scan(&alias.ID); err != nil {
+			return fmt.Errorf("failed to insert tag alias: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return model.TagAlias{}, err
+	}
+
+	return alias, nil
+}
+
+// CreateBookmarkRule stores a new bookmark rule.
+func (db *PGDatabase) CreateBookmarkRule(ctx context.Context, rule model.BookmarkRule) (*model.BookmarkRule, error) {
+	now := time.Now().UTC().Format(model.DatabaseDateFormat)
+	if rule.CreatedAt == "" {
+		rule.CreatedAt = now
+	}
+	if rule.ModifiedAt == "" {
+		rule.ModifiedAt = rule.CreatedAt
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.PostgreSQL.NewInsertBuilder()
+		ib.InsertInto("bookmark_rule")
+		ib.Cols("account_id", "name", "enabled", "conditions", "actions", "created_at", "modified_at")
+		ib.Values(rule.AccountID, rule.Name, rule.Enabled, rule.Conditions, rule.Actions, rule.CreatedAt, rule.ModifiedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query) + " RETURNING id"
+
+		if err := tx.QueryRowContext(ctx, query, args...).Scan(&rule.ID); err != nil {
+			return fmt.Errorf("failed to insert bookmark rule: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+// CreateSavedSearch stores a new saved search.
+func (db *PGDatabase) CreateSavedSearch(ctx context.Context, search model.SavedSearch) (*model.SavedSearch, error) {
+	now := time.Now().UTC().Format(model.DatabaseDateFormat)
+	if search.CreatedAt == "" {
+		search.CreatedAt = now
+	}
+	if search.ModifiedAt == "" {
+		search.ModifiedAt = search.CreatedAt
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.PostgreSQL.NewInsertBuilder()
+		ib.InsertInto("saved_search")
+		ib.Cols("account_id", "name", "filter", "created_at", "modified_at")
+		ib.Values(search.AccountID, search.Name, search.Filter, search.CreatedAt, search.ModifiedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query) + " RETURNING id"
+
+		if err := tx.QueryRowContext(ctx, query, args...).Scan(&search.ID); err != nil {
+			return fmt.Errorf("failed to insert saved search: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &search, nil
+}
+
+// CreateHighlight stores a new highlight.
+func (db *PGDatabase) CreateHighlight(ctx context.Context, highlight model.Highlight) (*model.Highlight, error) {
+	now := time.Now().UTC().Format(model.DatabaseDateFormat)
+	if highlight.CreatedAt == "" {
+		highlight.CreatedAt = now
+	}
+	if highlight.ModifiedAt == "" {
+		highlight.ModifiedAt = highlight.CreatedAt
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.PostgreSQL.NewInsertBuilder()
+		ib.InsertInto("bookmark_highlight")
+		ib.Cols("bookmark_id", "account_id", "selector", "note", "created_at", "modified_at")
+		ib.Values(highlight.BookmarkID, highlight.AccountID, highlight.Selector, highlight.Note, highlight.CreatedAt, highlight.ModifiedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query) + " RETURNING id"
+
+		if err := tx.QueryRowContext(ctx, query, args...).Scan(&highlight.ID); err != nil {
+			return fmt.Errorf("failed to insert highlight: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &highlight, nil
+}
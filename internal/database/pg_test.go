@@ -7,9 +7,11 @@ import (
 	"context"
 	"log"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/go-shiori/shiori/internal/model"
+	"github.com/stretchr/testify/require"
 )
 
 func init() {
@@ -39,4 +41,47 @@ func postgresqlTestDatabaseFactory(_ *testing.T, ctx context.Context) (model.DB,
 
 func TestPostgresDatabase(t *testing.T) {
 	testDatabase(t, postgresqlTestDatabaseFactory)
+	testPostgresSearchText(t)
+}
+
+// testPostgresSearchText checks that the text is searched anywhere in the bookmarks, not
+// only by whole words in the text search vector, which only holds the beginning of long
+// contents.
+func testPostgresSearchText(t *testing.T) {
+	ctx := context.TODO()
+
+	db, err := postgresqlTestDatabaseFactory(t, ctx)
+	require.NoError(t, err)
+
+	_, err = db.SaveBookmarks(ctx, true,
+		model.BookmarkDTO{
+			URL:     "https://example.com/gophers",
+			Title:   "Gophers",
+			Excerpt: "All about gophers",
+		},
+		model.BookmarkDTO{
+			URL:     "https://example.com/long",
+			Title:   "Long page",
+			Content: strings.Repeat("filler ", 100000) + "needle",
+		},
+	)
+	require.NoError(t, err)
+
+	titles := func(keyword string) []string {
+		bookmarks, err := db.GetBookmarks(ctx, model.DBGetBookmarksOptions{
+			Keyword:     keyword,
+			OrderMethod: model.ByRelevance,
+		})
+		require.NoError(t, err)
+
+		titles := []string{}
+		for _, book := range bookmarks {
+			titles = append(titles, book.Title)
+		}
+		return titles
+	}
+
+	require.Equal(t, []string{"Gophers"}, titles("goph"), "parts of words are matched")
+	require.Equal(t, []string{"Gophers"}, titles("about goph"))
+	require.Equal(t, []string{"Long page"}, titles("needle"), "content past the indexed length is matched")
 }
@@ -64,6 +64,23 @@ var sqliteMigrations = []migration{
 	newFileMigration("0.3.0", "0.4.0", "sqlite/0002_denormalize_content"),
 	newFileMigration("0.4.0", "0.5.0", "sqlite/0003_uniq_id"),
 	newFileMigration("0.5.0", "0.6.0", "sqlite/0004_created_time"),
+	newFileMigration("0.6.0", "0.7.0", "sqlite/0005_bookmark_account"),
+	newFileMigration("0.7.0", "0.8.0", "sqlite/0006_job"),
+	newFileMigration("0.8.0", "0.9.0", "sqlite/0007_archive_snapshot"),
+	newFileMigration("0.9.0", "0.10.0", "sqlite/0008_link_check"),
+	newFileMigration("0.10.0", "0.11.0", "sqlite/0009_api_token"),
+	newFileMigration("0.11.0", "0.12.0", "sqlite/0010_session"),
+	newFileMigration("0.12.0", "0.13.0", "sqlite/0011_totp"),
+	newFileMigration("0.13.0", "0.14.0", "sqlite/0012_feed_token"),
+	newFileMigration("0.14.0", "0.15.0", "sqlite/0013_subscription"),
+	newFileMigration("0.15.0", "0.16.0", "sqlite/0014_webhook"),
+	newFileMigration("0.16.0", "0.17.0", "sqlite/0015_collection"),
+	newFileMigration("0.17.0", "0.18.0", "sqlite/0016_tag_alias"),
+	newFileMigration("0.18.0", "0.19.0", "sqlite/0017_bookmark_rule"),
+	newFileMigration("0.19.0", "0.20.0", "sqlite/0018_saved_search"),
+	newFileMigration("0.20.0", "0.21.0", "sqlite/0019_bookmark_archive_text"),
+	newFileMigration("0.21.0", "0.22.0", "sqlite/0020_bookmark_highlight"),
+	newFileMigration("0.22.0", "0.23.0", "sqlite/0021_account_identity"),
 }
 
 // SQLiteDatabase is implementation of Database interface
@@ -220,8 +237,8 @@ func (db *SQLiteDatabase) SaveBookmarks(ctx context.Context, create bool, bookma
 		// Prepare statement
 
 		stmtInsertBook, err := tx.PreparexContext(ctx, `INSERT INTO bookmark
-			(url, title, excerpt, author, public, modified_at, has_content, created_at)
-			VALUES(?, ?, ?, ?, ?, ?, ?, ?) RETURNING id`)
+			(account_id, url, title, excerpt, author, public, modified_at, has_content, created_at, collection_id)
+			VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id`)
 		if err != nil {
 			return fmt.Errorf("failed to prepare insert book statement: %w", err)
 		}
@@ -253,6 +270,12 @@ func (db *SQLiteDatabase) SaveBookmarks(ctx context.Context, create bool, bookma
 			return fmt.Errorf("failed to prepare get tag statement: %w", err)
 		}
 
+		stmtGetTagAlias, err := tx.PreparexContext(ctx, `SELECT t.id, t.name FROM tag_alias a
+			JOIN tag t ON t.id = a.tag_id WHERE a.name = ?`)
+		if err != nil {
+			return fmt.Errorf("failed to prepare get tag alias statement: %w", err)
+		}
+
 		stmtInsertTag, err := tx.PreparexContext(ctx, `INSERT INTO tag (name) VALUES (?)`)
 		if err != nil {
 			return fmt.Errorf("failed to prepare insert tag statement: %w", err)
@@ -295,9 +318,12 @@ func (db *SQLiteDatabase) SaveBookmarks(ctx context.Context, create bool, bookma
 			// Create or update bookmark
 			var err error
 			if create {
-				book.CreatedAt = modifiedTime
+				if book.CreatedAt == "" {
+					book.CreatedAt = modifiedTime
+				}
 				err = stmtInsertBook.QueryRowContext(ctx,
-					book.URL, book.Title, book.Excerpt, book.Author, book.Public, book.ModifiedAt, hasContent, book.CreatedAt).Scan(&book.ID)
+					book.AccountID, book.URL, book.Title, book.Excerpt, book.Author, book.Public, book.ModifiedAt, hasContent, book.CreatedAt,
+					book.CollectionID).Scan(&book.ID)
 			} else {
 				_, err = stmtUpdateBook.ExecContext(ctx,
 					book.URL, book.Title, book.Excerpt, book.Author, book.Public, book.ModifiedAt, hasContent, book.ID)
@@ -328,7 +354,9 @@ func (db *SQLiteDatabase) SaveBookmarks(ctx context.Context, create bool, bookma
 			// Save book tags
 			newTags := []model.TagDTO{}
 			for _, tag := range book.Tags {
-				t := tag.ToDTO()
+				t := tag
+				// Only the tags inserted below are reported as created
+				t.Created = false
 				// If it's deleted tag, delete and continue
 				if t.Deleted {
 					_, err = stmtDeleteBookTag.ExecContext(ctx, book.ID, tag.ID)
@@ -348,6 +376,18 @@ func (db *SQLiteDatabase) SaveBookmarks(ctx context.Context, create bool, bookma
 						return fmt.Errorf("failed to get tag ID: %w", err)
 					}
 
+					// The name may be an alias, the bookmark gets the tag it stands for
+					if tag.ID == 0 {
+						var aliased model.Tag
+						if err := stmtGetTagAlias.GetContext(ctx, &aliased, tagName); err != nil && err != sql.ErrNoRows {
+							return fmt.Errorf("failed to get tag alias: %w", err)
+						}
+						tag.ID, t.ID = aliased.ID, aliased.ID
+						if aliased.ID != 0 {
+							t.Name = aliased.Name
+						}
+					}
+
 					// If tag doesn't exist in database, save it
 					if tag.ID == 0 {
 						res, err := stmtInsertTag.ExecContext(ctx, tagName)
@@ -362,6 +402,7 @@ func (db *SQLiteDatabase) SaveBookmarks(ctx context.Context, create bool, bookma
 
 						tag.ID = int(tagID64)
 						t.ID = int(tagID64)
+						t.Created = true
 					}
 
 					if _, err := stmtInsertBookTag.ExecContext(ctx, tag.ID, book.ID); err != nil {
@@ -384,11 +425,57 @@ func (db *SQLiteDatabase) SaveBookmarks(ctx context.Context, create bool, bookma
 	return result, nil
 }
 
+// sqliteSearch compiles search queries, the title and content of the bookmarks are searched in
+// their full text index.
+var sqliteSearch = searchDialect{
+	ID:         `b.id`,
+	URL:        `b.url`,
+	Public:     `b.public`,
+	CreatedAt:  `b.created_at`,
+	HasContent: `b.has_content = 1`,
+	Text: func(term model.SearchTerm, bind func(value interface{}) string) string {
+		ftsKeyword := sqliteFTSPhrase(term.Value)
+		return `b.url LIKE '%' || ` + bind(term.Value) + ` || '%' OR b.excerpt LIKE '%' || ` + bind(term.Value) + ` || '%' OR b.id IN (
+			SELECT docid id
+			FROM bookmark_content
+			WHERE title MATCH ` + bind(ftsKeyword) + ` OR content MATCH ` + bind(ftsKeyword) + `)`
+	},
+	ArchiveText: func(term model.SearchTerm, bind func(value interface{}) string) string {
+		return `b.id IN (
+			SELECT rowid
+			FROM bookmark_archive_text_fts
+			WHERE bookmark_archive_text_fts MATCH ` + bind(sqliteFTSPhrase(term.Value)) + `)`
+	},
+	Rank: func(terms []model.SearchTerm, bind func(value interface{}) string) string {
+		// bm25 is lower for better matches, the title weighs more than the content and the
+		// HTML and ID aren't counted. Bookmarks only matching outside of the index come last.
+		phrases := make([]string, len(terms))
+		for i, term := range terms {
+			phrases[i] = sqliteFTSPhrase(term.Value)
+		}
+		return `COALESCE((SELECT bm25(bookmark_content, 10.0, 1.0, 0.0, 0.0)
+			FROM bookmark_content
+			WHERE bookmark_content MATCH ` + bind(strings.Join(phrases, " OR ")) + ` AND docid = b.id), 0)`
+	},
+}
+
+// sqliteFTSPhrase quotes the text as a phrase of the full text index.
+func sqliteFTSPhrase(text string) string {
+	// Replace dash with spaces since FTS5 uses `-name` as column identifier and double quote
+	// since FTS5 uses double quote as string identifier
+	// Reference: https://sqlite.org/fts5.html#fts5_strings
+	phrase := strings.ReplaceAll(text, "-", " ")
+
+	// Properly set double quotes for string literals in sqlite's fts
+	return `"` + strings.ReplaceAll(phrase, `"`, `""`) + `"`
+}
+
 // GetBookmarks fetch list of bookmarks based on submitted options.
 func (db *SQLiteDatabase) GetBookmarks(ctx context.Context, opts model.DBGetBookmarksOptions) ([]model.BookmarkDTO, error) {
 	// Create initial query
 	query := `SELECT
 		b.id,
+		b.account_id,
 		b.url,
 		b.title,
 		b.excerpt,
@@ -396,37 +483,51 @@ func (db *SQLiteDatabase) GetBookmarks(ctx context.Context, opts model.DBGetBook
 		b.public,
 		b.created_at,
 		b.modified_at,
-		b.has_content
+		b.has_content,
+		b.collection_id
 		FROM bookmark b
 		WHERE 1`
 
 	// Add where clause
 	args := []interface{}{}
 
-	// Add where clause for IDs
-	if len(opts.IDs) > 0 {
-		query += ` AND b.id IN (?)`
-		args = append(args, opts.IDs)
+	// Add where clause for owner account
+	if opts.AccountID > 0 {
+		query += ` AND b.account_id = ?`
+		args = append(args, opts.AccountID)
 	}
 
-	// Add where clause for search keyword
-	if opts.Keyword != "" {
-		query += ` AND (b.url LIKE '%' || ? || '%' OR b.excerpt LIKE '%' || ? || '%' OR b.id IN (
-			SELECT docid id
-			FROM bookmark_content
-			WHERE title MATCH ? OR content MATCH ?))`
+	// Add where clause for public bookmarks
+	if opts.PublicOnly {
+		query += ` AND b.public = 1`
+	}
 
-		args = append(args, opts.Keyword, opts.Keyword)
+	// Add where clause for link status
+	if condition := linkStatusCondition(`b.id`, opts.LinkStatus); condition != "" {
+		query += ` AND ` + condition
+	}
+
+	// Add where clause for collection
+	if condition := collectionCondition(`b.collection_id`, opts.CollectionID); condition != "" {
+		query += ` AND ` + condition
+	}
 
-		// Replace dash with spaces since FTS5 uses `-name` as column identifier and double quote
-		// since FTS5 uses double quote as string identifier
-		// Reference: https://sqlite.org/fts5.html#fts5_strings
-		ftsKeyword := strings.ReplaceAll(opts.Keyword, "-", " ")
+	// Add where clause for creation time
+	if condition := addedAfterCondition(`b.created_at`, opts.AddedAfter, bindArg(&args)); condition != "" {
+		query += ` AND ` + condition
+	}
 
-		// Properly set double quotes for string literals in sqlite's fts
-		ftsKeyword = strings.ReplaceAll(ftsKeyword, "\"", "\"\"")
+	// Add where clause for IDs
+	if len(opts.IDs) > 0 {
+		query += ` AND b.id IN (?)`
+		args = append(args, opts.IDs)
+	}
 
-		args = append(args, "\""+ftsKeyword+"\"", "\""+ftsKeyword+"\"")
+	// Add where clause for search query
+	if condition, err := db.searchCondition(ctx, opts.Keyword, sqliteSearch, bindArg(&args)); err != nil {
+		return nil, err
+	} else if condition != "" {
+		query += ` AND ` + condition
 	}
 
 	// Add where clause for tags.
@@ -452,27 +553,11 @@ func (db *SQLiteDatabase) GetBookmarks(ctx context.Context, opts model.DBGetBook
 		query += ` AND b.id IN (SELECT DISTINCT bookmark_id FROM bookmark_tag)`
 	}
 
-	// Now we only need to find the normal tags
-	if len(opts.Tags) > 0 {
-		query += ` AND b.id IN (
-			SELECT bt.bookmark_id
-			FROM bookmark_tag bt
-			LEFT JOIN tag t ON bt.tag_id = t.id
-			WHERE t.name IN(?)
-			GROUP BY bt.bookmark_id
-			HAVING COUNT(bt.bookmark_id) = ?)`
-
-		args = append(args, opts.Tags, len(opts.Tags))
-	}
-
-	if len(opts.ExcludedTags) > 0 {
-		query += ` AND b.id NOT IN (
-			SELECT DISTINCT bt.bookmark_id
-			FROM bookmark_tag bt
-			LEFT JOIN tag t ON bt.tag_id = t.id
-			WHERE t.name IN(?))`
-
-		args = append(args, opts.ExcludedTags)
+	// Now we only need to find the normal tags, with their aliases and children
+	if condition, err := db.tagsCondition(ctx, "b.id", opts.Tags, opts.ExcludedTags); err != nil {
+		return nil, err
+	} else if condition != "" {
+		query += " AND " + condition
 	}
 
 	// Add order clause
@@ -481,6 +566,8 @@ func (db *SQLiteDatabase) GetBookmarks(ctx context.Context, opts model.DBGetBook
 		query += ` ORDER BY b.id DESC`
 	case model.ByLastModified:
 		query += ` ORDER BY b.modified_at DESC`
+	case model.ByRelevance:
+		query += ` ORDER BY ` + searchOrder(opts.Keyword, sqliteSearch, bindArg(&args), `b.id DESC`)
 	default:
 		query += ` ORDER BY b.id`
 	}
@@ -513,9 +600,10 @@ func (db *SQLiteDatabase) GetBookmarks(ctx context.Context, opts model.DBGetBook
 		return bookmarks, nil
 	}
 
-	// If content needed, fetch it separately
+	// If content or snippets needed, fetch the content separately
 	// It's faster than join with virtual table
-	if opts.WithContent {
+	snippetQuery, withSnippets := searchSnippetQuery(opts.Keyword)
+	if opts.WithContent || withSnippets {
 		contents := make([]bookmarkContent, 0, len(bookmarks))
 		contentMap := make(map[int]bookmarkContent, len(bookmarks))
 
@@ -541,6 +629,18 @@ func (db *SQLiteDatabase) GetBookmarks(ctx context.Context, opts model.DBGetBook
 				log.Printf("not found content for bookmark %d, but it should be; check DB consistency", book.ID)
 			}
 		}
+
+		if withSnippets {
+			if err := db.setSearchSnippets(ctx, bookmarks, snippetQuery); err != nil {
+				return nil, err
+			}
+		}
+		if !opts.WithContent {
+			for i := range bookmarks {
+				bookmarks[i].Content = ""
+				bookmarks[i].HTML = ""
+			}
+		}
 	}
 
 	// Fetch tags for each bookmark
@@ -585,30 +685,43 @@ func (db *SQLiteDatabase) GetBookmarksCount(ctx context.Context, opts model.DBGe
 	// Add where clause
 	args := []interface{}{}
 
-	// Add where clause for IDs
-	if len(opts.IDs) > 0 {
-		query += ` AND b.id IN (?)`
-		args = append(args, opts.IDs)
+	// Add where clause for owner account
+	if opts.AccountID > 0 {
+		query += ` AND b.account_id = ?`
+		args = append(args, opts.AccountID)
 	}
 
-	// Add where clause for search keyword
-	if opts.Keyword != "" {
-		query += ` AND (b.url LIKE '%' || ? || '%' OR b.excerpt LIKE '%' || ? || '%' OR b.id IN (
-			SELECT docid id
-			FROM bookmark_content
-			WHERE title MATCH ? OR content MATCH ?))`
+	// Add where clause for public bookmarks
+	if opts.PublicOnly {
+		query += ` AND b.public = 1`
+	}
 
-		args = append(args, opts.Keyword, opts.Keyword)
+	// Add where clause for link status
+	if condition := linkStatusCondition(`b.id`, opts.LinkStatus); condition != "" {
+		query += ` AND ` + condition
+	}
 
-		// Replace dash with spaces since FTS5 uses `-name` as column identifier and double quote
-		// since FTS5 uses double quote as string identifier
-		// Reference: https://sqlite.org/fts5.html#fts5_strings
-		ftsKeyword := strings.ReplaceAll(opts.Keyword, "-", " ")
+	// Add where clause for collection
+	if condition := collectionCondition(`b.collection_id`, opts.CollectionID); condition != "" {
+		query += ` AND ` + condition
+	}
 
-		// Properly set double quotes for string literals in sqlite's fts
-		ftsKeyword = strings.ReplaceAll(ftsKeyword, "\"", "\"\"")
+	// Add where clause for creation time
+	if condition := addedAfterCondition(`b.created_at`, opts.AddedAfter, bindArg(&args)); condition != "" {
+		query += ` AND ` + condition
+	}
 
-		args = append(args, "\""+ftsKeyword+"\"", "\""+ftsKeyword+"\"")
+	// Add where clause for IDs
+	if len(opts.IDs) > 0 {
+		query += ` AND b.id IN (?)`
+		args = append(args, opts.IDs)
+	}
+
+	// Add where clause for search query
+	if condition, err := db.searchCondition(ctx, opts.Keyword, sqliteSearch, bindArg(&args)); err != nil {
+		return 0, err
+	} else if condition != "" {
+		query += ` AND ` + condition
 	}
 
 	// Add where clause for tags.
@@ -640,27 +753,11 @@ func (db *SQLiteDatabase) GetBookmarksCount(ctx context.Context, opts model.DBGe
 		query += ` AND b.id IN (SELECT DISTINCT bookmark_id FROM bookmark_tag)`
 	}
 
-	// Now we only need to find the normal tags
-	if len(opts.Tags) > 0 {
-		query += ` AND b.id IN (
-			SELECT bt.bookmark_id
-			FROM bookmark_tag bt
-			LEFT JOIN tag t ON bt.tag_id = t.id
-			WHERE t.name IN(?)
-			GROUP BY bt.bookmark_id
-			HAVING COUNT(bt.bookmark_id) = ?)`
-
-		args = append(args, opts.Tags, len(opts.Tags))
-	}
-
-	if len(opts.ExcludedTags) > 0 {
-		query += ` AND b.id NOT IN (
-			SELECT DISTINCT bt.bookmark_id
-			FROM bookmark_tag bt
-			LEFT JOIN tag t ON bt.tag_id = t.id
-			WHERE t.name IN(?))`
-
-		args = append(args, opts.ExcludedTags)
+	// Now we only need to find the normal tags, with their aliases and children
+	if condition, err := db.tagsCondition(ctx, "b.id", opts.Tags, opts.ExcludedTags); err != nil {
+		return 0, err
+	} else if condition != "" {
+		query += " AND " + condition
 	}
 
 	// Expand query, because some of the args might be an array
@@ -680,12 +777,27 @@ func (db *SQLiteDatabase) GetBookmarksCount(ctx context.Context, opts model.DBGe
 }
 
 // DeleteBookmarks removes all record with matching ids from database.
-func (db *SQLiteDatabase) DeleteBookmarks(ctx context.Context, ids ...int) error {
+func (db *SQLiteDatabase) DeleteBookmarks(ctx context.Context, accountID model.DBID, ids ...int) error {
+	if accountID > 0 {
+		var err error
+		if ids, err = db.accountBookmarkIDs(ctx, accountID, ids); err != nil {
+			return err
+		}
+
+		if len(ids) == 0 {
+			return nil
+		}
+	}
+
 	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
 		// Prepare queries
 		delBookmark := `DELETE FROM bookmark`
 		delBookmarkTag := `DELETE FROM bookmark_tag`
 		delBookmarkContent := `DELETE FROM bookmark_content`
+		delArchiveSnapshot := `DELETE FROM archive_snapshot`
+		delLinkCheck := `DELETE FROM link_check`
+		delArchiveText := `DELETE FROM bookmark_archive_text`
+		delHighlight := `DELETE FROM bookmark_highlight`
 
 		// Delete bookmark(s)
 		if len(ids) == 0 {
@@ -694,6 +806,26 @@ func (db *SQLiteDatabase) DeleteBookmarks(ctx context.Context, ids ...int) error
 				return fmt.Errorf("failed to prepare delete statement: %w", err)
 			}
 
+			_, err = tx.ExecContext(ctx, delArchiveSnapshot)
+			if err != nil {
+				return fmt.Errorf("failed to delete archive snapshots: %w", err)
+			}
+
+			_, err = tx.ExecContext(ctx, delLinkCheck)
+			if err != nil {
+				return fmt.Errorf("failed to delete link checks: %w", err)
+			}
+
+			_, err = tx.ExecContext(ctx, delArchiveText)
+			if err != nil {
+				return fmt.Errorf("failed to delete archive texts: %w", err)
+			}
+
+			_, err = tx.ExecContext(ctx, delHighlight)
+			if err != nil {
+				return fmt.Errorf("failed to delete highlights: %w", err)
+			}
+
 			_, err = tx.ExecContext(ctx, delBookmarkTag)
 			if err != nil {
 				return fmt.Errorf("failed to execute delete account statement: %w", err)
@@ -707,6 +839,10 @@ func (db *SQLiteDatabase) DeleteBookmarks(ctx context.Context, ids ...int) error
 			delBookmark += ` WHERE id = ?`
 			delBookmarkTag += ` WHERE bookmark_id = ?`
 			delBookmarkContent += ` WHERE docid = ?`
+			delArchiveSnapshot += ` WHERE bookmark_id = ?`
+			delLinkCheck += ` WHERE bookmark_id = ?`
+			delArchiveText += ` WHERE bookmark_id = ?`
+			delHighlight += ` WHERE bookmark_id = ?`
 
 			stmtDelBookmark, err := tx.Preparex(delBookmark)
 			if err != nil {
@@ -723,6 +859,26 @@ func (db *SQLiteDatabase) DeleteBookmarks(ctx context.Context, ids ...int) error
 				return fmt.Errorf("failed to delete bookmark content: %w", err)
 			}
 
+			stmtDelArchiveSnapshot, err := tx.Preparex(delArchiveSnapshot)
+			if err != nil {
+				return fmt.Errorf("failed to prepare archive snapshot delete statement: %w", err)
+			}
+
+			stmtDelLinkCheck, err := tx.Preparex(delLinkCheck)
+			if err != nil {
+				return fmt.Errorf("failed to prepare link check delete statement: %w", err)
+			}
+
+			stmtDelArchiveText, err := tx.Preparex(delArchiveText)
+			if err != nil {
+				return fmt.Errorf("failed to prepare archive text delete statement: %w", err)
+			}
+
+			stmtDelHighlight, err := tx.Preparex(delHighlight)
+			if err != nil {
+				return fmt.Errorf("failed to prepare highlight delete statement: %w", err)
+			}
+
 			for _, id := range ids {
 				_, err = stmtDelBookmarkContent.ExecContext(ctx, id)
 				if err != nil {
@@ -734,6 +890,26 @@ func (db *SQLiteDatabase) DeleteBookmarks(ctx context.Context, ids ...int) error
 					return fmt.Errorf("failed to delete bookmark tag: %w", err)
 				}
 
+				_, err = stmtDelArchiveSnapshot.ExecContext(ctx, id)
+				if err != nil {
+					return fmt.Errorf("failed to delete archive snapshots: %w", err)
+				}
+
+				_, err = stmtDelLinkCheck.ExecContext(ctx, id)
+				if err != nil {
+					return fmt.Errorf("failed to delete link checks: %w", err)
+				}
+
+				_, err = stmtDelArchiveText.ExecContext(ctx, id)
+				if err != nil {
+					return fmt.Errorf("failed to delete archive texts: %w", err)
+				}
+
+				_, err = stmtDelHighlight.ExecContext(ctx, id)
+				if err != nil {
+					return fmt.Errorf("failed to delete highlights: %w", err)
+				}
+
 				_, err = stmtDelBookmark.ExecContext(ctx, id)
 				if err != nil {
 					return fmt.Errorf("failed to delete bookmark: %w", err)
@@ -751,12 +927,12 @@ func (db *SQLiteDatabase) DeleteBookmarks(ctx context.Context, ids ...int) error
 
 // GetBookmark fetches bookmark based on its ID or URL.
 // Returns the bookmark and boolean whether it's exist or not.
-func (db *SQLiteDatabase) GetBookmark(ctx context.Context, id int, url string) (model.BookmarkDTO, bool, error) {
+func (db *SQLiteDatabase) GetBookmark(ctx context.Context, id int, url string, accountID model.DBID) (model.BookmarkDTO, bool, error) {
 	// Create the main query builder for bookmark data
 	sb := sqlbuilder.NewSelectBuilder()
 	sb.Select(
-		"b.id", "b.url", "b.title", "b.excerpt", "b.author", "b.public", "b.modified_at",
-		"bc.content", "bc.html", "b.has_content", "b.created_at")
+		"b.id", "b.account_id", "b.url", "b.title", "b.excerpt", "b.author", "b.public", "b.modified_at",
+		"bc.content", "bc.html", "b.has_content", "b.created_at", "b.collection_id")
 	sb.From("bookmark b")
 	sb.JoinWithOption(sqlbuilder.LeftJoin, "bookmark_content bc", "bc.docid = b.id")
 
@@ -769,6 +945,10 @@ func (db *SQLiteDatabase) GetBookmark(ctx context.Context, id int, url string) (
 		return model.BookmarkDTO{}, false, fmt.Errorf("id or url is required")
 	}
 
+	if accountID > 0 {
+		sb.Where(sb.Equal("b.account_id", accountID))
+	}
+
 	// Build the query
 	query, args := sb.Build()
 	query = db.ReaderDB().Rebind(query)
@@ -778,7 +958,7 @@ func (db *SQLiteDatabase) GetBookmark(ctx context.Context, id int, url string) (
 	err := db.ReaderDB().GetContext(ctx, &book, query, args...)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return book, false, nil
+			return model.BookmarkDTO{}, false, nil
 		}
 		return book, false, fmt.Errorf("failed to get bookmark: %w", err)
 	}
@@ -961,6 +1141,46 @@ func (db *SQLiteDatabase) DeleteAccount(ctx context.Context, id model.DBID) erro
 			return ErrNotFound
 		}
 
+		if _, err := tx.ExecContext(ctx, `DELETE FROM api_token WHERE account_id = ?`, id); err != nil {
+			return fmt.Errorf("error deleting account tokens: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM session WHERE account_id = ?`, id); err != nil {
+			return fmt.Errorf("error deleting account sessions: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM account_totp WHERE account_id = ?`, id); err != nil {
+			return fmt.Errorf("error deleting account totp: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM account_identity WHERE account_id = ?`, id); err != nil {
+			return fmt.Errorf("error deleting account identities: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM feed_token WHERE account_id = ?`, id); err != nil {
+			return fmt.Errorf("error deleting feed token: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM subscription_item WHERE subscription_id IN (SELECT id FROM subscription WHERE account_id = ?)`, id); err != nil {
+			return fmt.Errorf("error deleting subscription items: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM subscription WHERE account_id = ?`, id); err != nil {
+			return fmt.Errorf("error deleting subscriptions: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM webhook_delivery WHERE webhook_id IN (SELECT id FROM webhook WHERE account_id = ?)`, id); err != nil {
+			return fmt.Errorf("error deleting webhook deliveries: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM webhook WHERE account_id = ?`, id); err != nil {
+			return fmt.Errorf("error deleting webhooks: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM collection WHERE account_id = ?`, id); err != nil {
+			return fmt.Errorf("error deleting collections: %w", err)
+		}
+
 		return nil
 	}); err != nil {
 		return fmt.Errorf("error running transaction: %w", err)
@@ -981,7 +1201,7 @@ func (db *SQLiteDatabase) CreateTags(ctx context.Context, tags ...model.Tag) ([]
 
 	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
 		// For SQLite, we need to insert tags one by one to get their IDs
-		stmtInsertTag, err := tx.PrepareContext(ctx, "INSERT INTO tag (name) VALUES (?)")
+		stmtInsertTag, err := tx.PrepareContext(ctx, "INSERT INTO tag (name, parent_id) VALUES (?, ?)")
 		if err != nil {
 			return fmt.Errorf("failed to prepare tag insertion statement: %w", err)
 		}
@@ -989,7 +1209,7 @@ func (db *SQLiteDatabase) CreateTags(ctx context.Context, tags ...model.Tag) ([]
 
 		// Insert each tag and get its ID
 		for i, tag := range createdTags {
-			result, err := stmtInsertTag.ExecContext(ctx, tag.Name)
+			result, err := stmtInsertTag.ExecContext(ctx, tag.Name, tag.ParentID)
 			if err != nil {
 				return fmt.Errorf("failed to insert tag: %w", err)
 			}
@@ -1052,7 +1272,7 @@ func (db *SQLiteDatabase) RenameTag(ctx context.Context, id int, newName string)
 // GetTag fetch a tag by its ID.
 func (db *SQLiteDatabase) GetTag(ctx context.Context, id int) (model.TagDTO, bool, error) {
 	sb := sqlbuilder.SQLite.NewSelectBuilder()
-	sb.Select("t.id", "t.name", "COUNT(bt.tag_id) bookmark_count")
+	sb.Select("t.id", "t.name", "t.parent_id", "COUNT(bt.tag_id) bookmark_count")
 	sb.From("tag t")
 	sb.JoinWithOption(sqlbuilder.LeftJoin, "bookmark_tag bt", "bt.tag_id = t.id")
 	sb.Where(sb.Equal("t.id", id))
@@ -1078,7 +1298,7 @@ func (db *SQLiteDatabase) GetTag(ctx context.Context, id int) (model.TagDTO, boo
 func (db *SQLiteDatabase) UpdateTag(ctx context.Context, tag model.Tag) error {
 	sb := sqlbuilder.NewUpdateBuilder()
 	sb.Update("tag")
-	sb.Set(sb.Assign("name", tag.Name))
+	sb.Set(sb.Assign("name", tag.Name), sb.Assign("parent_id", tag.ParentID))
 	sb.Where(sb.Equal("id", tag.ID))
 
 	query, args := sb.Build()
@@ -1116,6 +1336,22 @@ func (db *SQLiteDatabase) DeleteTag(ctx context.Context, id int) error {
 	deleteAssocQuery, deleteAssocArgs := deleteAssocSb.Build()
 	deleteAssocQuery = db.WriterDB().Rebind(deleteAssocQuery)
 
+	// Remove its aliases and move its children to the top level
+	deleteAliasesSb := sqlbuilder.NewDeleteBuilder()
+	deleteAliasesSb.DeleteFrom("tag_alias")
+	deleteAliasesSb.Where(deleteAliasesSb.Equal("tag_id", id))
+
+	deleteAliasesQuery, deleteAliasesArgs := deleteAliasesSb.Build()
+	deleteAliasesQuery = db.WriterDB().Rebind(deleteAliasesQuery)
+
+	orphanChildrenSb := sqlbuilder.NewUpdateBuilder()
+	orphanChildrenSb.Update("tag")
+	orphanChildrenSb.Set(orphanChildrenSb.Assign("parent_id", nil))
+	orphanChildrenSb.Where(orphanChildrenSb.Equal("parent_id", id))
+
+	orphanChildrenQuery, orphanChildrenArgs := orphanChildrenSb.Build()
+	orphanChildrenQuery = db.WriterDB().Rebind(orphanChildrenQuery)
+
 	// Then, delete the tag itself
 	deleteTagSb := sqlbuilder.NewDeleteBuilder()
 	deleteTagSb.DeleteFrom("tag")
@@ -1131,6 +1367,14 @@ func (db *SQLiteDatabase) DeleteTag(ctx context.Context, id int) error {
 			return fmt.Errorf("failed to delete tag associations: %w", err)
 		}
 
+		if _, err := tx.ExecContext(ctx, deleteAliasesQuery, deleteAliasesArgs...); err != nil {
+			return fmt.Errorf("failed to delete tag aliases: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, orphanChildrenQuery, orphanChildrenArgs...); err != nil {
+			return fmt.Errorf("failed to move child tags: %w", err)
+		}
+
 		// Delete the tag
 		_, err = tx.ExecContext(ctx, deleteTagQuery, deleteTagArgs...)
 		if err != nil {
@@ -1298,3 +1542,468 @@ func (db *SQLiteDatabase) BulkUpdateBookmarkTags(ctx context.Context, bookmarkID
 		return nil
 	})
 }
+
+// CreateJob adds a new job to the queue.
+func (db *SQLiteDatabase) CreateJob(ctx context.Context, job model.Job) (*model.Job, error) {
+	now := time.Now().UTC().Format(model.DatabaseDateFormat)
+	if job.Status == "" {
+		job.Status = model.JobStatusPending
+	}
+	if job.RunAt == "" {
+		job.RunAt = now
+	}
+	job.CreatedAt = now
+	job.UpdatedAt = now
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.SQLite.NewInsertBuilder()
+		ib.InsertInto("job")
+		ib.Cols("type", "payload", "status", "attempts", "max_attempts", "last_error", "run_at", "created_at", "updated_at")
+		ib.Values(job.Type, job.Payload, job.Status, job.Attempts, job.MaxAttempts, job.LastError, job.RunAt, job.CreatedAt, job.UpdatedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query)
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert job: %w", err)
+		}
+
+		jobID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+
+		job.ID = model.DBID(jobID)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// CreateArchiveSnapshot records a new archive snapshot of a bookmark.
+func (db *SQLiteDatabase) CreateArchiveSnapshot(ctx context.Context, snapshot model.ArchiveSnapshot) (*model.ArchiveSnapshot, error) {
+	if snapshot.CreatedAt == "" {
+		snapshot.CreatedAt = time.Now().UTC().Format(model.DatabaseDateFormat)
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.SQLite.NewInsertBuilder()
+		ib.InsertInto("archive_snapshot")
+		ib.Cols("bookmark_id", "path", "created_at")
+		ib.Values(snapshot.BookmarkID, snapshot.Path, snapshot.CreatedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query)
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert archive snapshot: %w", err)
+		}
+
+		snapshotID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+
+		snapshot.ID = model.DBID(snapshotID)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+// CreateLinkCheck stores the result of checking a bookmark URL.
+func (db *SQLiteDatabase) CreateLinkCheck(ctx context.Context, check model.LinkCheck) (*model.LinkCheck, error) {
+	if check.CheckedAt == "" {
+		check.CheckedAt = time.Now().UTC().Format(model.DatabaseDateFormat)
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.SQLite.NewInsertBuilder()
+		ib.InsertInto("link_check")
+		ib.Cols("bookmark_id", "url", "status_code", "final_url", "latency_ms", "error", "checked_at")
+		ib.Values(check.BookmarkID, check.URL, check.StatusCode, check.FinalURL, check.LatencyMs, check.Error, check.CheckedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query)
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert link check: %w", err)
+		}
+
+		checkID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+
+		check.ID = model.DBID(checkID)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &check, nil
+}
+
+// CreateAPIToken stores a new personal API token.
+func (db *SQLiteDatabase) CreateAPIToken(ctx context.Context, token model.APIToken) (*model.APIToken, error) {
+	if token.CreatedAt == "" {
+		token.CreatedAt = time.Now().UTC().Format(model.DatabaseDateFormat)
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.SQLite.NewInsertBuilder()
+		ib.InsertInto("api_token")
+		ib.Cols("account_id", "name", "token_hash", "scopes", "expires_at", "created_at")
+		ib.Values(token.AccountID, token.Name, token.TokenHash, token.Scopes, token.ExpiresAt, token.CreatedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query)
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert api token: %w", err)
+		}
+
+		tokenID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+
+		token.ID = model.DBID(tokenID)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// CreateAccountIdentity links an account to a user of an OpenID Connect provider.
+func (db *SQLiteDatabase) CreateAccountIdentity(ctx context.Context, identity model.AccountIdentity) (*model.AccountIdentity, error) {
+	if identity.CreatedAt == "" {
+		identity.CreatedAt = time.Now().UTC().Format(model.DatabaseDateFormat)
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.SQLite.NewInsertBuilder()
+		ib.InsertInto("account_identity")
+		ib.Cols("account_id", "issuer", "subject", "created_at")
+		ib.Values(identity.AccountID, identity.Issuer, identity.Subject, identity.CreatedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query)
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert account identity: %w", err)
+		}
+
+		identityID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+
+		identity.ID = model.DBID(identityID)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &identity, nil
+}
+
+// CreateSubscription stores a new feed subscription.
+func (db *SQLiteDatabase) CreateSubscription(ctx context.Context, subscription model.Subscription) (*model.Subscription, error) {
+	if subscription.CreatedAt == "" {
+		subscription.CreatedAt = time.Now().UTC().Format(model.DatabaseDateFormat)
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.SQLite.NewInsertBuilder()
+		ib.InsertInto("subscription")
+		ib.Cols("account_id", "url", "title", "tags", "create_archive", "create_ebook", "created_at")
+		ib.Values(subscription.AccountID, subscription.URL, subscription.Title, subscription.Tags,
+			subscription.CreateArchive, subscription.CreateEbook, subscription.CreatedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query)
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert subscription: %w", err)
+		}
+
+		subscriptionID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+
+		subscription.ID = model.DBID(subscriptionID)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+// CreateWebhook stores a new webhook.
+func (db *SQLiteDatabase) CreateWebhook(ctx context.Context, webhook model.Webhook) (*model.Webhook, error) {
+	if webhook.CreatedAt == "" {
+		webhook.CreatedAt = time.Now().UTC().Format(model.DatabaseDateFormat)
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.SQLite.NewInsertBuilder()
+		ib.InsertInto("webhook")
+		ib.Cols("account_id", "url", "events", "secret", "created_at")
+		ib.Values(webhook.AccountID, webhook.URL, webhook.Events, webhook.Secret, webhook.CreatedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query)
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert webhook: %w", err)
+		}
+
+		webhookID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+
+		webhook.ID = model.DBID(webhookID)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+// CreateWebhookDelivery stores a new webhook delivery.
+func (db *SQLiteDatabase) CreateWebhookDelivery(ctx context.Context, delivery model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	if delivery.CreatedAt == "" {
+		delivery.CreatedAt = time.Now().UTC().Format(model.DatabaseDateFormat)
+	}
+	if delivery.Status == "" {
+		delivery.Status = model.WebhookDeliveryStatusPending
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.SQLite.NewInsertBuilder()
+		ib.InsertInto("webhook_delivery")
+		ib.Cols("webhook_id", "event", "payload", "status", "response_body", "last_error", "created_at")
+		ib.Values(delivery.WebhookID, delivery.Event, delivery.Payload, delivery.Status, delivery.ResponseBody,
+			delivery.LastError, delivery.CreatedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query)
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert webhook delivery: %w", err)
+		}
+
+		deliveryID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+
+		delivery.ID = model.DBID(deliveryID)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+// CreateCollection stores a new collection.
+func (db *SQLiteDatabase) CreateCollection(ctx context.Context, collection model.Collection) (*model.Collection, error) {
+	now := time.Now().UTC().Format(model.DatabaseDateFormat)
+	if collection.CreatedAt == "" {
+		collection.CreatedAt = now
+	}
+	if collection.ModifiedAt == "" {
+		collection.ModifiedAt = collection.CreatedAt
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.SQLite.NewInsertBuilder()
+		ib.InsertInto("collection")
+		ib.Cols("account_id", "parent_id", "name", "position", "created_at", "modified_at")
+		ib.Values(collection.AccountID, collection.ParentID, collection.Name, collection.Position, collection.CreatedAt, collection.ModifiedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query)
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert collection: %w", err)
+		}
+
+		collectionID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+
+		collection.ID = model.DBID(collectionID)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &collection, nil
+}
+
+// CreateTagAlias saves a new name resolving to an existing tag.
+func (db *SQLiteDatabase) CreateTagAlias(ctx context.Context, alias model.TagAlias) (model.TagAlias, error) {
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.SQLite.NewInsertBuilder()
+		ib.InsertInto("tag_alias")
+		ib.Cols("name", "tag_id")
+		ib.Values(alias.Name, alias.TagID)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query)
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert tag alias: %w", err)
+		}
+
+		aliasID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+
+		alias.ID = int(aliasID)
+		return nil
+	}); err != nil {
+		return model.TagAlias{}, err
+	}
+
+	return alias, nil
+}
+
+// CreateBookmarkRule stores a new bookmark rule.
+func (db *SQLiteDatabase) CreateBookmarkRule(ctx context.Context, rule model.BookmarkRule) (*model.BookmarkRule, error) {
+	now := time.Now().UTC().Format(model.DatabaseDateFormat)
+	if rule.CreatedAt == "" {
+		rule.CreatedAt = now
+	}
+	if rule.ModifiedAt == "" {
+		rule.ModifiedAt = rule.CreatedAt
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.SQLite.NewInsertBuilder()
+		ib.InsertInto("bookmark_rule")
+		ib.Cols("account_id", "name", "enabled", "conditions", "actions", "created_at", "modified_at")
+		ib.Values(rule.AccountID, rule.Name, rule.Enabled, rule.Conditions, rule.Actions, rule.CreatedAt, rule.ModifiedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query)
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert bookmark rule: %w", err)
+		}
+
+		ruleID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+
+		rule.ID = model.DBID(ruleID)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+// CreateSavedSearch stores a new saved search.
+func (db *SQLiteDatabase) CreateSavedSearch(ctx context.Context, search model.SavedSearch) (*model.SavedSearch, error) {
+	now := time.Now().UTC().Format(model.DatabaseDateFormat)
+	if search.CreatedAt == "" {
+		search.CreatedAt = now
+	}
+	if search.ModifiedAt == "" {
+		search.ModifiedAt = search.CreatedAt
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.SQLite.NewInsertBuilder()
+		ib.InsertInto("saved_search")
+		ib.Cols("account_id", "name", "filter", "created_at", "modified_at")
+		ib.Values(search.AccountID, search.Name, search.Filter, search.CreatedAt, search.ModifiedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query)
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert saved search: %w", err)
+		}
+
+		searchID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+
+		search.ID = model.DBID(searchID)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &search, nil
+}
+
+// CreateHighlight stores a new highlight.
+func (db *SQLiteDatabase) CreateHighlight(ctx context.Context, highlight model.Highlight) (*model.Highlight, error) {
+	now := time.Now().UTC().Format(model.DatabaseDateFormat)
+	if highlight.CreatedAt == "" {
+		highlight.CreatedAt = now
+	}
+	if highlight.ModifiedAt == "" {
+		highlight.ModifiedAt = highlight.CreatedAt
+	}
+
+	if err := db.withTx(ctx, func(tx *sqlx.Tx) error {
+		ib := sqlbuilder.SQLite.NewInsertBuilder()
+		ib.InsertInto("bookmark_highlight")
+		ib.Cols("bookmark_id", "account_id", "selector", "note", "created_at", "modified_at")
+		ib.Values(highlight.BookmarkID, highlight.AccountID, highlight.Selector, highlight.Note, highlight.CreatedAt, highlight.ModifiedAt)
+
+		query, args := ib.Build()
+		query = db.WriterDB().Rebind(query)
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert highlight: %w", err)
+		}
+
+		highlightID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+
+		highlight.ID = model.DBID(highlightID)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &highlight, nil
+}
@@ -30,6 +30,7 @@ func sqliteTestDatabaseFactory(t *testing.T, ctx context.Context) (model.DB, err
 func TestSqliteDatabase(t *testing.T) {
 	testDatabase(t, sqliteTestDatabaseFactory)
 	testSqliteGetBookmarksWithDash(t)
+	testSqliteMigrateBookmarkAccount(t)
 }
 
 // testSqliteGetBookmarksWithDash ad-hoc test for SQLite that checks that a match search against
@@ -69,3 +70,45 @@ func testSqliteGetBookmarksWithDash(t *testing.T) {
 	assert.Len(t, results, 1, "results should contain one item")
 	assert.Equal(t, savedBookmark.ID, results[0].ID, "bookmark should be the one saved")
 }
+
+// testSqliteMigrateBookmarkAccount checks that the migration adding bookmark ownership assigns
+// the existing bookmarks to the first owner, and that rebuilding the bookmark table keeps
+// the bookmark tags around.
+func testSqliteMigrateBookmarkAccount(t *testing.T) {
+	ctx := context.TODO()
+
+	tmpDir, err := os.MkdirTemp("", "")
+	require.NoError(t, err)
+
+	db, err := OpenSQLiteDatabase(ctx, filepath.Join(tmpDir, "shiori.db"))
+	require.NoError(t, err)
+
+	// Migrate up to the version right before bookmark ownership
+	var previous []migration
+	for _, m := range sqliteMigrations {
+		if m.toVersion.String() == "0.7.0" {
+			break
+		}
+		previous = append(previous, m)
+	}
+	require.NoError(t, runMigrations(ctx, db, previous))
+
+	for _, query := range []string{
+		`INSERT INTO account (id, username, password, owner) VALUES (4, 'reader', 'x', 0), (7, 'owner', 'x', 1)`,
+		`INSERT INTO bookmark (id, url, title, modified_at) VALUES (1, 'https://example.com', 'Example', '2024-01-01 00:00:00')`,
+		`INSERT INTO bookmark_content (docid, title, content, html) VALUES (1, 'Example', '', '')`,
+		`INSERT INTO tag (id, name) VALUES (1, 'example')`,
+		`INSERT INTO bookmark_tag (bookmark_id, tag_id) VALUES (1, 1)`,
+	} {
+		_, err := db.WriterDB().ExecContext(ctx, query)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, db.Migrate(ctx))
+
+	bookmark, exists, err := db.GetBookmark(ctx, 1, "", 7)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, model.DBID(7), bookmark.AccountID)
+	require.Len(t, bookmark.Tags, 1)
+}
@@ -30,26 +30,68 @@ func (d *Dependencies) Database() model.DB {
 }
 
 type domains struct {
-	auth      model.AuthDomain
-	accounts  model.AccountsDomain
-	bookmarks model.BookmarksDomain
-	archiver  model.ArchiverDomain
-	storage   model.StorageDomain
-	tags      model.TagsDomain
-}
-
-func (d *domains) Auth() model.AuthDomain                       { return d.auth }
-func (d *domains) SetAuth(auth model.AuthDomain)                { d.auth = auth }
-func (d *domains) Accounts() model.AccountsDomain               { return d.accounts }
-func (d *domains) SetAccounts(accounts model.AccountsDomain)    { d.accounts = accounts }
-func (d *domains) Bookmarks() model.BookmarksDomain             { return d.bookmarks }
-func (d *domains) SetBookmarks(bookmarks model.BookmarksDomain) { d.bookmarks = bookmarks }
-func (d *domains) Archiver() model.ArchiverDomain               { return d.archiver }
-func (d *domains) SetArchiver(archiver model.ArchiverDomain)    { d.archiver = archiver }
-func (d *domains) Storage() model.StorageDomain                 { return d.storage }
-func (d *domains) SetStorage(storage model.StorageDomain)       { d.storage = storage }
-func (d *domains) Tags() model.TagsDomain                       { return d.tags }
-func (d *domains) SetTags(tags model.TagsDomain)                { d.tags = tags }
+	auth          model.AuthDomain
+	oidc          model.OIDCDomain
+	totp          model.TOTPDomain
+	accounts      model.AccountsDomain
+	bookmarks     model.BookmarksDomain
+	archiver      model.ArchiverDomain
+	storage       model.StorageDomain
+	tags          model.TagsDomain
+	jobs          model.JobsDomain
+	linkChecker   model.LinkCheckerDomain
+	subscriptions model.SubscriptionsDomain
+	webhooks      model.WebhooksDomain
+	collections   model.CollectionsDomain
+	rules         model.RulesDomain
+	savedSearches model.SavedSearchesDomain
+	highlights    model.HighlightsDomain
+	backup        model.BackupDomain
+}
+
+func (d *domains) Auth() model.AuthDomain                             { return d.auth }
+func (d *domains) SetAuth(auth model.AuthDomain)                      { d.auth = auth }
+func (d *domains) OIDC() model.OIDCDomain                             { return d.oidc }
+func (d *domains) SetOIDC(oidc model.OIDCDomain)                      { d.oidc = oidc }
+func (d *domains) TOTP() model.TOTPDomain                             { return d.totp }
+func (d *domains) SetTOTP(totp model.TOTPDomain)                      { d.totp = totp }
+func (d *domains) Accounts() model.AccountsDomain                     { return d.accounts }
+func (d *domains) SetAccounts(accounts model.AccountsDomain)          { d.accounts = accounts }
+func (d *domains) Bookmarks() model.BookmarksDomain                   { return d.bookmarks }
+func (d *domains) SetBookmarks(bookmarks model.BookmarksDomain)       { d.bookmarks = bookmarks }
+func (d *domains) Archiver() model.ArchiverDomain                     { return d.archiver }
+func (d *domains) SetArchiver(archiver model.ArchiverDomain)          { d.archiver = archiver }
+func (d *domains) Storage() model.StorageDomain                       { return d.storage }
+func (d *domains) SetStorage(storage model.StorageDomain)             { d.storage = storage }
+func (d *domains) Tags() model.TagsDomain                             { return d.tags }
+func (d *domains) SetTags(tags model.TagsDomain)                      { d.tags = tags }
+func (d *domains) Jobs() model.JobsDomain                             { return d.jobs }
+func (d *domains) SetJobs(jobs model.JobsDomain)                      { d.jobs = jobs }
+func (d *domains) LinkChecker() model.LinkCheckerDomain               { return d.linkChecker }
+func (d *domains) SetLinkChecker(linkChecker model.LinkCheckerDomain) { d.linkChecker = linkChecker }
+func (d *domains) Subscriptions() model.SubscriptionsDomain           { return d.subscriptions }
+func (d *domains) SetSubscriptions(subscriptions model.SubscriptionsDomain) {
+	d.subscriptions = subscriptions
+}
+func (d *domains) Webhooks() model.WebhooksDomain            { return d.webhooks }
+func (d *domains) SetWebhooks(webhooks model.WebhooksDomain) { d.webhooks = webhooks }
+func (d *domains) Collections() model.CollectionsDomain      { return d.collections }
+func (d *domains) SetCollections(collections model.CollectionsDomain) {
+	d.collections = collections
+}
+func (d *domains) Rules() model.RulesDomain                 { return d.rules }
+func (d *domains) SetRules(rules model.RulesDomain)         { d.rules = rules }
+func (d *domains) SavedSearches() model.SavedSearchesDomain { return d.savedSearches }
+func (d *domains) SetSavedSearches(savedSearches model.SavedSearchesDomain) {
+	d.savedSearches = savedSearches
+}
+
+func (d *domains) Highlights() model.HighlightsDomain { return d.highlights }
+func (d *domains) SetHighlights(highlights model.HighlightsDomain) {
+	d.highlights = highlights
+}
+func (d *domains) Backup() model.BackupDomain          { return d.backup }
+func (d *domains) SetBackup(backup model.BackupDomain) { d.backup = backup }
 
 var _ model.DomainDependencies = (*domains)(nil)
 
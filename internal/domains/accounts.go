@@ -92,6 +92,11 @@ func (d *AccountsDomain) DeleteAccount(ctx context.Context, id int) error {
 		return fmt.Errorf("error deleting account: %v", err)
 	}
 
+	// Leave the bookmarks unowned so an account reusing this ID doesn't get them
+	if err := d.deps.Database().TransferBookmarks(ctx, model.DBID(id), 0); err != nil {
+		return fmt.Errorf("error releasing account bookmarks: %v", err)
+	}
+
 	return nil
 }
 
@@ -140,6 +145,13 @@ func (d *AccountsDomain) UpdateAccount(ctx context.Context, account model.Accoun
 		return nil, fmt.Errorf("error updating account: %w", err)
 	}
 
+	// Sessions started with the old password must not outlive it
+	if account.Password != "" {
+		if err := d.deps.Database().DeleteAccountSessions(ctx, account.ID); err != nil {
+			return nil, fmt.Errorf("error ending account sessions: %w", err)
+		}
+	}
+
 	// Get updated account from database
 	updatedAccount, _, err := d.deps.Database().GetAccount(ctx, account.ID)
 	if err != nil {
@@ -172,6 +172,27 @@ func TestAccountDomainDeleteAccount(t *testing.T) {
 		require.Empty(t, accounts)
 	})
 
+	t.Run("delete account releases its bookmarks", func(t *testing.T) {
+		acc, err := deps.Domains().Accounts().CreateAccount(context.TODO(), model.AccountDTO{
+			Username: "bookmarker",
+			Password: "password",
+		})
+		require.NoError(t, err)
+
+		bookmark := testutil.GetValidBookmark()
+		bookmark.AccountID = acc.ID
+		saved, err := deps.Database().SaveBookmarks(context.TODO(), true, *bookmark)
+		require.NoError(t, err)
+
+		err = deps.Domains().Accounts().DeleteAccount(context.TODO(), int(acc.ID))
+		require.NoError(t, err)
+
+		released, exists, err := deps.Database().GetBookmark(context.TODO(), saved[0].ID, "", 0)
+		require.NoError(t, err)
+		require.True(t, exists)
+		require.Equal(t, model.DBID(0), released.AccountID)
+	})
+
 	t.Run("delete non-existing account", func(t *testing.T) {
 		err := deps.Domains().Accounts().DeleteAccount(context.TODO(), 999)
 		require.Error(t, err)
@@ -181,8 +202,10 @@ func TestAccountDomainDeleteAccount(t *testing.T) {
 	t.Run("valid account", func(t *testing.T) {
 		account := testutil.GetValidAccount().ToDTO()
 		token, err := deps.Domains().Auth().CreateTokenForAccount(
+			context.TODO(),
 			&account,
 			time.Now().Add(time.Hour*1),
+			"",
 		)
 		require.NoError(t, err)
 		require.NotEmpty(t, token)
@@ -190,8 +213,10 @@ func TestAccountDomainDeleteAccount(t *testing.T) {
 
 	t.Run("nil account", func(t *testing.T) {
 		token, err := deps.Domains().Auth().CreateTokenForAccount(
+			context.TODO(),
 			nil,
 			time.Now().Add(time.Hour*1),
+			"",
 		)
 		require.Error(t, err)
 		require.Empty(t, token)
@@ -199,11 +224,17 @@ func TestAccountDomainDeleteAccount(t *testing.T) {
 
 	t.Run("token expiration is valid", func(t *testing.T) {
 		ctx := context.TODO()
-		account := testutil.GetValidAccount().ToDTO()
+		account, err := deps.Domains().Accounts().CreateAccount(ctx, model.AccountDTO{
+			Username: "expiration",
+			Password: "expiration",
+		})
+		require.NoError(t, err)
 		expiration := time.Now().Add(time.Hour * 9)
 		token, err := deps.Domains().Auth().CreateTokenForAccount(
-			&account,
+			ctx,
+			account,
 			expiration,
+			"",
 		)
 		require.NoError(t, err)
 		require.NotEmpty(t, token)
@@ -211,4 +242,25 @@ func TestAccountDomainDeleteAccount(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, tokenAccount)
 	})
+
+	t.Run("deleted account sessions are revoked", func(t *testing.T) {
+		ctx := context.TODO()
+		account, err := deps.Domains().Accounts().CreateAccount(ctx, model.AccountDTO{
+			Username: "revoked",
+			Password: "revoked",
+		})
+		require.NoError(t, err)
+
+		token, err := deps.Domains().Auth().CreateTokenForAccount(ctx, account, time.Now().Add(time.Hour), "")
+		require.NoError(t, err)
+
+		require.NoError(t, deps.Domains().Accounts().DeleteAccount(ctx, int(account.ID)))
+
+		sessions, err := deps.Database().ListSessions(ctx, account.ID)
+		require.NoError(t, err)
+		require.Empty(t, sessions)
+
+		_, err = deps.Domains().Auth().CheckToken(ctx, token)
+		require.Error(t, err)
+	})
 }
@@ -1,7 +1,10 @@
 package domains
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/go-shiori/shiori/internal/core"
@@ -48,6 +51,231 @@ func (d *ArchiverDomain) GetBookmarkArchive(book *model.BookmarkDTO) (*warc.Arch
 	return warc.Open(filepath.Join(d.deps.Config().Storage.DataDir, archivePath))
 }
 
+// ListSnapshots returns the archive snapshots of a bookmark, newest first.
+func (d *ArchiverDomain) ListSnapshots(ctx context.Context, book *model.BookmarkDTO) ([]model.ArchiveSnapshot, error) {
+	snapshots, err := d.deps.Database().GetArchiveSnapshots(ctx, book.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	currentPath := model.GetArchivePath(book)
+	for i := range snapshots {
+		snapshots[i].Current = snapshots[i].Path == currentPath
+	}
+
+	return snapshots, nil
+}
+
+// GetSnapshot returns an archive snapshot of the bookmark, model.ErrNotFound if it belongs to another one.
+func (d *ArchiverDomain) GetSnapshot(ctx context.Context, book *model.BookmarkDTO, id model.DBID) (*model.ArchiveSnapshot, error) {
+	snapshot, exists, err := d.deps.Database().GetArchiveSnapshot(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists || snapshot.BookmarkID != book.ID {
+		return nil, model.ErrNotFound
+	}
+
+	snapshot.Current = snapshot.Path == model.GetArchivePath(book)
+
+	return snapshot, nil
+}
+
+func (d *ArchiverDomain) GetSnapshotArchive(snapshot *model.ArchiveSnapshot) (*warc.Archive, error) {
+	if !d.deps.Domains().Storage().FileExists(snapshot.Path) {
+		return nil, fmt.Errorf("archive snapshot %d doesn't exist", snapshot.ID)
+	}
+
+	// FIXME: This only works in local filesystem
+	return warc.Open(filepath.Join(d.deps.Config().Storage.DataDir, snapshot.Path))
+}
+
+// DeleteSnapshot removes an archive snapshot of the bookmark. When the current archive is
+// removed the newest remaining snapshot takes its place.
+func (d *ArchiverDomain) DeleteSnapshot(ctx context.Context, book *model.BookmarkDTO, id model.DBID) error {
+	snapshot, err := d.GetSnapshot(ctx, book, id)
+	if err != nil {
+		return err
+	}
+
+	if err := d.deps.Domains().Storage().FS().Remove(snapshot.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove archive snapshot file: %w", err)
+	}
+
+	if err := d.deps.Database().DeleteArchiveSnapshots(ctx, snapshot.ID); err != nil {
+		return err
+	}
+
+	if !snapshot.Current {
+		return nil
+	}
+
+	remaining, err := d.deps.Database().GetArchiveSnapshots(ctx, book.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(remaining) == 0 {
+		return d.deps.Database().DeleteArchiveTexts(ctx, book.ID)
+	}
+
+	if err := d.moveSnapshot(ctx, remaining[0], model.GetArchivePath(book)); err != nil {
+		return err
+	}
+
+	// The text of the deleted archive stays searchable until it is indexed again
+	if err := d.IndexArchive(ctx, book); err != nil {
+		d.deps.Logger().WithError(err).WithField("bookmark_id", book.ID).Warn("failed to index archive text")
+	}
+
+	return nil
+}
+
+// PreserveArchive moves the current archive of the bookmark among its snapshots, so a new
+// capture doesn't overwrite it. Archives saved before snapshots existed are recorded as well.
+func (d *ArchiverDomain) PreserveArchive(ctx context.Context, book *model.BookmarkDTO) error {
+	storage := d.deps.Domains().Storage()
+	currentPath := model.GetArchivePath(book)
+
+	info, err := storage.Stat(currentPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to check current archive: %w", err)
+	}
+
+	snapshots, err := d.deps.Database().GetArchiveSnapshots(ctx, book.ID)
+	if err != nil {
+		return err
+	}
+
+	var current *model.ArchiveSnapshot
+	for i := range snapshots {
+		if snapshots[i].Path == currentPath {
+			current = &snapshots[i]
+			break
+		}
+	}
+
+	if current == nil {
+		current, err = d.deps.Database().CreateArchiveSnapshot(ctx, model.ArchiveSnapshot{
+			BookmarkID: book.ID,
+			Path:       currentPath,
+			CreatedAt:  info.ModTime().UTC().Format(model.DatabaseDateFormat),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return d.moveSnapshot(ctx, *current, model.GetArchiveSnapshotPath(book, current.ID))
+}
+
+// RecordArchive registers the current archive of the bookmark as a new snapshot and removes
+// the oldest snapshots beyond the configured retention.
+func (d *ArchiverDomain) RecordArchive(ctx context.Context, book *model.BookmarkDTO) error {
+	if _, err := d.deps.Database().CreateArchiveSnapshot(ctx, model.ArchiveSnapshot{
+		BookmarkID: book.ID,
+		Path:       model.GetArchivePath(book),
+	}); err != nil {
+		return err
+	}
+
+	keep := d.deps.Config().Storage.ArchiveSnapshotsKeep
+	if keep <= 0 {
+		return nil
+	}
+
+	snapshots, err := d.deps.Database().GetArchiveSnapshots(ctx, book.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(snapshots) <= keep {
+		return nil
+	}
+
+	expired := []model.DBID{}
+	for _, snapshot := range snapshots[keep:] {
+		if err := d.deps.Domains().Storage().FS().Remove(snapshot.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			d.deps.Logger().WithError(err).WithField("path", snapshot.Path).Warn("failed to remove expired archive snapshot")
+			continue
+		}
+		expired = append(expired, snapshot.ID)
+	}
+
+	return d.deps.Database().DeleteArchiveSnapshots(ctx, expired...)
+}
+
+// IndexArchive extracts the text of the current archive of the bookmark and saves it so it can
+// be searched, the text of a bookmark without archive is removed.
+func (d *ArchiverDomain) IndexArchive(ctx context.Context, book *model.BookmarkDTO) error {
+	if !d.deps.Domains().Storage().FileExists(model.GetArchivePath(book)) {
+		return d.deps.Database().DeleteArchiveTexts(ctx, book.ID)
+	}
+
+	archive, err := d.GetBookmarkArchive(book)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	text, err := core.ExtractArchiveText(archive)
+	if err != nil {
+		return fmt.Errorf("failed to extract archive text: %w", err)
+	}
+
+	return d.deps.Database().SaveArchiveText(ctx, book.ID, text)
+}
+
+// IndexArchives indexes the archives of the bookmarks with the given ids, or of every bookmark
+// if there are none, from the saved archives without downloading the pages again.
+func (d *ArchiverDomain) IndexArchives(ctx context.Context, ids []int) error {
+	bookmarks, err := d.deps.Database().GetBookmarks(ctx, model.DBGetBookmarksOptions{IDs: ids})
+	if err != nil {
+		return fmt.Errorf("failed to get bookmarks: %w", err)
+	}
+
+	for _, book := range bookmarks {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := d.IndexArchive(ctx, &book); err != nil {
+			d.deps.Logger().WithError(err).WithField("bookmark_id", book.ID).Error("failed to index archive text")
+		}
+	}
+
+	return nil
+}
+
+// moveSnapshot moves the snapshot file to dstPath and updates its record.
+func (d *ArchiverDomain) moveSnapshot(ctx context.Context, snapshot model.ArchiveSnapshot, dstPath string) error {
+	fs := d.deps.Domains().Storage().FS()
+
+	if err := fs.MkdirAll(filepath.Dir(dstPath), model.DataDirPerm); err != nil {
+		return fmt.Errorf("failed to create archive snapshot dir: %w", err)
+	}
+
+	if err := fs.Rename(snapshot.Path, dstPath); err != nil {
+		return fmt.Errorf("failed to move archive snapshot: %w", err)
+	}
+
+	srcPath := snapshot.Path
+	snapshot.Path = dstPath
+	if err := d.deps.Database().UpdateArchiveSnapshot(ctx, snapshot); err != nil {
+		// Put the file back so it still matches its record
+		if err := fs.Rename(dstPath, srcPath); err != nil {
+			d.deps.Logger().WithError(err).WithField("path", dstPath).Error("failed to restore archive snapshot")
+		}
+		return err
+	}
+
+	return nil
+}
+
 func NewArchiverDomain(deps *dependencies.Dependencies) *ArchiverDomain {
 	return &ArchiverDomain{
 		deps: deps,
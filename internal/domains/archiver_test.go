@@ -0,0 +1,165 @@
+package domains_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/shiori/internal/testutil"
+	"github.com/go-shiori/warc"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiverDomainSnapshots(t *testing.T) {
+	ctx := context.Background()
+	logger := logrus.New()
+
+	setup := func(t *testing.T, keep int) (model.Dependencies, *model.BookmarkDTO) {
+		cfg, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		cfg.Storage.ArchiveSnapshotsKeep = keep
+
+		result, err := deps.Database().SaveBookmarks(ctx, true, model.BookmarkDTO{
+			URL:   "https://github.com/go-shiori/shiori",
+			Title: "shiori",
+		})
+		require.NoError(t, err)
+		book := result[0]
+		return deps, &book
+	}
+
+	// capture simulates a new archive being saved for the bookmark
+	capture := func(t *testing.T, deps model.Dependencies, book *model.BookmarkDTO, content string) {
+		archiver := deps.Domains().Archiver()
+		require.NoError(t, archiver.PreserveArchive(ctx, book))
+		require.NoError(t, deps.Domains().Storage().WriteData(model.GetArchivePath(book), []byte(content)))
+		require.NoError(t, archiver.RecordArchive(ctx, book))
+	}
+
+	readSnapshot := func(t *testing.T, deps model.Dependencies, snapshot model.ArchiveSnapshot) string {
+		data, err := deps.Domains().Storage().FS().Open(snapshot.Path)
+		require.NoError(t, err)
+		defer data.Close()
+
+		content := make([]byte, 64)
+		n, _ := data.Read(content)
+		return string(content[:n])
+	}
+
+	t.Run("previous archives are kept as snapshots", func(t *testing.T) {
+		deps, book := setup(t, 5)
+		capture(t, deps, book, "first")
+		capture(t, deps, book, "second")
+
+		snapshots, err := deps.Domains().Archiver().ListSnapshots(ctx, book)
+		require.NoError(t, err)
+		require.Len(t, snapshots, 2)
+		require.True(t, snapshots[0].Current)
+		require.Equal(t, "second", readSnapshot(t, deps, snapshots[0]))
+		require.False(t, snapshots[1].Current)
+		require.Equal(t, model.GetArchiveSnapshotPath(book, snapshots[1].ID), snapshots[1].Path)
+		require.Equal(t, "first", readSnapshot(t, deps, snapshots[1]))
+	})
+
+	t.Run("archives saved before snapshots are recorded", func(t *testing.T) {
+		deps, book := setup(t, 5)
+		require.NoError(t, deps.Domains().Storage().WriteData(model.GetArchivePath(book), []byte("legacy")))
+		capture(t, deps, book, "new")
+
+		snapshots, err := deps.Domains().Archiver().ListSnapshots(ctx, book)
+		require.NoError(t, err)
+		require.Len(t, snapshots, 2)
+		require.Equal(t, "legacy", readSnapshot(t, deps, snapshots[1]))
+	})
+
+	t.Run("oldest snapshots beyond retention are removed", func(t *testing.T) {
+		deps, book := setup(t, 2)
+		capture(t, deps, book, "first")
+		capture(t, deps, book, "second")
+		capture(t, deps, book, "third")
+
+		snapshots, err := deps.Domains().Archiver().ListSnapshots(ctx, book)
+		require.NoError(t, err)
+		require.Len(t, snapshots, 2)
+		require.Equal(t, "third", readSnapshot(t, deps, snapshots[0]))
+		require.Equal(t, "second", readSnapshot(t, deps, snapshots[1]))
+	})
+
+	t.Run("snapshot of another bookmark", func(t *testing.T) {
+		deps, book := setup(t, 5)
+		capture(t, deps, book, "first")
+
+		snapshots, err := deps.Domains().Archiver().ListSnapshots(ctx, book)
+		require.NoError(t, err)
+
+		other := &model.BookmarkDTO{ID: book.ID + 1}
+		_, err = deps.Domains().Archiver().GetSnapshot(ctx, other, snapshots[0].ID)
+		require.ErrorIs(t, err, model.ErrNotFound)
+	})
+
+	t.Run("deleting the current archive promotes the newest snapshot", func(t *testing.T) {
+		deps, book := setup(t, 5)
+		capture(t, deps, book, "first")
+		capture(t, deps, book, "second")
+
+		snapshots, err := deps.Domains().Archiver().ListSnapshots(ctx, book)
+		require.NoError(t, err)
+		require.NoError(t, deps.Domains().Archiver().DeleteSnapshot(ctx, book, snapshots[0].ID))
+
+		snapshots, err = deps.Domains().Archiver().ListSnapshots(ctx, book)
+		require.NoError(t, err)
+		require.Len(t, snapshots, 1)
+		require.True(t, snapshots[0].Current)
+		require.Equal(t, "first", readSnapshot(t, deps, snapshots[0]))
+		require.True(t, deps.Domains().Bookmarks().HasArchive(book))
+	})
+
+	t.Run("deleting the last archive", func(t *testing.T) {
+		deps, book := setup(t, 5)
+		capture(t, deps, book, "first")
+
+		snapshots, err := deps.Domains().Archiver().ListSnapshots(ctx, book)
+		require.NoError(t, err)
+		require.NoError(t, deps.Domains().Archiver().DeleteSnapshot(ctx, book, snapshots[0].ID))
+		require.False(t, deps.Domains().Bookmarks().HasArchive(book))
+	})
+}
+
+func TestArchiverDomainIndexArchive(t *testing.T) {
+	ctx := context.Background()
+	logger := logrus.New()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`<html><body><p>Archived text</p></body></html>`))
+	}))
+	defer server.Close()
+
+	cfg, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+	result, err := deps.Database().SaveBookmarks(ctx, true, model.BookmarkDTO{URL: server.URL, Title: "page"})
+	require.NoError(t, err)
+	book := result[0]
+
+	archivePath := filepath.Join(cfg.Storage.DataDir, model.GetArchivePath(&book))
+	require.NoError(t, warc.NewArchive(warc.ArchivalRequest{URL: server.URL}, archivePath))
+
+	t.Run("text of the archive is saved", func(t *testing.T) {
+		require.NoError(t, deps.Domains().Archiver().IndexArchives(ctx, []int{book.ID}))
+
+		texts, err := deps.Database().GetArchiveTexts(ctx, []int{book.ID})
+		require.NoError(t, err)
+		require.Equal(t, "Archived text", texts[book.ID])
+	})
+
+	t.Run("text is removed without archive", func(t *testing.T) {
+		require.NoError(t, deps.Domains().Storage().FS().Remove(model.GetArchivePath(&book)))
+		require.NoError(t, deps.Domains().Archiver().IndexArchive(ctx, &book))
+
+		texts, err := deps.Database().GetArchiveTexts(ctx, []int{book.ID})
+		require.NoError(t, err)
+		require.Empty(t, texts)
+	})
+}
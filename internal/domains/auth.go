@@ -2,11 +2,20 @@ package domains
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/go-shiori/shiori/internal/database"
 	"github.com/go-shiori/shiori/internal/dependencies"
 	"github.com/go-shiori/shiori/internal/model"
+	"github.com/gofrs/uuid/v5"
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -17,11 +26,20 @@ type AuthDomain struct {
 
 type JWTClaim struct {
 	jwt.RegisteredClaims
-
-	Account *model.AccountDTO
 }
 
+// sessionUserAgentLength is the longest user agent kept for a session
+const sessionUserAgentLength = 512
+
+// CheckToken returns the account a session JWT belongs to.
 func (d *AuthDomain) CheckToken(ctx context.Context, userJWT string) (*model.AccountDTO, error) {
+	account, _, err := d.CheckSession(ctx, userJWT)
+	return account, err
+}
+
+// CheckSession validates a session JWT and returns its session along with the account, read
+// from the database so changes to the account apply to existing sessions.
+func (d *AuthDomain) CheckSession(ctx context.Context, userJWT string) (*model.AccountDTO, *model.Session, error) {
 	token, err := jwt.ParseWithClaims(userJWT, &JWTClaim{}, func(token *jwt.Token) (interface{}, error) {
 		// Validate algorithm
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -31,17 +49,51 @@ func (d *AuthDomain) CheckToken(ctx context.Context, userJWT string) (*model.Acc
 		return d.deps.Config().Http.SecretKey, nil
 	})
 	if err != nil {
-		return nil, fmt.Errorf("error parsing token: %w", err)
+		return nil, nil, fmt.Errorf("error parsing token: %w", err)
 	}
 
-	if claims, ok := token.Claims.(*JWTClaim); ok && token.Valid {
-		if claims.Account.ID > 0 {
-			return claims.Account, nil
-		}
+	claims, ok := token.Claims.(*JWTClaim)
+	if !ok || !token.Valid || claims.ID == "" {
+		return nil, nil, fmt.Errorf("error obtaining session from JWT claims")
+	}
+
+	session, exists, err := d.deps.Database().GetSession(ctx, claims.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !exists {
+		return nil, nil, fmt.Errorf("session not found")
+	}
+
+	now := time.Now().UTC()
+	expiresAt, err := parseDatabaseTime(session.ExpiresAt)
+	if err != nil || !expiresAt.After(now) {
+		return nil, nil, fmt.Errorf("session expired")
+	}
+
+	account, exists, err := d.deps.Database().GetAccount(ctx, session.AccountID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !exists {
+		return nil, nil, fmt.Errorf("session account not found")
+	}
 
-		return claims.Account, nil
+	lastUsed := time.Time{}
+	if session.LastUsedAt != nil {
+		lastUsed, _ = parseDatabaseTime(*session.LastUsedAt)
 	}
-	return nil, fmt.Errorf("error obtaining user from JWT claims")
+
+	if now.Sub(lastUsed) >= lastUsedInterval {
+		session.LastUsedAt = model.Ptr(now.Format(model.DatabaseDateFormat))
+		if err := d.deps.Database().UpdateSessionLastUsed(ctx, session.ID, *session.LastUsedAt); err != nil {
+			d.deps.Logger().WithError(err).Warn("failed to update session last use")
+		}
+	}
+
+	return model.Ptr(account.ToDTO()), session, nil
 }
 
 func (d *AuthDomain) GetAccountFromCredentials(ctx context.Context, username, password string) (*model.AccountDTO, error) {
@@ -66,14 +118,40 @@ func (d *AuthDomain) GetAccountFromCredentials(ctx context.Context, username, pa
 	return model.Ptr(account.ToDTO()), nil
 }
 
-func (d *AuthDomain) CreateTokenForAccount(account *model.AccountDTO, expiration time.Time) (string, error) {
+// CreateTokenForAccount starts a session for the account and returns its JWT, which is
+// valid until the expiration or until the session is revoked.
+func (d *AuthDomain) CreateTokenForAccount(ctx context.Context, account *model.AccountDTO, expiration time.Time, userAgent string) (string, error) {
 	if account == nil {
 		return "", fmt.Errorf("account is nil")
 	}
 
-	claims := jwt.MapClaims{
-		"account": account,
-		"exp":     expiration.UTC().Unix(),
+	sessionID, err := uuid.NewV4()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate session id: %w", err)
+	}
+
+	if len(userAgent) > sessionUserAgentLength {
+		userAgent = strings.ToValidUTF8(userAgent[:sessionUserAgentLength], "")
+	}
+
+	d.pruneSessions(ctx, account.ID)
+
+	session, err := d.deps.Database().CreateSession(ctx, model.Session{
+		ID:        sessionID.String(),
+		AccountID: account.ID,
+		UserAgent: userAgent,
+		ExpiresAt: expiration.UTC().Format(model.DatabaseDateFormat),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create session: %w", err)
+	}
+
+	claims := JWTClaim{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        session.ID,
+			Subject:   strconv.Itoa(int(account.ID)),
+			ExpiresAt: jwt.NewNumericDate(expiration),
+		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -86,6 +164,255 @@ func (d *AuthDomain) CreateTokenForAccount(account *model.AccountDTO, expiration
 	return t, err
 }
 
+// pruneSessions removes the expired sessions of an account, so they don't pile up for
+// accounts that never log out.
+func (d *AuthDomain) pruneSessions(ctx context.Context, accountID model.DBID) {
+	sessions, err := d.deps.Database().ListSessions(ctx, accountID)
+	if err != nil {
+		d.deps.Logger().WithError(err).Warn("failed to list sessions to prune")
+		return
+	}
+
+	now := time.Now().UTC()
+	for _, session := range sessions {
+		if expiresAt, err := parseDatabaseTime(session.ExpiresAt); err == nil && expiresAt.After(now) {
+			continue
+		}
+
+		if err := d.deps.Database().DeleteSession(ctx, accountID, session.ID); err != nil && !errors.Is(err, database.ErrNotFound) {
+			d.deps.Logger().WithError(err).Warn("failed to prune session")
+		}
+	}
+}
+
+// ListSessions returns the sessions of an account that haven't expired.
+func (d *AuthDomain) ListSessions(ctx context.Context, accountID model.DBID) ([]model.Session, error) {
+	sessions, err := d.deps.Database().ListSessions(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	active := []model.Session{}
+	for _, session := range sessions {
+		if expiresAt, err := parseDatabaseTime(session.ExpiresAt); err == nil && expiresAt.After(now) {
+			active = append(active, session)
+		}
+	}
+
+	return active, nil
+}
+
+// RevokeSession ends a session of an account, model.ErrNotFound if it doesn't exist or
+// belongs to another account.
+func (d *AuthDomain) RevokeSession(ctx context.Context, accountID model.DBID, id string) error {
+	err := d.deps.Database().DeleteSession(ctx, accountID, id)
+	if errors.Is(err, database.ErrNotFound) {
+		return model.ErrNotFound
+	}
+	return err
+}
+
+// lastUsedInterval is how often the last use of a token or session is written, so busy
+// clients don't update the database on every request
+const lastUsedInterval = time.Minute
+
+// hashAPIToken returns the hash stored in place of the token. Tokens are random, so a fast
+// hash is enough to keep them from being usable if the database leaks.
+func hashAPIToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// parseDatabaseTime parses the dates of a token or session as returned by any of the databases.
+func parseDatabaseTime(value string) (time.Time, error) {
+	for _, layout := range []string{model.DatabaseDateFormat, time.RFC3339Nano} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date: %s", value)
+}
+
+// CreateAPIToken creates a personal API token for the account. The token itself is only
+// returned here, the database keeps its hash.
+func (d *AuthDomain) CreateAPIToken(ctx context.Context, account *model.AccountDTO, name string, scopes model.APITokenScopes, expiresAt *time.Time) (*model.APIToken, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", model.NewValidationError("name", "name should not be empty")
+	}
+
+	if len(scopes) == 0 {
+		return nil, "", model.NewValidationError("scopes", "at least one scope is required")
+	}
+
+	for _, scope := range scopes {
+		if err := scope.IsValid(); err != nil {
+			return nil, "", model.NewValidationError("scopes", err.Error())
+		}
+	}
+
+	if scopes.Allows(model.APITokenScopeAdmin) && !account.IsOwner() {
+		return nil, "", model.NewValidationError("scopes", "only owners can create tokens with the admin scope")
+	}
+
+	token := model.APIToken{
+		AccountID: account.ID,
+		Name:      name,
+		Scopes:    scopes,
+	}
+
+	if expiresAt != nil {
+		if !expiresAt.After(time.Now()) {
+			return nil, "", model.NewValidationError("expires_at", "expiration should be in the future")
+		}
+		token.ExpiresAt = model.Ptr(expiresAt.UTC().Format(model.DatabaseDateFormat))
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	value := model.APITokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	token.TokenHash = hashAPIToken(value)
+
+	created, err := d.deps.Database().CreateAPIToken(ctx, token)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create token: %w", err)
+	}
+
+	return created, value, nil
+}
+
+// ListAPITokens returns the personal API tokens of an account.
+func (d *AuthDomain) ListAPITokens(ctx context.Context, accountID model.DBID) ([]model.APIToken, error) {
+	return d.deps.Database().ListAPITokens(ctx, accountID)
+}
+
+// RevokeAPIToken removes a personal API token of an account, model.ErrNotFound if it
+// doesn't exist or belongs to another account.
+func (d *AuthDomain) RevokeAPIToken(ctx context.Context, accountID model.DBID, id model.DBID) error {
+	err := d.deps.Database().DeleteAPIToken(ctx, accountID, id)
+	if errors.Is(err, database.ErrNotFound) {
+		return model.ErrNotFound
+	}
+	return err
+}
+
+// CheckAPIToken returns the account a personal API token belongs to, along with the token
+// so its scopes can be enforced.
+func (d *AuthDomain) CheckAPIToken(ctx context.Context, value string) (*model.AccountDTO, *model.APIToken, error) {
+	if !strings.HasPrefix(value, model.APITokenPrefix) {
+		return nil, nil, fmt.Errorf("not an api token")
+	}
+
+	token, exists, err := d.deps.Database().GetAPIToken(ctx, hashAPIToken(value))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !exists {
+		return nil, nil, fmt.Errorf("api token not found")
+	}
+
+	now := time.Now().UTC()
+	if token.ExpiresAt != nil {
+		expiresAt, err := parseDatabaseTime(*token.ExpiresAt)
+		if err != nil || !expiresAt.After(now) {
+			return nil, nil, fmt.Errorf("api token expired")
+		}
+	}
+
+	account, exists, err := d.deps.Database().GetAccount(ctx, token.AccountID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !exists {
+		return nil, nil, fmt.Errorf("api token account not found")
+	}
+
+	lastUsed := time.Time{}
+	if token.LastUsedAt != nil {
+		lastUsed, _ = parseDatabaseTime(*token.LastUsedAt)
+	}
+
+	if now.Sub(lastUsed) >= lastUsedInterval {
+		token.LastUsedAt = model.Ptr(now.Format(model.DatabaseDateFormat))
+		if err := d.deps.Database().UpdateAPITokenLastUsed(ctx, token.ID, *token.LastUsedAt); err != nil {
+			d.deps.Logger().WithError(err).Warn("failed to update api token last use")
+		}
+	}
+
+	return model.Ptr(account.ToDTO()), token, nil
+}
+
+// CreateFeedToken creates the token of the private feed of an account, replacing the
+// existing one. Like API tokens, it is only returned here.
+func (d *AuthDomain) CreateFeedToken(ctx context.Context, accountID model.DBID) (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	value := model.FeedTokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	if err := d.deps.Database().SaveFeedToken(ctx, model.FeedToken{
+		AccountID: accountID,
+		TokenHash: hashAPIToken(value),
+	}); err != nil {
+		return "", fmt.Errorf("failed to save feed token: %w", err)
+	}
+
+	return value, nil
+}
+
+// GetFeedToken returns the feed token of an account, model.ErrNotFound if it has none.
+func (d *AuthDomain) GetFeedToken(ctx context.Context, accountID model.DBID) (*model.FeedToken, error) {
+	token, exists, err := d.deps.Database().GetFeedToken(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, model.ErrNotFound
+	}
+
+	return token, nil
+}
+
+// RevokeFeedToken removes the feed token of an account, closing its private feed.
+func (d *AuthDomain) RevokeFeedToken(ctx context.Context, accountID model.DBID) error {
+	return d.deps.Database().DeleteFeedToken(ctx, accountID)
+}
+
+// CheckFeedToken returns the account a feed token belongs to.
+func (d *AuthDomain) CheckFeedToken(ctx context.Context, value string) (*model.AccountDTO, error) {
+	if !strings.HasPrefix(value, model.FeedTokenPrefix) {
+		return nil, fmt.Errorf("not a feed token")
+	}
+
+	token, exists, err := d.deps.Database().GetFeedTokenByHash(ctx, hashAPIToken(value))
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("feed token not found")
+	}
+
+	account, exists, err := d.deps.Database().GetAccount(ctx, token.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("feed token account not found")
+	}
+
+	return model.Ptr(account.ToDTO()), nil
+}
+
 func NewAuthDomain(deps *dependencies.Dependencies) *AuthDomain {
 	return &AuthDomain{
 		deps: deps,
@@ -2,6 +2,7 @@ package domains_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -19,27 +20,35 @@ func TestAuthDomainCheckToken(t *testing.T) {
 	_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
 	domain := domains.NewAuthDomain(deps)
 
+	account, err := deps.Domains().Accounts().CreateAccount(ctx, model.AccountDTO{
+		Username: "test",
+		Password: "test",
+	})
+	require.NoError(t, err)
+
 	t.Run("valid token", func(t *testing.T) {
 		// Create a valid token
-		account := testutil.GetValidAccount().ToDTO()
 		token, err := domain.CreateTokenForAccount(
-			&account,
+			ctx,
+			account,
 			time.Now().Add(time.Hour*1),
+			"",
 		)
 		require.NoError(t, err)
 
 		acc, err := domain.CheckToken(ctx, token)
 		require.NoError(t, err)
 		require.NotNil(t, acc)
-		require.Equal(t, model.DBID(99), acc.ID)
+		require.Equal(t, account.ID, acc.ID)
 	})
 
 	t.Run("expired token", func(t *testing.T) {
 		// Create an expired token
-		account := testutil.GetValidAccount().ToDTO()
 		token, err := domain.CreateTokenForAccount(
-			&account,
+			ctx,
+			account,
 			time.Now().Add(time.Hour*-1),
+			"",
 		)
 		require.NoError(t, err)
 
@@ -55,11 +64,122 @@ func TestAuthDomainCheckToken(t *testing.T) {
 	})
 
 	t.Run("nil account", func(t *testing.T) {
-		token, err := domain.CreateTokenForAccount(nil, time.Now().Add(time.Hour))
+		token, err := domain.CreateTokenForAccount(ctx, nil, time.Now().Add(time.Hour), "")
 		require.Error(t, err)
 		require.Empty(t, token)
 		require.Contains(t, err.Error(), "account is nil")
 	})
+
+	t.Run("token without session", func(t *testing.T) {
+		// Tokens signed with the right key but not started through a session are rejected
+		claims := jwt.MapClaims{
+			"account": account,
+			"exp":     time.Now().Add(time.Hour).UTC().Unix(),
+		}
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(deps.Config().Http.SecretKey)
+		require.NoError(t, err)
+
+		acc, err := domain.CheckToken(ctx, token)
+		require.Error(t, err)
+		require.Nil(t, acc)
+	})
+
+	t.Run("deleted account", func(t *testing.T) {
+		deleted, err := deps.Domains().Accounts().CreateAccount(ctx, model.AccountDTO{
+			Username: "deleted",
+			Password: "deleted",
+		})
+		require.NoError(t, err)
+
+		token, err := domain.CreateTokenForAccount(ctx, deleted, time.Now().Add(time.Hour), "")
+		require.NoError(t, err)
+
+		require.NoError(t, deps.Domains().Accounts().DeleteAccount(ctx, int(deleted.ID)))
+
+		acc, err := domain.CheckToken(ctx, token)
+		require.Error(t, err)
+		require.Nil(t, acc)
+	})
+}
+
+func TestAuthDomainSessions(t *testing.T) {
+	ctx := context.TODO()
+	logger := logrus.New()
+	_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+	domain := domains.NewAuthDomain(deps)
+
+	user, err := deps.Domains().Accounts().CreateAccount(ctx, model.AccountDTO{
+		Username: "user",
+		Password: "user",
+	})
+	require.NoError(t, err)
+
+	other, err := deps.Domains().Accounts().CreateAccount(ctx, model.AccountDTO{
+		Username: "other",
+		Password: "other",
+	})
+	require.NoError(t, err)
+
+	t.Run("account is read from the database", func(t *testing.T) {
+		token, err := domain.CreateTokenForAccount(ctx, user, time.Now().Add(time.Hour), "Firefox")
+		require.NoError(t, err)
+
+		_, err = deps.Domains().Accounts().UpdateAccount(ctx, model.AccountDTO{ID: user.ID, Owner: model.Ptr(true)})
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_, err := deps.Domains().Accounts().UpdateAccount(ctx, model.AccountDTO{ID: user.ID, Owner: model.Ptr(false)})
+			require.NoError(t, err)
+		})
+
+		account, session, err := domain.CheckSession(ctx, token)
+		require.NoError(t, err)
+		require.True(t, account.IsOwner())
+		require.Equal(t, "Firefox", session.UserAgent)
+		require.NotNil(t, session.LastUsedAt)
+	})
+
+	t.Run("list and revoke", func(t *testing.T) {
+		token, err := domain.CreateTokenForAccount(ctx, user, time.Now().Add(time.Hour), "")
+		require.NoError(t, err)
+
+		_, session, err := domain.CheckSession(ctx, token)
+		require.NoError(t, err)
+
+		sessions, err := domain.ListSessions(ctx, user.ID)
+		require.NoError(t, err)
+		require.Contains(t, sessions, *session)
+
+		// Only the owner of the session can revoke it
+		require.ErrorIs(t, domain.RevokeSession(ctx, other.ID, session.ID), model.ErrNotFound)
+		require.NoError(t, domain.RevokeSession(ctx, user.ID, session.ID))
+
+		_, err = domain.CheckToken(ctx, token)
+		require.Error(t, err)
+	})
+
+	t.Run("expired sessions are not listed", func(t *testing.T) {
+		_, err := domain.CreateTokenForAccount(ctx, other, time.Now().Add(-time.Hour), "")
+		require.NoError(t, err)
+
+		sessions, err := domain.ListSessions(ctx, other.ID)
+		require.NoError(t, err)
+		require.Empty(t, sessions)
+	})
+
+	t.Run("password change ends the sessions", func(t *testing.T) {
+		token, err := domain.CreateTokenForAccount(ctx, user, time.Now().Add(time.Hour), "")
+		require.NoError(t, err)
+
+		_, err = deps.Domains().Accounts().UpdateAccount(ctx, model.AccountDTO{ID: user.ID, Password: "changed"})
+		require.NoError(t, err)
+
+		_, err = domain.CheckToken(ctx, token)
+		require.Error(t, err)
+
+		sessions, err := domain.ListSessions(ctx, user.ID)
+		require.NoError(t, err)
+		require.Empty(t, sessions)
+	})
 }
 
 func TestAuthDomainCheckTokenInvalidMethod(t *testing.T) {
@@ -116,3 +236,148 @@ func TestAuthDomainGetAccountFromCredentials(t *testing.T) {
 		require.Nil(t, acc)
 	})
 }
+
+func TestAuthDomainAPITokens(t *testing.T) {
+	ctx := context.TODO()
+	logger := logrus.New()
+	_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+	domain := domains.NewAuthDomain(deps)
+
+	owner, err := deps.Domains().Accounts().CreateAccount(ctx, model.AccountDTO{
+		Username: "owner",
+		Password: "owner",
+		Owner:    model.Ptr(true),
+	})
+	require.NoError(t, err)
+
+	user, err := deps.Domains().Accounts().CreateAccount(ctx, model.AccountDTO{
+		Username: "user",
+		Password: "user",
+	})
+	require.NoError(t, err)
+
+	read := model.APITokenScopes{model.APITokenScopeRead}
+
+	t.Run("invalid tokens are rejected", func(t *testing.T) {
+		cases := map[string]struct {
+			account   *model.AccountDTO
+			name      string
+			scopes    model.APITokenScopes
+			expiresAt *time.Time
+		}{
+			"empty name":        {account: user, name: " ", scopes: read},
+			"no scopes":         {account: user, name: "script"},
+			"unknown scope":     {account: user, name: "script", scopes: model.APITokenScopes{"delete"}},
+			"admin for users":   {account: user, name: "script", scopes: model.APITokenScopes{model.APITokenScopeAdmin}},
+			"expired on create": {account: user, name: "script", scopes: read, expiresAt: model.Ptr(time.Now().Add(-time.Hour))},
+		}
+
+		for name, tc := range cases {
+			t.Run(name, func(t *testing.T) {
+				_, _, err := domain.CreateAPIToken(ctx, tc.account, tc.name, tc.scopes, tc.expiresAt)
+				require.ErrorAs(t, err, &model.ValidationError{})
+			})
+		}
+	})
+
+	t.Run("create, check and revoke", func(t *testing.T) {
+		token, value, err := domain.CreateAPIToken(ctx, owner, "script", model.APITokenScopes{model.APITokenScopeAdmin}, nil)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(value, model.APITokenPrefix))
+		require.NotEqual(t, value, token.TokenHash)
+		require.Nil(t, token.ExpiresAt)
+
+		account, checked, err := domain.CheckAPIToken(ctx, value)
+		require.NoError(t, err)
+		require.Equal(t, owner.ID, account.ID)
+		require.True(t, account.IsOwner())
+		require.Equal(t, token.ID, checked.ID)
+		require.NotNil(t, checked.LastUsedAt)
+
+		tokens, err := domain.ListAPITokens(ctx, owner.ID)
+		require.NoError(t, err)
+		require.Len(t, tokens, 1)
+		require.NotNil(t, tokens[0].LastUsedAt)
+
+		// Only the owner of the token can revoke it
+		require.ErrorIs(t, domain.RevokeAPIToken(ctx, user.ID, token.ID), model.ErrNotFound)
+		require.NoError(t, domain.RevokeAPIToken(ctx, owner.ID, token.ID))
+
+		_, _, err = domain.CheckAPIToken(ctx, value)
+		require.Error(t, err)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		token, value, err := domain.CreateAPIToken(ctx, user, "script", read, model.Ptr(time.Now().Add(time.Hour)))
+		require.NoError(t, err)
+
+		_, _, err = domain.CheckAPIToken(ctx, value)
+		require.NoError(t, err)
+
+		// Expire the token through the database, the domain refuses past dates
+		expired := time.Now().UTC().Add(-time.Hour).Format(model.DatabaseDateFormat)
+		_, err = deps.Database().WriterDB().ExecContext(ctx, "UPDATE api_token SET expires_at = ? WHERE id = ?", expired, token.ID)
+		require.NoError(t, err)
+
+		_, _, err = domain.CheckAPIToken(ctx, value)
+		require.Error(t, err)
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		_, _, err := domain.CheckAPIToken(ctx, model.APITokenPrefix+"unknown")
+		require.Error(t, err)
+
+		_, _, err = domain.CheckAPIToken(ctx, "not-a-token")
+		require.Error(t, err)
+	})
+}
+
+func TestAuthDomainFeedTokens(t *testing.T) {
+	ctx := context.TODO()
+	logger := logrus.New()
+	_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+	domain := domains.NewAuthDomain(deps)
+
+	account, err := deps.Domains().Accounts().CreateAccount(ctx, model.AccountDTO{
+		Username: "reader",
+		Password: "reader",
+	})
+	require.NoError(t, err)
+
+	_, err = domain.GetFeedToken(ctx, account.ID)
+	require.ErrorIs(t, err, model.ErrNotFound)
+
+	first, err := domain.CreateFeedToken(ctx, account.ID)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(first, model.FeedTokenPrefix))
+
+	checked, err := domain.CheckFeedToken(ctx, first)
+	require.NoError(t, err)
+	require.Equal(t, account.ID, checked.ID)
+
+	_, err = domain.GetFeedToken(ctx, account.ID)
+	require.NoError(t, err)
+
+	t.Run("replaced token", func(t *testing.T) {
+		second, err := domain.CreateFeedToken(ctx, account.ID)
+		require.NoError(t, err)
+
+		_, err = domain.CheckFeedToken(ctx, first)
+		require.Error(t, err)
+
+		_, err = domain.CheckFeedToken(ctx, second)
+		require.NoError(t, err)
+
+		require.NoError(t, domain.RevokeFeedToken(ctx, account.ID))
+		_, err = domain.CheckFeedToken(ctx, second)
+		require.Error(t, err)
+	})
+
+	t.Run("feed tokens aren't api tokens", func(t *testing.T) {
+		value, err := domain.CreateFeedToken(ctx, account.ID)
+		require.NoError(t, err)
+
+		_, _, err = domain.CheckAPIToken(ctx, value)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,1275 @@
+package domains
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/core"
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/spf13/afero"
+)
+
+// Entries of a backup archive, written and restored in this order. Storage files follow
+// the data, under the files directory, and the manifest is the last entry.
+//
+// Credentials are left out of backups: API tokens, sessions, feed tokens and second factors
+// are created again on the restored instance. The text indexed from the archives is
+// extracted again from their files, and job, link check and webhook delivery history isn't kept.
+const (
+	backupAccountsEntry      = "data/accounts.json"
+	backupIdentitiesEntry    = "data/account_identities.json"
+	backupTagsEntry          = "data/tags.json"
+	backupTagAliasesEntry    = "data/tag_aliases.json"
+	backupCollectionsEntry   = "data/collections.json"
+	backupBookmarksEntry     = "data/bookmarks.json"
+	backupSnapshotsEntry     = "data/archive_snapshots.json"
+	backupRulesEntry         = "data/bookmark_rules.json"
+	backupSavedSearchesEntry = "data/saved_searches.json"
+	backupWebhooksEntry      = "data/webhooks.json"
+	backupSubscriptionsEntry = "data/subscriptions.json"
+	backupHighlightsEntry    = "data/highlights.json"
+	backupFilesDir           = "files/"
+)
+
+// backupStorageDirs are the storage directories saved in backups
+var backupStorageDirs = []string{"thumb", "ebook", "archive"}
+
+// backupRestoreBatchSize is the number of bookmarks saved at once while restoring
+const backupRestoreBatchSize = 100
+
+// backupSnapshot is an archive snapshot in a backup, where its path is kept.
+type backupSnapshot struct {
+	ID         model.DBID `json:"id"`
+	BookmarkID int        `json:"bookmark_id"`
+	Path       string     `json:"path"`
+	CreatedAt  string     `json:"created_at"`
+}
+
+// backupSubscription is a feed subscription in a backup, with the items already seen so
+// they aren't saved again.
+type backupSubscription struct {
+	model.Subscription
+	Items []model.SubscriptionItem `json:"items"`
+}
+
+// backupBookmark is a bookmark in a backup, written by the JSON exporter with its content.
+type backupBookmark struct {
+	model.BookmarkDTO
+	Content string `json:"content"`
+}
+
+// BackupDomain saves the data and storage files of the instance into a single archive, and
+// restores them into any database.
+type BackupDomain struct {
+	deps model.Dependencies
+}
+
+// backupWriter writes the entries of a backup archive, recording them in the manifest.
+type backupWriter struct {
+	tw       *tar.Writer
+	manifest *model.BackupManifest
+}
+
+func (bw *backupWriter) writeEntry(name string, size int64, modTime time.Time, r io.Reader) (string, error) {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    size,
+		ModTime: modTime,
+	}
+	if err := bw.tw.WriteHeader(header); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	hash := sha256.New()
+	if _, err := io.CopyN(bw.tw, io.TeeReader(r, hash), size); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// add writes an entry and records its checksum in the manifest.
+func (bw *backupWriter) add(name string, size int64, modTime time.Time, r io.Reader) error {
+	checksum, err := bw.writeEntry(name, size, modTime, r)
+	if err != nil {
+		return err
+	}
+
+	bw.manifest.Files = append(bw.manifest.Files, model.BackupFile{Name: name, Size: size, SHA256: checksum})
+	return nil
+}
+
+func (bw *backupWriter) addJSON(name string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+	return bw.add(name, int64(len(data)), time.Now(), bytes.NewReader(data))
+}
+
+// backupExporter collects the IDs of the bookmarks written to the backup.
+type backupExporter struct {
+	model.BookmarkExporter
+	ids []int
+}
+
+func (e *backupExporter) WriteBookmark(bookmark model.BookmarkDTO) error {
+	e.ids = append(e.ids, bookmark.ID)
+	return e.BookmarkExporter.WriteBookmark(bookmark)
+}
+
+// Backup writes the accounts, tags, collections and bookmarks, with their content, the rules,
+// saved searches, webhooks, subscriptions and highlights of the accounts, and every storage file
+// into a gzipped tar archive, encrypted if a passphrase is provided.
+func (d *BackupDomain) Backup(ctx context.Context, w io.Writer, passphrase string) (*model.BackupManifest, error) {
+	var encrypter io.WriteCloser
+	if passphrase != "" {
+		var err error
+		encrypter, err = core.NewEncryptWriter(w, passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt backup: %w", err)
+		}
+		w = encrypter
+	}
+
+	gz := gzip.NewWriter(w)
+	bw := &backupWriter{
+		tw: tar.NewWriter(gz),
+		manifest: &model.BackupManifest{
+			Version:       model.BackupFormatVersion,
+			ShioriVersion: model.BuildVersion,
+			CreatedAt:     time.Now().UTC().Format(model.DatabaseDateFormat),
+			Database:      d.deps.Database().WriterDB().DriverName(),
+			Files:         []model.BackupFile{},
+		},
+	}
+
+	if err := d.backupData(ctx, bw); err != nil {
+		return nil, err
+	}
+
+	if err := d.backupFiles(bw); err != nil {
+		return nil, err
+	}
+
+	manifest, err := json.MarshalIndent(bw.manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if _, err := bw.writeEntry(model.BackupManifestName, int64(len(manifest)), time.Now(), bytes.NewReader(manifest)); err != nil {
+		return nil, err
+	}
+
+	if err := bw.tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish backup: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish backup: %w", err)
+	}
+	if encrypter != nil {
+		if err := encrypter.Close(); err != nil {
+			return nil, fmt.Errorf("failed to finish backup: %w", err)
+		}
+	}
+
+	return bw.manifest, nil
+}
+
+func (d *BackupDomain) backupData(ctx context.Context, bw *backupWriter) error {
+	db := d.deps.Database()
+
+	accounts, err := db.ListAccounts(ctx, model.DBListAccountsOptions{WithPassword: true})
+	if err != nil {
+		return fmt.Errorf("failed to get accounts: %w", err)
+	}
+	bw.manifest.Accounts = len(accounts)
+	if err := bw.addJSON(backupAccountsEntry, accounts); err != nil {
+		return err
+	}
+
+	// Accounts created by OIDC logins have no usable password, they need their identities
+	identities, err := db.ListAccountIdentities(ctx, 0)
+	if err != nil {
+		return fmt.Errorf("failed to get account identities: %w", err)
+	}
+	if err := bw.addJSON(backupIdentitiesEntry, identities); err != nil {
+		return err
+	}
+
+	tagDTOs, err := db.GetTags(ctx, model.DBListTagsOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get tags: %w", err)
+	}
+	tags := make([]model.Tag, 0, len(tagDTOs))
+	for _, tag := range tagDTOs {
+		tags = append(tags, tag.ToTag())
+	}
+	bw.manifest.Tags = len(tags)
+	if err := bw.addJSON(backupTagsEntry, tags); err != nil {
+		return err
+	}
+
+	aliases, err := db.GetTagAliases(ctx, 0)
+	if err != nil {
+		return fmt.Errorf("failed to get tag aliases: %w", err)
+	}
+	if err := bw.addJSON(backupTagAliasesEntry, aliases); err != nil {
+		return err
+	}
+
+	collections, err := db.ListCollections(ctx, 0)
+	if err != nil {
+		return fmt.Errorf("failed to get collections: %w", err)
+	}
+	bw.manifest.Collections = len(collections)
+	if err := bw.addJSON(backupCollectionsEntry, collections); err != nil {
+		return err
+	}
+
+	// Bookmarks go through a temporary file, their size must be known before writing them
+	tmpFile, err := os.CreateTemp("", "shiori-backup-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	defer tmpFile.Close()
+
+	jsonExporter, err := core.NewBookmarkExporter(tmpFile, model.ExportFormatJSON)
+	if err != nil {
+		return fmt.Errorf("failed to export bookmarks: %w", err)
+	}
+	exporter := &backupExporter{BookmarkExporter: jsonExporter}
+	if err := d.deps.Domains().Bookmarks().ExportBookmarks(ctx, exporter, model.ListBookmarksOptions{}); err != nil {
+		return err
+	}
+
+	size, err := tmpFile.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("failed to read temporary file: %w", err)
+	}
+	if _, err := tmpFile.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read temporary file: %w", err)
+	}
+
+	bw.manifest.Bookmarks = len(exporter.ids)
+	if err := bw.add(backupBookmarksEntry, size, time.Now(), tmpFile); err != nil {
+		return err
+	}
+
+	snapshots := []backupSnapshot{}
+	for _, id := range exporter.ids {
+		bookmarkSnapshots, err := db.GetArchiveSnapshots(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		for _, snapshot := range bookmarkSnapshots {
+			snapshots = append(snapshots, backupSnapshot{
+				ID:         snapshot.ID,
+				BookmarkID: snapshot.BookmarkID,
+				Path:       filepath.ToSlash(snapshot.Path),
+				CreatedAt:  snapshot.CreatedAt,
+			})
+		}
+	}
+
+	if err := bw.addJSON(backupSnapshotsEntry, snapshots); err != nil {
+		return err
+	}
+
+	return d.backupAccountsData(ctx, bw)
+}
+
+// backupAccountsData writes the records of the accounts that refer to their bookmarks and
+// collections, restored once those are.
+func (d *BackupDomain) backupAccountsData(ctx context.Context, bw *backupWriter) error {
+	db := d.deps.Database()
+
+	rules, err := db.ListBookmarkRules(ctx, 0)
+	if err != nil {
+		return fmt.Errorf("failed to get bookmark rules: %w", err)
+	}
+	if err := bw.addJSON(backupRulesEntry, rules); err != nil {
+		return err
+	}
+
+	searches, err := db.ListSavedSearches(ctx, 0)
+	if err != nil {
+		return fmt.Errorf("failed to get saved searches: %w", err)
+	}
+	if err := bw.addJSON(backupSavedSearchesEntry, searches); err != nil {
+		return err
+	}
+
+	webhooks, err := db.ListWebhooks(ctx, model.DBListWebhooksOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get webhooks: %w", err)
+	}
+	if err := bw.addJSON(backupWebhooksEntry, webhooks); err != nil {
+		return err
+	}
+
+	subscriptions, err := db.ListSubscriptions(ctx, 0)
+	if err != nil {
+		return fmt.Errorf("failed to get subscriptions: %w", err)
+	}
+	backupSubscriptions := make([]backupSubscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		items, err := db.GetSubscriptionItems(ctx, subscription.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get subscription items: %w", err)
+		}
+		backupSubscriptions = append(backupSubscriptions, backupSubscription{Subscription: subscription, Items: items})
+	}
+	if err := bw.addJSON(backupSubscriptionsEntry, backupSubscriptions); err != nil {
+		return err
+	}
+
+	highlights, err := db.ListHighlights(ctx, model.ListHighlightsOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get highlights: %w", err)
+	}
+	return bw.addJSON(backupHighlightsEntry, highlights)
+}
+
+func (d *BackupDomain) backupFiles(bw *backupWriter) error {
+	storage := d.deps.Domains().Storage()
+
+	for _, dir := range backupStorageDirs {
+		if !storage.DirExists(dir) {
+			continue
+		}
+
+		err := afero.Walk(storage.FS(), dir, func(path string, info fs.FileInfo, err error) error {
+			if err != nil || !info.Mode().IsRegular() {
+				return err
+			}
+
+			file, err := storage.FS().Open(path)
+			if err != nil {
+				return fmt.Errorf("failed to open %s: %w", path, err)
+			}
+			defer file.Close()
+
+			bw.manifest.StorageFiles++
+			return bw.add(backupFilesDir+filepath.ToSlash(path), info.Size(), info.ModTime(), file)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// openBackup returns the reader of the entries of a backup archive.
+func openBackup(r io.Reader, passphrase string) (*tar.Reader, error) {
+	decrypted, err := core.NewDecryptReader(r, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	gz, err := gzip.NewReader(decrypted)
+	if err != nil {
+		return nil, fmt.Errorf("not a backup archive: %w", err)
+	}
+
+	return tar.NewReader(gz), nil
+}
+
+// Verify reads a whole backup archive and checks every entry against the checksums of
+// the manifest.
+func (d *BackupDomain) Verify(ctx context.Context, r io.Reader, passphrase string) (*model.BackupManifest, error) {
+	tr, err := openBackup(r, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest *model.BackupManifest
+	entries := map[string]model.BackupFile{}
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read backup: %w", err)
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		if header.Name == model.BackupManifestName {
+			manifest = &model.BackupManifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, fmt.Errorf("failed to read manifest: %w", err)
+			}
+			continue
+		}
+
+		hash := sha256.New()
+		size, err := io.Copy(hash, tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+		entries[header.Name] = model.BackupFile{Name: header.Name, Size: size, SHA256: hex.EncodeToString(hash.Sum(nil))}
+	}
+
+	if manifest == nil {
+		return nil, fmt.Errorf("backup has no manifest")
+	}
+
+	if manifest.Version != model.BackupFormatVersion {
+		return nil, fmt.Errorf("unsupported backup version %d", manifest.Version)
+	}
+
+	for _, file := range manifest.Files {
+		entry, exists := entries[file.Name]
+		if !exists {
+			return nil, fmt.Errorf("%s is missing from the backup", file.Name)
+		}
+		if entry != file {
+			return nil, fmt.Errorf("checksum mismatch for %s", file.Name)
+		}
+		delete(entries, file.Name)
+	}
+
+	for name := range entries {
+		return nil, fmt.Errorf("%s is not listed in the backup manifest", name)
+	}
+
+	return manifest, nil
+}
+
+// backupRestore maps the IDs of the backup to the ones of the restored records.
+type backupRestore struct {
+	accounts    map[model.DBID]model.DBID
+	collections map[model.DBID]model.DBID
+	bookmarks   map[int]int
+	snapshots   map[model.DBID]model.DBID
+	// Tags are matched by name, this keeps the names of the backup tag IDs
+	tagNames map[int]string
+	// removeDefaultAccount is set when the default account of a new instance is to be
+	// dropped, once everything else is restored
+	removeDefaultAccount bool
+	// undo reverts the changes made so far, the last one first
+	undo []func(ctx context.Context) error
+}
+
+// onUndo records how to revert a change made by the restore.
+func (s *backupRestore) onUndo(fn func(ctx context.Context) error) {
+	s.undo = append(s.undo, fn)
+}
+
+// rollback reverts every change of the restore, going on when one of them fails.
+func (s *backupRestore) rollback(ctx context.Context) error {
+	var errs []error
+	for i := len(s.undo) - 1; i >= 0; i-- {
+		if err := s.undo[i](ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Restore verifies a backup archive and loads it into the instance, which must not have
+// bookmarks. Records get new IDs, storage files are renamed to match them. Accounts that
+// already exist are overwritten by the ones in the backup. When the restore fails, the
+// records and files restored so far are removed and the overwritten accounts are put back.
+func (d *BackupDomain) Restore(ctx context.Context, r io.ReadSeeker, passphrase string) (*model.BackupManifest, error) {
+	manifest, err := d.Verify(ctx, r, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := d.deps.Database().GetBookmarksCount(ctx, model.DBGetBookmarksOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count bookmarks: %w", err)
+	}
+	if count > 0 {
+		return nil, fmt.Errorf("backups can only be restored into an instance without bookmarks, found %d", count)
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to rewind backup: %w", err)
+	}
+
+	tr, err := openBackup(r, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &backupRestore{
+		accounts:    map[model.DBID]model.DBID{0: 0},
+		collections: map[model.DBID]model.DBID{},
+		bookmarks:   map[int]int{},
+		snapshots:   map[model.DBID]model.DBID{},
+		tagNames:    map[int]string{},
+	}
+
+	if err := d.restore(ctx, tr, state); err != nil {
+		if undoErr := state.rollback(ctx); undoErr != nil {
+			return nil, fmt.Errorf("%w, failed to undo the partial restore: %w", err, undoErr)
+		}
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// restore loads the entries of a backup archive, recording how to undo each change.
+func (d *BackupDomain) restore(ctx context.Context, tr *tar.Reader, state *backupRestore) error {
+	if err := d.undoNewTags(ctx, state); err != nil {
+		return err
+	}
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read backup: %w", err)
+		}
+
+		switch {
+		case header.Name == backupAccountsEntry:
+			err = d.restoreAccounts(ctx, tr, state)
+		case header.Name == backupIdentitiesEntry:
+			err = d.restoreIdentities(ctx, tr, state)
+		case header.Name == backupTagsEntry:
+			err = d.restoreTags(ctx, tr, state)
+		case header.Name == backupTagAliasesEntry:
+			err = d.restoreTagAliases(ctx, tr, state)
+		case header.Name == backupCollectionsEntry:
+			err = d.restoreCollections(ctx, tr, state)
+		case header.Name == backupBookmarksEntry:
+			err = d.restoreBookmarks(ctx, tr, state)
+		case header.Name == backupSnapshotsEntry:
+			err = d.restoreSnapshots(ctx, tr, state)
+		case header.Name == backupRulesEntry:
+			err = d.restoreRules(ctx, tr, state)
+		case header.Name == backupSavedSearchesEntry:
+			err = d.restoreSavedSearches(ctx, tr, state)
+		case header.Name == backupWebhooksEntry:
+			err = d.restoreWebhooks(ctx, tr, state)
+		case header.Name == backupSubscriptionsEntry:
+			err = d.restoreSubscriptions(ctx, tr, state)
+		case header.Name == backupHighlightsEntry:
+			err = d.restoreHighlights(ctx, tr, state)
+		case strings.HasPrefix(header.Name, backupFilesDir):
+			err = d.restoreFile(strings.TrimPrefix(header.Name, backupFilesDir), tr, state)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// Backups don't hold the text indexed from the archives, it is extracted from their files
+	restored := make([]int, 0, len(state.bookmarks))
+	for _, id := range state.bookmarks {
+		restored = append(restored, id)
+	}
+	if len(restored) > 0 {
+		if err := d.deps.Domains().Archiver().IndexArchives(ctx, restored); err != nil {
+			return err
+		}
+	}
+
+	// New instances get a default account, it is dropped unless the backup has it or its
+	// password was changed.
+	if state.removeDefaultAccount {
+		if account, err := d.deps.Domains().Auth().GetAccountFromCredentials(ctx, "shiori", "gopher"); err == nil {
+			if err := d.deps.Database().DeleteAccount(ctx, account.ID); err != nil {
+				return fmt.Errorf("failed to remove default account: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// undoNewTags records the removal of the tags created by the restore, with the restored
+// tags or the bookmarks. It is undone last, once the bookmarks are removed.
+func (d *BackupDomain) undoNewTags(ctx context.Context, state *backupRestore) error {
+	existing, err := d.deps.Database().GetTags(ctx, model.DBListTagsOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	existingIDs := make(map[int]struct{}, len(existing))
+	for _, tag := range existing {
+		existingIDs[tag.ID] = struct{}{}
+	}
+
+	state.onUndo(func(ctx context.Context) error {
+		tags, err := d.deps.Database().GetTags(ctx, model.DBListTagsOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get tags: %w", err)
+		}
+		for _, tag := range tags {
+			if _, exists := existingIDs[tag.ID]; exists {
+				continue
+			}
+			if err := d.deps.Database().DeleteTag(ctx, tag.ID); err != nil {
+				return fmt.Errorf("failed to remove tag %s: %w", tag.Name, err)
+			}
+		}
+		return nil
+	})
+
+	return nil
+}
+
+func (d *BackupDomain) restoreAccounts(ctx context.Context, r io.Reader, state *backupRestore) error {
+	var accounts []model.Account
+	if err := json.NewDecoder(r).Decode(&accounts); err != nil {
+		return fmt.Errorf("failed to read accounts: %w", err)
+	}
+
+	existing, err := d.deps.Database().ListAccounts(ctx, model.DBListAccountsOptions{WithPassword: true})
+	if err != nil {
+		return fmt.Errorf("failed to get accounts: %w", err)
+	}
+
+	existingAccounts := map[string]model.Account{}
+	for _, account := range existing {
+		existingAccounts[account.Username] = account
+	}
+
+	for _, account := range accounts {
+		backupID := account.ID
+
+		if previous, exists := existingAccounts[account.Username]; exists {
+			account.ID = previous.ID
+			if err := d.deps.Database().UpdateAccount(ctx, account); err != nil {
+				return fmt.Errorf("failed to restore account %s: %w", account.Username, err)
+			}
+			state.onUndo(func(ctx context.Context) error {
+				return d.deps.Database().UpdateAccount(ctx, previous)
+			})
+		} else {
+			account.ID = 0
+			created, err := d.deps.Database().CreateAccount(ctx, account)
+			if err != nil {
+				return fmt.Errorf("failed to restore account %s: %w", account.Username, err)
+			}
+			account.ID = created.ID
+			state.onUndo(func(ctx context.Context) error {
+				return d.deps.Database().DeleteAccount(ctx, created.ID)
+			})
+		}
+
+		state.accounts[backupID] = account.ID
+	}
+
+	restored := slices.ContainsFunc(accounts, func(account model.Account) bool { return account.Username == "shiori" })
+	state.removeDefaultAccount = len(accounts) > 0 && !restored
+
+	return nil
+}
+
+func (d *BackupDomain) restoreIdentities(ctx context.Context, r io.Reader, state *backupRestore) error {
+	var identities []model.AccountIdentity
+	if err := json.NewDecoder(r).Decode(&identities); err != nil {
+		return fmt.Errorf("failed to read account identities: %w", err)
+	}
+
+	for _, identity := range identities {
+		accountID, exists := state.accounts[identity.AccountID]
+		if !exists {
+			continue
+		}
+
+		// An identity already linked here keeps its account
+		_, linked, err := d.deps.Database().GetAccountIdentity(ctx, identity.Issuer, identity.Subject)
+		if err != nil {
+			return fmt.Errorf("failed to get account identity: %w", err)
+		}
+		if linked {
+			continue
+		}
+
+		identity.ID = 0
+		identity.AccountID = accountID
+		created, err := d.deps.Database().CreateAccountIdentity(ctx, identity)
+		if err != nil {
+			return fmt.Errorf("failed to restore account identity %s: %w", identity.Subject, err)
+		}
+		state.onUndo(func(ctx context.Context) error {
+			return d.deps.Database().DeleteAccountIdentity(ctx, created.AccountID, created.ID)
+		})
+	}
+
+	return nil
+}
+
+func (d *BackupDomain) restoreTags(ctx context.Context, r io.Reader, state *backupRestore) error {
+	var tags []model.Tag
+	if err := json.NewDecoder(r).Decode(&tags); err != nil {
+		return fmt.Errorf("failed to read tags: %w", err)
+	}
+
+	for _, tag := range tags {
+		state.tagNames[tag.ID] = tag.Name
+	}
+
+	existing, err := d.deps.Database().GetTags(ctx, model.DBListTagsOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	names := map[string]struct{}{}
+	for _, tag := range existing {
+		names[tag.Name] = struct{}{}
+	}
+
+	// Tags of bookmarks are restored with them, this keeps the unused ones
+	missing := []model.Tag{}
+	for _, tag := range tags {
+		if _, exists := names[tag.Name]; !exists {
+			missing = append(missing, model.Tag{Name: tag.Name})
+		}
+	}
+
+	if len(missing) > 0 {
+		if _, err := d.deps.Database().CreateTags(ctx, missing...); err != nil {
+			return fmt.Errorf("failed to restore tags: %w", err)
+		}
+	}
+
+	return d.restoreTagParents(ctx, tags, state)
+}
+
+// restoreTagParents gives the restored tags the parent they had in the backup, keeping the
+// parents already set in the instance.
+func (d *BackupDomain) restoreTagParents(ctx context.Context, tags []model.Tag, state *backupRestore) error {
+	existing, err := d.deps.Database().GetTags(ctx, model.DBListTagsOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	byName := make(map[string]model.Tag, len(existing))
+	for _, tag := range existing {
+		byName[tag.Name] = tag.ToTag()
+	}
+
+	for _, tag := range tags {
+		if tag.ParentID == nil {
+			continue
+		}
+
+		restored, exists := byName[tag.Name]
+		parent, parentExists := byName[state.tagNames[*tag.ParentID]]
+		if !exists || !parentExists || restored.ParentID != nil || parent.ID == restored.ID {
+			continue
+		}
+
+		previous := restored
+		restored.ParentID = &parent.ID
+		if err := d.deps.Database().UpdateTag(ctx, restored); err != nil {
+			return fmt.Errorf("failed to restore parent of tag %s: %w", tag.Name, err)
+		}
+		state.onUndo(func(ctx context.Context) error {
+			return d.deps.Database().UpdateTag(ctx, previous)
+		})
+	}
+
+	return nil
+}
+
+func (d *BackupDomain) restoreTagAliases(ctx context.Context, r io.Reader, state *backupRestore) error {
+	var aliases []model.TagAlias
+	if err := json.NewDecoder(r).Decode(&aliases); err != nil {
+		return fmt.Errorf("failed to read tag aliases: %w", err)
+	}
+
+	for _, alias := range aliases {
+		// Names already used by a tag or an alias of the instance win
+		if _, exists, err := d.deps.Database().GetTagByName(ctx, alias.Name); err != nil {
+			return fmt.Errorf("failed to get tag: %w", err)
+		} else if exists {
+			continue
+		}
+
+		tag, exists, err := d.deps.Database().GetTagByName(ctx, state.tagNames[alias.TagID])
+		if err != nil {
+			return fmt.Errorf("failed to get tag: %w", err)
+		}
+		if !exists {
+			continue
+		}
+
+		created, err := d.deps.Database().CreateTagAlias(ctx, model.TagAlias{Name: alias.Name, TagID: tag.ID})
+		if err != nil {
+			return fmt.Errorf("failed to restore tag alias %s: %w", alias.Name, err)
+		}
+		state.onUndo(func(ctx context.Context) error {
+			return d.deps.Database().DeleteTagAlias(ctx, created.ID)
+		})
+	}
+
+	return nil
+}
+
+func (d *BackupDomain) restoreCollections(ctx context.Context, r io.Reader, state *backupRestore) error {
+	var collections []model.Collection
+	if err := json.NewDecoder(r).Decode(&collections); err != nil {
+		return fmt.Errorf("failed to read collections: %w", err)
+	}
+
+	// Parents are created before their children, each pass restores the collections whose
+	// parent is known. The ones left with a missing parent are restored at the top level.
+	for len(collections) > 0 {
+		pending := []model.Collection{}
+		for _, collection := range collections {
+			if collection.ParentID != nil {
+				if _, exists := state.collections[*collection.ParentID]; !exists {
+					pending = append(pending, collection)
+					continue
+				}
+			}
+
+			if err := d.restoreCollection(ctx, collection, state); err != nil {
+				return err
+			}
+		}
+
+		if len(pending) == len(collections) {
+			for _, collection := range pending {
+				collection.ParentID = nil
+				if err := d.restoreCollection(ctx, collection, state); err != nil {
+					return err
+				}
+			}
+			break
+		}
+		collections = pending
+	}
+
+	return nil
+}
+
+func (d *BackupDomain) restoreCollection(ctx context.Context, collection model.Collection, state *backupRestore) error {
+	backupID := collection.ID
+
+	collection.ID = 0
+	collection.AccountID = state.accounts[collection.AccountID]
+	if collection.ParentID != nil {
+		collection.ParentID = model.Ptr(state.collections[*collection.ParentID])
+	}
+
+	created, err := d.deps.Database().CreateCollection(ctx, collection)
+	if err != nil {
+		return fmt.Errorf("failed to restore collection %s: %w", collection.Name, err)
+	}
+
+	state.collections[backupID] = created.ID
+	state.onUndo(func(ctx context.Context) error {
+		return d.deps.Database().DeleteCollection(ctx, created.ID)
+	})
+	return nil
+}
+
+func (d *BackupDomain) restoreBookmarks(ctx context.Context, r io.Reader, state *backupRestore) error {
+	decoder := json.NewDecoder(r)
+	if _, err := decoder.Token(); err != nil {
+		return fmt.Errorf("failed to read bookmarks: %w", err)
+	}
+
+	batch := []model.BookmarkDTO{}
+	batchIDs := []int{}
+
+	save := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		saved, err := d.deps.Database().SaveBookmarks(ctx, true, batch...)
+		if err != nil {
+			return fmt.Errorf("failed to restore bookmarks: %w", err)
+		}
+
+		ids := make([]int, 0, len(saved))
+		for i, bookmark := range saved {
+			state.bookmarks[batchIDs[i]] = bookmark.ID
+			ids = append(ids, bookmark.ID)
+		}
+		state.onUndo(func(ctx context.Context) error {
+			return d.deps.Database().DeleteBookmarks(ctx, 0, ids...)
+		})
+
+		batch = batch[:0]
+		batchIDs = batchIDs[:0]
+		return nil
+	}
+
+	for decoder.More() {
+		var item backupBookmark
+		if err := decoder.Decode(&item); err != nil {
+			return fmt.Errorf("failed to read bookmarks: %w", err)
+		}
+
+		bookmark := item.BookmarkDTO
+		bookmark.ID = 0
+		bookmark.Content = item.Content
+		bookmark.AccountID = state.accounts[bookmark.AccountID]
+
+		// Bookmarks of unknown collections are restored in none
+		if bookmark.CollectionID != nil {
+			if id, exists := state.collections[*bookmark.CollectionID]; exists {
+				bookmark.CollectionID = &id
+			} else {
+				bookmark.CollectionID = nil
+			}
+		}
+
+		// Tags are matched by name, their IDs are those of the backup
+		tags := make([]model.TagDTO, 0, len(bookmark.Tags))
+		for _, tag := range bookmark.Tags {
+			tags = append(tags, model.TagDTO{Tag: model.Tag{Name: tag.Name}})
+		}
+		bookmark.Tags = tags
+
+		batch = append(batch, bookmark)
+		batchIDs = append(batchIDs, item.ID)
+
+		if len(batch) == backupRestoreBatchSize {
+			if err := save(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return save()
+}
+
+func (d *BackupDomain) restoreSnapshots(ctx context.Context, r io.Reader, state *backupRestore) error {
+	var snapshots []backupSnapshot
+	if err := json.NewDecoder(r).Decode(&snapshots); err != nil {
+		return fmt.Errorf("failed to read archive snapshots: %w", err)
+	}
+
+	// Snapshots are created oldest first so their IDs keep the same order
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		if snapshots[i].CreatedAt != snapshots[j].CreatedAt {
+			return snapshots[i].CreatedAt < snapshots[j].CreatedAt
+		}
+		return snapshots[i].ID < snapshots[j].ID
+	})
+
+	for _, snapshot := range snapshots {
+		bookmarkID, exists := state.bookmarks[snapshot.BookmarkID]
+		if !exists {
+			continue
+		}
+
+		backupBook := &model.BookmarkDTO{ID: snapshot.BookmarkID}
+		book := &model.BookmarkDTO{ID: bookmarkID}
+		current := filepath.FromSlash(snapshot.Path) == model.GetArchivePath(backupBook)
+
+		created, err := d.deps.Database().CreateArchiveSnapshot(ctx, model.ArchiveSnapshot{
+			BookmarkID: bookmarkID,
+			Path:       model.GetArchivePath(book),
+			CreatedAt:  snapshot.CreatedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to restore archive snapshot: %w", err)
+		}
+
+		// Previous archives are named after the snapshot ID, which is only known now
+		if !current {
+			created.Path = model.GetArchiveSnapshotPath(book, created.ID)
+			if err := d.deps.Database().UpdateArchiveSnapshot(ctx, *created); err != nil {
+				return fmt.Errorf("failed to restore archive snapshot: %w", err)
+			}
+		}
+
+		state.snapshots[snapshot.ID] = created.ID
+	}
+
+	return nil
+}
+
+func (d *BackupDomain) restoreRules(ctx context.Context, r io.Reader, state *backupRestore) error {
+	var rules []model.BookmarkRule
+	if err := json.NewDecoder(r).Decode(&rules); err != nil {
+		return fmt.Errorf("failed to read bookmark rules: %w", err)
+	}
+
+	for _, rule := range rules {
+		accountID, exists := state.accounts[rule.AccountID]
+		if !exists {
+			continue
+		}
+
+		rule.ID = 0
+		rule.AccountID = accountID
+		created, err := d.deps.Database().CreateBookmarkRule(ctx, rule)
+		if err != nil {
+			return fmt.Errorf("failed to restore bookmark rule %s: %w", rule.Name, err)
+		}
+		state.onUndo(func(ctx context.Context) error {
+			return d.deps.Database().DeleteBookmarkRule(ctx, created.ID)
+		})
+	}
+
+	return nil
+}
+
+func (d *BackupDomain) restoreSavedSearches(ctx context.Context, r io.Reader, state *backupRestore) error {
+	var searches []model.SavedSearch
+	if err := json.NewDecoder(r).Decode(&searches); err != nil {
+		return fmt.Errorf("failed to read saved searches: %w", err)
+	}
+
+	// Names already used by a search of the account win
+	names := map[model.DBID]map[string]struct{}{}
+	for _, search := range searches {
+		accountID, exists := state.accounts[search.AccountID]
+		if !exists {
+			continue
+		}
+
+		if _, listed := names[accountID]; !listed {
+			existing, err := d.deps.Database().ListSavedSearches(ctx, accountID)
+			if err != nil {
+				return fmt.Errorf("failed to get saved searches: %w", err)
+			}
+			names[accountID] = map[string]struct{}{}
+			for _, existingSearch := range existing {
+				names[accountID][strings.ToLower(existingSearch.Name)] = struct{}{}
+			}
+		}
+		if _, used := names[accountID][strings.ToLower(search.Name)]; used {
+			continue
+		}
+
+		// Searches of a collection that isn't restored search in none
+		if search.Filter.CollectionID != nil && *search.Filter.CollectionID != 0 {
+			if id, exists := state.collections[*search.Filter.CollectionID]; exists {
+				search.Filter.CollectionID = &id
+			} else {
+				search.Filter.CollectionID = model.Ptr(model.DBID(0))
+			}
+		}
+
+		search.ID = 0
+		search.AccountID = accountID
+		created, err := d.deps.Database().CreateSavedSearch(ctx, search)
+		if err != nil {
+			return fmt.Errorf("failed to restore saved search %s: %w", search.Name, err)
+		}
+		names[accountID][strings.ToLower(search.Name)] = struct{}{}
+		state.onUndo(func(ctx context.Context) error {
+			return d.deps.Database().DeleteSavedSearch(ctx, created.ID)
+		})
+	}
+
+	return nil
+}
+
+func (d *BackupDomain) restoreWebhooks(ctx context.Context, r io.Reader, state *backupRestore) error {
+	var webhooks []model.Webhook
+	if err := json.NewDecoder(r).Decode(&webhooks); err != nil {
+		return fmt.Errorf("failed to read webhooks: %w", err)
+	}
+
+	for _, webhook := range webhooks {
+		accountID, exists := state.accounts[webhook.AccountID]
+		if !exists {
+			continue
+		}
+
+		webhook.ID = 0
+		webhook.AccountID = accountID
+		created, err := d.deps.Database().CreateWebhook(ctx, webhook)
+		if err != nil {
+			return fmt.Errorf("failed to restore webhook %s: %w", webhook.URL, err)
+		}
+		state.onUndo(func(ctx context.Context) error {
+			return d.deps.Database().DeleteWebhook(ctx, created.ID)
+		})
+	}
+
+	return nil
+}
+
+func (d *BackupDomain) restoreSubscriptions(ctx context.Context, r io.Reader, state *backupRestore) error {
+	var subscriptions []backupSubscription
+	if err := json.NewDecoder(r).Decode(&subscriptions); err != nil {
+		return fmt.Errorf("failed to read subscriptions: %w", err)
+	}
+
+	// Feeds the account already subscribed to are kept as they are
+	urls := map[model.DBID]map[string]struct{}{}
+	for _, item := range subscriptions {
+		subscription := item.Subscription
+		accountID, exists := state.accounts[subscription.AccountID]
+		if !exists {
+			continue
+		}
+
+		if _, listed := urls[accountID]; !listed {
+			existing, err := d.deps.Database().ListSubscriptions(ctx, accountID)
+			if err != nil {
+				return fmt.Errorf("failed to get subscriptions: %w", err)
+			}
+			urls[accountID] = map[string]struct{}{}
+			for _, existingSubscription := range existing {
+				urls[accountID][existingSubscription.URL] = struct{}{}
+			}
+		}
+		if _, used := urls[accountID][subscription.URL]; used {
+			continue
+		}
+
+		subscription.ID = 0
+		subscription.AccountID = accountID
+		created, err := d.deps.Database().CreateSubscription(ctx, subscription)
+		if err != nil {
+			return fmt.Errorf("failed to restore subscription %s: %w", subscription.URL, err)
+		}
+		urls[accountID][subscription.URL] = struct{}{}
+		state.onUndo(func(ctx context.Context) error {
+			return d.deps.Database().DeleteSubscription(ctx, 0, created.ID)
+		})
+
+		// The poll status is kept, so the next poll doesn't look new
+		created.LastError = subscription.LastError
+		created.LastPolledAt = subscription.LastPolledAt
+		if err := d.deps.Database().UpdateSubscription(ctx, *created); err != nil {
+			return fmt.Errorf("failed to restore subscription %s: %w", subscription.URL, err)
+		}
+
+		items := make([]model.SubscriptionItem, 0, len(item.Items))
+		for _, subscriptionItem := range item.Items {
+			subscriptionItem.SubscriptionID = created.ID
+			subscriptionItem.BookmarkID = state.bookmarks[subscriptionItem.BookmarkID]
+			items = append(items, subscriptionItem)
+		}
+		if err := d.deps.Database().CreateSubscriptionItems(ctx, items...); err != nil {
+			return fmt.Errorf("failed to restore items of subscription %s: %w", subscription.URL, err)
+		}
+	}
+
+	return nil
+}
+
+func (d *BackupDomain) restoreHighlights(ctx context.Context, r io.Reader, state *backupRestore) error {
+	var highlights []model.Highlight
+	if err := json.NewDecoder(r).Decode(&highlights); err != nil {
+		return fmt.Errorf("failed to read highlights: %w", err)
+	}
+
+	// Highlights are removed with their bookmarks, they are undone with them
+	for _, highlight := range highlights {
+		bookmarkID, bookmarkExists := state.bookmarks[highlight.BookmarkID]
+		accountID, accountExists := state.accounts[highlight.AccountID]
+		if !bookmarkExists || !accountExists {
+			continue
+		}
+
+		highlight.ID = 0
+		highlight.BookmarkID = bookmarkID
+		highlight.AccountID = accountID
+		if _, err := d.deps.Database().CreateHighlight(ctx, highlight); err != nil {
+			return fmt.Errorf("failed to restore highlight: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// restoreStoragePath returns the path of a storage file of the backup once the IDs in it
+// are replaced by the restored ones. False is returned for files of unknown bookmarks.
+func restoreStoragePath(name string, state *backupRestore) (string, bool) {
+	bookmarkPath := func(id string) (*model.BookmarkDTO, bool) {
+		backupID, err := strconv.Atoi(id)
+		if err != nil {
+			return nil, false
+		}
+		bookmarkID, exists := state.bookmarks[backupID]
+		return &model.BookmarkDTO{ID: bookmarkID}, exists
+	}
+
+	parts := strings.Split(name, "/")
+	switch {
+	case len(parts) == 2 && parts[0] == "thumb":
+		if book, ok := bookmarkPath(parts[1]); ok {
+			return model.GetThumbnailPath(book), true
+		}
+	case len(parts) == 2 && parts[0] == "ebook" && strings.HasSuffix(parts[1], ".epub"):
+		if book, ok := bookmarkPath(strings.TrimSuffix(parts[1], ".epub")); ok {
+			return model.GetEbookPath(book), true
+		}
+	case len(parts) == 2 && parts[0] == "archive":
+		if book, ok := bookmarkPath(parts[1]); ok {
+			return model.GetArchivePath(book), true
+		}
+	case len(parts) == 4 && parts[0] == "archive" && parts[1] == "snapshots":
+		book, ok := bookmarkPath(parts[2])
+		snapshotID, err := strconv.Atoi(parts[3])
+		if !ok || err != nil {
+			return "", false
+		}
+		if id, exists := state.snapshots[model.DBID(snapshotID)]; exists {
+			return model.GetArchiveSnapshotPath(book, id), true
+		}
+	}
+
+	return "", false
+}
+
+func (d *BackupDomain) restoreFile(name string, r io.Reader, state *backupRestore) error {
+	dstPath, ok := restoreStoragePath(name, state)
+	if !ok {
+		d.deps.Logger().WithField("path", name).Warn("skipping file of unknown bookmark in backup")
+		return nil
+	}
+
+	fs := d.deps.Domains().Storage().FS()
+	if err := fs.MkdirAll(filepath.Dir(dstPath), model.DataDirPerm); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", dstPath, err)
+	}
+
+	file, err := fs.Create(dstPath)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dstPath, err)
+	}
+	defer file.Close()
+	state.onUndo(func(ctx context.Context) error {
+		return fs.Remove(dstPath)
+	})
+
+	if _, err := io.Copy(file, r); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", dstPath, err)
+	}
+
+	return nil
+}
+
+func NewBackupDomain(deps model.Dependencies) *BackupDomain {
+	return &BackupDomain{
+		deps: deps,
+	}
+}
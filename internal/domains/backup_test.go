@@ -0,0 +1,395 @@
+package domains_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/core"
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/shiori/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackupDomain(t *testing.T) {
+	ctx := context.Background()
+	logger := logrus.New()
+
+	// setupSource creates an instance with an account, an unused child tag, a tag alias, nested
+	// collections and two bookmarks, the first bookmark is deleted so the restored IDs differ from the ones in
+	// the backup. The child collection is created before its parent. The account has a linked OIDC identity,
+	// a rule, a saved search, a webhook, a subscription and a highlight.
+	setupSource := func(t *testing.T) (model.Dependencies, model.BookmarkDTO) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		account, err := deps.Domains().Accounts().CreateAccount(ctx, model.AccountDTO{
+			Username: "reader",
+			Password: "p4ssw0rd",
+			Owner:    model.Ptr(true),
+		})
+		require.NoError(t, err)
+
+		_, err = deps.Database().CreateAccountIdentity(ctx, model.AccountIdentity{AccountID: account.ID, Issuer: "https://id.example.com", Subject: "reader"})
+		require.NoError(t, err)
+
+		unused, err := deps.Database().CreateTag(ctx, model.Tag{Name: "unused"})
+		require.NoError(t, err)
+
+		child, err := deps.Database().CreateCollection(ctx, model.Collection{AccountID: account.ID, Name: "Go"})
+		require.NoError(t, err)
+		parent, err := deps.Database().CreateCollection(ctx, model.Collection{AccountID: account.ID, Name: "Reading"})
+		require.NoError(t, err)
+		child.ParentID = &parent.ID
+		require.NoError(t, deps.Database().UpdateCollection(ctx, *child))
+
+		deleted := testutil.GetValidBookmark()
+		book := testutil.GetValidBookmark()
+		book.AccountID = account.ID
+		book.CollectionID = &child.ID
+		book.Content = "Shiori content"
+		book.HTML = "<p>Shiori content</p>"
+		book.CreatedAt = "2019-01-01 10:00:00"
+		book.Tags = []model.TagDTO{{Tag: model.Tag{Name: "go"}}}
+		saved, err := deps.Database().SaveBookmarks(ctx, true, *deleted, *book)
+		require.NoError(t, err)
+		require.NoError(t, deps.Database().DeleteBookmarks(ctx, 0, saved[0].ID))
+		bookmark := saved[1]
+
+		goTag, _, err := deps.Database().GetTagByName(ctx, "go")
+		require.NoError(t, err)
+		unused.ParentID = &goTag.ID
+		require.NoError(t, deps.Database().UpdateTag(ctx, unused))
+		_, err = deps.Database().CreateTagAlias(ctx, model.TagAlias{Name: "golang", TagID: goTag.ID})
+		require.NoError(t, err)
+
+		_, err = deps.Database().CreateBookmarkRule(ctx, model.BookmarkRule{
+			AccountID:  account.ID,
+			Name:       "Go blogs",
+			Enabled:    true,
+			Conditions: model.RuleConditions{Host: "go.dev"},
+			Actions:    model.RuleActions{AddTags: []string{"go"}},
+		})
+		require.NoError(t, err)
+		_, err = deps.Database().CreateSavedSearch(ctx, model.SavedSearch{
+			AccountID: account.ID,
+			Name:      "Reading Go",
+			Filter:    model.SearchFilter{CollectionID: &child.ID},
+		})
+		require.NoError(t, err)
+		_, err = deps.Database().CreateWebhook(ctx, model.Webhook{
+			AccountID: account.ID,
+			URL:       "https://hooks.example.com/shiori",
+			Events:    model.WebhookEvents{model.WebhookEventBookmarkCreated},
+			Secret:    "webhook secret",
+		})
+		require.NoError(t, err)
+		subscription, err := deps.Database().CreateSubscription(ctx, model.Subscription{
+			AccountID: account.ID,
+			URL:       "https://go.dev/blog/feed.atom",
+		})
+		require.NoError(t, err)
+		require.NoError(t, deps.Database().CreateSubscriptionItems(ctx, model.SubscriptionItem{
+			SubscriptionID: subscription.ID,
+			GUID:           "first-post",
+			BookmarkID:     bookmark.ID,
+		}))
+		_, err = deps.Database().CreateHighlight(ctx, model.Highlight{
+			BookmarkID: bookmark.ID,
+			AccountID:  account.ID,
+			Selector:   model.HighlightSelector{Quote: model.TextQuoteSelector{Exact: "content"}},
+			Note:       "read later",
+		})
+		require.NoError(t, err)
+
+		storage := deps.Domains().Storage()
+		require.NoError(t, storage.WriteData(model.GetThumbnailPath(&bookmark), []byte("thumbnail")))
+		require.NoError(t, storage.WriteData(model.GetEbookPath(&bookmark), []byte("ebook")))
+
+		archiver := deps.Domains().Archiver()
+		for _, content := range []string{"first archive", "second archive"} {
+			require.NoError(t, archiver.PreserveArchive(ctx, &bookmark))
+			require.NoError(t, storage.WriteData(model.GetArchivePath(&bookmark), []byte(content)))
+			require.NoError(t, archiver.RecordArchive(ctx, &bookmark))
+		}
+
+		return deps, bookmark
+	}
+
+	backup := func(t *testing.T, deps model.Dependencies, passphrase string) []byte {
+		buf := bytes.Buffer{}
+		manifest, err := deps.Domains().Backup().Backup(ctx, &buf, passphrase)
+		require.NoError(t, err)
+		require.Equal(t, 1, manifest.Bookmarks)
+		require.Equal(t, 2, manifest.Collections)
+		require.Equal(t, 4, manifest.StorageFiles)
+		return buf.Bytes()
+	}
+
+	readFile := func(t *testing.T, deps model.Dependencies, path string) string {
+		data, err := afero.ReadFile(deps.Domains().Storage().FS(), path)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	t.Run("backup and restore", func(t *testing.T) {
+		source, original := setupSource(t)
+		data := backup(t, source, "")
+
+		manifest, err := source.Domains().Backup().Verify(ctx, bytes.NewReader(data), "")
+		require.NoError(t, err)
+		require.Equal(t, model.BackupFormatVersion, manifest.Version)
+
+		_, target := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		_, err = target.Domains().Backup().Restore(ctx, bytes.NewReader(data), "")
+		require.NoError(t, err)
+
+		// Accounts keep their password
+		account, err := target.Domains().Auth().GetAccountFromCredentials(ctx, "reader", "p4ssw0rd")
+		require.NoError(t, err)
+
+		identity, exists, err := target.Database().GetAccountIdentity(ctx, "https://id.example.com", "reader")
+		require.NoError(t, err)
+		require.True(t, exists)
+		require.Equal(t, account.ID, identity.AccountID)
+
+		bookmarks, err := target.Database().GetBookmarks(ctx, model.DBGetBookmarksOptions{WithContent: true})
+		require.NoError(t, err)
+		require.Len(t, bookmarks, 1)
+		restored := bookmarks[0]
+		require.NotEqual(t, original.ID, restored.ID)
+		require.Equal(t, original.URL, restored.URL)
+		require.Equal(t, "Shiori content", restored.Content)
+		require.Equal(t, "<p>Shiori content</p>", restored.HTML)
+		require.Equal(t, original.CreatedAt, restored.CreatedAt)
+		require.Len(t, restored.Tags, 1)
+		require.Equal(t, "go", restored.Tags[0].Name)
+
+		tags, err := target.Database().GetTags(ctx, model.DBListTagsOptions{})
+		require.NoError(t, err)
+		require.Len(t, tags, 2)
+
+		// Tags keep their parent and aliases
+		goTag, exists, err := target.Database().GetTagByName(ctx, "golang")
+		require.NoError(t, err)
+		require.True(t, exists)
+		require.Equal(t, restored.Tags[0].ID, goTag.ID)
+		unused, _, err := target.Database().GetTagByName(ctx, "unused")
+		require.NoError(t, err)
+		require.Equal(t, &goTag.ID, unused.ParentID)
+
+		collection, exists, err := target.Database().GetCollection(ctx, *restored.CollectionID)
+		require.NoError(t, err)
+		require.True(t, exists)
+		require.Equal(t, "Go", collection.Name)
+		require.Equal(t, restored.AccountID, collection.AccountID)
+		parent, _, err := target.Database().GetCollection(ctx, *collection.ParentID)
+		require.NoError(t, err)
+		require.Equal(t, "Reading", parent.Name)
+
+		// Files are renamed after the restored bookmark
+		require.Equal(t, "thumbnail", readFile(t, target, model.GetThumbnailPath(&restored)))
+		require.Equal(t, "ebook", readFile(t, target, model.GetEbookPath(&restored)))
+
+		snapshots, err := target.Domains().Archiver().ListSnapshots(ctx, &restored)
+		require.NoError(t, err)
+		require.Len(t, snapshots, 2)
+		require.True(t, snapshots[0].Current)
+		require.Equal(t, "second archive", readFile(t, target, snapshots[0].Path))
+		require.Equal(t, model.GetArchiveSnapshotPath(&restored, snapshots[1].ID), snapshots[1].Path)
+		require.Equal(t, "first archive", readFile(t, target, snapshots[1].Path))
+
+		// Records of the account follow its bookmarks and collections
+		rules, err := target.Database().ListBookmarkRules(ctx, restored.AccountID)
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+		require.Equal(t, "go.dev", rules[0].Conditions.Host)
+
+		searches, err := target.Database().ListSavedSearches(ctx, restored.AccountID)
+		require.NoError(t, err)
+		require.Len(t, searches, 1)
+		require.Equal(t, restored.CollectionID, searches[0].Filter.CollectionID)
+
+		webhooks, err := target.Database().ListWebhooks(ctx, model.DBListWebhooksOptions{AccountIDs: []model.DBID{restored.AccountID}})
+		require.NoError(t, err)
+		require.Len(t, webhooks, 1)
+		require.Equal(t, "webhook secret", webhooks[0].Secret)
+
+		subscriptions, err := target.Database().ListSubscriptions(ctx, restored.AccountID)
+		require.NoError(t, err)
+		require.Len(t, subscriptions, 1)
+		items, err := target.Database().GetSubscriptionItems(ctx, subscriptions[0].ID)
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		require.Equal(t, restored.ID, items[0].BookmarkID)
+
+		highlights, err := target.Database().ListHighlights(ctx, model.ListHighlightsOptions{BookmarkID: restored.ID})
+		require.NoError(t, err)
+		require.Len(t, highlights, 1)
+		require.Equal(t, restored.AccountID, highlights[0].AccountID)
+		require.Equal(t, "read later", highlights[0].Note)
+	})
+
+	t.Run("encrypted backup", func(t *testing.T) {
+		source, _ := setupSource(t)
+		data := backup(t, source, "secret")
+
+		require.NotContains(t, string(data), "Shiori content")
+
+		_, err := source.Domains().Backup().Verify(ctx, bytes.NewReader(data), "")
+		require.ErrorIs(t, err, core.ErrEncrypted)
+
+		_, err = source.Domains().Backup().Verify(ctx, bytes.NewReader(data), "wrong")
+		require.ErrorIs(t, err, core.ErrDecryption)
+
+		_, target := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		_, err = target.Domains().Backup().Restore(ctx, bytes.NewReader(data), "secret")
+		require.NoError(t, err)
+
+		count, err := target.Database().GetBookmarksCount(ctx, model.DBGetBookmarksOptions{})
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+	})
+
+	t.Run("modified backup is rejected", func(t *testing.T) {
+		source, _ := setupSource(t)
+		data := backup(t, source, "")
+
+		// Rewrite the archive with a different thumbnail
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		require.NoError(t, err)
+		tr := tar.NewReader(gz)
+
+		modified := bytes.Buffer{}
+		gzw := gzip.NewWriter(&modified)
+		tw := tar.NewWriter(gzw)
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+
+			content, err := io.ReadAll(tr)
+			require.NoError(t, err)
+			if header.Name == "files/thumb/2" {
+				content = []byte("THUMBNAIL")
+			}
+
+			require.NoError(t, tw.WriteHeader(header))
+			_, err = tw.Write(content)
+			require.NoError(t, err)
+		}
+		require.NoError(t, tw.Close())
+		require.NoError(t, gzw.Close())
+
+		_, target := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		_, err = target.Domains().Backup().Restore(ctx, bytes.NewReader(modified.Bytes()), "")
+		require.ErrorContains(t, err, "checksum mismatch for files/thumb/2")
+
+		count, err := target.Database().GetBookmarksCount(ctx, model.DBGetBookmarksOptions{})
+		require.NoError(t, err)
+		require.Zero(t, count)
+	})
+
+	t.Run("failed restore is undone", func(t *testing.T) {
+		source, _ := setupSource(t)
+		data := backup(t, source, "")
+
+		// Break an entry restored after the bookmarks, keeping the checksums valid
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		require.NoError(t, err)
+		tr := tar.NewReader(gz)
+
+		broken := []byte(`{"not": "highlights"}`)
+		hash := sha256.Sum256(broken)
+
+		modified := bytes.Buffer{}
+		gzw := gzip.NewWriter(&modified)
+		tw := tar.NewWriter(gzw)
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+
+			content, err := io.ReadAll(tr)
+			require.NoError(t, err)
+			switch header.Name {
+			case "data/highlights.json":
+				content = broken
+			case model.BackupManifestName:
+				manifest := model.BackupManifest{}
+				require.NoError(t, json.Unmarshal(content, &manifest))
+				for i, file := range manifest.Files {
+					if file.Name == "data/highlights.json" {
+						manifest.Files[i].Size = int64(len(broken))
+						manifest.Files[i].SHA256 = hex.EncodeToString(hash[:])
+					}
+				}
+				content, err = json.Marshal(manifest)
+				require.NoError(t, err)
+			}
+
+			header.Size = int64(len(content))
+			require.NoError(t, tw.WriteHeader(header))
+			_, err = tw.Write(content)
+			require.NoError(t, err)
+		}
+		require.NoError(t, tw.Close())
+		require.NoError(t, gzw.Close())
+
+		_, target := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		before, err := target.Database().ListAccounts(ctx, model.DBListAccountsOptions{})
+		require.NoError(t, err)
+
+		_, err = target.Domains().Backup().Restore(ctx, bytes.NewReader(modified.Bytes()), "")
+		require.ErrorContains(t, err, "failed to read highlights")
+
+		count, err := target.Database().GetBookmarksCount(ctx, model.DBGetBookmarksOptions{})
+		require.NoError(t, err)
+		require.Zero(t, count)
+
+		accounts, err := target.Database().ListAccounts(ctx, model.DBListAccountsOptions{})
+		require.NoError(t, err)
+		require.Equal(t, before, accounts)
+
+		tags, err := target.Database().GetTags(ctx, model.DBListTagsOptions{})
+		require.NoError(t, err)
+		require.Empty(t, tags)
+
+		collections, err := target.Database().ListCollections(ctx, 0)
+		require.NoError(t, err)
+		require.Empty(t, collections)
+
+		exists, err := afero.Exists(target.Domains().Storage().FS(), "thumb")
+		require.NoError(t, err)
+		if exists {
+			files, err := afero.ReadDir(target.Domains().Storage().FS(), "thumb")
+			require.NoError(t, err)
+			require.Empty(t, files)
+		}
+	})
+
+	t.Run("instances with bookmarks are not restored", func(t *testing.T) {
+		source, _ := setupSource(t)
+		data := backup(t, source, "")
+
+		_, err := source.Domains().Backup().Restore(ctx, bytes.NewReader(data), "")
+		require.Error(t, err)
+	})
+
+	t.Run("not a backup", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		_, err := deps.Domains().Backup().Verify(ctx, bytes.NewReader([]byte("not a backup")), "")
+		require.Error(t, err)
+	})
+}
@@ -44,12 +44,12 @@ func TestBookmarkTagOperations(t *testing.T) {
 	// Test BookmarkExists
 	t.Run("BookmarkExists", func(t *testing.T) {
 		// Test with existing bookmark
-		exists, err := bookmarksDomain.BookmarkExists(ctx, bookmarkID)
+		exists, err := bookmarksDomain.BookmarkExists(ctx, bookmarkID, 0)
 		require.NoError(t, err)
 		assert.True(t, exists, "Bookmark should exist")
 
 		// Test with non-existent bookmark
-		exists, err = bookmarksDomain.BookmarkExists(ctx, 9999)
+		exists, err = bookmarksDomain.BookmarkExists(ctx, 9999, 0)
 		require.NoError(t, err)
 		assert.False(t, exists, "Non-existent bookmark should not exist")
 	})
@@ -70,7 +70,7 @@ func TestBookmarkTagOperations(t *testing.T) {
 	// Test AddTagToBookmark
 	t.Run("AddTagToBookmark", func(t *testing.T) {
 		// Add tag to bookmark
-		err := bookmarksDomain.AddTagToBookmark(ctx, bookmarkID, tagID)
+		err := bookmarksDomain.AddTagToBookmark(ctx, bookmarkID, tagID, 0)
 		require.NoError(t, err)
 
 		// Verify tag was added by listing tags for the bookmark
@@ -83,16 +83,16 @@ func TestBookmarkTagOperations(t *testing.T) {
 		assert.Equal(t, "test-tag", tags[0].Name, "Tag name should match")
 
 		// Test adding the same tag again (should not error)
-		err = bookmarksDomain.AddTagToBookmark(ctx, bookmarkID, tagID)
+		err = bookmarksDomain.AddTagToBookmark(ctx, bookmarkID, tagID, 0)
 		require.NoError(t, err, "Adding the same tag again should not error")
 
 		// Test adding tag to non-existent bookmark
-		err = bookmarksDomain.AddTagToBookmark(ctx, 9999, tagID)
+		err = bookmarksDomain.AddTagToBookmark(ctx, 9999, tagID, 0)
 		require.Error(t, err)
 		assert.ErrorIs(t, err, model.ErrBookmarkNotFound, "Should return bookmark not found error")
 
 		// Test adding non-existent tag to bookmark
-		err = bookmarksDomain.AddTagToBookmark(ctx, bookmarkID, 9999)
+		err = bookmarksDomain.AddTagToBookmark(ctx, bookmarkID, 9999, 0)
 		require.Error(t, err)
 		assert.ErrorIs(t, err, model.ErrTagNotFound, "Should return tag not found error")
 	})
@@ -100,7 +100,7 @@ func TestBookmarkTagOperations(t *testing.T) {
 	// Test RemoveTagFromBookmark
 	t.Run("RemoveTagFromBookmark", func(t *testing.T) {
 		// Remove tag from bookmark
-		err := bookmarksDomain.RemoveTagFromBookmark(ctx, bookmarkID, tagID)
+		err := bookmarksDomain.RemoveTagFromBookmark(ctx, bookmarkID, tagID, 0)
 		require.NoError(t, err)
 
 		// Verify tag was removed by listing tags for the bookmark
@@ -111,16 +111,16 @@ func TestBookmarkTagOperations(t *testing.T) {
 		require.Len(t, tags, 0, "Should have no tags after removal")
 
 		// Test removing a tag that's not associated with the bookmark (should not error)
-		err = bookmarksDomain.RemoveTagFromBookmark(ctx, bookmarkID, tagID)
+		err = bookmarksDomain.RemoveTagFromBookmark(ctx, bookmarkID, tagID, 0)
 		require.NoError(t, err, "Removing a tag that's not associated should not error")
 
 		// Test removing tag from non-existent bookmark
-		err = bookmarksDomain.RemoveTagFromBookmark(ctx, 9999, tagID)
+		err = bookmarksDomain.RemoveTagFromBookmark(ctx, 9999, tagID, 0)
 		require.Error(t, err)
 		assert.ErrorIs(t, err, model.ErrBookmarkNotFound, "Should return bookmark not found error")
 
 		// Test removing non-existent tag from bookmark
-		err = bookmarksDomain.RemoveTagFromBookmark(ctx, bookmarkID, 9999)
+		err = bookmarksDomain.RemoveTagFromBookmark(ctx, bookmarkID, 9999, 0)
 		require.Error(t, err)
 		assert.ErrorIs(t, err, model.ErrTagNotFound, "Should return tag not found error")
 	})
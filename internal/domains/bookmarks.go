@@ -2,7 +2,11 @@ package domains
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path"
+	"strconv"
 
 	"github.com/go-shiori/shiori/internal/core"
 	"github.com/go-shiori/shiori/internal/model"
@@ -27,8 +31,8 @@ func (d *BookmarksDomain) HasThumbnail(b *model.BookmarkDTO) bool {
 	return d.deps.Domains().Storage().FileExists(thumbnailPath)
 }
 
-func (d *BookmarksDomain) GetBookmark(ctx context.Context, id model.DBID) (*model.BookmarkDTO, error) {
-	bookmark, exists, err := d.deps.Database().GetBookmark(ctx, int(id), "")
+func (d *BookmarksDomain) GetBookmark(ctx context.Context, id model.DBID, accountID model.DBID) (*model.BookmarkDTO, error) {
+	bookmark, exists, err := d.deps.Database().GetBookmark(ctx, int(id), "", accountID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get bookmark: %w", err)
 	}
@@ -37,17 +41,16 @@ func (d *BookmarksDomain) GetBookmark(ctx context.Context, id model.DBID) (*mode
 		return nil, model.ErrBookmarkNotFound
 	}
 
-	// Check if it has ebook and archive.
-	bookmark.HasEbook = d.HasEbook(&bookmark)
-	bookmark.HasArchive = d.HasArchive(&bookmark)
+	// Check if it has ebook, archive and thumbnail.
+	d.setFileAttributes(&bookmark)
 
 	return &bookmark, nil
 }
 
-func (d *BookmarksDomain) GetBookmarks(ctx context.Context, ids []int) ([]model.BookmarkDTO, error) {
+func (d *BookmarksDomain) GetBookmarks(ctx context.Context, ids []int, accountID model.DBID) ([]model.BookmarkDTO, error) {
 	var bookmarks []model.BookmarkDTO
 	for _, id := range ids {
-		bookmark, exists, err := d.deps.Database().GetBookmark(ctx, id, "")
+		bookmark, exists, err := d.deps.Database().GetBookmark(ctx, id, "", accountID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get bookmark %d: %w", id, err)
 		}
@@ -55,14 +58,219 @@ func (d *BookmarksDomain) GetBookmarks(ctx context.Context, ids []int) ([]model.
 			continue
 		}
 
-		// Check if it has ebook and archive
-		bookmark.HasEbook = d.HasEbook(&bookmark)
-		bookmark.HasArchive = d.HasArchive(&bookmark)
+		// Check if it has ebook, archive and thumbnail
+		d.setFileAttributes(&bookmark)
 		bookmarks = append(bookmarks, bookmark)
 	}
 	return bookmarks, nil
 }
 
+// ListBookmarks returns the bookmarks matching the provided options
+func (d *BookmarksDomain) ListBookmarks(ctx context.Context, opts model.ListBookmarksOptions) ([]model.BookmarkDTO, error) {
+	bookmarks, err := d.deps.Database().GetBookmarks(ctx, opts.ToDBGetBookmarksOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bookmarks: %w", err)
+	}
+
+	for i := range bookmarks {
+		d.setFileAttributes(&bookmarks[i])
+	}
+
+	return bookmarks, nil
+}
+
+// exportPageSize is the number of bookmarks loaded at once while exporting
+const exportPageSize = 100
+
+// ExportBookmarks writes the bookmarks matching the provided options to the exporter, oldest
+// first and with their content. Bookmarks are loaded a page at a time, the limit and offset
+// of the options are ignored.
+func (d *BookmarksDomain) ExportBookmarks(ctx context.Context, exporter model.BookmarkExporter, opts model.ListBookmarksOptions) error {
+	dbOpts := opts.ToDBGetBookmarksOptions()
+	dbOpts.WithContent = true
+	dbOpts.OrderMethod = model.DefaultOrder
+	dbOpts.Limit = exportPageSize
+
+	if collectionsExporter, ok := exporter.(model.CollectionsExporter); ok {
+		collections, err := d.deps.Database().ListCollections(ctx, opts.AccountID)
+		if err != nil {
+			return fmt.Errorf("failed to get collections: %w", err)
+		}
+		collectionsExporter.SetCollections(collections)
+	}
+
+	for dbOpts.Offset = 0; ; dbOpts.Offset += exportPageSize {
+		bookmarks, err := d.deps.Database().GetBookmarks(ctx, dbOpts)
+		if err != nil {
+			return fmt.Errorf("failed to get bookmarks: %w", err)
+		}
+
+		for _, bookmark := range bookmarks {
+			d.setFileAttributes(&bookmark)
+			if err := exporter.WriteBookmark(bookmark); err != nil {
+				return fmt.Errorf("failed to export bookmark %d: %w", bookmark.ID, err)
+			}
+		}
+
+		if len(bookmarks) < exportPageSize {
+			break
+		}
+	}
+
+	if err := exporter.Close(); err != nil {
+		return fmt.Errorf("failed to finish export: %w", err)
+	}
+
+	return nil
+}
+
+// CountBookmarks returns the number of bookmarks matching the provided options,
+// ignoring the limit and offset.
+func (d *BookmarksDomain) CountBookmarks(ctx context.Context, opts model.ListBookmarksOptions) (int, error) {
+	count, err := d.deps.Database().GetBookmarksCount(ctx, opts.ToDBGetBookmarksOptions())
+	if err != nil {
+		return 0, fmt.Errorf("failed to count bookmarks: %w", err)
+	}
+	return count, nil
+}
+
+// CreateBookmark cleans up and stores a new bookmark. Content is not downloaded here,
+// use UpdateBookmarkCache for that once the bookmark has an ID.
+func (d *BookmarksDomain) CreateBookmark(ctx context.Context, bookmark model.BookmarkDTO) (*model.BookmarkDTO, error) {
+	var err error
+	bookmark.URL, err = core.RemoveUTMParams(bookmark.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to clean URL: %w", err)
+	}
+
+	if bookmark.Title == "" {
+		bookmark.Title = bookmark.URL
+	}
+
+	if err := d.deps.Domains().Rules().Apply(ctx, &bookmark, ""); err != nil {
+		return nil, err
+	}
+
+	results, err := d.deps.Database().SaveBookmarks(ctx, true, bookmark)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save bookmark: %w", err)
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("failed to save bookmark: no bookmark returned")
+	}
+
+	created := results[0]
+	d.setFileAttributes(&created)
+
+	d.deps.Domains().Webhooks().EmitCreatedTags(ctx, created)
+	d.deps.Domains().Webhooks().Emit(ctx, model.WebhookEventBookmarkCreated, created.AccountID, model.NewWebhookBookmark(created))
+
+	return &created, nil
+}
+
+// UpdateBookmark stores the provided bookmark data. Tags marked as deleted are removed
+// from the bookmark and tags without an ID are created.
+func (d *BookmarksDomain) UpdateBookmark(ctx context.Context, bookmark model.BookmarkDTO, accountID model.DBID) (*model.BookmarkDTO, error) {
+	exists, err := d.BookmarkExists(ctx, bookmark.ID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, model.ErrBookmarkNotFound
+	}
+
+	bookmark.URL, err = core.RemoveUTMParams(bookmark.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to clean URL: %w", err)
+	}
+
+	// Let the database set the modification time
+	bookmark.ModifiedAt = ""
+
+	results, err := d.deps.Database().SaveBookmarks(ctx, false, bookmark)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save bookmark: %w", err)
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("failed to save bookmark: no bookmark returned")
+	}
+
+	updated, err := d.GetBookmark(ctx, model.DBID(bookmark.ID), accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	d.deps.Domains().Webhooks().EmitCreatedTags(ctx, results...)
+	d.deps.Domains().Webhooks().Emit(ctx, model.WebhookEventBookmarkUpdated, updated.AccountID, model.NewWebhookBookmark(*updated))
+
+	return updated, nil
+}
+
+// DeleteBookmarks removes the bookmarks and their thumbnail, archive and ebook files.
+// IDs of bookmarks not owned by accountID are ignored.
+func (d *BookmarksDomain) DeleteBookmarks(ctx context.Context, ids []int, accountID model.DBID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	// Resolve the owned bookmarks first, so we don't remove files of someone else's bookmark.
+	// They are kept for the webhooks, which get the data of the deleted bookmarks.
+	owned, err := d.deps.Database().GetBookmarks(ctx, model.DBGetBookmarksOptions{
+		IDs:       ids,
+		AccountID: accountID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get bookmarks: %w", err)
+	}
+
+	ids = make([]int, 0, len(owned))
+	for _, bookmark := range owned {
+		ids = append(ids, bookmark.ID)
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := d.deps.Database().DeleteBookmarks(ctx, accountID, ids...); err != nil {
+		return fmt.Errorf("failed to delete bookmarks: %w", err)
+	}
+
+	for _, bookmark := range owned {
+		d.deps.Domains().Webhooks().Emit(ctx, model.WebhookEventBookmarkDeleted, bookmark.AccountID, model.NewWebhookBookmark(bookmark))
+	}
+
+	storage := d.deps.Domains().Storage()
+	for _, id := range ids {
+		bookmark := model.BookmarkDTO{ID: id}
+		for _, filePath := range []string{
+			model.GetThumbnailPath(&bookmark),
+			model.GetArchivePath(&bookmark),
+			model.GetEbookPath(&bookmark),
+		} {
+			if err := storage.FS().Remove(filePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+				d.deps.Logger().WithError(err).WithField("path", filePath).Warn("failed to remove bookmark file")
+			}
+		}
+
+		snapshotsDir := model.GetArchiveSnapshotsDir(&bookmark)
+		if err := storage.FS().RemoveAll(snapshotsDir); err != nil {
+			d.deps.Logger().WithError(err).WithField("path", snapshotsDir).Warn("failed to remove archive snapshots")
+		}
+	}
+
+	return nil
+}
+
+// setFileAttributes fills the attributes that depend on the files in storage
+func (d *BookmarksDomain) setFileAttributes(bookmark *model.BookmarkDTO) {
+	bookmark.HasEbook = d.HasEbook(bookmark)
+	bookmark.HasArchive = d.HasArchive(bookmark)
+	if d.HasThumbnail(bookmark) {
+		bookmark.ImageURL = path.Join(d.deps.Config().Http.RootPath, "bookmark", strconv.Itoa(bookmark.ID), "thumb")
+	}
+}
+
 func (d *BookmarksDomain) UpdateBookmarkCache(ctx context.Context, bookmark model.BookmarkDTO, keepMetadata bool, skipExist bool) (*model.BookmarkDTO, error) {
 	// Download data from internet
 	content, contentType, err := core.DownloadBookmark(bookmark.URL)
@@ -71,6 +279,11 @@ func (d *BookmarksDomain) UpdateBookmarkCache(ctx context.Context, bookmark mode
 	}
 	defer content.Close()
 
+	// The content type is known now, the rules can reject the bookmark or change what is created
+	if err := d.deps.Domains().Rules().Apply(ctx, &bookmark, contentType); err != nil {
+		return nil, err
+	}
+
 	// Check if we should skip existing ebook
 	if skipExist && bookmark.CreateEbook {
 		ebookPath := model.GetEbookPath(&bookmark)
@@ -95,15 +308,50 @@ func (d *BookmarksDomain) UpdateBookmarkCache(ctx context.Context, bookmark mode
 		return nil, fmt.Errorf("failed to process bookmark: %w", err)
 	}
 
+	// Rules matching the downloaded content can still add tags or reject the bookmark, but
+	// the archive and ebook are already created
+	actions, err := d.deps.Domains().Rules().Evaluate(ctx, processedBookmark.AccountID, model.NewRuleInput(processedBookmark, contentType))
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate rules: %w", err)
+	}
+	if actions.Reject {
+		return nil, model.ErrBookmarkRejected
+	}
+	actions.CreateArchive, actions.CreateEbook = nil, nil
+	actions.ApplyTo(&processedBookmark)
+
+	if bookmark.CreateArchive && processedBookmark.HasArchive {
+		d.deps.Domains().Webhooks().Emit(ctx, model.WebhookEventBookmarkArchived, processedBookmark.AccountID, model.NewWebhookBookmark(processedBookmark))
+	}
+
 	return &processedBookmark, nil
 }
 
 // BulkUpdateBookmarkTags updates tags for multiple bookmarks using tag IDs
-func (d *BookmarksDomain) BulkUpdateBookmarkTags(ctx context.Context, bookmarkIDs []int, tagIDs []int) error {
+func (d *BookmarksDomain) BulkUpdateBookmarkTags(ctx context.Context, bookmarkIDs []int, tagIDs []int, accountID model.DBID) error {
 	if len(bookmarkIDs) == 0 {
 		return nil
 	}
 
+	// Every bookmark must belong to the account, otherwise nothing is updated
+	if accountID > 0 {
+		uniqueIDs := map[int]struct{}{}
+		for _, id := range bookmarkIDs {
+			uniqueIDs[id] = struct{}{}
+		}
+
+		count, err := d.deps.Database().GetBookmarksCount(ctx, model.DBGetBookmarksOptions{
+			IDs:       bookmarkIDs,
+			AccountID: accountID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to check bookmarks: %w", err)
+		}
+		if count != len(uniqueIDs) {
+			return model.ErrBookmarkNotFound
+		}
+	}
+
 	// Call the database method directly
 	err := d.deps.Database().BulkUpdateBookmarkTags(ctx, bookmarkIDs, tagIDs)
 	if err != nil {
@@ -114,9 +362,9 @@ func (d *BookmarksDomain) BulkUpdateBookmarkTags(ctx context.Context, bookmarkID
 }
 
 // AddTagToBookmark adds a tag to a bookmark
-func (d *BookmarksDomain) AddTagToBookmark(ctx context.Context, bookmarkID int, tagID int) error {
+func (d *BookmarksDomain) AddTagToBookmark(ctx context.Context, bookmarkID int, tagID int, accountID model.DBID) error {
 	// Check if bookmark exists
-	exists, err := d.BookmarkExists(ctx, bookmarkID)
+	exists, err := d.BookmarkExists(ctx, bookmarkID, accountID)
 	if err != nil {
 		return err
 	}
@@ -138,9 +386,9 @@ func (d *BookmarksDomain) AddTagToBookmark(ctx context.Context, bookmarkID int,
 }
 
 // RemoveTagFromBookmark removes a tag from a bookmark
-func (d *BookmarksDomain) RemoveTagFromBookmark(ctx context.Context, bookmarkID int, tagID int) error {
+func (d *BookmarksDomain) RemoveTagFromBookmark(ctx context.Context, bookmarkID int, tagID int, accountID model.DBID) error {
 	// Check if bookmark exists
-	exists, err := d.BookmarkExists(ctx, bookmarkID)
+	exists, err := d.BookmarkExists(ctx, bookmarkID, accountID)
 	if err != nil {
 		return err
 	}
@@ -161,9 +409,21 @@ func (d *BookmarksDomain) RemoveTagFromBookmark(ctx context.Context, bookmarkID
 	return d.deps.Database().RemoveTagFromBookmark(ctx, bookmarkID, tagID)
 }
 
-// BookmarkExists checks if a bookmark with the given ID exists
-func (d *BookmarksDomain) BookmarkExists(ctx context.Context, id int) (bool, error) {
-	return d.deps.Database().BookmarkExists(ctx, id)
+// BookmarkExists checks if a bookmark with the given ID exists and is owned by accountID
+func (d *BookmarksDomain) BookmarkExists(ctx context.Context, id int, accountID model.DBID) (bool, error) {
+	if accountID == 0 {
+		return d.deps.Database().BookmarkExists(ctx, id)
+	}
+
+	count, err := d.deps.Database().GetBookmarksCount(ctx, model.DBGetBookmarksOptions{
+		IDs:       []int{id},
+		AccountID: accountID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to check if bookmark exists: %w", err)
+	}
+
+	return count > 0, nil
 }
 
 func NewBookmarksDomain(deps model.Dependencies) *BookmarksDomain {
@@ -62,7 +62,7 @@ func TestBookmarkDomain(t *testing.T) {
 		t.Run("Success", func(t *testing.T) {
 			_, err := deps.Database().SaveBookmarks(context.TODO(), true, *testutil.GetValidBookmark())
 			require.NoError(t, err)
-			bookmark, err := domain.GetBookmark(context.Background(), 1)
+			bookmark, err := domain.GetBookmark(context.Background(), 1, 0)
 			require.NoError(t, err)
 			require.Equal(t, 1, bookmark.ID)
 
@@ -72,7 +72,7 @@ func TestBookmarkDomain(t *testing.T) {
 		})
 
 		t.Run("NotFound", func(t *testing.T) {
-			bookmark, err := domain.GetBookmark(context.Background(), 999)
+			bookmark, err := domain.GetBookmark(context.Background(), 999, 0)
 			require.Error(t, err)
 			require.Nil(t, bookmark)
 			require.Equal(t, model.ErrBookmarkNotFound, err)
@@ -82,7 +82,7 @@ func TestBookmarkDomain(t *testing.T) {
 			// Create a new context with a timeout to force an error
 			cancelCtx, cancel := context.WithCancel(context.Background())
 			cancel() // Cancel immediately to force error
-			bookmark, err := domain.GetBookmark(cancelCtx, 1)
+			bookmark, err := domain.GetBookmark(cancelCtx, 1, 0)
 			require.Error(t, err)
 			require.Nil(t, bookmark)
 			require.Contains(t, err.Error(), "failed to get bookmark")
@@ -102,7 +102,7 @@ func TestBookmarkDomain(t *testing.T) {
 			require.NoError(t, err)
 
 			// Test getting multiple bookmarks
-			bookmarks, err := domain.GetBookmarks(context.Background(), []int{1, 2})
+			bookmarks, err := domain.GetBookmarks(context.Background(), []int{1, 2}, 0)
 			require.NoError(t, err)
 			require.Len(t, bookmarks, 2)
 
@@ -118,7 +118,7 @@ func TestBookmarkDomain(t *testing.T) {
 
 		t.Run("PartialResults", func(t *testing.T) {
 			// Test with a mix of existing and non-existing IDs
-			bookmarks, err := domain.GetBookmarks(context.Background(), []int{1, 999})
+			bookmarks, err := domain.GetBookmarks(context.Background(), []int{1, 999}, 0)
 			require.NoError(t, err)
 			require.Len(t, bookmarks, 1)
 			assert.Equal(t, 1, bookmarks[0].ID)
@@ -126,7 +126,7 @@ func TestBookmarkDomain(t *testing.T) {
 
 		t.Run("EmptyResults", func(t *testing.T) {
 			// Test with non-existing IDs
-			bookmarks, err := domain.GetBookmarks(context.Background(), []int{998, 999})
+			bookmarks, err := domain.GetBookmarks(context.Background(), []int{998, 999}, 0)
 			require.NoError(t, err)
 			require.Len(t, bookmarks, 0)
 		})
@@ -135,7 +135,7 @@ func TestBookmarkDomain(t *testing.T) {
 			// Create a new context with a timeout to force an error
 			cancelCtx, cancel := context.WithCancel(context.Background())
 			cancel() // Cancel immediately to force error
-			bookmarks, err := domain.GetBookmarks(cancelCtx, []int{1})
+			bookmarks, err := domain.GetBookmarks(cancelCtx, []int{1}, 0)
 			require.Error(t, err)
 			require.Nil(t, bookmarks)
 			require.Contains(t, err.Error(), "failed to get bookmark")
@@ -216,17 +216,17 @@ func TestBookmarksDomain_BulkUpdateBookmarkTags(t *testing.T) {
 	domain := domains.NewBookmarksDomain(deps)
 
 	t.Run("empty_bookmark_ids", func(t *testing.T) {
-		err := domain.BulkUpdateBookmarkTags(ctx, []int{}, []int{1, 2, 3})
+		err := domain.BulkUpdateBookmarkTags(ctx, []int{}, []int{1, 2, 3}, 0)
 		require.NoError(t, err) // Should not return an error for empty bookmark IDs
 	})
 
 	t.Run("empty_tag_ids", func(t *testing.T) {
-		err := domain.BulkUpdateBookmarkTags(ctx, []int{1, 2, 3}, []int{})
+		err := domain.BulkUpdateBookmarkTags(ctx, []int{1, 2, 3}, []int{}, 0)
 		require.NoError(t, err) // Should not return an error for empty tag IDs
 	})
 
 	t.Run("non_existent_bookmarks", func(t *testing.T) {
-		err := domain.BulkUpdateBookmarkTags(ctx, []int{999, 1000}, []int{1, 2, 3})
+		err := domain.BulkUpdateBookmarkTags(ctx, []int{999, 1000}, []int{1, 2, 3}, 0)
 		require.Error(t, err)
 	})
 
@@ -252,12 +252,12 @@ func TestBookmarksDomain_BulkUpdateBookmarkTags(t *testing.T) {
 		tagIDs := []int{createdTags[0].ID, createdTags[1].ID}
 
 		// Update the bookmarks with the tags
-		err = domain.BulkUpdateBookmarkTags(ctx, bookmarkIDs, tagIDs)
+		err = domain.BulkUpdateBookmarkTags(ctx, bookmarkIDs, tagIDs, 0)
 		require.NoError(t, err)
 
 		// Verify the bookmarks have the tags
 		for _, bookmarkID := range bookmarkIDs {
-			bookmark, err := domain.GetBookmark(ctx, model.DBID(bookmarkID))
+			bookmark, err := domain.GetBookmark(ctx, model.DBID(bookmarkID), 0)
 			require.NoError(t, err)
 
 			// Check that the bookmark has both tags
@@ -274,3 +274,209 @@ func TestBookmarksDomain_BulkUpdateBookmarkTags(t *testing.T) {
 		}
 	})
 }
+
+func TestBookmarksDomain_ListBookmarks(t *testing.T) {
+	ctx := context.Background()
+	logger := logrus.New()
+	_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+	domain := domains.NewBookmarksDomain(deps)
+
+	for i := 0; i < 3; i++ {
+		_, err := deps.Database().SaveBookmarks(ctx, true, *testutil.GetValidBookmark())
+		require.NoError(t, err)
+	}
+
+	t.Run("paginated", func(t *testing.T) {
+		opts := model.ListBookmarksOptions{Limit: 2, OrderMethod: model.ByLastAdded}
+
+		bookmarks, err := domain.ListBookmarks(ctx, opts)
+		require.NoError(t, err)
+		require.Len(t, bookmarks, 2)
+
+		count, err := domain.CountBookmarks(ctx, opts)
+		require.NoError(t, err)
+		require.Equal(t, 3, count)
+	})
+
+	t.Run("no_results", func(t *testing.T) {
+		opts := model.ListBookmarksOptions{Keyword: "does-not-exist"}
+
+		bookmarks, err := domain.ListBookmarks(ctx, opts)
+		require.NoError(t, err)
+		require.Len(t, bookmarks, 0)
+
+		count, err := domain.CountBookmarks(ctx, opts)
+		require.NoError(t, err)
+		require.Equal(t, 0, count)
+	})
+}
+
+// recordingExporter keeps the exported bookmarks in memory
+type recordingExporter struct {
+	bookmarks []model.BookmarkDTO
+	closed    bool
+}
+
+func (e *recordingExporter) WriteBookmark(bookmark model.BookmarkDTO) error {
+	e.bookmarks = append(e.bookmarks, bookmark)
+	return nil
+}
+
+func (e *recordingExporter) Close() error {
+	e.closed = true
+	return nil
+}
+
+func TestBookmarksDomain_ExportBookmarks(t *testing.T) {
+	ctx := context.Background()
+	logger := logrus.New()
+	_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+	domain := domains.NewBookmarksDomain(deps)
+
+	// More than a page of bookmarks, the first one with content
+	bookmarks := []model.BookmarkDTO{}
+	for i := 0; i < 105; i++ {
+		bookmarks = append(bookmarks, *testutil.GetValidBookmark())
+	}
+	bookmarks[0].Content = "Shiori content"
+	bookmarks[0].HTML = "<p>Shiori content</p>"
+	_, err := deps.Database().SaveBookmarks(ctx, true, bookmarks...)
+	require.NoError(t, err)
+
+	other := testutil.GetValidBookmark()
+	other.AccountID = testutil.FakeAccountID + 1
+	_, err = deps.Database().SaveBookmarks(ctx, true, *other)
+	require.NoError(t, err)
+
+	t.Run("every bookmark", func(t *testing.T) {
+		exporter := &recordingExporter{}
+		require.NoError(t, domain.ExportBookmarks(ctx, exporter, model.ListBookmarksOptions{}))
+		require.True(t, exporter.closed)
+		require.Len(t, exporter.bookmarks, 106)
+		require.Equal(t, 1, exporter.bookmarks[0].ID)
+		require.Equal(t, "<p>Shiori content</p>", exporter.bookmarks[0].HTML)
+	})
+
+	t.Run("bookmarks of an account", func(t *testing.T) {
+		exporter := &recordingExporter{}
+		require.NoError(t, domain.ExportBookmarks(ctx, exporter, model.ListBookmarksOptions{AccountID: other.AccountID}))
+		require.Len(t, exporter.bookmarks, 1)
+		require.Equal(t, other.URL, exporter.bookmarks[0].URL)
+	})
+}
+
+func TestBookmarksDomain_CreateBookmark(t *testing.T) {
+	ctx := context.Background()
+	logger := logrus.New()
+	_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+	domain := domains.NewBookmarksDomain(deps)
+
+	t.Run("cleans_url_and_sets_title", func(t *testing.T) {
+		bookmark, err := domain.CreateBookmark(ctx, model.BookmarkDTO{
+			URL: "https://example.com/?utm_source=test",
+			Tags: []model.TagDTO{
+				{Tag: model.Tag{Name: "example"}},
+			},
+		})
+		require.NoError(t, err)
+		require.NotZero(t, bookmark.ID)
+		require.Equal(t, "https://example.com/", bookmark.URL)
+		require.Equal(t, "https://example.com/", bookmark.Title)
+		require.Len(t, bookmark.Tags, 1)
+	})
+
+	t.Run("invalid_url", func(t *testing.T) {
+		_, err := domain.CreateBookmark(ctx, model.BookmarkDTO{URL: "://invalid"})
+		require.Error(t, err)
+	})
+}
+
+func TestBookmarksDomain_UpdateBookmark(t *testing.T) {
+	ctx := context.Background()
+	logger := logrus.New()
+	_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+	domain := domains.NewBookmarksDomain(deps)
+
+	t.Run("not_found", func(t *testing.T) {
+		bookmark := testutil.GetValidBookmark()
+		bookmark.ID = 999
+		_, err := domain.UpdateBookmark(ctx, *bookmark, 0)
+		require.ErrorIs(t, err, model.ErrBookmarkNotFound)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		saved, err := deps.Database().SaveBookmarks(ctx, true, *testutil.GetValidBookmark())
+		require.NoError(t, err)
+
+		bookmark := saved[0]
+		bookmark.Title = "Updated title"
+		bookmark.Tags = []model.TagDTO{{Tag: model.Tag{Name: "updated"}}}
+
+		updated, err := domain.UpdateBookmark(ctx, bookmark, 0)
+		require.NoError(t, err)
+		require.Equal(t, "Updated title", updated.Title)
+		require.Len(t, updated.Tags, 1)
+		require.Equal(t, "updated", updated.Tags[0].Name)
+	})
+}
+
+func TestBookmarksDomain_DeleteBookmarks(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	ctx := context.Background()
+	logger := logrus.New()
+	_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+	deps.Domains().SetStorage(domains.NewStorageDomain(deps, fs))
+
+	domain := domains.NewBookmarksDomain(deps)
+
+	saved, err := deps.Database().SaveBookmarks(ctx, true, *testutil.GetValidBookmark())
+	require.NoError(t, err)
+	bookmark := saved[0]
+
+	for _, path := range []string{
+		model.GetThumbnailPath(&bookmark),
+		model.GetArchivePath(&bookmark),
+		model.GetEbookPath(&bookmark),
+	} {
+		_, err := fs.Create(path)
+		require.NoError(t, err)
+	}
+
+	err = domain.DeleteBookmarks(ctx, []int{bookmark.ID}, 0)
+	require.NoError(t, err)
+
+	exists, err := domain.BookmarkExists(ctx, bookmark.ID, 0)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	require.False(t, domain.HasThumbnail(&bookmark))
+	require.False(t, domain.HasArchive(&bookmark))
+	require.False(t, domain.HasEbook(&bookmark))
+
+	t.Run("bookmark of another account", func(t *testing.T) {
+		other := testutil.GetValidBookmark()
+		other.AccountID = testutil.FakeAccountID + 1
+		saved, err := deps.Database().SaveBookmarks(ctx, true, *other)
+		require.NoError(t, err)
+		bookmark := saved[0]
+
+		_, err = fs.Create(model.GetThumbnailPath(&bookmark))
+		require.NoError(t, err)
+
+		err = domain.DeleteBookmarks(ctx, []int{bookmark.ID}, testutil.FakeAccountID)
+		require.NoError(t, err)
+
+		exists, err := domain.BookmarkExists(ctx, bookmark.ID, testutil.FakeAccountID)
+		require.NoError(t, err)
+		require.False(t, exists, "bookmark must not be visible to other accounts")
+
+		exists, err = domain.BookmarkExists(ctx, bookmark.ID, bookmark.AccountID)
+		require.NoError(t, err)
+		require.True(t, exists, "bookmark must not be deleted by other accounts")
+		require.True(t, domain.HasThumbnail(&bookmark))
+	})
+}
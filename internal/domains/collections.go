@@ -0,0 +1,227 @@
+package domains
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-shiori/shiori/internal/database"
+	"github.com/go-shiori/shiori/internal/model"
+)
+
+// CollectionsDomain manages the folders of bookmarks of each account. Collections are nested
+// through their parent and ordered manually among their siblings.
+type CollectionsDomain struct {
+	deps model.Dependencies
+}
+
+// ListCollections returns every collection of the account, ordered by position.
+func (d *CollectionsDomain) ListCollections(ctx context.Context, account *model.AccountDTO) ([]model.Collection, error) {
+	return d.deps.Database().ListCollections(ctx, account.ID)
+}
+
+// GetCollection returns a collection of the account.
+func (d *CollectionsDomain) GetCollection(ctx context.Context, account *model.AccountDTO, id model.DBID) (*model.Collection, error) {
+	collection, exists, err := d.deps.Database().GetCollection(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists || collection.AccountID != account.ID {
+		return nil, model.ErrNotFound
+	}
+
+	return collection, nil
+}
+
+// CreateCollection adds a collection to the account, after the other children of its parent.
+func (d *CollectionsDomain) CreateCollection(ctx context.Context, account *model.AccountDTO, collection model.Collection) (*model.Collection, error) {
+	collection.AccountID = account.ID
+	collection.Name = strings.TrimSpace(collection.Name)
+	if err := collection.IsValid(); err != nil {
+		return nil, err
+	}
+
+	collections, err := d.deps.Database().ListCollections(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if collection.ParentID != nil && !containsCollection(collections, *collection.ParentID) {
+		return nil, model.NewValidationError("parent_id", "parent collection not found")
+	}
+
+	collection.Position = 0
+	for _, sibling := range collections {
+		if model.SameCollection(sibling.ParentID, collection.ParentID) && sibling.Position >= collection.Position {
+			collection.Position = sibling.Position + 1
+		}
+	}
+
+	return d.deps.Database().CreateCollection(ctx, collection)
+}
+
+// RenameCollection changes the name of a collection of the account.
+func (d *CollectionsDomain) RenameCollection(ctx context.Context, account *model.AccountDTO, id model.DBID, name string) (*model.Collection, error) {
+	collection, err := d.GetCollection(ctx, account, id)
+	if err != nil {
+		return nil, err
+	}
+
+	collection.Name = strings.TrimSpace(name)
+	if err := collection.IsValid(); err != nil {
+		return nil, err
+	}
+
+	if err := d.deps.Database().UpdateCollection(ctx, *collection); err != nil {
+		return nil, err
+	}
+
+	return d.GetCollection(ctx, account, id)
+}
+
+// MoveCollection moves a collection under a new parent, nil for the top level, at the given
+// position among its new siblings. The old and new siblings are renumbered in a single
+// transaction to keep their order contiguous.
+func (d *CollectionsDomain) MoveCollection(ctx context.Context, account *model.AccountDTO, id model.DBID, parentID *model.DBID, position int) (*model.Collection, error) {
+	if position < 0 {
+		return nil, model.NewValidationError("position", "position should not be negative")
+	}
+
+	collections, err := d.deps.Database().ListCollections(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[model.DBID]model.Collection, len(collections))
+	for _, collection := range collections {
+		byID[collection.ID] = collection
+	}
+
+	moved, exists := byID[id]
+	if !exists {
+		return nil, model.ErrNotFound
+	}
+
+	if parentID != nil {
+		if _, exists := byID[*parentID]; !exists {
+			return nil, model.NewValidationError("parent_id", "parent collection not found")
+		}
+
+		// Walk up from the new parent, reaching the moved collection means it would contain itself
+		for current := *parentID; ; {
+			if current == id {
+				return nil, model.NewValidationError("parent_id", "a collection can't be moved inside itself")
+			}
+			parent := byID[current]
+			if parent.ParentID == nil {
+				break
+			}
+			current = *parent.ParentID
+		}
+	}
+
+	// The collections left by the moved one close the gap, the new siblings make room for it
+	oldSiblings, siblings := []model.Collection{}, []model.Collection{}
+	for _, collection := range collections {
+		if collection.ID == id {
+			continue
+		}
+		if model.SameCollection(collection.ParentID, parentID) {
+			siblings = append(siblings, collection)
+		} else if model.SameCollection(collection.ParentID, moved.ParentID) {
+			oldSiblings = append(oldSiblings, collection)
+		}
+	}
+
+	for _, group := range [][]model.Collection{oldSiblings, siblings} {
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].Position < group[j].Position
+		})
+	}
+
+	position = min(position, len(siblings))
+	moved.ParentID = parentID
+	siblings = append(siblings[:position], append([]model.Collection{moved}, siblings[position:]...)...)
+
+	changed := []model.Collection{}
+	for _, group := range [][]model.Collection{oldSiblings, siblings} {
+		for i, sibling := range group {
+			if sibling.ID != id && sibling.Position == i {
+				continue
+			}
+			sibling.Position = i
+			changed = append(changed, sibling)
+		}
+	}
+
+	if err := d.deps.Database().UpdateCollections(ctx, changed...); err != nil {
+		return nil, err
+	}
+
+	return d.GetCollection(ctx, account, id)
+}
+
+// DeleteCollection removes a collection of the account. Its bookmarks and child collections
+// are moved to its parent.
+func (d *CollectionsDomain) DeleteCollection(ctx context.Context, account *model.AccountDTO, id model.DBID) error {
+	if _, err := d.GetCollection(ctx, account, id); err != nil {
+		return err
+	}
+
+	err := d.deps.Database().DeleteCollection(ctx, id)
+	if errors.Is(err, database.ErrNotFound) {
+		return model.ErrNotFound
+	}
+
+	return err
+}
+
+// MoveBookmarks puts bookmarks of the account in a collection, or in none if nil.
+// Nothing is moved if one of the bookmarks doesn't belong to the account.
+func (d *CollectionsDomain) MoveBookmarks(ctx context.Context, account *model.AccountDTO, collectionID *model.DBID, bookmarkIDs []int) error {
+	if len(bookmarkIDs) == 0 {
+		return nil
+	}
+
+	if collectionID != nil {
+		if _, err := d.GetCollection(ctx, account, *collectionID); err != nil {
+			return err
+		}
+	}
+
+	uniqueIDs := map[int]struct{}{}
+	for _, id := range bookmarkIDs {
+		uniqueIDs[id] = struct{}{}
+	}
+
+	count, err := d.deps.Database().GetBookmarksCount(ctx, model.DBGetBookmarksOptions{
+		IDs:       bookmarkIDs,
+		AccountID: account.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to check bookmarks: %w", err)
+	}
+	if count != len(uniqueIDs) {
+		return model.ErrBookmarkNotFound
+	}
+
+	return d.deps.Database().SetBookmarksCollection(ctx, collectionID, bookmarkIDs...)
+}
+
+func containsCollection(collections []model.Collection, id model.DBID) bool {
+	for _, collection := range collections {
+		if collection.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func NewCollectionsDomain(deps model.Dependencies) *CollectionsDomain {
+	return &CollectionsDomain{
+		deps: deps,
+	}
+}
@@ -0,0 +1,149 @@
+package domains_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/shiori/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectionsDomain(t *testing.T) {
+	ctx := context.Background()
+	logger := logrus.New()
+
+	account := testutil.FakeAccount(false)
+	other := &model.AccountDTO{ID: account.ID + 1, Username: "other"}
+
+	t.Run("create appends to the siblings", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		collections := deps.Domains().Collections()
+
+		_, err := collections.CreateCollection(ctx, account, model.Collection{Name: "  "})
+		require.ErrorAs(t, err, &model.ValidationError{})
+
+		first, err := collections.CreateCollection(ctx, account, model.Collection{Name: "Work"})
+		require.NoError(t, err)
+		second, err := collections.CreateCollection(ctx, account, model.Collection{Name: " Home "})
+		require.NoError(t, err)
+		require.Equal(t, "Home", second.Name)
+		require.Equal(t, first.Position+1, second.Position)
+
+		child, err := collections.CreateCollection(ctx, account, model.Collection{Name: "Reports", ParentID: &first.ID})
+		require.NoError(t, err)
+		require.Equal(t, 0, child.Position)
+
+		_, err = collections.CreateCollection(ctx, other, model.Collection{Name: "Stolen", ParentID: &first.ID})
+		require.ErrorAs(t, err, &model.ValidationError{})
+
+		_, err = collections.GetCollection(ctx, other, first.ID)
+		require.ErrorIs(t, err, model.ErrNotFound)
+	})
+
+	t.Run("move reorders the siblings", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		collections := deps.Domains().Collections()
+
+		a, err := collections.CreateCollection(ctx, account, model.Collection{Name: "A"})
+		require.NoError(t, err)
+		b, err := collections.CreateCollection(ctx, account, model.Collection{Name: "B"})
+		require.NoError(t, err)
+		c, err := collections.CreateCollection(ctx, account, model.Collection{Name: "C"})
+		require.NoError(t, err)
+
+		_, err = collections.MoveCollection(ctx, account, c.ID, nil, 0)
+		require.NoError(t, err)
+
+		list, err := collections.ListCollections(ctx, account)
+		require.NoError(t, err)
+		require.Equal(t, []string{"C", "A", "B"}, []string{list[0].Name, list[1].Name, list[2].Name})
+
+		moved, err := collections.MoveCollection(ctx, account, a.ID, &b.ID, 10)
+		require.NoError(t, err)
+		require.Equal(t, &b.ID, moved.ParentID)
+		require.Equal(t, 0, moved.Position)
+
+		_, err = collections.MoveCollection(ctx, account, b.ID, &a.ID, 0)
+		require.ErrorAs(t, err, &model.ValidationError{})
+
+		_, err = collections.MoveCollection(ctx, account, b.ID, &b.ID, 0)
+		require.ErrorAs(t, err, &model.ValidationError{})
+
+		_, err = collections.MoveCollection(ctx, other, b.ID, nil, 0)
+		require.ErrorIs(t, err, model.ErrNotFound)
+	})
+
+	t.Run("move between parents renumbers both", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		collections := deps.Domains().Collections()
+
+		from, err := collections.CreateCollection(ctx, account, model.Collection{Name: "From"})
+		require.NoError(t, err)
+		to, err := collections.CreateCollection(ctx, account, model.Collection{Name: "To"})
+		require.NoError(t, err)
+		for _, name := range []string{"X", "Y", "Z"} {
+			_, err := collections.CreateCollection(ctx, account, model.Collection{Name: name, ParentID: &from.ID})
+			require.NoError(t, err)
+		}
+		_, err = collections.CreateCollection(ctx, account, model.Collection{Name: "W", ParentID: &to.ID})
+		require.NoError(t, err)
+
+		list, err := collections.ListCollections(ctx, account)
+		require.NoError(t, err)
+		x := list[slices.IndexFunc(list, func(c model.Collection) bool { return c.Name == "X" })]
+
+		_, err = collections.MoveCollection(ctx, account, x.ID, &to.ID, 0)
+		require.NoError(t, err)
+
+		children := func(parentID model.DBID) map[string]int {
+			list, err := collections.ListCollections(ctx, account)
+			require.NoError(t, err)
+
+			positions := map[string]int{}
+			for _, collection := range list {
+				if collection.ParentID != nil && *collection.ParentID == parentID {
+					positions[collection.Name] = collection.Position
+				}
+			}
+			return positions
+		}
+
+		require.Equal(t, map[string]int{"Y": 0, "Z": 1}, children(from.ID))
+		require.Equal(t, map[string]int{"X": 0, "W": 1}, children(to.ID))
+	})
+
+	t.Run("move bookmarks of the account only", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		collections := deps.Domains().Collections()
+
+		collection, err := collections.CreateCollection(ctx, account, model.Collection{Name: "Reading"})
+		require.NoError(t, err)
+
+		bookmarks, err := deps.Database().SaveBookmarks(ctx, true,
+			model.BookmarkDTO{AccountID: account.ID, URL: "https://example.com/mine", Title: "Mine"},
+			model.BookmarkDTO{AccountID: other.ID, URL: "https://example.com/theirs", Title: "Theirs"},
+		)
+		require.NoError(t, err)
+
+		err = collections.MoveBookmarks(ctx, account, &collection.ID, []int{bookmarks[0].ID, bookmarks[1].ID})
+		require.ErrorIs(t, err, model.ErrBookmarkNotFound)
+
+		require.NoError(t, collections.MoveBookmarks(ctx, account, &collection.ID, []int{bookmarks[0].ID}))
+
+		bookmark, err := deps.Domains().Bookmarks().GetBookmark(ctx, model.DBID(bookmarks[0].ID), account.ID)
+		require.NoError(t, err)
+		require.Equal(t, &collection.ID, bookmark.CollectionID)
+
+		err = collections.MoveBookmarks(ctx, other, &collection.ID, []int{bookmarks[1].ID})
+		require.ErrorIs(t, err, model.ErrNotFound)
+
+		require.NoError(t, collections.DeleteCollection(ctx, account, collection.ID))
+
+		bookmark, err = deps.Domains().Bookmarks().GetBookmark(ctx, model.DBID(bookmarks[0].ID), account.ID)
+		require.NoError(t, err)
+		require.Nil(t, bookmark.CollectionID)
+	})
+}
@@ -0,0 +1,179 @@
+package domains
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/go-shiori/shiori/internal/core"
+	"github.com/go-shiori/shiori/internal/database"
+	"github.com/go-shiori/shiori/internal/model"
+)
+
+// HighlightsDomain manages the highlights and notes of each account on the readable content
+// of bookmarks.
+type HighlightsDomain struct {
+	deps model.Dependencies
+}
+
+// ListHighlights returns the highlights of the account on a bookmark, in the order of the
+// content. Their positions are the ones in the current content of the bookmark.
+func (d *HighlightsDomain) ListHighlights(ctx context.Context, account *model.AccountDTO, bookmark *model.BookmarkDTO) ([]model.Highlight, error) {
+	highlights, err := d.deps.Database().ListHighlights(ctx, model.ListHighlightsOptions{
+		BookmarkID: bookmark.ID,
+		AccountID:  account.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	text := core.HighlightText(bookmark.HTML)
+	for i := range highlights {
+		if position, ok := core.LocateHighlight(text, highlights[i].Selector); ok {
+			highlights[i].Selector.Position = &position
+		}
+	}
+	core.SortHighlights(highlights)
+
+	return highlights, nil
+}
+
+// GetHighlight returns a highlight of the account on a bookmark.
+func (d *HighlightsDomain) GetHighlight(ctx context.Context, account *model.AccountDTO, bookmark *model.BookmarkDTO, id model.DBID) (*model.Highlight, error) {
+	highlight, exists, err := d.deps.Database().GetHighlight(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists || highlight.AccountID != account.ID || highlight.BookmarkID != bookmark.ID {
+		return nil, model.ErrNotFound
+	}
+
+	return highlight, nil
+}
+
+// CreateHighlight highlights a passage of the bookmark content for the account. The passage
+// must be found in the content, its position is saved with the selector.
+func (d *HighlightsDomain) CreateHighlight(ctx context.Context, account *model.AccountDTO, bookmark *model.BookmarkDTO, highlight model.Highlight) (*model.Highlight, error) {
+	highlight.AccountID = account.ID
+	highlight.BookmarkID = bookmark.ID
+	if err := d.anchorHighlight(bookmark, &highlight); err != nil {
+		return nil, err
+	}
+
+	created, err := d.deps.Database().CreateHighlight(ctx, highlight)
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// UpdateHighlight replaces the selector and note of a highlight of the account.
+func (d *HighlightsDomain) UpdateHighlight(ctx context.Context, account *model.AccountDTO, bookmark *model.BookmarkDTO, highlight model.Highlight) (*model.Highlight, error) {
+	if _, err := d.GetHighlight(ctx, account, bookmark, highlight.ID); err != nil {
+		return nil, err
+	}
+
+	highlight.AccountID = account.ID
+	highlight.BookmarkID = bookmark.ID
+	if err := d.anchorHighlight(bookmark, &highlight); err != nil {
+		return nil, err
+	}
+
+	if err := d.deps.Database().UpdateHighlight(ctx, highlight); err != nil {
+		return nil, err
+	}
+
+	return d.GetHighlight(ctx, account, bookmark, highlight.ID)
+}
+
+// DeleteHighlight removes a highlight of the account.
+func (d *HighlightsDomain) DeleteHighlight(ctx context.Context, account *model.AccountDTO, bookmark *model.BookmarkDTO, id model.DBID) error {
+	if _, err := d.GetHighlight(ctx, account, bookmark, id); err != nil {
+		return err
+	}
+
+	err := d.deps.Database().DeleteHighlight(ctx, id)
+	if errors.Is(err, database.ErrNotFound) {
+		return model.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ExportHighlights returns every highlight of the account grouped by bookmark, in the order
+// the bookmarks were saved. Bookmarks the account can't see anymore are left out.
+func (d *HighlightsDomain) ExportHighlights(ctx context.Context, account *model.AccountDTO) ([]model.BookmarkHighlights, error) {
+	highlights, err := d.deps.Database().ListHighlights(ctx, model.ListHighlightsOptions{AccountID: account.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []int{}
+	byBookmark := map[int][]model.Highlight{}
+	for _, highlight := range highlights {
+		if _, ok := byBookmark[highlight.BookmarkID]; !ok {
+			ids = append(ids, highlight.BookmarkID)
+		}
+		byBookmark[highlight.BookmarkID] = append(byBookmark[highlight.BookmarkID], highlight)
+	}
+
+	exported := []model.BookmarkHighlights{}
+	if len(ids) == 0 {
+		return exported, nil
+	}
+
+	bookmarks, err := d.deps.Database().GetBookmarks(ctx, model.DBGetBookmarksOptions{IDs: ids})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(bookmarks, func(a, b model.BookmarkDTO) int {
+		return a.ID - b.ID
+	})
+
+	for _, bookmark := range bookmarks {
+		// Private bookmarks are only visible to the account that saved them and to owners,
+		// the highlights of bookmarks made private since then aren't exported
+		if bookmark.Public != 1 && bookmark.AccountID != account.ID && !account.IsOwner() {
+			continue
+		}
+
+		bookmarkHighlights := byBookmark[bookmark.ID]
+		core.SortHighlights(bookmarkHighlights)
+
+		exported = append(exported, model.BookmarkHighlights{
+			ID:         bookmark.ID,
+			URL:        bookmark.URL,
+			Title:      bookmark.Title,
+			Highlights: bookmarkHighlights,
+		})
+	}
+
+	return exported, nil
+}
+
+// anchorHighlight validates the highlight and sets its position to the passage it selects in
+// the bookmark content.
+func (d *HighlightsDomain) anchorHighlight(bookmark *model.BookmarkDTO, highlight *model.Highlight) error {
+	if err := highlight.IsValid(); err != nil {
+		return err
+	}
+
+	position, ok := core.LocateHighlight(core.HighlightText(bookmark.HTML), highlight.Selector)
+	if !ok {
+		return model.NewValidationError("selector", "highlighted text not found in the bookmark content")
+	}
+
+	highlight.Selector.Position = &position
+	return nil
+}
+
+func NewHighlightsDomain(deps model.Dependencies) *HighlightsDomain {
+	return &HighlightsDomain{
+		deps: deps,
+	}
+}
@@ -0,0 +1,141 @@
+package domains_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/shiori/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHighlightsDomain(t *testing.T) {
+	ctx := context.Background()
+	logger := logrus.New()
+
+	account := testutil.FakeAccount(false)
+	other := &model.AccountDTO{ID: account.ID + 1, Username: "other"}
+
+	saveBookmark := func(t *testing.T, deps model.Dependencies, html string) *model.BookmarkDTO {
+		bookmark := testutil.GetValidBookmark()
+		bookmark.HTML = html
+		saved, err := deps.Database().SaveBookmarks(ctx, true, *bookmark)
+		require.NoError(t, err)
+
+		bookmark, err = deps.Domains().Bookmarks().GetBookmark(ctx, model.DBID(saved[0].ID), 0)
+		require.NoError(t, err)
+		return bookmark
+	}
+
+	quote := func(exact, prefix string) model.HighlightSelector {
+		return model.HighlightSelector{Quote: model.TextQuoteSelector{Exact: exact, Prefix: prefix}}
+	}
+
+	t.Run("highlights are anchored and scoped to the account", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		highlights := deps.Domains().Highlights()
+		bookmark := saveBookmark(t, deps, "<p>A gopher digs. Another <i>gopher</i> sleeps.</p>")
+
+		_, err := highlights.CreateHighlight(ctx, account, bookmark, model.Highlight{Selector: quote(" ", "")})
+		require.ErrorAs(t, err, &model.ValidationError{})
+
+		_, err = highlights.CreateHighlight(ctx, account, bookmark, model.Highlight{Selector: quote("beaver", "")})
+		require.ErrorAs(t, err, &model.ValidationError{})
+
+		second, err := highlights.CreateHighlight(ctx, account, bookmark, model.Highlight{Selector: quote("gopher", "Another "), Note: "sleepy"})
+		require.NoError(t, err)
+		require.Equal(t, &model.TextPositionSelector{Start: 23, End: 29}, second.Selector.Position)
+		require.Equal(t, account.ID, second.AccountID)
+
+		first, err := highlights.CreateHighlight(ctx, account, bookmark, model.Highlight{Selector: quote("gopher", "")})
+		require.NoError(t, err)
+		require.Equal(t, &model.TextPositionSelector{Start: 2, End: 8}, first.Selector.Position)
+
+		_, err = highlights.CreateHighlight(ctx, other, bookmark, model.Highlight{Selector: quote("digs", "")})
+		require.NoError(t, err)
+
+		list, err := highlights.ListHighlights(ctx, account, bookmark)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		require.Equal(t, first.ID, list[0].ID)
+		require.Equal(t, second.ID, list[1].ID)
+
+		_, err = highlights.GetHighlight(ctx, other, bookmark, first.ID)
+		require.ErrorIs(t, err, model.ErrNotFound)
+		require.ErrorIs(t, highlights.DeleteHighlight(ctx, other, bookmark, first.ID), model.ErrNotFound)
+
+		first.Note = "awake"
+		updated, err := highlights.UpdateHighlight(ctx, account, bookmark, *first)
+		require.NoError(t, err)
+		require.Equal(t, "awake", updated.Note)
+
+		require.NoError(t, highlights.DeleteHighlight(ctx, account, bookmark, first.ID))
+		_, err = highlights.GetHighlight(ctx, account, bookmark, first.ID)
+		require.ErrorIs(t, err, model.ErrNotFound)
+	})
+
+	t.Run("export groups highlights by bookmark", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		highlights := deps.Domains().Highlights()
+		first := saveBookmark(t, deps, "<p>First article</p>")
+		second := saveBookmark(t, deps, "<p>Second article</p>")
+
+		_, err := highlights.CreateHighlight(ctx, account, second, model.Highlight{Selector: quote("article", "")})
+		require.NoError(t, err)
+		_, err = highlights.CreateHighlight(ctx, account, first, model.Highlight{Selector: quote("article", "")})
+		require.NoError(t, err)
+		_, err = highlights.CreateHighlight(ctx, account, first, model.Highlight{Selector: quote("First", "")})
+		require.NoError(t, err)
+		_, err = highlights.CreateHighlight(ctx, other, first, model.Highlight{Selector: quote("First", "")})
+		require.NoError(t, err)
+
+		exported, err := highlights.ExportHighlights(ctx, account)
+		require.NoError(t, err)
+		require.Len(t, exported, 2)
+		require.Equal(t, first.ID, exported[0].ID)
+		require.Equal(t, first.URL, exported[0].URL)
+		require.Len(t, exported[0].Highlights, 2)
+		require.Equal(t, "First", exported[0].Highlights[0].Selector.Quote.Exact)
+		require.Equal(t, second.ID, exported[1].ID)
+	})
+
+	t.Run("export leaves out bookmarks the account can't see", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		highlights := deps.Domains().Highlights()
+		own := saveBookmark(t, deps, "<p>Own article</p>")
+
+		shared := testutil.GetValidBookmark()
+		shared.AccountID = other.ID
+		shared.Public = 1
+		shared.HTML = "<p>Shared article</p>"
+		saved, err := deps.Database().SaveBookmarks(ctx, true, *shared)
+		require.NoError(t, err)
+		shared, err = deps.Domains().Bookmarks().GetBookmark(ctx, model.DBID(saved[0].ID), 0)
+		require.NoError(t, err)
+
+		_, err = highlights.CreateHighlight(ctx, account, own, model.Highlight{Selector: quote("article", "")})
+		require.NoError(t, err)
+		_, err = highlights.CreateHighlight(ctx, account, shared, model.Highlight{Selector: quote("article", "")})
+		require.NoError(t, err)
+
+		exported, err := highlights.ExportHighlights(ctx, account)
+		require.NoError(t, err)
+		require.Len(t, exported, 2)
+
+		// The other account makes its bookmark private
+		shared.Public = 0
+		_, err = deps.Database().SaveBookmarks(ctx, false, *shared)
+		require.NoError(t, err)
+
+		exported, err = highlights.ExportHighlights(ctx, account)
+		require.NoError(t, err)
+		require.Len(t, exported, 1)
+		require.Equal(t, own.ID, exported[0].ID)
+
+		// Owners see every bookmark
+		exported, err = highlights.ExportHighlights(ctx, &model.AccountDTO{ID: account.ID, Owner: model.Ptr(true)})
+		require.NoError(t, err)
+		require.Len(t, exported, 2)
+	})
+}
@@ -0,0 +1,310 @@
+package domains
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/model"
+)
+
+// JobsDomain runs the jobs stored in the database with a pool of workers.
+type JobsDomain struct {
+	deps     model.Dependencies
+	handlers map[model.JobType]model.JobHandler
+	wake     chan struct{}
+
+	mu     sync.Mutex
+	stop   chan struct{}
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Enqueue stores a new job in the queue and wakes up an idle worker.
+func (d *JobsDomain) Enqueue(ctx context.Context, jobType model.JobType, payload any) (*model.Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job payload: %w", err)
+	}
+
+	job, err := d.deps.Database().CreateJob(ctx, model.Job{
+		Type:        jobType,
+		Payload:     string(data),
+		MaxAttempts: d.deps.Config().Jobs.MaxAttempts,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job: %w", err)
+	}
+
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+
+	return job, nil
+}
+
+func (d *JobsDomain) ListJobs(ctx context.Context, opts model.ListJobsOptions) ([]model.Job, error) {
+	return d.deps.Database().ListJobs(ctx, model.DBListJobsOptions(opts))
+}
+
+// RegisterHandler sets the function that runs the jobs of the given type.
+func (d *JobsDomain) RegisterHandler(jobType model.JobType, handler model.JobHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.handlers[jobType] = handler
+}
+
+// Start launches the workers. Jobs left running by a previous process are queued again.
+func (d *JobsDomain) Start(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.stop != nil {
+		return fmt.Errorf("job workers are already running")
+	}
+
+	if err := d.deps.Database().RequeueRunningJobs(ctx); err != nil {
+		return fmt.Errorf("failed to requeue running jobs: %w", err)
+	}
+
+	// Jobs get their own context so they are not interrupted along with the caller
+	runCtx, cancel := context.WithCancel(context.Background())
+	d.stop = make(chan struct{})
+	d.cancel = cancel
+
+	concurrency := d.deps.Config().Jobs.Concurrency
+	d.deps.Logger().WithField("concurrency", concurrency).Info("starting job workers")
+	for i := 0; i < concurrency; i++ {
+		d.wg.Add(1)
+		go d.worker(runCtx, d.stop)
+	}
+
+	return nil
+}
+
+// Stop makes the workers finish their current job and exit. If ctx expires first the
+// running jobs are cancelled, they will be picked up again on the next start.
+func (d *JobsDomain) Stop(ctx context.Context) error {
+	d.mu.Lock()
+	if d.stop == nil {
+		d.mu.Unlock()
+		return nil
+	}
+	close(d.stop)
+	cancel := d.cancel
+	d.stop = nil
+	d.cancel = nil
+	d.mu.Unlock()
+
+	d.deps.Logger().Info("waiting for running jobs to finish")
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	defer cancel()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("job workers did not stop in time: %w", ctx.Err())
+	}
+}
+
+func (d *JobsDomain) worker(ctx context.Context, stop <-chan struct{}) {
+	defer d.wg.Done()
+
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		job, err := d.deps.Database().ClaimJob(ctx)
+		if err != nil {
+			d.deps.Logger().WithError(err).Error("failed to claim job")
+		}
+
+		if job == nil {
+			select {
+			case <-stop:
+				return
+			case <-d.wake:
+			case <-time.After(d.deps.Config().Jobs.PollInterval):
+			}
+			continue
+		}
+
+		d.run(ctx, *job)
+	}
+}
+
+func (d *JobsDomain) run(ctx context.Context, job model.Job) {
+	logger := d.deps.Logger().WithField("job_id", job.ID).WithField("job_type", job.Type)
+
+	d.mu.Lock()
+	handler, ok := d.handlers[job.Type]
+	d.mu.Unlock()
+
+	var err error
+	if ok {
+		err = d.call(ctx, handler, job)
+	} else {
+		err = fmt.Errorf("no handler registered for job type %s", job.Type)
+	}
+
+	switch {
+	case err == nil:
+		job.Status = model.JobStatusCompleted
+		job.LastError = ""
+	case ctx.Err() != nil:
+		// Interrupted by a shutdown, it doesn't count as an attempt
+		job.Status = model.JobStatusPending
+		job.LastError = err.Error()
+	default:
+		job.Attempts++
+		job.LastError = err.Error()
+		if !ok || job.Attempts >= job.MaxAttempts {
+			job.Status = model.JobStatusFailed
+			logger.WithError(err).Error("job failed")
+		} else {
+			job.Status = model.JobStatusPending
+			job.RunAt = time.Now().UTC().Add(d.backoff(job.Attempts)).Format(model.DatabaseDateFormat)
+			logger.WithError(err).WithField("run_at", job.RunAt).Warn("job failed, retrying later")
+		}
+	}
+
+	// The run context may be cancelled already, the result must be stored anyway
+	if err := d.deps.Database().UpdateJob(context.Background(), job); err != nil {
+		logger.WithError(err).Error("failed to update job")
+	}
+}
+
+// call runs the handler turning a panic into an error, so a faulty job doesn't stop the worker.
+func (d *JobsDomain) call(ctx context.Context, handler model.JobHandler, job model.Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	return handler(ctx, job)
+}
+
+// backoff doubles the configured retry delay on every failed attempt.
+func (d *JobsDomain) backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return d.deps.Config().Jobs.RetryBackoff * time.Duration(1<<(attempts-1))
+}
+
+// processBookmark downloads the bookmark and runs it through core.ProcessBookmark.
+func (d *JobsDomain) processBookmark(ctx context.Context, job model.Job) error {
+	var payload model.ProcessBookmarkJobPayload
+	if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+		return fmt.Errorf("invalid job payload: %w", err)
+	}
+
+	bookmarks := d.deps.Domains().Bookmarks()
+
+	bookmark, err := bookmarks.GetBookmark(ctx, model.DBID(payload.BookmarkID), 0)
+	if err != nil {
+		if errors.Is(err, model.ErrBookmarkNotFound) {
+			// Deleted since the job was queued, nothing left to do
+			return nil
+		}
+		return fmt.Errorf("failed to get bookmark: %w", err)
+	}
+
+	bookmark.CreateArchive = payload.CreateArchive
+	bookmark.CreateEbook = payload.CreateEbook
+
+	processed, err := bookmarks.UpdateBookmarkCache(ctx, *bookmark, false, false)
+	if errors.Is(err, model.ErrBookmarkRejected) {
+		// A rule matched the downloaded content, the new bookmark isn't kept
+		return bookmarks.DeleteBookmarks(ctx, []int{bookmark.ID}, bookmark.AccountID)
+	}
+	if err != nil {
+		return err
+	}
+
+	if payload.KeepTitle {
+		processed.Title = bookmark.Title
+	}
+	if payload.KeepExcerpt {
+		processed.Excerpt = bookmark.Excerpt
+	}
+
+	if _, err := bookmarks.UpdateBookmark(ctx, *processed, 0); err != nil {
+		return fmt.Errorf("failed to save processed bookmark: %w", err)
+	}
+
+	return nil
+}
+
+// checkLinks runs a link check of the bookmarks in the payload.
+func (d *JobsDomain) checkLinks(ctx context.Context, job model.Job) error {
+	var payload model.CheckLinksJobPayload
+	if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+		return fmt.Errorf("invalid job payload: %w", err)
+	}
+
+	return d.deps.Domains().LinkChecker().CheckBookmarks(ctx, payload.BookmarkIDs)
+}
+
+// pollSubscriptions polls the feed subscriptions in the payload.
+func (d *JobsDomain) pollSubscriptions(ctx context.Context, job model.Job) error {
+	var payload model.PollSubscriptionsJobPayload
+	if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+		return fmt.Errorf("invalid job payload: %w", err)
+	}
+
+	_, err := d.deps.Domains().Subscriptions().PollSubscriptions(ctx, payload.SubscriptionIDs)
+	return err
+}
+
+// deliverWebhook sends the webhook delivery in the payload.
+func (d *JobsDomain) deliverWebhook(ctx context.Context, job model.Job) error {
+	var payload model.DeliverWebhookJobPayload
+	if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+		return fmt.Errorf("invalid job payload: %w", err)
+	}
+
+	return d.deps.Domains().Webhooks().Deliver(ctx, payload.DeliveryID)
+}
+
+// indexArchives indexes the text of the bookmark archives in the payload.
+func (d *JobsDomain) indexArchives(ctx context.Context, job model.Job) error {
+	var payload model.IndexArchivesJobPayload
+	if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+		return fmt.Errorf("invalid job payload: %w", err)
+	}
+
+	return d.deps.Domains().Archiver().IndexArchives(ctx, payload.BookmarkIDs)
+}
+
+func NewJobsDomain(deps model.Dependencies) *JobsDomain {
+	d := &JobsDomain{
+		deps:     deps,
+		handlers: map[model.JobType]model.JobHandler{},
+		wake:     make(chan struct{}, 1),
+	}
+
+	d.RegisterHandler(model.JobTypeProcessBookmark, d.processBookmark)
+	d.RegisterHandler(model.JobTypeCheckLinks, d.checkLinks)
+	d.RegisterHandler(model.JobTypePollSubscriptions, d.pollSubscriptions)
+	d.RegisterHandler(model.JobTypeDeliverWebhook, d.deliverWebhook)
+	d.RegisterHandler(model.JobTypeIndexArchives, d.indexArchives)
+
+	return d
+}
@@ -0,0 +1,162 @@
+package domains_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/shiori/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+const testJobType model.JobType = "test"
+
+func TestJobsDomain(t *testing.T) {
+	ctx := context.Background()
+	logger := logrus.New()
+
+	setup := func(t *testing.T, handler model.JobHandler) model.JobsDomain {
+		cfg, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		cfg.Jobs.RetryBackoff = 0
+		cfg.Jobs.PollInterval = 10 * time.Millisecond
+
+		jobs := deps.Domains().Jobs()
+		jobs.RegisterHandler(testJobType, handler)
+		return jobs
+	}
+
+	jobStatus := func(t *testing.T, jobs model.JobsDomain, id model.DBID) model.Job {
+		list, err := jobs.ListJobs(ctx, model.ListJobsOptions{})
+		require.NoError(t, err)
+		for _, job := range list {
+			if job.ID == id {
+				return job
+			}
+		}
+		t.Fatalf("job %d not found", id)
+		return model.Job{}
+	}
+
+	t.Run("runs queued jobs", func(t *testing.T) {
+		received := make(chan string, 1)
+		jobs := setup(t, func(ctx context.Context, job model.Job) error {
+			received <- job.Payload
+			return nil
+		})
+
+		require.NoError(t, jobs.Start(ctx))
+		job, err := jobs.Enqueue(ctx, testJobType, map[string]int{"value": 1})
+		require.NoError(t, err)
+
+		select {
+		case payload := <-received:
+			require.JSONEq(t, `{"value": 1}`, payload)
+		case <-time.After(5 * time.Second):
+			t.Fatal("job was not run")
+		}
+
+		require.NoError(t, jobs.Stop(ctx))
+		require.Equal(t, model.JobStatusCompleted, jobStatus(t, jobs, job.ID).Status)
+	})
+
+	t.Run("failed jobs are retried until max attempts", func(t *testing.T) {
+		jobs := setup(t, func(ctx context.Context, job model.Job) error {
+			return errors.New("boom")
+		})
+
+		require.NoError(t, jobs.Start(ctx))
+		job, err := jobs.Enqueue(ctx, testJobType, nil)
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			return jobStatus(t, jobs, job.ID).Status == model.JobStatusFailed
+		}, 5*time.Second, 10*time.Millisecond)
+		require.NoError(t, jobs.Stop(ctx))
+
+		failed := jobStatus(t, jobs, job.ID)
+		require.Equal(t, job.MaxAttempts, failed.Attempts)
+		require.Equal(t, "boom", failed.LastError)
+	})
+
+	t.Run("jobs without handler fail", func(t *testing.T) {
+		jobs := setup(t, nil)
+
+		require.NoError(t, jobs.Start(ctx))
+		job, err := jobs.Enqueue(ctx, "unknown", nil)
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			return jobStatus(t, jobs, job.ID).Status == model.JobStatusFailed
+		}, 5*time.Second, 10*time.Millisecond)
+		require.NoError(t, jobs.Stop(ctx))
+	})
+
+	t.Run("stop waits for running jobs", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		jobs := setup(t, func(ctx context.Context, job model.Job) error {
+			close(started)
+			<-release
+			return nil
+		})
+
+		require.NoError(t, jobs.Start(ctx))
+		job, err := jobs.Enqueue(ctx, testJobType, nil)
+		require.NoError(t, err)
+		<-started
+
+		stopped := make(chan error)
+		go func() {
+			stopped <- jobs.Stop(ctx)
+		}()
+
+		select {
+		case <-stopped:
+			t.Fatal("stop returned while a job was running")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		close(release)
+		require.NoError(t, <-stopped)
+		require.Equal(t, model.JobStatusCompleted, jobStatus(t, jobs, job.ID).Status)
+	})
+
+	t.Run("jobs interrupted by stop are kept pending", func(t *testing.T) {
+		started := make(chan struct{})
+		jobs := setup(t, func(ctx context.Context, job model.Job) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		require.NoError(t, jobs.Start(ctx))
+		job, err := jobs.Enqueue(ctx, testJobType, nil)
+		require.NoError(t, err)
+		<-started
+
+		stopCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		require.Error(t, jobs.Stop(stopCtx))
+
+		require.Eventually(t, func() bool {
+			return jobStatus(t, jobs, job.ID).Status == model.JobStatusPending
+		}, 5*time.Second, 10*time.Millisecond)
+		require.Zero(t, jobStatus(t, jobs, job.ID).Attempts)
+	})
+
+	t.Run("processing a deleted bookmark completes", func(t *testing.T) {
+		jobs := setup(t, nil)
+
+		require.NoError(t, jobs.Start(ctx))
+		job, err := jobs.Enqueue(ctx, model.JobTypeProcessBookmark, model.ProcessBookmarkJobPayload{BookmarkID: 999})
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			return jobStatus(t, jobs, job.ID).Status == model.JobStatusCompleted
+		}, 5*time.Second, 10*time.Millisecond)
+		require.NoError(t, jobs.Stop(ctx))
+	})
+}
@@ -0,0 +1,160 @@
+package domains
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/core"
+	"github.com/go-shiori/shiori/internal/model"
+)
+
+// LinkCheckerDomain checks that bookmark URLs are still reachable and keeps the history of
+// the results.
+type LinkCheckerDomain struct {
+	deps model.Dependencies
+
+	mu   sync.Mutex
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// CheckBookmark requests the bookmark URL and stores the result.
+func (d *LinkCheckerDomain) CheckBookmark(ctx context.Context, book *model.BookmarkDTO) (*model.LinkCheck, error) {
+	check := core.CheckLink(ctx, book.URL, d.deps.Config().LinkCheck.Timeout)
+	check.BookmarkID = book.ID
+
+	saved, err := d.deps.Database().CreateLinkCheck(ctx, check)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.deps.Database().PruneLinkChecks(ctx, book.ID, d.deps.Config().LinkCheck.HistoryKeep); err != nil {
+		return nil, err
+	}
+
+	return saved, nil
+}
+
+// CheckBookmarks checks the bookmarks with the given ids, or every bookmark if there are none.
+func (d *LinkCheckerDomain) CheckBookmarks(ctx context.Context, ids []int) error {
+	bookmarks, err := d.deps.Database().GetBookmarks(ctx, model.DBGetBookmarksOptions{IDs: ids})
+	if err != nil {
+		return fmt.Errorf("failed to get bookmarks: %w", err)
+	}
+
+	semaphore := make(chan struct{}, d.deps.Config().LinkCheck.Concurrency)
+	wg := sync.WaitGroup{}
+
+	for _, book := range bookmarks {
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return ctx.Err()
+		}
+
+		wg.Add(1)
+		go func(book model.BookmarkDTO) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			if _, err := d.CheckBookmark(ctx, &book); err != nil {
+				d.deps.Logger().WithError(err).WithField("bookmark_id", book.ID).Error("failed to check bookmark link")
+			}
+		}(book)
+	}
+
+	wg.Wait()
+	return ctx.Err()
+}
+
+// ListChecks returns the stored link checks of a bookmark, newest first.
+func (d *LinkCheckerDomain) ListChecks(ctx context.Context, bookmarkID int) ([]model.LinkCheck, error) {
+	return d.deps.Database().GetLinkChecks(ctx, bookmarkID, 0)
+}
+
+// Start queues a check of every bookmark each configured interval, if there is one.
+func (d *LinkCheckerDomain) Start(ctx context.Context) error {
+	interval := d.deps.Config().LinkCheck.Interval
+	if interval <= 0 {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.stop != nil {
+		return fmt.Errorf("link check scheduler is already running")
+	}
+
+	d.stop = make(chan struct{})
+	d.deps.Logger().WithField("interval", interval).Info("scheduling link checks")
+
+	d.wg.Add(1)
+	go d.schedule(interval, d.stop)
+
+	return nil
+}
+
+// Stop ends the periodic checks. Checks already queued are left to the job workers.
+func (d *LinkCheckerDomain) Stop(ctx context.Context) error {
+	d.mu.Lock()
+	if d.stop == nil {
+		d.mu.Unlock()
+		return nil
+	}
+	close(d.stop)
+	d.stop = nil
+	d.mu.Unlock()
+
+	d.wg.Wait()
+	return nil
+}
+
+func (d *LinkCheckerDomain) schedule(interval time.Duration, stop <-chan struct{}) {
+	defer d.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			d.enqueueCheck()
+		}
+	}
+}
+
+// enqueueCheck queues a check of every bookmark unless one is waiting or running already.
+func (d *LinkCheckerDomain) enqueueCheck() {
+	ctx := context.Background()
+	jobs := d.deps.Domains().Jobs()
+
+	queued, err := jobs.ListJobs(ctx, model.ListJobsOptions{
+		Status: []model.JobStatus{model.JobStatusPending, model.JobStatusRunning},
+	})
+	if err != nil {
+		d.deps.Logger().WithError(err).Error("failed to list queued jobs")
+		return
+	}
+
+	for _, job := range queued {
+		if job.Type == model.JobTypeCheckLinks {
+			return
+		}
+	}
+
+	if _, err := jobs.Enqueue(ctx, model.JobTypeCheckLinks, model.CheckLinksJobPayload{}); err != nil {
+		d.deps.Logger().WithError(err).Error("failed to queue link check")
+	}
+}
+
+func NewLinkCheckerDomain(deps model.Dependencies) *LinkCheckerDomain {
+	return &LinkCheckerDomain{
+		deps: deps,
+	}
+}
@@ -0,0 +1,98 @@
+package domains_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/shiori/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinkCheckerDomain(t *testing.T) {
+	ctx := context.Background()
+	logger := logrus.New()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	setup := func(t *testing.T) (model.Dependencies, []model.BookmarkDTO) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		bookmarks, err := deps.Database().SaveBookmarks(ctx, true,
+			model.BookmarkDTO{URL: server.URL + "/ok", Title: "ok"},
+			model.BookmarkDTO{URL: server.URL + "/missing", Title: "missing"},
+		)
+		require.NoError(t, err)
+		return deps, bookmarks
+	}
+
+	t.Run("check bookmark stores the result", func(t *testing.T) {
+		deps, bookmarks := setup(t)
+		checker := deps.Domains().LinkChecker()
+
+		check, err := checker.CheckBookmark(ctx, &bookmarks[1])
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, check.StatusCode)
+
+		checks, err := checker.ListChecks(ctx, bookmarks[1].ID)
+		require.NoError(t, err)
+		require.Len(t, checks, 1)
+		require.Equal(t, model.LinkStatusBroken, checks[0].Status())
+	})
+
+	t.Run("history is pruned", func(t *testing.T) {
+		deps, bookmarks := setup(t)
+		deps.Config().LinkCheck.HistoryKeep = 2
+		checker := deps.Domains().LinkChecker()
+
+		for i := 0; i < 3; i++ {
+			_, err := checker.CheckBookmark(ctx, &bookmarks[0])
+			require.NoError(t, err)
+		}
+
+		checks, err := checker.ListChecks(ctx, bookmarks[0].ID)
+		require.NoError(t, err)
+		require.Len(t, checks, 2)
+	})
+
+	t.Run("check every bookmark", func(t *testing.T) {
+		deps, _ := setup(t)
+		require.NoError(t, deps.Domains().LinkChecker().CheckBookmarks(ctx, nil))
+
+		broken, err := deps.Domains().Bookmarks().ListBookmarks(ctx, model.ListBookmarksOptions{LinkStatus: model.LinkStatusBroken})
+		require.NoError(t, err)
+		require.Len(t, broken, 1)
+		require.Equal(t, "missing", broken[0].Title)
+	})
+
+	t.Run("scheduled checks are queued", func(t *testing.T) {
+		deps, _ := setup(t)
+		deps.Config().LinkCheck.Interval = 10 * time.Millisecond
+		checker := deps.Domains().LinkChecker()
+
+		require.NoError(t, checker.Start(ctx))
+		require.Eventually(t, func() bool {
+			jobs, err := deps.Domains().Jobs().ListJobs(ctx, model.ListJobsOptions{})
+			require.NoError(t, err)
+			return len(jobs) > 0
+		}, 5*time.Second, 10*time.Millisecond)
+		require.NoError(t, checker.Stop(ctx))
+
+		// The job workers are not running, so the first check is still waiting
+		jobs, err := deps.Domains().Jobs().ListJobs(ctx, model.ListJobsOptions{})
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		require.Equal(t, model.JobTypeCheckLinks, jobs[0].Type)
+	})
+}
@@ -0,0 +1,600 @@
+package domains
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/database"
+	"github.com/go-shiori/shiori/internal/dependencies"
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// oidcAuthRequestAudience tells the signed login requests apart from the session tokens
+const oidcAuthRequestAudience = "shiori-oidc"
+
+// oidcAuthRequestLifetime is how long users have to log in with the provider
+const oidcAuthRequestLifetime = 10 * time.Minute
+
+// oidcSigningMethods are the algorithms accepted for ID tokens
+var oidcSigningMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// oidcProvider is the part of the provider metadata used by the login
+type oidcProvider struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// oidcAuthRequest is kept signed in the browser between the redirect to the provider and
+// the callback, so the callback can be checked without storing anything server side.
+type oidcAuthRequest struct {
+	jwt.RegisteredClaims
+
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+
+	// LinkAccountID is the logged in account the identity is linked to, zero for logins
+	LinkAccountID model.DBID `json:"link_account_id,omitempty"`
+}
+
+type oidcJSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type oidcTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	IDToken     string `json:"id_token"`
+}
+
+type OIDCDomain struct {
+	deps   *dependencies.Dependencies
+	client *http.Client
+
+	mu       sync.Mutex
+	provider *oidcProvider
+	keys     map[string]crypto.PublicKey
+}
+
+// AuthorizationURL starts a login, returning the provider URL to send the user to and the
+// signed request to keep in the browser until the callback. When linkAccountID is set, the
+// callback links the identity to that account instead of logging in.
+func (d *OIDCDomain) AuthorizationURL(ctx context.Context, linkAccountID model.DBID) (string, string, error) {
+	provider, err := d.getProvider(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	request := oidcAuthRequest{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{oidcAuthRequestAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(oidcAuthRequestLifetime)),
+		},
+		LinkAccountID: linkAccountID,
+	}
+	for _, value := range []*string{&request.State, &request.Nonce, &request.Verifier} {
+		if *value, err = oidcRandomString(); err != nil {
+			return "", "", err
+		}
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, request).SignedString(d.deps.Config().Http.SecretKey)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to sign login request: %w", err)
+	}
+
+	authURL, err := url.Parse(provider.AuthorizationEndpoint)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid authorization endpoint: %w", err)
+	}
+
+	challenge := sha256.Sum256([]byte(request.Verifier))
+	cfg := d.deps.Config().Http
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", cfg.OIDCClientID)
+	query.Set("redirect_uri", cfg.OIDCRedirectURL)
+	query.Set("scope", strings.Join(cfg.OIDCScopes, " "))
+	query.Set("state", request.State)
+	query.Set("nonce", request.Nonce)
+	query.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+
+	return authURL.String(), signed, nil
+}
+
+// Login completes a login with the code and state sent to the callback, checking them against
+// the request started by AuthorizationURL. The account is created on its first login. It
+// reports true when the request linked the identity to a logged in account instead.
+func (d *OIDCDomain) Login(ctx context.Context, code, state, authRequest string) (*model.AccountDTO, bool, error) {
+	request := oidcAuthRequest{}
+	_, err := jwt.ParseWithClaims(authRequest, &request, func(token *jwt.Token) (interface{}, error) {
+		return d.deps.Config().Http.SecretKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(oidcAuthRequestAudience), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid login request: %w", err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(state), []byte(request.State)) != 1 {
+		return nil, false, fmt.Errorf("login state doesn't match")
+	}
+
+	provider, err := d.getProvider(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	tokens, err := d.exchangeCode(ctx, provider, code, request.Verifier)
+	if err != nil {
+		return nil, false, err
+	}
+
+	claims, err := d.verifyIDToken(ctx, provider, tokens.IDToken)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if nonce, _ := claims["nonce"].(string); subtle.ConstantTimeCompare([]byte(nonce), []byte(request.Nonce)) != 1 {
+		return nil, false, fmt.Errorf("id token nonce doesn't match")
+	}
+
+	cfg := d.deps.Config().Http
+	_, hasUsername := claims[cfg.OIDCUsernameClaim]
+	_, hasOwner := claims[cfg.OIDCOwnerClaim]
+	if provider.UserinfoEndpoint != "" && (!hasUsername || (cfg.OIDCOwnerValue != "" && !hasOwner)) {
+		if err := d.addUserinfoClaims(ctx, provider, tokens.AccessToken, claims); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if request.LinkAccountID != 0 {
+		account, err := d.linkAccount(ctx, provider, claims, request.LinkAccountID)
+		return account, true, err
+	}
+
+	account, err := d.provisionAccount(ctx, provider, claims)
+	return account, false, err
+}
+
+// getProvider returns the provider metadata, discovered on first use.
+func (d *OIDCDomain) getProvider(ctx context.Context) (*oidcProvider, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.provider != nil {
+		return d.provider, nil
+	}
+
+	issuer := strings.TrimSuffix(d.deps.Config().Http.OIDCIssuer, "/")
+	provider := oidcProvider{}
+	if err := d.getJSON(ctx, issuer+"/.well-known/openid-configuration", "", &provider); err != nil {
+		return nil, fmt.Errorf("failed to discover oidc provider: %w", err)
+	}
+
+	if strings.TrimSuffix(provider.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("oidc provider issuer %q doesn't match %q", provider.Issuer, issuer)
+	}
+
+	if provider.AuthorizationEndpoint == "" || provider.TokenEndpoint == "" || provider.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc provider metadata is incomplete")
+	}
+
+	d.provider = &provider
+	return d.provider, nil
+}
+
+// getKey returns the provider key with the given ID, fetching the keys again when it's
+// unknown in case they were rotated.
+func (d *OIDCDomain) getKey(ctx context.Context, provider *oidcProvider, kid string) (crypto.PublicKey, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if key := oidcFindKey(d.keys, kid); key != nil {
+		return key, nil
+	}
+
+	var jwks struct {
+		Keys []oidcJSONWebKey `json:"keys"`
+	}
+	if err := d.getJSON(ctx, provider.JWKSURI, "", &jwks); err != nil {
+		return nil, fmt.Errorf("failed to get oidc provider keys: %w", err)
+	}
+
+	keys := map[string]crypto.PublicKey{}
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			d.deps.Logger().WithError(err).WithField("kid", jwk.Kid).Warn("ignoring oidc provider key")
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	d.keys = keys
+
+	if key := oidcFindKey(d.keys, kid); key != nil {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unknown oidc provider key %q", kid)
+}
+
+// oidcFindKey returns the key with the given ID, or the only key when tokens don't name one.
+func oidcFindKey(keys map[string]crypto.PublicKey, kid string) crypto.PublicKey {
+	if key, ok := keys[kid]; ok {
+		return key
+	}
+
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key
+		}
+	}
+
+	return nil
+}
+
+func (k oidcJSONWebKey) publicKey() (crypto.PublicKey, error) {
+	decode := func(value string) (*big.Int, error) {
+		raw, err := base64.RawURLEncoding.DecodeString(value)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Int).SetBytes(raw), nil
+	}
+
+	switch k.Kty {
+	case "RSA":
+		n, err := decode(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus: %w", err)
+		}
+
+		e, err := decode(k.E)
+		if err != nil || !e.IsInt64() {
+			return nil, fmt.Errorf("invalid exponent")
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		curves := map[string]elliptic.Curve{"P-256": elliptic.P256(), "P-384": elliptic.P384(), "P-521": elliptic.P521()}
+		curve, ok := curves[k.Crv]
+		if !ok {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x coordinate: %w", err)
+		}
+
+		y, err := decode(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y coordinate: %w", err)
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// exchangeCode trades the authorization code for the tokens of the user.
+func (d *OIDCDomain) exchangeCode(ctx context.Context, provider *oidcProvider, code, verifier string) (*oidcTokenResponse, error) {
+	cfg := d.deps.Config().Http
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {cfg.OIDCRedirectURL},
+		"code_verifier": {verifier},
+		"client_id":     {cfg.OIDCClientID},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, provider.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if cfg.OIDCClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(cfg.OIDCClientID), url.QueryEscape(cfg.OIDCClientSecret))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange oidc code: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&failure)
+		return nil, fmt.Errorf("oidc provider refused the code: %s %s %s", resp.Status, failure.Error, failure.Description)
+	}
+
+	tokens := oidcTokenResponse{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&tokens); err != nil {
+		return nil, fmt.Errorf("invalid oidc token response: %w", err)
+	}
+
+	if tokens.IDToken == "" {
+		return nil, fmt.Errorf("oidc provider didn't return an id token")
+	}
+
+	return &tokens, nil
+}
+
+// verifyIDToken checks the signature, issuer, audience and expiration of the ID token and
+// returns its claims.
+func (d *OIDCDomain) verifyIDToken(ctx context.Context, provider *oidcProvider, idToken string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return d.getKey(ctx, provider, kid)
+	},
+		jwt.WithValidMethods(oidcSigningMethods),
+		jwt.WithIssuer(provider.Issuer),
+		jwt.WithAudience(d.deps.Config().Http.OIDCClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id token: %w", err)
+	}
+
+	if subject, _ := claims.GetSubject(); subject == "" {
+		return nil, fmt.Errorf("id token has no subject")
+	}
+
+	return claims, nil
+}
+
+// addUserinfoClaims completes the ID token claims with the ones from the userinfo endpoint,
+// some providers only put the groups there.
+func (d *OIDCDomain) addUserinfoClaims(ctx context.Context, provider *oidcProvider, accessToken string, claims jwt.MapClaims) error {
+	userinfo := map[string]any{}
+	if err := d.getJSON(ctx, provider.UserinfoEndpoint, accessToken, &userinfo); err != nil {
+		return fmt.Errorf("failed to get oidc userinfo: %w", err)
+	}
+
+	if userinfo["sub"] != claims["sub"] {
+		return fmt.Errorf("oidc userinfo subject doesn't match the id token")
+	}
+
+	for name, value := range userinfo {
+		if _, exists := claims[name]; !exists {
+			claims[name] = value
+		}
+	}
+
+	return nil
+}
+
+// provisionAccount returns the account linked to the identity in the claims, creating it on
+// the first login, and keeps its owner flag in sync with the provider when the mapping is
+// configured. Accounts are never matched by username, the claim can be changed by the user
+// on some providers, so existing accounts have to link the identity themselves.
+func (d *OIDCDomain) provisionAccount(ctx context.Context, provider *oidcProvider, claims jwt.MapClaims) (*model.AccountDTO, error) {
+	cfg := d.deps.Config().Http
+
+	var owner *bool
+	if cfg.OIDCOwnerValue != "" {
+		owner = model.Ptr(oidcClaimHas(claims[cfg.OIDCOwnerClaim], cfg.OIDCOwnerValue))
+	}
+
+	subject, _ := claims.GetSubject()
+	identity, exists, err := d.deps.Database().GetAccountIdentity(ctx, provider.Issuer, subject)
+	if err != nil {
+		return nil, fmt.Errorf("error getting account identity: %w", err)
+	}
+
+	if exists {
+		account, exists, err := d.deps.Database().GetAccount(ctx, identity.AccountID)
+		if err != nil {
+			return nil, fmt.Errorf("error getting account: %w", err)
+		}
+		if !exists {
+			return nil, fmt.Errorf("account %d of the identity doesn't exist", identity.AccountID)
+		}
+
+		if owner != nil && *owner != account.Owner {
+			return d.deps.Domains().Accounts().UpdateAccount(ctx, model.AccountDTO{ID: account.ID, Owner: owner})
+		}
+
+		return model.Ptr(account.ToDTO()), nil
+	}
+
+	username, _ := claims[cfg.OIDCUsernameClaim].(string)
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return nil, fmt.Errorf("oidc claims have no %s", cfg.OIDCUsernameClaim)
+	}
+
+	accounts, err := d.deps.Database().ListAccounts(ctx, model.DBListAccountsOptions{Username: username})
+	if err != nil {
+		return nil, fmt.Errorf("error getting accounts: %w", err)
+	}
+
+	if len(accounts) > 0 {
+		return nil, fmt.Errorf("account %s already exists, log in to it to link the identity", username)
+	}
+
+	// The password is never shown, OIDC accounts log in through the provider
+	password, err := oidcRandomString()
+	if err != nil {
+		return nil, err
+	}
+
+	d.deps.Logger().WithField("username", username).Info("creating account for oidc login")
+	account, err := d.deps.Domains().Accounts().CreateAccount(ctx, model.AccountDTO{
+		Username: username,
+		Password: password,
+		Owner:    owner,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = d.deps.Database().CreateAccountIdentity(ctx, model.AccountIdentity{
+		AccountID: account.ID,
+		Issuer:    provider.Issuer,
+		Subject:   subject,
+	})
+	if err != nil {
+		// Without the identity the account could never be logged in to again
+		if err := d.deps.Domains().Accounts().DeleteAccount(ctx, int(account.ID)); err != nil {
+			d.deps.Logger().WithError(err).WithField("username", username).Error("failed to delete account of failed oidc login")
+		}
+		return nil, fmt.Errorf("error creating account identity: %w", err)
+	}
+
+	return account, nil
+}
+
+// linkAccount links the identity in the claims to the account that started the link. The
+// owner flag isn't changed until the next login with the provider.
+func (d *OIDCDomain) linkAccount(ctx context.Context, provider *oidcProvider, claims jwt.MapClaims, accountID model.DBID) (*model.AccountDTO, error) {
+	account, exists, err := d.deps.Database().GetAccount(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting account: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("account %d doesn't exist", accountID)
+	}
+
+	subject, _ := claims.GetSubject()
+	identity, exists, err := d.deps.Database().GetAccountIdentity(ctx, provider.Issuer, subject)
+	if err != nil {
+		return nil, fmt.Errorf("error getting account identity: %w", err)
+	}
+
+	if exists {
+		if identity.AccountID != account.ID {
+			return nil, fmt.Errorf("identity is already linked to another account")
+		}
+		return model.Ptr(account.ToDTO()), nil
+	}
+
+	_, err = d.deps.Database().CreateAccountIdentity(ctx, model.AccountIdentity{
+		AccountID: account.ID,
+		Issuer:    provider.Issuer,
+		Subject:   subject,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating account identity: %w", err)
+	}
+
+	d.deps.Logger().WithField("username", account.Username).Info("linked oidc identity to account")
+	return model.Ptr(account.ToDTO()), nil
+}
+
+// ListIdentities returns the provider identities linked to the account.
+func (d *OIDCDomain) ListIdentities(ctx context.Context, accountID model.DBID) ([]model.AccountIdentity, error) {
+	identities, err := d.deps.Database().ListAccountIdentities(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing account identities: %w", err)
+	}
+
+	return identities, nil
+}
+
+// UnlinkIdentity removes a provider identity from the account, the identity can then be
+// linked again or used to create a new account.
+func (d *OIDCDomain) UnlinkIdentity(ctx context.Context, accountID model.DBID, id model.DBID) error {
+	err := d.deps.Database().DeleteAccountIdentity(ctx, accountID, id)
+	if errors.Is(err, database.ErrNotFound) {
+		return model.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("error deleting account identity: %w", err)
+	}
+
+	return nil
+}
+
+// oidcClaimHas reports whether a claim is the value or a list containing it.
+func oidcClaimHas(claim any, value string) bool {
+	switch v := claim.(type) {
+	case []any:
+		return slices.ContainsFunc(v, func(item any) bool {
+			return fmt.Sprint(item) == value
+		})
+	case nil:
+		return false
+	default:
+		return fmt.Sprint(v) == value
+	}
+}
+
+func (d *OIDCDomain) getJSON(ctx context.Context, url, accessToken string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s from %s", resp.Status, url)
+	}
+
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(target)
+}
+
+func oidcRandomString() (string, error) {
+	value := make([]byte, 32)
+	if _, err := rand.Read(value); err != nil {
+		return "", fmt.Errorf("failed to generate random value: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(value), nil
+}
+
+func NewOIDCDomain(deps *dependencies.Dependencies) *OIDCDomain {
+	return &OIDCDomain{
+		deps:   deps,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
@@ -0,0 +1,198 @@
+package domains_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/domains"
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/shiori/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOIDCDomainLogin(t *testing.T) {
+	ctx := context.TODO()
+	logger := logrus.New()
+	provider := testutil.NewOIDCProvider(t)
+	_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+	provider.Configure(deps.Config().Http)
+	deps.Config().Http.OIDCOwnerValue = "admins"
+	domain := domains.NewOIDCDomain(deps)
+
+	login := func(t *testing.T) (*model.AccountDTO, error) {
+		authURL, authRequest, err := domain.AuthorizationURL(ctx, 0)
+		require.NoError(t, err)
+
+		code, state := provider.Authorize(t, authURL)
+		account, linked, err := domain.Login(ctx, code, state, authRequest)
+		require.False(t, linked)
+		return account, err
+	}
+
+	link := func(t *testing.T, accountID model.DBID) (*model.AccountDTO, error) {
+		authURL, authRequest, err := domain.AuthorizationURL(ctx, accountID)
+		require.NoError(t, err)
+
+		code, state := provider.Authorize(t, authURL)
+		account, linked, err := domain.Login(ctx, code, state, authRequest)
+		if err == nil {
+			require.True(t, linked)
+		}
+		return account, err
+	}
+
+	t.Run("first login creates the account", func(t *testing.T) {
+		provider.Claims = map[string]any{"preferred_username": "alice", "groups": []string{"users"}}
+
+		account, err := login(t)
+		require.NoError(t, err)
+		require.Equal(t, "alice", account.Username)
+		require.False(t, *account.Owner)
+
+		accounts, err := deps.Database().ListAccounts(ctx, model.DBListAccountsOptions{Username: "alice"})
+		require.NoError(t, err)
+		require.Len(t, accounts, 1)
+		require.Equal(t, account.ID, accounts[0].ID)
+
+		identities, err := domain.ListIdentities(ctx, account.ID)
+		require.NoError(t, err)
+		require.Len(t, identities, 1)
+		require.Equal(t, "user-1", identities[0].Subject)
+	})
+
+	t.Run("accounts are matched by subject, not username", func(t *testing.T) {
+		provider.Claims = map[string]any{"preferred_username": "alice-renamed"}
+
+		account, err := login(t)
+		require.NoError(t, err)
+		require.Equal(t, "alice", account.Username)
+	})
+
+	t.Run("owner follows the provider groups", func(t *testing.T) {
+		provider.Claims = map[string]any{"preferred_username": "alice", "groups": []string{"users", "admins"}}
+
+		account, err := login(t)
+		require.NoError(t, err)
+		require.True(t, *account.Owner)
+
+		provider.Claims = map[string]any{"preferred_username": "alice"}
+
+		account, err = login(t)
+		require.NoError(t, err)
+		require.False(t, *account.Owner)
+	})
+
+	t.Run("claims missing from the id token come from userinfo", func(t *testing.T) {
+		provider.Claims = map[string]any{"sub": "user-2"}
+		provider.UserinfoClaims = map[string]any{"preferred_username": "bob", "groups": "admins"}
+		t.Cleanup(func() { provider.UserinfoClaims = map[string]any{} })
+
+		account, err := login(t)
+		require.NoError(t, err)
+		require.Equal(t, "bob", account.Username)
+		require.True(t, *account.Owner)
+	})
+
+	t.Run("existing account with the same username", func(t *testing.T) {
+		owner, err := deps.Domains().Accounts().CreateAccount(ctx, model.AccountDTO{Username: "carol", Password: "password", Owner: model.Ptr(true)})
+		require.NoError(t, err)
+
+		provider.Claims = map[string]any{"sub": "user-3", "preferred_username": "carol", "groups": []string{"admins"}}
+
+		_, err = login(t)
+		require.ErrorContains(t, err, "already exists")
+
+		identities, err := domain.ListIdentities(ctx, owner.ID)
+		require.NoError(t, err)
+		require.Empty(t, identities)
+	})
+
+	t.Run("linked identity logs in to the account", func(t *testing.T) {
+		account, err := deps.Domains().Accounts().CreateAccount(ctx, model.AccountDTO{Username: "dave", Password: "password"})
+		require.NoError(t, err)
+
+		provider.Claims = map[string]any{"sub": "user-4", "preferred_username": "someone-else"}
+
+		linked, err := link(t, account.ID)
+		require.NoError(t, err)
+		require.Equal(t, account.ID, linked.ID)
+		require.False(t, *linked.Owner)
+
+		loggedIn, err := login(t)
+		require.NoError(t, err)
+		require.Equal(t, account.ID, loggedIn.ID)
+
+		identities, err := domain.ListIdentities(ctx, account.ID)
+		require.NoError(t, err)
+		require.Len(t, identities, 1)
+
+		require.ErrorIs(t, domain.UnlinkIdentity(ctx, account.ID+1, identities[0].ID), model.ErrNotFound)
+		require.NoError(t, domain.UnlinkIdentity(ctx, account.ID, identities[0].ID))
+
+		// Once unlinked, the identity gets an account of its own
+		loggedIn, err = login(t)
+		require.NoError(t, err)
+		require.NotEqual(t, account.ID, loggedIn.ID)
+	})
+
+	t.Run("identity linked to another account", func(t *testing.T) {
+		account, err := deps.Domains().Accounts().CreateAccount(ctx, model.AccountDTO{Username: "erin", Password: "password"})
+		require.NoError(t, err)
+
+		provider.Claims = map[string]any{"preferred_username": "alice"}
+
+		_, err = link(t, account.ID)
+		require.ErrorContains(t, err, "another account")
+	})
+
+	t.Run("no username", func(t *testing.T) {
+		provider.Claims = map[string]any{"sub": "user-5"}
+
+		_, err := login(t)
+		require.ErrorContains(t, err, "preferred_username")
+	})
+
+	t.Run("state doesn't match", func(t *testing.T) {
+		provider.Claims = map[string]any{"preferred_username": "alice"}
+
+		authURL, authRequest, err := domain.AuthorizationURL(ctx, 0)
+		require.NoError(t, err)
+		code, _ := provider.Authorize(t, authURL)
+
+		_, _, err = domain.Login(ctx, code, "other", authRequest)
+		require.ErrorContains(t, err, "state")
+	})
+
+	t.Run("code of another login", func(t *testing.T) {
+		provider.Claims = map[string]any{"preferred_username": "alice"}
+
+		authURL, _, err := domain.AuthorizationURL(ctx, 0)
+		require.NoError(t, err)
+		code, _ := provider.Authorize(t, authURL)
+
+		// The verifier of this login doesn't match the challenge of the code
+		otherURL, otherRequest, err := domain.AuthorizationURL(ctx, 0)
+		require.NoError(t, err)
+		_, otherState := provider.Authorize(t, otherURL)
+
+		_, _, err = domain.Login(ctx, code, otherState, otherRequest)
+		require.ErrorContains(t, err, "invalid_grant")
+	})
+
+	t.Run("id token of another client", func(t *testing.T) {
+		provider.Claims = map[string]any{"preferred_username": "alice", "aud": "other"}
+
+		_, err := login(t)
+		require.ErrorContains(t, err, "invalid id token")
+	})
+
+	t.Run("tampered login request", func(t *testing.T) {
+		authURL, authRequest, err := domain.AuthorizationURL(ctx, 0)
+		require.NoError(t, err)
+		code, state := provider.Authorize(t, authURL)
+
+		_, _, err = domain.Login(ctx, code, state, authRequest+"x")
+		require.ErrorContains(t, err, "invalid login request")
+	})
+}
@@ -0,0 +1,162 @@
+package domains
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-shiori/shiori/internal/database"
+	"github.com/go-shiori/shiori/internal/model"
+)
+
+// dryRunPageSize is the number of bookmarks read at once while looking for the ones a rule matches
+const dryRunPageSize = 100
+
+// RulesDomain manages the rules tagging the bookmarks of each account and deciding how they
+// are saved.
+type RulesDomain struct {
+	deps model.Dependencies
+}
+
+// ListRules returns every rule of the account, in the order they are applied.
+func (d *RulesDomain) ListRules(ctx context.Context, account *model.AccountDTO) ([]model.BookmarkRule, error) {
+	return d.deps.Database().ListBookmarkRules(ctx, account.ID)
+}
+
+// GetRule returns a rule of the account.
+func (d *RulesDomain) GetRule(ctx context.Context, account *model.AccountDTO, id model.DBID) (*model.BookmarkRule, error) {
+	rule, exists, err := d.deps.Database().GetBookmarkRule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists || rule.AccountID != account.ID {
+		return nil, model.ErrNotFound
+	}
+
+	return rule, nil
+}
+
+// CreateRule adds a rule to the account, applied after the existing ones.
+func (d *RulesDomain) CreateRule(ctx context.Context, account *model.AccountDTO, rule model.BookmarkRule) (*model.BookmarkRule, error) {
+	rule.AccountID = account.ID
+	rule.Name = strings.TrimSpace(rule.Name)
+	if err := rule.IsValid(); err != nil {
+		return nil, err
+	}
+
+	return d.deps.Database().CreateBookmarkRule(ctx, rule)
+}
+
+// UpdateRule replaces the name, state, conditions and actions of a rule of the account.
+func (d *RulesDomain) UpdateRule(ctx context.Context, account *model.AccountDTO, rule model.BookmarkRule) (*model.BookmarkRule, error) {
+	if _, err := d.GetRule(ctx, account, rule.ID); err != nil {
+		return nil, err
+	}
+
+	rule.AccountID = account.ID
+	rule.Name = strings.TrimSpace(rule.Name)
+	if err := rule.IsValid(); err != nil {
+		return nil, err
+	}
+
+	if err := d.deps.Database().UpdateBookmarkRule(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	return d.GetRule(ctx, account, rule.ID)
+}
+
+// DeleteRule removes a rule of the account.
+func (d *RulesDomain) DeleteRule(ctx context.Context, account *model.AccountDTO, id model.DBID) error {
+	if _, err := d.GetRule(ctx, account, id); err != nil {
+		return err
+	}
+
+	err := d.deps.Database().DeleteBookmarkRule(ctx, id)
+	if errors.Is(err, database.ErrNotFound) {
+		return model.ErrNotFound
+	}
+
+	return err
+}
+
+// Evaluate returns the actions of the enabled rules of the account matching the input, merged
+// in the order the rules were created: the tags of every rule are added, the options of the
+// latest rule win and any rule rejecting the bookmark rejects it.
+func (d *RulesDomain) Evaluate(ctx context.Context, accountID model.DBID, input model.RuleInput) (model.RuleActions, error) {
+	actions := model.RuleActions{}
+	if accountID == 0 {
+		return actions, nil
+	}
+
+	rules, err := d.deps.Database().ListBookmarkRules(ctx, accountID)
+	if err != nil {
+		return actions, err
+	}
+
+	for _, rule := range rules {
+		if rule.Enabled && rule.Conditions.Match(input) {
+			actions = actions.Merge(rule.Actions)
+		}
+	}
+
+	return actions, nil
+}
+
+// Apply evaluates the rules of the account owning the bookmark and applies their actions to it.
+// ErrBookmarkRejected if one of them rejects the bookmark.
+func (d *RulesDomain) Apply(ctx context.Context, bookmark *model.BookmarkDTO, contentType string) error {
+	actions, err := d.Evaluate(ctx, bookmark.AccountID, model.NewRuleInput(*bookmark, contentType))
+	if err != nil {
+		return fmt.Errorf("failed to evaluate rules: %w", err)
+	}
+
+	if actions.Reject {
+		return model.ErrBookmarkRejected
+	}
+
+	actions.ApplyTo(bookmark)
+	return nil
+}
+
+// DryRun returns the bookmarks of the account the conditions of the rule match, without their
+// content. The content type of existing bookmarks isn't known, so a rule with a content type
+// condition matches none of them.
+func (d *RulesDomain) DryRun(ctx context.Context, account *model.AccountDTO, rule model.BookmarkRule) ([]model.BookmarkDTO, error) {
+	if err := rule.Conditions.IsValid(); err != nil {
+		return nil, err
+	}
+
+	matches := []model.BookmarkDTO{}
+	for offset := 0; ; offset += dryRunPageSize {
+		bookmarks, err := d.deps.Database().GetBookmarks(ctx, model.DBGetBookmarksOptions{
+			AccountID:   account.ID,
+			WithContent: true,
+			Limit:       dryRunPageSize,
+			Offset:      offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, bookmark := range bookmarks {
+			if rule.Conditions.Match(model.NewRuleInput(bookmark, "")) {
+				bookmark.Content = ""
+				bookmark.HTML = ""
+				matches = append(matches, bookmark)
+			}
+		}
+
+		if len(bookmarks) < dryRunPageSize {
+			return matches, nil
+		}
+	}
+}
+
+func NewRulesDomain(deps model.Dependencies) *RulesDomain {
+	return &RulesDomain{
+		deps: deps,
+	}
+}
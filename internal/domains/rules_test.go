@@ -0,0 +1,137 @@
+package domains_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/shiori/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRulesDomain(t *testing.T) {
+	ctx := context.Background()
+	logger := logrus.New()
+
+	account := testutil.FakeAccount(false)
+	other := &model.AccountDTO{ID: account.ID + 1, Username: "other"}
+	public := true
+
+	t.Run("rules are scoped to the account", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		rules := deps.Domains().Rules()
+
+		_, err := rules.CreateRule(ctx, account, model.BookmarkRule{Name: "No condition", Actions: model.RuleActions{Reject: true}})
+		require.ErrorAs(t, err, &model.ValidationError{})
+
+		rule, err := rules.CreateRule(ctx, account, model.BookmarkRule{
+			Name:       " Videos ",
+			Enabled:    true,
+			Conditions: model.RuleConditions{Host: "youtube.com"},
+			Actions:    model.RuleActions{AddTags: []string{"video"}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "Videos", rule.Name)
+		require.Equal(t, account.ID, rule.AccountID)
+
+		_, err = rules.GetRule(ctx, other, rule.ID)
+		require.ErrorIs(t, err, model.ErrNotFound)
+		require.ErrorIs(t, rules.DeleteRule(ctx, other, rule.ID), model.ErrNotFound)
+
+		rule.Enabled = false
+		updated, err := rules.UpdateRule(ctx, account, *rule)
+		require.NoError(t, err)
+		require.False(t, updated.Enabled)
+
+		list, err := rules.ListRules(ctx, account)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+
+		require.NoError(t, rules.DeleteRule(ctx, account, rule.ID))
+		_, err = rules.GetRule(ctx, account, rule.ID)
+		require.ErrorIs(t, err, model.ErrNotFound)
+	})
+
+	t.Run("evaluate merges the enabled rules", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		rules := deps.Domains().Rules()
+
+		for _, rule := range []model.BookmarkRule{
+			{Name: "Go", Enabled: true, Conditions: model.RuleConditions{Host: "go.dev"}, Actions: model.RuleActions{AddTags: []string{"golang"}, Public: &public}},
+			{Name: "Blog", Enabled: true, Conditions: model.RuleConditions{URLPattern: "/blog/"}, Actions: model.RuleActions{AddTags: []string{"blog"}}},
+			{Name: "Disabled", Enabled: false, Conditions: model.RuleConditions{Host: "go.dev"}, Actions: model.RuleActions{Reject: true}},
+		} {
+			_, err := rules.CreateRule(ctx, account, rule)
+			require.NoError(t, err)
+		}
+
+		actions, err := rules.Evaluate(ctx, account.ID, model.RuleInput{URL: "https://go.dev/blog/range-functions"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"golang", "blog"}, actions.AddTags)
+		require.True(t, *actions.Public)
+		require.False(t, actions.Reject)
+
+		actions, err = rules.Evaluate(ctx, other.ID, model.RuleInput{URL: "https://go.dev/blog/range-functions"})
+		require.NoError(t, err)
+		require.True(t, actions.IsEmpty())
+	})
+
+	t.Run("created bookmarks are tagged or rejected", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		rules := deps.Domains().Rules()
+
+		_, err := rules.CreateRule(ctx, account, model.BookmarkRule{
+			Name:       "Go",
+			Enabled:    true,
+			Conditions: model.RuleConditions{Host: "go.dev"},
+			Actions:    model.RuleActions{AddTags: []string{"golang"}, Public: &public},
+		})
+		require.NoError(t, err)
+		_, err = rules.CreateRule(ctx, account, model.BookmarkRule{
+			Name:       "No trackers",
+			Enabled:    true,
+			Conditions: model.RuleConditions{Host: "tracker.example.com"},
+			Actions:    model.RuleActions{Reject: true},
+		})
+		require.NoError(t, err)
+
+		created, err := deps.Domains().Bookmarks().CreateBookmark(ctx, model.BookmarkDTO{AccountID: account.ID, URL: "https://go.dev/doc"})
+		require.NoError(t, err)
+		require.Equal(t, 1, created.Public)
+
+		saved, err := deps.Domains().Bookmarks().GetBookmark(ctx, model.DBID(created.ID), account.ID)
+		require.NoError(t, err)
+		require.Len(t, saved.Tags, 1)
+		require.Equal(t, "golang", saved.Tags[0].Name)
+
+		_, err = deps.Domains().Bookmarks().CreateBookmark(ctx, model.BookmarkDTO{AccountID: account.ID, URL: "https://tracker.example.com/pixel"})
+		require.ErrorIs(t, err, model.ErrBookmarkRejected)
+
+		// Rules of an account don't apply to the bookmarks of another one
+		_, err = deps.Domains().Bookmarks().CreateBookmark(ctx, model.BookmarkDTO{AccountID: other.ID, URL: "https://tracker.example.com/pixel"})
+		require.NoError(t, err)
+	})
+
+	t.Run("dry run lists the matching bookmarks", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		_, err := deps.Database().SaveBookmarks(ctx, true,
+			model.BookmarkDTO{AccountID: account.ID, URL: "https://example.com/go", Title: "Go", Content: "Learn about goroutines"},
+			model.BookmarkDTO{AccountID: account.ID, URL: "https://example.com/rust", Title: "Rust", Content: "Learn about ownership"},
+			model.BookmarkDTO{AccountID: other.ID, URL: "https://example.com/theirs", Title: "Theirs", Content: "Goroutines too"},
+		)
+		require.NoError(t, err)
+
+		matches, err := deps.Domains().Rules().DryRun(ctx, account, model.BookmarkRule{
+			Conditions: model.RuleConditions{Keywords: []string{"goroutines"}},
+		})
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		require.Equal(t, "https://example.com/go", matches[0].URL)
+		require.Empty(t, matches[0].Content)
+
+		_, err = deps.Domains().Rules().DryRun(ctx, account, model.BookmarkRule{})
+		require.ErrorAs(t, err, &model.ValidationError{})
+	})
+}
@@ -0,0 +1,126 @@
+package domains
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/database"
+	"github.com/go-shiori/shiori/internal/model"
+)
+
+// SavedSearchesDomain manages the named bookmark searches of each account.
+type SavedSearchesDomain struct {
+	deps model.Dependencies
+}
+
+// ListSearches returns every saved search of the account, ordered by name.
+func (d *SavedSearchesDomain) ListSearches(ctx context.Context, account *model.AccountDTO) ([]model.SavedSearch, error) {
+	return d.deps.Database().ListSavedSearches(ctx, account.ID)
+}
+
+// GetSearch returns a saved search of the account.
+func (d *SavedSearchesDomain) GetSearch(ctx context.Context, account *model.AccountDTO, id model.DBID) (*model.SavedSearch, error) {
+	search, exists, err := d.deps.Database().GetSavedSearch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists || search.AccountID != account.ID {
+		return nil, model.ErrNotFound
+	}
+
+	return search, nil
+}
+
+// CreateSearch saves a search for the account.
+func (d *SavedSearchesDomain) CreateSearch(ctx context.Context, account *model.AccountDTO, search model.SavedSearch) (*model.SavedSearch, error) {
+	search.AccountID = account.ID
+	if err := d.validateSearch(ctx, account, &search); err != nil {
+		return nil, err
+	}
+
+	return d.deps.Database().CreateSavedSearch(ctx, search)
+}
+
+// UpdateSearch replaces the name and filter of a saved search of the account.
+func (d *SavedSearchesDomain) UpdateSearch(ctx context.Context, account *model.AccountDTO, search model.SavedSearch) (*model.SavedSearch, error) {
+	if _, err := d.GetSearch(ctx, account, search.ID); err != nil {
+		return nil, err
+	}
+
+	search.AccountID = account.ID
+	if err := d.validateSearch(ctx, account, &search); err != nil {
+		return nil, err
+	}
+
+	if err := d.deps.Database().UpdateSavedSearch(ctx, search); err != nil {
+		return nil, err
+	}
+
+	return d.GetSearch(ctx, account, search.ID)
+}
+
+// DeleteSearch removes a saved search of the account.
+func (d *SavedSearchesDomain) DeleteSearch(ctx context.Context, account *model.AccountDTO, id model.DBID) error {
+	if _, err := d.GetSearch(ctx, account, id); err != nil {
+		return err
+	}
+
+	err := d.deps.Database().DeleteSavedSearch(ctx, id)
+	if errors.Is(err, database.ErrNotFound) {
+		return model.ErrNotFound
+	}
+
+	return err
+}
+
+// ApplySearch narrows the listing options down to the bookmarks matching a saved search of
+// the account, as of now.
+func (d *SavedSearchesDomain) ApplySearch(ctx context.Context, account *model.AccountDTO, id model.DBID, opts *model.ListBookmarksOptions) error {
+	search, err := d.GetSearch(ctx, account, id)
+	if err != nil {
+		return err
+	}
+
+	search.Filter.ApplyTo(opts, time.Now())
+	return nil
+}
+
+// validateSearch trims the name of the search and checks it is unique in the account, and
+// that the collection of its filter belongs to the account.
+func (d *SavedSearchesDomain) validateSearch(ctx context.Context, account *model.AccountDTO, search *model.SavedSearch) error {
+	search.Name = strings.TrimSpace(search.Name)
+	if err := search.IsValid(); err != nil {
+		return err
+	}
+
+	searches, err := d.deps.Database().ListSavedSearches(ctx, account.ID)
+	if err != nil {
+		return err
+	}
+	for _, other := range searches {
+		if other.ID != search.ID && strings.EqualFold(other.Name, search.Name) {
+			return model.NewValidationError("name", "a saved search with this name already exists")
+		}
+	}
+
+	if search.Filter.CollectionID != nil && *search.Filter.CollectionID != 0 {
+		_, err := d.deps.Domains().Collections().GetCollection(ctx, account, *search.Filter.CollectionID)
+		if errors.Is(err, model.ErrNotFound) {
+			return model.NewValidationError("collection_id", "collection not found")
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func NewSavedSearchesDomain(deps model.Dependencies) *SavedSearchesDomain {
+	return &SavedSearchesDomain{
+		deps: deps,
+	}
+}
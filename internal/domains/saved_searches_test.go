@@ -0,0 +1,87 @@
+package domains_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/shiori/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSavedSearchesDomain(t *testing.T) {
+	ctx := context.Background()
+	logger := logrus.New()
+
+	account := testutil.FakeAccount(false)
+	other := &model.AccountDTO{ID: account.ID + 1, Username: "other"}
+
+	t.Run("searches are scoped to the account", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		searches := deps.Domains().SavedSearches()
+
+		_, err := searches.CreateSearch(ctx, account, model.SavedSearch{Name: "  "})
+		require.ErrorAs(t, err, &model.ValidationError{})
+
+		search, err := searches.CreateSearch(ctx, account, model.SavedSearch{Name: " Go ", Filter: model.SearchFilter{Tags: []string{"golang"}}})
+		require.NoError(t, err)
+		require.Equal(t, "Go", search.Name)
+		require.Equal(t, account.ID, search.AccountID)
+
+		_, err = searches.CreateSearch(ctx, account, model.SavedSearch{Name: "GO"})
+		require.ErrorAs(t, err, &model.ValidationError{})
+
+		_, err = searches.CreateSearch(ctx, other, model.SavedSearch{Name: "Go"})
+		require.NoError(t, err)
+
+		_, err = searches.GetSearch(ctx, other, search.ID)
+		require.ErrorIs(t, err, model.ErrNotFound)
+		require.ErrorIs(t, searches.DeleteSearch(ctx, other, search.ID), model.ErrNotFound)
+
+		search.Filter.Keyword = "generics"
+		updated, err := searches.UpdateSearch(ctx, account, *search)
+		require.NoError(t, err)
+		require.Equal(t, "generics", updated.Filter.Keyword)
+
+		list, err := searches.ListSearches(ctx, account)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+
+		require.NoError(t, searches.DeleteSearch(ctx, account, search.ID))
+		_, err = searches.GetSearch(ctx, account, search.ID)
+		require.ErrorIs(t, err, model.ErrNotFound)
+	})
+
+	t.Run("collection of another account", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		collection, err := deps.Domains().Collections().CreateCollection(ctx, other, model.Collection{Name: "Theirs"})
+		require.NoError(t, err)
+
+		_, err = deps.Domains().SavedSearches().CreateSearch(ctx, account, model.SavedSearch{
+			Name:   "Theirs",
+			Filter: model.SearchFilter{CollectionID: &collection.ID},
+		})
+		require.ErrorAs(t, err, &model.ValidationError{})
+	})
+
+	t.Run("apply narrows the listing", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		searches := deps.Domains().SavedSearches()
+
+		search, err := searches.CreateSearch(ctx, account, model.SavedSearch{
+			Name:   "Recent Go",
+			Filter: model.SearchFilter{Keyword: "generics", Tags: []string{"golang"}, AddedWithinDays: 7},
+		})
+		require.NoError(t, err)
+
+		opts := model.ListBookmarksOptions{AccountID: account.ID, Tags: []string{"blog"}}
+		require.NoError(t, searches.ApplySearch(ctx, account, search.ID, &opts))
+		require.Equal(t, []string{"blog", "golang"}, opts.Tags)
+		require.Equal(t, "generics", opts.Keyword)
+		require.False(t, opts.AddedAfter.IsZero())
+
+		require.ErrorIs(t, searches.ApplySearch(ctx, other, search.ID, &opts), model.ErrNotFound)
+	})
+}
@@ -0,0 +1,342 @@
+package domains
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/core"
+	"github.com/go-shiori/shiori/internal/database"
+	"github.com/go-shiori/shiori/internal/model"
+)
+
+// maxSubscriptionGUIDLength is the longest item GUID stored as is, longer ones are hashed so
+// they fit the indexed column of every database
+const maxSubscriptionGUIDLength = 191
+
+// SubscriptionsDomain follows RSS, Atom and JSON feeds, saving their new items as bookmarks.
+type SubscriptionsDomain struct {
+	deps model.Dependencies
+
+	mu   sync.Mutex
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// CreateSubscription subscribes an account to a feed. The feed is not fetched until it is polled.
+func (d *SubscriptionsDomain) CreateSubscription(ctx context.Context, subscription model.Subscription) (*model.Subscription, error) {
+	subscription.URL = strings.TrimSpace(subscription.URL)
+	subscription.Title = strings.TrimSpace(subscription.Title)
+	subscription.Tags = normalizeSubscriptionTags(subscription.Tags)
+
+	if err := subscription.IsValid(); err != nil {
+		return nil, err
+	}
+
+	existing, err := d.deps.Database().ListSubscriptions(ctx, subscription.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, other := range existing {
+		if other.URL == subscription.URL {
+			return nil, model.NewValidationError("url", "already subscribed to this feed")
+		}
+	}
+
+	return d.deps.Database().CreateSubscription(ctx, subscription)
+}
+
+// GetSubscription returns a subscription of the account, or of any account if zero.
+func (d *SubscriptionsDomain) GetSubscription(ctx context.Context, id model.DBID, accountID model.DBID) (*model.Subscription, error) {
+	subscription, exists, err := d.deps.Database().GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists || (accountID > 0 && subscription.AccountID != accountID) {
+		return nil, model.ErrNotFound
+	}
+
+	return subscription, nil
+}
+
+// ListSubscriptions returns the subscriptions of the account, or of every account if zero.
+func (d *SubscriptionsDomain) ListSubscriptions(ctx context.Context, accountID model.DBID) ([]model.Subscription, error) {
+	return d.deps.Database().ListSubscriptions(ctx, accountID)
+}
+
+// DeleteSubscription unsubscribes from a feed. The bookmarks saved from it are kept.
+func (d *SubscriptionsDomain) DeleteSubscription(ctx context.Context, id model.DBID, accountID model.DBID) error {
+	if err := d.deps.Database().DeleteSubscription(ctx, accountID, id); err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return model.ErrNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Poll fetches the feed of a subscription and saves the items not seen before as bookmarks of
+// its account, unless the account has their URL bookmarked already. Failing to fetch the feed
+// is not an error, it's recorded in the subscription and the result.
+func (d *SubscriptionsDomain) Poll(ctx context.Context, subscription model.Subscription) (*model.PollResult, error) {
+	logger := d.deps.Logger().WithField("subscription_id", subscription.ID)
+	result := &model.PollResult{SubscriptionID: subscription.ID, Created: []int{}}
+
+	feed, err := core.FetchFeed(ctx, subscription.URL, d.deps.Config().Subscriptions.Timeout)
+	subscription.LastPolledAt = model.Ptr(time.Now().UTC().Format(model.DatabaseDateFormat))
+	if err != nil {
+		logger.WithError(err).Warn("failed to fetch subscribed feed")
+		subscription.LastError = err.Error()
+		result.Error = subscription.LastError
+		return result, d.deps.Database().UpdateSubscription(ctx, subscription)
+	}
+
+	subscription.LastError = ""
+	if subscription.Title == "" {
+		subscription.Title = feed.Title
+	}
+
+	seenItems, err := d.deps.Database().GetSubscriptionItems(ctx, subscription.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(seenItems))
+	for _, item := range seenItems {
+		seen[item.GUID] = true
+	}
+
+	// Feeds list the newest items first, they are saved oldest first so they keep that order
+	for _, item := range slices.Backward(feed.Items) {
+		guid := subscriptionItemGUID(item.GUID)
+		if seen[guid] {
+			continue
+		}
+		seen[guid] = true
+
+		// Failed items aren't recorded, so they are tried again on the next poll
+		bookmarkID, created, err := d.saveItem(ctx, subscription, item)
+		if err != nil {
+			logger.WithError(err).WithField("url", item.URL).Warn("failed to save feed item")
+			result.Failed++
+			continue
+		}
+
+		if created {
+			result.Created = append(result.Created, bookmarkID)
+		} else {
+			result.Skipped++
+		}
+
+		if err := d.deps.Database().CreateSubscriptionItems(ctx, model.SubscriptionItem{
+			SubscriptionID: subscription.ID,
+			GUID:           guid,
+			BookmarkID:     bookmarkID,
+		}); err != nil {
+			// The bookmark is saved already, the next poll skips the item as bookmarked
+			logger.WithError(err).WithField("url", item.URL).Warn("failed to record feed item")
+		}
+	}
+
+	if err := d.deps.Database().UpdateSubscription(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	logger.WithField("created", len(result.Created)).WithField("skipped", result.Skipped).WithField("failed", result.Failed).Debug("polled subscribed feed")
+	return result, nil
+}
+
+// saveItem creates the bookmark of a feed item and queues the download of its content, the
+// same way bookmarks added from the API are. It returns the existing bookmark instead if the
+// account has the URL bookmarked already, and no bookmark if a rule of the account rejects it.
+func (d *SubscriptionsDomain) saveItem(ctx context.Context, subscription model.Subscription, item model.ParsedFeedItem) (int, bool, error) {
+	url, err := core.RemoveUTMParams(item.URL)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to clean URL: %w", err)
+	}
+
+	existing, exists, err := d.deps.Database().GetBookmark(ctx, 0, url, subscription.AccountID)
+	if err != nil {
+		return 0, false, err
+	}
+	if exists {
+		return existing.ID, false, nil
+	}
+
+	bookmark := model.BookmarkDTO{
+		AccountID:     subscription.AccountID,
+		URL:           url,
+		Title:         item.Title,
+		Excerpt:       item.Excerpt,
+		CreateArchive: subscription.CreateArchive,
+		CreateEbook:   subscription.CreateEbook,
+	}
+	for _, tag := range subscription.Tags {
+		bookmark.Tags = append(bookmark.Tags, model.TagDTO{Tag: model.Tag{Name: tag}})
+	}
+
+	created, err := d.deps.Domains().Bookmarks().CreateBookmark(ctx, bookmark)
+	if errors.Is(err, model.ErrBookmarkRejected) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	// The feed excerpt is only kept until the content is processed, it's often cut or missing
+	if _, err := d.deps.Domains().Jobs().Enqueue(ctx, model.JobTypeProcessBookmark, model.ProcessBookmarkJobPayload{
+		BookmarkID:    created.ID,
+		KeepTitle:     item.Title != "",
+		CreateArchive: created.CreateArchive,
+		CreateEbook:   created.CreateEbook,
+	}); err != nil {
+		// The bookmark is already saved, its content can be downloaded later with the cache update
+		d.deps.Logger().WithError(err).WithField("id", created.ID).Error("failed to queue bookmark processing")
+	}
+
+	return created.ID, true, nil
+}
+
+// PollSubscriptions polls the subscriptions with the given ids, or every subscription if
+// there are none. A subscription failing doesn't stop the others, its error is in its result.
+func (d *SubscriptionsDomain) PollSubscriptions(ctx context.Context, ids []model.DBID) ([]model.PollResult, error) {
+	subscriptions, err := d.deps.Database().ListSubscriptions(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []model.PollResult{}
+	for _, subscription := range subscriptions {
+		if len(ids) > 0 && !slices.Contains(ids, subscription.ID) {
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
+		result, err := d.Poll(ctx, subscription)
+		if err != nil {
+			d.deps.Logger().WithError(err).WithField("subscription_id", subscription.ID).Error("failed to poll subscription")
+			result = &model.PollResult{SubscriptionID: subscription.ID, Created: []int{}, Error: err.Error()}
+		}
+		results = append(results, *result)
+	}
+
+	return results, nil
+}
+
+// Start queues a poll of every subscription each configured interval, if there is one.
+func (d *SubscriptionsDomain) Start(ctx context.Context) error {
+	interval := d.deps.Config().Subscriptions.PollInterval
+	if interval <= 0 {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.stop != nil {
+		return fmt.Errorf("subscriptions scheduler is already running")
+	}
+
+	d.stop = make(chan struct{})
+	d.deps.Logger().WithField("interval", interval).Info("scheduling feed subscription polls")
+
+	d.wg.Add(1)
+	go d.schedule(interval, d.stop)
+
+	return nil
+}
+
+// Stop ends the periodic polls. Polls already queued are left to the job workers.
+func (d *SubscriptionsDomain) Stop(ctx context.Context) error {
+	d.mu.Lock()
+	if d.stop == nil {
+		d.mu.Unlock()
+		return nil
+	}
+	close(d.stop)
+	d.stop = nil
+	d.mu.Unlock()
+
+	d.wg.Wait()
+	return nil
+}
+
+func (d *SubscriptionsDomain) schedule(interval time.Duration, stop <-chan struct{}) {
+	defer d.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			d.enqueuePoll()
+		}
+	}
+}
+
+// enqueuePoll queues a poll of every subscription unless one is waiting or running already.
+func (d *SubscriptionsDomain) enqueuePoll() {
+	ctx := context.Background()
+	jobs := d.deps.Domains().Jobs()
+
+	queued, err := jobs.ListJobs(ctx, model.ListJobsOptions{
+		Status: []model.JobStatus{model.JobStatusPending, model.JobStatusRunning},
+	})
+	if err != nil {
+		d.deps.Logger().WithError(err).Error("failed to list queued jobs")
+		return
+	}
+
+	for _, job := range queued {
+		if job.Type == model.JobTypePollSubscriptions {
+			return
+		}
+	}
+
+	if _, err := jobs.Enqueue(ctx, model.JobTypePollSubscriptions, model.PollSubscriptionsJobPayload{}); err != nil {
+		d.deps.Logger().WithError(err).Error("failed to queue subscriptions poll")
+	}
+}
+
+// normalizeSubscriptionTags trims the tags, leaving out the empty and repeated ones
+func normalizeSubscriptionTags(tags model.SubscriptionTags) model.SubscriptionTags {
+	normalized := model.SubscriptionTags{}
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+// subscriptionItemGUID returns the GUID an item is stored with
+func subscriptionItemGUID(guid string) string {
+	if len(guid) <= maxSubscriptionGUIDLength {
+		return guid
+	}
+
+	hash := sha256.Sum256([]byte(guid))
+	return "sha256:" + hex.EncodeToString(hash[:])
+}
+
+func NewSubscriptionsDomain(deps model.Dependencies) *SubscriptionsDomain {
+	return &SubscriptionsDomain{
+		deps: deps,
+	}
+}
@@ -0,0 +1,245 @@
+package domains_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/shiori/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// feedServer serves a RSS feed whose items can be changed between polls
+type feedServer struct {
+	*httptest.Server
+
+	mu    sync.Mutex
+	items []string
+}
+
+func newFeedServer(t *testing.T) *feedServer {
+	server := &feedServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/feed.xml" {
+			http.NotFound(w, r)
+			return
+		}
+
+		server.mu.Lock()
+		defer server.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(`<?xml version="1.0"?><rss version="2.0"><channel><title>Test blog</title>` +
+			strings.Join(server.items, "") + `</channel></rss>`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// publish adds an item at the top of the feed
+func (s *feedServer) publish(guid, path, title string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item := `<item><guid>` + guid + `</guid><link>` + s.URL + path + `</link><title>` + title + `</title></item>`
+	s.items = append([]string{item}, s.items...)
+}
+
+func TestSubscriptionsDomain(t *testing.T) {
+	ctx := context.Background()
+	logger := logrus.New()
+
+	t.Run("create validates the subscription", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		subscriptions := deps.Domains().Subscriptions()
+
+		_, err := subscriptions.CreateSubscription(ctx, model.Subscription{AccountID: 1, URL: "ftp://example.com/feed"})
+		require.ErrorAs(t, err, &model.ValidationError{})
+
+		created, err := subscriptions.CreateSubscription(ctx, model.Subscription{
+			AccountID: 1,
+			URL:       " https://example.com/feed ",
+			Tags:      model.SubscriptionTags{" blog ", "", "blog", "go"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "https://example.com/feed", created.URL)
+		require.Equal(t, model.SubscriptionTags{"blog", "go"}, created.Tags)
+
+		_, err = subscriptions.CreateSubscription(ctx, model.Subscription{AccountID: 1, URL: "https://example.com/feed"})
+		require.ErrorAs(t, err, &model.ValidationError{})
+
+		// Other accounts can subscribe to the same feed
+		_, err = subscriptions.CreateSubscription(ctx, model.Subscription{AccountID: 2, URL: "https://example.com/feed"})
+		require.NoError(t, err)
+	})
+
+	t.Run("get and delete are scoped to the account", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		subscriptions := deps.Domains().Subscriptions()
+
+		created, err := subscriptions.CreateSubscription(ctx, model.Subscription{AccountID: 1, URL: "https://example.com/feed"})
+		require.NoError(t, err)
+
+		_, err = subscriptions.GetSubscription(ctx, created.ID, 2)
+		require.ErrorIs(t, err, model.ErrNotFound)
+		require.ErrorIs(t, subscriptions.DeleteSubscription(ctx, created.ID, 2), model.ErrNotFound)
+
+		require.NoError(t, subscriptions.DeleteSubscription(ctx, created.ID, 1))
+		_, err = subscriptions.GetSubscription(ctx, created.ID, 0)
+		require.ErrorIs(t, err, model.ErrNotFound)
+	})
+
+	t.Run("poll saves new items once", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		subscriptions := deps.Domains().Subscriptions()
+		server := newFeedServer(t)
+		server.publish("post-1", "/first?utm_source=rss", "First post")
+		server.publish("post-2", "/second", "Second post")
+
+		subscription, err := subscriptions.CreateSubscription(ctx, model.Subscription{
+			AccountID:     testutil.FakeAccountID,
+			URL:           server.URL + "/feed.xml",
+			Tags:          model.SubscriptionTags{"blog"},
+			CreateArchive: true,
+		})
+		require.NoError(t, err)
+
+		result, err := subscriptions.Poll(ctx, *subscription)
+		require.NoError(t, err)
+		require.Empty(t, result.Error)
+		require.Len(t, result.Created, 2)
+
+		// The oldest item is saved first, with the subscription tags and a clean URL
+		first, err := deps.Domains().Bookmarks().GetBookmark(ctx, model.DBID(result.Created[0]), testutil.FakeAccountID)
+		require.NoError(t, err)
+		require.Equal(t, server.URL+"/first", first.URL)
+		require.Equal(t, "First post", first.Title)
+		require.Len(t, first.Tags, 1)
+		require.Equal(t, "blog", first.Tags[0].Name)
+
+		// Their content is downloaded by the job workers
+		jobs, err := deps.Domains().Jobs().ListJobs(ctx, model.ListJobsOptions{})
+		require.NoError(t, err)
+		require.Len(t, jobs, 2)
+		require.Equal(t, model.JobTypeProcessBookmark, jobs[0].Type)
+		require.Contains(t, jobs[0].Payload, `"create_archive":true`)
+
+		// The feed title is used when the subscription has none
+		subscription, err = subscriptions.GetSubscription(ctx, subscription.ID, 0)
+		require.NoError(t, err)
+		require.Equal(t, "Test blog", subscription.Title)
+		require.NotNil(t, subscription.LastPolledAt)
+
+		server.publish("post-3", "/third", "Third post")
+		result, err = subscriptions.Poll(ctx, *subscription)
+		require.NoError(t, err)
+		require.Len(t, result.Created, 1)
+	})
+
+	t.Run("poll skips urls bookmarked already", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		subscriptions := deps.Domains().Subscriptions()
+		server := newFeedServer(t)
+		server.publish("post-1", "/first", "First post")
+		// The same post under a new GUID
+		server.publish("post-1-updated", "/first", "First post, updated")
+
+		_, err := deps.Domains().Bookmarks().CreateBookmark(ctx, model.BookmarkDTO{AccountID: testutil.FakeAccountID, URL: server.URL + "/first"})
+		require.NoError(t, err)
+
+		subscription, err := subscriptions.CreateSubscription(ctx, model.Subscription{AccountID: testutil.FakeAccountID, URL: server.URL + "/feed.xml"})
+		require.NoError(t, err)
+
+		result, err := subscriptions.Poll(ctx, *subscription)
+		require.NoError(t, err)
+		require.Empty(t, result.Created)
+		require.Equal(t, 2, result.Skipped)
+	})
+
+	t.Run("failing items don't stop the poll", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		subscriptions := deps.Domains().Subscriptions()
+		server := newFeedServer(t)
+		server.publish("post-1", "/first", "First post")
+
+		broken, err := subscriptions.CreateSubscription(ctx, model.Subscription{AccountID: testutil.FakeAccountID + 1, URL: server.URL + "/feed.xml"})
+		require.NoError(t, err)
+		working, err := subscriptions.CreateSubscription(ctx, model.Subscription{AccountID: testutil.FakeAccountID, URL: server.URL + "/feed.xml"})
+		require.NoError(t, err)
+
+		// A rule that can't be read makes saving the bookmarks of its account fail
+		rule, err := deps.Database().CreateBookmarkRule(ctx, model.BookmarkRule{
+			AccountID:  broken.AccountID,
+			Name:       "broken",
+			Enabled:    true,
+			Conditions: model.RuleConditions{Host: "example.com"},
+		})
+		require.NoError(t, err)
+		_, err = deps.Database().WriterDB().ExecContext(ctx, "UPDATE bookmark_rule SET conditions = 'invalid' WHERE id = ?", rule.ID)
+		require.NoError(t, err)
+
+		results, err := subscriptions.PollSubscriptions(ctx, nil)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		for _, result := range results {
+			switch result.SubscriptionID {
+			case broken.ID:
+				require.Equal(t, 1, result.Failed)
+				require.Empty(t, result.Created)
+			case working.ID:
+				require.Equal(t, 0, result.Failed)
+				require.Len(t, result.Created, 1)
+			}
+		}
+
+		// Failed items are tried again
+		require.NoError(t, deps.Database().DeleteBookmarkRule(ctx, rule.ID))
+		result, err := subscriptions.Poll(ctx, *broken)
+		require.NoError(t, err)
+		require.Equal(t, 0, result.Failed)
+		require.Len(t, result.Created, 1)
+	})
+
+	t.Run("poll records fetch errors", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		subscriptions := deps.Domains().Subscriptions()
+		server := newFeedServer(t)
+
+		subscription, err := subscriptions.CreateSubscription(ctx, model.Subscription{AccountID: testutil.FakeAccountID, URL: server.URL + "/missing.xml"})
+		require.NoError(t, err)
+
+		results, err := subscriptions.PollSubscriptions(ctx, nil)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Contains(t, results[0].Error, "404")
+
+		subscription, err = subscriptions.GetSubscription(ctx, subscription.ID, 0)
+		require.NoError(t, err)
+		require.Contains(t, subscription.LastError, "404")
+	})
+
+	t.Run("scheduled polls are queued", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		deps.Config().Subscriptions.PollInterval = 10 * time.Millisecond
+		subscriptions := deps.Domains().Subscriptions()
+
+		require.NoError(t, subscriptions.Start(ctx))
+		require.Eventually(t, func() bool {
+			jobs, err := deps.Domains().Jobs().ListJobs(ctx, model.ListJobsOptions{})
+			require.NoError(t, err)
+			return len(jobs) > 0
+		}, 5*time.Second, 10*time.Millisecond)
+		require.NoError(t, subscriptions.Stop(ctx))
+
+		jobs, err := deps.Domains().Jobs().ListJobs(ctx, model.ListJobsOptions{})
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		require.Equal(t, model.JobTypePollSubscriptions, jobs[0].Type)
+	})
+}
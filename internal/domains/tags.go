@@ -3,6 +3,7 @@ package domains
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/go-shiori/shiori/internal/database"
 	"github.com/go-shiori/shiori/internal/model"
@@ -27,11 +28,17 @@ func (d *tagsDomain) ListTags(ctx context.Context, opts model.ListTagsOptions) (
 
 func (d *tagsDomain) CreateTag(ctx context.Context, tagDTO model.TagDTO) (model.TagDTO, error) {
 	tag := tagDTO.ToTag()
+	if err := d.validateTag(ctx, tag); err != nil {
+		return model.TagDTO{}, err
+	}
+
 	createdTag, err := d.deps.Database().CreateTag(ctx, tag)
 	if err != nil {
 		return model.TagDTO{}, err
 	}
 
+	d.deps.Domains().Webhooks().Emit(ctx, model.WebhookEventTagCreated, 0, createdTag)
+
 	return createdTag.ToDTO(), nil
 }
 
@@ -48,6 +55,14 @@ func (d *tagsDomain) GetTag(ctx context.Context, id int) (model.TagDTO, error) {
 
 func (d *tagsDomain) UpdateTag(ctx context.Context, tagDTO model.TagDTO) (model.TagDTO, error) {
 	tag := tagDTO.ToTag()
+	if _, err := d.GetTag(ctx, tag.ID); err != nil {
+		return model.TagDTO{}, err
+	}
+
+	if err := d.validateTag(ctx, tag); err != nil {
+		return model.TagDTO{}, err
+	}
+
 	err := d.deps.Database().UpdateTag(ctx, tag)
 	if err != nil {
 		if errors.Is(err, database.ErrNotFound) {
@@ -66,6 +81,12 @@ func (d *tagsDomain) UpdateTag(ctx context.Context, tagDTO model.TagDTO) (model.
 }
 
 func (d *tagsDomain) DeleteTag(ctx context.Context, id int) error {
+	// Fetched first so the webhooks get the name of the deleted tag
+	tag, err := d.GetTag(ctx, id)
+	if err != nil {
+		return err
+	}
+
 	if err := d.deps.Database().DeleteTag(ctx, id); err != nil {
 		if errors.Is(err, database.ErrNotFound) {
 			return model.ErrNotFound
@@ -73,6 +94,8 @@ func (d *tagsDomain) DeleteTag(ctx context.Context, id int) error {
 		return err
 	}
 
+	d.deps.Domains().Webhooks().Emit(ctx, model.WebhookEventTagDeleted, 0, tag.ToTag())
+
 	return nil
 }
 
@@ -80,3 +103,126 @@ func (d *tagsDomain) DeleteTag(ctx context.Context, id int) error {
 func (d *tagsDomain) TagExists(ctx context.Context, id int) (bool, error) {
 	return d.deps.Database().TagExists(ctx, id)
 }
+
+// MergeTags folds the source tags into the target: their bookmarks, aliases and children move
+// to the target and their names become aliases of it.
+func (d *tagsDomain) MergeTags(ctx context.Context, targetID int, sourceIDs []int) (model.TagDTO, error) {
+	if len(sourceIDs) == 0 {
+		return model.TagDTO{}, model.NewValidationError("tag_ids", "at least one tag should be merged")
+	}
+
+	target, err := d.GetTag(ctx, targetID)
+	if err != nil {
+		return model.TagDTO{}, err
+	}
+
+	sources := make([]model.TagDTO, 0, len(sourceIDs))
+	for _, id := range sourceIDs {
+		if id == targetID {
+			return model.TagDTO{}, model.NewValidationError("tag_ids", "a tag can't be merged into itself")
+		}
+
+		source, err := d.GetTag(ctx, id)
+		if errors.Is(err, model.ErrNotFound) {
+			return model.TagDTO{}, model.NewValidationError("tag_ids", fmt.Sprintf("tag %d not found", id))
+		}
+		if err != nil {
+			return model.TagDTO{}, err
+		}
+		sources = append(sources, source)
+	}
+
+	if err := d.deps.Database().MergeTags(ctx, target.ID, sourceIDs...); err != nil {
+		return model.TagDTO{}, err
+	}
+
+	for _, source := range sources {
+		d.deps.Domains().Webhooks().Emit(ctx, model.WebhookEventTagDeleted, 0, source.ToTag())
+	}
+
+	return d.GetTag(ctx, target.ID)
+}
+
+// ListTagAliases returns the aliases of a tag.
+func (d *tagsDomain) ListTagAliases(ctx context.Context, tagID int) ([]model.TagAlias, error) {
+	if _, err := d.GetTag(ctx, tagID); err != nil {
+		return nil, err
+	}
+
+	return d.deps.Database().GetTagAliases(ctx, tagID)
+}
+
+// CreateTagAlias adds another name to a tag. The name can't be used by a tag or an alias.
+func (d *tagsDomain) CreateTagAlias(ctx context.Context, alias model.TagAlias) (model.TagAlias, error) {
+	if _, err := d.GetTag(ctx, alias.TagID); err != nil {
+		return model.TagAlias{}, err
+	}
+
+	alias.Name = model.NormalizeTagName(alias.Name)
+	if alias.Name == "" {
+		return model.TagAlias{}, model.NewValidationError("name", "alias name should not be empty")
+	}
+
+	if _, exists, err := d.deps.Database().GetTagByName(ctx, alias.Name); err != nil {
+		return model.TagAlias{}, err
+	} else if exists {
+		return model.TagAlias{}, model.NewValidationError("name", "a tag or alias with this name already exists")
+	}
+
+	return d.deps.Database().CreateTagAlias(ctx, alias)
+}
+
+// DeleteTagAlias removes an alias of a tag.
+func (d *tagsDomain) DeleteTagAlias(ctx context.Context, tagID int, aliasID int) error {
+	aliases, err := d.ListTagAliases(ctx, tagID)
+	if err != nil {
+		return err
+	}
+
+	for _, alias := range aliases {
+		if alias.ID != aliasID {
+			continue
+		}
+
+		err := d.deps.Database().DeleteTagAlias(ctx, aliasID)
+		if errors.Is(err, database.ErrNotFound) {
+			return model.ErrNotFound
+		}
+		return err
+	}
+
+	return model.ErrNotFound
+}
+
+// validateTag checks the name of a tag isn't used by another tag or alias, and that its parent
+// exists without being one of its descendants.
+func (d *tagsDomain) validateTag(ctx context.Context, tag model.Tag) error {
+	if existing, exists, err := d.deps.Database().GetTagByName(ctx, tag.Name); err != nil {
+		return err
+	} else if exists && existing.ID != tag.ID {
+		return model.NewValidationError("name", "a tag or alias with this name already exists")
+	}
+
+	if tag.ParentID == nil {
+		return nil
+	}
+
+	// Walk up from the parent, reaching the tag means it would be its own ancestor
+	for current := *tag.ParentID; ; {
+		if current == tag.ID {
+			return model.NewValidationError("parent_id", "a tag can't be its own ancestor")
+		}
+
+		parent, exists, err := d.deps.Database().GetTag(ctx, current)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return model.NewValidationError("parent_id", "parent tag not found")
+		}
+		if parent.ParentID == nil {
+			return nil
+		}
+		current = *parent.ParentID
+	}
+}
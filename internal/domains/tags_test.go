@@ -276,3 +276,87 @@ func TestTagsDomain(t *testing.T) {
 			"Expected error to be or contain 'not found', got: %v", err)
 	})
 }
+
+func TestTagsDomain_MergeAndAliases(t *testing.T) {
+	ctx := context.Background()
+	logger := logrus.New()
+
+	t.Run("names are unique among tags and aliases", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		tags := deps.Domains().Tags()
+
+		kubernetes, err := tags.CreateTag(ctx, model.TagDTO{Tag: model.Tag{Name: "kubernetes"}})
+		require.NoError(t, err)
+
+		alias, err := tags.CreateTagAlias(ctx, model.TagAlias{Name: " K8s ", TagID: kubernetes.ID})
+		require.NoError(t, err)
+		require.Equal(t, "k8s", alias.Name)
+
+		_, err = tags.CreateTagAlias(ctx, model.TagAlias{Name: "kubernetes", TagID: kubernetes.ID})
+		require.ErrorAs(t, err, &model.ValidationError{})
+
+		_, err = tags.CreateTag(ctx, model.TagDTO{Tag: model.Tag{Name: "k8s"}})
+		require.ErrorAs(t, err, &model.ValidationError{})
+
+		_, err = tags.CreateTagAlias(ctx, model.TagAlias{Name: "docker", TagID: 9999})
+		require.ErrorIs(t, err, model.ErrNotFound)
+
+		require.ErrorIs(t, tags.DeleteTagAlias(ctx, kubernetes.ID+1, alias.ID), model.ErrNotFound)
+		require.NoError(t, tags.DeleteTagAlias(ctx, kubernetes.ID, alias.ID))
+
+		aliases, err := tags.ListTagAliases(ctx, kubernetes.ID)
+		require.NoError(t, err)
+		require.Empty(t, aliases)
+	})
+
+	t.Run("parents can't form a cycle", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		tags := deps.Domains().Tags()
+
+		parent, err := tags.CreateTag(ctx, model.TagDTO{Tag: model.Tag{Name: "programming"}})
+		require.NoError(t, err)
+		child, err := tags.CreateTag(ctx, model.TagDTO{Tag: model.Tag{Name: "go", ParentID: &parent.ID}})
+		require.NoError(t, err)
+		require.Equal(t, &parent.ID, child.ParentID)
+
+		parent.ParentID = &child.ID
+		_, err = tags.UpdateTag(ctx, parent)
+		require.ErrorAs(t, err, &model.ValidationError{})
+
+		_, err = tags.CreateTag(ctx, model.TagDTO{Tag: model.Tag{Name: "rust", ParentID: model.Ptr(9999)}})
+		require.ErrorAs(t, err, &model.ValidationError{})
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		tags := deps.Domains().Tags()
+
+		_, err := deps.Database().SaveBookmarks(ctx, true,
+			model.BookmarkDTO{URL: "https://go.dev", Title: "Go", Tags: []model.TagDTO{{Tag: model.Tag{Name: "go"}}}},
+			model.BookmarkDTO{URL: "https://pkg.go.dev", Title: "Packages", Tags: []model.TagDTO{{Tag: model.Tag{Name: "golang"}}}},
+		)
+		require.NoError(t, err)
+
+		target, _, err := deps.Database().GetTagByName(ctx, "go")
+		require.NoError(t, err)
+		source, _, err := deps.Database().GetTagByName(ctx, "golang")
+		require.NoError(t, err)
+
+		_, err = tags.MergeTags(ctx, target.ID, []int{target.ID})
+		require.ErrorAs(t, err, &model.ValidationError{})
+
+		_, err = tags.MergeTags(ctx, target.ID, []int{9999})
+		require.ErrorAs(t, err, &model.ValidationError{})
+
+		merged, err := tags.MergeTags(ctx, target.ID, []int{source.ID})
+		require.NoError(t, err)
+		require.EqualValues(t, 2, merged.BookmarkCount)
+
+		_, err = tags.GetTag(ctx, source.ID)
+		require.ErrorIs(t, err, model.ErrNotFound)
+
+		bookmarks, err := deps.Domains().Bookmarks().ListBookmarks(ctx, model.ListBookmarksOptions{Tags: []string{"golang"}})
+		require.NoError(t, err)
+		require.Len(t, bookmarks, 2)
+	})
+}
@@ -0,0 +1,421 @@
+package domains
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/dependencies"
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// totpPeriod and totpDigits are the RFC 6238 defaults, the only ones most apps support
+	totpPeriod = 30
+	totpDigits = 6
+	// totpSkew is the number of periods accepted before and after the current one
+	totpSkew = 1
+	// totpIssuer names the account in authenticator apps
+	totpIssuer = "Shiori"
+	// totpRecoveryCodes is the number of recovery codes generated when the second factor is enabled
+	totpRecoveryCodes = 10
+
+	// totpChallengeAudience tells the login challenges apart from the session tokens
+	totpChallengeAudience = "shiori-totp"
+	// totpChallengeLifetime is how long users have to enter their code once the password is checked
+	totpChallengeLifetime = 5 * time.Minute
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// totpChallenge is handed to the client once the password is checked, the login is completed
+// by sending it back along with a code.
+type totpChallenge struct {
+	jwt.RegisteredClaims
+
+	RememberMe bool `json:"remember_me"`
+}
+
+type TOTPDomain struct {
+	deps *dependencies.Dependencies
+}
+
+// GetStatus returns the second factor state of an account.
+func (d *TOTPDomain) GetStatus(ctx context.Context, account *model.AccountDTO) (*model.TOTPStatus, error) {
+	status := model.TOTPStatus{}
+
+	totp, exists, err := d.deps.Database().GetAccountTOTP(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if exists && totp.Enabled {
+		status.Enabled = true
+		status.RecoveryCodesLeft = len(totp.RecoveryCodes)
+	}
+
+	if status.Required, err = d.IsRequired(ctx, account); err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+// IsRequired reports whether the account must use a second factor.
+func (d *TOTPDomain) IsRequired(ctx context.Context, account *model.AccountDTO) (bool, error) {
+	if !account.IsOwner() {
+		return false, nil
+	}
+
+	value, _, err := d.deps.Database().GetSetting(ctx, model.SettingOwnerTOTPRequired)
+	if err != nil {
+		return false, err
+	}
+
+	return value == "true", nil
+}
+
+// SetOwnersRequired makes the second factor mandatory for owner accounts, or optional again.
+// The owner making it mandatory must have enabled it first so they aren't locked out.
+func (d *TOTPDomain) SetOwnersRequired(ctx context.Context, account *model.AccountDTO, required bool) error {
+	if required {
+		status, err := d.GetStatus(ctx, account)
+		if err != nil {
+			return err
+		}
+
+		if !status.Enabled {
+			return model.NewValidationError("required", "enable two-factor authentication on your account first")
+		}
+	}
+
+	return d.deps.Database().SetSetting(ctx, model.SettingOwnerTOTPRequired, strconv.FormatBool(required))
+}
+
+// Enroll generates a new secret for the account. It isn't used to log in until Enable
+// confirms a code generated from it.
+func (d *TOTPDomain) Enroll(ctx context.Context, account *model.AccountDTO) (*model.TOTPEnrollment, error) {
+	// Secrets are encrypted with the secret key, a random one would lock the account out on restart
+	if d.deps.Config().Http.SecretKeyRandom {
+		d.deps.Logger().Error("SHIORI_HTTP_SECRET_KEY must be set to enable two-factor authentication")
+		return nil, model.NewValidationError("totp", "two-factor authentication can't be enabled until the server has a secret key configured")
+	}
+
+	existing, exists, err := d.deps.Database().GetAccountTOTP(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if exists && existing.Enabled {
+		return nil, model.NewValidationError("totp", "two-factor authentication is already enabled")
+	}
+
+	raw := make([]byte, 20)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate totp secret: %w", err)
+	}
+	secret := totpEncoding.EncodeToString(raw)
+
+	encrypted, err := d.encrypt(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.deps.Database().SaveAccountTOTP(ctx, model.AccountTOTP{
+		AccountID: account.ID,
+		Secret:    encrypted,
+	}); err != nil {
+		return nil, err
+	}
+
+	uri := url.URL{
+		Scheme: "otpauth",
+		Host:   "totp",
+		Path:   "/" + totpIssuer + ":" + account.Username,
+		RawQuery: url.Values{
+			"secret":    {secret},
+			"issuer":    {totpIssuer},
+			"algorithm": {"SHA1"},
+			"digits":    {strconv.Itoa(totpDigits)},
+			"period":    {strconv.Itoa(totpPeriod)},
+		}.Encode(),
+	}
+
+	return &model.TOTPEnrollment{Secret: secret, URI: uri.String()}, nil
+}
+
+// Enable turns on the second factor enrolled for the account once the code generated from
+// its secret is checked, and returns the recovery codes. They are only shown this once.
+func (d *TOTPDomain) Enable(ctx context.Context, accountID model.DBID, code string) ([]string, error) {
+	totp, exists, err := d.deps.Database().GetAccountTOTP(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists || totp.Enabled {
+		return nil, model.NewValidationError("totp", "two-factor authentication is not being set up")
+	}
+
+	step, err := d.checkCode(totp, code)
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, totpRecoveryCodes)
+	totp.RecoveryCodes = make(model.TOTPRecoveryCodes, 0, totpRecoveryCodes)
+	for range totpRecoveryCodes {
+		raw := make([]byte, 10)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+
+		code := totpEncoding.EncodeToString(raw)
+		codes = append(codes, code[:4]+"-"+code[4:8]+"-"+code[8:12]+"-"+code[12:])
+		totp.RecoveryCodes = append(totp.RecoveryCodes, totpHashRecoveryCode(code))
+	}
+
+	totp.Enabled = true
+	totp.LastUsedStep = step
+	if err := d.deps.Database().SaveAccountTOTP(ctx, *totp); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Disable turns off the second factor of the account after checking a code or a recovery
+// code. Owners can't turn it off while it is mandatory for them.
+func (d *TOTPDomain) Disable(ctx context.Context, account *model.AccountDTO, code string) error {
+	required, err := d.IsRequired(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	if required {
+		return model.NewValidationError("totp", "two-factor authentication is required for owners")
+	}
+
+	if err := d.Verify(ctx, account.ID, code); err != nil {
+		return err
+	}
+
+	return d.deps.Database().DeleteAccountTOTP(ctx, account.ID)
+}
+
+// Verify checks a code from the authenticator app or a recovery code of the account. Each
+// of them can only be used once.
+func (d *TOTPDomain) Verify(ctx context.Context, accountID model.DBID, code string) error {
+	totp, exists, err := d.deps.Database().GetAccountTOTP(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	if !exists || !totp.Enabled {
+		return model.NewValidationError("totp", "two-factor authentication is not enabled")
+	}
+
+	code = strings.ReplaceAll(code, " ", "")
+	if len(code) != totpDigits {
+		return d.useRecoveryCode(ctx, totp, code)
+	}
+
+	step, err := d.checkCode(totp, code)
+	if err != nil {
+		return err
+	}
+
+	used, err := d.deps.Database().UpdateAccountTOTPStep(ctx, accountID, step)
+	if err != nil {
+		return err
+	}
+
+	if !used {
+		return model.NewValidationError("code", "code was already used")
+	}
+
+	return nil
+}
+
+// CreateLoginChallenge returns the challenge completing the login of an account with a code.
+func (d *TOTPDomain) CreateLoginChallenge(account *model.AccountDTO, rememberMe bool) (string, error) {
+	challenge := totpChallenge{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.Itoa(int(account.ID)),
+			Audience:  jwt.ClaimStrings{totpChallengeAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(totpChallengeLifetime)),
+		},
+		RememberMe: rememberMe,
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, challenge).SignedString(d.deps.Config().Http.SecretKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign login challenge: %w", err)
+	}
+
+	return signed, nil
+}
+
+// CheckLoginChallenge returns the account a login challenge was created for, and whether
+// the login should be remembered.
+func (d *TOTPDomain) CheckLoginChallenge(ctx context.Context, challenge string) (*model.AccountDTO, bool, error) {
+	claims := totpChallenge{}
+	_, err := jwt.ParseWithClaims(challenge, &claims, func(token *jwt.Token) (interface{}, error) {
+		return d.deps.Config().Http.SecretKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(totpChallengeAudience), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, false, model.NewValidationError("challenge", "login expired, please log in again")
+	}
+
+	accountID, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return nil, false, model.NewValidationError("challenge", "invalid login challenge")
+	}
+
+	account, exists, err := d.deps.Database().GetAccount(ctx, model.DBID(accountID))
+	if err != nil || !exists {
+		return nil, false, model.NewValidationError("challenge", "invalid login challenge")
+	}
+
+	return model.Ptr(account.ToDTO()), claims.RememberMe, nil
+}
+
+// checkCode returns the time step of the code if it is valid for the secret.
+func (d *TOTPDomain) checkCode(totp *model.AccountTOTP, code string) (int64, error) {
+	encoded, err := d.decrypt(totp.Secret)
+	if err != nil {
+		return 0, err
+	}
+
+	secret, err := totpEncoding.DecodeString(encoded)
+	if err != nil {
+		return 0, fmt.Errorf("invalid totp secret: %w", err)
+	}
+
+	code = strings.ReplaceAll(code, " ", "")
+	current := time.Now().Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if subtle.ConstantTimeCompare([]byte(totpCode(secret, step)), []byte(code)) == 1 {
+			return step, nil
+		}
+	}
+
+	return 0, model.NewValidationError("code", "invalid code")
+}
+
+// useRecoveryCode removes the code from the recovery codes of the account. The codes are only
+// replaced if no other login changed them meanwhile, so a code can't be used twice at once.
+func (d *TOTPDomain) useRecoveryCode(ctx context.Context, totp *model.AccountTOTP, code string) error {
+	hash := totpHashRecoveryCode(code)
+	for {
+		i := slices.Index(totp.RecoveryCodes, hash)
+		if i < 0 {
+			return model.NewValidationError("code", "invalid code")
+		}
+
+		remaining := slices.Delete(slices.Clone(totp.RecoveryCodes), i, i+1)
+		updated, err := d.deps.Database().UpdateAccountTOTPRecoveryCodes(ctx, totp.AccountID, totp.RecoveryCodes, remaining)
+		if err != nil {
+			return err
+		}
+		if updated {
+			return nil
+		}
+
+		// Another code was used meanwhile, try again with the codes left
+		var exists bool
+		totp, exists, err = d.deps.Database().GetAccountTOTP(ctx, totp.AccountID)
+		if err != nil {
+			return err
+		}
+		if !exists || !totp.Enabled {
+			return model.NewValidationError("totp", "two-factor authentication is not enabled")
+		}
+	}
+}
+
+// totpCode is the RFC 4226 HOTP value of the secret for a time step.
+func totpCode(secret []byte, step int64) string {
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(step))
+
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000)
+}
+
+// totpHashRecoveryCode hashes a recovery code, ignoring its case and dashes.
+func totpHashRecoveryCode(code string) string {
+	code = strings.ToUpper(strings.ReplaceAll(code, "-", ""))
+	hash := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(hash[:])
+}
+
+// secretCipher returns the AES-GCM cipher the secrets are encrypted with, keyed by the server secret key.
+func (d *TOTPDomain) secretCipher() (cipher.AEAD, error) {
+	key := sha256.Sum256(d.deps.Config().Http.SecretKey)
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (d *TOTPDomain) encrypt(secret string) (string, error) {
+	aead, err := d.secretCipher()
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt totp secret: %w", err)
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to encrypt totp secret: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(secret), nil)), nil
+}
+
+func (d *TOTPDomain) decrypt(encrypted string) (string, error) {
+	aead, err := d.secretCipher()
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt totp secret: %w", err)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil || len(raw) < aead.NonceSize() {
+		return "", fmt.Errorf("failed to decrypt totp secret: invalid value")
+	}
+
+	secret, err := aead.Open(nil, raw[:aead.NonceSize()], raw[aead.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt totp secret, was the secret key changed? %w", err)
+	}
+
+	return string(secret), nil
+}
+
+func NewTOTPDomain(deps *dependencies.Dependencies) *TOTPDomain {
+	return &TOTPDomain{
+		deps: deps,
+	}
+}
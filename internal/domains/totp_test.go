@@ -0,0 +1,182 @@
+package domains_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/domains"
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/shiori/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTOTPDomain(t *testing.T) {
+	ctx := context.TODO()
+	logger := logrus.New()
+	_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+	domain := domains.NewTOTPDomain(deps)
+
+	account, err := deps.Domains().Accounts().CreateAccount(ctx, model.AccountDTO{
+		Username: "test",
+		Password: "test",
+		Owner:    model.Ptr(true),
+	})
+	require.NoError(t, err)
+
+	_, err = domain.Enable(ctx, account.ID, "123456")
+	require.Error(t, err, "nothing to enable before enrolling")
+
+	enrollment, err := domain.Enroll(ctx, account)
+	require.NoError(t, err)
+
+	uri, err := url.Parse(enrollment.URI)
+	require.NoError(t, err)
+	require.Equal(t, "otpauth", uri.Scheme)
+	require.Equal(t, "/Shiori:test", uri.Path)
+	require.Equal(t, enrollment.Secret, uri.Query().Get("secret"))
+
+	t.Run("secret is encrypted", func(t *testing.T) {
+		totp, exists, err := deps.Database().GetAccountTOTP(ctx, account.ID)
+		require.NoError(t, err)
+		require.True(t, exists)
+		require.NotContains(t, totp.Secret, enrollment.Secret)
+		require.False(t, totp.Enabled)
+	})
+
+	_, err = domain.Enable(ctx, account.ID, "000000")
+	require.Error(t, err)
+
+	code := testutil.TOTPCode(t, enrollment.Secret, time.Now())
+	recoveryCodes, err := domain.Enable(ctx, account.ID, code)
+	require.NoError(t, err)
+	require.Len(t, recoveryCodes, 10)
+
+	_, err = domain.Enroll(ctx, account)
+	require.Error(t, err, "already enabled")
+
+	t.Run("codes are only used once", func(t *testing.T) {
+		require.Error(t, domain.Verify(ctx, account.ID, code))
+
+		next := testutil.TOTPCode(t, enrollment.Secret, time.Now().Add(30*time.Second))
+		require.NoError(t, domain.Verify(ctx, account.ID, next))
+		require.Error(t, domain.Verify(ctx, account.ID, next))
+	})
+
+	t.Run("codes out of the time window are refused", func(t *testing.T) {
+		require.Error(t, domain.Verify(ctx, account.ID, testutil.TOTPCode(t, enrollment.Secret, time.Now().Add(-5*time.Minute))))
+	})
+
+	t.Run("recovery codes are only used once", func(t *testing.T) {
+		require.NoError(t, domain.Verify(ctx, account.ID, recoveryCodes[0]))
+		require.Error(t, domain.Verify(ctx, account.ID, recoveryCodes[0]))
+
+		status, err := domain.GetStatus(ctx, account)
+		require.NoError(t, err)
+		require.True(t, status.Enabled)
+		require.Equal(t, 9, status.RecoveryCodesLeft)
+	})
+
+	t.Run("recovery codes used at once", func(t *testing.T) {
+		codes := []string{recoveryCodes[2], recoveryCodes[3], recoveryCodes[4], recoveryCodes[4]}
+		errs := make(chan error, len(codes))
+		for _, recoveryCode := range codes {
+			go func() {
+				errs <- domain.Verify(ctx, account.ID, recoveryCode)
+			}()
+		}
+
+		failed := 0
+		for range codes {
+			if err := <-errs; err != nil {
+				require.ErrorContains(t, err, "invalid code")
+				failed++
+			}
+		}
+		require.Equal(t, 1, failed, "the repeated code is only accepted once")
+
+		status, err := domain.GetStatus(ctx, account)
+		require.NoError(t, err)
+		require.Equal(t, 6, status.RecoveryCodesLeft)
+	})
+
+	t.Run("required for owners", func(t *testing.T) {
+		require.NoError(t, domain.SetOwnersRequired(ctx, account, true))
+
+		required, err := domain.IsRequired(ctx, account)
+		require.NoError(t, err)
+		require.True(t, required)
+
+		user := model.AccountDTO{ID: account.ID + 1, Owner: model.Ptr(false)}
+		required, err = domain.IsRequired(ctx, &user)
+		require.NoError(t, err)
+		require.False(t, required)
+
+		require.Error(t, domain.Disable(ctx, account, recoveryCodes[1]), "owners can't disable it while required")
+		require.NoError(t, domain.SetOwnersRequired(ctx, account, false))
+	})
+
+	t.Run("disable", func(t *testing.T) {
+		require.Error(t, domain.Disable(ctx, account, "wrong"))
+		require.NoError(t, domain.Disable(ctx, account, recoveryCodes[1]))
+
+		status, err := domain.GetStatus(ctx, account)
+		require.NoError(t, err)
+		require.False(t, status.Enabled)
+
+		err = domain.SetOwnersRequired(ctx, account, true)
+		require.Error(t, err, "can't be required by an owner without it")
+	})
+}
+
+func TestTOTPDomainEnrollWithoutSecretKey(t *testing.T) {
+	ctx := context.TODO()
+	logger := logrus.New()
+	_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+	deps.Config().Http.SecretKeyRandom = true
+	domain := domains.NewTOTPDomain(deps)
+
+	account, err := deps.Domains().Accounts().CreateAccount(ctx, model.AccountDTO{
+		Username: "test",
+		Password: "test",
+	})
+	require.NoError(t, err)
+
+	_, err = domain.Enroll(ctx, account)
+	require.ErrorContains(t, err, "secret key")
+
+	_, exists, err := deps.Database().GetAccountTOTP(ctx, account.ID)
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+func TestTOTPDomainLoginChallenge(t *testing.T) {
+	ctx := context.TODO()
+	logger := logrus.New()
+	_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+	domain := domains.NewTOTPDomain(deps)
+
+	account, err := deps.Domains().Accounts().CreateAccount(ctx, model.AccountDTO{
+		Username: "test",
+		Password: "test",
+	})
+	require.NoError(t, err)
+
+	challenge, err := domain.CreateLoginChallenge(account, true)
+	require.NoError(t, err)
+
+	challenged, rememberMe, err := domain.CheckLoginChallenge(ctx, challenge)
+	require.NoError(t, err)
+	require.Equal(t, account.ID, challenged.ID)
+	require.True(t, rememberMe)
+
+	t.Run("session tokens are not challenges", func(t *testing.T) {
+		token, err := deps.Domains().Auth().CreateTokenForAccount(ctx, account, time.Now().Add(time.Hour), "")
+		require.NoError(t, err)
+
+		_, _, err = domain.CheckLoginChallenge(ctx, token)
+		require.Error(t, err)
+	})
+}
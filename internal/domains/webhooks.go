@@ -0,0 +1,281 @@
+package domains
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/core"
+	"github.com/go-shiori/shiori/internal/database"
+	"github.com/go-shiori/shiori/internal/model"
+)
+
+// WebhooksDomain notifies webhooks of the changes to bookmarks and tags. Each notification is
+// stored as a delivery and sent by a job, so failed attempts are retried by the job workers.
+type WebhooksDomain struct {
+	deps model.Dependencies
+}
+
+// CreateWebhook adds a webhook with a new signing secret, which is only returned here.
+// Instance webhooks, with a zero account, can only be created by owners.
+func (d *WebhooksDomain) CreateWebhook(ctx context.Context, account *model.AccountDTO, webhook model.Webhook) (*model.Webhook, error) {
+	if webhook.AccountID == 0 && !account.IsOwner() {
+		return nil, model.ErrUnauthorized
+	}
+	if webhook.AccountID > 0 && webhook.AccountID != account.ID {
+		return nil, model.ErrUnauthorized
+	}
+
+	webhook.URL = strings.TrimSpace(webhook.URL)
+	if err := webhook.IsValid(); err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	webhook.Secret = model.WebhookSecretPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	return d.deps.Database().CreateWebhook(ctx, webhook)
+}
+
+// ListWebhooks returns the webhooks of the account, and the instance ones for owners.
+func (d *WebhooksDomain) ListWebhooks(ctx context.Context, account *model.AccountDTO) ([]model.Webhook, error) {
+	accountIDs := []model.DBID{account.ID}
+	if account.IsOwner() {
+		accountIDs = append(accountIDs, 0)
+	}
+
+	webhooks, err := d.deps.Database().ListWebhooks(ctx, model.DBListWebhooksOptions{AccountIDs: accountIDs})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	return webhooks, nil
+}
+
+// GetWebhook returns a webhook the account manages.
+func (d *WebhooksDomain) GetWebhook(ctx context.Context, account *model.AccountDTO, id model.DBID) (*model.Webhook, error) {
+	webhook, exists, err := d.deps.Database().GetWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists || !canManageWebhook(account, webhook) {
+		return nil, model.ErrNotFound
+	}
+
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+// DeleteWebhook removes a webhook the account manages, with its deliveries.
+func (d *WebhooksDomain) DeleteWebhook(ctx context.Context, account *model.AccountDTO, id model.DBID) error {
+	if _, err := d.GetWebhook(ctx, account, id); err != nil {
+		return err
+	}
+
+	if err := d.deps.Database().DeleteWebhook(ctx, id); err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return model.ErrNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// ListDeliveries returns the latest deliveries of a webhook the account manages, newest first.
+func (d *WebhooksDomain) ListDeliveries(ctx context.Context, account *model.AccountDTO, webhookID model.DBID) ([]model.WebhookDelivery, error) {
+	if _, err := d.GetWebhook(ctx, account, webhookID); err != nil {
+		return nil, err
+	}
+
+	return d.deps.Database().ListWebhookDeliveries(ctx, webhookID, d.deps.Config().Webhooks.DeliveriesKeep)
+}
+
+// Redeliver queues a new delivery with the payload of a previous one.
+func (d *WebhooksDomain) Redeliver(ctx context.Context, account *model.AccountDTO, webhookID model.DBID, deliveryID model.DBID) (*model.WebhookDelivery, error) {
+	if _, err := d.GetWebhook(ctx, account, webhookID); err != nil {
+		return nil, err
+	}
+
+	delivery, exists, err := d.deps.Database().GetWebhookDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists || delivery.WebhookID != webhookID {
+		return nil, model.ErrNotFound
+	}
+
+	return d.queue(ctx, webhookID, delivery.Event, delivery.Payload)
+}
+
+// Emit queues a delivery of the event to the webhooks of the account and to the instance
+// webhooks, a zero account only notifies the instance ones. Errors are logged, notifying the
+// webhooks never fails the change that triggered the event.
+func (d *WebhooksDomain) Emit(ctx context.Context, event model.WebhookEvent, accountID model.DBID, data any) {
+	// The change is done already, the deliveries are stored even if the request is cancelled
+	ctx = context.WithoutCancel(ctx)
+	logger := d.deps.Logger().WithField("event", event)
+
+	accountIDs := []model.DBID{0}
+	if accountID > 0 {
+		accountIDs = append(accountIDs, accountID)
+	}
+
+	webhooks, err := d.deps.Database().ListWebhooks(ctx, model.DBListWebhooksOptions{AccountIDs: accountIDs})
+	if err != nil {
+		logger.WithError(err).Error("failed to list webhooks")
+		return
+	}
+
+	var payload []byte
+	for _, webhook := range webhooks {
+		if !webhook.Events.Includes(event) {
+			continue
+		}
+
+		if payload == nil {
+			payload, err = json.Marshal(model.WebhookPayload{
+				Event:     event,
+				CreatedAt: time.Now().UTC().Format(time.RFC3339),
+				Data:      data,
+			})
+			if err != nil {
+				logger.WithError(err).Error("failed to encode webhook payload")
+				return
+			}
+		}
+
+		if _, err := d.queue(ctx, webhook.ID, event, string(payload)); err != nil {
+			logger.WithError(err).WithField("webhook_id", webhook.ID).Error("failed to queue webhook delivery")
+		}
+	}
+}
+
+// EmitCreatedTags sends WebhookEventTagCreated for the tags created when the bookmarks were
+// saved, tags are added along with bookmarks as well as by TagsDomain.CreateTag.
+func (d *WebhooksDomain) EmitCreatedTags(ctx context.Context, bookmarks ...model.BookmarkDTO) {
+	emitted := map[int]bool{}
+	for _, bookmark := range bookmarks {
+		for _, tag := range bookmark.Tags {
+			if !tag.Created || emitted[tag.ID] {
+				continue
+			}
+			emitted[tag.ID] = true
+			d.Emit(ctx, model.WebhookEventTagCreated, 0, tag.ToTag())
+		}
+	}
+}
+
+// queue stores a delivery and the job sending it, removing the deliveries of the webhook
+// beyond the configured number.
+func (d *WebhooksDomain) queue(ctx context.Context, webhookID model.DBID, event model.WebhookEvent, payload string) (*model.WebhookDelivery, error) {
+	delivery, err := d.deps.Database().CreateWebhookDelivery(ctx, model.WebhookDelivery{
+		WebhookID: webhookID,
+		Event:     event,
+		Payload:   payload,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := d.deps.Domains().Jobs().Enqueue(ctx, model.JobTypeDeliverWebhook, model.DeliverWebhookJobPayload{
+		DeliveryID: delivery.ID,
+	}); err != nil {
+		return nil, err
+	}
+
+	keep := d.deps.Config().Webhooks.DeliveriesKeep
+	kept, err := d.deps.Database().ListWebhookDeliveries(ctx, webhookID, keep)
+	if err != nil {
+		return nil, err
+	}
+	if len(kept) == keep {
+		if err := d.deps.Database().DeleteWebhookDeliveries(ctx, webhookID, kept[keep-1].ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return delivery, nil
+}
+
+// Deliver sends a delivery to its webhook and records the attempt. An error is returned when
+// the attempt failed, so the job is retried.
+func (d *WebhooksDomain) Deliver(ctx context.Context, deliveryID model.DBID) error {
+	delivery, exists, err := d.deps.Database().GetWebhookDelivery(ctx, deliveryID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		// Removed along with its webhook, or from the log, since it was queued
+		return nil
+	}
+
+	webhook, exists, err := d.deps.Database().GetWebhook(ctx, delivery.WebhookID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+
+	response, sendErr := core.SendWebhook(ctx, core.WebhookRequest{
+		URL:        webhook.URL,
+		Secret:     webhook.Secret,
+		Event:      delivery.Event,
+		DeliveryID: delivery.ID,
+		Payload:    []byte(delivery.Payload),
+	}, d.deps.Config().Webhooks.Timeout)
+
+	delivery.Attempts++
+	delivery.DeliveredAt = model.Ptr(time.Now().UTC().Format(model.DatabaseDateFormat))
+	delivery.ResponseStatus = 0
+	delivery.ResponseBody = ""
+	if response != nil {
+		delivery.ResponseStatus = response.StatusCode
+		delivery.ResponseBody = response.Body
+	}
+
+	if sendErr != nil {
+		delivery.Status = model.WebhookDeliveryStatusFailed
+		delivery.LastError = sendErr.Error()
+	} else {
+		delivery.Status = model.WebhookDeliveryStatusSucceeded
+		delivery.LastError = ""
+	}
+
+	// The attempt must be recorded even if the job is being cancelled
+	if err := d.deps.Database().UpdateWebhookDelivery(context.WithoutCancel(ctx), *delivery); err != nil {
+		return err
+	}
+
+	return sendErr
+}
+
+// canManageWebhook reports whether the account manages the webhook, instance webhooks are
+// managed by the owners.
+func canManageWebhook(account *model.AccountDTO, webhook *model.Webhook) bool {
+	if webhook.AccountID == 0 {
+		return account.IsOwner()
+	}
+	return webhook.AccountID == account.ID
+}
+
+func NewWebhooksDomain(deps model.Dependencies) *WebhooksDomain {
+	return &WebhooksDomain{
+		deps: deps,
+	}
+}
@@ -0,0 +1,222 @@
+package domains_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/core"
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/shiori/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookReceiver records the deliveries it gets, answering with its status
+type webhookReceiver struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	status   int
+	received []*http.Request
+	bodies   [][]byte
+}
+
+func newWebhookReceiver(t *testing.T) *webhookReceiver {
+	receiver := &webhookReceiver{status: http.StatusOK}
+	receiver.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		receiver.mu.Lock()
+		defer receiver.mu.Unlock()
+
+		receiver.received = append(receiver.received, r)
+		receiver.bodies = append(receiver.bodies, body)
+		w.WriteHeader(receiver.status)
+		w.Write([]byte("received"))
+	}))
+	t.Cleanup(receiver.Close)
+	return receiver
+}
+
+func TestWebhooksDomain(t *testing.T) {
+	ctx := context.Background()
+	logger := logrus.New()
+
+	owner := testutil.FakeAccount(true)
+	user := testutil.FakeAccount(false)
+
+	t.Run("instance webhooks require an owner", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		_, err := deps.Domains().Webhooks().CreateWebhook(ctx, user, model.Webhook{URL: "https://example.com/hook"})
+		require.ErrorIs(t, err, model.ErrUnauthorized)
+
+		webhook, err := deps.Domains().Webhooks().CreateWebhook(ctx, owner, model.Webhook{URL: "https://example.com/hook"})
+		require.NoError(t, err)
+		require.Equal(t, model.DBID(0), webhook.AccountID)
+		require.NotEmpty(t, webhook.Secret)
+	})
+
+	t.Run("create validates the webhook", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		_, err := deps.Domains().Webhooks().CreateWebhook(ctx, user, model.Webhook{
+			AccountID: user.ID,
+			URL:       "ftp://example.com/hook",
+		})
+		require.ErrorAs(t, err, &model.ValidationError{})
+	})
+
+	t.Run("bookmark events are delivered signed", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		receiver := newWebhookReceiver(t)
+
+		webhook, err := deps.Domains().Webhooks().CreateWebhook(ctx, user, model.Webhook{
+			AccountID: user.ID,
+			URL:       receiver.URL,
+			Events:    model.WebhookEvents{model.WebhookEventBookmarkCreated},
+		})
+		require.NoError(t, err)
+
+		// A webhook of another account isn't notified
+		_, err = deps.Database().CreateWebhook(ctx, model.Webhook{
+			AccountID: user.ID + 1,
+			URL:       receiver.URL,
+			Secret:    "secret",
+		})
+		require.NoError(t, err)
+
+		bookmark := testutil.GetValidBookmark()
+		bookmark.AccountID = user.ID
+		created, err := deps.Domains().Bookmarks().CreateBookmark(ctx, *bookmark)
+		require.NoError(t, err)
+
+		// Not subscribed to updates
+		_, err = deps.Domains().Bookmarks().UpdateBookmark(ctx, *created, user.ID)
+		require.NoError(t, err)
+
+		deliveries, err := deps.Domains().Webhooks().ListDeliveries(ctx, user, webhook.ID)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		require.Equal(t, model.WebhookDeliveryStatusPending, deliveries[0].Status)
+
+		require.NoError(t, deps.Domains().Webhooks().Deliver(ctx, deliveries[0].ID))
+		require.Len(t, receiver.received, 1)
+
+		request := receiver.received[0]
+		require.Equal(t, string(model.WebhookEventBookmarkCreated), request.Header.Get("X-Shiori-Event"))
+		timestamp := request.Header.Get(model.WebhookTimestampHeader)
+		require.NotEmpty(t, timestamp)
+		require.Equal(t, core.SignWebhookPayload(webhook.Secret, timestamp, receiver.bodies[0]), request.Header.Get(model.WebhookSignatureHeader))
+
+		var payload struct {
+			Event model.WebhookEvent    `json:"event"`
+			Data  model.WebhookBookmark `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(receiver.bodies[0], &payload))
+		require.Equal(t, model.WebhookEventBookmarkCreated, payload.Event)
+		require.Equal(t, created.ID, payload.Data.ID)
+		require.Equal(t, created.URL, payload.Data.URL)
+
+		delivery, _, err := deps.Database().GetWebhookDelivery(ctx, deliveries[0].ID)
+		require.NoError(t, err)
+		require.Equal(t, model.WebhookDeliveryStatusSucceeded, delivery.Status)
+		require.Equal(t, 1, delivery.Attempts)
+		require.Equal(t, http.StatusOK, delivery.ResponseStatus)
+		require.Equal(t, "received", delivery.ResponseBody)
+	})
+
+	t.Run("failed deliveries are recorded and replayed", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		receiver := newWebhookReceiver(t)
+		receiver.status = http.StatusInternalServerError
+
+		webhook, err := deps.Domains().Webhooks().CreateWebhook(ctx, owner, model.Webhook{URL: receiver.URL})
+		require.NoError(t, err)
+
+		_, err = deps.Domains().Tags().CreateTag(ctx, model.TagDTO{Tag: model.Tag{Name: "golang"}})
+		require.NoError(t, err)
+
+		deliveries, err := deps.Domains().Webhooks().ListDeliveries(ctx, owner, webhook.ID)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		require.Equal(t, model.WebhookEventTagCreated, deliveries[0].Event)
+
+		require.Error(t, deps.Domains().Webhooks().Deliver(ctx, deliveries[0].ID))
+
+		failed, _, err := deps.Database().GetWebhookDelivery(ctx, deliveries[0].ID)
+		require.NoError(t, err)
+		require.Equal(t, model.WebhookDeliveryStatusFailed, failed.Status)
+		require.Equal(t, http.StatusInternalServerError, failed.ResponseStatus)
+		require.NotEmpty(t, failed.LastError)
+
+		replayed, err := deps.Domains().Webhooks().Redeliver(ctx, owner, webhook.ID, failed.ID)
+		require.NoError(t, err)
+		require.Equal(t, failed.Payload, replayed.Payload)
+
+		_, err = deps.Domains().Webhooks().Redeliver(ctx, user, webhook.ID, failed.ID)
+		require.ErrorIs(t, err, model.ErrNotFound)
+	})
+
+	t.Run("tags created with a bookmark are sent", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		webhook, err := deps.Domains().Webhooks().CreateWebhook(ctx, owner, model.Webhook{
+			URL:    "https://example.com/hook",
+			Events: model.WebhookEvents{model.WebhookEventTagCreated},
+		})
+		require.NoError(t, err)
+
+		_, err = deps.Database().CreateTag(ctx, model.Tag{Name: "existing"})
+		require.NoError(t, err)
+
+		bookmark := testutil.GetValidBookmark()
+		bookmark.Tags = []model.TagDTO{
+			{Tag: model.Tag{Name: "existing"}},
+			{Tag: model.Tag{Name: "golang"}},
+		}
+		created, err := deps.Domains().Bookmarks().CreateBookmark(ctx, *bookmark)
+		require.NoError(t, err)
+
+		// The tag is new to the update only
+		created.Tags = append(created.Tags, model.TagDTO{Tag: model.Tag{Name: "golang"}}, model.TagDTO{Tag: model.Tag{Name: "rust"}})
+		_, err = deps.Domains().Bookmarks().UpdateBookmark(ctx, *created, created.AccountID)
+		require.NoError(t, err)
+
+		deliveries, err := deps.Database().ListWebhookDeliveries(ctx, webhook.ID, 0)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 2)
+
+		names := []string{}
+		for _, delivery := range deliveries {
+			var payload struct {
+				Data model.Tag `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(delivery.Payload), &payload))
+			require.NotZero(t, payload.Data.ID)
+			names = append(names, payload.Data.Name)
+		}
+		require.ElementsMatch(t, []string{"golang", "rust"}, names)
+	})
+
+	t.Run("delivery log is bounded", func(t *testing.T) {
+		cfg, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		cfg.Webhooks.DeliveriesKeep = 2
+
+		webhook, err := deps.Domains().Webhooks().CreateWebhook(ctx, owner, model.Webhook{URL: "https://example.com/hook"})
+		require.NoError(t, err)
+
+		for range 3 {
+			deps.Domains().Webhooks().Emit(ctx, model.WebhookEventTagDeleted, 0, model.Tag{Name: "golang"})
+		}
+
+		deliveries, err := deps.Database().ListWebhookDeliveries(ctx, webhook.ID, 0)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 2)
+	})
+}
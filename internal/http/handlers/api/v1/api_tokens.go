@@ -0,0 +1,128 @@
+package api_v1
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/http/middleware"
+	"github.com/go-shiori/shiori/internal/http/response"
+	"github.com/go-shiori/shiori/internal/model"
+)
+
+type createAPITokenPayload struct {
+	Name   string                `json:"name"`
+	Scopes []model.APITokenScope `json:"scopes"`
+	// Expiration date in RFC3339 format, tokens without one don't expire
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type createAPITokenResponse struct {
+	model.APIToken
+	// The token value, only returned once
+	Token string `json:"token"`
+}
+
+// @Summary					List personal API tokens
+// @Description				List the personal API tokens of the logged in account. Token values are never returned.
+// @Tags						Auth
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Success					200	{array}		model.APIToken
+// @Failure					401	{object}	nil	"Authentication required"
+// @Failure					500	{object}	nil	"Internal server error"
+// @Router						/api/v1/auth/tokens [get]
+func HandleListAPITokens(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	tokens, err := deps.Domains().Auth().ListAPITokens(c.Request().Context(), c.GetAccount().ID)
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to list api tokens")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, tokens)
+}
+
+// @Summary					Create a personal API token
+// @Description				Create a long lived token for scripts and extensions, sent as a bearer token like the session one.
+// @Description				Scopes are read (GET requests only), write (any request) and admin (owner requests, owners only).
+// @Tags						Auth
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Accept						json
+// @Produce					json
+// @Param						payload	body		createAPITokenPayload	true	"Token data"
+// @Success					201		{object}	createAPITokenResponse
+// @Failure					400		{object}	nil	"Invalid token data"
+// @Failure					401		{object}	nil	"Authentication required"
+// @Failure					500		{object}	nil	"Internal server error"
+// @Router						/api/v1/auth/tokens [post]
+func HandleCreateAPIToken(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	var payload createAPITokenPayload
+	if err := json.NewDecoder(c.Request().Body).Decode(&payload); err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	token, value, err := deps.Domains().Auth().CreateAPIToken(c.Request().Context(), c.GetAccount(), payload.Name, payload.Scopes, payload.ExpiresAt)
+	if err, isValidationErr := err.(model.ValidationError); isValidationErr {
+		response.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to create api token")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusCreated, createAPITokenResponse{
+		APIToken: *token,
+		Token:    value,
+	})
+}
+
+// @Summary					Revoke a personal API token
+// @Tags						Auth
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Param						id	path		int	true	"Token ID"
+// @Success					204	{object}	nil
+// @Failure					400	{object}	nil	"Invalid token ID"
+// @Failure					401	{object}	nil	"Authentication required"
+// @Failure					404	{object}	nil	"Token not found"
+// @Failure					500	{object}	nil	"Internal server error"
+// @Router						/api/v1/auth/tokens/{id} [delete]
+func HandleRevokeAPIToken(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Request().PathValue("id"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid token ID")
+		return
+	}
+
+	err = deps.Domains().Auth().RevokeAPIToken(c.Request().Context(), c.GetAccount().ID, model.DBID(id))
+	if errors.Is(err, model.ErrNotFound) {
+		response.NotFound(c)
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to revoke api token")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusNoContent, nil)
+}
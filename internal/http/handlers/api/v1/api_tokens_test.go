@@ -0,0 +1,140 @@
+package api_v1
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/shiori/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleListAPITokens(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("requires authentication", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleListAPITokens, http.MethodGet, "/api/v1/auth/tokens")
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("list own tokens", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		for _, account := range []*model.AccountDTO{testutil.FakeAccount(false), {ID: testutil.FakeAccountID + 1}} {
+			_, _, err := deps.Domains().Auth().CreateAPIToken(ctx, account, "script", model.APITokenScopes{model.APITokenScopeRead}, nil)
+			require.NoError(t, err)
+		}
+
+		w := testutil.PerformRequest(deps, HandleListAPITokens, http.MethodGet, "/api/v1/auth/tokens", testutil.WithFakeUser())
+		require.Equal(t, http.StatusOK, w.Code)
+
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertOk(t)
+		response.AssertMessageIsListLength(t, 1)
+		response.ForEach(t, func(item map[string]any) {
+			require.Equal(t, "script", item["name"])
+			require.NotContains(t, item, "token_hash")
+		})
+	})
+}
+
+func TestHandleCreateAPIToken(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("requires authentication", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleCreateAPIToken, http.MethodPost, "/api/v1/auth/tokens")
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("invalid payload", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleCreateAPIToken, http.MethodPost, "/api/v1/auth/tokens",
+			testutil.WithFakeUser(),
+			testutil.WithBody(`{"name": "script", "scopes": ["unknown"]}`),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("admin scope requires an owner", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleCreateAPIToken, http.MethodPost, "/api/v1/auth/tokens",
+			testutil.WithFakeUser(),
+			testutil.WithBody(`{"name": "script", "scopes": ["admin"]}`),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("create token", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleCreateAPIToken, http.MethodPost, "/api/v1/auth/tokens",
+			testutil.WithFakeAdmin(),
+			testutil.WithBody(`{"name": "extension", "scopes": ["read", "write"], "expires_at": "2100-01-01T00:00:00Z"}`),
+		)
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertOk(t)
+		response.AssertMessageJSONKeyValue(t, "token", func(t *testing.T, value any) {
+			require.True(t, strings.HasPrefix(value.(string), model.APITokenPrefix))
+		})
+		response.AssertMessageJSONKeyValue(t, "expires_at", func(t *testing.T, value any) {
+			require.Contains(t, value, "2100-01-01")
+		})
+
+		tokens, err := deps.Domains().Auth().ListAPITokens(ctx, testutil.FakeAccountID)
+		require.NoError(t, err)
+		require.Len(t, tokens, 1)
+		require.Equal(t, model.APITokenScopes{model.APITokenScopeRead, model.APITokenScopeWrite}, tokens[0].Scopes)
+	})
+}
+
+func TestHandleRevokeAPIToken(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("requires authentication", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleRevokeAPIToken, http.MethodDelete, "/api/v1/auth/tokens/1",
+			testutil.WithRequestPathValue("id", "1"),
+		)
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("token of another account", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		token, _, err := deps.Domains().Auth().CreateAPIToken(ctx, &model.AccountDTO{ID: testutil.FakeAccountID + 1}, "script", model.APITokenScopes{model.APITokenScopeRead}, nil)
+		require.NoError(t, err)
+
+		id := strconv.Itoa(int(token.ID))
+		w := testutil.PerformRequest(deps, HandleRevokeAPIToken, http.MethodDelete, "/api/v1/auth/tokens/"+id,
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+		)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("revoke token", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		token, _, err := deps.Domains().Auth().CreateAPIToken(ctx, testutil.FakeAccount(false), "script", model.APITokenScopes{model.APITokenScopeRead}, nil)
+		require.NoError(t, err)
+
+		id := strconv.Itoa(int(token.ID))
+		w := testutil.PerformRequest(deps, HandleRevokeAPIToken, http.MethodDelete, "/api/v1/auth/tokens/"+id,
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+		)
+		require.Equal(t, http.StatusNoContent, w.Code)
+
+		tokens, err := deps.Domains().Auth().ListAPITokens(ctx, testutil.FakeAccountID)
+		require.NoError(t, err)
+		require.Empty(t, tokens)
+	})
+}
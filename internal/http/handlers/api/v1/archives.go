@@ -0,0 +1,179 @@
+package api_v1
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-shiori/shiori/internal/http/middleware"
+	"github.com/go-shiori/shiori/internal/http/response"
+	"github.com/go-shiori/shiori/internal/model"
+)
+
+type reindexArchivesPayload struct {
+	// Bookmarks whose archive is indexed, empty indexes every bookmark of the account
+	IDs []int `json:"ids"`
+}
+
+// getScopedBookmark retrieves the bookmark in the path, restricted to the accounts the
+// user can access. Errors are already sent when it returns nil.
+func getScopedBookmark(deps model.Dependencies, c model.WebContext) *model.BookmarkDTO {
+	bookmarkID, err := strconv.Atoi(c.Request().PathValue("id"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid bookmark ID")
+		return nil
+	}
+
+	bookmark, err := deps.Domains().Bookmarks().GetBookmark(c.Request().Context(), model.DBID(bookmarkID), bookmarksAccountScope(c))
+	if err != nil {
+		if errors.Is(err, model.ErrBookmarkNotFound) {
+			response.SendError(c, http.StatusNotFound, "Bookmark not found")
+			return nil
+		}
+		deps.Logger().WithError(err).Error("failed to get bookmark")
+		response.SendInternalServerError(c)
+		return nil
+	}
+
+	return bookmark
+}
+
+// @Summary					List bookmark archives
+// @Description				List the archive snapshots of a bookmark, newest first. The current archive is flagged.
+// @Tags						Bookmarks
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Param						id				path		int		true	"Bookmark ID"
+// @Param						all_accounts	query		boolean	false	"Look up bookmarks of every account, owners only"
+// @Success					200				{array}		model.ArchiveSnapshot
+// @Failure					400				{object}	nil	"Invalid bookmark ID"
+// @Failure					401				{object}	nil	"Authentication required"
+// @Failure					404				{object}	nil	"Bookmark not found"
+// @Failure					500				{object}	nil	"Internal server error"
+// @Router						/api/v1/bookmarks/{id}/archives [get]
+func HandleListBookmarkArchives(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	bookmark := getScopedBookmark(deps, c)
+	if bookmark == nil {
+		return
+	}
+
+	snapshots, err := deps.Domains().Archiver().ListSnapshots(c.Request().Context(), bookmark)
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to list archive snapshots")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, snapshots)
+}
+
+// @Summary					Delete bookmark archive
+// @Description				Delete an archive snapshot of a bookmark. Deleting the current archive makes the newest remaining snapshot current.
+// @Tags						Bookmarks
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Param						id				path		int		true	"Bookmark ID"
+// @Param						snapshot_id		path		int		true	"Archive snapshot ID"
+// @Param						all_accounts	query		boolean	false	"Look up bookmarks of every account, owners only"
+// @Success					200				{object}	nil
+// @Failure					400				{object}	nil	"Invalid bookmark or snapshot ID"
+// @Failure					401				{object}	nil	"Authentication required"
+// @Failure					404				{object}	nil	"Bookmark or snapshot not found"
+// @Failure					500				{object}	nil	"Internal server error"
+// @Router						/api/v1/bookmarks/{id}/archives/{snapshot_id} [delete]
+func HandleDeleteBookmarkArchive(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	snapshotID, err := strconv.Atoi(c.Request().PathValue("snapshot_id"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid snapshot ID")
+		return
+	}
+
+	bookmark := getScopedBookmark(deps, c)
+	if bookmark == nil {
+		return
+	}
+
+	if err := deps.Domains().Archiver().DeleteSnapshot(c.Request().Context(), bookmark, model.DBID(snapshotID)); err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			response.SendError(c, http.StatusNotFound, "Archive snapshot not found")
+			return
+		}
+		deps.Logger().WithError(err).Error("failed to delete archive snapshot")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, nil)
+}
+
+// @Summary					Reindex bookmark archives
+// @Description				Queue the extraction of the text of the bookmark archives, from their HTML pages and PDFs, so it can be searched with in:archive. The saved archives are read without downloading the pages again. Without ids every bookmark of the account is indexed.
+// @Tags						Bookmarks
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Accept						json
+// @Produce					json
+// @Param						payload			body		reindexArchivesPayload	false	"Bookmarks to index"
+// @Param						all_accounts	query		boolean					false	"Index the bookmarks of every account, owners only"
+// @Success					202				{object}	model.Job
+// @Failure					400				{object}	nil	"Invalid request payload"
+// @Failure					401				{object}	nil	"Authentication required"
+// @Failure					404				{object}	nil	"No bookmarks found"
+// @Failure					500				{object}	nil	"Internal server error"
+// @Router						/api/v1/bookmarks/reindex [post]
+func HandleReindexBookmarkArchives(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	// The payload is optional, an empty body indexes every bookmark
+	var payload reindexArchivesPayload
+	if err := json.NewDecoder(c.Request().Body).Decode(&payload); err != nil && !errors.Is(err, io.EOF) {
+		response.SendError(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	ctx := c.Request().Context()
+
+	var bookmarks []model.BookmarkDTO
+	var err error
+	if len(payload.IDs) > 0 {
+		bookmarks, err = deps.Domains().Bookmarks().GetBookmarks(ctx, payload.IDs, bookmarksAccountScope(c))
+	} else {
+		bookmarks, err = deps.Domains().Bookmarks().ListBookmarks(ctx, model.ListBookmarksOptions{
+			AccountID: bookmarksAccountScope(c),
+		})
+	}
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to get bookmarks")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	if len(bookmarks) == 0 {
+		response.SendError(c, http.StatusNotFound, "No bookmarks found")
+		return
+	}
+
+	ids := make([]int, 0, len(bookmarks))
+	for _, bookmark := range bookmarks {
+		ids = append(ids, bookmark.ID)
+	}
+
+	job, err := deps.Domains().Jobs().Enqueue(ctx, model.JobTypeIndexArchives, model.IndexArchivesJobPayload{BookmarkIDs: ids})
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to queue archive reindex")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusAccepted, job)
+}
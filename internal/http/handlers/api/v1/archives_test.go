@@ -0,0 +1,182 @@
+package api_v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/shiori/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleBookmarkArchives(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	// setupForAccount saves a bookmark of the account with two archive captures
+	setupForAccount := func(t *testing.T, accountID model.DBID) (model.Dependencies, model.BookmarkDTO) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		bookmark := testutil.GetValidBookmark()
+		bookmark.AccountID = accountID
+		result, err := deps.Database().SaveBookmarks(ctx, true, *bookmark)
+		require.NoError(t, err)
+		book := result[0]
+
+		for _, content := range []string{"first", "second"} {
+			require.NoError(t, deps.Domains().Archiver().PreserveArchive(ctx, &book))
+			require.NoError(t, deps.Domains().Storage().WriteData(model.GetArchivePath(&book), []byte(content)))
+			require.NoError(t, deps.Domains().Archiver().RecordArchive(ctx, &book))
+		}
+
+		return deps, book
+	}
+
+	setup := func(t *testing.T) (model.Dependencies, model.BookmarkDTO) {
+		return setupForAccount(t, testutil.FakeAccountID)
+	}
+
+	t.Run("list requires authentication", func(t *testing.T) {
+		deps, book := setup(t)
+		w := testutil.PerformRequest(
+			deps,
+			HandleListBookmarkArchives,
+			http.MethodGet,
+			"/api/v1/bookmarks/"+strconv.Itoa(book.ID)+"/archives",
+			testutil.WithRequestPathValue("id", strconv.Itoa(book.ID)),
+		)
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("list archives", func(t *testing.T) {
+		deps, book := setup(t)
+		w := testutil.PerformRequest(
+			deps,
+			HandleListBookmarkArchives,
+			http.MethodGet,
+			"/api/v1/bookmarks/"+strconv.Itoa(book.ID)+"/archives",
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", strconv.Itoa(book.ID)),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertOk(t)
+		response.AssertMessageIsListLength(t, 2)
+	})
+
+	t.Run("list archives of a bookmark of another account", func(t *testing.T) {
+		deps, book := setupForAccount(t, testutil.FakeAccountID+1)
+		w := testutil.PerformRequest(
+			deps,
+			HandleListBookmarkArchives,
+			http.MethodGet,
+			"/api/v1/bookmarks/"+strconv.Itoa(book.ID)+"/archives",
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", strconv.Itoa(book.ID)),
+		)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("delete invalid snapshot id", func(t *testing.T) {
+		deps, book := setup(t)
+		w := testutil.PerformRequest(
+			deps,
+			HandleDeleteBookmarkArchive,
+			http.MethodDelete,
+			"/api/v1/bookmarks/"+strconv.Itoa(book.ID)+"/archives/invalid",
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", strconv.Itoa(book.ID)),
+			testutil.WithRequestPathValue("snapshot_id", "invalid"),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("delete unknown snapshot", func(t *testing.T) {
+		deps, book := setup(t)
+		w := testutil.PerformRequest(
+			deps,
+			HandleDeleteBookmarkArchive,
+			http.MethodDelete,
+			"/api/v1/bookmarks/"+strconv.Itoa(book.ID)+"/archives/999",
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", strconv.Itoa(book.ID)),
+			testutil.WithRequestPathValue("snapshot_id", "999"),
+		)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("delete snapshot", func(t *testing.T) {
+		deps, book := setup(t)
+		snapshots, err := deps.Domains().Archiver().ListSnapshots(ctx, &book)
+		require.NoError(t, err)
+		snapshotID := strconv.Itoa(int(snapshots[1].ID))
+
+		w := testutil.PerformRequest(
+			deps,
+			HandleDeleteBookmarkArchive,
+			http.MethodDelete,
+			"/api/v1/bookmarks/"+strconv.Itoa(book.ID)+"/archives/"+snapshotID,
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", strconv.Itoa(book.ID)),
+			testutil.WithRequestPathValue("snapshot_id", snapshotID),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		snapshots, err = deps.Domains().Archiver().ListSnapshots(ctx, &book)
+		require.NoError(t, err)
+		require.Len(t, snapshots, 1)
+		require.True(t, snapshots[0].Current)
+	})
+}
+
+func TestHandleReindexBookmarkArchives(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("requires authentication", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleReindexBookmarkArchives, http.MethodPost, "/api/v1/bookmarks/reindex")
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("no bookmarks", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleReindexBookmarkArchives, http.MethodPost, "/api/v1/bookmarks/reindex", testutil.WithFakeUser())
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("queues the reindex of the given bookmarks", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		first := testutil.GetValidBookmark()
+		first.AccountID = testutil.FakeAccountID
+		second := testutil.GetValidBookmark()
+		second.URL = "https://example.com/second"
+		second.AccountID = testutil.FakeAccountID
+		saved, err := deps.Database().SaveBookmarks(ctx, true, *first, *second)
+		require.NoError(t, err)
+
+		w := testutil.PerformRequest(
+			deps,
+			HandleReindexBookmarkArchives,
+			http.MethodPost,
+			"/api/v1/bookmarks/reindex",
+			testutil.WithFakeUser(),
+			testutil.WithBody(`{"ids": [`+strconv.Itoa(saved[1].ID)+`]}`),
+		)
+		require.Equal(t, http.StatusAccepted, w.Code)
+
+		jobs, err := deps.Domains().Jobs().ListJobs(ctx, model.ListJobsOptions{})
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		require.Equal(t, model.JobTypeIndexArchives, jobs[0].Type)
+
+		var payload model.IndexArchivesJobPayload
+		require.NoError(t, json.Unmarshal([]byte(jobs[0].Payload), &payload))
+		require.Equal(t, []int{saved[1].ID}, payload.BookmarkIDs)
+	})
+}
@@ -2,6 +2,7 @@ package api_v1
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -28,18 +29,28 @@ func (p *loginRequestPayload) IsValid() error {
 }
 
 type loginResponseMessage struct {
-	Token      string `json:"token"`
-	Expiration int64  `json:"expires"`
+	Token      string `json:"token,omitempty"`
+	Expiration int64  `json:"expires,omitempty"`
+	// Set instead of the token when the account uses a second factor, the login is completed
+	// by sending the challenge and a code to /api/v1/auth/login/totp
+	TOTPRequired bool   `json:"totp_required,omitempty"`
+	Challenge    string `json:"challenge,omitempty"`
+	// Set when the owner account must set up a second factor before logging in
+	TOTPSetupRequired bool `json:"totp_setup_required,omitempty"`
+	// Recovery codes of the second factor set up during the login, only returned once
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
 }
 
-// @Summary	Login to an account using username and password
-// @Tags		Auth
-// @Accept		json
-// @Produce	json
-// @Param		payload	body		loginRequestPayload		false	"Login data"
-// @Success	200		{object}	loginResponseMessage	"Login successful"
-// @Failure	400		{object}	nil						"Invalid login data"
-// @Router		/api/v1/auth/login [post]
+// @Summary		Login to an account using username and password
+// @Description	Accounts with two-factor authentication get a challenge instead of a token, see /api/v1/auth/login/totp.
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Param			payload	body		loginRequestPayload		false	"Login data"
+// @Success		200		{object}	loginResponseMessage	"Login successful"
+// @Failure		400		{object}	nil						"Invalid login data"
+// @Failure		429		{object}	nil						"Too many login attempts"
+// @Router			/api/v1/auth/login [post]
 func HandleLogin(deps model.Dependencies, c model.WebContext) {
 	var payload loginRequestPayload
 	if err := json.NewDecoder(c.Request().Body).Decode(&payload); err != nil {
@@ -58,26 +69,57 @@ func HandleLogin(deps model.Dependencies, c model.WebContext) {
 		return
 	}
 
+	status, err := deps.Domains().TOTP().GetStatus(c.Request().Context(), account)
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to get two-factor authentication status")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	if status.Enabled || status.Required {
+		challenge, err := deps.Domains().TOTP().CreateLoginChallenge(account, payload.RememberMe)
+		if err != nil {
+			deps.Logger().WithError(err).Error("failed to create login challenge")
+			response.SendInternalServerError(c)
+			return
+		}
+
+		response.SendJSON(c, http.StatusOK, loginResponseMessage{
+			TOTPRequired:      true,
+			Challenge:         challenge,
+			TOTPSetupRequired: !status.Enabled,
+		})
+		return
+	}
+
+	sendLoginSession(deps, c, account, payload.RememberMe, nil)
+}
+
+// sendLoginSession starts a session for the account once it is logged in.
+func sendLoginSession(deps model.Dependencies, c model.WebContext, account *model.AccountDTO, rememberMe bool, recoveryCodes []string) {
 	expiration := time.Hour
-	if payload.RememberMe {
+	if rememberMe {
 		expiration = time.Hour * 24 * 30
 	}
 
 	expirationTime := time.Now().Add(expiration)
 
-	token, err := deps.Domains().Auth().CreateTokenForAccount(account, expirationTime)
+	token, err := deps.Domains().Auth().CreateTokenForAccount(c.Request().Context(), account, expirationTime, c.Request().UserAgent())
 	if err != nil {
+		deps.Logger().WithError(err).Error("failed to create session")
 		response.SendInternalServerError(c)
 		return
 	}
 
 	response.SendJSON(c, http.StatusOK, loginResponseMessage{
-		Token:      token,
-		Expiration: expirationTime.Unix(),
+		Token:         token,
+		Expiration:    expirationTime.Unix(),
+		RecoveryCodes: recoveryCodes,
 	})
 }
 
 // @Summary					Refresh a token for an account
+// @Description				Start a new session and end the one the request was made with.
 // @Tags						Auth
 // @securityDefinitions.apikey	ApiKeyAuth
 // @Produce					json
@@ -91,12 +133,19 @@ func HandleRefreshToken(deps model.Dependencies, c model.WebContext) {
 
 	expiration := time.Now().UTC().Add(time.Hour * 24 * 30)
 	account := c.GetAccount()
-	token, err := deps.Domains().Auth().CreateTokenForAccount(account, expiration)
+	token, err := deps.Domains().Auth().CreateTokenForAccount(c.Request().Context(), account, expiration, c.Request().UserAgent())
 	if err != nil {
+		deps.Logger().WithError(err).Error("failed to create session")
 		response.SendInternalServerError(c)
 		return
 	}
 
+	if sessionID := c.GetSessionID(); sessionID != "" {
+		if err := deps.Domains().Auth().RevokeSession(c.Request().Context(), account.ID, sessionID); err != nil {
+			deps.Logger().WithError(err).Warn("failed to end refreshed session")
+		}
+	}
+
 	response.SendJSON(c, http.StatusAccepted, loginResponseMessage{
 		Token:      token,
 		Expiration: expiration.Unix(),
@@ -152,6 +201,7 @@ func (p *updateAccountPayload) ToAccountDTO() model.AccountDTO {
 }
 
 // @Summary					Update account information
+// @Description				Changing the password ends every session of the account, including the current one.
 // @Tags						Auth
 // @securityDefinitions.apikey	ApiKeyAuth
 // @Param						payload	body	updateAccountPayload	false	"Account data"
@@ -202,6 +252,7 @@ func HandleUpdateLoggedAccount(deps model.Dependencies, c model.WebContext) {
 }
 
 // @Summary					Logout from the current session
+// @Description				End the session the request was made with, its token can't be used anymore.
 // @Tags						Auth
 // @securityDefinitions.apikey	ApiKeyAuth
 // @Produce					json
@@ -213,10 +264,20 @@ func HandleLogout(deps model.Dependencies, c model.WebContext) {
 		return
 	}
 
+	if sessionID := c.GetSessionID(); sessionID != "" {
+		err := deps.Domains().Auth().RevokeSession(c.Request().Context(), c.GetAccount().ID, sessionID)
+		if err != nil && !errors.Is(err, model.ErrNotFound) {
+			deps.Logger().WithError(err).Error("failed to end session")
+			response.SendInternalServerError(c)
+			return
+		}
+	}
+
 	// Remove token cookie
-	c.Request().AddCookie(&http.Cookie{
-		Name:  "token",
-		Value: "",
+	http.SetCookie(c.ResponseWriter(), &http.Cookie{
+		Name:   "token",
+		Value:  "",
+		MaxAge: -1,
 	})
 
 	response.SendJSON(c, http.StatusOK, nil)
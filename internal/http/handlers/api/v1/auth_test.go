@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/go-shiori/shiori/internal/model"
 	"github.com/go-shiori/shiori/internal/testutil"
@@ -102,6 +103,32 @@ func TestHandleRefreshToken(t *testing.T) {
 			require.NotZero(t, value)
 		})
 	})
+
+	t.Run("ends the refreshed session", func(t *testing.T) {
+		ctx := context.Background()
+		account, err := deps.Domains().Accounts().GetAccountByUsername(ctx, testutil.GetValidAccount().Username)
+		require.NoError(t, err)
+
+		token, err := deps.Domains().Auth().CreateTokenForAccount(ctx, account, time.Now().Add(time.Hour), "")
+		require.NoError(t, err)
+		_, session, err := deps.Domains().Auth().CheckSession(ctx, token)
+		require.NoError(t, err)
+
+		w := testutil.PerformRequest(deps, HandleRefreshToken, "POST", "/refresh",
+			testutil.WithAccount(account),
+			testutil.WithSessionID(session.ID),
+		)
+		require.Equal(t, http.StatusAccepted, w.Code)
+
+		_, err = deps.Domains().Auth().CheckToken(ctx, token)
+		require.Error(t, err)
+
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertMessageJSONKeyValue(t, "token", func(t *testing.T, value any) {
+			_, err := deps.Domains().Auth().CheckToken(ctx, value.(string))
+			require.NoError(t, err)
+		})
+	})
 }
 
 func TestHandleGetMe(t *testing.T) {
@@ -236,4 +263,31 @@ func TestHandleLogout(t *testing.T) {
 		HandleLogout(deps, c)
 		require.Equal(t, http.StatusOK, w.Code)
 	})
+
+	t.Run("ends the session", func(t *testing.T) {
+		ctx := context.Background()
+		account := testutil.FakeAccount(false)
+		_, err := deps.Domains().Auth().CreateTokenForAccount(ctx, account, time.Now().Add(time.Hour), "")
+		require.NoError(t, err)
+
+		sessions, err := deps.Domains().Auth().ListSessions(ctx, account.ID)
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+
+		w := testutil.PerformRequest(deps, HandleLogout, http.MethodPost, "/api/v1/auth/logout",
+			testutil.WithAccount(account),
+			testutil.WithSessionID(sessions[0].ID),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		sessions, err = deps.Domains().Auth().ListSessions(ctx, account.ID)
+		require.NoError(t, err)
+		require.Empty(t, sessions)
+
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+		require.Equal(t, "token", cookies[0].Name)
+		require.Empty(t, cookies[0].Value)
+		require.Negative(t, cookies[0].MaxAge)
+	})
 }
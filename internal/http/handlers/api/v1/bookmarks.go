@@ -1,11 +1,15 @@
 package api_v1
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/go-shiori/shiori/internal/http/middleware"
@@ -33,6 +37,512 @@ func (p *updateCachePayload) IsValid() error {
 	return nil
 }
 
+type listBookmarksResponseMessage struct {
+	Bookmarks []model.BookmarkDTO `json:"bookmarks"`
+	Page      int                 `json:"page"`
+	MaxPage   int                 `json:"max_page"`
+	Total     int                 `json:"total"`
+}
+
+// splitQueryList splits a comma separated query parameter, ignoring empty items
+func splitQueryList(value string) []string {
+	result := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// bookmarksAccountScope returns the account bookmark operations are restricted to. Owners
+// can pass all_accounts=true to operate on the bookmarks of every account, in which case
+// zero is returned.
+func bookmarksAccountScope(c model.WebContext) model.DBID {
+	account := c.GetAccount()
+	if account.IsOwner() {
+		if all, _ := strconv.ParseBool(c.Request().URL.Query().Get("all_accounts")); all {
+			return 0
+		}
+	}
+	return account.ID
+}
+
+// validateBookmarkURL checks that the provided value is an absolute http(s) URL
+func validateBookmarkURL(value string) error {
+	if value == "" {
+		return fmt.Errorf("url should not be empty")
+	}
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return fmt.Errorf("url should be a valid http or https URL")
+	}
+	return nil
+}
+
+type createBookmarkPayload struct {
+	URL           string   `json:"url"`
+	Title         string   `json:"title"`
+	Excerpt       string   `json:"excerpt"`
+	Public        int      `json:"public"`
+	Tags          []string `json:"tags"`
+	CreateArchive bool     `json:"create_archive"`
+	CreateEbook   bool     `json:"create_ebook"`
+	Async         bool     `json:"async"`
+}
+
+// newCreateBookmarkPayload returns the payload with its defaults
+func newCreateBookmarkPayload() *createBookmarkPayload {
+	return &createBookmarkPayload{
+		Async: true,
+	}
+}
+
+func (p *createBookmarkPayload) IsValid() error {
+	if err := validateBookmarkURL(p.URL); err != nil {
+		return err
+	}
+	if p.Public != 0 && p.Public != 1 {
+		return fmt.Errorf("public should be 0 or 1")
+	}
+	return nil
+}
+
+func (p *createBookmarkPayload) ToBookmarkDTO() model.BookmarkDTO {
+	bookmark := model.BookmarkDTO{
+		URL:           p.URL,
+		Title:         strings.TrimSpace(p.Title),
+		Excerpt:       strings.TrimSpace(p.Excerpt),
+		Public:        p.Public,
+		Tags:          []model.TagDTO{},
+		CreateArchive: p.CreateArchive,
+		CreateEbook:   p.CreateEbook,
+	}
+	for _, name := range p.Tags {
+		if name = model.NormalizeTagName(name); name != "" {
+			bookmark.Tags = append(bookmark.Tags, model.TagDTO{Tag: model.Tag{Name: name}})
+		}
+	}
+	return bookmark
+}
+
+type updateBookmarkPayload struct {
+	URL     *string   `json:"url"`
+	Title   *string   `json:"title"`
+	Excerpt *string   `json:"excerpt"`
+	Public  *int      `json:"public"`
+	Tags    *[]string `json:"tags"`
+}
+
+func (p *updateBookmarkPayload) IsValid() error {
+	if p.URL != nil {
+		if err := validateBookmarkURL(*p.URL); err != nil {
+			return err
+		}
+	}
+	if p.Title != nil && strings.TrimSpace(*p.Title) == "" {
+		return fmt.Errorf("title should not be empty")
+	}
+	if p.Public != nil && *p.Public != 0 && *p.Public != 1 {
+		return fmt.Errorf("public should be 0 or 1")
+	}
+	return nil
+}
+
+// ApplyTo sets the provided fields into the bookmark. When tags are provided they
+// replace the current ones: missing tags are marked as deleted and new ones are appended.
+func (p *updateBookmarkPayload) ApplyTo(bookmark *model.BookmarkDTO) {
+	if p.URL != nil {
+		bookmark.URL = *p.URL
+	}
+	if p.Title != nil {
+		bookmark.Title = strings.TrimSpace(*p.Title)
+	}
+	if p.Excerpt != nil {
+		bookmark.Excerpt = strings.TrimSpace(*p.Excerpt)
+	}
+	if p.Public != nil {
+		bookmark.Public = *p.Public
+	}
+	if p.Tags == nil {
+		return
+	}
+
+	for i := range bookmark.Tags {
+		bookmark.Tags[i].Deleted = true
+	}
+
+	for _, name := range *p.Tags {
+		name = model.NormalizeTagName(name)
+		if name == "" {
+			continue
+		}
+
+		found := false
+		for i := range bookmark.Tags {
+			if bookmark.Tags[i].Name == name {
+				bookmark.Tags[i].Deleted = false
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			bookmark.Tags = append(bookmark.Tags, model.TagDTO{Tag: model.Tag{Name: name}})
+		}
+	}
+}
+
+// @Summary					List bookmarks
+// @Description				List and search the bookmarks of the current account, newest first or by relevance to the text searched. When text is searched, the bookmarks have a snippet of their matching text, escaped as HTML with the matches between <mark> and </mark>.
+// @Tags						Bookmarks
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Param						keyword			query		string	false	"Search query, like site:github.com tag:go -tag:old is:public has:archive before:2024-01-01 \"exact phrase\""
+// @Param						tags			query		string	false	"Comma separated list of tags the bookmarks must have"
+// @Param						exclude			query		string	false	"Comma separated list of tags the bookmarks must not have"
+// @Param						link_status		query		string	false	"Status of the latest link check: ok, broken, redirected or unchecked"
+// @Param						collection_id	query		integer	false	"Only list the bookmarks directly in this collection, 0 for the ones in none"
+// @Param						search_id		query		integer	false	"Only list the bookmarks matching this saved search"
+// @Param						order			query		string	false	"newest (default) or relevance, the best matches of the text searched first"
+// @Param						page			query		integer	false	"Page number, starting at 1"
+// @Param						all_accounts	query		boolean	false	"List the bookmarks of every account, owners only"
+// @Success					200				{object}	listBookmarksResponseMessage
+// @Failure					400				{object}	nil	"Invalid page, search query, order, link status, collection or saved search"
+// @Failure					401				{object}	nil	"Authentication required"
+// @Failure					404				{object}	nil	"Saved search not found"
+// @Failure					500				{object}	nil	"Internal server error"
+// @Router						/api/v1/bookmarks [get]
+func HandleListBookmarks(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	query := c.Request().URL.Query()
+
+	page := 1
+	if pageParam := query.Get("page"); pageParam != "" {
+		var err error
+		page, err = strconv.Atoi(pageParam)
+		if err != nil || page < 1 {
+			response.SendError(c, http.StatusBadRequest, "Invalid page")
+			return
+		}
+	}
+
+	pageSize := deps.Config().Http.BookmarksPageSize
+	opts := model.ListBookmarksOptions{
+		AccountID:    bookmarksAccountScope(c),
+		Keyword:      strings.TrimSpace(query.Get("keyword")),
+		Tags:         splitQueryList(query.Get("tags")),
+		ExcludedTags: splitQueryList(query.Get("exclude")),
+		OrderMethod:  model.ByLastAdded,
+		Limit:        pageSize,
+		Offset:       (page - 1) * pageSize,
+	}
+
+	if _, err := model.ParseSearchQuery(opts.Keyword); err != nil {
+		response.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	switch query.Get("order") {
+	case "", "newest":
+	case "relevance":
+		opts.OrderMethod = model.ByRelevance
+	default:
+		response.SendError(c, http.StatusBadRequest, "Invalid order")
+		return
+	}
+
+	if linkStatus := query.Get("link_status"); linkStatus != "" {
+		opts.LinkStatus = model.LinkStatus(linkStatus)
+		if err := opts.LinkStatus.IsValid(); err != nil {
+			response.SendError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	if collectionParam := query.Get("collection_id"); collectionParam != "" {
+		id, err := strconv.Atoi(collectionParam)
+		if err != nil || id < 0 {
+			response.SendError(c, http.StatusBadRequest, "Invalid collection ID")
+			return
+		}
+		opts.CollectionID = model.Ptr(model.DBID(id))
+	}
+
+	if !applySavedSearchParam(deps, c, &opts) {
+		return
+	}
+
+	total, err := deps.Domains().Bookmarks().CountBookmarks(c.Request().Context(), opts)
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to count bookmarks")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	bookmarks, err := deps.Domains().Bookmarks().ListBookmarks(c.Request().Context(), opts)
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to list bookmarks")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, listBookmarksResponseMessage{
+		Bookmarks: bookmarks,
+		Page:      page,
+		MaxPage:   int(math.Ceil(float64(total) / float64(pageSize))),
+		Total:     total,
+	})
+}
+
+// @Summary					Get bookmark
+// @Description				Get a bookmark by ID
+// @Tags						Bookmarks
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Param						id				path		int		true	"Bookmark ID"
+// @Param						all_accounts	query		boolean	false	"Look up bookmarks of every account, owners only"
+// @Success					200				{object}	model.BookmarkDTO
+// @Failure					400				{object}	nil	"Invalid bookmark ID"
+// @Failure					401				{object}	nil	"Authentication required"
+// @Failure					404				{object}	nil	"Bookmark not found"
+// @Failure					500				{object}	nil	"Internal server error"
+// @Router						/api/v1/bookmarks/{id} [get]
+func HandleGetBookmark(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	bookmarkID, err := strconv.Atoi(c.Request().PathValue("id"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid bookmark ID")
+		return
+	}
+
+	bookmark, err := deps.Domains().Bookmarks().GetBookmark(c.Request().Context(), model.DBID(bookmarkID), bookmarksAccountScope(c))
+	if err != nil {
+		if errors.Is(err, model.ErrBookmarkNotFound) {
+			response.SendError(c, http.StatusNotFound, "Bookmark not found")
+			return
+		}
+		deps.Logger().WithError(err).Error("failed to get bookmark")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, bookmark)
+}
+
+// processNewBookmark downloads and processes the content of a newly created bookmark,
+// keeping the title and excerpt if the user provided them.
+func processNewBookmark(ctx context.Context, deps model.Dependencies, bookmark model.BookmarkDTO) (*model.BookmarkDTO, error) {
+	userTitle := bookmark.Title
+	if userTitle == bookmark.URL {
+		userTitle = ""
+	}
+	userExcerpt := bookmark.Excerpt
+
+	processed, err := deps.Domains().Bookmarks().UpdateBookmarkCache(ctx, bookmark, false, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if userTitle != "" {
+		processed.Title = userTitle
+	}
+	if userExcerpt != "" {
+		processed.Excerpt = userExcerpt
+	}
+
+	return deps.Domains().Bookmarks().UpdateBookmark(ctx, *processed, processed.AccountID)
+}
+
+// @Summary					Create bookmark
+// @Description				Create a new bookmark. Its content is downloaded by a background job unless async is false. The rules of the account can add tags, change its options or reject it.
+// @Tags						Bookmarks
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Accept						json
+// @Produce					json
+// @Param						payload	body		createBookmarkPayload	true	"Bookmark data"
+// @Success					201		{object}	model.BookmarkDTO
+// @Failure					400		{object}	nil	"Invalid request payload"
+// @Failure					401		{object}	nil	"Authentication required"
+// @Failure					422		{object}	nil	"Bookmark rejected by a rule"
+// @Failure					500		{object}	nil	"Internal server error"
+// @Router						/api/v1/bookmarks [post]
+func HandleCreateBookmark(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	payload := newCreateBookmarkPayload()
+	if err := json.NewDecoder(c.Request().Body).Decode(payload); err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	if err := payload.IsValid(); err != nil {
+		response.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	newBookmark := payload.ToBookmarkDTO()
+	newBookmark.AccountID = c.GetAccount().ID
+
+	bookmark, err := deps.Domains().Bookmarks().CreateBookmark(c.Request().Context(), newBookmark)
+	if errors.Is(err, model.ErrBookmarkRejected) {
+		response.SendError(c, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to create bookmark")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	if payload.Async {
+		if _, err := deps.Domains().Jobs().Enqueue(c.Request().Context(), model.JobTypeProcessBookmark, model.ProcessBookmarkJobPayload{
+			BookmarkID:    bookmark.ID,
+			KeepTitle:     bookmark.Title != bookmark.URL,
+			KeepExcerpt:   bookmark.Excerpt != "",
+			CreateArchive: bookmark.CreateArchive,
+			CreateEbook:   bookmark.CreateEbook,
+		}); err != nil {
+			// The bookmark is already saved, its content can be downloaded later with the cache update
+			deps.Logger().WithError(err).WithField("id", bookmark.ID).Error("failed to queue bookmark processing")
+		}
+	} else {
+		processed, err := processNewBookmark(c.Request().Context(), deps, *bookmark)
+		if errors.Is(err, model.ErrBookmarkRejected) {
+			// A rule matched the downloaded content, the bookmark shouldn't have been saved
+			if err := deps.Domains().Bookmarks().DeleteBookmarks(c.Request().Context(), []int{bookmark.ID}, bookmark.AccountID); err != nil {
+				deps.Logger().WithError(err).WithField("id", bookmark.ID).Error("failed to delete rejected bookmark")
+			}
+			response.SendError(c, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+
+		if err != nil {
+			// The bookmark is already saved, return it without the processed content
+			deps.Logger().WithError(err).WithField("id", bookmark.ID).Error("failed to process bookmark")
+		} else {
+			bookmark = processed
+		}
+	}
+
+	response.SendJSON(c, http.StatusCreated, bookmark)
+}
+
+// @Summary					Update bookmark
+// @Description				Update the provided fields of a bookmark. Tags, if provided, replace the current ones.
+// @Tags						Bookmarks
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Accept						json
+// @Produce					json
+// @Param						id		path		int						true	"Bookmark ID"
+// @Param						payload	body		updateBookmarkPayload	true	"Bookmark data"
+// @Success					200		{object}	model.BookmarkDTO
+// @Failure					400		{object}	nil	"Invalid bookmark ID or request payload"
+// @Failure					401		{object}	nil	"Authentication required"
+// @Failure					404		{object}	nil	"Bookmark not found"
+// @Failure					500		{object}	nil	"Internal server error"
+// @Router						/api/v1/bookmarks/{id} [patch]
+func HandleUpdateBookmark(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	bookmarkID, err := strconv.Atoi(c.Request().PathValue("id"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid bookmark ID")
+		return
+	}
+
+	var payload updateBookmarkPayload
+	if err := json.NewDecoder(c.Request().Body).Decode(&payload); err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	if err := payload.IsValid(); err != nil {
+		response.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	bookmark, err := deps.Domains().Bookmarks().GetBookmark(c.Request().Context(), model.DBID(bookmarkID), bookmarksAccountScope(c))
+	if err != nil {
+		if errors.Is(err, model.ErrBookmarkNotFound) {
+			response.SendError(c, http.StatusNotFound, "Bookmark not found")
+			return
+		}
+		deps.Logger().WithError(err).Error("failed to get bookmark")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	payload.ApplyTo(bookmark)
+
+	updated, err := deps.Domains().Bookmarks().UpdateBookmark(c.Request().Context(), *bookmark, bookmark.AccountID)
+	if err != nil {
+		if errors.Is(err, model.ErrBookmarkNotFound) {
+			response.SendError(c, http.StatusNotFound, "Bookmark not found")
+			return
+		}
+		deps.Logger().WithError(err).Error("failed to update bookmark")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, updated)
+}
+
+// @Summary					Delete bookmark
+// @Description				Delete a bookmark and its thumbnail, archive and ebook
+// @Tags						Bookmarks
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Param						id	path		int	true	"Bookmark ID"
+// @Success					204	{object}	nil	"No content"
+// @Failure					400	{object}	nil	"Invalid bookmark ID"
+// @Failure					401	{object}	nil	"Authentication required"
+// @Failure					404	{object}	nil	"Bookmark not found"
+// @Failure					500	{object}	nil	"Internal server error"
+// @Router						/api/v1/bookmarks/{id} [delete]
+func HandleDeleteBookmark(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	bookmarkID, err := strconv.Atoi(c.Request().PathValue("id"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid bookmark ID")
+		return
+	}
+
+	exists, err := deps.Domains().Bookmarks().BookmarkExists(c.Request().Context(), bookmarkID, bookmarksAccountScope(c))
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to check if bookmark exists")
+		response.SendInternalServerError(c)
+		return
+	}
+	if !exists {
+		response.SendError(c, http.StatusNotFound, "Bookmark not found")
+		return
+	}
+
+	if err := deps.Domains().Bookmarks().DeleteBookmarks(c.Request().Context(), []int{bookmarkID}, bookmarksAccountScope(c)); err != nil {
+		deps.Logger().WithError(err).Error("failed to delete bookmark")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusNoContent, nil)
+}
+
 type readableResponseMessage struct {
 	Content string `json:"content"`
 	HTML    string `json:"html"`
@@ -59,7 +569,7 @@ func HandleBookmarkReadable(deps model.Dependencies, c model.WebContext) {
 		return
 	}
 
-	bookmark, err := deps.Domains().Bookmarks().GetBookmark(c.Request().Context(), model.DBID(bookmarkID))
+	bookmark, err := deps.Domains().Bookmarks().GetBookmark(c.Request().Context(), model.DBID(bookmarkID), bookmarksAccountScope(c))
 	if err != nil {
 		response.SendError(c, http.StatusNotFound, "Bookmark not found")
 		return
@@ -100,7 +610,7 @@ func HandleUpdateCache(deps model.Dependencies, c model.WebContext) {
 	}
 
 	// Get bookmarks from database
-	bookmarks, err := deps.Domains().Bookmarks().GetBookmarks(c.Request().Context(), payload.Ids)
+	bookmarks, err := deps.Domains().Bookmarks().GetBookmarks(c.Request().Context(), payload.Ids, bookmarksAccountScope(c))
 	if err != nil {
 		response.SendError(c, http.StatusInternalServerError, "Failed to get bookmarks")
 		return
@@ -201,7 +711,7 @@ func HandleGetBookmarkTags(deps model.Dependencies, c model.WebContext) {
 	}
 
 	// Check if bookmark exists
-	exists, err := deps.Domains().Bookmarks().BookmarkExists(c.Request().Context(), bookmarkID)
+	exists, err := deps.Domains().Bookmarks().BookmarkExists(c.Request().Context(), bookmarkID, bookmarksAccountScope(c))
 	if err != nil {
 		response.SendError(c, http.StatusInternalServerError, "Failed to check if bookmark exists")
 		return
@@ -248,7 +758,7 @@ func (p *bookmarkTagPayload) IsValid() error {
 //	@Failure					404	{object}	nil	"Bookmark or tag not found"
 //	@Router						/api/v1/bookmarks/{id}/tags [post]
 func HandleAddTagToBookmark(deps model.Dependencies, c model.WebContext) {
-	if err := middleware.RequireLoggedInAdmin(deps, c); err != nil {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
 		response.SendError(c, http.StatusForbidden, err.Error())
 		return
 	}
@@ -272,7 +782,7 @@ func HandleAddTagToBookmark(deps model.Dependencies, c model.WebContext) {
 	}
 
 	// Add tag to bookmark
-	err = deps.Domains().Bookmarks().AddTagToBookmark(c.Request().Context(), bookmarkID, payload.TagID)
+	err = deps.Domains().Bookmarks().AddTagToBookmark(c.Request().Context(), bookmarkID, payload.TagID, bookmarksAccountScope(c))
 	if err != nil {
 		if errors.Is(err, model.ErrBookmarkNotFound) {
 			response.SendError(c, http.StatusNotFound, "Bookmark not found")
@@ -326,7 +836,7 @@ func HandleRemoveTagFromBookmark(deps model.Dependencies, c model.WebContext) {
 	}
 
 	// Remove tag from bookmark
-	err = deps.Domains().Bookmarks().RemoveTagFromBookmark(c.Request().Context(), bookmarkID, payload.TagID)
+	err = deps.Domains().Bookmarks().RemoveTagFromBookmark(c.Request().Context(), bookmarkID, payload.TagID, bookmarksAccountScope(c))
 	if err != nil {
 		if errors.Is(err, model.ErrBookmarkNotFound) {
 			response.SendError(c, http.StatusNotFound, "Bookmark not found")
@@ -374,7 +884,7 @@ func HandleBulkUpdateBookmarkTags(deps model.Dependencies, c model.WebContext) {
 	}
 
 	// Use the domain method to update bookmark tags
-	err := deps.Domains().Bookmarks().BulkUpdateBookmarkTags(c.Request().Context(), payload.BookmarkIDs, payload.TagIDs)
+	err := deps.Domains().Bookmarks().BulkUpdateBookmarkTags(c.Request().Context(), payload.BookmarkIDs, payload.TagIDs, bookmarksAccountScope(c))
 	if err != nil {
 		if errors.Is(err, model.ErrBookmarkNotFound) {
 			response.SendError(c, http.StatusNotFound, "No bookmarks found")
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"strconv"
 	"testing"
 	"time"
@@ -190,7 +191,7 @@ func TestHandleUpdateCache(t *testing.T) {
 		time.Sleep(1 * time.Second)
 
 		// Verify bookmark was updated
-		updatedBookmark, exists, err := deps.Database().GetBookmark(ctx, savedBookmark[0].ID, "")
+		updatedBookmark, exists, err := deps.Database().GetBookmark(ctx, savedBookmark[0].ID, "", 0)
 		require.NoError(t, err)
 		require.True(t, exists)
 		require.True(t, updatedBookmark.HasEbook)
@@ -317,3 +318,687 @@ func TestHandleUpdateBookmarkTags(t *testing.T) {
 		response.AssertOk(t)
 	})
 }
+
+func TestHandleListBookmarks(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("requires authentication", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleListBookmarks, http.MethodGet, "/api/v1/bookmarks")
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("invalid page", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(
+			deps,
+			HandleListBookmarks,
+			http.MethodGet,
+			"/api/v1/bookmarks",
+			testutil.WithFakeUser(),
+			testutil.WithRequestQueryParam("page", "0"),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("paginated results", func(t *testing.T) {
+		cfg, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		cfg.Http.BookmarksPageSize = 2
+
+		for i := 0; i < 3; i++ {
+			_, err := deps.Database().SaveBookmarks(ctx, true, *testutil.GetValidBookmark())
+			require.NoError(t, err)
+		}
+
+		w := testutil.PerformRequest(
+			deps,
+			HandleListBookmarks,
+			http.MethodGet,
+			"/api/v1/bookmarks",
+			testutil.WithFakeUser(),
+			testutil.WithRequestQueryParam("page", "2"),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertOk(t)
+		response.AssertMessageJSONKeyValue(t, "bookmarks", func(t *testing.T, value any) {
+			require.Len(t, value, 1)
+		})
+		response.AssertMessageJSONKeyValue(t, "page", func(t *testing.T, value any) {
+			require.Equal(t, float64(2), value)
+		})
+		response.AssertMessageJSONKeyValue(t, "max_page", func(t *testing.T, value any) {
+			require.Equal(t, float64(2), value)
+		})
+		response.AssertMessageJSONKeyValue(t, "total", func(t *testing.T, value any) {
+			require.Equal(t, float64(3), value)
+		})
+	})
+
+	t.Run("filter by tag", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		tagged := testutil.GetValidBookmark()
+		tagged.Tags = []model.TagDTO{{Tag: model.Tag{Name: "golang"}}}
+		_, err := deps.Database().SaveBookmarks(ctx, true, *tagged, *testutil.GetValidBookmark())
+		require.NoError(t, err)
+
+		w := testutil.PerformRequest(
+			deps,
+			HandleListBookmarks,
+			http.MethodGet,
+			"/api/v1/bookmarks",
+			testutil.WithFakeUser(),
+			testutil.WithRequestQueryParam("tags", "golang"),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertMessageJSONKeyValue(t, "total", func(t *testing.T, value any) {
+			require.Equal(t, float64(1), value)
+		})
+	})
+
+	t.Run("invalid search query", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(
+			deps,
+			HandleListBookmarks,
+			http.MethodGet,
+			"/api/v1/bookmarks",
+			testutil.WithFakeUser(),
+			testutil.WithRequestQueryParam("keyword", "is:hidden"),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid order", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(
+			deps,
+			HandleListBookmarks,
+			http.MethodGet,
+			"/api/v1/bookmarks",
+			testutil.WithFakeUser(),
+			testutil.WithRequestQueryParam("order", "oldest"),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("order by relevance with snippets", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		bookmark := testutil.GetValidBookmark()
+		bookmark.Content = "A page about gophers"
+		_, err := deps.Database().SaveBookmarks(ctx, true, *bookmark, *testutil.GetValidBookmark())
+		require.NoError(t, err)
+
+		w := testutil.PerformRequest(
+			deps,
+			HandleListBookmarks,
+			http.MethodGet,
+			"/api/v1/bookmarks",
+			testutil.WithFakeUser(),
+			testutil.WithRequestQueryParam("keyword", "gophers"),
+			testutil.WithRequestQueryParam("order", "relevance"),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertMessageJSONKeyValue(t, "bookmarks", func(t *testing.T, value any) {
+			bookmarks := value.([]any)
+			require.Len(t, bookmarks, 1)
+			require.Equal(t, "A page about <mark>gophers</mark>", bookmarks[0].(map[string]any)["snippet"])
+		})
+	})
+
+	t.Run("filter by search query", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		tagged := testutil.GetValidBookmark()
+		tagged.Public = 1
+		tagged.Tags = []model.TagDTO{{Tag: model.Tag{Name: "golang"}}}
+		_, err := deps.Database().SaveBookmarks(ctx, true, *tagged, *testutil.GetValidBookmark())
+		require.NoError(t, err)
+
+		w := testutil.PerformRequest(
+			deps,
+			HandleListBookmarks,
+			http.MethodGet,
+			"/api/v1/bookmarks",
+			testutil.WithFakeUser(),
+			testutil.WithRequestQueryParam("keyword", "tag:golang is:public"),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertMessageJSONKeyValue(t, "total", func(t *testing.T, value any) {
+			require.Equal(t, float64(1), value)
+		})
+	})
+
+	t.Run("invalid link status", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(
+			deps,
+			HandleListBookmarks,
+			http.MethodGet,
+			"/api/v1/bookmarks",
+			testutil.WithFakeUser(),
+			testutil.WithRequestQueryParam("link_status", "unknown"),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("filter by link status", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		saved, err := deps.Database().SaveBookmarks(ctx, true, *testutil.GetValidBookmark(), *testutil.GetValidBookmark())
+		require.NoError(t, err)
+		_, err = deps.Database().CreateLinkCheck(ctx, model.LinkCheck{BookmarkID: saved[0].ID, URL: saved[0].URL, StatusCode: http.StatusNotFound})
+		require.NoError(t, err)
+
+		w := testutil.PerformRequest(
+			deps,
+			HandleListBookmarks,
+			http.MethodGet,
+			"/api/v1/bookmarks",
+			testutil.WithFakeUser(),
+			testutil.WithRequestQueryParam("link_status", "broken"),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertMessageJSONKeyValue(t, "total", func(t *testing.T, value any) {
+			require.Equal(t, float64(1), value)
+		})
+	})
+
+	t.Run("filter by saved search", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		tagged := testutil.GetValidBookmark()
+		tagged.Tags = []model.TagDTO{{Tag: model.Tag{Name: "golang"}}}
+		_, err := deps.Database().SaveBookmarks(ctx, true, *tagged, *testutil.GetValidBookmark())
+		require.NoError(t, err)
+
+		search, err := deps.Database().CreateSavedSearch(ctx, model.SavedSearch{
+			AccountID: testutil.FakeAccountID,
+			Name:      "Go",
+			Filter:    model.SearchFilter{Tags: []string{"golang"}},
+		})
+		require.NoError(t, err)
+		theirs, err := deps.Database().CreateSavedSearch(ctx, model.SavedSearch{
+			AccountID: testutil.FakeAccountID + 1,
+			Name:      "Go",
+		})
+		require.NoError(t, err)
+
+		w := testutil.PerformRequest(
+			deps,
+			HandleListBookmarks,
+			http.MethodGet,
+			"/api/v1/bookmarks",
+			testutil.WithFakeUser(),
+			testutil.WithRequestQueryParam("search_id", strconv.Itoa(int(search.ID))),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertMessageJSONKeyValue(t, "total", func(t *testing.T, value any) {
+			require.Equal(t, float64(1), value)
+		})
+
+		w = testutil.PerformRequest(
+			deps,
+			HandleListBookmarks,
+			http.MethodGet,
+			"/api/v1/bookmarks",
+			testutil.WithFakeUser(),
+			testutil.WithRequestQueryParam("search_id", strconv.Itoa(int(theirs.ID))),
+		)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("only bookmarks of the account", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		other := testutil.GetValidBookmark()
+		other.AccountID = testutil.FakeAccountID + 1
+		_, err := deps.Database().SaveBookmarks(ctx, true, *testutil.GetValidBookmark(), *other)
+		require.NoError(t, err)
+
+		w := testutil.PerformRequest(
+			deps,
+			HandleListBookmarks,
+			http.MethodGet,
+			"/api/v1/bookmarks",
+			testutil.WithFakeUser(),
+			testutil.WithRequestQueryParam("all_accounts", "true"),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertMessageJSONKeyValue(t, "total", func(t *testing.T, value any) {
+			require.Equal(t, float64(1), value)
+		})
+
+		w = testutil.PerformRequest(
+			deps,
+			HandleListBookmarks,
+			http.MethodGet,
+			"/api/v1/bookmarks",
+			testutil.WithFakeAdmin(),
+			testutil.WithRequestQueryParam("all_accounts", "true"),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		response = testutil.NewTestResponseFromRecorder(w)
+		response.AssertMessageJSONKeyValue(t, "total", func(t *testing.T, value any) {
+			require.Equal(t, float64(2), value)
+		})
+	})
+}
+
+func TestHandleGetBookmark(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("requires authentication", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(
+			deps,
+			HandleGetBookmark,
+			http.MethodGet,
+			"/api/v1/bookmarks/1",
+			testutil.WithRequestPathValue("id", "1"),
+		)
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("bookmark not found", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(
+			deps,
+			HandleGetBookmark,
+			http.MethodGet,
+			"/api/v1/bookmarks/999",
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", "999"),
+		)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		bookmark := testutil.GetValidBookmark()
+		saved, err := deps.Database().SaveBookmarks(ctx, true, *bookmark)
+		require.NoError(t, err)
+
+		w := testutil.PerformRequest(
+			deps,
+			HandleGetBookmark,
+			http.MethodGet,
+			"/api/v1/bookmarks/"+strconv.Itoa(saved[0].ID),
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", strconv.Itoa(saved[0].ID)),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertOk(t)
+		response.AssertMessageJSONKeyValue(t, "url", func(t *testing.T, value any) {
+			require.Equal(t, bookmark.URL, value)
+		})
+	})
+}
+
+func TestHandleCreateBookmark(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	ctx := context.Background()
+
+	t.Run("requires authentication", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(
+			deps,
+			HandleCreateBookmark,
+			http.MethodPost,
+			"/api/v1/bookmarks",
+		)
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("invalid url", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(
+			deps,
+			HandleCreateBookmark,
+			http.MethodPost,
+			"/api/v1/bookmarks",
+			testutil.WithFakeAdmin(),
+			testutil.WithBody(`{"url": "not a url"}`),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		// Processing fails for an unreachable address, but the bookmark is still created
+		w := testutil.PerformRequest(
+			deps,
+			HandleCreateBookmark,
+			http.MethodPost,
+			"/api/v1/bookmarks",
+			testutil.WithFakeAdmin(),
+			testutil.WithBody(`{"url": "http://127.0.0.1:1/page", "title": "My page", "tags": ["one", "two"], "async": false}`),
+		)
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertOk(t)
+		response.AssertMessageJSONKeyValue(t, "title", func(t *testing.T, value any) {
+			require.Equal(t, "My page", value)
+		})
+		response.AssertMessageJSONKeyValue(t, "tags", func(t *testing.T, value any) {
+			require.Len(t, value, 2)
+		})
+		response.AssertMessageJSONKeyValue(t, "account_id", func(t *testing.T, value any) {
+			require.Equal(t, float64(testutil.FakeAccountID), value)
+		})
+
+		count, err := deps.Database().GetBookmarksCount(ctx, model.DBGetBookmarksOptions{})
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+	})
+	t.Run("tags are normalized like on update", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		tagNames := func(t *testing.T, w *httptest.ResponseRecorder) []string {
+			names := []string{}
+			testutil.NewTestResponseFromRecorder(w).AssertMessageJSONKeyValue(t, "tags", func(t *testing.T, value any) {
+				for _, tag := range value.([]any) {
+					names = append(names, tag.(map[string]any)["name"].(string))
+				}
+			})
+			return names
+		}
+
+		w := testutil.PerformRequest(
+			deps,
+			HandleCreateBookmark,
+			http.MethodPost,
+			"/api/v1/bookmarks",
+			testutil.WithFakeAdmin(),
+			testutil.WithBody(`{"url": "http://127.0.0.1:1/page", "tags": ["  Go   Lang "], "async": false}`),
+		)
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Equal(t, []string{"go lang"}, tagNames(t, w))
+		id := strconv.Itoa(int(testutil.NewTestResponseFromRecorder(w).AssertMessageJSONContainsKey(t, "id").(float64)))
+
+		w = testutil.PerformRequest(
+			deps,
+			HandleUpdateBookmark,
+			http.MethodPatch,
+			"/api/v1/bookmarks/"+id,
+			testutil.WithFakeAdmin(),
+			testutil.WithRequestPathValue("id", id),
+			testutil.WithBody(`{"tags": ["  Go   Lang "]}`),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, []string{"go lang"}, tagNames(t, w))
+
+		tags, err := deps.Database().GetTags(ctx, model.DBListTagsOptions{})
+		require.NoError(t, err)
+		require.Len(t, tags, 1)
+	})
+
+	t.Run("async queues a processing job", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		w := testutil.PerformRequest(
+			deps,
+			HandleCreateBookmark,
+			http.MethodPost,
+			"/api/v1/bookmarks",
+			testutil.WithFakeUser(),
+			testutil.WithBody(`{"url": "http://127.0.0.1:1/page", "title": "My page", "create_archive": true}`),
+		)
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		jobs, err := deps.Domains().Jobs().ListJobs(ctx, model.ListJobsOptions{})
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		require.Equal(t, model.JobTypeProcessBookmark, jobs[0].Type)
+		require.Equal(t, model.JobStatusPending, jobs[0].Status)
+
+		var payload model.ProcessBookmarkJobPayload
+		require.NoError(t, json.Unmarshal([]byte(jobs[0].Payload), &payload))
+		require.True(t, payload.KeepTitle)
+		require.True(t, payload.CreateArchive)
+	})
+
+	t.Run("rules change the options", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		disabled := false
+		_, err := deps.Domains().Rules().CreateRule(ctx, testutil.FakeAccount(false), model.BookmarkRule{
+			Name:       "Local",
+			Enabled:    true,
+			Conditions: model.RuleConditions{Host: "127.0.0.1"},
+			Actions:    model.RuleActions{AddTags: []string{"local"}, CreateArchive: &disabled},
+		})
+		require.NoError(t, err)
+
+		w := testutil.PerformRequest(
+			deps,
+			HandleCreateBookmark,
+			http.MethodPost,
+			"/api/v1/bookmarks",
+			testutil.WithFakeUser(),
+			testutil.WithBody(`{"url": "http://127.0.0.1:1/page", "create_archive": true}`),
+		)
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Contains(t, w.Body.String(), `"local"`)
+
+		jobs, err := deps.Domains().Jobs().ListJobs(ctx, model.ListJobsOptions{})
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+
+		var payload model.ProcessBookmarkJobPayload
+		require.NoError(t, json.Unmarshal([]byte(jobs[0].Payload), &payload))
+		require.False(t, payload.CreateArchive)
+	})
+
+	t.Run("rejected by a rule", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		_, err := deps.Domains().Rules().CreateRule(ctx, testutil.FakeAccount(false), model.BookmarkRule{
+			Name:       "Local",
+			Enabled:    true,
+			Conditions: model.RuleConditions{Host: "127.0.0.1"},
+			Actions:    model.RuleActions{Reject: true},
+		})
+		require.NoError(t, err)
+
+		w := testutil.PerformRequest(
+			deps,
+			HandleCreateBookmark,
+			http.MethodPost,
+			"/api/v1/bookmarks",
+			testutil.WithFakeUser(),
+			testutil.WithBody(`{"url": "http://127.0.0.1:1/page"}`),
+		)
+		require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		count, err := deps.Database().GetBookmarksCount(ctx, model.DBGetBookmarksOptions{})
+		require.NoError(t, err)
+		require.Zero(t, count)
+	})
+}
+
+func TestHandleUpdateBookmark(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("bookmark of another account", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		bookmark := testutil.GetValidBookmark()
+		bookmark.AccountID = testutil.FakeAccountID + 1
+		saved, err := deps.Database().SaveBookmarks(ctx, true, *bookmark)
+		require.NoError(t, err)
+		id := strconv.Itoa(saved[0].ID)
+
+		w := testutil.PerformRequest(
+			deps,
+			HandleUpdateBookmark,
+			http.MethodPatch,
+			"/api/v1/bookmarks/"+id,
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+			testutil.WithBody(`{"title": "New title"}`),
+		)
+		require.Equal(t, http.StatusNotFound, w.Code)
+
+		// Owners can reach it when asking for every account
+		w = testutil.PerformRequest(
+			deps,
+			HandleUpdateBookmark,
+			http.MethodPatch,
+			"/api/v1/bookmarks/"+id,
+			testutil.WithFakeAdmin(),
+			testutil.WithRequestPathValue("id", id),
+			testutil.WithRequestQueryParam("all_accounts", "true"),
+			testutil.WithBody(`{"title": "New title"}`),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("bookmark not found", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(
+			deps,
+			HandleUpdateBookmark,
+			http.MethodPatch,
+			"/api/v1/bookmarks/999",
+			testutil.WithFakeAdmin(),
+			testutil.WithRequestPathValue("id", "999"),
+			testutil.WithBody(`{"title": "New title"}`),
+		)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("empty title", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(
+			deps,
+			HandleUpdateBookmark,
+			http.MethodPatch,
+			"/api/v1/bookmarks/1",
+			testutil.WithFakeAdmin(),
+			testutil.WithRequestPathValue("id", "1"),
+			testutil.WithBody(`{"title": " "}`),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		bookmark := testutil.GetValidBookmark()
+		bookmark.Excerpt = "Original excerpt"
+		bookmark.Tags = []model.TagDTO{{Tag: model.Tag{Name: "old"}}}
+		saved, err := deps.Database().SaveBookmarks(ctx, true, *bookmark)
+		require.NoError(t, err)
+		id := strconv.Itoa(saved[0].ID)
+
+		w := testutil.PerformRequest(
+			deps,
+			HandleUpdateBookmark,
+			http.MethodPatch,
+			"/api/v1/bookmarks/"+id,
+			testutil.WithFakeAdmin(),
+			testutil.WithRequestPathValue("id", id),
+			testutil.WithBody(`{"title": "New title", "tags": ["new"]}`),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertOk(t)
+		response.AssertMessageJSONKeyValue(t, "title", func(t *testing.T, value any) {
+			require.Equal(t, "New title", value)
+		})
+		response.AssertMessageJSONKeyValue(t, "excerpt", func(t *testing.T, value any) {
+			require.Equal(t, "Original excerpt", value)
+		})
+		response.AssertMessageJSONKeyValue(t, "tags", func(t *testing.T, value any) {
+			tags := value.([]any)
+			require.Len(t, tags, 1)
+			require.Equal(t, "new", tags[0].(map[string]any)["name"])
+		})
+	})
+}
+
+func TestHandleDeleteBookmark(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("bookmark of another account", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		bookmark := testutil.GetValidBookmark()
+		bookmark.AccountID = testutil.FakeAccountID + 1
+		saved, err := deps.Database().SaveBookmarks(ctx, true, *bookmark)
+		require.NoError(t, err)
+		id := strconv.Itoa(saved[0].ID)
+
+		w := testutil.PerformRequest(
+			deps,
+			HandleDeleteBookmark,
+			http.MethodDelete,
+			"/api/v1/bookmarks/"+id,
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+		)
+		require.Equal(t, http.StatusNotFound, w.Code)
+
+		exists, err := deps.Database().BookmarkExists(ctx, saved[0].ID)
+		require.NoError(t, err)
+		require.True(t, exists)
+	})
+
+	t.Run("bookmark not found", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(
+			deps,
+			HandleDeleteBookmark,
+			http.MethodDelete,
+			"/api/v1/bookmarks/999",
+			testutil.WithFakeAdmin(),
+			testutil.WithRequestPathValue("id", "999"),
+		)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		saved, err := deps.Database().SaveBookmarks(ctx, true, *testutil.GetValidBookmark())
+		require.NoError(t, err)
+		id := strconv.Itoa(saved[0].ID)
+
+		w := testutil.PerformRequest(
+			deps,
+			HandleDeleteBookmark,
+			http.MethodDelete,
+			"/api/v1/bookmarks/"+id,
+			testutil.WithFakeAdmin(),
+			testutil.WithRequestPathValue("id", id),
+		)
+		require.Equal(t, http.StatusNoContent, w.Code)
+
+		exists, err := deps.Database().BookmarkExists(ctx, saved[0].ID)
+		require.NoError(t, err)
+		require.False(t, exists)
+	})
+}
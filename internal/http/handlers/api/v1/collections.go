@@ -0,0 +1,333 @@
+package api_v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-shiori/shiori/internal/http/middleware"
+	"github.com/go-shiori/shiori/internal/http/response"
+	"github.com/go-shiori/shiori/internal/model"
+)
+
+type createCollectionPayload struct {
+	Name string `json:"name"`
+	// Parent collection, top level if empty
+	ParentID *model.DBID `json:"parent_id"`
+}
+
+type updateCollectionPayload struct {
+	Name string `json:"name"`
+}
+
+type moveCollectionPayload struct {
+	// New parent collection, top level if empty
+	ParentID *model.DBID `json:"parent_id"`
+	// Position among the children of the new parent, starting at 0
+	Position int `json:"position"`
+}
+
+type moveBookmarksPayload struct {
+	BookmarkIDs []int `json:"bookmark_ids"`
+	// Collection the bookmarks are moved to, none if empty
+	CollectionID *model.DBID `json:"collection_id"`
+}
+
+func (p *moveBookmarksPayload) IsValid() error {
+	if len(p.BookmarkIDs) == 0 {
+		return fmt.Errorf("bookmark_ids should not be empty")
+	}
+	return nil
+}
+
+// collectionID returns the ID of the collection in the path, sending the error response if it is invalid.
+func collectionID(c model.WebContext) (model.DBID, bool) {
+	id, err := strconv.Atoi(c.Request().PathValue("id"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid collection ID")
+		return 0, false
+	}
+
+	return model.DBID(id), true
+}
+
+// @Summary					List collections
+// @Description				List every collection of the logged in account, ordered by position. The tree is built from their parent IDs.
+// @Tags						Collections
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Success					200	{array}		model.Collection
+// @Failure					401	{object}	nil	"Authentication required"
+// @Failure					500	{object}	nil	"Internal server error"
+// @Router						/api/v1/collections [get]
+func HandleListCollections(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	collections, err := deps.Domains().Collections().ListCollections(c.Request().Context(), c.GetAccount())
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to list collections")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, collections)
+}
+
+// @Summary					Create a collection
+// @Description				Create a collection after the other children of its parent.
+// @Tags						Collections
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Accept						json
+// @Produce					json
+// @Param						payload	body		createCollectionPayload	true	"Collection data"
+// @Success					201		{object}	model.Collection
+// @Failure					400		{object}	nil	"Invalid collection data"
+// @Failure					401		{object}	nil	"Authentication required"
+// @Failure					500		{object}	nil	"Internal server error"
+// @Router						/api/v1/collections [post]
+func HandleCreateCollection(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	var payload createCollectionPayload
+	if err := json.NewDecoder(c.Request().Body).Decode(&payload); err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	created, err := deps.Domains().Collections().CreateCollection(c.Request().Context(), c.GetAccount(), model.Collection{
+		Name:     payload.Name,
+		ParentID: payload.ParentID,
+	})
+	if err, isValidationErr := err.(model.ValidationError); isValidationErr {
+		response.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to create collection")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusCreated, created)
+}
+
+// @Summary					Get a collection
+// @Tags						Collections
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Param						id	path		int	true	"Collection ID"
+// @Success					200	{object}	model.Collection
+// @Failure					400	{object}	nil	"Invalid collection ID"
+// @Failure					401	{object}	nil	"Authentication required"
+// @Failure					404	{object}	nil	"Collection not found"
+// @Failure					500	{object}	nil	"Internal server error"
+// @Router						/api/v1/collections/{id} [get]
+func HandleGetCollection(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	id, ok := collectionID(c)
+	if !ok {
+		return
+	}
+
+	collection, err := deps.Domains().Collections().GetCollection(c.Request().Context(), c.GetAccount(), id)
+	if errors.Is(err, model.ErrNotFound) {
+		response.NotFound(c)
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to get collection")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, collection)
+}
+
+// @Summary					Rename a collection
+// @Tags						Collections
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Accept						json
+// @Produce					json
+// @Param						id		path		int						true	"Collection ID"
+// @Param						payload	body		updateCollectionPayload	true	"Collection data"
+// @Success					200		{object}	model.Collection
+// @Failure					400		{object}	nil	"Invalid collection ID or data"
+// @Failure					401		{object}	nil	"Authentication required"
+// @Failure					404		{object}	nil	"Collection not found"
+// @Failure					500		{object}	nil	"Internal server error"
+// @Router						/api/v1/collections/{id} [patch]
+func HandleUpdateCollection(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	id, ok := collectionID(c)
+	if !ok {
+		return
+	}
+
+	var payload updateCollectionPayload
+	if err := json.NewDecoder(c.Request().Body).Decode(&payload); err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	collection, err := deps.Domains().Collections().RenameCollection(c.Request().Context(), c.GetAccount(), id, payload.Name)
+	if err, isValidationErr := err.(model.ValidationError); isValidationErr {
+		response.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, model.ErrNotFound) {
+		response.NotFound(c)
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to update collection")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, collection)
+}
+
+// @Summary					Move a collection
+// @Description				Move a collection under another parent, or to the top level, at the given position among its new siblings.
+// @Tags						Collections
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Accept						json
+// @Produce					json
+// @Param						id		path		int						true	"Collection ID"
+// @Param						payload	body		moveCollectionPayload	true	"New place of the collection"
+// @Success					200		{object}	model.Collection
+// @Failure					400		{object}	nil	"Invalid collection ID, parent or position"
+// @Failure					401		{object}	nil	"Authentication required"
+// @Failure					404		{object}	nil	"Collection not found"
+// @Failure					500		{object}	nil	"Internal server error"
+// @Router						/api/v1/collections/{id}/move [post]
+func HandleMoveCollection(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	id, ok := collectionID(c)
+	if !ok {
+		return
+	}
+
+	var payload moveCollectionPayload
+	if err := json.NewDecoder(c.Request().Body).Decode(&payload); err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	collection, err := deps.Domains().Collections().MoveCollection(c.Request().Context(), c.GetAccount(), id, payload.ParentID, payload.Position)
+	if err, isValidationErr := err.(model.ValidationError); isValidationErr {
+		response.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, model.ErrNotFound) {
+		response.NotFound(c)
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to move collection")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, collection)
+}
+
+// @Summary					Delete a collection
+// @Description				Delete a collection. Its bookmarks and child collections are moved to its parent.
+// @Tags						Collections
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Param						id	path		int	true	"Collection ID"
+// @Success					204	{object}	nil
+// @Failure					400	{object}	nil	"Invalid collection ID"
+// @Failure					401	{object}	nil	"Authentication required"
+// @Failure					404	{object}	nil	"Collection not found"
+// @Failure					500	{object}	nil	"Internal server error"
+// @Router						/api/v1/collections/{id} [delete]
+func HandleDeleteCollection(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	id, ok := collectionID(c)
+	if !ok {
+		return
+	}
+
+	err := deps.Domains().Collections().DeleteCollection(c.Request().Context(), c.GetAccount(), id)
+	if errors.Is(err, model.ErrNotFound) {
+		response.NotFound(c)
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to delete collection")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusNoContent, nil)
+}
+
+// @Summary					Move bookmarks to a collection
+// @Description				Put bookmarks of the logged in account in a collection, or in none if the collection is empty.
+// @Tags						Collections
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Accept						json
+// @Param						payload	body		moveBookmarksPayload	true	"Bookmarks and their collection"
+// @Success					204		{object}	nil
+// @Failure					400		{object}	nil	"Invalid payload"
+// @Failure					401		{object}	nil	"Authentication required"
+// @Failure					404		{object}	nil	"Collection or bookmarks not found"
+// @Failure					500		{object}	nil	"Internal server error"
+// @Router						/api/v1/bookmarks/bulk/collection [put]
+func HandleMoveBookmarksToCollection(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	var payload moveBookmarksPayload
+	if err := json.NewDecoder(c.Request().Body).Decode(&payload); err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	if err := payload.IsValid(); err != nil {
+		response.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err := deps.Domains().Collections().MoveBookmarks(c.Request().Context(), c.GetAccount(), payload.CollectionID, payload.BookmarkIDs)
+	if errors.Is(err, model.ErrNotFound) || errors.Is(err, model.ErrBookmarkNotFound) {
+		response.NotFound(c)
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to move bookmarks")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusNoContent, nil)
+}
@@ -0,0 +1,204 @@
+package api_v1
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/shiori/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleCreateCollection(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("requires authentication", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleCreateCollection, http.MethodPost, "/api/v1/collections")
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("empty name", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleCreateCollection, http.MethodPost, "/api/v1/collections",
+			testutil.WithFakeUser(),
+			testutil.WithBody(`{"name": " "}`),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("nested collection", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		parent, err := deps.Domains().Collections().CreateCollection(ctx, testutil.FakeAccount(false), model.Collection{Name: "Work"})
+		require.NoError(t, err)
+
+		w := testutil.PerformRequest(deps, HandleCreateCollection, http.MethodPost, "/api/v1/collections",
+			testutil.WithFakeUser(),
+			testutil.WithBody(`{"name": "Reports", "parent_id": `+strconv.Itoa(int(parent.ID))+`}`),
+		)
+		require.Equal(t, http.StatusCreated, w.Code)
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertOk(t)
+		response.AssertMessageJSONKeyValue(t, "parent_id", func(t *testing.T, value any) {
+			require.EqualValues(t, parent.ID, value)
+		})
+
+		w = testutil.PerformRequest(deps, HandleListCollections, http.MethodGet, "/api/v1/collections", testutil.WithFakeUser())
+		require.Equal(t, http.StatusOK, w.Code)
+		testutil.NewTestResponseFromRecorder(w).AssertMessageIsListLength(t, 2)
+	})
+}
+
+func TestHandleMoveCollection(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+	account := testutil.FakeAccount(false)
+
+	t.Run("invalid id", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleMoveCollection, http.MethodPost, "/api/v1/collections/abc/move",
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", "abc"),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleMoveCollection, http.MethodPost, "/api/v1/collections/99/move",
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", "99"),
+			testutil.WithBody(`{"position": 0}`),
+		)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("inside itself", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		parent, err := deps.Domains().Collections().CreateCollection(ctx, account, model.Collection{Name: "Work"})
+		require.NoError(t, err)
+		child, err := deps.Domains().Collections().CreateCollection(ctx, account, model.Collection{Name: "Reports", ParentID: &parent.ID})
+		require.NoError(t, err)
+
+		id := strconv.Itoa(int(parent.ID))
+		w := testutil.PerformRequest(deps, HandleMoveCollection, http.MethodPost, "/api/v1/collections/"+id+"/move",
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+			testutil.WithBody(`{"parent_id": `+strconv.Itoa(int(child.ID))+`}`),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("to the top level", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		parent, err := deps.Domains().Collections().CreateCollection(ctx, account, model.Collection{Name: "Work"})
+		require.NoError(t, err)
+		child, err := deps.Domains().Collections().CreateCollection(ctx, account, model.Collection{Name: "Reports", ParentID: &parent.ID})
+		require.NoError(t, err)
+
+		id := strconv.Itoa(int(child.ID))
+		w := testutil.PerformRequest(deps, HandleMoveCollection, http.MethodPost, "/api/v1/collections/"+id+"/move",
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+			testutil.WithBody(`{"parent_id": null, "position": 0}`),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertMessageJSONKeyValue(t, "parent_id", func(t *testing.T, value any) {
+			require.Nil(t, value)
+		})
+		response.AssertMessageJSONKeyValue(t, "position", func(t *testing.T, value any) {
+			require.EqualValues(t, 0, value)
+		})
+	})
+}
+
+func TestHandleDeleteCollection(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("collection of another account", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		collection, err := deps.Database().CreateCollection(ctx, model.Collection{AccountID: testutil.FakeAccountID + 1, Name: "Theirs"})
+		require.NoError(t, err)
+
+		id := strconv.Itoa(int(collection.ID))
+		w := testutil.PerformRequest(deps, HandleDeleteCollection, http.MethodDelete, "/api/v1/collections/"+id,
+			testutil.WithFakeAdmin(),
+			testutil.WithRequestPathValue("id", id),
+		)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		collection, err := deps.Domains().Collections().CreateCollection(ctx, testutil.FakeAccount(false), model.Collection{Name: "Work"})
+		require.NoError(t, err)
+
+		id := strconv.Itoa(int(collection.ID))
+		w := testutil.PerformRequest(deps, HandleDeleteCollection, http.MethodDelete, "/api/v1/collections/"+id,
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+		)
+		require.Equal(t, http.StatusNoContent, w.Code)
+
+		_, err = deps.Domains().Collections().GetCollection(ctx, testutil.FakeAccount(false), collection.ID)
+		require.ErrorIs(t, err, model.ErrNotFound)
+	})
+}
+
+func TestHandleMoveBookmarksToCollection(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("empty bookmarks", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleMoveBookmarksToCollection, http.MethodPut, "/api/v1/bookmarks/bulk/collection",
+			testutil.WithFakeUser(),
+			testutil.WithBody(`{"bookmark_ids": []}`),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("moved bookmarks are listed in the collection", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		collection, err := deps.Domains().Collections().CreateCollection(ctx, testutil.FakeAccount(false), model.Collection{Name: "Reading"})
+		require.NoError(t, err)
+
+		bookmarks, err := deps.Database().SaveBookmarks(ctx, true,
+			model.BookmarkDTO{AccountID: testutil.FakeAccountID, URL: "https://example.com/one", Title: "One"},
+			model.BookmarkDTO{AccountID: testutil.FakeAccountID, URL: "https://example.com/two", Title: "Two"},
+		)
+		require.NoError(t, err)
+
+		w := testutil.PerformRequest(deps, HandleMoveBookmarksToCollection, http.MethodPut, "/api/v1/bookmarks/bulk/collection",
+			testutil.WithFakeUser(),
+			testutil.WithBody(`{"bookmark_ids": [`+strconv.Itoa(bookmarks[0].ID)+`], "collection_id": `+strconv.Itoa(int(collection.ID))+`}`),
+		)
+		require.Equal(t, http.StatusNoContent, w.Code)
+
+		w = testutil.PerformRequest(deps, HandleListBookmarks, http.MethodGet, "/api/v1/bookmarks?collection_id="+strconv.Itoa(int(collection.ID)),
+			testutil.WithFakeUser(),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), "https://example.com/one")
+		require.NotContains(t, w.Body.String(), "https://example.com/two")
+
+		w = testutil.PerformRequest(deps, HandleListBookmarks, http.MethodGet, "/api/v1/bookmarks?collection_id=0",
+			testutil.WithFakeUser(),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), "https://example.com/two")
+		require.NotContains(t, w.Body.String(), "https://example.com/one")
+	})
+}
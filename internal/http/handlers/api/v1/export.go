@@ -0,0 +1,65 @@
+package api_v1
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/core"
+	"github.com/go-shiori/shiori/internal/http/middleware"
+	"github.com/go-shiori/shiori/internal/http/response"
+	"github.com/go-shiori/shiori/internal/model"
+)
+
+// @Summary					Export bookmarks
+// @Description				Download the bookmarks of the account. The netscape format can be imported by browsers, json keeps every field including the content, csv suits spreadsheets and markdown is a zip archive with one file per bookmark.
+// @Tags						Bookmarks
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					html,json,text/csv,application/zip
+// @Param						format			query		string	false	"Export format: netscape (default), json, csv or markdown"
+// @Param						search_id		query		integer	false	"Only export the bookmarks matching this saved search"
+// @Param						all_accounts	query		boolean	false	"Export bookmarks of every account, owners only"
+// @Success					200				{file}		file
+// @Failure					400				{object}	nil	"Invalid format or saved search"
+// @Failure					401				{object}	nil	"Authentication required"
+// @Failure					404				{object}	nil	"Saved search not found"
+// @Router						/api/v1/export [get]
+func HandleExportBookmarks(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	format := model.ExportFormatNetscape
+	if formatParam := c.Request().URL.Query().Get("format"); formatParam != "" {
+		format = model.ExportFormat(formatParam)
+	}
+
+	if err := format.IsValid(); err != nil {
+		response.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	opts := model.ListBookmarksOptions{
+		AccountID: bookmarksAccountScope(c),
+	}
+	if !applySavedSearchParam(deps, c, &opts) {
+		return
+	}
+
+	contentType, extension := core.ExportFileType(format)
+	fileName := "shiori-bookmarks-" + time.Now().UTC().Format("2006-01-02") + extension
+
+	c.ResponseWriter().Header().Set("Content-Type", contentType)
+	c.ResponseWriter().Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	c.ResponseWriter().WriteHeader(http.StatusOK)
+
+	// The status is already sent once bookmarks are being written, errors can only be logged
+	exporter, err := core.NewBookmarkExporter(c.ResponseWriter(), format)
+	if err == nil {
+		err = deps.Domains().Bookmarks().ExportBookmarks(c.Request().Context(), exporter, opts)
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to export bookmarks")
+	}
+}
@@ -0,0 +1,96 @@
+package api_v1
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/shiori/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleExportBookmarks(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	setup := func(t *testing.T) model.Dependencies {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		own := testutil.GetValidBookmark()
+		other := testutil.GetValidBookmark()
+		other.AccountID = testutil.FakeAccountID + 1
+		_, err := deps.Database().SaveBookmarks(ctx, true, *own, *other)
+		require.NoError(t, err)
+
+		return deps
+	}
+
+	t.Run("requires authentication", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleExportBookmarks, http.MethodGet, "/api/v1/export")
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(
+			deps,
+			HandleExportBookmarks,
+			http.MethodGet,
+			"/api/v1/export",
+			testutil.WithFakeUser(),
+			testutil.WithRequestQueryParam("format", "xml"),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("netscape by default", func(t *testing.T) {
+		deps := setup(t)
+		w := testutil.PerformRequest(deps, HandleExportBookmarks, http.MethodGet, "/api/v1/export", testutil.WithFakeUser())
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Header().Get("Content-Type"), "text/html")
+		require.Contains(t, w.Header().Get("Content-Disposition"), ".html")
+		require.Contains(t, w.Body.String(), "<!DOCTYPE NETSCAPE-Bookmark-file-1>")
+	})
+
+	t.Run("json only has bookmarks of the account", func(t *testing.T) {
+		deps := setup(t)
+		w := testutil.PerformRequest(
+			deps,
+			HandleExportBookmarks,
+			http.MethodGet,
+			"/api/v1/export",
+			testutil.WithFakeUser(),
+			testutil.WithRequestQueryParam("format", "json"),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+		var bookmarks []model.BookmarkDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bookmarks))
+		require.Len(t, bookmarks, 1)
+		require.Equal(t, testutil.FakeAccountID, bookmarks[0].AccountID)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		deps := setup(t)
+		w := testutil.PerformRequest(
+			deps,
+			HandleExportBookmarks,
+			http.MethodGet,
+			"/api/v1/export",
+			testutil.WithFakeUser(),
+			testutil.WithRequestQueryParam("format", "csv"),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		records, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+	})
+}
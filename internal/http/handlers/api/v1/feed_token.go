@@ -0,0 +1,86 @@
+package api_v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-shiori/shiori/internal/http/middleware"
+	"github.com/go-shiori/shiori/internal/http/response"
+	"github.com/go-shiori/shiori/internal/model"
+)
+
+type createFeedTokenResponse struct {
+	// The token value, only returned once. Private feeds are read from /feeds/{format}?token={token}
+	Token string `json:"token"`
+}
+
+// @Summary					Get the private feed token
+// @Description				Tell whether the logged in account has a private feed token. Its value is never returned.
+// @Tags						Auth
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Success					200	{object}	model.FeedToken
+// @Failure					401	{object}	nil	"Authentication required"
+// @Failure					404	{object}	nil	"No feed token"
+// @Router						/api/v1/auth/feed-token [get]
+func HandleGetFeedToken(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	token, err := deps.Domains().Auth().GetFeedToken(c.Request().Context(), c.GetAccount().ID)
+	if errors.Is(err, model.ErrNotFound) {
+		response.SendError(c, http.StatusNotFound, "Feed token not found")
+		return
+	}
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to get feed token")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, token)
+}
+
+// @Summary					Create the private feed token
+// @Description				Create the secret token of the feeds of every bookmark of the account, replacing the previous one.
+// @Tags						Auth
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Success					201	{object}	createFeedTokenResponse
+// @Failure					401	{object}	nil	"Authentication required"
+// @Router						/api/v1/auth/feed-token [post]
+func HandleCreateFeedToken(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	token, err := deps.Domains().Auth().CreateFeedToken(c.Request().Context(), c.GetAccount().ID)
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to create feed token")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusCreated, createFeedTokenResponse{Token: token})
+}
+
+// @Summary					Revoke the private feed token
+// @Tags						Auth
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Success					204	{object}	nil
+// @Failure					401	{object}	nil	"Authentication required"
+// @Router						/api/v1/auth/feed-token [delete]
+func HandleRevokeFeedToken(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	if err := deps.Domains().Auth().RevokeFeedToken(c.Request().Context(), c.GetAccount().ID); err != nil {
+		deps.Logger().WithError(err).Error("failed to revoke feed token")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusNoContent, nil)
+}
@@ -0,0 +1,54 @@
+package api_v1
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/shiori/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleFeedToken(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("requires authentication", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		for _, handler := range []model.HttpHandler{HandleGetFeedToken, HandleCreateFeedToken, HandleRevokeFeedToken} {
+			w := testutil.PerformRequest(deps, handler, http.MethodGet, "/api/v1/auth/feed-token")
+			require.Equal(t, http.StatusUnauthorized, w.Code)
+		}
+	})
+
+	t.Run("create and revoke", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		account, err := deps.Domains().Accounts().CreateAccount(ctx, model.AccountDTO{Username: "user", Password: "test"})
+		require.NoError(t, err)
+
+		w := testutil.PerformRequest(deps, HandleGetFeedToken, http.MethodGet, "/api/v1/auth/feed-token", testutil.WithAccount(account))
+		require.Equal(t, http.StatusNotFound, w.Code)
+
+		w = testutil.PerformRequest(deps, HandleCreateFeedToken, http.MethodPost, "/api/v1/auth/feed-token", testutil.WithAccount(account))
+		require.Equal(t, http.StatusCreated, w.Code)
+		response := testutil.NewTestResponseFromRecorder(w)
+		token := response.AssertMessageJSONContainsKey(t, "token").(string)
+
+		checked, err := deps.Domains().Auth().CheckFeedToken(ctx, token)
+		require.NoError(t, err)
+		require.Equal(t, account.ID, checked.ID)
+
+		w = testutil.PerformRequest(deps, HandleGetFeedToken, http.MethodGet, "/api/v1/auth/feed-token", testutil.WithAccount(account))
+		require.Equal(t, http.StatusOK, w.Code)
+		response = testutil.NewTestResponseFromRecorder(w)
+		response.AssertMessageJSONContainsKey(t, "created_at")
+
+		w = testutil.PerformRequest(deps, HandleRevokeFeedToken, http.MethodDelete, "/api/v1/auth/feed-token", testutil.WithAccount(account))
+		require.Equal(t, http.StatusNoContent, w.Code)
+
+		_, err = deps.Domains().Auth().CheckFeedToken(ctx, token)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,309 @@
+package api_v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/core"
+	"github.com/go-shiori/shiori/internal/http/middleware"
+	"github.com/go-shiori/shiori/internal/http/response"
+	"github.com/go-shiori/shiori/internal/model"
+)
+
+type highlightPayload struct {
+	Selector model.HighlightSelector `json:"selector"`
+	Note     string                  `json:"note"`
+}
+
+// highlightID returns the ID of the highlight in the path, sending the error response if it is invalid.
+func highlightID(c model.WebContext) (model.DBID, bool) {
+	id, err := strconv.Atoi(c.Request().PathValue("highlight_id"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid highlight ID")
+		return 0, false
+	}
+
+	return model.DBID(id), true
+}
+
+// @Summary					List bookmark highlights
+// @Description				List the highlights of the logged in account on a bookmark, in the order of its content. Positions are located in the current content of the bookmark.
+// @Tags						Highlights
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Param						id				path		int		true	"Bookmark ID"
+// @Param						all_accounts	query		boolean	false	"Look up bookmarks of every account, owners only"
+// @Success					200				{array}		model.Highlight
+// @Failure					400				{object}	nil	"Invalid bookmark ID"
+// @Failure					401				{object}	nil	"Authentication required"
+// @Failure					404				{object}	nil	"Bookmark not found"
+// @Failure					500				{object}	nil	"Internal server error"
+// @Router						/api/v1/bookmarks/{id}/highlights [get]
+func HandleListHighlights(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	bookmark := getScopedBookmark(deps, c)
+	if bookmark == nil {
+		return
+	}
+
+	highlights, err := deps.Domains().Highlights().ListHighlights(c.Request().Context(), c.GetAccount(), bookmark)
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to list highlights")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, highlights)
+}
+
+// @Summary					Highlight a passage of a bookmark
+// @Description				Highlight a passage of the readable content of a bookmark, with an optional note. The passage is selected by its text quote, with some of the text before and after it, and optionally its position. It must be found in the content.
+// @Tags						Highlights
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Accept						json
+// @Produce					json
+// @Param						id				path		int					true	"Bookmark ID"
+// @Param						all_accounts	query		boolean				false	"Look up bookmarks of every account, owners only"
+// @Param						payload			body		highlightPayload	true	"Highlight data"
+// @Success					201				{object}	model.Highlight
+// @Failure					400				{object}	nil	"Invalid bookmark ID or highlight data"
+// @Failure					401				{object}	nil	"Authentication required"
+// @Failure					404				{object}	nil	"Bookmark not found"
+// @Failure					500				{object}	nil	"Internal server error"
+// @Router						/api/v1/bookmarks/{id}/highlights [post]
+func HandleCreateHighlight(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	bookmark := getScopedBookmark(deps, c)
+	if bookmark == nil {
+		return
+	}
+
+	var payload highlightPayload
+	if err := json.NewDecoder(c.Request().Body).Decode(&payload); err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	created, err := deps.Domains().Highlights().CreateHighlight(c.Request().Context(), c.GetAccount(), bookmark, model.Highlight{
+		Selector: payload.Selector,
+		Note:     payload.Note,
+	})
+	if err, isValidationErr := err.(model.ValidationError); isValidationErr {
+		response.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to create highlight")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusCreated, created)
+}
+
+// @Summary					Get a bookmark highlight
+// @Tags						Highlights
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Param						id				path		int		true	"Bookmark ID"
+// @Param						highlight_id	path		int		true	"Highlight ID"
+// @Param						all_accounts	query		boolean	false	"Look up bookmarks of every account, owners only"
+// @Success					200				{object}	model.Highlight
+// @Failure					400				{object}	nil	"Invalid bookmark or highlight ID"
+// @Failure					401				{object}	nil	"Authentication required"
+// @Failure					404				{object}	nil	"Bookmark or highlight not found"
+// @Failure					500				{object}	nil	"Internal server error"
+// @Router						/api/v1/bookmarks/{id}/highlights/{highlight_id} [get]
+func HandleGetHighlight(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	id, ok := highlightID(c)
+	if !ok {
+		return
+	}
+
+	bookmark := getScopedBookmark(deps, c)
+	if bookmark == nil {
+		return
+	}
+
+	highlight, err := deps.Domains().Highlights().GetHighlight(c.Request().Context(), c.GetAccount(), bookmark, id)
+	if errors.Is(err, model.ErrNotFound) {
+		response.NotFound(c)
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to get highlight")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, highlight)
+}
+
+// @Summary					Update a bookmark highlight
+// @Description				Replace the selector and note of a highlight.
+// @Tags						Highlights
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Accept						json
+// @Produce					json
+// @Param						id				path		int					true	"Bookmark ID"
+// @Param						highlight_id	path		int					true	"Highlight ID"
+// @Param						all_accounts	query		boolean				false	"Look up bookmarks of every account, owners only"
+// @Param						payload			body		highlightPayload	true	"Highlight data"
+// @Success					200				{object}	model.Highlight
+// @Failure					400				{object}	nil	"Invalid bookmark or highlight ID or data"
+// @Failure					401				{object}	nil	"Authentication required"
+// @Failure					404				{object}	nil	"Bookmark or highlight not found"
+// @Failure					500				{object}	nil	"Internal server error"
+// @Router						/api/v1/bookmarks/{id}/highlights/{highlight_id} [put]
+func HandleUpdateHighlight(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	id, ok := highlightID(c)
+	if !ok {
+		return
+	}
+
+	bookmark := getScopedBookmark(deps, c)
+	if bookmark == nil {
+		return
+	}
+
+	var payload highlightPayload
+	if err := json.NewDecoder(c.Request().Body).Decode(&payload); err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	updated, err := deps.Domains().Highlights().UpdateHighlight(c.Request().Context(), c.GetAccount(), bookmark, model.Highlight{
+		ID:       id,
+		Selector: payload.Selector,
+		Note:     payload.Note,
+	})
+	if err, isValidationErr := err.(model.ValidationError); isValidationErr {
+		response.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, model.ErrNotFound) {
+		response.NotFound(c)
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to update highlight")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, updated)
+}
+
+// @Summary					Delete a bookmark highlight
+// @Tags						Highlights
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Param						id				path		int		true	"Bookmark ID"
+// @Param						highlight_id	path		int		true	"Highlight ID"
+// @Param						all_accounts	query		boolean	false	"Look up bookmarks of every account, owners only"
+// @Success					204				{object}	nil
+// @Failure					400				{object}	nil	"Invalid bookmark or highlight ID"
+// @Failure					401				{object}	nil	"Authentication required"
+// @Failure					404				{object}	nil	"Bookmark or highlight not found"
+// @Failure					500				{object}	nil	"Internal server error"
+// @Router						/api/v1/bookmarks/{id}/highlights/{highlight_id} [delete]
+func HandleDeleteHighlight(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	id, ok := highlightID(c)
+	if !ok {
+		return
+	}
+
+	bookmark := getScopedBookmark(deps, c)
+	if bookmark == nil {
+		return
+	}
+
+	err := deps.Domains().Highlights().DeleteHighlight(c.Request().Context(), c.GetAccount(), bookmark, id)
+	if errors.Is(err, model.ErrNotFound) {
+		response.NotFound(c)
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to delete highlight")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusNoContent, nil)
+}
+
+// @Summary					Export highlights
+// @Description				Download every highlight of the logged in account, grouped by bookmark. The markdown format quotes the highlighted passages followed by their notes, json keeps the selectors.
+// @Tags						Highlights
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json,text/markdown
+// @Param						format	query		string	false	"Export format: markdown (default) or json"
+// @Success					200		{file}		file
+// @Failure					400		{object}	nil	"Invalid format"
+// @Failure					401		{object}	nil	"Authentication required"
+// @Failure					500		{object}	nil	"Internal server error"
+// @Router						/api/v1/highlights/export [get]
+func HandleExportHighlights(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	format := model.ExportFormatMarkdown
+	if formatParam := c.Request().URL.Query().Get("format"); formatParam != "" {
+		format = model.ExportFormat(formatParam)
+	}
+
+	if err := model.ValidateHighlightsExportFormat(format); err != nil {
+		response.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	highlights, err := deps.Domains().Highlights().ExportHighlights(c.Request().Context(), c.GetAccount())
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to export highlights")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	buf := bytes.Buffer{}
+	if err := core.ExportHighlights(&buf, format, highlights); err != nil {
+		deps.Logger().WithError(err).Error("failed to export highlights")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	contentType, extension := core.HighlightsExportFileType(format)
+	fileName := "shiori-highlights-" + time.Now().UTC().Format("2006-01-02") + extension
+
+	c.ResponseWriter().Header().Set("Content-Type", contentType)
+	c.ResponseWriter().Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	c.ResponseWriter().WriteHeader(http.StatusOK)
+	c.ResponseWriter().Write(buf.Bytes())
+}
@@ -0,0 +1,171 @@
+package api_v1
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/shiori/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func setupHighlightsBookmark(t *testing.T, ctx context.Context, deps model.Dependencies) string {
+	bookmark := testutil.GetValidBookmark()
+	bookmark.AccountID = testutil.FakeAccountID
+	bookmark.HTML = "<p>Go is an open source programming language.</p>"
+	saved, err := deps.Database().SaveBookmarks(ctx, true, *bookmark)
+	require.NoError(t, err)
+	return strconv.Itoa(saved[0].ID)
+}
+
+func TestHandleCreateHighlight(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("requires authentication", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleCreateHighlight, http.MethodPost, "/api/v1/bookmarks/1/highlights")
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("text not in the content", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		id := setupHighlightsBookmark(t, ctx, deps)
+
+		w := testutil.PerformRequest(deps, HandleCreateHighlight, http.MethodPost, "/api/v1/bookmarks/"+id+"/highlights",
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+			testutil.WithBody(`{"selector": {"quote": {"exact": "Rust"}}}`),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("highlight", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		id := setupHighlightsBookmark(t, ctx, deps)
+
+		w := testutil.PerformRequest(deps, HandleCreateHighlight, http.MethodPost, "/api/v1/bookmarks/"+id+"/highlights",
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+			testutil.WithBody(`{"selector": {"quote": {"exact": "open source"}}, "note": "license"}`),
+		)
+		require.Equal(t, http.StatusCreated, w.Code)
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertOk(t)
+		response.AssertMessageJSONKeyValue(t, "selector", func(t *testing.T, value any) {
+			require.Equal(t, map[string]any{
+				"quote":    map[string]any{"exact": "open source"},
+				"position": map[string]any{"start": float64(9), "end": float64(20)},
+			}, value)
+		})
+
+		w = testutil.PerformRequest(deps, HandleListHighlights, http.MethodGet, "/api/v1/bookmarks/"+id+"/highlights",
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+		testutil.NewTestResponseFromRecorder(w).AssertMessageIsListLength(t, 1)
+	})
+}
+
+func TestHandleDeleteHighlight(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("highlight of another account", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		id := setupHighlightsBookmark(t, ctx, deps)
+		bookmarkID, _ := strconv.Atoi(id)
+
+		highlight, err := deps.Database().CreateHighlight(ctx, model.Highlight{
+			BookmarkID: bookmarkID,
+			AccountID:  testutil.FakeAccountID + 1,
+			Selector:   model.HighlightSelector{Quote: model.TextQuoteSelector{Exact: "Go"}},
+		})
+		require.NoError(t, err)
+
+		highlightID := strconv.Itoa(int(highlight.ID))
+		w := testutil.PerformRequest(deps, HandleDeleteHighlight, http.MethodDelete, "/api/v1/bookmarks/"+id+"/highlights/"+highlightID,
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+			testutil.WithRequestPathValue("highlight_id", highlightID),
+		)
+		require.Equal(t, http.StatusNotFound, w.Code)
+
+		_, exists, err := deps.Database().GetHighlight(ctx, highlight.ID)
+		require.NoError(t, err)
+		require.True(t, exists)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		id := setupHighlightsBookmark(t, ctx, deps)
+		bookmarkID, _ := strconv.Atoi(id)
+
+		highlight, err := deps.Database().CreateHighlight(ctx, model.Highlight{
+			BookmarkID: bookmarkID,
+			AccountID:  testutil.FakeAccountID,
+			Selector:   model.HighlightSelector{Quote: model.TextQuoteSelector{Exact: "Go"}},
+		})
+		require.NoError(t, err)
+
+		highlightID := strconv.Itoa(int(highlight.ID))
+		w := testutil.PerformRequest(deps, HandleDeleteHighlight, http.MethodDelete, "/api/v1/bookmarks/"+id+"/highlights/"+highlightID,
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+			testutil.WithRequestPathValue("highlight_id", highlightID),
+		)
+		require.Equal(t, http.StatusNoContent, w.Code)
+
+		_, exists, err := deps.Database().GetHighlight(ctx, highlight.ID)
+		require.NoError(t, err)
+		require.False(t, exists)
+	})
+}
+
+func TestHandleExportHighlights(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("invalid format", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleExportHighlights, http.MethodGet, "/api/v1/highlights/export?format=csv",
+			testutil.WithFakeUser(),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		id := setupHighlightsBookmark(t, ctx, deps)
+
+		w := testutil.PerformRequest(deps, HandleCreateHighlight, http.MethodPost, "/api/v1/bookmarks/"+id+"/highlights",
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+			testutil.WithBody(`{"selector": {"quote": {"exact": "open source"}}, "note": "license"}`),
+		)
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		w = testutil.PerformRequest(deps, HandleExportHighlights, http.MethodGet, "/api/v1/highlights/export",
+			testutil.WithFakeUser(),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "text/markdown; charset=utf-8", w.Header().Get("Content-Type"))
+		require.Contains(t, w.Header().Get("Content-Disposition"), ".md")
+		require.Contains(t, w.Body.String(), "> open source\n")
+		require.Contains(t, w.Body.String(), "license")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleExportHighlights, http.MethodGet, "/api/v1/highlights/export?format=json",
+			testutil.WithFakeUser(),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		require.JSONEq(t, "[]", w.Body.String())
+	})
+}
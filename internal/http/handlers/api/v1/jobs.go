@@ -0,0 +1,68 @@
+package api_v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-shiori/shiori/internal/http/middleware"
+	"github.com/go-shiori/shiori/internal/http/response"
+	"github.com/go-shiori/shiori/internal/model"
+)
+
+const defaultJobsLimit = 100
+
+// @Summary					List jobs
+// @Description				List the background jobs, newest first. Without a status filter completed jobs are left out.
+// @Tags						Jobs
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Param						status	query		string	false	"Comma separated list of statuses: pending, running, completed, failed"
+// @Param						limit	query		integer	false	"Maximum number of jobs to return, 100 by default"
+// @Success					200		{array}		model.Job
+// @Failure					400		{object}	nil	"Invalid status or limit"
+// @Failure					401		{object}	nil	"Authentication required"
+// @Failure					403		{object}	nil	"Only owners can list jobs"
+// @Failure					500		{object}	nil	"Internal server error"
+// @Router						/api/v1/jobs [get]
+func HandleListJobs(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInAdmin(deps, c); err != nil {
+		return
+	}
+
+	query := c.Request().URL.Query()
+
+	opts := model.ListJobsOptions{
+		Status: []model.JobStatus{model.JobStatusPending, model.JobStatusRunning, model.JobStatusFailed},
+		Limit:  defaultJobsLimit,
+	}
+
+	if statusParam := query.Get("status"); statusParam != "" {
+		opts.Status = []model.JobStatus{}
+		for _, value := range splitQueryList(statusParam) {
+			status := model.JobStatus(value)
+			if err := status.IsValid(); err != nil {
+				response.SendError(c, http.StatusBadRequest, err.Error())
+				return
+			}
+			opts.Status = append(opts.Status, status)
+		}
+	}
+
+	if limitParam := query.Get("limit"); limitParam != "" {
+		limit, err := strconv.Atoi(limitParam)
+		if err != nil || limit < 1 {
+			response.SendError(c, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		opts.Limit = limit
+	}
+
+	jobs, err := deps.Domains().Jobs().ListJobs(c.Request().Context(), opts)
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to list jobs")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, jobs)
+}
@@ -0,0 +1,104 @@
+package api_v1
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/shiori/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleListJobs(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("requires authentication", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleListJobs, http.MethodGet, "/api/v1/jobs")
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("requires admin access", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleListJobs, http.MethodGet, "/api/v1/jobs", testutil.WithFakeUser())
+		require.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(
+			deps,
+			HandleListJobs,
+			http.MethodGet,
+			"/api/v1/jobs",
+			testutil.WithFakeAdmin(),
+			testutil.WithRequestQueryParam("status", "pending,unknown"),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(
+			deps,
+			HandleListJobs,
+			http.MethodGet,
+			"/api/v1/jobs",
+			testutil.WithFakeAdmin(),
+			testutil.WithRequestQueryParam("limit", "0"),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("completed jobs are hidden by default", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		for _, status := range []model.JobStatus{model.JobStatusPending, model.JobStatusCompleted, model.JobStatusFailed} {
+			_, err := deps.Database().CreateJob(ctx, model.Job{
+				Type:        model.JobTypeProcessBookmark,
+				Payload:     "{}",
+				Status:      status,
+				MaxAttempts: 1,
+			})
+			require.NoError(t, err)
+		}
+
+		w := testutil.PerformRequest(deps, HandleListJobs, http.MethodGet, "/api/v1/jobs", testutil.WithFakeAdmin())
+		require.Equal(t, http.StatusOK, w.Code)
+
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertOk(t)
+		response.AssertMessageIsListLength(t, 2)
+	})
+
+	t.Run("filter by status", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		for _, status := range []model.JobStatus{model.JobStatusPending, model.JobStatusCompleted} {
+			_, err := deps.Database().CreateJob(ctx, model.Job{
+				Type:        model.JobTypeProcessBookmark,
+				Payload:     "{}",
+				Status:      status,
+				MaxAttempts: 1,
+			})
+			require.NoError(t, err)
+		}
+
+		w := testutil.PerformRequest(
+			deps,
+			HandleListJobs,
+			http.MethodGet,
+			"/api/v1/jobs",
+			testutil.WithFakeAdmin(),
+			testutil.WithRequestQueryParam("status", "completed"),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertMessageIsListLength(t, 1)
+		response.ForEach(t, func(item map[string]any) {
+			require.Equal(t, string(model.JobStatusCompleted), item["status"])
+		})
+	})
+}
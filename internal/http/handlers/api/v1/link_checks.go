@@ -0,0 +1,113 @@
+package api_v1
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-shiori/shiori/internal/http/middleware"
+	"github.com/go-shiori/shiori/internal/http/response"
+	"github.com/go-shiori/shiori/internal/model"
+)
+
+type checkLinksPayload struct {
+	// Bookmarks to check, empty checks every bookmark of the account
+	IDs []int `json:"ids"`
+}
+
+// @Summary					List bookmark link checks
+// @Description				List the results of checking the URL of a bookmark, newest first
+// @Tags						Bookmarks
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Param						id				path		int		true	"Bookmark ID"
+// @Param						all_accounts	query		boolean	false	"Look up bookmarks of every account, owners only"
+// @Success					200				{array}		model.LinkCheck
+// @Failure					400				{object}	nil	"Invalid bookmark ID"
+// @Failure					401				{object}	nil	"Authentication required"
+// @Failure					404				{object}	nil	"Bookmark not found"
+// @Failure					500				{object}	nil	"Internal server error"
+// @Router						/api/v1/bookmarks/{id}/checks [get]
+func HandleListBookmarkLinkChecks(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	bookmark := getScopedBookmark(deps, c)
+	if bookmark == nil {
+		return
+	}
+
+	checks, err := deps.Domains().LinkChecker().ListChecks(c.Request().Context(), bookmark.ID)
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to list link checks")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, checks)
+}
+
+// @Summary					Check bookmark links
+// @Description				Queue a check of the bookmark URLs. Without ids every bookmark of the account is checked.
+// @Tags						Bookmarks
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Accept						json
+// @Produce					json
+// @Param						payload			body		checkLinksPayload	false	"Bookmarks to check"
+// @Param						all_accounts	query		boolean				false	"Check bookmarks of every account, owners only"
+// @Success					202				{object}	model.Job
+// @Failure					400				{object}	nil	"Invalid request payload"
+// @Failure					401				{object}	nil	"Authentication required"
+// @Failure					404				{object}	nil	"No bookmarks found"
+// @Failure					500				{object}	nil	"Internal server error"
+// @Router						/api/v1/bookmarks/check [post]
+func HandleCheckBookmarkLinks(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	// The payload is optional, an empty body checks every bookmark
+	var payload checkLinksPayload
+	if err := json.NewDecoder(c.Request().Body).Decode(&payload); err != nil && !errors.Is(err, io.EOF) {
+		response.SendError(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	ctx := c.Request().Context()
+
+	var bookmarks []model.BookmarkDTO
+	var err error
+	if len(payload.IDs) > 0 {
+		bookmarks, err = deps.Domains().Bookmarks().GetBookmarks(ctx, payload.IDs, bookmarksAccountScope(c))
+	} else {
+		bookmarks, err = deps.Domains().Bookmarks().ListBookmarks(ctx, model.ListBookmarksOptions{
+			AccountID: bookmarksAccountScope(c),
+		})
+	}
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to get bookmarks")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	if len(bookmarks) == 0 {
+		response.SendError(c, http.StatusNotFound, "No bookmarks found")
+		return
+	}
+
+	ids := make([]int, 0, len(bookmarks))
+	for _, bookmark := range bookmarks {
+		ids = append(ids, bookmark.ID)
+	}
+
+	job, err := deps.Domains().Jobs().Enqueue(ctx, model.JobTypeCheckLinks, model.CheckLinksJobPayload{BookmarkIDs: ids})
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to queue link check")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusAccepted, job)
+}
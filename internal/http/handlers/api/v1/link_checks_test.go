@@ -0,0 +1,125 @@
+package api_v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/shiori/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleListBookmarkLinkChecks(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("requires authentication", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(
+			deps,
+			HandleListBookmarkLinkChecks,
+			http.MethodGet,
+			"/api/v1/bookmarks/1/checks",
+			testutil.WithRequestPathValue("id", "1"),
+		)
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("bookmark not found", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(
+			deps,
+			HandleListBookmarkLinkChecks,
+			http.MethodGet,
+			"/api/v1/bookmarks/999/checks",
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", "999"),
+		)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("list checks", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		bookmark := testutil.GetValidBookmark()
+		bookmark.AccountID = testutil.FakeAccountID
+		saved, err := deps.Database().SaveBookmarks(ctx, true, *bookmark)
+		require.NoError(t, err)
+		id := strconv.Itoa(saved[0].ID)
+
+		for _, status := range []int{404, 200} {
+			_, err := deps.Database().CreateLinkCheck(ctx, model.LinkCheck{BookmarkID: saved[0].ID, URL: saved[0].URL, StatusCode: status})
+			require.NoError(t, err)
+		}
+
+		w := testutil.PerformRequest(
+			deps,
+			HandleListBookmarkLinkChecks,
+			http.MethodGet,
+			"/api/v1/bookmarks/"+id+"/checks",
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertOk(t)
+		response.AssertMessageIsListLength(t, 2)
+	})
+}
+
+func TestHandleCheckBookmarkLinks(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("requires authentication", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleCheckBookmarkLinks, http.MethodPost, "/api/v1/bookmarks/check")
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("no bookmarks", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleCheckBookmarkLinks, http.MethodPost, "/api/v1/bookmarks/check", testutil.WithFakeUser())
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("invalid payload", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(
+			deps,
+			HandleCheckBookmarkLinks,
+			http.MethodPost,
+			"/api/v1/bookmarks/check",
+			testutil.WithFakeUser(),
+			testutil.WithBody(`{"ids": "invalid"}`),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("queues a check of the account bookmarks", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		own := testutil.GetValidBookmark()
+		own.AccountID = testutil.FakeAccountID
+		other := testutil.GetValidBookmark()
+		other.URL = "https://example.com/other"
+		other.AccountID = testutil.FakeAccountID + 1
+		saved, err := deps.Database().SaveBookmarks(ctx, true, *own, *other)
+		require.NoError(t, err)
+
+		w := testutil.PerformRequest(deps, HandleCheckBookmarkLinks, http.MethodPost, "/api/v1/bookmarks/check", testutil.WithFakeUser())
+		require.Equal(t, http.StatusAccepted, w.Code)
+
+		jobs, err := deps.Domains().Jobs().ListJobs(ctx, model.ListJobsOptions{})
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		require.Equal(t, model.JobTypeCheckLinks, jobs[0].Type)
+
+		var payload model.CheckLinksJobPayload
+		require.NoError(t, json.Unmarshal([]byte(jobs[0].Payload), &payload))
+		require.Equal(t, []int{saved[0].ID}, payload.BookmarkIDs)
+	})
+}
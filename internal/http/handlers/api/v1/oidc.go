@@ -0,0 +1,244 @@
+package api_v1
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/http/middleware"
+	"github.com/go-shiori/shiori/internal/http/response"
+	"github.com/go-shiori/shiori/internal/model"
+)
+
+// oidcLoginCookie keeps the signed login request between the redirect to the provider and the callback
+const oidcLoginCookie = "oidc_login"
+
+type oidcLinkResponse struct {
+	// Provider URL to send the user to
+	URL string `json:"url"`
+}
+
+// setOIDCLoginCookie keeps the signed login request in the browser until the callback
+func setOIDCLoginCookie(c model.WebContext, authRequest string) {
+	http.SetCookie(c.ResponseWriter(), &http.Cookie{
+		Name:     oidcLoginCookie,
+		Value:    authRequest,
+		MaxAge:   int((10 * time.Minute).Seconds()),
+		HttpOnly: true,
+		Secure:   c.Request().TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// @Summary		Login with the OpenID Connect provider
+// @Description	Redirect to the provider configured with SHIORI_OIDC_*, which sends the user back to the callback.
+// @Tags			Auth
+// @Success		302	{object}	nil	"Redirect to the provider"
+// @Failure		404	{object}	nil	"OIDC login is disabled"
+// @Router			/api/v1/auth/oidc/login [get]
+func HandleOIDCLogin(deps model.Dependencies, c model.WebContext) {
+	if !deps.Config().Http.OIDCEnabled {
+		response.NotFound(c)
+		return
+	}
+
+	authURL, authRequest, err := deps.Domains().OIDC().AuthorizationURL(c.Request().Context(), 0)
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to start oidc login")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	setOIDCLoginCookie(c, authRequest)
+	http.Redirect(c.ResponseWriter(), c.Request(), authURL, http.StatusFound)
+}
+
+// @Summary		Complete a login with the OpenID Connect provider
+// @Description	Callback the provider sends the user back to. The account is created on its first login, then the user is
+// @Description	redirected to the web interface with the session token in the URL fragment. Accounts are matched by the
+// @Description	identity of the user at the provider, never by username. When the login was started by the link endpoint,
+// @Description	the identity is linked to the account that started it and no session is created. Accounts with two-factor
+// @Description	authentication, or owners when it's required for them, get the challenge of the second login step in the
+// @Description	fragment instead of the token, to complete with the TOTP login endpoint.
+// @Tags			Auth
+// @Param			code	query		string	true	"Authorization code"
+// @Param			state	query		string	true	"Login state"
+// @Success		302		{object}	nil		"Redirect to the web interface"
+// @Failure		400		{object}	nil		"Login failed"
+// @Failure		404		{object}	nil		"OIDC login is disabled"
+// @Router			/api/v1/auth/oidc/callback [get]
+func HandleOIDCCallback(deps model.Dependencies, c model.WebContext) {
+	if !deps.Config().Http.OIDCEnabled {
+		response.NotFound(c)
+		return
+	}
+
+	query := c.Request().URL.Query()
+	if providerErr := query.Get("error"); providerErr != "" {
+		deps.Logger().WithField("error", providerErr).WithField("description", query.Get("error_description")).Warn("oidc provider refused the login")
+		response.SendError(c, http.StatusBadRequest, "Login refused by the provider")
+		return
+	}
+
+	cookie, err := c.Request().Cookie(oidcLoginCookie)
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "Login expired, please try again")
+		return
+	}
+
+	http.SetCookie(c.ResponseWriter(), &http.Cookie{
+		Name:   oidcLoginCookie,
+		Value:  "",
+		MaxAge: -1,
+	})
+
+	account, linked, err := deps.Domains().OIDC().Login(c.Request().Context(), query.Get("code"), query.Get("state"), cookie.Value)
+	if err != nil {
+		deps.Logger().WithError(err).Warn("oidc login failed")
+		response.SendError(c, http.StatusBadRequest, "Login failed")
+		return
+	}
+
+	if linked {
+		http.Redirect(c.ResponseWriter(), c.Request(), deps.Config().Http.RootPath, http.StatusFound)
+		return
+	}
+
+	// The second factor is asked like for password logins, OIDC sessions are remembered
+	status, err := deps.Domains().TOTP().GetStatus(c.Request().Context(), account)
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to get two-factor authentication status")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	if status.Enabled || status.Required {
+		challenge, err := deps.Domains().TOTP().CreateLoginChallenge(account, true)
+		if err != nil {
+			deps.Logger().WithError(err).Error("failed to create login challenge")
+			response.SendInternalServerError(c)
+			return
+		}
+
+		fragment := url.Values{"oidc_challenge": {challenge}}
+		if !status.Enabled {
+			fragment.Set("totp_setup_required", "true")
+		}
+		http.Redirect(c.ResponseWriter(), c.Request(), deps.Config().Http.RootPath+"#"+fragment.Encode(), http.StatusFound)
+		return
+	}
+
+	expiration := time.Now().Add(time.Hour * 24 * 30)
+	token, err := deps.Domains().Auth().CreateTokenForAccount(c.Request().Context(), account, expiration, c.Request().UserAgent())
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to create session")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	// The fragment isn't sent to servers, the web interface reads the token from it
+	fragment := url.Values{
+		"oidc_token": {token},
+		"expires":    {strconv.FormatInt(expiration.Unix(), 10)},
+	}
+	http.Redirect(c.ResponseWriter(), c.Request(), deps.Config().Http.RootPath+"#"+fragment.Encode(), http.StatusFound)
+}
+
+// @Summary					Link an OpenID Connect identity
+// @Description				Start a login with the provider that links the identity of the user to the logged in account, so it
+// @Description				can be used to log in afterwards. The browser has to be sent to the returned URL.
+// @Tags						Auth
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Success					200	{object}	oidcLinkResponse
+// @Failure					401	{object}	nil	"Authentication required"
+// @Failure					404	{object}	nil	"OIDC login is disabled"
+// @Failure					500	{object}	nil	"Internal server error"
+// @Router						/api/v1/auth/oidc/link [post]
+func HandleOIDCLink(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	if !deps.Config().Http.OIDCEnabled {
+		response.NotFound(c)
+		return
+	}
+
+	authURL, authRequest, err := deps.Domains().OIDC().AuthorizationURL(c.Request().Context(), c.GetAccount().ID)
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to start oidc link")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	setOIDCLoginCookie(c, authRequest)
+	response.SendJSON(c, http.StatusOK, oidcLinkResponse{URL: authURL})
+}
+
+// @Summary					List linked OpenID Connect identities
+// @Tags						Auth
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Success					200	{array}		model.AccountIdentity
+// @Failure					401	{object}	nil	"Authentication required"
+// @Failure					404	{object}	nil	"OIDC login is disabled"
+// @Failure					500	{object}	nil	"Internal server error"
+// @Router						/api/v1/auth/oidc/identities [get]
+func HandleListOIDCIdentities(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	if !deps.Config().Http.OIDCEnabled {
+		response.NotFound(c)
+		return
+	}
+
+	identities, err := deps.Domains().OIDC().ListIdentities(c.Request().Context(), c.GetAccount().ID)
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to list oidc identities")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, identities)
+}
+
+// @Summary					Unlink an OpenID Connect identity
+// @Tags						Auth
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Param						id	path		int	true	"Identity ID"
+// @Success					204	{object}	nil
+// @Failure					400	{object}	nil	"Invalid identity ID"
+// @Failure					401	{object}	nil	"Authentication required"
+// @Failure					404	{object}	nil	"Identity not found"
+// @Failure					500	{object}	nil	"Internal server error"
+// @Router						/api/v1/auth/oidc/identities/{id} [delete]
+func HandleUnlinkOIDCIdentity(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Request().PathValue("id"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid identity ID")
+		return
+	}
+
+	err = deps.Domains().OIDC().UnlinkIdentity(c.Request().Context(), c.GetAccount().ID, model.DBID(id))
+	if errors.Is(err, model.ErrNotFound) {
+		response.NotFound(c)
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to unlink oidc identity")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusNoContent, nil)
+}
@@ -0,0 +1,225 @@
+package api_v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/shiori/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleOIDCLogin(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("disabled", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleOIDCLogin, http.MethodGet, "/api/v1/auth/oidc/login")
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("redirects to the provider", func(t *testing.T) {
+		provider := testutil.NewOIDCProvider(t)
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		provider.Configure(deps.Config().Http)
+
+		w := testutil.PerformRequest(deps, HandleOIDCLogin, http.MethodGet, "/api/v1/auth/oidc/login")
+		require.Equal(t, http.StatusFound, w.Code)
+		require.True(t, strings.HasPrefix(w.Header().Get("Location"), provider.URL+"/authorize?"))
+
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+		require.Equal(t, oidcLoginCookie, cookies[0].Name)
+		require.True(t, cookies[0].HttpOnly)
+	})
+}
+
+func TestHandleOIDCCallback(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("disabled", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleOIDCCallback, http.MethodGet, "/api/v1/auth/oidc/callback")
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("login expired", func(t *testing.T) {
+		provider := testutil.NewOIDCProvider(t)
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		provider.Configure(deps.Config().Http)
+
+		w := testutil.PerformRequest(deps, HandleOIDCCallback, http.MethodGet, "/api/v1/auth/oidc/callback",
+			testutil.WithRequestQueryParam("code", "code"),
+			testutil.WithRequestQueryParam("state", "state"),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("refused by the provider", func(t *testing.T) {
+		provider := testutil.NewOIDCProvider(t)
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		provider.Configure(deps.Config().Http)
+
+		w := testutil.PerformRequest(deps, HandleOIDCCallback, http.MethodGet, "/api/v1/auth/oidc/callback",
+			testutil.WithRequestQueryParam("error", "access_denied"),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("successful login", func(t *testing.T) {
+		provider := testutil.NewOIDCProvider(t)
+		provider.Claims["preferred_username"] = "alice"
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		provider.Configure(deps.Config().Http)
+
+		w := testutil.PerformRequest(deps, HandleOIDCLogin, http.MethodGet, "/api/v1/auth/oidc/login")
+		require.Equal(t, http.StatusFound, w.Code)
+		cookie := w.Result().Cookies()[0]
+		code, state := provider.Authorize(t, w.Header().Get("Location"))
+
+		w = testutil.PerformRequest(deps, HandleOIDCCallback, http.MethodGet, "/api/v1/auth/oidc/callback",
+			testutil.WithHeader("Cookie", cookie.String()),
+			testutil.WithRequestQueryParam("code", code),
+			testutil.WithRequestQueryParam("state", state),
+		)
+		require.Equal(t, http.StatusFound, w.Code)
+
+		location, err := url.Parse(w.Header().Get("Location"))
+		require.NoError(t, err)
+		require.Equal(t, deps.Config().Http.RootPath, location.Path)
+
+		fragment, err := url.ParseQuery(location.Fragment)
+		require.NoError(t, err)
+		require.NotEmpty(t, fragment.Get("expires"))
+
+		account, err := deps.Domains().Auth().CheckToken(ctx, fragment.Get("oidc_token"))
+		require.NoError(t, err)
+		require.Equal(t, "alice", account.Username)
+	})
+	t.Run("owners must use two-factor authentication", func(t *testing.T) {
+		provider := testutil.NewOIDCProvider(t)
+		provider.Claims["preferred_username"] = "alice"
+		provider.Claims["groups"] = []string{"admins"}
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		provider.Configure(deps.Config().Http)
+		deps.Config().Http.OIDCOwnerValue = "admins"
+		require.NoError(t, deps.Database().SetSetting(ctx, model.SettingOwnerTOTPRequired, "true"))
+
+		w := testutil.PerformRequest(deps, HandleOIDCLogin, http.MethodGet, "/api/v1/auth/oidc/login")
+		require.Equal(t, http.StatusFound, w.Code)
+		cookie := w.Result().Cookies()[0]
+		code, state := provider.Authorize(t, w.Header().Get("Location"))
+
+		w = testutil.PerformRequest(deps, HandleOIDCCallback, http.MethodGet, "/api/v1/auth/oidc/callback",
+			testutil.WithHeader("Cookie", cookie.String()),
+			testutil.WithRequestQueryParam("code", code),
+			testutil.WithRequestQueryParam("state", state),
+		)
+		require.Equal(t, http.StatusFound, w.Code)
+
+		location, err := url.Parse(w.Header().Get("Location"))
+		require.NoError(t, err)
+		fragment, err := url.ParseQuery(location.Fragment)
+		require.NoError(t, err)
+		require.Empty(t, fragment.Get("oidc_token"))
+		require.Equal(t, "true", fragment.Get("totp_setup_required"))
+
+		account, _, err := deps.Domains().TOTP().CheckLoginChallenge(ctx, fragment.Get("oidc_challenge"))
+		require.NoError(t, err)
+		require.Equal(t, "alice", account.Username)
+
+		_, err = deps.Domains().Auth().CheckToken(ctx, fragment.Get("oidc_challenge"))
+		require.Error(t, err, "the challenge isn't a session")
+	})
+}
+
+func TestHandleOIDCLink(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("requires authentication", func(t *testing.T) {
+		provider := testutil.NewOIDCProvider(t)
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		provider.Configure(deps.Config().Http)
+
+		w := testutil.PerformRequest(deps, HandleOIDCLink, http.MethodPost, "/api/v1/auth/oidc/link")
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("links the identity to the account", func(t *testing.T) {
+		provider := testutil.NewOIDCProvider(t)
+		provider.Claims["preferred_username"] = "someone-else"
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		provider.Configure(deps.Config().Http)
+
+		account, err := deps.Domains().Accounts().CreateAccount(ctx, model.AccountDTO{Username: "alice", Password: "password"})
+		require.NoError(t, err)
+
+		w := testutil.PerformRequest(deps, HandleOIDCLink, http.MethodPost, "/api/v1/auth/oidc/link",
+			testutil.WithAccount(account),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+		cookie := w.Result().Cookies()[0]
+
+		body := oidcLinkResponse{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		code, state := provider.Authorize(t, body.URL)
+
+		w = testutil.PerformRequest(deps, HandleOIDCCallback, http.MethodGet, "/api/v1/auth/oidc/callback",
+			testutil.WithHeader("Cookie", cookie.String()),
+			testutil.WithRequestQueryParam("code", code),
+			testutil.WithRequestQueryParam("state", state),
+		)
+		require.Equal(t, http.StatusFound, w.Code)
+		require.Equal(t, deps.Config().Http.RootPath, w.Header().Get("Location"))
+
+		w = testutil.PerformRequest(deps, HandleListOIDCIdentities, http.MethodGet, "/api/v1/auth/oidc/identities",
+			testutil.WithAccount(account),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+		testutil.NewTestResponseFromRecorder(w).AssertMessageIsListLength(t, 1)
+	})
+}
+
+func TestHandleUnlinkOIDCIdentity(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("identity of another account", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		identity, err := deps.Database().CreateAccountIdentity(ctx, model.AccountIdentity{AccountID: testutil.FakeAccountID + 1, Issuer: "https://id.example.com", Subject: "user-1"})
+		require.NoError(t, err)
+
+		id := strconv.Itoa(int(identity.ID))
+		w := testutil.PerformRequest(deps, HandleUnlinkOIDCIdentity, http.MethodDelete, "/api/v1/auth/oidc/identities/"+id,
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+		)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("unlink", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		identity, err := deps.Database().CreateAccountIdentity(ctx, model.AccountIdentity{AccountID: testutil.FakeAccountID, Issuer: "https://id.example.com", Subject: "user-1"})
+		require.NoError(t, err)
+
+		id := strconv.Itoa(int(identity.ID))
+		w := testutil.PerformRequest(deps, HandleUnlinkOIDCIdentity, http.MethodDelete, "/api/v1/auth/oidc/identities/"+id,
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+		)
+		require.Equal(t, http.StatusNoContent, w.Code)
+
+		_, exists, err := deps.Database().GetAccountIdentity(ctx, "https://id.example.com", "user-1")
+		require.NoError(t, err)
+		require.False(t, exists)
+	})
+}
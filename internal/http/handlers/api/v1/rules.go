@@ -0,0 +1,272 @@
+package api_v1
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-shiori/shiori/internal/http/middleware"
+	"github.com/go-shiori/shiori/internal/http/response"
+	"github.com/go-shiori/shiori/internal/model"
+)
+
+type rulePayload struct {
+	Name string `json:"name"`
+	// Disabled rules are kept but not applied, enabled if empty
+	Enabled    *bool                `json:"enabled"`
+	Conditions model.RuleConditions `json:"conditions"`
+	Actions    model.RuleActions    `json:"actions"`
+}
+
+func (p *rulePayload) ToBookmarkRule() model.BookmarkRule {
+	rule := model.BookmarkRule{
+		Name:       p.Name,
+		Enabled:    true,
+		Conditions: p.Conditions,
+		Actions:    p.Actions,
+	}
+	if p.Enabled != nil {
+		rule.Enabled = *p.Enabled
+	}
+	return rule
+}
+
+type ruleDryRunPayload struct {
+	Conditions model.RuleConditions `json:"conditions"`
+}
+
+// ruleID returns the ID of the rule in the path, sending the error response if it is invalid.
+func ruleID(c model.WebContext) (model.DBID, bool) {
+	id, err := strconv.Atoi(c.Request().PathValue("id"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid rule ID")
+		return 0, false
+	}
+
+	return model.DBID(id), true
+}
+
+// @Summary					List rules
+// @Description				List the rules of the logged in account, in the order they are applied.
+// @Tags						Rules
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Success					200	{array}		model.BookmarkRule
+// @Failure					401	{object}	nil	"Authentication required"
+// @Failure					500	{object}	nil	"Internal server error"
+// @Router						/api/v1/rules [get]
+func HandleListRules(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	rules, err := deps.Domains().Rules().ListRules(c.Request().Context(), c.GetAccount())
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to list rules")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, rules)
+}
+
+// @Summary					Create a rule
+// @Description				Create a rule checked when a bookmark of the logged in account is saved or its content downloaded. Its actions are applied when every condition matches.
+// @Tags						Rules
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Accept						json
+// @Produce					json
+// @Param						payload	body		rulePayload	true	"Rule data"
+// @Success					201		{object}	model.BookmarkRule
+// @Failure					400		{object}	nil	"Invalid rule data"
+// @Failure					401		{object}	nil	"Authentication required"
+// @Failure					500		{object}	nil	"Internal server error"
+// @Router						/api/v1/rules [post]
+func HandleCreateRule(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	var payload rulePayload
+	if err := json.NewDecoder(c.Request().Body).Decode(&payload); err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	created, err := deps.Domains().Rules().CreateRule(c.Request().Context(), c.GetAccount(), payload.ToBookmarkRule())
+	if err, isValidationErr := err.(model.ValidationError); isValidationErr {
+		response.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to create rule")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusCreated, created)
+}
+
+// @Summary					Get a rule
+// @Tags						Rules
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Param						id	path		int	true	"Rule ID"
+// @Success					200	{object}	model.BookmarkRule
+// @Failure					400	{object}	nil	"Invalid rule ID"
+// @Failure					401	{object}	nil	"Authentication required"
+// @Failure					404	{object}	nil	"Rule not found"
+// @Failure					500	{object}	nil	"Internal server error"
+// @Router						/api/v1/rules/{id} [get]
+func HandleGetRule(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	id, ok := ruleID(c)
+	if !ok {
+		return
+	}
+
+	rule, err := deps.Domains().Rules().GetRule(c.Request().Context(), c.GetAccount(), id)
+	if errors.Is(err, model.ErrNotFound) {
+		response.NotFound(c)
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to get rule")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, rule)
+}
+
+// @Summary					Update a rule
+// @Description				Replace the name, state, conditions and actions of a rule.
+// @Tags						Rules
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Accept						json
+// @Produce					json
+// @Param						id		path		int			true	"Rule ID"
+// @Param						payload	body		rulePayload	true	"Rule data"
+// @Success					200		{object}	model.BookmarkRule
+// @Failure					400		{object}	nil	"Invalid rule ID or data"
+// @Failure					401		{object}	nil	"Authentication required"
+// @Failure					404		{object}	nil	"Rule not found"
+// @Failure					500		{object}	nil	"Internal server error"
+// @Router						/api/v1/rules/{id} [put]
+func HandleUpdateRule(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	id, ok := ruleID(c)
+	if !ok {
+		return
+	}
+
+	var payload rulePayload
+	if err := json.NewDecoder(c.Request().Body).Decode(&payload); err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	rule := payload.ToBookmarkRule()
+	rule.ID = id
+
+	updated, err := deps.Domains().Rules().UpdateRule(c.Request().Context(), c.GetAccount(), rule)
+	if err, isValidationErr := err.(model.ValidationError); isValidationErr {
+		response.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, model.ErrNotFound) {
+		response.NotFound(c)
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to update rule")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, updated)
+}
+
+// @Summary					Delete a rule
+// @Tags						Rules
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Param						id	path		int	true	"Rule ID"
+// @Success					204	{object}	nil
+// @Failure					400	{object}	nil	"Invalid rule ID"
+// @Failure					401	{object}	nil	"Authentication required"
+// @Failure					404	{object}	nil	"Rule not found"
+// @Failure					500	{object}	nil	"Internal server error"
+// @Router						/api/v1/rules/{id} [delete]
+func HandleDeleteRule(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	id, ok := ruleID(c)
+	if !ok {
+		return
+	}
+
+	err := deps.Domains().Rules().DeleteRule(c.Request().Context(), c.GetAccount(), id)
+	if errors.Is(err, model.ErrNotFound) {
+		response.NotFound(c)
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to delete rule")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusNoContent, nil)
+}
+
+// @Summary					Dry run a rule
+// @Description				List the existing bookmarks of the logged in account matched by the conditions, without saving the rule. The content type of existing bookmarks isn't known, a content type condition matches none of them.
+// @Tags						Rules
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Accept						json
+// @Produce					json
+// @Param						payload	body		ruleDryRunPayload	true	"Rule conditions"
+// @Success					200		{array}		model.BookmarkDTO
+// @Failure					400		{object}	nil	"Invalid conditions"
+// @Failure					401		{object}	nil	"Authentication required"
+// @Failure					500		{object}	nil	"Internal server error"
+// @Router						/api/v1/rules/dry-run [post]
+func HandleDryRunRule(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	var payload ruleDryRunPayload
+	if err := json.NewDecoder(c.Request().Body).Decode(&payload); err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	bookmarks, err := deps.Domains().Rules().DryRun(c.Request().Context(), c.GetAccount(), model.BookmarkRule{Conditions: payload.Conditions})
+	if err, isValidationErr := err.(model.ValidationError); isValidationErr {
+		response.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to dry run rule")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, bookmarks)
+}
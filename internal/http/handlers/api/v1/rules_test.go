@@ -0,0 +1,146 @@
+package api_v1
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/shiori/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleCreateRule(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("requires authentication", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleCreateRule, http.MethodPost, "/api/v1/rules")
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("invalid url pattern", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleCreateRule, http.MethodPost, "/api/v1/rules",
+			testutil.WithFakeUser(),
+			testutil.WithBody(`{"name": "Broken", "conditions": {"url_pattern": "("}, "actions": {"reject": true}}`),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("enabled by default", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleCreateRule, http.MethodPost, "/api/v1/rules",
+			testutil.WithFakeUser(),
+			testutil.WithBody(`{"name": "PDFs", "conditions": {"content_type": "application/pdf"}, "actions": {"add_tags": ["pdf"], "create_ebook": false}}`),
+		)
+		require.Equal(t, http.StatusCreated, w.Code)
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertOk(t)
+		response.AssertMessageJSONKeyValue(t, "enabled", func(t *testing.T, value any) {
+			require.Equal(t, true, value)
+		})
+		response.AssertMessageJSONKeyValue(t, "actions", func(t *testing.T, value any) {
+			require.Equal(t, map[string]any{"add_tags": []any{"pdf"}, "create_ebook": false}, value)
+		})
+
+		w = testutil.PerformRequest(deps, HandleListRules, http.MethodGet, "/api/v1/rules", testutil.WithFakeUser())
+		require.Equal(t, http.StatusOK, w.Code)
+		testutil.NewTestResponseFromRecorder(w).AssertMessageIsListLength(t, 1)
+	})
+}
+
+func TestHandleUpdateRule(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("rule of another account", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		rule, err := deps.Database().CreateBookmarkRule(ctx, model.BookmarkRule{
+			AccountID:  testutil.FakeAccountID + 1,
+			Name:       "Theirs",
+			Conditions: model.RuleConditions{Host: "example.com"},
+			Actions:    model.RuleActions{Reject: true},
+		})
+		require.NoError(t, err)
+
+		id := strconv.Itoa(int(rule.ID))
+		w := testutil.PerformRequest(deps, HandleUpdateRule, http.MethodPut, "/api/v1/rules/"+id,
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+			testutil.WithBody(`{"name": "Mine", "conditions": {"host": "example.com"}, "actions": {"add_tags": ["example"]}}`),
+		)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("disable", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		rule, err := deps.Domains().Rules().CreateRule(ctx, testutil.FakeAccount(false), model.BookmarkRule{
+			Name:       "Example",
+			Enabled:    true,
+			Conditions: model.RuleConditions{Host: "example.com"},
+			Actions:    model.RuleActions{Reject: true},
+		})
+		require.NoError(t, err)
+
+		id := strconv.Itoa(int(rule.ID))
+		w := testutil.PerformRequest(deps, HandleUpdateRule, http.MethodPut, "/api/v1/rules/"+id,
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+			testutil.WithBody(`{"name": "Example", "enabled": false, "conditions": {"host": "example.com"}, "actions": {"reject": true}}`),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+		testutil.NewTestResponseFromRecorder(w).AssertMessageJSONKeyValue(t, "enabled", func(t *testing.T, value any) {
+			require.Equal(t, false, value)
+		})
+
+		w = testutil.PerformRequest(deps, HandleDeleteRule, http.MethodDelete, "/api/v1/rules/"+id,
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+		)
+		require.Equal(t, http.StatusNoContent, w.Code)
+
+		w = testutil.PerformRequest(deps, HandleGetRule, http.MethodGet, "/api/v1/rules/"+id,
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+		)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestHandleDryRunRule(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("without conditions", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleDryRunRule, http.MethodPost, "/api/v1/rules/dry-run",
+			testutil.WithFakeUser(),
+			testutil.WithBody(`{"conditions": {}}`),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("matching bookmarks", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		_, err := deps.Database().SaveBookmarks(ctx, true,
+			model.BookmarkDTO{AccountID: testutil.FakeAccountID, URL: "https://news.example.com/one", Title: "One"},
+			model.BookmarkDTO{AccountID: testutil.FakeAccountID, URL: "https://example.org/two", Title: "Two"},
+		)
+		require.NoError(t, err)
+
+		w := testutil.PerformRequest(deps, HandleDryRunRule, http.MethodPost, "/api/v1/rules/dry-run",
+			testutil.WithFakeUser(),
+			testutil.WithBody(`{"conditions": {"host": "example.com"}}`),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+		testutil.NewTestResponseFromRecorder(w).AssertMessageIsListLength(t, 1)
+		require.Contains(t, w.Body.String(), "https://news.example.com/one")
+	})
+}
@@ -0,0 +1,247 @@
+package api_v1
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-shiori/shiori/internal/http/middleware"
+	"github.com/go-shiori/shiori/internal/http/response"
+	"github.com/go-shiori/shiori/internal/model"
+)
+
+type savedSearchPayload struct {
+	Name   string             `json:"name"`
+	Filter model.SearchFilter `json:"filter"`
+}
+
+// savedSearchID returns the ID of the saved search in the path, sending the error response if it is invalid.
+func savedSearchID(c model.WebContext) (model.DBID, bool) {
+	id, err := strconv.Atoi(c.Request().PathValue("id"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid saved search ID")
+		return 0, false
+	}
+
+	return model.DBID(id), true
+}
+
+// applySavedSearchParam narrows the listing options down to the saved search in the search_id
+// query parameter, if any. It sends the error response and returns false if the search is invalid.
+func applySavedSearchParam(deps model.Dependencies, c model.WebContext, opts *model.ListBookmarksOptions) bool {
+	searchParam := c.Request().URL.Query().Get("search_id")
+	if searchParam == "" {
+		return true
+	}
+
+	id, err := strconv.Atoi(searchParam)
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid saved search ID")
+		return false
+	}
+
+	err = deps.Domains().SavedSearches().ApplySearch(c.Request().Context(), c.GetAccount(), model.DBID(id), opts)
+	if errors.Is(err, model.ErrNotFound) {
+		response.SendError(c, http.StatusNotFound, "Saved search not found")
+		return false
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to get saved search")
+		response.SendInternalServerError(c)
+		return false
+	}
+
+	return true
+}
+
+// @Summary					List saved searches
+// @Description				List the saved searches of the logged in account, ordered by name.
+// @Tags						Saved searches
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Success					200	{array}		model.SavedSearch
+// @Failure					401	{object}	nil	"Authentication required"
+// @Failure					500	{object}	nil	"Internal server error"
+// @Router						/api/v1/searches [get]
+func HandleListSavedSearches(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	searches, err := deps.Domains().SavedSearches().ListSearches(c.Request().Context(), c.GetAccount())
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to list saved searches")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, searches)
+}
+
+// @Summary					Save a search
+// @Description				Save a bookmark search under a name unique in the account. Its bookmarks are listed with the search_id parameter of the bookmark listing.
+// @Tags						Saved searches
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Accept						json
+// @Produce					json
+// @Param						payload	body		savedSearchPayload	true	"Saved search data"
+// @Success					201		{object}	model.SavedSearch
+// @Failure					400		{object}	nil	"Invalid saved search data"
+// @Failure					401		{object}	nil	"Authentication required"
+// @Failure					500		{object}	nil	"Internal server error"
+// @Router						/api/v1/searches [post]
+func HandleCreateSavedSearch(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	var payload savedSearchPayload
+	if err := json.NewDecoder(c.Request().Body).Decode(&payload); err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	created, err := deps.Domains().SavedSearches().CreateSearch(c.Request().Context(), c.GetAccount(), model.SavedSearch{
+		Name:   payload.Name,
+		Filter: payload.Filter,
+	})
+	if err, isValidationErr := err.(model.ValidationError); isValidationErr {
+		response.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to create saved search")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusCreated, created)
+}
+
+// @Summary					Get a saved search
+// @Tags						Saved searches
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Param						id	path		int	true	"Saved search ID"
+// @Success					200	{object}	model.SavedSearch
+// @Failure					400	{object}	nil	"Invalid saved search ID"
+// @Failure					401	{object}	nil	"Authentication required"
+// @Failure					404	{object}	nil	"Saved search not found"
+// @Failure					500	{object}	nil	"Internal server error"
+// @Router						/api/v1/searches/{id} [get]
+func HandleGetSavedSearch(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	id, ok := savedSearchID(c)
+	if !ok {
+		return
+	}
+
+	search, err := deps.Domains().SavedSearches().GetSearch(c.Request().Context(), c.GetAccount(), id)
+	if errors.Is(err, model.ErrNotFound) {
+		response.NotFound(c)
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to get saved search")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, search)
+}
+
+// @Summary					Update a saved search
+// @Description				Replace the name and filter of a saved search.
+// @Tags						Saved searches
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Accept						json
+// @Produce					json
+// @Param						id		path		int					true	"Saved search ID"
+// @Param						payload	body		savedSearchPayload	true	"Saved search data"
+// @Success					200		{object}	model.SavedSearch
+// @Failure					400		{object}	nil	"Invalid saved search ID or data"
+// @Failure					401		{object}	nil	"Authentication required"
+// @Failure					404		{object}	nil	"Saved search not found"
+// @Failure					500		{object}	nil	"Internal server error"
+// @Router						/api/v1/searches/{id} [put]
+func HandleUpdateSavedSearch(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	id, ok := savedSearchID(c)
+	if !ok {
+		return
+	}
+
+	var payload savedSearchPayload
+	if err := json.NewDecoder(c.Request().Body).Decode(&payload); err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	updated, err := deps.Domains().SavedSearches().UpdateSearch(c.Request().Context(), c.GetAccount(), model.SavedSearch{
+		ID:     id,
+		Name:   payload.Name,
+		Filter: payload.Filter,
+	})
+	if err, isValidationErr := err.(model.ValidationError); isValidationErr {
+		response.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, model.ErrNotFound) {
+		response.NotFound(c)
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to update saved search")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, updated)
+}
+
+// @Summary					Delete a saved search
+// @Tags						Saved searches
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Param						id	path		int	true	"Saved search ID"
+// @Success					204	{object}	nil
+// @Failure					400	{object}	nil	"Invalid saved search ID"
+// @Failure					401	{object}	nil	"Authentication required"
+// @Failure					404	{object}	nil	"Saved search not found"
+// @Failure					500	{object}	nil	"Internal server error"
+// @Router						/api/v1/searches/{id} [delete]
+func HandleDeleteSavedSearch(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	id, ok := savedSearchID(c)
+	if !ok {
+		return
+	}
+
+	err := deps.Domains().SavedSearches().DeleteSearch(c.Request().Context(), c.GetAccount(), id)
+	if errors.Is(err, model.ErrNotFound) {
+		response.NotFound(c)
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to delete saved search")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusNoContent, nil)
+}
@@ -0,0 +1,113 @@
+package api_v1
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/shiori/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleCreateSavedSearch(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("requires authentication", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleCreateSavedSearch, http.MethodPost, "/api/v1/searches")
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("invalid link status", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleCreateSavedSearch, http.MethodPost, "/api/v1/searches",
+			testutil.WithFakeUser(),
+			testutil.WithBody(`{"name": "Broken", "filter": {"link_status": "unknown"}}`),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("duplicated name", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleCreateSavedSearch, http.MethodPost, "/api/v1/searches",
+			testutil.WithFakeUser(),
+			testutil.WithBody(`{"name": "Go", "filter": {"tags": ["golang"], "added_within_days": 7}}`),
+		)
+		require.Equal(t, http.StatusCreated, w.Code)
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertOk(t)
+		response.AssertMessageJSONKeyValue(t, "filter", func(t *testing.T, value any) {
+			require.Equal(t, map[string]any{"tags": []any{"golang"}, "added_within_days": float64(7)}, value)
+		})
+
+		w = testutil.PerformRequest(deps, HandleCreateSavedSearch, http.MethodPost, "/api/v1/searches",
+			testutil.WithFakeUser(),
+			testutil.WithBody(`{"name": "go", "filter": {}}`),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = testutil.PerformRequest(deps, HandleListSavedSearches, http.MethodGet, "/api/v1/searches", testutil.WithFakeUser())
+		require.Equal(t, http.StatusOK, w.Code)
+		testutil.NewTestResponseFromRecorder(w).AssertMessageIsListLength(t, 1)
+	})
+}
+
+func TestHandleUpdateSavedSearch(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("search of another account", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		search, err := deps.Database().CreateSavedSearch(ctx, model.SavedSearch{
+			AccountID: testutil.FakeAccountID + 1,
+			Name:      "Theirs",
+		})
+		require.NoError(t, err)
+
+		id := strconv.Itoa(int(search.ID))
+		w := testutil.PerformRequest(deps, HandleUpdateSavedSearch, http.MethodPut, "/api/v1/searches/"+id,
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+			testutil.WithBody(`{"name": "Mine", "filter": {}}`),
+		)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("rename", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		search, err := deps.Domains().SavedSearches().CreateSearch(ctx, testutil.FakeAccount(false), model.SavedSearch{
+			Name:   "Go",
+			Filter: model.SearchFilter{Keyword: "generics"},
+		})
+		require.NoError(t, err)
+
+		id := strconv.Itoa(int(search.ID))
+		w := testutil.PerformRequest(deps, HandleUpdateSavedSearch, http.MethodPut, "/api/v1/searches/"+id,
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+			testutil.WithBody(`{"name": "Golang", "filter": {"keyword": "generics"}}`),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+		testutil.NewTestResponseFromRecorder(w).AssertMessageJSONKeyValue(t, "name", func(t *testing.T, value any) {
+			require.Equal(t, "Golang", value)
+		})
+
+		w = testutil.PerformRequest(deps, HandleDeleteSavedSearch, http.MethodDelete, "/api/v1/searches/"+id,
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+		)
+		require.Equal(t, http.StatusNoContent, w.Code)
+
+		w = testutil.PerformRequest(deps, HandleGetSavedSearch, http.MethodGet, "/api/v1/searches/"+id,
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+		)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
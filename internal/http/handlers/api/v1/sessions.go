@@ -0,0 +1,78 @@
+package api_v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-shiori/shiori/internal/http/middleware"
+	"github.com/go-shiori/shiori/internal/http/response"
+	"github.com/go-shiori/shiori/internal/model"
+)
+
+type sessionResponse struct {
+	model.Session
+	// Whether the request was made with this session
+	Current bool `json:"current"`
+}
+
+// @Summary					List active sessions
+// @Description				List the sessions of the logged in account that haven't expired, newest first.
+// @Tags						Auth
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Success					200	{array}		sessionResponse
+// @Failure					401	{object}	nil	"Authentication required"
+// @Failure					500	{object}	nil	"Internal server error"
+// @Router						/api/v1/auth/sessions [get]
+func HandleListSessions(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	sessions, err := deps.Domains().Auth().ListSessions(c.Request().Context(), c.GetAccount().ID)
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to list sessions")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	result := make([]sessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, sessionResponse{
+			Session: session,
+			Current: session.ID == c.GetSessionID(),
+		})
+	}
+
+	response.SendJSON(c, http.StatusOK, result)
+}
+
+// @Summary					Revoke a session
+// @Description				End a session of the logged in account, its token can't be used anymore.
+// @Tags						Auth
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Param						id	path		string	true	"Session ID"
+// @Success					204	{object}	nil
+// @Failure					401	{object}	nil	"Authentication required"
+// @Failure					404	{object}	nil	"Session not found"
+// @Failure					500	{object}	nil	"Internal server error"
+// @Router						/api/v1/auth/sessions/{id} [delete]
+func HandleRevokeSession(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	err := deps.Domains().Auth().RevokeSession(c.Request().Context(), c.GetAccount().ID, c.Request().PathValue("id"))
+	if errors.Is(err, model.ErrNotFound) {
+		response.NotFound(c)
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to revoke session")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusNoContent, nil)
+}
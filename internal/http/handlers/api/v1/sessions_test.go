@@ -0,0 +1,114 @@
+package api_v1
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleListSessions(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("requires authentication", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleListSessions, http.MethodGet, "/api/v1/auth/sessions")
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("list own sessions", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		account := testutil.FakeAccount(false)
+		for _, userAgent := range []string{"Firefox", "Chrome"} {
+			_, err := deps.Domains().Auth().CreateTokenForAccount(ctx, account, time.Now().Add(time.Hour), userAgent)
+			require.NoError(t, err)
+		}
+		_, err := deps.Domains().Auth().CreateTokenForAccount(ctx, testutil.FakeAccount(true), time.Now().Add(time.Hour), "Safari")
+		require.NoError(t, err)
+		other := testutil.FakeAccount(false)
+		other.ID++
+		_, err = deps.Domains().Auth().CreateTokenForAccount(ctx, other, time.Now().Add(time.Hour), "Edge")
+		require.NoError(t, err)
+
+		sessions, err := deps.Domains().Auth().ListSessions(ctx, account.ID)
+		require.NoError(t, err)
+		require.Len(t, sessions, 3)
+
+		w := testutil.PerformRequest(deps, HandleListSessions, http.MethodGet, "/api/v1/auth/sessions",
+			testutil.WithFakeUser(),
+			testutil.WithSessionID(sessions[0].ID),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertOk(t)
+		response.AssertMessageIsListLength(t, 3)
+		response.ForEach(t, func(item map[string]any) {
+			require.NotEqual(t, "Edge", item["user_agent"])
+			require.Equal(t, item["id"] == sessions[0].ID, item["current"])
+		})
+	})
+}
+
+func TestHandleRevokeSession(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("requires authentication", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleRevokeSession, http.MethodDelete, "/api/v1/auth/sessions/1")
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleRevokeSession, http.MethodDelete, "/api/v1/auth/sessions/unknown",
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", "unknown"),
+		)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("sessions of other accounts are not revoked", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		other := testutil.FakeAccount(false)
+		other.ID++
+		_, err := deps.Domains().Auth().CreateTokenForAccount(ctx, other, time.Now().Add(time.Hour), "")
+		require.NoError(t, err)
+		sessions, err := deps.Domains().Auth().ListSessions(ctx, other.ID)
+		require.NoError(t, err)
+
+		w := testutil.PerformRequest(deps, HandleRevokeSession, http.MethodDelete, "/api/v1/auth/sessions/"+sessions[0].ID,
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", sessions[0].ID),
+		)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("revoke own session", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		account := testutil.FakeAccount(false)
+		_, err := deps.Domains().Auth().CreateTokenForAccount(ctx, account, time.Now().Add(time.Hour), "")
+		require.NoError(t, err)
+		sessions, err := deps.Domains().Auth().ListSessions(ctx, account.ID)
+		require.NoError(t, err)
+
+		w := testutil.PerformRequest(deps, HandleRevokeSession, http.MethodDelete, "/api/v1/auth/sessions/"+sessions[0].ID,
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", sessions[0].ID),
+		)
+		require.Equal(t, http.StatusNoContent, w.Code)
+
+		sessions, err = deps.Domains().Auth().ListSessions(ctx, account.ID)
+		require.NoError(t, err)
+		require.Empty(t, sessions)
+	})
+}
@@ -0,0 +1,209 @@
+package api_v1
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-shiori/shiori/internal/http/middleware"
+	"github.com/go-shiori/shiori/internal/http/response"
+	"github.com/go-shiori/shiori/internal/model"
+)
+
+type createSubscriptionPayload struct {
+	URL string `json:"url"`
+	// Title of the subscription, the feed title is used if empty
+	Title string `json:"title"`
+	// Tags given to the bookmarks saved from the feed
+	Tags          []string `json:"tags"`
+	CreateArchive bool     `json:"create_archive"`
+	CreateEbook   bool     `json:"create_ebook"`
+}
+
+// getSubscription returns the subscription of the path owned by the logged in account,
+// sending the error response if there is none.
+func getSubscription(deps model.Dependencies, c model.WebContext) (*model.Subscription, bool) {
+	id, err := strconv.Atoi(c.Request().PathValue("id"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid subscription ID")
+		return nil, false
+	}
+
+	subscription, err := deps.Domains().Subscriptions().GetSubscription(c.Request().Context(), model.DBID(id), c.GetAccount().ID)
+	if errors.Is(err, model.ErrNotFound) {
+		response.NotFound(c)
+		return nil, false
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to get subscription")
+		response.SendInternalServerError(c)
+		return nil, false
+	}
+
+	return subscription, true
+}
+
+// @Summary					List feed subscriptions
+// @Description				List the feeds the logged in account is subscribed to.
+// @Tags						Subscriptions
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Success					200	{array}		model.Subscription
+// @Failure					401	{object}	nil	"Authentication required"
+// @Failure					500	{object}	nil	"Internal server error"
+// @Router						/api/v1/subscriptions [get]
+func HandleListSubscriptions(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	subscriptions, err := deps.Domains().Subscriptions().ListSubscriptions(c.Request().Context(), c.GetAccount().ID)
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to list subscriptions")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, subscriptions)
+}
+
+// @Summary					Subscribe to a feed
+// @Description				Subscribe to a RSS, Atom or JSON feed. Its items are saved as bookmarks when it is polled, skipping the URLs bookmarked already.
+// @Tags						Subscriptions
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Accept						json
+// @Produce					json
+// @Param						payload	body		createSubscriptionPayload	true	"Subscription data"
+// @Success					201		{object}	model.Subscription
+// @Failure					400		{object}	nil	"Invalid subscription data"
+// @Failure					401		{object}	nil	"Authentication required"
+// @Failure					500		{object}	nil	"Internal server error"
+// @Router						/api/v1/subscriptions [post]
+func HandleCreateSubscription(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	var payload createSubscriptionPayload
+	if err := json.NewDecoder(c.Request().Body).Decode(&payload); err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	subscription, err := deps.Domains().Subscriptions().CreateSubscription(c.Request().Context(), model.Subscription{
+		AccountID:     c.GetAccount().ID,
+		URL:           payload.URL,
+		Title:         payload.Title,
+		Tags:          payload.Tags,
+		CreateArchive: payload.CreateArchive,
+		CreateEbook:   payload.CreateEbook,
+	})
+	if err, isValidationErr := err.(model.ValidationError); isValidationErr {
+		response.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to create subscription")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusCreated, subscription)
+}
+
+// @Summary					Get a feed subscription
+// @Tags						Subscriptions
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Param						id	path		int	true	"Subscription ID"
+// @Success					200	{object}	model.Subscription
+// @Failure					400	{object}	nil	"Invalid subscription ID"
+// @Failure					401	{object}	nil	"Authentication required"
+// @Failure					404	{object}	nil	"Subscription not found"
+// @Failure					500	{object}	nil	"Internal server error"
+// @Router						/api/v1/subscriptions/{id} [get]
+func HandleGetSubscription(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	subscription, ok := getSubscription(deps, c)
+	if !ok {
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, subscription)
+}
+
+// @Summary					Unsubscribe from a feed
+// @Description				Delete a feed subscription. The bookmarks saved from it are kept.
+// @Tags						Subscriptions
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Param						id	path		int	true	"Subscription ID"
+// @Success					204	{object}	nil
+// @Failure					400	{object}	nil	"Invalid subscription ID"
+// @Failure					401	{object}	nil	"Authentication required"
+// @Failure					404	{object}	nil	"Subscription not found"
+// @Failure					500	{object}	nil	"Internal server error"
+// @Router						/api/v1/subscriptions/{id} [delete]
+func HandleDeleteSubscription(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Request().PathValue("id"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid subscription ID")
+		return
+	}
+
+	err = deps.Domains().Subscriptions().DeleteSubscription(c.Request().Context(), model.DBID(id), c.GetAccount().ID)
+	if errors.Is(err, model.ErrNotFound) {
+		response.NotFound(c)
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to delete subscription")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusNoContent, nil)
+}
+
+// @Summary					Poll a feed subscription
+// @Description				Fetch the feed now and save its new items as bookmarks. Their content is downloaded by background jobs.
+// @Description				A feed that can't be fetched is reported in the error field of the result.
+// @Tags						Subscriptions
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Param						id	path		int	true	"Subscription ID"
+// @Success					200	{object}	model.PollResult
+// @Failure					400	{object}	nil	"Invalid subscription ID"
+// @Failure					401	{object}	nil	"Authentication required"
+// @Failure					404	{object}	nil	"Subscription not found"
+// @Failure					500	{object}	nil	"Internal server error"
+// @Router						/api/v1/subscriptions/{id}/poll [post]
+func HandlePollSubscription(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	subscription, ok := getSubscription(deps, c)
+	if !ok {
+		return
+	}
+
+	result, err := deps.Domains().Subscriptions().Poll(c.Request().Context(), *subscription)
+	if err != nil {
+		deps.Logger().WithError(err).WithField("subscription_id", subscription.ID).Error("failed to poll subscription")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, result)
+}
@@ -0,0 +1,192 @@
+package api_v1
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/shiori/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleListSubscriptions(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("requires authentication", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleListSubscriptions, http.MethodGet, "/api/v1/subscriptions")
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("list own subscriptions", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		for _, accountID := range []model.DBID{testutil.FakeAccountID, testutil.FakeAccountID + 1} {
+			_, err := deps.Domains().Subscriptions().CreateSubscription(ctx, model.Subscription{
+				AccountID: accountID,
+				URL:       "https://example.com/feed.xml",
+			})
+			require.NoError(t, err)
+		}
+
+		w := testutil.PerformRequest(deps, HandleListSubscriptions, http.MethodGet, "/api/v1/subscriptions", testutil.WithFakeUser())
+		require.Equal(t, http.StatusOK, w.Code)
+
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertOk(t)
+		response.AssertMessageIsListLength(t, 1)
+	})
+}
+
+func TestHandleCreateSubscription(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("requires authentication", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleCreateSubscription, http.MethodPost, "/api/v1/subscriptions")
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleCreateSubscription, http.MethodPost, "/api/v1/subscriptions",
+			testutil.WithFakeUser(),
+			testutil.WithBody(`{`),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid url", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleCreateSubscription, http.MethodPost, "/api/v1/subscriptions",
+			testutil.WithFakeUser(),
+			testutil.WithBody(`{"url": "not a url"}`),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("create subscription", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleCreateSubscription, http.MethodPost, "/api/v1/subscriptions",
+			testutil.WithFakeUser(),
+			testutil.WithBody(`{"url": "https://example.com/feed.xml", "tags": ["news"], "create_archive": true}`),
+		)
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertMessageJSONKeyValue(t, "url", func(t *testing.T, value any) {
+			require.Equal(t, "https://example.com/feed.xml", value)
+		})
+		response.AssertMessageJSONKeyValue(t, "create_archive", func(t *testing.T, value any) {
+			require.Equal(t, true, value)
+		})
+
+		subscriptions, err := deps.Domains().Subscriptions().ListSubscriptions(ctx, testutil.FakeAccountID)
+		require.NoError(t, err)
+		require.Len(t, subscriptions, 1)
+		require.Equal(t, model.SubscriptionTags{"news"}, subscriptions[0].Tags)
+	})
+}
+
+func TestHandleDeleteSubscription(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("requires authentication", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleDeleteSubscription, http.MethodDelete, "/api/v1/subscriptions/1")
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleDeleteSubscription, http.MethodDelete, "/api/v1/subscriptions/invalid",
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", "invalid"),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("other account subscription", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		subscription, err := deps.Domains().Subscriptions().CreateSubscription(ctx, model.Subscription{
+			AccountID: testutil.FakeAccountID + 1,
+			URL:       "https://example.com/feed.xml",
+		})
+		require.NoError(t, err)
+
+		id := strconv.Itoa(int(subscription.ID))
+		w := testutil.PerformRequest(deps, HandleDeleteSubscription, http.MethodDelete, "/api/v1/subscriptions/"+id,
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+		)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("delete subscription", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		subscription, err := deps.Domains().Subscriptions().CreateSubscription(ctx, model.Subscription{
+			AccountID: testutil.FakeAccountID,
+			URL:       "https://example.com/feed.xml",
+		})
+		require.NoError(t, err)
+
+		id := strconv.Itoa(int(subscription.ID))
+		w := testutil.PerformRequest(deps, HandleDeleteSubscription, http.MethodDelete, "/api/v1/subscriptions/"+id,
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+		)
+		require.Equal(t, http.StatusNoContent, w.Code)
+
+		subscriptions, err := deps.Domains().Subscriptions().ListSubscriptions(ctx, testutil.FakeAccountID)
+		require.NoError(t, err)
+		require.Empty(t, subscriptions)
+	})
+}
+
+func TestHandlePollSubscription(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("requires authentication", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandlePollSubscription, http.MethodPost, "/api/v1/subscriptions/1/poll")
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandlePollSubscription, http.MethodPost, "/api/v1/subscriptions/99/poll",
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", "99"),
+		)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("unreachable feed is reported", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		subscription, err := deps.Domains().Subscriptions().CreateSubscription(ctx, model.Subscription{
+			AccountID: testutil.FakeAccountID,
+			URL:       "http://127.0.0.1:1/feed.xml",
+		})
+		require.NoError(t, err)
+
+		id := strconv.Itoa(int(subscription.ID))
+		w := testutil.PerformRequest(deps, HandlePollSubscription, http.MethodPost, "/api/v1/subscriptions/"+id+"/poll",
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertOk(t)
+		response.AssertMessageJSONKeyValue(t, "error", func(t *testing.T, value any) {
+			require.NotEmpty(t, value)
+		})
+	})
+}
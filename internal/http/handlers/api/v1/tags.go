@@ -2,6 +2,9 @@ package api_v1
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 
@@ -11,13 +14,14 @@ import (
 )
 
 // @Summary					List tags
-// @Description				List all tags
+// @Description				List the tags used by the current account and the unused ones
 // @Tags						Tags
 // @securityDefinitions.apikey	ApiKeyAuth
 // @Produce					json
 // @Param						with_bookmark_count	query		boolean	false	"Include bookmark count for each tag"
 // @Param						bookmark_id			query		integer	false	"Filter tags by bookmark ID"
 // @Param						search				query		string	false	"Search tags by name"
+// @Param						all_accounts		query		boolean	false	"Include tags of every account, owners only"
 // @Success					200					{array}		model.TagDTO
 // @Failure					403					{object}	nil	"Authentication required"
 // @Failure					500					{object}	nil	"Internal server error"
@@ -43,6 +47,7 @@ func HandleListTags(deps model.Dependencies, c model.WebContext) {
 
 	// Create options and validate
 	opts := model.ListTagsOptions{
+		AccountID:         bookmarksAccountScope(c),
 		WithBookmarkCount: withBookmarkCount,
 		BookmarkID:        bookmarkID,
 		OrderBy:           model.DBTagOrderByTagName,
@@ -109,7 +114,7 @@ func HandleGetTag(deps model.Dependencies, c model.WebContext) {
 // @Produce					json
 // @Param						tag	body		model.TagDTO	true	"Tag data"
 // @Success					201	{object}	model.TagDTO
-// @Failure					400	{object}	nil	"Invalid request"
+// @Failure					400	{object}	nil	"Invalid request, name already used or invalid parent"
 // @Failure					403	{object}	nil	"Authentication required"
 // @Failure					500	{object}	nil	"Internal server error"
 // @Router						/api/v1/tags [post]
@@ -131,6 +136,11 @@ func HandleCreateTag(deps model.Dependencies, c model.WebContext) {
 	}
 
 	createdTag, err := deps.Domains().Tags().CreateTag(c.Request().Context(), tag)
+	if err, isValidationErr := err.(model.ValidationError); isValidationErr {
+		response.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	if err != nil {
 		deps.Logger().WithError(err).Error("failed to create tag")
 		response.SendInternalServerError(c)
@@ -141,7 +151,7 @@ func HandleCreateTag(deps model.Dependencies, c model.WebContext) {
 }
 
 // @Summary					Update tag
-// @Description				Update an existing tag
+// @Description				Update the name and parent of an existing tag. The parent is only changed when parent_id is sent, a null parent moves the tag to the top level
 // @Tags						Tags
 // @securityDefinitions.apikey	ApiKeyAuth
 // @Accept						json
@@ -155,7 +165,7 @@ func HandleCreateTag(deps model.Dependencies, c model.WebContext) {
 // @Failure					500	{object}	nil	"Internal server error"
 // @Router						/api/v1/tags/{id} [put]
 func HandleUpdateTag(deps model.Dependencies, c model.WebContext) {
-	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+	if err := middleware.RequireLoggedInAdmin(deps, c); err != nil {
 		return
 	}
 
@@ -166,13 +176,23 @@ func HandleUpdateTag(deps model.Dependencies, c model.WebContext) {
 		return
 	}
 
-	var tag model.TagDTO
-	err = json.NewDecoder(c.Request().Body).Decode(&tag)
+	body, err := io.ReadAll(c.Request().Body)
 	if err != nil {
 		response.SendError(c, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
+	var tag model.TagDTO
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &tag); err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
 	if tag.Name == "" {
 		response.SendError(c, http.StatusBadRequest, "Tag name is required")
 		return
@@ -181,7 +201,27 @@ func HandleUpdateTag(deps model.Dependencies, c model.WebContext) {
 	// Ensure the ID in the URL matches the ID in the body
 	tag.ID = id
 
+	// Renaming a tag keeps its parent, it is only changed when parent_id is sent
+	if _, sent := fields["parent_id"]; !sent {
+		existing, err := deps.Domains().Tags().GetTag(c.Request().Context(), id)
+		if errors.Is(err, model.ErrNotFound) {
+			response.NotFound(c)
+			return
+		}
+		if err != nil {
+			deps.Logger().WithError(err).Error("failed to get tag")
+			response.SendInternalServerError(c)
+			return
+		}
+		tag.ParentID = existing.ParentID
+	}
+
 	updatedTag, err := deps.Domains().Tags().UpdateTag(c.Request().Context(), tag)
+	if err, isValidationErr := err.(model.ValidationError); isValidationErr {
+		response.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	if err != nil {
 		if err == model.ErrNotFound {
 			response.NotFound(c)
@@ -230,3 +270,207 @@ func HandleDeleteTag(deps model.Dependencies, c model.WebContext) {
 
 	response.SendJSON(c, http.StatusNoContent, nil)
 }
+
+type mergeTagsPayload struct {
+	// Tags merged into the tag in the path
+	TagIDs []int `json:"tag_ids"`
+}
+
+func (p *mergeTagsPayload) IsValid() error {
+	if len(p.TagIDs) == 0 {
+		return fmt.Errorf("tag_ids should not be empty")
+	}
+	return nil
+}
+
+type createTagAliasPayload struct {
+	Name string `json:"name"`
+}
+
+// @Summary					Merge tags
+// @Description				Merge tags into the tag in the path. Their bookmarks, aliases and child tags are moved to it, their names become its aliases and they are deleted.
+// @Tags						Tags
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Accept						json
+// @Produce					json
+// @Param						id		path		int					true	"Tag ID"
+// @Param						payload	body		mergeTagsPayload	true	"Merged tags"
+// @Success					200		{object}	model.TagDTO
+// @Failure					400		{object}	nil	"Invalid tag ID or merged tags"
+// @Failure					403		{object}	nil	"Authentication required"
+// @Failure					404		{object}	nil	"Tag not found"
+// @Failure					500		{object}	nil	"Internal server error"
+// @Router						/api/v1/tags/{id}/merge [post]
+func HandleMergeTags(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInAdmin(deps, c); err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Request().PathValue("id"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid tag ID")
+		return
+	}
+
+	var payload mergeTagsPayload
+	if err := json.NewDecoder(c.Request().Body).Decode(&payload); err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	if err := payload.IsValid(); err != nil {
+		response.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tag, err := deps.Domains().Tags().MergeTags(c.Request().Context(), id, payload.TagIDs)
+	if err, isValidationErr := err.(model.ValidationError); isValidationErr {
+		response.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, model.ErrNotFound) {
+		response.NotFound(c)
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to merge tags")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, tag)
+}
+
+// @Summary					List tag aliases
+// @Description				List the other names resolving to a tag
+// @Tags						Tags
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Param						id	path		int	true	"Tag ID"
+// @Success					200	{array}		model.TagAlias
+// @Failure					400	{object}	nil	"Invalid tag ID"
+// @Failure					403	{object}	nil	"Authentication required"
+// @Failure					404	{object}	nil	"Tag not found"
+// @Failure					500	{object}	nil	"Internal server error"
+// @Router						/api/v1/tags/{id}/aliases [get]
+func HandleListTagAliases(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Request().PathValue("id"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid tag ID")
+		return
+	}
+
+	aliases, err := deps.Domains().Tags().ListTagAliases(c.Request().Context(), id)
+	if errors.Is(err, model.ErrNotFound) {
+		response.NotFound(c)
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to list tag aliases")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, aliases)
+}
+
+// @Summary					Create a tag alias
+// @Description				Add another name to a tag. Bookmarks tagged or searched with the alias use the tag instead.
+// @Tags						Tags
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Accept						json
+// @Produce					json
+// @Param						id		path		int						true	"Tag ID"
+// @Param						payload	body		createTagAliasPayload	true	"Alias data"
+// @Success					201		{object}	model.TagAlias
+// @Failure					400		{object}	nil	"Invalid tag ID or name already used"
+// @Failure					403		{object}	nil	"Authentication required"
+// @Failure					404		{object}	nil	"Tag not found"
+// @Failure					500		{object}	nil	"Internal server error"
+// @Router						/api/v1/tags/{id}/aliases [post]
+func HandleCreateTagAlias(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInAdmin(deps, c); err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Request().PathValue("id"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid tag ID")
+		return
+	}
+
+	var payload createTagAliasPayload
+	if err := json.NewDecoder(c.Request().Body).Decode(&payload); err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	alias, err := deps.Domains().Tags().CreateTagAlias(c.Request().Context(), model.TagAlias{Name: payload.Name, TagID: id})
+	if err, isValidationErr := err.(model.ValidationError); isValidationErr {
+		response.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, model.ErrNotFound) {
+		response.NotFound(c)
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to create tag alias")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusCreated, alias)
+}
+
+// @Summary					Delete a tag alias
+// @Tags						Tags
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Param						id			path		int	true	"Tag ID"
+// @Param						alias_id	path		int	true	"Alias ID"
+// @Success					204			{object}	nil
+// @Failure					400			{object}	nil	"Invalid tag or alias ID"
+// @Failure					403			{object}	nil	"Authentication required"
+// @Failure					404			{object}	nil	"Tag or alias not found"
+// @Failure					500			{object}	nil	"Internal server error"
+// @Router						/api/v1/tags/{id}/aliases/{alias_id} [delete]
+func HandleDeleteTagAlias(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInAdmin(deps, c); err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Request().PathValue("id"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid tag ID")
+		return
+	}
+
+	aliasID, err := strconv.Atoi(c.Request().PathValue("alias_id"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid alias ID")
+		return
+	}
+
+	err = deps.Domains().Tags().DeleteTagAlias(c.Request().Context(), id, aliasID)
+	if errors.Is(err, model.ErrNotFound) {
+		response.NotFound(c)
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to delete tag alias")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusNoContent, nil)
+}
@@ -51,9 +51,10 @@ func TestHandleListTags(t *testing.T) {
 
 		// Create a bookmark with this tag
 		bookmark := model.BookmarkDTO{
-			URL:   "https://example.com/test",
-			Title: "Test Bookmark",
-			Tags:  []model.TagDTO{{Tag: model.Tag{Name: tag.Name}}},
+			AccountID: testutil.FakeAccountID,
+			URL:       "https://example.com/test",
+			Title:     "Test Bookmark",
+			Tags:      []model.TagDTO{{Tag: model.Tag{Name: tag.Name}}},
 		}
 		_, err = deps.Database().SaveBookmarks(ctx, true, bookmark)
 		require.NoError(t, err)
@@ -392,6 +393,20 @@ func TestHandleUpdateTag(t *testing.T) {
 		require.Equal(t, http.StatusUnauthorized, w.Code)
 	})
 
+	t.Run("requires admin privileges", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(
+			deps,
+			HandleUpdateTag,
+			"PUT",
+			"/api/v1/tags/1",
+			testutil.WithFakeUser(), // Regular user, not admin
+			testutil.WithRequestPathValue("id", "1"),
+			testutil.WithBody(`{"name": "updated-tag"}`),
+		)
+		require.Equal(t, http.StatusForbidden, w.Code)
+	})
+
 	t.Run("invalid tag id", func(t *testing.T) {
 		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
 		w := testutil.PerformRequest(
@@ -399,7 +414,7 @@ func TestHandleUpdateTag(t *testing.T) {
 			HandleUpdateTag,
 			"PUT",
 			"/api/v1/tags/invalid",
-			testutil.WithFakeUser(),
+			testutil.WithFakeAdmin(),
 			testutil.WithRequestPathValue("id", "invalid"),
 		)
 		require.Equal(t, http.StatusBadRequest, w.Code)
@@ -412,7 +427,7 @@ func TestHandleUpdateTag(t *testing.T) {
 			HandleUpdateTag,
 			"PUT",
 			"/api/v1/tags/1",
-			testutil.WithFakeUser(),
+			testutil.WithFakeAdmin(),
 			testutil.WithRequestPathValue("id", "1"),
 			testutil.WithBody("invalid json"),
 		)
@@ -426,7 +441,7 @@ func TestHandleUpdateTag(t *testing.T) {
 			HandleUpdateTag,
 			"PUT",
 			"/api/v1/tags/1",
-			testutil.WithFakeUser(),
+			testutil.WithFakeAdmin(),
 			testutil.WithRequestPathValue("id", "1"),
 			testutil.WithBody(`{"name": ""}`),
 		)
@@ -440,7 +455,7 @@ func TestHandleUpdateTag(t *testing.T) {
 			HandleUpdateTag,
 			"PUT",
 			"/api/v1/tags/999",
-			testutil.WithFakeUser(),
+			testutil.WithFakeAdmin(),
 			testutil.WithRequestPathValue("id", "999"),
 			testutil.WithBody(`{"name": "updated-tag"}`),
 		)
@@ -462,7 +477,7 @@ func TestHandleUpdateTag(t *testing.T) {
 			HandleUpdateTag,
 			"PUT",
 			"/api/v1/tags/"+strconv.Itoa(tagID),
-			testutil.WithFakeUser(),
+			testutil.WithFakeAdmin(),
 			testutil.WithRequestPathValue("id", strconv.Itoa(tagID)),
 			testutil.WithBody(`{"name": "updated-test-tag"}`),
 		)
@@ -482,6 +497,40 @@ func TestHandleUpdateTag(t *testing.T) {
 		require.True(t, exists)
 		require.Equal(t, "updated-test-tag", updatedTag.Name)
 	})
+
+	t.Run("parent is only changed when sent", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		parent, err := deps.Database().CreateTag(ctx, model.Tag{Name: "languages"})
+		require.NoError(t, err)
+		child, err := deps.Database().CreateTag(ctx, model.Tag{Name: "go", ParentID: &parent.ID})
+		require.NoError(t, err)
+
+		id := strconv.Itoa(child.ID)
+		w := testutil.PerformRequest(deps, HandleUpdateTag, http.MethodPut, "/api/v1/tags/"+id,
+			testutil.WithFakeAdmin(),
+			testutil.WithRequestPathValue("id", id),
+			testutil.WithBody(`{"name": "golang"}`),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		updated, _, err := deps.Database().GetTag(ctx, child.ID)
+		require.NoError(t, err)
+		require.Equal(t, "golang", updated.Name)
+		require.NotNil(t, updated.ParentID)
+		require.Equal(t, parent.ID, *updated.ParentID)
+
+		w = testutil.PerformRequest(deps, HandleUpdateTag, http.MethodPut, "/api/v1/tags/"+id,
+			testutil.WithFakeAdmin(),
+			testutil.WithRequestPathValue("id", id),
+			testutil.WithBody(`{"name": "golang", "parent_id": null}`),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		updated, _, err = deps.Database().GetTag(ctx, child.ID)
+		require.NoError(t, err)
+		require.Nil(t, updated.ParentID)
+	})
 }
 
 func TestHandleDeleteTag(t *testing.T) {
@@ -566,3 +615,144 @@ func TestHandleDeleteTag(t *testing.T) {
 		require.False(t, exists)
 	})
 }
+
+func TestHandleMergeTags(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("requires admin privileges", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleMergeTags, http.MethodPost, "/api/v1/tags/1/merge",
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", "1"),
+		)
+		require.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("empty tags", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleMergeTags, http.MethodPost, "/api/v1/tags/1/merge",
+			testutil.WithFakeAdmin(),
+			testutil.WithRequestPathValue("id", "1"),
+			testutil.WithBody(`{"tag_ids": []}`),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("target not found", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleMergeTags, http.MethodPost, "/api/v1/tags/999/merge",
+			testutil.WithFakeAdmin(),
+			testutil.WithRequestPathValue("id", "999"),
+			testutil.WithBody(`{"tag_ids": [1]}`),
+		)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("successful merge", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		created, err := deps.Database().CreateTags(ctx, model.Tag{Name: "go"}, model.Tag{Name: "golang"})
+		require.NoError(t, err)
+
+		id := strconv.Itoa(created[0].ID)
+		w := testutil.PerformRequest(deps, HandleMergeTags, http.MethodPost, "/api/v1/tags/"+id+"/merge",
+			testutil.WithFakeAdmin(),
+			testutil.WithRequestPathValue("id", id),
+			testutil.WithBody(`{"tag_ids": [`+strconv.Itoa(created[1].ID)+`]}`),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+		testutil.NewTestResponseFromRecorder(w).AssertMessageJSONKeyValue(t, "name", func(t *testing.T, value any) {
+			require.Equal(t, "go", value)
+		})
+
+		w = testutil.PerformRequest(deps, HandleListTagAliases, http.MethodGet, "/api/v1/tags/"+id+"/aliases",
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"golang"`)
+	})
+}
+
+func TestHandleTagAliases(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("requires admin privileges", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		tag, err := deps.Database().CreateTag(ctx, model.Tag{Name: "kubernetes"})
+		require.NoError(t, err)
+
+		id := strconv.Itoa(tag.ID)
+		w := testutil.PerformRequest(deps, HandleCreateTagAlias, http.MethodPost, "/api/v1/tags/"+id+"/aliases",
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+			testutil.WithBody(`{"name": "k8s"}`),
+		)
+		require.Equal(t, http.StatusForbidden, w.Code)
+
+		alias, err := deps.Database().CreateTagAlias(ctx, model.TagAlias{Name: "k8s", TagID: tag.ID})
+		require.NoError(t, err)
+
+		aliasID := strconv.Itoa(alias.ID)
+		w = testutil.PerformRequest(deps, HandleDeleteTagAlias, http.MethodDelete, "/api/v1/tags/"+id+"/aliases/"+aliasID,
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+			testutil.WithRequestPathValue("alias_id", aliasID),
+		)
+		require.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("tag not found", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleCreateTagAlias, http.MethodPost, "/api/v1/tags/999/aliases",
+			testutil.WithFakeAdmin(),
+			testutil.WithRequestPathValue("id", "999"),
+			testutil.WithBody(`{"name": "k8s"}`),
+		)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("create and delete", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		tag, err := deps.Database().CreateTag(ctx, model.Tag{Name: "kubernetes"})
+		require.NoError(t, err)
+
+		id := strconv.Itoa(tag.ID)
+		w := testutil.PerformRequest(deps, HandleCreateTagAlias, http.MethodPost, "/api/v1/tags/"+id+"/aliases",
+			testutil.WithFakeAdmin(),
+			testutil.WithRequestPathValue("id", id),
+			testutil.WithBody(`{"name": "k8s"}`),
+		)
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		var alias model.TagAlias
+		testutil.NewTestResponseFromRecorder(w).AssertMessageJSONKeyValue(t, "id", func(t *testing.T, value any) {
+			alias.ID = int(value.(float64))
+		})
+
+		// The alias name is taken now
+		w = testutil.PerformRequest(deps, HandleCreateTag, http.MethodPost, "/api/v1/tags",
+			testutil.WithFakeUser(),
+			testutil.WithBody(`{"name": "k8s"}`),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = testutil.PerformRequest(deps, HandleDeleteTagAlias, http.MethodDelete, "/api/v1/tags/"+id+"/aliases/"+strconv.Itoa(alias.ID),
+			testutil.WithFakeAdmin(),
+			testutil.WithRequestPathValue("id", id),
+			testutil.WithRequestPathValue("alias_id", strconv.Itoa(alias.ID)),
+		)
+		require.Equal(t, http.StatusNoContent, w.Code)
+
+		w = testutil.PerformRequest(deps, HandleDeleteTagAlias, http.MethodDelete, "/api/v1/tags/"+id+"/aliases/"+strconv.Itoa(alias.ID),
+			testutil.WithFakeAdmin(),
+			testutil.WithRequestPathValue("id", id),
+			testutil.WithRequestPathValue("alias_id", strconv.Itoa(alias.ID)),
+		)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
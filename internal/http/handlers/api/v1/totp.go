@@ -0,0 +1,271 @@
+package api_v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-shiori/shiori/internal/http/middleware"
+	"github.com/go-shiori/shiori/internal/http/response"
+	"github.com/go-shiori/shiori/internal/model"
+)
+
+type loginTOTPPayload struct {
+	// Challenge returned by /api/v1/auth/login
+	Challenge string `json:"challenge"`
+	// Code from the authenticator app or a recovery code
+	Code string `json:"code"`
+}
+
+type totpCodePayload struct {
+	Code string `json:"code"`
+}
+
+type totpRecoveryCodesResponse struct {
+	// Recovery codes, only returned once
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type totpPolicyPayload struct {
+	OwnersRequired bool `json:"owners_required"`
+}
+
+// sendTOTPError sends validation errors back to the user, other errors are logged.
+func sendTOTPError(deps model.Dependencies, c model.WebContext, err error, message string) {
+	if err, isValidationErr := err.(model.ValidationError); isValidationErr {
+		response.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	deps.Logger().WithError(err).Error(message)
+	response.SendInternalServerError(c)
+}
+
+// @Summary		Complete a login with a two-factor authentication code
+// @Description	Second login step of accounts with two-factor authentication. Owners who must set it up send the
+// @Description	first code of their new secret, from /api/v1/auth/login/totp/setup, and get their recovery codes too.
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Param			payload	body		loginTOTPPayload		true	"Login challenge and code"
+// @Success		200		{object}	loginResponseMessage	"Login successful"
+// @Failure		400		{object}	nil						"Invalid challenge or code"
+// @Failure		429		{object}	nil						"Too many login attempts"
+// @Router			/api/v1/auth/login/totp [post]
+func HandleLoginTOTP(deps model.Dependencies, c model.WebContext) {
+	var payload loginTOTPPayload
+	if err := json.NewDecoder(c.Request().Body).Decode(&payload); err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	ctx := c.Request().Context()
+	account, rememberMe, err := deps.Domains().TOTP().CheckLoginChallenge(ctx, payload.Challenge)
+	if err != nil {
+		sendTOTPError(deps, c, err, "failed to check login challenge")
+		return
+	}
+
+	status, err := deps.Domains().TOTP().GetStatus(ctx, account)
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to get two-factor authentication status")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	if !status.Enabled && !status.Required {
+		response.SendError(c, http.StatusBadRequest, "Two-factor authentication is not enabled")
+		return
+	}
+
+	var recoveryCodes []string
+	if status.Enabled {
+		err = deps.Domains().TOTP().Verify(ctx, account.ID, payload.Code)
+	} else {
+		recoveryCodes, err = deps.Domains().TOTP().Enable(ctx, account.ID, payload.Code)
+	}
+	if err != nil {
+		sendTOTPError(deps, c, err, "failed to check two-factor authentication code")
+		return
+	}
+
+	sendLoginSession(deps, c, account, rememberMe, recoveryCodes)
+}
+
+// @Summary		Set up two-factor authentication during a login
+// @Description	Owners who must use two-factor authentication get their secret here when the login asks them to set it up.
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Param			payload	body		loginTOTPPayload	true	"Login challenge"
+// @Success		200		{object}	model.TOTPEnrollment
+// @Failure		400		{object}	nil	"Invalid challenge or already set up"
+// @Router			/api/v1/auth/login/totp/setup [post]
+func HandleLoginTOTPSetup(deps model.Dependencies, c model.WebContext) {
+	var payload loginTOTPPayload
+	if err := json.NewDecoder(c.Request().Body).Decode(&payload); err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	ctx := c.Request().Context()
+	account, _, err := deps.Domains().TOTP().CheckLoginChallenge(ctx, payload.Challenge)
+	if err != nil {
+		sendTOTPError(deps, c, err, "failed to check login challenge")
+		return
+	}
+
+	required, err := deps.Domains().TOTP().IsRequired(ctx, account)
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to get two-factor authentication status")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	if !required {
+		response.SendError(c, http.StatusBadRequest, "Two-factor authentication is not required")
+		return
+	}
+
+	enrollment, err := deps.Domains().TOTP().Enroll(ctx, account)
+	if err != nil {
+		sendTOTPError(deps, c, err, "failed to set up two-factor authentication")
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, enrollment)
+}
+
+// @Summary					Get the two-factor authentication status
+// @Tags						Auth
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Success					200	{object}	model.TOTPStatus
+// @Failure					401	{object}	nil	"Authentication required"
+// @Router						/api/v1/auth/totp [get]
+func HandleGetTOTP(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	status, err := deps.Domains().TOTP().GetStatus(c.Request().Context(), c.GetAccount())
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to get two-factor authentication status")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, status)
+}
+
+// @Summary					Set up two-factor authentication
+// @Description				Generate a new secret for the account, to add to an authenticator app with the URI. It is only
+// @Description				used to log in once enabled with /api/v1/auth/totp/enable.
+// @Tags						Auth
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Success					200	{object}	model.TOTPEnrollment
+// @Failure					400	{object}	nil	"Already enabled"
+// @Failure					401	{object}	nil	"Authentication required"
+// @Router						/api/v1/auth/totp [post]
+func HandleEnrollTOTP(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	enrollment, err := deps.Domains().TOTP().Enroll(c.Request().Context(), c.GetAccount())
+	if err != nil {
+		sendTOTPError(deps, c, err, "failed to set up two-factor authentication")
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, enrollment)
+}
+
+// @Summary					Enable two-factor authentication
+// @Description				Enable the secret set up with POST /api/v1/auth/totp by sending a code generated from it.
+// @Tags						Auth
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Accept						json
+// @Produce					json
+// @Param						payload	body		totpCodePayload	true	"Code from the authenticator app"
+// @Success					200		{object}	totpRecoveryCodesResponse
+// @Failure					400		{object}	nil	"Invalid code"
+// @Failure					401		{object}	nil	"Authentication required"
+// @Router						/api/v1/auth/totp/enable [post]
+func HandleEnableTOTP(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	var payload totpCodePayload
+	if err := json.NewDecoder(c.Request().Body).Decode(&payload); err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	codes, err := deps.Domains().TOTP().Enable(c.Request().Context(), c.GetAccount().ID, payload.Code)
+	if err != nil {
+		sendTOTPError(deps, c, err, "failed to enable two-factor authentication")
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, totpRecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// @Summary					Disable two-factor authentication
+// @Description				Owners can't disable it while it is required for them.
+// @Tags						Auth
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Accept						json
+// @Param						payload	body		totpCodePayload	true	"Code from the authenticator app or recovery code"
+// @Success					204		{object}	nil
+// @Failure					400		{object}	nil	"Invalid code"
+// @Failure					401		{object}	nil	"Authentication required"
+// @Router						/api/v1/auth/totp/disable [post]
+func HandleDisableTOTP(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	var payload totpCodePayload
+	if err := json.NewDecoder(c.Request().Body).Decode(&payload); err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	if err := deps.Domains().TOTP().Disable(c.Request().Context(), c.GetAccount(), payload.Code); err != nil {
+		sendTOTPError(deps, c, err, "failed to disable two-factor authentication")
+		return
+	}
+
+	response.SendJSON(c, http.StatusNoContent, nil)
+}
+
+// @Summary					Require two-factor authentication for owners
+// @Description				Owners without it have to set it up on their next login. The owner requiring it must have enabled it.
+// @Tags						Auth
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Accept						json
+// @Param						payload	body		totpPolicyPayload	true	"Policy"
+// @Success					204		{object}	nil
+// @Failure					400		{object}	nil	"Two-factor authentication not enabled"
+// @Failure					403		{object}	nil	"Only owners can change the policy"
+// @Router						/api/v1/auth/totp/policy [put]
+func HandleUpdateTOTPPolicy(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInAdmin(deps, c); err != nil {
+		return
+	}
+
+	var payload totpPolicyPayload
+	if err := json.NewDecoder(c.Request().Body).Decode(&payload); err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	if err := deps.Domains().TOTP().SetOwnersRequired(c.Request().Context(), c.GetAccount(), payload.OwnersRequired); err != nil {
+		sendTOTPError(deps, c, err, "failed to update two-factor authentication policy")
+		return
+	}
+
+	response.SendJSON(c, http.StatusNoContent, nil)
+}
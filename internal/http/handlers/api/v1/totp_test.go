@@ -0,0 +1,160 @@
+package api_v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/shiori/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// loginChallenge logs in with the password and returns the two-factor challenge.
+func loginChallenge(t *testing.T, deps model.Dependencies, username string) (string, map[string]any) {
+	t.Helper()
+
+	body := fmt.Sprintf(`{"username": %q, "password": "test"}`, username)
+	w := testutil.PerformRequest(deps, HandleLogin, http.MethodPost, "/api/v1/auth/login", testutil.WithBody(body))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	message := map[string]any{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &message))
+	require.Nil(t, message["token"])
+	require.Equal(t, true, message["totp_required"])
+
+	return message["challenge"].(string), message
+}
+
+func TestHandleLoginTOTP(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("login with a code", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		account, err := deps.Domains().Accounts().CreateAccount(ctx, model.AccountDTO{Username: "user", Password: "test"})
+		require.NoError(t, err)
+		enrollment, err := deps.Domains().TOTP().Enroll(ctx, account)
+		require.NoError(t, err)
+		_, err = deps.Domains().TOTP().Enable(ctx, account.ID, testutil.TOTPCode(t, enrollment.Secret, time.Now().Add(-30*time.Second)))
+		require.NoError(t, err)
+
+		challenge, message := loginChallenge(t, deps, "user")
+		require.Nil(t, message["totp_setup_required"])
+
+		w := testutil.PerformRequest(deps, HandleLoginTOTP, http.MethodPost, "/api/v1/auth/login/totp",
+			testutil.WithBody(fmt.Sprintf(`{"challenge": %q, "code": "000000"}`, challenge)))
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = testutil.PerformRequest(deps, HandleLoginTOTP, http.MethodPost, "/api/v1/auth/login/totp",
+			testutil.WithBody(fmt.Sprintf(`{"challenge": %q, "code": %q}`, challenge, testutil.TOTPCode(t, enrollment.Secret, time.Now()))))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertMessageJSONKeyValue(t, "token", func(t *testing.T, value any) {
+			require.NotEmpty(t, value)
+		})
+	})
+
+	t.Run("invalid challenge", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		w := testutil.PerformRequest(deps, HandleLoginTOTP, http.MethodPost, "/api/v1/auth/login/totp",
+			testutil.WithBody(`{"challenge": "invalid", "code": "000000"}`))
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("owners set it up when it is required", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		_, err := deps.Domains().Accounts().CreateAccount(ctx, model.AccountDTO{Username: "owner", Password: "test", Owner: model.Ptr(true)})
+		require.NoError(t, err)
+		require.NoError(t, deps.Database().SetSetting(ctx, model.SettingOwnerTOTPRequired, "true"))
+
+		challenge, message := loginChallenge(t, deps, "owner")
+		require.Equal(t, true, message["totp_setup_required"])
+
+		w := testutil.PerformRequest(deps, HandleLoginTOTPSetup, http.MethodPost, "/api/v1/auth/login/totp/setup",
+			testutil.WithBody(fmt.Sprintf(`{"challenge": %q}`, challenge)))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		response := testutil.NewTestResponseFromRecorder(w)
+		secret := response.AssertMessageJSONContainsKey(t, "secret").(string)
+
+		w = testutil.PerformRequest(deps, HandleLoginTOTP, http.MethodPost, "/api/v1/auth/login/totp",
+			testutil.WithBody(fmt.Sprintf(`{"challenge": %q, "code": %q}`, challenge, testutil.TOTPCode(t, secret, time.Now()))))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		response = testutil.NewTestResponseFromRecorder(w)
+		response.AssertMessageJSONKeyValue(t, "token", func(t *testing.T, value any) {
+			require.NotEmpty(t, value)
+		})
+		response.AssertMessageJSONKeyValue(t, "recovery_codes", func(t *testing.T, value any) {
+			require.Len(t, value, 10)
+		})
+	})
+}
+
+func TestHandleTOTPSettings(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("requires authentication", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleGetTOTP, http.MethodGet, "/api/v1/auth/totp")
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("enable and disable", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		account, err := deps.Domains().Accounts().CreateAccount(ctx, model.AccountDTO{Username: "user", Password: "test"})
+		require.NoError(t, err)
+
+		w := testutil.PerformRequest(deps, HandleEnrollTOTP, http.MethodPost, "/api/v1/auth/totp", testutil.WithAccount(account))
+		require.Equal(t, http.StatusOK, w.Code)
+		response := testutil.NewTestResponseFromRecorder(w)
+		secret := response.AssertMessageJSONContainsKey(t, "secret").(string)
+
+		w = testutil.PerformRequest(deps, HandleEnableTOTP, http.MethodPost, "/api/v1/auth/totp/enable", testutil.WithAccount(account),
+			testutil.WithBody(fmt.Sprintf(`{"code": %q}`, testutil.TOTPCode(t, secret, time.Now()))))
+		require.Equal(t, http.StatusOK, w.Code)
+		response = testutil.NewTestResponseFromRecorder(w)
+		response.AssertMessageJSONKeyValue(t, "recovery_codes", func(t *testing.T, value any) {
+			require.Len(t, value, 10)
+		})
+
+		w = testutil.PerformRequest(deps, HandleGetTOTP, http.MethodGet, "/api/v1/auth/totp", testutil.WithAccount(account))
+		require.Equal(t, http.StatusOK, w.Code)
+		response = testutil.NewTestResponseFromRecorder(w)
+		response.AssertMessageJSONKeyValue(t, "enabled", func(t *testing.T, value any) {
+			require.Equal(t, true, value)
+		})
+
+		w = testutil.PerformRequest(deps, HandleDisableTOTP, http.MethodPost, "/api/v1/auth/totp/disable", testutil.WithAccount(account),
+			testutil.WithBody(`{"code": "000000"}`))
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = testutil.PerformRequest(deps, HandleDisableTOTP, http.MethodPost, "/api/v1/auth/totp/disable", testutil.WithAccount(account),
+			testutil.WithBody(fmt.Sprintf(`{"code": %q}`, testutil.TOTPCode(t, secret, time.Now().Add(30*time.Second)))))
+		require.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("only owners change the policy", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleUpdateTOTPPolicy, http.MethodPut, "/api/v1/auth/totp/policy", testutil.WithFakeUser(),
+			testutil.WithBody(`{"owners_required": true}`))
+		require.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("owners need it before requiring it", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleUpdateTOTPPolicy, http.MethodPut, "/api/v1/auth/totp/policy", testutil.WithFakeAdmin(),
+			testutil.WithBody(`{"owners_required": true}`))
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,262 @@
+package api_v1
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-shiori/shiori/internal/http/middleware"
+	"github.com/go-shiori/shiori/internal/http/response"
+	"github.com/go-shiori/shiori/internal/model"
+)
+
+type createWebhookPayload struct {
+	URL string `json:"url"`
+	// Events the webhook is notified of, every event if empty
+	Events []model.WebhookEvent `json:"events"`
+	// Instance webhooks are notified of the events of every account, only owners can create them
+	Instance bool `json:"instance"`
+}
+
+// webhookID returns the ID of the webhook in the path, sending the error response if it is invalid.
+func webhookID(c model.WebContext) (model.DBID, bool) {
+	id, err := strconv.Atoi(c.Request().PathValue("id"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid webhook ID")
+		return 0, false
+	}
+
+	return model.DBID(id), true
+}
+
+// @Summary					List webhooks
+// @Description				List the webhooks of the logged in account. Owners also get the instance webhooks.
+// @Tags						Webhooks
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Success					200	{array}		model.Webhook
+// @Failure					401	{object}	nil	"Authentication required"
+// @Failure					500	{object}	nil	"Internal server error"
+// @Router						/api/v1/webhooks [get]
+func HandleListWebhooks(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	webhooks, err := deps.Domains().Webhooks().ListWebhooks(c.Request().Context(), c.GetAccount())
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to list webhooks")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, webhooks)
+}
+
+// @Summary					Create a webhook
+// @Description				Create a webhook notified of the bookmark and tag events. The returned secret signs the deliveries and is not shown again.
+// @Tags						Webhooks
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Accept						json
+// @Produce					json
+// @Param						payload	body		createWebhookPayload	true	"Webhook data"
+// @Success					201		{object}	model.Webhook
+// @Failure					400		{object}	nil	"Invalid webhook data"
+// @Failure					401		{object}	nil	"Authentication required"
+// @Failure					403		{object}	nil	"Only owners can create instance webhooks"
+// @Failure					500		{object}	nil	"Internal server error"
+// @Router						/api/v1/webhooks [post]
+func HandleCreateWebhook(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	var payload createWebhookPayload
+	if err := json.NewDecoder(c.Request().Body).Decode(&payload); err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	account := c.GetAccount()
+	webhook := model.Webhook{
+		AccountID: account.ID,
+		URL:       payload.URL,
+		Events:    payload.Events,
+	}
+	if payload.Instance {
+		webhook.AccountID = 0
+	}
+
+	created, err := deps.Domains().Webhooks().CreateWebhook(c.Request().Context(), account, webhook)
+	if err, isValidationErr := err.(model.ValidationError); isValidationErr {
+		response.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, model.ErrUnauthorized) {
+		response.SendError(c, http.StatusForbidden, "Only owners can create instance webhooks")
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to create webhook")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusCreated, created)
+}
+
+// @Summary					Get a webhook
+// @Tags						Webhooks
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Param						id	path		int	true	"Webhook ID"
+// @Success					200	{object}	model.Webhook
+// @Failure					400	{object}	nil	"Invalid webhook ID"
+// @Failure					401	{object}	nil	"Authentication required"
+// @Failure					404	{object}	nil	"Webhook not found"
+// @Failure					500	{object}	nil	"Internal server error"
+// @Router						/api/v1/webhooks/{id} [get]
+func HandleGetWebhook(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	webhook, err := deps.Domains().Webhooks().GetWebhook(c.Request().Context(), c.GetAccount(), id)
+	if errors.Is(err, model.ErrNotFound) {
+		response.NotFound(c)
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to get webhook")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, webhook)
+}
+
+// @Summary					Delete a webhook
+// @Description				Delete a webhook and its delivery log. Pending deliveries are not sent.
+// @Tags						Webhooks
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Param						id	path		int	true	"Webhook ID"
+// @Success					204	{object}	nil
+// @Failure					400	{object}	nil	"Invalid webhook ID"
+// @Failure					401	{object}	nil	"Authentication required"
+// @Failure					404	{object}	nil	"Webhook not found"
+// @Failure					500	{object}	nil	"Internal server error"
+// @Router						/api/v1/webhooks/{id} [delete]
+func HandleDeleteWebhook(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	err := deps.Domains().Webhooks().DeleteWebhook(c.Request().Context(), c.GetAccount(), id)
+	if errors.Is(err, model.ErrNotFound) {
+		response.NotFound(c)
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to delete webhook")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusNoContent, nil)
+}
+
+// @Summary					List webhook deliveries
+// @Description				List the latest deliveries of a webhook, newest first, with the answer to their last attempt.
+// @Tags						Webhooks
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Param						id	path		int	true	"Webhook ID"
+// @Success					200	{array}		model.WebhookDelivery
+// @Failure					400	{object}	nil	"Invalid webhook ID"
+// @Failure					401	{object}	nil	"Authentication required"
+// @Failure					404	{object}	nil	"Webhook not found"
+// @Failure					500	{object}	nil	"Internal server error"
+// @Router						/api/v1/webhooks/{id}/deliveries [get]
+func HandleListWebhookDeliveries(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	deliveries, err := deps.Domains().Webhooks().ListDeliveries(c.Request().Context(), c.GetAccount(), id)
+	if errors.Is(err, model.ErrNotFound) {
+		response.NotFound(c)
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to list webhook deliveries")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusOK, deliveries)
+}
+
+// @Summary					Replay a webhook delivery
+// @Description				Queue a new delivery with the payload of a previous one.
+// @Tags						Webhooks
+// @securityDefinitions.apikey	ApiKeyAuth
+// @Produce					json
+// @Param						id			path		int	true	"Webhook ID"
+// @Param						delivery_id	path		int	true	"Delivery ID"
+// @Success					202			{object}	model.WebhookDelivery
+// @Failure					400			{object}	nil	"Invalid webhook or delivery ID"
+// @Failure					401			{object}	nil	"Authentication required"
+// @Failure					404			{object}	nil	"Webhook or delivery not found"
+// @Failure					500			{object}	nil	"Internal server error"
+// @Router						/api/v1/webhooks/{id}/deliveries/{delivery_id}/replay [post]
+func HandleReplayWebhookDelivery(deps model.Dependencies, c model.WebContext) {
+	if err := middleware.RequireLoggedInUser(deps, c); err != nil {
+		return
+	}
+
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	deliveryID, err := strconv.Atoi(c.Request().PathValue("delivery_id"))
+	if err != nil {
+		response.SendError(c, http.StatusBadRequest, "Invalid delivery ID")
+		return
+	}
+
+	delivery, err := deps.Domains().Webhooks().Redeliver(c.Request().Context(), c.GetAccount(), id, model.DBID(deliveryID))
+	if errors.Is(err, model.ErrNotFound) {
+		response.NotFound(c)
+		return
+	}
+
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to replay webhook delivery")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	response.SendJSON(c, http.StatusAccepted, delivery)
+}
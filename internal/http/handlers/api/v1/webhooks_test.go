@@ -0,0 +1,225 @@
+package api_v1
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/shiori/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleListWebhooks(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("requires authentication", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleListWebhooks, http.MethodGet, "/api/v1/webhooks")
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("instance webhooks are listed for owners", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+		for _, accountID := range []model.DBID{0, testutil.FakeAccountID, testutil.FakeAccountID + 1} {
+			_, err := deps.Database().CreateWebhook(ctx, model.Webhook{
+				AccountID: accountID,
+				URL:       "https://example.com/hook",
+				Secret:    "secret",
+			})
+			require.NoError(t, err)
+		}
+
+		w := testutil.PerformRequest(deps, HandleListWebhooks, http.MethodGet, "/api/v1/webhooks", testutil.WithFakeUser())
+		require.Equal(t, http.StatusOK, w.Code)
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertOk(t)
+		response.AssertMessageIsListLength(t, 1)
+
+		w = testutil.PerformRequest(deps, HandleListWebhooks, http.MethodGet, "/api/v1/webhooks", testutil.WithFakeAdmin())
+		require.Equal(t, http.StatusOK, w.Code)
+		response = testutil.NewTestResponseFromRecorder(w)
+		response.AssertOk(t)
+		response.AssertMessageIsListLength(t, 2)
+		require.NotContains(t, w.Body.String(), `"secret"`)
+	})
+}
+
+func TestHandleCreateWebhook(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("requires authentication", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleCreateWebhook, http.MethodPost, "/api/v1/webhooks")
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleCreateWebhook, http.MethodPost, "/api/v1/webhooks",
+			testutil.WithFakeUser(),
+			testutil.WithBody(`{`),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid event", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleCreateWebhook, http.MethodPost, "/api/v1/webhooks",
+			testutil.WithFakeUser(),
+			testutil.WithBody(`{"url": "https://example.com/hook", "events": ["bookmark.read"]}`),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("instance webhook requires owner", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleCreateWebhook, http.MethodPost, "/api/v1/webhooks",
+			testutil.WithFakeUser(),
+			testutil.WithBody(`{"url": "https://example.com/hook", "instance": true}`),
+		)
+		require.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("create webhook", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleCreateWebhook, http.MethodPost, "/api/v1/webhooks",
+			testutil.WithFakeUser(),
+			testutil.WithBody(`{"url": "https://example.com/hook", "events": ["bookmark.created"]}`),
+		)
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertOk(t)
+		response.AssertMessageJSONKeyValue(t, "account_id", func(t *testing.T, value any) {
+			require.EqualValues(t, testutil.FakeAccountID, value)
+		})
+		response.AssertMessageJSONKeyValue(t, "secret", func(t *testing.T, value any) {
+			require.True(t, strings.HasPrefix(value.(string), model.WebhookSecretPrefix))
+		})
+	})
+}
+
+func TestHandleGetWebhook(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("invalid id", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		w := testutil.PerformRequest(deps, HandleGetWebhook, http.MethodGet, "/api/v1/webhooks/abc",
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", "abc"),
+		)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("webhook of another account", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		webhook, err := deps.Database().CreateWebhook(ctx, model.Webhook{
+			AccountID: testutil.FakeAccountID + 1,
+			URL:       "https://example.com/hook",
+			Secret:    "secret",
+		})
+		require.NoError(t, err)
+
+		id := strconv.Itoa(int(webhook.ID))
+		w := testutil.PerformRequest(deps, HandleGetWebhook, http.MethodGet, "/api/v1/webhooks/"+id,
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+		)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestHandleDeleteWebhook(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+	webhook, err := deps.Database().CreateWebhook(ctx, model.Webhook{
+		AccountID: testutil.FakeAccountID,
+		URL:       "https://example.com/hook",
+		Secret:    "secret",
+	})
+	require.NoError(t, err)
+
+	id := strconv.Itoa(int(webhook.ID))
+	w := testutil.PerformRequest(deps, HandleDeleteWebhook, http.MethodDelete, "/api/v1/webhooks/"+id,
+		testutil.WithFakeUser(),
+		testutil.WithRequestPathValue("id", id),
+	)
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	_, exists, err := deps.Database().GetWebhook(ctx, webhook.ID)
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+func TestHandleWebhookDeliveries(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+	webhook, err := deps.Database().CreateWebhook(ctx, model.Webhook{
+		AccountID: testutil.FakeAccountID,
+		URL:       "https://example.com/hook",
+		Secret:    "secret",
+	})
+	require.NoError(t, err)
+
+	delivery, err := deps.Database().CreateWebhookDelivery(ctx, model.WebhookDelivery{
+		WebhookID: webhook.ID,
+		Event:     model.WebhookEventBookmarkCreated,
+		Payload:   `{"event":"bookmark.created"}`,
+	})
+	require.NoError(t, err)
+
+	id := strconv.Itoa(int(webhook.ID))
+	deliveryID := strconv.Itoa(int(delivery.ID))
+
+	t.Run("list deliveries", func(t *testing.T) {
+		w := testutil.PerformRequest(deps, HandleListWebhookDeliveries, http.MethodGet, "/api/v1/webhooks/"+id+"/deliveries",
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+		)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertOk(t)
+		response.AssertMessageIsListLength(t, 1)
+	})
+
+	t.Run("replay unknown delivery", func(t *testing.T) {
+		w := testutil.PerformRequest(deps, HandleReplayWebhookDelivery, http.MethodPost, "/api/v1/webhooks/"+id+"/deliveries/999/replay",
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+			testutil.WithRequestPathValue("delivery_id", "999"),
+		)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("replay delivery", func(t *testing.T) {
+		w := testutil.PerformRequest(deps, HandleReplayWebhookDelivery, http.MethodPost, "/api/v1/webhooks/"+id+"/deliveries/"+deliveryID+"/replay",
+			testutil.WithFakeUser(),
+			testutil.WithRequestPathValue("id", id),
+			testutil.WithRequestPathValue("delivery_id", deliveryID),
+		)
+		require.Equal(t, http.StatusAccepted, w.Code)
+
+		response := testutil.NewTestResponseFromRecorder(w)
+		response.AssertOk(t)
+		response.AssertMessageJSONKeyValue(t, "payload", func(t *testing.T, value any) {
+			require.Equal(t, delivery.Payload, value)
+		})
+
+		deliveries, err := deps.Database().ListWebhookDeliveries(ctx, webhook.ID, 0)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 2)
+	})
+}
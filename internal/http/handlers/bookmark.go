@@ -1,13 +1,17 @@
 package handlers
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"html/template"
 	"net/http"
 	"strconv"
 
+	"github.com/go-shiori/shiori/internal/core"
 	"github.com/go-shiori/shiori/internal/http/response"
 	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/warc"
 	"github.com/gofrs/uuid/v5"
 )
 
@@ -23,7 +27,7 @@ func getBookmark(deps model.Dependencies, c model.WebContext) (*model.BookmarkDT
 	}
 
 	// Get bookmark from database
-	bookmark, err := deps.Domains().Bookmarks().GetBookmark(c.Request().Context(), model.DBID(bookmarkID))
+	bookmark, err := deps.Domains().Bookmarks().GetBookmark(c.Request().Context(), model.DBID(bookmarkID), 0)
 	if err != nil {
 		return nil, response.SendError(c, http.StatusNotFound, "Bookmark not found")
 	}
@@ -34,6 +38,14 @@ func getBookmark(deps model.Dependencies, c model.WebContext) (*model.BookmarkDT
 		return nil, nil
 	}
 
+	// Private bookmarks are only visible to the account that saved them and to owners
+	if bookmark.Public != 1 {
+		account := c.GetAccount()
+		if bookmark.AccountID != account.ID && !account.IsOwner() {
+			return nil, response.SendError(c, http.StatusNotFound, "Bookmark not found")
+		}
+	}
+
 	return bookmark, nil
 }
 
@@ -44,11 +56,32 @@ func HandleBookmarkContent(deps model.Dependencies, c model.WebContext) {
 		return
 	}
 
+	// Accounts highlight the bookmarks they can reach through the API, and see their highlights
+	content := bookmark.HTML
+	highlightsPath := ""
+	if c.UserIsLogged() {
+		account := c.GetAccount()
+		if bookmark.AccountID == account.ID || account.IsOwner() {
+			highlightsPath = fmt.Sprintf("api/v1/bookmarks/%d/highlights", bookmark.ID)
+			if bookmark.AccountID != account.ID {
+				highlightsPath += "?all_accounts=true"
+			}
+
+			highlights, err := deps.Domains().Highlights().ListHighlights(c.Request().Context(), account, bookmark)
+			if err != nil {
+				deps.Logger().WithError(err).Error("failed to list highlights")
+			} else {
+				content = core.RenderHighlights(content, highlights)
+			}
+		}
+	}
+
 	data := map[string]any{
-		"RootPath": deps.Config().Http.RootPath,
-		"Version":  model.BuildVersion,
-		"Book":     bookmark,
-		"HTML":     template.HTML(bookmark.HTML),
+		"RootPath":       deps.Config().Http.RootPath,
+		"Version":        model.BuildVersion,
+		"Book":           bookmark,
+		"HTML":           template.HTML(content),
+		"HighlightsPath": highlightsPath,
 	}
 
 	if err := response.SendTemplate(c, "content.html", data); err != nil {
@@ -56,6 +89,38 @@ func HandleBookmarkContent(deps model.Dependencies, c model.WebContext) {
 	}
 }
 
+// getArchiveSnapshot retrieves the snapshot requested in the path, or nil when the current
+// archive is requested
+func getArchiveSnapshot(deps model.Dependencies, c model.WebContext, bookmark *model.BookmarkDTO) (*model.ArchiveSnapshot, error) {
+	snapshotParam := c.Request().PathValue("snapshot")
+	if snapshotParam == "" {
+		if !deps.Domains().Bookmarks().HasArchive(bookmark) {
+			response.NotFound(c)
+			return nil, model.ErrNotFound
+		}
+		return nil, nil
+	}
+
+	snapshotID, err := strconv.Atoi(snapshotParam)
+	if err != nil {
+		response.NotFound(c)
+		return nil, model.ErrNotFound
+	}
+
+	snapshot, err := deps.Domains().Archiver().GetSnapshot(c.Request().Context(), bookmark, model.DBID(snapshotID))
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			response.NotFound(c)
+		} else {
+			deps.Logger().WithError(err).Error("failed to get archive snapshot")
+			response.SendInternalServerError(c)
+		}
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
 // HandleBookmarkArchive serves the bookmark archive page
 func HandleBookmarkArchive(deps model.Dependencies, c model.WebContext) {
 	bookmark, err := getBookmark(deps, c)
@@ -63,15 +128,21 @@ func HandleBookmarkArchive(deps model.Dependencies, c model.WebContext) {
 		return
 	}
 
-	if !deps.Domains().Bookmarks().HasArchive(bookmark) {
-		response.NotFound(c)
+	snapshot, err := getArchiveSnapshot(deps, c, bookmark)
+	if err != nil {
 		return
 	}
 
+	archiveFilePath := fmt.Sprintf("bookmark/%d/archive/file/", bookmark.ID)
+	if snapshot != nil {
+		archiveFilePath = fmt.Sprintf("bookmark/%d/archive/snapshot/%d/file/", bookmark.ID, snapshot.ID)
+	}
+
 	data := map[string]any{
-		"RootPath": deps.Config().Http.RootPath,
-		"Version":  model.BuildVersion,
-		"Book":     bookmark,
+		"RootPath":        deps.Config().Http.RootPath,
+		"Version":         model.BuildVersion,
+		"Book":            bookmark,
+		"ArchiveFilePath": archiveFilePath,
 	}
 
 	if err := response.SendTemplate(c, "archive.html", data); err != nil {
@@ -79,21 +150,26 @@ func HandleBookmarkArchive(deps model.Dependencies, c model.WebContext) {
 	}
 }
 
-// HandleBookmarkArchiveFile serves files from the bookmark archive
+// HandleBookmarkArchiveFile serves files from the bookmark archive or one of its snapshots
 func HandleBookmarkArchiveFile(deps model.Dependencies, c model.WebContext) {
 	bookmark, err := getBookmark(deps, c)
 	if err != nil || bookmark == nil {
 		return
 	}
 
-	if !deps.Domains().Bookmarks().HasArchive(bookmark) {
-		response.NotFound(c)
+	snapshot, err := getArchiveSnapshot(deps, c, bookmark)
+	if err != nil {
 		return
 	}
 
 	resourcePath := c.Request().PathValue("path")
 
-	archive, err := deps.Domains().Archiver().GetBookmarkArchive(bookmark)
+	var archive *warc.Archive
+	if snapshot != nil {
+		archive, err = deps.Domains().Archiver().GetSnapshotArchive(snapshot)
+	} else {
+		archive, err = deps.Domains().Archiver().GetBookmarkArchive(bookmark)
+	}
 	if err != nil {
 		deps.Logger().WithError(err).Error("error opening archive")
 		response.SendInternalServerError(c)
@@ -115,7 +191,11 @@ func HandleBookmarkArchiveFile(deps model.Dependencies, c model.WebContext) {
 
 	// Generate weak ETAG
 	shioriUUID := uuid.NewV5(uuid.NamespaceURL, model.ShioriURLNamespace)
-	etag := fmt.Sprintf("W/%s", uuid.NewV5(shioriUUID, fmt.Sprintf("%x-%x-%x", bookmark.ID, resourcePath, len(content))))
+	etagSeed := fmt.Sprintf("%x-%x-%x", bookmark.ID, resourcePath, len(content))
+	if snapshot != nil {
+		etagSeed = fmt.Sprintf("%x-%x-%x-%x", bookmark.ID, snapshot.ID, resourcePath, len(content))
+	}
+	etag := fmt.Sprintf("W/%s", uuid.NewV5(shioriUUID, etagSeed))
 
 	c.ResponseWriter().Header().Set("Etag", etag)
 	c.ResponseWriter().Header().Set("Cache-Control", "max-age=31536000")
@@ -169,6 +249,33 @@ func HandleBookmarkEbook(deps model.Dependencies, c model.WebContext) {
 		return
 	}
 
+	// The stored ebook is shared with everyone who can read the bookmark, accounts with
+	// highlights on it get a copy with their highlights and notes instead
+	var highlights []model.Highlight
+	if c.UserIsLogged() {
+		highlights, err = deps.Domains().Highlights().ListHighlights(c.Request().Context(), c.GetAccount(), bookmark)
+		if err != nil {
+			deps.Logger().WithError(err).Error("failed to list highlights")
+			response.SendInternalServerError(c)
+			return
+		}
+	}
+
 	c.ResponseWriter().Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.epub"`, bookmark.Title))
-	response.SendFile(c, deps.Domains().Storage(), ebookPath, nil)
+	if len(highlights) == 0 {
+		response.SendFile(c, deps.Domains().Storage(), ebookPath, nil)
+		return
+	}
+
+	ebook := bytes.Buffer{}
+	if err := core.WriteHighlightsEbook(deps, *bookmark, highlights, &ebook); err != nil {
+		deps.Logger().WithError(err).Error("failed to create ebook with highlights")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	c.ResponseWriter().Header().Set("Content-Type", "application/epub+zip")
+	c.ResponseWriter().Header().Set("Cache-Control", "no-store")
+	c.ResponseWriter().WriteHeader(http.StatusOK)
+	c.ResponseWriter().Write(ebook.Bytes())
 }
@@ -1,15 +1,20 @@
 package handlers
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/go-shiori/shiori/internal/http/templates"
 	"github.com/go-shiori/shiori/internal/model"
 	"github.com/go-shiori/shiori/internal/testutil"
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/require"
 )
 
@@ -23,9 +28,12 @@ func TestGetBookmark(t *testing.T) {
 	// Create a private and a public bookmark to use in tests
 	publicBookmark := testutil.GetValidBookmark()
 	publicBookmark.Public = 1
+	otherAccountBookmark := testutil.GetValidBookmark()
+	otherAccountBookmark.AccountID = testutil.FakeAccountID + 1
 	bookmarks, err := deps.Database().SaveBookmarks(context.TODO(), true, []model.BookmarkDTO{
 		*testutil.GetValidBookmark(),
 		*publicBookmark,
+		*otherAccountBookmark,
 	}...)
 	require.NoError(t, err)
 
@@ -69,6 +77,22 @@ func TestGetBookmark(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, bookmark)
 	})
+	t.Run("private bookmark of another account", func(t *testing.T) {
+		c, w := testutil.NewTestWebContextWithMethod("GET", "/bookmark/"+strconv.Itoa(bookmarks[2].ID)+"/content")
+		testutil.SetFakeUser(c)
+		testutil.SetRequestPathValue(c, "id", strconv.Itoa(bookmarks[2].ID))
+		bookmark, _ := getBookmark(deps, c)
+		require.Nil(t, bookmark)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("private bookmark of another account viewed by an owner", func(t *testing.T) {
+		c, _ := testutil.NewTestWebContextWithMethod("GET", "/bookmark/"+strconv.Itoa(bookmarks[2].ID)+"/content")
+		testutil.SetFakeAdmin(c)
+		testutil.SetRequestPathValue(c, "id", strconv.Itoa(bookmarks[2].ID))
+		bookmark, _ := getBookmark(deps, c)
+		require.NotNil(t, bookmark)
+	})
 }
 
 func TestBookmarkContentHandler(t *testing.T) {
@@ -99,6 +123,24 @@ func TestBookmarkContentHandler(t *testing.T) {
 		require.Equal(t, http.StatusOK, w.Code)
 		require.Contains(t, w.Body.String(), bookmark.HTML)
 	})
+
+	t.Run("highlights of the account are rendered", func(t *testing.T) {
+		highlight, err := deps.Database().CreateHighlight(context.TODO(), model.Highlight{
+			BookmarkID: bookmark.ID,
+			AccountID:  testutil.FakeAccountID,
+			Selector:   model.HighlightSelector{Quote: model.TextQuoteSelector{Exact: "HTML content"}},
+			Note:       "heading",
+		})
+		require.NoError(t, err)
+
+		c, w := testutil.NewTestWebContextWithMethod("GET", "/bookmark/"+strconv.Itoa(bookmark.ID)+"/content")
+		testutil.SetFakeUser(c)
+		testutil.SetRequestPathValue(c, "id", strconv.Itoa(bookmark.ID))
+		HandleBookmarkContent(deps, c)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `<h1>Bookmark <mark class="highlight" data-highlight-id="`+
+			strconv.Itoa(int(highlight.ID))+`" title="heading">HTML content</mark></h1>`)
+	})
 }
 
 func TestBookmarkFileHandlers(t *testing.T) {
@@ -160,6 +202,29 @@ func TestBookmarkFileHandlers(t *testing.T) {
 		require.Equal(t, http.StatusOK, w.Code)
 	})
 
+	t.Run("get bookmark archive snapshot file", func(t *testing.T) {
+		snapshots, err := deps.Domains().Archiver().ListSnapshots(context.TODO(), bookmark)
+		require.NoError(t, err)
+		require.NotEmpty(t, snapshots)
+		snapshotID := strconv.Itoa(int(snapshots[0].ID))
+
+		c, w := testutil.NewTestWebContextWithMethod("GET", "/bookmark/"+strconv.Itoa(bookmark.ID)+"/archive/snapshot/"+snapshotID+"/file/")
+		testutil.SetFakeUser(c)
+		testutil.SetRequestPathValue(c, "id", strconv.Itoa(bookmark.ID))
+		testutil.SetRequestPathValue(c, "snapshot", snapshotID)
+		HandleBookmarkArchiveFile(deps, c)
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("unknown bookmark archive snapshot", func(t *testing.T) {
+		c, w := testutil.NewTestWebContextWithMethod("GET", "/bookmark/"+strconv.Itoa(bookmark.ID)+"/archive/snapshot/999")
+		testutil.SetFakeUser(c)
+		testutil.SetRequestPathValue(c, "id", strconv.Itoa(bookmark.ID))
+		testutil.SetRequestPathValue(c, "snapshot", "999")
+		HandleBookmarkArchive(deps, c)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
 	t.Run("bookmark with ebook", func(t *testing.T) {
 		c, w := testutil.NewTestWebContextWithMethod("GET", "/bookmark/"+strconv.Itoa(bookmark.ID)+"/ebook")
 		testutil.SetFakeUser(c)
@@ -180,3 +245,66 @@ func TestBookmarkFileHandlers(t *testing.T) {
 		require.Equal(t, http.StatusNotFound, w.Code)
 	})
 }
+
+func TestHandleBookmarkEbookHighlights(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+	_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+	bookmark := testutil.GetValidBookmark()
+	bookmark.Public = 1
+	bookmark.HTML = "<p>A gopher digs.</p>"
+	bookmarks, err := deps.Database().SaveBookmarks(ctx, true, *bookmark)
+	require.NoError(t, err)
+	bookmark = &bookmarks[0]
+	require.NoError(t, deps.Domains().Storage().WriteData(model.GetEbookPath(bookmark), []byte("shared ebook")))
+
+	_, err = deps.Database().CreateHighlight(ctx, model.Highlight{
+		BookmarkID: bookmark.ID,
+		AccountID:  testutil.FakeAccountID,
+		Selector:   model.HighlightSelector{Quote: model.TextQuoteSelector{Exact: "gopher"}},
+		Note:       "private note",
+	})
+	require.NoError(t, err)
+
+	id := strconv.Itoa(bookmark.ID)
+
+	t.Run("anonymous requests get the shared ebook", func(t *testing.T) {
+		c, w := testutil.NewTestWebContextWithMethod("GET", "/bookmark/"+id+"/ebook")
+		testutil.SetRequestPathValue(c, "id", id)
+		HandleBookmarkEbook(deps, c)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "shared ebook", w.Body.String())
+	})
+
+	t.Run("accounts get their highlights", func(t *testing.T) {
+		c, w := testutil.NewTestWebContextWithMethod("GET", "/bookmark/"+id+"/ebook")
+		testutil.SetFakeUser(c)
+		testutil.SetRequestPathValue(c, "id", id)
+		HandleBookmarkEbook(deps, c)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "application/epub+zip", w.Header().Get("Content-Type"))
+
+		reader, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+		require.NoError(t, err)
+
+		content := strings.Builder{}
+		for _, file := range reader.File {
+			if strings.HasSuffix(file.Name, ".xhtml") {
+				rc, err := file.Open()
+				require.NoError(t, err)
+				data, err := io.ReadAll(rc)
+				rc.Close()
+				require.NoError(t, err)
+				content.Write(data)
+			}
+		}
+		require.Contains(t, content.String(), `<mark class="highlight"`)
+		require.Contains(t, content.String(), "<p>private note</p>")
+
+		// The shared ebook is left without them
+		data, err := afero.ReadFile(deps.Domains().Storage().FS(), model.GetEbookPath(bookmark))
+		require.NoError(t, err)
+		require.Equal(t, "shared ebook", string(data))
+	})
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/go-shiori/shiori/internal/core"
+	"github.com/go-shiori/shiori/internal/http/response"
+	"github.com/go-shiori/shiori/internal/model"
+)
+
+// feedSize is the number of bookmarks in a feed, the latest added ones
+const feedSize = 50
+
+// feedBaseURL returns the absolute URL of the Shiori instance the request was sent to
+func feedBaseURL(deps model.Dependencies, r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+
+	return scheme + "://" + r.Host + deps.Config().Http.RootPath
+}
+
+// HandleFeed serves an Atom or RSS feed of the latest public bookmarks, or of every bookmark
+// of an account with its feed token, optionally filtered by tag.
+func HandleFeed(deps model.Dependencies, c model.WebContext) {
+	format := model.FeedFormat(c.Request().PathValue("format"))
+	if err := format.IsValid(); err != nil {
+		response.NotFound(c)
+		return
+	}
+
+	ctx := c.Request().Context()
+	query := c.Request().URL.Query()
+
+	opts := model.ListBookmarksOptions{
+		PublicOnly:  true,
+		WithContent: query.Get("content") == "full",
+		OrderMethod: model.ByLastAdded,
+		Limit:       feedSize,
+	}
+	title := "Shiori public bookmarks"
+
+	if token := query.Get("token"); token != "" {
+		account, err := deps.Domains().Auth().CheckFeedToken(ctx, token)
+		if err != nil {
+			response.SendError(c, http.StatusUnauthorized, "Invalid feed token")
+			return
+		}
+
+		opts.PublicOnly = false
+		opts.AccountID = account.ID
+		title = "Shiori bookmarks of " + account.Username
+	}
+
+	if tag := strings.TrimSpace(query.Get("tag")); tag != "" {
+		opts.Tags = []string{tag}
+		title += " tagged " + tag
+	}
+
+	bookmarks, err := deps.Domains().Bookmarks().ListBookmarks(ctx, opts)
+	if err != nil {
+		deps.Logger().WithError(err).Error("failed to get feed bookmarks")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	baseURL := feedBaseURL(deps, c.Request())
+	selfURL := baseURL + strings.TrimPrefix(c.Request().URL.Path, "/")
+	if c.Request().URL.RawQuery != "" {
+		selfURL += "?" + c.Request().URL.RawQuery
+	}
+
+	body := bytes.Buffer{}
+	if err := core.WriteFeed(&body, format, model.Feed{
+		Title:       title,
+		SelfURL:     selfURL,
+		BaseURL:     baseURL,
+		FullContent: opts.WithContent,
+	}, bookmarks); err != nil {
+		deps.Logger().WithError(err).Error("failed to write feed")
+		response.SendInternalServerError(c)
+		return
+	}
+
+	// The feed is rendered before answering so conditional requests can be checked against it
+	hash := sha256.Sum256(body.Bytes())
+	header := c.ResponseWriter().Header()
+	header.Set("Content-Type", core.FeedContentType(format))
+	header.Set("ETag", `"`+hex.EncodeToString(hash[:16])+`"`)
+	header.Set("Cache-Control", "no-cache")
+	if !opts.PublicOnly {
+		header.Set("Cache-Control", "private, no-cache")
+	}
+
+	http.ServeContent(c.ResponseWriter(), c.Request(), "", core.FeedUpdated(bookmarks), bytes.NewReader(body.Bytes()))
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/shiori/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleFeed(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.TODO()
+	_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+	account, err := deps.Domains().Accounts().CreateAccount(ctx, model.AccountDTO{Username: "reader", Password: "reader"})
+	require.NoError(t, err)
+
+	_, err = deps.Database().SaveBookmarks(ctx, true,
+		model.BookmarkDTO{AccountID: account.ID, URL: "https://github.com/go-shiori/shiori", Title: "public go", Public: 1,
+			Tags: []model.TagDTO{{Tag: model.Tag{Name: "go"}}}},
+		model.BookmarkDTO{AccountID: account.ID, URL: "https://example.com", Title: "public other", Public: 1},
+		model.BookmarkDTO{AccountID: account.ID, URL: "https://go.dev", Title: "private go",
+			Tags: []model.TagDTO{{Tag: model.Tag{Name: "go"}}}},
+	)
+	require.NoError(t, err)
+
+	feed := func(format string, options ...testutil.Option) (int, string, http.Header) {
+		options = append(options, testutil.WithRequestPathValue("format", format))
+		w := testutil.PerformRequest(deps, HandleFeed, http.MethodGet, "/feeds/"+format, options...)
+		return w.Code, w.Body.String(), w.Header()
+	}
+
+	t.Run("unknown format", func(t *testing.T) {
+		code, _, _ := feed("json")
+		require.Equal(t, http.StatusNotFound, code)
+	})
+
+	t.Run("public bookmarks", func(t *testing.T) {
+		code, body, header := feed("atom")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "application/atom+xml; charset=utf-8", header.Get("Content-Type"))
+		require.NotEmpty(t, header.Get("ETag"))
+		require.NotEmpty(t, header.Get("Last-Modified"))
+		require.Contains(t, body, "public go")
+		require.Contains(t, body, "public other")
+		require.NotContains(t, body, "private go")
+	})
+
+	t.Run("public bookmarks of a tag", func(t *testing.T) {
+		code, body, header := feed("rss", testutil.WithRequestQueryParam("tag", "go"))
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "application/rss+xml; charset=utf-8", header.Get("Content-Type"))
+		require.Contains(t, body, "public go")
+		require.NotContains(t, body, "public other")
+		require.NotContains(t, body, "private go")
+	})
+
+	t.Run("private feed", func(t *testing.T) {
+		token, err := deps.Domains().Auth().CreateFeedToken(ctx, account.ID)
+		require.NoError(t, err)
+
+		code, body, header := feed("atom", testutil.WithRequestQueryParam("token", token), testutil.WithRequestQueryParam("tag", "go"))
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "private, no-cache", header.Get("Cache-Control"))
+		require.Contains(t, body, "public go")
+		require.Contains(t, body, "private go")
+		require.NotContains(t, body, "public other")
+
+		code, _, _ = feed("atom", testutil.WithRequestQueryParam("token", model.FeedTokenPrefix+"invalid"))
+		require.Equal(t, http.StatusUnauthorized, code)
+	})
+
+	t.Run("conditional requests", func(t *testing.T) {
+		_, _, header := feed("atom")
+
+		code, body, _ := feed("atom", testutil.WithHeader("If-None-Match", header.Get("ETag")))
+		require.Equal(t, http.StatusNotModified, code)
+		require.Empty(t, body)
+
+		code, _, _ = feed("atom", testutil.WithHeader("If-Modified-Since", header.Get("Last-Modified")))
+		require.Equal(t, http.StatusNotModified, code)
+
+		code, _, _ = feed("atom", testutil.WithHeader("If-None-Match", `"outdated"`))
+		require.Equal(t, http.StatusOK, code)
+	})
+}
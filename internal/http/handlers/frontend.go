@@ -37,6 +37,7 @@ func HandleFrontend(deps model.Dependencies, c model.WebContext) {
 	data := map[string]any{
 		"RootPath": deps.Config().Http.RootPath,
 		"Version":  model.BuildVersion,
+		"OIDC":     deps.Config().Http.OIDCEnabled,
 	}
 
 	if err := response.SendTemplate(c, "index.html", data); err != nil {
@@ -15,8 +15,20 @@ import (
 
 // SetFakeAuthorizationHeader sets a fake authorization header for the request in order to have
 // a valid session. If we don't set this the `validateSession` function will return an error.
+// The account is created in the database if needed, since sessions read it from there.
 func SetFakeAuthorizationHeader(t *testing.T, deps model.Dependencies, c model.WebContext) {
-	token, err := deps.Domains().Auth().CreateTokenForAccount(c.GetAccount(), time.Now().Add(time.Hour))
+	account := c.GetAccount()
+	if _, exists, _ := deps.Database().GetAccount(context.TODO(), account.ID); !exists {
+		created, err := deps.Database().CreateAccount(context.TODO(), model.Account{
+			Username: account.Username,
+			Password: "fake",
+			Owner:    account.IsOwner(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, account.ID, created.ID)
+	}
+
+	token, err := deps.Domains().Auth().CreateTokenForAccount(context.TODO(), account, time.Now().Add(time.Hour), "")
 	require.NoError(t, err)
 	c.Request().Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
 }
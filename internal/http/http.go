@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/go-shiori/shiori/internal/http/webcontext"
@@ -13,8 +14,14 @@ func ToHTTPHandler(deps model.Dependencies, h model.HttpHandler, middlewares ...
 		c := webcontext.NewWebContext(w, r)
 
 		// Execute OnRequest middlewares
-		for _, m := range middlewares {
+		for i, m := range middlewares {
 			if err := m.OnRequest(deps, c); err != nil {
+				// The response was sent by the middleware, finish it with the ones run before
+				if errors.Is(err, model.ErrRequestHandled) {
+					runOnResponse(deps, c, middlewares[:i])
+					return
+				}
+
 				// Handle middleware error
 				deps.Logger().WithError(err).Error("middleware error in request")
 				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
@@ -25,13 +32,17 @@ func ToHTTPHandler(deps model.Dependencies, h model.HttpHandler, middlewares ...
 		// Execute handler
 		h(deps, c)
 
-		// Execute OnResponse middlewares in reverse order
-		for i := len(middlewares) - 1; i >= 0; i-- {
-			m := middlewares[i]
-			if err := m.OnResponse(deps, c); err != nil {
-				deps.Logger().WithError(err).Error("middleware error in response")
-				return
-			}
+		runOnResponse(deps, c, middlewares)
+	}
+}
+
+// runOnResponse executes the OnResponse middlewares in reverse order
+func runOnResponse(deps model.Dependencies, c model.WebContext, middlewares []model.HttpMiddleware) {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		m := middlewares[i]
+		if err := m.OnResponse(deps, c); err != nil {
+			deps.Logger().WithError(err).Error("middleware error in response")
+			return
 		}
 	}
 }
@@ -16,6 +16,7 @@ type testMiddleware struct {
 	onRequestCalled  bool
 	onResponseCalled bool
 	returnError      bool
+	handleRequest    bool
 }
 
 func (m *testMiddleware) OnRequest(deps model.Dependencies, c model.WebContext) error {
@@ -23,6 +24,10 @@ func (m *testMiddleware) OnRequest(deps model.Dependencies, c model.WebContext)
 	if m.returnError {
 		return errors.New("test error")
 	}
+	if m.handleRequest {
+		c.ResponseWriter().WriteHeader(http.StatusTooManyRequests)
+		return model.ErrRequestHandled
+	}
 	return nil
 }
 
@@ -95,4 +100,25 @@ func TestToHTTPHandler(t *testing.T) {
 		require.False(t, middleware2.onResponseCalled)
 		require.Equal(t, http.StatusInternalServerError, w.Code)
 	})
+
+	t.Run("skips the handler when a middleware sent the response", func(t *testing.T) {
+		middleware1 := &testMiddleware{}
+		middleware2 := &testMiddleware{handleRequest: true}
+		middleware3 := &testMiddleware{}
+
+		handlerCalled := false
+		handler := func(deps model.Dependencies, c model.WebContext) {
+			handlerCalled = true
+		}
+
+		c, w := testutil.NewTestWebContext()
+		httpHandler := ToHTTPHandler(deps, handler, middleware1, middleware2, middleware3)
+		httpHandler.ServeHTTP(w, c.Request())
+
+		require.False(t, handlerCalled)
+		require.True(t, middleware1.onResponseCalled)
+		require.False(t, middleware2.onResponseCalled)
+		require.False(t, middleware3.onRequestCalled)
+		require.Equal(t, http.StatusTooManyRequests, w.Code)
+	})
 }
@@ -34,7 +34,11 @@ func (m *AuthMiddleware) OnRequest(deps model.Dependencies, c model.WebContext)
 		return nil
 	}
 
-	account, err := deps.Domains().Auth().CheckToken(c.Request().Context(), token)
+	if strings.HasPrefix(token, model.APITokenPrefix) {
+		return m.authenticateAPIToken(deps, c, token)
+	}
+
+	account, session, err := deps.Domains().Auth().CheckSession(c.Request().Context(), token)
 	if err != nil {
 		// If we fail to check token, remove the token cookie and redirect to login
 		deps.Logger().WithError(err).WithField("request_id", c.GetRequestID()).Error("Failed to check token")
@@ -46,6 +50,33 @@ func (m *AuthMiddleware) OnRequest(deps model.Dependencies, c model.WebContext)
 		return nil
 	}
 
+	c.SetAccount(account)
+	c.SetSessionID(session.ID)
+	return nil
+}
+
+// authenticateAPIToken sets the account of a personal API token, limited by its scopes: read
+// only tokens are ignored outside of safe methods and only admin tokens keep the owner flag.
+func (m *AuthMiddleware) authenticateAPIToken(deps model.Dependencies, c model.WebContext, token string) error {
+	account, apiToken, err := deps.Domains().Auth().CheckAPIToken(c.Request().Context(), token)
+	if err != nil {
+		deps.Logger().WithError(err).WithField("request_id", c.GetRequestID()).Error("Failed to check api token")
+		return nil
+	}
+
+	switch c.Request().Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		if !apiToken.Scopes.Allows(model.APITokenScopeWrite) {
+			deps.Logger().WithField("request_id", c.GetRequestID()).Warn("Read only api token used for a write request")
+			return nil
+		}
+	}
+
+	if !apiToken.Scopes.Allows(model.APITokenScopeAdmin) {
+		account.Owner = model.Ptr(false)
+	}
+
 	c.SetAccount(account)
 	return nil
 }
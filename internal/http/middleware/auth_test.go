@@ -18,6 +18,12 @@ func TestAuthMiddleware(t *testing.T) {
 	logger := logrus.New()
 	_, deps := testutil.GetTestConfigurationAndDependencies(t, context.TODO(), logger)
 
+	account, err := deps.Domains().Accounts().CreateAccount(context.TODO(), model.AccountDTO{
+		Username: "shiori",
+		Password: "shiori",
+	})
+	require.NoError(t, err)
+
 	t.Run("test no authorization method", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		r := httptest.NewRequest(http.MethodGet, "/", nil)
@@ -30,8 +36,7 @@ func TestAuthMiddleware(t *testing.T) {
 	})
 
 	t.Run("test authorization header", func(t *testing.T) {
-		account := testutil.GetValidAccount().ToDTO()
-		token, err := deps.Domains().Auth().CreateTokenForAccount(&account, time.Now().Add(time.Minute))
+		token, err := deps.Domains().Auth().CreateTokenForAccount(context.TODO(), account, time.Now().Add(time.Minute), "")
 		require.NoError(t, err)
 
 		w := httptest.NewRecorder()
@@ -46,8 +51,7 @@ func TestAuthMiddleware(t *testing.T) {
 	})
 
 	t.Run("test authorization cookie", func(t *testing.T) {
-		account := model.AccountDTO{Username: "shiori"}
-		token, err := deps.Domains().Auth().CreateTokenForAccount(&account, time.Now().Add(time.Minute))
+		token, err := deps.Domains().Auth().CreateTokenForAccount(context.TODO(), account, time.Now().Add(time.Minute), "")
 		require.NoError(t, err)
 
 		w := httptest.NewRecorder()
@@ -65,6 +69,41 @@ func TestAuthMiddleware(t *testing.T) {
 		require.NotNil(t, c.GetAccount())
 	})
 
+	t.Run("test session is set", func(t *testing.T) {
+		token, err := deps.Domains().Auth().CreateTokenForAccount(context.TODO(), account, time.Now().Add(time.Minute), "")
+		require.NoError(t, err)
+
+		_, session, err := deps.Domains().Auth().CheckSession(context.TODO(), token)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set(model.AuthorizationHeader, model.AuthorizationTokenType+" "+token)
+		c := webcontext.NewWebContext(w, r)
+
+		err = NewAuthMiddleware(deps).OnRequest(deps, c)
+		require.NoError(t, err)
+		require.Equal(t, session.ID, c.GetSessionID())
+	})
+
+	t.Run("test revoked session", func(t *testing.T) {
+		token, err := deps.Domains().Auth().CreateTokenForAccount(context.TODO(), account, time.Now().Add(time.Minute), "")
+		require.NoError(t, err)
+
+		_, session, err := deps.Domains().Auth().CheckSession(context.TODO(), token)
+		require.NoError(t, err)
+		require.NoError(t, deps.Domains().Auth().RevokeSession(context.TODO(), account.ID, session.ID))
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set(model.AuthorizationHeader, model.AuthorizationTokenType+" "+token)
+		c := webcontext.NewWebContext(w, r)
+
+		err = NewAuthMiddleware(deps).OnRequest(deps, c)
+		require.NoError(t, err)
+		require.Nil(t, c.GetAccount())
+	})
+
 	t.Run("test invalid token cookie is removed", func(t *testing.T) {
 		// Create an invalid token
 		invalidToken := "invalid-token"
@@ -99,6 +138,69 @@ func TestAuthMiddleware(t *testing.T) {
 	})
 }
 
+func TestAuthMiddlewareAPITokens(t *testing.T) {
+	ctx := context.TODO()
+	logger := logrus.New()
+	_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+
+	owner, err := deps.Domains().Accounts().CreateAccount(ctx, model.AccountDTO{
+		Username: "owner",
+		Password: "owner",
+		Owner:    model.Ptr(true),
+	})
+	require.NoError(t, err)
+
+	createToken := func(t *testing.T, scope model.APITokenScope) string {
+		_, value, err := deps.Domains().Auth().CreateAPIToken(ctx, owner, string(scope), model.APITokenScopes{scope}, nil)
+		require.NoError(t, err)
+		return value
+	}
+
+	authenticate := func(method, token string) model.WebContext {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(method, "/", nil)
+		r.Header.Set(model.AuthorizationHeader, model.AuthorizationTokenType+" "+token)
+		c := webcontext.NewWebContext(w, r)
+
+		err := NewAuthMiddleware(deps).OnRequest(deps, c)
+		require.NoError(t, err)
+		return c
+	}
+
+	t.Run("read scope", func(t *testing.T) {
+		token := createToken(t, model.APITokenScopeRead)
+
+		c := authenticate(http.MethodGet, token)
+		require.NotNil(t, c.GetAccount())
+		require.Equal(t, owner.ID, c.GetAccount().ID)
+		require.False(t, c.GetAccount().IsOwner())
+
+		c = authenticate(http.MethodPost, token)
+		require.Nil(t, c.GetAccount())
+	})
+
+	t.Run("write scope", func(t *testing.T) {
+		token := createToken(t, model.APITokenScopeWrite)
+
+		c := authenticate(http.MethodDelete, token)
+		require.NotNil(t, c.GetAccount())
+		require.False(t, c.GetAccount().IsOwner())
+	})
+
+	t.Run("admin scope", func(t *testing.T) {
+		token := createToken(t, model.APITokenScopeAdmin)
+
+		c := authenticate(http.MethodPost, token)
+		require.NotNil(t, c.GetAccount())
+		require.True(t, c.GetAccount().IsOwner())
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		c := authenticate(http.MethodGet, model.APITokenPrefix+"unknown")
+		require.Nil(t, c.GetAccount())
+	})
+}
+
 func TestRequireLoggedInUser(t *testing.T) {
 	logger := logrus.New()
 	_, deps := testutil.GetTestConfigurationAndDependencies(t, context.TODO(), logger)
@@ -0,0 +1,316 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/http/response"
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/shiori/internal/webserver"
+)
+
+const (
+	// RateLimitLimitHeader is the header with the requests allowed in the current window
+	RateLimitLimitHeader = "X-RateLimit-Limit"
+	// RateLimitRemainingHeader is the header with the requests left in the current window
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	// RateLimitResetHeader is the header with the unix time the current window ends
+	RateLimitResetHeader = "X-RateLimit-Reset"
+)
+
+var _ model.HttpMiddleware = &RateLimitMiddleware{}
+
+// loginPaths are the endpoints checking credentials, limited by the login budget
+var loginPaths = []string{
+	"/api/v1/auth/login",
+	"/api/v1/auth/login/totp",
+	"/api/v1/auth/login/totp/setup",
+}
+
+// rateLimitWindow counts the hits of a client until the window resets
+type rateLimitWindow struct {
+	hits  int
+	reset time.Time
+}
+
+// RateLimitMiddleware limits the login attempts of each client IP and the API calls of each
+// account or client IP, locking clients out of logins after repeated failures. Clients failing
+// to log in to an account many clients failed to log in to are locked out of that account only,
+// its owner can still log in from elsewhere. The state is kept in memory, so a single instance
+// must be shared by all the routes.
+type RateLimitMiddleware struct {
+	window          time.Duration
+	loginLimit      int
+	apiLimit        int
+	lockoutFailures int
+	lockoutDuration time.Duration
+
+	mu        sync.Mutex
+	requests  map[string]*rateLimitWindow
+	failures  map[string]*rateLimitWindow
+	lockouts  map[string]time.Time
+	nextSweep time.Time
+	now       func() time.Time
+}
+
+// NewRateLimitMiddleware creates a new RateLimitMiddleware with the budgets of the configuration
+func NewRateLimitMiddleware(deps model.Dependencies) *RateLimitMiddleware {
+	cfg := deps.Config().Http
+	return &RateLimitMiddleware{
+		window:          cfg.RateLimitWindow,
+		loginLimit:      cfg.RateLimitLogin,
+		apiLimit:        cfg.RateLimitAPI,
+		lockoutFailures: cfg.RateLimitLockoutFailures,
+		lockoutDuration: cfg.RateLimitLockoutDuration,
+		requests:        make(map[string]*rateLimitWindow),
+		failures:        make(map[string]*rateLimitWindow),
+		lockouts:        make(map[string]time.Time),
+		now:             time.Now,
+	}
+}
+
+// OnRequest rejects the request with a 429 status if the client is over its budget or locked out
+func (m *RateLimitMiddleware) OnRequest(deps model.Dependencies, c model.WebContext) error {
+	path := c.Request().URL.Path
+	if !strings.HasPrefix(path, "/api/") {
+		return nil
+	}
+
+	ip := clientIP(c.Request())
+	isLogin := slices.Contains(loginPaths, path)
+
+	key, limit := "api:ip:"+ip, m.apiLimit
+	if isLogin {
+		key, limit = "login:"+ip, m.loginLimit
+	} else if c.UserIsLogged() {
+		key = fmt.Sprintf("api:account:%d", c.GetAccount().ID)
+	}
+
+	m.mu.Lock()
+	now := m.now()
+	m.sweep(now)
+
+	if isLogin && m.rejectLockedOut(deps, c, lockoutKey(ip, ""), now) {
+		return model.ErrRequestHandled
+	}
+
+	if limit > 0 {
+		counter := m.hit(m.requests, key, now)
+		hits, reset := counter.hits, counter.reset
+		m.mu.Unlock()
+
+		header := c.ResponseWriter().Header()
+		header.Set(RateLimitLimitHeader, strconv.Itoa(limit))
+		header.Set(RateLimitRemainingHeader, strconv.Itoa(max(limit-hits, 0)))
+		header.Set(RateLimitResetHeader, strconv.FormatInt(reset.Unix(), 10))
+
+		if hits > limit {
+			deps.Logger().WithField("key", key).WithField("request_id", c.GetRequestID()).Warn("Rate limit exceeded")
+			setRetryAfter(c, reset.Sub(now))
+			response.SendError(c, http.StatusTooManyRequests, "Too many requests, try again later")
+			return model.ErrRequestHandled
+		}
+	} else {
+		m.mu.Unlock()
+	}
+
+	if !isLogin || m.lockoutFailures <= 0 {
+		return nil
+	}
+
+	// The account is only read once the client is within its budget, finding it can query the
+	// database
+	target := loginTarget(deps, c)
+	if target != "" {
+		m.mu.Lock()
+		if m.rejectLockedOut(deps, c, lockoutKey(ip, target), now) {
+			return model.ErrRequestHandled
+		}
+		m.mu.Unlock()
+	}
+
+	// Failed logins are counted once the handler has answered
+	c.SetResponseWriter(&statusWriter{ResponseWriter: c.ResponseWriter(), statusCode: http.StatusOK, target: target})
+
+	return nil
+}
+
+// rejectLockedOut answers with a 429 status when the key is locked out. The caller must hold
+// the lock, which is released when the request is rejected.
+func (m *RateLimitMiddleware) rejectLockedOut(deps model.Dependencies, c model.WebContext, key string, now time.Time) bool {
+	until, locked := m.lockouts[key]
+	if !locked {
+		return false
+	}
+	m.mu.Unlock()
+
+	deps.Logger().WithField("key", key).WithField("request_id", c.GetRequestID()).Warn("Login attempt of a locked out client")
+	setRetryAfter(c, until.Sub(now))
+	response.SendError(c, http.StatusTooManyRequests, "Too many failed login attempts, try again later")
+	return true
+}
+
+// OnResponse counts the failed logins, locking the client out when it has too many of them.
+// Once an account has too many failures, from any client, each client failing to log in to it
+// is locked out of that account.
+func (m *RateLimitMiddleware) OnResponse(deps model.Dependencies, c model.WebContext) error {
+	writer, ok := c.ResponseWriter().(*statusWriter)
+	if !ok {
+		return nil
+	}
+
+	// Restore the writer for the middlewares answering after this one
+	c.SetResponseWriter(writer.ResponseWriter)
+
+	if writer.statusCode != http.StatusBadRequest && writer.statusCode != http.StatusUnauthorized {
+		return nil
+	}
+
+	ip := clientIP(c.Request())
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if counter := m.hit(m.failures, lockoutKey(ip, ""), now); counter.hits >= m.lockoutFailures {
+		delete(m.failures, lockoutKey(ip, ""))
+		m.lock(deps, c, lockoutKey(ip, ""), now)
+	}
+
+	// The account itself is never locked, a correct password from another client still logs in
+	if writer.target != "" {
+		if counter := m.hit(m.failures, "account:"+writer.target, now); counter.hits >= m.lockoutFailures {
+			m.lock(deps, c, lockoutKey(ip, writer.target), now)
+		}
+	}
+
+	return nil
+}
+
+// lock locks the key out of logins. The caller must hold the lock.
+func (m *RateLimitMiddleware) lock(deps model.Dependencies, c model.WebContext, key string, now time.Time) {
+	m.lockouts[key] = now.Add(m.lockoutDuration)
+	deps.Logger().WithField("key", key).WithField("request_id", c.GetRequestID()).Warn("Locked out after failed logins")
+}
+
+// lockoutKey is the key a client is locked out of logins with, of every account when target is
+// empty or of the account with the target username
+func lockoutKey(ip, target string) string {
+	if target == "" {
+		return "ip:" + ip
+	}
+	return "account:" + target + ":ip:" + ip
+}
+
+// loginTarget returns the username a login request is for, read from the credentials or from
+// the challenge of the second step, empty when it can't be told. The body is left for the handler.
+func loginTarget(deps model.Dependencies, c model.WebContext) string {
+	r := c.Request()
+	if r.Body == nil {
+		return ""
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<16))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+	if err != nil {
+		return ""
+	}
+
+	var payload struct {
+		Username  string `json:"username"`
+		Challenge string `json:"challenge"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return ""
+	}
+
+	username := payload.Username
+	if payload.Challenge != "" {
+		account, _, err := deps.Domains().TOTP().CheckLoginChallenge(r.Context(), payload.Challenge)
+		if err != nil {
+			return ""
+		}
+		username = account.Username
+	}
+
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+// hit counts a hit of the key in its current window, starting a new one if it is over.
+// The caller must hold the lock.
+func (m *RateLimitMiddleware) hit(windows map[string]*rateLimitWindow, key string, now time.Time) *rateLimitWindow {
+	counter, exists := windows[key]
+	if !exists || !now.Before(counter.reset) {
+		counter = &rateLimitWindow{reset: now.Add(m.window)}
+		windows[key] = counter
+	}
+
+	counter.hits++
+	return counter
+}
+
+// sweep removes the windows and lockouts that are over, once every window.
+// The caller must hold the lock.
+func (m *RateLimitMiddleware) sweep(now time.Time) {
+	if now.Before(m.nextSweep) {
+		return
+	}
+	m.nextSweep = now.Add(m.window)
+
+	for _, windows := range []map[string]*rateLimitWindow{m.requests, m.failures} {
+		for key, counter := range windows {
+			if !now.Before(counter.reset) {
+				delete(windows, key)
+			}
+		}
+	}
+
+	for key, until := range m.lockouts {
+		if !now.Before(until) {
+			delete(m.lockouts, key)
+		}
+	}
+}
+
+// setRetryAfter sets the Retry-After header in seconds, rounded up
+func setRetryAfter(c model.WebContext, wait time.Duration) {
+	seconds := int(math.Ceil(wait.Seconds()))
+	c.ResponseWriter().Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+}
+
+// clientIP returns the IP of the client, from the proxy headers when the request comes
+// through a private network
+func clientIP(r *http.Request) string {
+	ip := webserver.GetUserRealIP(r)
+	// Without the port, each connection would have its own budget
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		return host
+	}
+	return ip
+}
+
+// statusWriter is a ResponseWriter keeping the status code sent by the handler
+type statusWriter struct {
+	http.ResponseWriter
+	statusCode int
+	// target is the username the login is for
+	target string
+}
+
+func (w *statusWriter) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+	w.ResponseWriter.WriteHeader(statusCode)
+}
@@ -0,0 +1,220 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-shiori/shiori/internal/model"
+	"github.com/go-shiori/shiori/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("limits login attempts by client ip", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		deps.Config().Http.RateLimitLogin = 2
+		middleware := NewRateLimitMiddleware(deps)
+
+		client := testutil.WithRemoteAddr("34.23.123.122:1234")
+		for i := range 2 {
+			c, w := testutil.NewTestWebContextWithMethod(http.MethodPost, "/api/v1/auth/login", client)
+			require.NoError(t, middleware.OnRequest(deps, c))
+			require.Equal(t, "2", w.Header().Get(RateLimitLimitHeader))
+			require.Equal(t, []string{"1", "0"}[i], w.Header().Get(RateLimitRemainingHeader))
+			require.NotEmpty(t, w.Header().Get(RateLimitResetHeader))
+		}
+
+		c, w := testutil.NewTestWebContextWithMethod(http.MethodPost, "/api/v1/auth/login/totp", client)
+		require.ErrorIs(t, middleware.OnRequest(deps, c), model.ErrRequestHandled)
+		require.Equal(t, http.StatusTooManyRequests, w.Code)
+		require.NotEmpty(t, w.Header().Get("Retry-After"))
+
+		// Proxy headers sent by a public client itself are ignored
+		c, _ = testutil.NewTestWebContextWithMethod(http.MethodPost, "/api/v1/auth/login", client, testutil.WithHeader("X-Real-Ip", "8.8.8.8"))
+		require.ErrorIs(t, middleware.OnRequest(deps, c), model.ErrRequestHandled)
+
+		// Another client has its own budget
+		c, _ = testutil.NewTestWebContextWithMethod(http.MethodPost, "/api/v1/auth/login", testutil.WithRemoteAddr("34.23.123.123:1234"))
+		require.NoError(t, middleware.OnRequest(deps, c))
+	})
+
+	t.Run("client ip behind proxies", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		deps.Config().Http.RateLimitLogin = 1
+		middleware := NewRateLimitMiddleware(deps)
+
+		c, _ := testutil.NewTestWebContextWithMethod(http.MethodPost, "/api/v1/auth/login",
+			testutil.WithRemoteAddr("10.0.0.2:1234"),
+			testutil.WithHeader("X-Forwarded-For", "8.8.8.8, 34.23.123.122"),
+		)
+		require.NoError(t, middleware.OnRequest(deps, c))
+
+		// The first hops are set by the client, changing them doesn't give a new budget
+		c, _ = testutil.NewTestWebContextWithMethod(http.MethodPost, "/api/v1/auth/login",
+			testutil.WithRemoteAddr("10.0.0.2:5678"),
+			testutil.WithHeader("X-Forwarded-For", "8.8.4.4, 34.23.123.122, 10.0.0.3"),
+		)
+		require.ErrorIs(t, middleware.OnRequest(deps, c), model.ErrRequestHandled)
+
+		// Clients of the private network without proxy headers are told apart by address only
+		c, _ = testutil.NewTestWebContextWithMethod(http.MethodPost, "/api/v1/auth/login", testutil.WithRemoteAddr("10.0.0.5:1234"))
+		require.NoError(t, middleware.OnRequest(deps, c))
+		c, _ = testutil.NewTestWebContextWithMethod(http.MethodPost, "/api/v1/auth/login", testutil.WithRemoteAddr("10.0.0.5:5678"))
+		require.ErrorIs(t, middleware.OnRequest(deps, c), model.ErrRequestHandled)
+	})
+
+	t.Run("limits api calls by account", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		deps.Config().Http.RateLimitAPI = 1
+		middleware := NewRateLimitMiddleware(deps)
+
+		user := &model.AccountDTO{ID: 1, Username: "user"}
+		c, _ := testutil.NewTestWebContextWithMethod(http.MethodGet, "/api/v1/bookmarks", testutil.WithAccount(user))
+		require.NoError(t, middleware.OnRequest(deps, c))
+
+		c, w := testutil.NewTestWebContextWithMethod(http.MethodGet, "/api/v1/tags", testutil.WithAccount(user))
+		require.ErrorIs(t, middleware.OnRequest(deps, c), model.ErrRequestHandled)
+		require.Equal(t, http.StatusTooManyRequests, w.Code)
+
+		other := &model.AccountDTO{ID: 2, Username: "other"}
+		c, _ = testutil.NewTestWebContextWithMethod(http.MethodGet, "/api/v1/bookmarks", testutil.WithAccount(other))
+		require.NoError(t, middleware.OnRequest(deps, c))
+
+		// Pages and assets aren't limited
+		c, w = testutil.NewTestWebContextWithMethod(http.MethodGet, "/assets/js/app.js", testutil.WithAccount(user))
+		require.NoError(t, middleware.OnRequest(deps, c))
+		require.Empty(t, w.Header().Get(RateLimitLimitHeader))
+	})
+
+	t.Run("locks clients out after failed logins", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		deps.Config().Http.RateLimitLockoutFailures = 2
+		deps.Config().Http.RateLimitLockoutDuration = time.Hour
+		middleware := NewRateLimitMiddleware(deps)
+		now := time.Now()
+		middleware.now = func() time.Time { return now }
+
+		for range 2 {
+			c, w := testutil.NewTestWebContextWithMethod(http.MethodPost, "/api/v1/auth/login")
+			require.NoError(t, middleware.OnRequest(deps, c))
+			c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+			require.NoError(t, middleware.OnResponse(deps, c))
+			require.Equal(t, w, c.ResponseWriter())
+		}
+
+		c, w := testutil.NewTestWebContextWithMethod(http.MethodPost, "/api/v1/auth/login")
+		require.ErrorIs(t, middleware.OnRequest(deps, c), model.ErrRequestHandled)
+		require.Equal(t, http.StatusTooManyRequests, w.Code)
+		require.Equal(t, "3600", w.Header().Get("Retry-After"))
+
+		// Other API calls aren't locked out
+		c, _ = testutil.NewTestWebContextWithMethod(http.MethodGet, "/api/v1/bookmarks")
+		require.NoError(t, middleware.OnRequest(deps, c))
+
+		now = now.Add(time.Hour)
+		c, _ = testutil.NewTestWebContextWithMethod(http.MethodPost, "/api/v1/auth/login")
+		require.NoError(t, middleware.OnRequest(deps, c))
+	})
+
+	t.Run("locks clients out of an account failing from many clients", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		deps.Config().Http.RateLimitLockoutFailures = 2
+		middleware := NewRateLimitMiddleware(deps)
+
+		login := func(client int, body string) (model.WebContext, error) {
+			c, _ := testutil.NewTestWebContextWithMethod(http.MethodPost, "/api/v1/auth/login",
+				testutil.WithRemoteAddr(fmt.Sprintf("34.23.123.%d:1234", client)),
+				testutil.WithBody(body),
+			)
+			return c, middleware.OnRequest(deps, c)
+		}
+
+		for i := range 2 {
+			c, err := login(i, `{"username": "Victim", "password": "guess"}`)
+			require.NoError(t, err)
+
+			// The handler still gets the whole body
+			body, err := io.ReadAll(c.Request().Body)
+			require.NoError(t, err)
+			require.Contains(t, string(body), "guess")
+
+			c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+			require.NoError(t, middleware.OnResponse(deps, c))
+		}
+
+		// The client failing once the account had too many failures is locked out of it
+		_, err := login(1, `{"username": "victim", "password": "guess"}`)
+		require.ErrorIs(t, err, model.ErrRequestHandled)
+
+		// But not of other accounts
+		_, err = login(1, `{"username": "other", "password": "password"}`)
+		require.NoError(t, err)
+
+		// The account isn't locked, its owner can still log in from another client
+		c, err := login(9, `{"username": "victim", "password": "password"}`)
+		require.NoError(t, err)
+		c.ResponseWriter().WriteHeader(http.StatusOK)
+		require.NoError(t, middleware.OnResponse(deps, c))
+
+		c, err = login(9, `{"username": "victim", "password": "password"}`)
+		require.NoError(t, err)
+
+		// Another guess is locked out after its first failure
+		c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+		require.NoError(t, middleware.OnResponse(deps, c))
+		_, err = login(9, `{"username": "victim", "password": "guess"}`)
+		require.ErrorIs(t, err, model.ErrRequestHandled)
+
+		// The second step counts for the account of the challenge
+		account, err := deps.Domains().Accounts().CreateAccount(ctx, model.AccountDTO{Username: "victim", Password: "password"})
+		require.NoError(t, err)
+		challenge, err := deps.Domains().TOTP().CreateLoginChallenge(account, false)
+		require.NoError(t, err)
+
+		c, _ = testutil.NewTestWebContextWithMethod(http.MethodPost, "/api/v1/auth/login/totp",
+			testutil.WithRemoteAddr("34.23.123.1:1234"),
+			testutil.WithBody(`{"challenge": "`+challenge+`", "code": "000000"}`),
+		)
+		require.ErrorIs(t, middleware.OnRequest(deps, c), model.ErrRequestHandled)
+	})
+
+	t.Run("over budget clients are rejected before the login is read", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		deps.Config().Http.RateLimitLogin = 1
+		middleware := NewRateLimitMiddleware(deps)
+
+		c, _ := testutil.NewTestWebContextWithMethod(http.MethodPost, "/api/v1/auth/login", testutil.WithBody(`{"username": "user"}`))
+		require.NoError(t, middleware.OnRequest(deps, c))
+
+		c, _ = testutil.NewTestWebContextWithMethod(http.MethodPost, "/api/v1/auth/login", testutil.WithBody(`{"username": "user"}`))
+		original := c.Request().Body
+		require.ErrorIs(t, middleware.OnRequest(deps, c), model.ErrRequestHandled)
+
+		// The body wasn't read
+		require.Equal(t, original, c.Request().Body)
+		body, err := io.ReadAll(c.Request().Body)
+		require.NoError(t, err)
+		require.Equal(t, `{"username": "user"}`, string(body))
+	})
+
+	t.Run("successful logins are not failures", func(t *testing.T) {
+		_, deps := testutil.GetTestConfigurationAndDependencies(t, ctx, logger)
+		deps.Config().Http.RateLimitLockoutFailures = 1
+		middleware := NewRateLimitMiddleware(deps)
+
+		for range 2 {
+			c, _ := testutil.NewTestWebContextWithMethod(http.MethodPost, "/api/v1/auth/login")
+			require.NoError(t, middleware.OnRequest(deps, c))
+			c.ResponseWriter().WriteHeader(http.StatusOK)
+			require.NoError(t, middleware.OnResponse(deps, c))
+		}
+	})
+}
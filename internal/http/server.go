@@ -2,6 +2,7 @@ package http
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -19,13 +20,19 @@ import (
 )
 
 type HttpServer struct {
-	mux    *http.ServeMux
-	server *http.Server
-	logger *logrus.Logger
+	mux           *http.ServeMux
+	server        *http.Server
+	logger        *logrus.Logger
+	jobs          model.JobsDomain
+	linkChecker   model.LinkCheckerDomain
+	subscriptions model.SubscriptionsDomain
 }
 
 func (s *HttpServer) Setup(cfg *config.Config, deps *dependencies.Dependencies) (*HttpServer, error) {
 	s.mux = http.NewServeMux()
+	s.jobs = deps.Domains().Jobs()
+	s.linkChecker = deps.Domains().LinkChecker()
+	s.subscriptions = deps.Domains().Subscriptions()
 
 	if err := templates.SetupTemplates(cfg); err != nil {
 		return nil, fmt.Errorf("failed to setup templates: %w", err)
@@ -45,6 +52,11 @@ func (s *HttpServer) Setup(cfg *config.Config, deps *dependencies.Dependencies)
 		middleware.NewCORSMiddleware([]string{"*"}),
 	}...)
 
+	// Rate limits go after the authentication, API calls are limited by account
+	if cfg.Http.RateLimitEnabled {
+		globalMiddleware = append(globalMiddleware, middleware.NewRateLimitMiddleware(deps))
+	}
+
 	if cfg.Http.AccessLog {
 		globalMiddleware = append(globalMiddleware, middleware.NewLoggingMiddleware())
 	}
@@ -59,8 +71,11 @@ func (s *HttpServer) Setup(cfg *config.Config, deps *dependencies.Dependencies)
 	s.mux.HandleFunc("GET /bookmark/{id}/content", ToHTTPHandler(deps, handlers.HandleBookmarkContent, globalMiddleware...))
 	s.mux.HandleFunc("GET /bookmark/{id}/archive", ToHTTPHandler(deps, handlers.HandleBookmarkArchive, globalMiddleware...))
 	s.mux.HandleFunc("GET /bookmark/{id}/archive/file/{path...}", ToHTTPHandler(deps, handlers.HandleBookmarkArchiveFile, globalMiddleware...))
+	s.mux.HandleFunc("GET /bookmark/{id}/archive/snapshot/{snapshot}", ToHTTPHandler(deps, handlers.HandleBookmarkArchive, globalMiddleware...))
+	s.mux.HandleFunc("GET /bookmark/{id}/archive/snapshot/{snapshot}/file/{path...}", ToHTTPHandler(deps, handlers.HandleBookmarkArchiveFile, globalMiddleware...))
 	s.mux.HandleFunc("GET /bookmark/{id}/thumb", ToHTTPHandler(deps, handlers.HandleBookmarkThumbnail, globalMiddleware...))
 	s.mux.HandleFunc("GET /bookmark/{id}/ebook", ToHTTPHandler(deps, handlers.HandleBookmarkEbook, globalMiddleware...))
+	s.mux.HandleFunc("GET /feeds/{format}", ToHTTPHandler(deps, handlers.HandleFeed, globalMiddleware...))
 
 	// Add this inside Setup() where other routes are registered
 	if cfg.Http.ServeSwagger {
@@ -125,6 +140,86 @@ func (s *HttpServer) Setup(cfg *config.Config, deps *dependencies.Dependencies)
 		api_v1.HandleLogout,
 		globalMiddleware...,
 	))
+	s.mux.HandleFunc("POST /api/v1/auth/login/totp", ToHTTPHandler(deps,
+		api_v1.HandleLoginTOTP,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("POST /api/v1/auth/login/totp/setup", ToHTTPHandler(deps,
+		api_v1.HandleLoginTOTPSetup,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("GET /api/v1/auth/totp", ToHTTPHandler(deps,
+		api_v1.HandleGetTOTP,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("POST /api/v1/auth/totp", ToHTTPHandler(deps,
+		api_v1.HandleEnrollTOTP,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("POST /api/v1/auth/totp/enable", ToHTTPHandler(deps,
+		api_v1.HandleEnableTOTP,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("POST /api/v1/auth/totp/disable", ToHTTPHandler(deps,
+		api_v1.HandleDisableTOTP,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("PUT /api/v1/auth/totp/policy", ToHTTPHandler(deps,
+		api_v1.HandleUpdateTOTPPolicy,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("GET /api/v1/auth/oidc/login", ToHTTPHandler(deps,
+		api_v1.HandleOIDCLogin,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("GET /api/v1/auth/oidc/callback", ToHTTPHandler(deps,
+		api_v1.HandleOIDCCallback,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("POST /api/v1/auth/oidc/link", ToHTTPHandler(deps,
+		api_v1.HandleOIDCLink,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("GET /api/v1/auth/oidc/identities", ToHTTPHandler(deps,
+		api_v1.HandleListOIDCIdentities,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("DELETE /api/v1/auth/oidc/identities/{id}", ToHTTPHandler(deps,
+		api_v1.HandleUnlinkOIDCIdentity,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("GET /api/v1/auth/sessions", ToHTTPHandler(deps,
+		api_v1.HandleListSessions,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("DELETE /api/v1/auth/sessions/{id}", ToHTTPHandler(deps,
+		api_v1.HandleRevokeSession,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("GET /api/v1/auth/tokens", ToHTTPHandler(deps,
+		api_v1.HandleListAPITokens,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("POST /api/v1/auth/tokens", ToHTTPHandler(deps,
+		api_v1.HandleCreateAPIToken,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("DELETE /api/v1/auth/tokens/{id}", ToHTTPHandler(deps,
+		api_v1.HandleRevokeAPIToken,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("GET /api/v1/auth/feed-token", ToHTTPHandler(deps,
+		api_v1.HandleGetFeedToken,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("POST /api/v1/auth/feed-token", ToHTTPHandler(deps,
+		api_v1.HandleCreateFeedToken,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("DELETE /api/v1/auth/feed-token", ToHTTPHandler(deps,
+		api_v1.HandleRevokeFeedToken,
+		globalMiddleware...,
+	))
 	// Accounts
 	s.mux.HandleFunc("GET /api/v1/accounts", ToHTTPHandler(deps,
 		api_v1.HandleListAccounts,
@@ -163,7 +258,43 @@ func (s *HttpServer) Setup(cfg *config.Config, deps *dependencies.Dependencies)
 		api_v1.HandleDeleteTag,
 		globalMiddleware...,
 	))
+	s.mux.HandleFunc("POST /api/v1/tags/{id}/merge", ToHTTPHandler(deps,
+		api_v1.HandleMergeTags,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("GET /api/v1/tags/{id}/aliases", ToHTTPHandler(deps,
+		api_v1.HandleListTagAliases,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("POST /api/v1/tags/{id}/aliases", ToHTTPHandler(deps,
+		api_v1.HandleCreateTagAlias,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("DELETE /api/v1/tags/{id}/aliases/{alias_id}", ToHTTPHandler(deps,
+		api_v1.HandleDeleteTagAlias,
+		globalMiddleware...,
+	))
 	// Bookmarks
+	s.mux.HandleFunc("GET /api/v1/bookmarks", ToHTTPHandler(deps,
+		api_v1.HandleListBookmarks,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("POST /api/v1/bookmarks", ToHTTPHandler(deps,
+		api_v1.HandleCreateBookmark,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("GET /api/v1/bookmarks/{id}", ToHTTPHandler(deps,
+		api_v1.HandleGetBookmark,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("PATCH /api/v1/bookmarks/{id}", ToHTTPHandler(deps,
+		api_v1.HandleUpdateBookmark,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("DELETE /api/v1/bookmarks/{id}", ToHTTPHandler(deps,
+		api_v1.HandleDeleteBookmark,
+		globalMiddleware...,
+	))
 	s.mux.HandleFunc("PUT /api/v1/bookmarks/cache", ToHTTPHandler(deps,
 		api_v1.HandleUpdateCache,
 		globalMiddleware...,
@@ -176,6 +307,59 @@ func (s *HttpServer) Setup(cfg *config.Config, deps *dependencies.Dependencies)
 		api_v1.HandleBulkUpdateBookmarkTags,
 		globalMiddleware...,
 	))
+	s.mux.HandleFunc("PUT /api/v1/bookmarks/bulk/collection", ToHTTPHandler(deps,
+		api_v1.HandleMoveBookmarksToCollection,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("GET /api/v1/bookmarks/{id}/archives", ToHTTPHandler(deps,
+		api_v1.HandleListBookmarkArchives,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("DELETE /api/v1/bookmarks/{id}/archives/{snapshot_id}", ToHTTPHandler(deps,
+		api_v1.HandleDeleteBookmarkArchive,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("GET /api/v1/bookmarks/{id}/checks", ToHTTPHandler(deps,
+		api_v1.HandleListBookmarkLinkChecks,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("POST /api/v1/bookmarks/check", ToHTTPHandler(deps,
+		api_v1.HandleCheckBookmarkLinks,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("POST /api/v1/bookmarks/reindex", ToHTTPHandler(deps,
+		api_v1.HandleReindexBookmarkArchives,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("GET /api/v1/export", ToHTTPHandler(deps,
+		api_v1.HandleExportBookmarks,
+		globalMiddleware...,
+	))
+	// Highlights
+	s.mux.HandleFunc("GET /api/v1/bookmarks/{id}/highlights", ToHTTPHandler(deps,
+		api_v1.HandleListHighlights,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("POST /api/v1/bookmarks/{id}/highlights", ToHTTPHandler(deps,
+		api_v1.HandleCreateHighlight,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("GET /api/v1/bookmarks/{id}/highlights/{highlight_id}", ToHTTPHandler(deps,
+		api_v1.HandleGetHighlight,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("PUT /api/v1/bookmarks/{id}/highlights/{highlight_id}", ToHTTPHandler(deps,
+		api_v1.HandleUpdateHighlight,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("DELETE /api/v1/bookmarks/{id}/highlights/{highlight_id}", ToHTTPHandler(deps,
+		api_v1.HandleDeleteHighlight,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("GET /api/v1/highlights/export", ToHTTPHandler(deps,
+		api_v1.HandleExportHighlights,
+		globalMiddleware...,
+	))
 	// Bookmark tags endpoints
 	s.mux.HandleFunc("GET /api/v1/bookmarks/{id}/tags", ToHTTPHandler(deps,
 		api_v1.HandleGetBookmarkTags,
@@ -189,6 +373,131 @@ func (s *HttpServer) Setup(cfg *config.Config, deps *dependencies.Dependencies)
 		api_v1.HandleRemoveTagFromBookmark,
 		globalMiddleware...,
 	))
+	// Jobs
+	s.mux.HandleFunc("GET /api/v1/jobs", ToHTTPHandler(deps,
+		api_v1.HandleListJobs,
+		globalMiddleware...,
+	))
+	// Feed subscriptions
+	s.mux.HandleFunc("GET /api/v1/subscriptions", ToHTTPHandler(deps,
+		api_v1.HandleListSubscriptions,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("POST /api/v1/subscriptions", ToHTTPHandler(deps,
+		api_v1.HandleCreateSubscription,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("GET /api/v1/subscriptions/{id}", ToHTTPHandler(deps,
+		api_v1.HandleGetSubscription,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("DELETE /api/v1/subscriptions/{id}", ToHTTPHandler(deps,
+		api_v1.HandleDeleteSubscription,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("POST /api/v1/subscriptions/{id}/poll", ToHTTPHandler(deps,
+		api_v1.HandlePollSubscription,
+		globalMiddleware...,
+	))
+	// Webhooks
+	s.mux.HandleFunc("GET /api/v1/webhooks", ToHTTPHandler(deps,
+		api_v1.HandleListWebhooks,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("POST /api/v1/webhooks", ToHTTPHandler(deps,
+		api_v1.HandleCreateWebhook,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("GET /api/v1/webhooks/{id}", ToHTTPHandler(deps,
+		api_v1.HandleGetWebhook,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("DELETE /api/v1/webhooks/{id}", ToHTTPHandler(deps,
+		api_v1.HandleDeleteWebhook,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("GET /api/v1/webhooks/{id}/deliveries", ToHTTPHandler(deps,
+		api_v1.HandleListWebhookDeliveries,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("POST /api/v1/webhooks/{id}/deliveries/{delivery_id}/replay", ToHTTPHandler(deps,
+		api_v1.HandleReplayWebhookDelivery,
+		globalMiddleware...,
+	))
+
+	// Collections
+	s.mux.HandleFunc("GET /api/v1/collections", ToHTTPHandler(deps,
+		api_v1.HandleListCollections,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("POST /api/v1/collections", ToHTTPHandler(deps,
+		api_v1.HandleCreateCollection,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("GET /api/v1/collections/{id}", ToHTTPHandler(deps,
+		api_v1.HandleGetCollection,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("PATCH /api/v1/collections/{id}", ToHTTPHandler(deps,
+		api_v1.HandleUpdateCollection,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("DELETE /api/v1/collections/{id}", ToHTTPHandler(deps,
+		api_v1.HandleDeleteCollection,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("POST /api/v1/collections/{id}/move", ToHTTPHandler(deps,
+		api_v1.HandleMoveCollection,
+		globalMiddleware...,
+	))
+
+	// Rules
+	s.mux.HandleFunc("GET /api/v1/rules", ToHTTPHandler(deps,
+		api_v1.HandleListRules,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("POST /api/v1/rules", ToHTTPHandler(deps,
+		api_v1.HandleCreateRule,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("POST /api/v1/rules/dry-run", ToHTTPHandler(deps,
+		api_v1.HandleDryRunRule,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("GET /api/v1/rules/{id}", ToHTTPHandler(deps,
+		api_v1.HandleGetRule,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("PUT /api/v1/rules/{id}", ToHTTPHandler(deps,
+		api_v1.HandleUpdateRule,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("DELETE /api/v1/rules/{id}", ToHTTPHandler(deps,
+		api_v1.HandleDeleteRule,
+		globalMiddleware...,
+	))
+
+	// Saved searches
+	s.mux.HandleFunc("GET /api/v1/searches", ToHTTPHandler(deps,
+		api_v1.HandleListSavedSearches,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("POST /api/v1/searches", ToHTTPHandler(deps,
+		api_v1.HandleCreateSavedSearch,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("GET /api/v1/searches/{id}", ToHTTPHandler(deps,
+		api_v1.HandleGetSavedSearch,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("PUT /api/v1/searches/{id}", ToHTTPHandler(deps,
+		api_v1.HandleUpdateSavedSearch,
+		globalMiddleware...,
+	))
+	s.mux.HandleFunc("DELETE /api/v1/searches/{id}", ToHTTPHandler(deps,
+		api_v1.HandleDeleteSavedSearch,
+		globalMiddleware...,
+	))
 
 	s.server = &http.Server{
 		Addr:    fmt.Sprintf("%s%d", cfg.Http.Address, cfg.Http.Port),
@@ -198,7 +507,19 @@ func (s *HttpServer) Setup(cfg *config.Config, deps *dependencies.Dependencies)
 	return s, nil
 }
 
-func (s *HttpServer) Start(_ context.Context) error {
+func (s *HttpServer) Start(ctx context.Context) error {
+	if err := s.jobs.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start job workers: %w", err)
+	}
+
+	if err := s.linkChecker.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start link check scheduler: %w", err)
+	}
+
+	if err := s.subscriptions.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start subscriptions scheduler: %w", err)
+	}
+
 	s.logger.WithField("addr", s.server.Addr).Info("starting http server")
 	go func() {
 		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -210,7 +531,15 @@ func (s *HttpServer) Start(_ context.Context) error {
 
 func (s *HttpServer) Stop(ctx context.Context) error {
 	s.logger.WithField("addr", s.server.Addr).Info("stopping http server")
-	return s.server.Shutdown(ctx)
+
+	// Every component is stopped even if another fails, so no job is left running.
+	// Requests stop first, so no new jobs are queued while the queue drains.
+	return errors.Join(
+		s.server.Shutdown(ctx),
+		s.linkChecker.Stop(ctx),
+		s.subscriptions.Stop(ctx),
+		s.jobs.Stop(ctx),
+	)
 }
 
 func (s *HttpServer) WaitStop(ctx context.Context) {
@@ -184,10 +184,13 @@ func TestHttpServer_Middleware(t *testing.T) {
 
 				if route.auth {
 					// Create a non-admin user token
-					account := testutil.GetValidAccount()
-					account.Owner = false // Ensure not admin
-					accountDTO := account.ToDTO()
-					token, err := deps.Domains().Auth().CreateTokenForAccount(&accountDTO, time.Now().Add(time.Hour))
+					account, err := deps.Domains().Accounts().CreateAccount(context.TODO(), model.AccountDTO{
+						Username: "user",
+						Password: "user",
+						Owner:    model.Ptr(false), // Ensure not admin
+					})
+					require.NoError(t, err)
+					token, err := deps.Domains().Auth().CreateTokenForAccount(context.TODO(), account, time.Now().Add(time.Hour), "")
 					require.NoError(t, err)
 					req.Header.Set(model.AuthorizationHeader, model.AuthorizationTokenType+" "+token)
 				}
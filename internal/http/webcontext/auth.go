@@ -25,6 +25,21 @@ func (c *WebContext) SetAccount(account *model.AccountDTO) {
 	c.request = c.request.WithContext(ctx)
 }
 
+// GetSessionID returns the ID of the session the request was authenticated with, empty
+// for requests without one like the ones using personal API tokens
+func (c *WebContext) GetSessionID() string {
+	if id := c.request.Context().Value(sessionIDKey); id != nil {
+		return id.(string)
+	}
+	return ""
+}
+
+// SetSessionID stores the ID of the session in the request context
+func (c *WebContext) SetSessionID(id string) {
+	ctx := context.WithValue(c.request.Context(), sessionIDKey, id)
+	c.request = c.request.WithContext(ctx)
+}
+
 // WithAccount creates a new context with the account
 func WithAccount(ctx context.Context, account *model.AccountDTO) context.Context {
 	return context.WithValue(ctx, accountKey, account)
@@ -5,4 +5,5 @@ type contextKey string
 const (
 	accountKey   contextKey = "account"
 	requestIDKey contextKey = "requestID"
+	sessionIDKey contextKey = "sessionID"
 )
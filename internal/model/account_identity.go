@@ -0,0 +1,11 @@
+package model
+
+// AccountIdentity links an account to a user of an OpenID Connect provider, known by the
+// issuer and subject of their ID tokens. Unlike the other claims, the subject never changes.
+type AccountIdentity struct {
+	ID        DBID   `db:"id"         json:"id"`
+	AccountID DBID   `db:"account_id" json:"account_id"`
+	Issuer    string `db:"issuer"     json:"issuer"`
+	Subject   string `db:"subject"    json:"subject"`
+	CreatedAt string `db:"created_at" json:"created_at"`
+}
@@ -0,0 +1,88 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// APITokenPrefix starts every personal API token, telling them apart from session JWTs
+const APITokenPrefix = "shiori_pat_"
+
+// APITokenScope is a permission granted to a personal API token
+type APITokenScope string
+
+const (
+	// APITokenScopeRead allows read only requests
+	APITokenScopeRead APITokenScope = "read"
+	// APITokenScopeWrite allows every request a regular user can make
+	APITokenScopeWrite APITokenScope = "write"
+	// APITokenScopeAdmin allows the requests reserved to owners, for owner accounts
+	APITokenScopeAdmin APITokenScope = "admin"
+)
+
+// apiTokenScopes are the known scopes, each one includes the ones before it
+var apiTokenScopes = []APITokenScope{APITokenScopeRead, APITokenScopeWrite, APITokenScopeAdmin}
+
+// IsValid checks that the scope is a known one
+func (s APITokenScope) IsValid() error {
+	if !slices.Contains(apiTokenScopes, s) {
+		return fmt.Errorf("invalid token scope: %s", s)
+	}
+	return nil
+}
+
+// APITokenScopes is the list of scopes of a token, stored as a comma separated string
+type APITokenScopes []APITokenScope
+
+// Allows reports whether the scopes grant the given one, directly or through a wider scope.
+func (s APITokenScopes) Allows(scope APITokenScope) bool {
+	wanted := slices.Index(apiTokenScopes, scope)
+	for _, granted := range s {
+		if i := slices.Index(apiTokenScopes, granted); i >= 0 && i >= wanted {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *APITokenScopes) Scan(value interface{}) error {
+	var raw string
+	switch v := value.(type) {
+	case []byte:
+		raw = string(v)
+	case string:
+		raw = v
+	default:
+		return fmt.Errorf("unsupported type: %T", v)
+	}
+
+	*s = APITokenScopes{}
+	for _, scope := range strings.Split(raw, ",") {
+		if scope != "" {
+			*s = append(*s, APITokenScope(scope))
+		}
+	}
+	return nil
+}
+
+func (s APITokenScopes) Value() (driver.Value, error) {
+	scopes := make([]string, 0, len(s))
+	for _, scope := range s {
+		scopes = append(scopes, string(scope))
+	}
+	return strings.Join(scopes, ","), nil
+}
+
+// APIToken is a long lived credential of an account, only the hash of the token is stored
+type APIToken struct {
+	ID         DBID           `db:"id"           json:"id"`
+	AccountID  DBID           `db:"account_id"   json:"account_id"`
+	Name       string         `db:"name"         json:"name"`
+	TokenHash  string         `db:"token_hash"   json:"-"`
+	Scopes     APITokenScopes `db:"scopes"       json:"scopes"`
+	ExpiresAt  *string        `db:"expires_at"   json:"expires_at"`
+	LastUsedAt *string        `db:"last_used_at" json:"last_used_at"`
+	CreatedAt  string         `db:"created_at"   json:"created_at"`
+}
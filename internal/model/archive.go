@@ -0,0 +1,20 @@
+package model
+
+// ArchiveTextMaxLength is the maximum length in bytes of the text indexed from an archive
+const ArchiveTextMaxLength = 500000
+
+// ArchiveSnapshot is a capture of the offline archive of a bookmark
+type ArchiveSnapshot struct {
+	ID         DBID   `db:"id"          json:"id"`
+	BookmarkID int    `db:"bookmark_id" json:"bookmark_id"`
+	Path       string `db:"path"        json:"-"`
+	CreatedAt  string `db:"created_at"  json:"created_at"`
+	// Current is set for the snapshot served as the bookmark archive
+	Current bool `db:"-" json:"current"`
+}
+
+// IndexArchivesJobPayload is the payload of a JobTypeIndexArchives job
+type IndexArchivesJobPayload struct {
+	// Bookmarks whose archive is indexed, empty indexes every bookmark
+	BookmarkIDs []int `json:"bookmark_ids"`
+}
@@ -0,0 +1,31 @@
+package model
+
+// BackupFormatVersion is the version of the layout of backup archives
+const BackupFormatVersion = 1
+
+// BackupManifestName is the name of the manifest inside a backup archive
+const BackupManifestName = "manifest.json"
+
+// BackupManifest describes the content of a backup archive. It is the last entry of the
+// archive and holds the checksum of every other entry.
+type BackupManifest struct {
+	Version       int    `json:"version"`
+	ShioriVersion string `json:"shiori_version"`
+	CreatedAt     string `json:"created_at"`
+	// Driver of the database the backup was made from
+	Database    string `json:"database"`
+	Accounts    int    `json:"accounts"`
+	Tags        int    `json:"tags"`
+	Collections int    `json:"collections"`
+	Bookmarks   int    `json:"bookmarks"`
+	// Number of thumbnails, archives and ebooks
+	StorageFiles int          `json:"storage_files"`
+	Files        []BackupFile `json:"files"`
+}
+
+// BackupFile is an entry of a backup archive
+type BackupFile struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
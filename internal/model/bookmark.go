@@ -3,11 +3,13 @@ package model
 import (
 	"path/filepath"
 	"strconv"
+	"time"
 )
 
 // Bookmark is the database representation of a bookmark
 type Bookmark struct {
 	ID         int    `db:"id"`
+	AccountID  DBID   `db:"account_id"`
 	URL        string `db:"url"`
 	Title      string `db:"title"`
 	Excerpt    string `db:"excerpt"`
@@ -22,6 +24,7 @@ type Bookmark struct {
 // at the same time, pending a refactor to two separate object to represent each role.
 type BookmarkDTO struct {
 	ID            int      `db:"id"            json:"id"`
+	AccountID     DBID     `db:"account_id"    json:"account_id"`
 	URL           string   `db:"url"           json:"url"`
 	Title         string   `db:"title"         json:"title"`
 	Excerpt       string   `db:"excerpt"       json:"excerpt"`
@@ -33,7 +36,9 @@ type BookmarkDTO struct {
 	HTML          string   `db:"html"          json:"html,omitempty"`
 	ImageURL      string   `db:"image_url"     json:"imageURL"`
 	HasContent    bool     `db:"has_content"   json:"hasContent"`
+	CollectionID  *DBID    `db:"collection_id" json:"collection_id"` // nil if the bookmark is in no collection
 	Tags          []TagDTO `json:"tags"`
+	Snippet       string   `json:"snippet,omitempty"` // extract matching the text searched, see SearchQuery.Snippet
 	HasArchive    bool     `json:"hasArchive"`
 	HasEbook      bool     `json:"hasEbook"`
 	CreateArchive bool     `json:"create_archive"` // TODO: migrate outside the DTO
@@ -44,6 +49,7 @@ type BookmarkDTO struct {
 func (dto *BookmarkDTO) ToBookmark() Bookmark {
 	return Bookmark{
 		ID:         dto.ID,
+		AccountID:  dto.AccountID,
 		URL:        dto.URL,
 		Title:      dto.Title,
 		Excerpt:    dto.Excerpt,
@@ -59,6 +65,7 @@ func (dto *BookmarkDTO) ToBookmark() Bookmark {
 func (b *Bookmark) ToDTO() BookmarkDTO {
 	return BookmarkDTO{
 		ID:         b.ID,
+		AccountID:  b.AccountID,
 		URL:        b.URL,
 		Title:      b.Title,
 		Excerpt:    b.Excerpt,
@@ -71,6 +78,45 @@ func (b *Bookmark) ToDTO() BookmarkDTO {
 	}
 }
 
+// ListBookmarksOptions is options for listing bookmarks through the bookmarks domain.
+type ListBookmarksOptions struct {
+	// Only list bookmarks owned by this account, zero lists bookmarks from every account
+	AccountID DBID
+	// Only list public bookmarks
+	PublicOnly   bool
+	Keyword      string
+	Tags         []string
+	ExcludedTags []string
+	LinkStatus   LinkStatus
+	// Only list bookmarks directly in this collection if set, zero lists the ones in none
+	CollectionID *DBID
+	// Only list bookmarks added after this time if set
+	AddedAfter time.Time
+	// Load the content and readable HTML of the bookmarks
+	WithContent bool
+	OrderMethod DBOrderMethod
+	Limit       int
+	Offset      int
+}
+
+// ToDBGetBookmarksOptions converts the listing options into database options.
+func (o ListBookmarksOptions) ToDBGetBookmarksOptions() DBGetBookmarksOptions {
+	return DBGetBookmarksOptions{
+		AccountID:    o.AccountID,
+		PublicOnly:   o.PublicOnly,
+		Keyword:      o.Keyword,
+		Tags:         o.Tags,
+		ExcludedTags: o.ExcludedTags,
+		LinkStatus:   o.LinkStatus,
+		CollectionID: o.CollectionID,
+		AddedAfter:   o.AddedAfter,
+		WithContent:  o.WithContent,
+		OrderMethod:  o.OrderMethod,
+		Limit:        o.Limit,
+		Offset:       o.Offset,
+	}
+}
+
 // GetTumnbailPath returns the relative path to the thumbnail of a bookmark in the filesystem
 func GetThumbnailPath(bookmark *BookmarkDTO) string {
 	return filepath.Join("thumb", strconv.Itoa(bookmark.ID))
@@ -85,3 +131,13 @@ func GetEbookPath(bookmark *BookmarkDTO) string {
 func GetArchivePath(bookmark *BookmarkDTO) string {
 	return filepath.Join("archive", strconv.Itoa(bookmark.ID))
 }
+
+// GetArchiveSnapshotsDir returns the relative path to the directory holding the previous archives of a bookmark
+func GetArchiveSnapshotsDir(bookmark *BookmarkDTO) string {
+	return filepath.Join("archive", "snapshots", strconv.Itoa(bookmark.ID))
+}
+
+// GetArchiveSnapshotPath returns the relative path to a previous archive of a bookmark in the filesystem
+func GetArchiveSnapshotPath(bookmark *BookmarkDTO, snapshotID DBID) string {
+	return filepath.Join(GetArchiveSnapshotsDir(bookmark), strconv.Itoa(int(snapshotID)))
+}
@@ -0,0 +1,40 @@
+package model
+
+import "strings"
+
+// CollectionMaxNameLength is the longest name a collection can have
+const CollectionMaxNameLength = 250
+
+// Collection is a folder of bookmarks. Collections are nested through their parent, and a
+// bookmark belongs to at most one collection.
+type Collection struct {
+	ID        DBID `db:"id"         json:"id"`
+	AccountID DBID `db:"account_id" json:"account_id"`
+	// ParentID is nil for the top level collections
+	ParentID *DBID  `db:"parent_id" json:"parent_id"`
+	Name     string `db:"name"      json:"name"`
+	// Position orders the collections sharing a parent, lowest first
+	Position   int    `db:"position"    json:"position"`
+	CreatedAt  string `db:"created_at"  json:"created_at"`
+	ModifiedAt string `db:"modified_at" json:"modified_at"`
+}
+
+// IsValid checks the name of the collection
+func (c Collection) IsValid() error {
+	name := strings.TrimSpace(c.Name)
+	if name == "" {
+		return NewValidationError("name", "collection name should not be empty")
+	}
+	if len(name) > CollectionMaxNameLength {
+		return NewValidationError("name", "collection name is too long")
+	}
+	return nil
+}
+
+// SameCollection reports whether two collection references, nil for the top level, are equal
+func SameCollection(a, b *DBID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"context"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -45,10 +46,15 @@ type DB interface {
 	GetBookmarksCount(ctx context.Context, opts DBGetBookmarksOptions) (int, error)
 
 	// DeleteBookmarks removes all record with matching ids from database.
-	DeleteBookmarks(ctx context.Context, ids ...int) error
+	// If accountID is not zero only bookmarks owned by that account are removed.
+	DeleteBookmarks(ctx context.Context, accountID DBID, ids ...int) error
 
 	// GetBookmark fetches bookmark based on its ID or URL.
-	GetBookmark(ctx context.Context, id int, url string) (BookmarkDTO, bool, error)
+	// If accountID is not zero only bookmarks owned by that account are returned.
+	GetBookmark(ctx context.Context, id int, url string, accountID DBID) (BookmarkDTO, bool, error)
+
+	// TransferBookmarks moves all bookmarks owned by an account to another one.
+	TransferBookmarks(ctx context.Context, fromAccountID, toAccountID DBID) error
 
 	// CreateAccount saves new account in database
 	CreateAccount(ctx context.Context, a Account) (*Account, error)
@@ -99,8 +105,267 @@ type DB interface {
 	// TagExists checks if a tag with the given ID exists in the database
 	TagExists(ctx context.Context, tagID int) (bool, error)
 
+	// GetTagByName fetch the tag with the given name or alias.
+	GetTagByName(ctx context.Context, name string) (Tag, bool, error)
+
+	// MergeTags moves the bookmarks, aliases and child tags of the source tags to the target
+	// tag in a single transaction. The source tags are removed and their names kept as aliases.
+	MergeTags(ctx context.Context, targetID int, sourceIDs ...int) error
+
+	// CreateTagAlias adds another name to a tag.
+	CreateTagAlias(ctx context.Context, alias TagAlias) (TagAlias, error)
+
+	// GetTagAliases fetch the aliases of a tag, or of every tag if zero.
+	GetTagAliases(ctx context.Context, tagID int) ([]TagAlias, error)
+
+	// DeleteTagAlias removes an alias.
+	DeleteTagAlias(ctx context.Context, id int) error
+
 	// BookmarkExists checks if a bookmark with the given ID exists in the database
 	BookmarkExists(ctx context.Context, bookmarkID int) (bool, error)
+
+	// CreateJob adds a new job to the queue.
+	CreateJob(ctx context.Context, job Job) (*Job, error)
+
+	// ClaimJob marks the oldest due pending job as running and returns it, nil if there is none.
+	ClaimJob(ctx context.Context) (*Job, error)
+
+	// UpdateJob saves the status, attempts, error and schedule of a job.
+	UpdateJob(ctx context.Context, job Job) error
+
+	// ListJobs fetch jobs from the queue, newest first.
+	ListJobs(ctx context.Context, opts DBListJobsOptions) ([]Job, error)
+
+	// RequeueRunningJobs moves the jobs left running by a stopped process back to pending.
+	RequeueRunningJobs(ctx context.Context) error
+
+	// CreateArchiveSnapshot records a new archive snapshot of a bookmark.
+	CreateArchiveSnapshot(ctx context.Context, snapshot ArchiveSnapshot) (*ArchiveSnapshot, error)
+
+	// GetArchiveSnapshots fetch the archive snapshots of a bookmark, newest first.
+	GetArchiveSnapshots(ctx context.Context, bookmarkID int) ([]ArchiveSnapshot, error)
+
+	// GetArchiveSnapshot fetch an archive snapshot by its ID.
+	GetArchiveSnapshot(ctx context.Context, id DBID) (*ArchiveSnapshot, bool, error)
+
+	// UpdateArchiveSnapshot updates the file path of an archive snapshot.
+	UpdateArchiveSnapshot(ctx context.Context, snapshot ArchiveSnapshot) error
+
+	// DeleteArchiveSnapshots removes the archive snapshots with matching ids.
+	DeleteArchiveSnapshots(ctx context.Context, ids ...DBID) error
+
+	// SaveArchiveText replaces the text indexed from the archive of a bookmark, an empty text
+	// removes it.
+	SaveArchiveText(ctx context.Context, bookmarkID int, text string) error
+
+	// GetArchiveTexts fetch the texts indexed from the archives of bookmarks, by bookmark ID.
+	GetArchiveTexts(ctx context.Context, bookmarkIDs []int) (map[int]string, error)
+
+	// DeleteArchiveTexts removes the texts indexed from the archives of bookmarks.
+	DeleteArchiveTexts(ctx context.Context, bookmarkIDs ...int) error
+
+	// CreateLinkCheck stores the result of checking a bookmark URL.
+	CreateLinkCheck(ctx context.Context, check LinkCheck) (*LinkCheck, error)
+
+	// GetLinkChecks fetch the link checks of a bookmark, newest first. A zero limit returns all of them.
+	GetLinkChecks(ctx context.Context, bookmarkID int, limit int) ([]LinkCheck, error)
+
+	// PruneLinkChecks removes the link checks of a bookmark except the newest keep ones.
+	PruneLinkChecks(ctx context.Context, bookmarkID int, keep int) error
+
+	// CreateAPIToken stores a new personal API token.
+	CreateAPIToken(ctx context.Context, token APIToken) (*APIToken, error)
+
+	// GetAPIToken fetch a personal API token by the hash of its value.
+	GetAPIToken(ctx context.Context, tokenHash string) (*APIToken, bool, error)
+
+	// ListAPITokens fetch the personal API tokens of an account, newest first.
+	ListAPITokens(ctx context.Context, accountID DBID) ([]APIToken, error)
+
+	// UpdateAPITokenLastUsed records when a personal API token was last used.
+	UpdateAPITokenLastUsed(ctx context.Context, id DBID, lastUsedAt string) error
+
+	// DeleteAPIToken removes a personal API token of an account.
+	DeleteAPIToken(ctx context.Context, accountID DBID, id DBID) error
+
+	// CreateAccountIdentity links an account to a user of an OpenID Connect provider.
+	CreateAccountIdentity(ctx context.Context, identity AccountIdentity) (*AccountIdentity, error)
+
+	// GetAccountIdentity fetch the link of a user of an OpenID Connect provider.
+	GetAccountIdentity(ctx context.Context, issuer, subject string) (*AccountIdentity, bool, error)
+
+	// ListAccountIdentities fetch the identities linked to an account, or to any account if zero.
+	ListAccountIdentities(ctx context.Context, accountID DBID) ([]AccountIdentity, error)
+
+	// DeleteAccountIdentity removes an identity linked to an account.
+	DeleteAccountIdentity(ctx context.Context, accountID DBID, id DBID) error
+
+	// CreateSession stores a new login session.
+	CreateSession(ctx context.Context, session Session) (*Session, error)
+
+	// GetSession fetch a login session by its ID.
+	GetSession(ctx context.Context, id string) (*Session, bool, error)
+
+	// ListSessions fetch the login sessions of an account, newest first.
+	ListSessions(ctx context.Context, accountID DBID) ([]Session, error)
+
+	// UpdateSessionLastUsed records when a login session was last used.
+	UpdateSessionLastUsed(ctx context.Context, id string, lastUsedAt string) error
+
+	// DeleteSession removes a login session of an account.
+	DeleteSession(ctx context.Context, accountID DBID, id string) error
+
+	// DeleteAccountSessions removes all the login sessions of an account.
+	DeleteAccountSessions(ctx context.Context, accountID DBID) error
+
+	// GetAccountTOTP fetch the TOTP second factor of an account.
+	GetAccountTOTP(ctx context.Context, accountID DBID) (*AccountTOTP, bool, error)
+
+	// SaveAccountTOTP stores the TOTP second factor of an account, replacing the existing one.
+	SaveAccountTOTP(ctx context.Context, totp AccountTOTP) error
+
+	// UpdateAccountTOTPStep records the time step of the last code used, returning false if
+	// a code of that step or a later one was already used.
+	UpdateAccountTOTPStep(ctx context.Context, accountID DBID, step int64) (bool, error)
+
+	// UpdateAccountTOTPRecoveryCodes replaces the recovery codes if they are still the previous
+	// ones, returning false if they were changed meanwhile.
+	UpdateAccountTOTPRecoveryCodes(ctx context.Context, accountID DBID, previous, codes TOTPRecoveryCodes) (bool, error)
+
+	// DeleteAccountTOTP removes the TOTP second factor of an account.
+	DeleteAccountTOTP(ctx context.Context, accountID DBID) error
+
+	// GetSetting fetch an instance wide setting.
+	GetSetting(ctx context.Context, name string) (string, bool, error)
+
+	// SetSetting stores an instance wide setting.
+	SetSetting(ctx context.Context, name, value string) error
+
+	// GetFeedToken fetch the feed token of an account.
+	GetFeedToken(ctx context.Context, accountID DBID) (*FeedToken, bool, error)
+
+	// GetFeedTokenByHash fetch a feed token by the hash of its value.
+	GetFeedTokenByHash(ctx context.Context, tokenHash string) (*FeedToken, bool, error)
+
+	// SaveFeedToken stores the feed token of an account, replacing the existing one.
+	SaveFeedToken(ctx context.Context, token FeedToken) error
+
+	// DeleteFeedToken removes the feed token of an account.
+	DeleteFeedToken(ctx context.Context, accountID DBID) error
+
+	// CreateSubscription stores a new feed subscription.
+	CreateSubscription(ctx context.Context, subscription Subscription) (*Subscription, error)
+
+	// GetSubscription fetch a feed subscription by its ID.
+	GetSubscription(ctx context.Context, id DBID) (*Subscription, bool, error)
+
+	// ListSubscriptions fetch the feed subscriptions of an account, or of every account if zero.
+	ListSubscriptions(ctx context.Context, accountID DBID) ([]Subscription, error)
+
+	// UpdateSubscription saves the title, options and poll status of a feed subscription.
+	UpdateSubscription(ctx context.Context, subscription Subscription) error
+
+	// DeleteSubscription removes a feed subscription of an account, or of any account if zero.
+	DeleteSubscription(ctx context.Context, accountID DBID, id DBID) error
+
+	// GetSubscriptionItems fetch the items already seen of a feed subscription.
+	GetSubscriptionItems(ctx context.Context, subscriptionID DBID) ([]SubscriptionItem, error)
+
+	// CreateSubscriptionItems records items of a feed subscription as seen.
+	CreateSubscriptionItems(ctx context.Context, items ...SubscriptionItem) error
+
+	// CreateWebhook stores a new webhook.
+	CreateWebhook(ctx context.Context, webhook Webhook) (*Webhook, error)
+
+	// GetWebhook fetch a webhook by its ID.
+	GetWebhook(ctx context.Context, id DBID) (*Webhook, bool, error)
+
+	// ListWebhooks fetch the webhooks matching the options.
+	ListWebhooks(ctx context.Context, opts DBListWebhooksOptions) ([]Webhook, error)
+
+	// DeleteWebhook removes a webhook with its deliveries.
+	DeleteWebhook(ctx context.Context, id DBID) error
+
+	// CreateWebhookDelivery stores a new webhook delivery.
+	CreateWebhookDelivery(ctx context.Context, delivery WebhookDelivery) (*WebhookDelivery, error)
+
+	// GetWebhookDelivery fetch a webhook delivery by its ID.
+	GetWebhookDelivery(ctx context.Context, id DBID) (*WebhookDelivery, bool, error)
+
+	// ListWebhookDeliveries fetch the latest deliveries of a webhook, newest first.
+	ListWebhookDeliveries(ctx context.Context, webhookID DBID, limit int) ([]WebhookDelivery, error)
+
+	// UpdateWebhookDelivery saves the status and last attempt of a webhook delivery.
+	UpdateWebhookDelivery(ctx context.Context, delivery WebhookDelivery) error
+
+	// DeleteWebhookDeliveries removes the deliveries of a webhook older than the given one.
+	DeleteWebhookDeliveries(ctx context.Context, webhookID DBID, beforeID DBID) error
+
+	// CreateCollection stores a new collection.
+	CreateCollection(ctx context.Context, collection Collection) (*Collection, error)
+
+	// GetCollection fetch a collection by its ID.
+	GetCollection(ctx context.Context, id DBID) (*Collection, bool, error)
+
+	// ListCollections fetch the collections of an account, or of every account if zero.
+	ListCollections(ctx context.Context, accountID DBID) ([]Collection, error)
+
+	// UpdateCollection saves the name, parent and position of a collection.
+	UpdateCollection(ctx context.Context, collection Collection) error
+
+	// UpdateCollections saves several collections in a single transaction.
+	UpdateCollections(ctx context.Context, collections ...Collection) error
+
+	// DeleteCollection removes a collection, moving its bookmarks and children to its parent.
+	DeleteCollection(ctx context.Context, id DBID) error
+
+	// SetBookmarksCollection moves bookmarks into a collection, or out of any if nil.
+	SetBookmarksCollection(ctx context.Context, collectionID *DBID, bookmarkIDs ...int) error
+
+	// CreateBookmarkRule stores a new bookmark rule.
+	CreateBookmarkRule(ctx context.Context, rule BookmarkRule) (*BookmarkRule, error)
+
+	// GetBookmarkRule fetch a bookmark rule by its ID.
+	GetBookmarkRule(ctx context.Context, id DBID) (*BookmarkRule, bool, error)
+
+	// ListBookmarkRules fetch the bookmark rules of an account, or of every account if zero.
+	ListBookmarkRules(ctx context.Context, accountID DBID) ([]BookmarkRule, error)
+
+	// UpdateBookmarkRule saves the name, state, conditions and actions of a bookmark rule.
+	UpdateBookmarkRule(ctx context.Context, rule BookmarkRule) error
+
+	// DeleteBookmarkRule removes a bookmark rule.
+	DeleteBookmarkRule(ctx context.Context, id DBID) error
+
+	// CreateSavedSearch stores a new saved search.
+	CreateSavedSearch(ctx context.Context, search SavedSearch) (*SavedSearch, error)
+
+	// GetSavedSearch fetch a saved search by its ID.
+	GetSavedSearch(ctx context.Context, id DBID) (*SavedSearch, bool, error)
+
+	// ListSavedSearches fetch the saved searches of an account, or of every account if zero.
+	ListSavedSearches(ctx context.Context, accountID DBID) ([]SavedSearch, error)
+
+	// UpdateSavedSearch saves the name and filter of a saved search.
+	UpdateSavedSearch(ctx context.Context, search SavedSearch) error
+
+	// DeleteSavedSearch removes a saved search.
+	DeleteSavedSearch(ctx context.Context, id DBID) error
+
+	// CreateHighlight stores a new highlight.
+	CreateHighlight(ctx context.Context, highlight Highlight) (*Highlight, error)
+
+	// GetHighlight fetch a highlight by its ID.
+	GetHighlight(ctx context.Context, id DBID) (*Highlight, bool, error)
+
+	// ListHighlights fetch the highlights matching the options.
+	ListHighlights(ctx context.Context, opts ListHighlightsOptions) ([]Highlight, error)
+
+	// UpdateHighlight saves the selector and note of a highlight.
+	UpdateHighlight(ctx context.Context, highlight Highlight) error
+
+	// DeleteHighlight removes a highlight.
+	DeleteHighlight(ctx context.Context, id DBID) error
 }
 
 // DBOrderMethod is the order method for getting bookmarks
@@ -113,18 +378,32 @@ const (
 	ByLastAdded
 	// ByLastModified is from latest modified to the oldest.
 	ByLastModified
+	// ByRelevance is from the best match of the text searched to the worst, then from newest
+	// addition to the oldest. Without text to search it is the same as ByLastAdded.
+	ByRelevance
 )
 
 // DBGetBookmarksOptions is options for fetching bookmarks from database.
 type DBGetBookmarksOptions struct {
+	// Filter bookmarks owned by this account, zero means any account
+	AccountID DBID
+	// Only fetch public bookmarks
+	PublicOnly   bool
 	IDs          []int
 	Tags         []string
 	ExcludedTags []string
 	Keyword      string
-	WithContent  bool
-	OrderMethod  DBOrderMethod
-	Limit        int
-	Offset       int
+	// Filter bookmarks by the result of their latest link check, empty means any status
+	LinkStatus LinkStatus
+	// Filter bookmarks directly in this collection, nil means any collection and zero the
+	// bookmarks in none
+	CollectionID *DBID
+	// Filter bookmarks added after this time, zero means any time
+	AddedAfter  time.Time
+	WithContent bool
+	OrderMethod DBOrderMethod
+	Limit       int
+	Offset      int
 }
 
 // DBListAccountsOptions is options for fetching accounts from database.
@@ -147,8 +426,23 @@ const (
 
 // DBListTagsOptions is options for fetching tags from database.
 type DBListTagsOptions struct {
+	// Skip tags only used by other accounts and count only this account bookmarks, zero means any account
+	AccountID         DBID
 	BookmarkID        int
 	WithBookmarkCount bool
 	OrderBy           DBTagOrderBy
 	Search            string
 }
+
+// DBListJobsOptions is options for fetching jobs from database.
+type DBListJobsOptions struct {
+	// Filter jobs by status, empty means any status
+	Status []JobStatus
+	Limit  int
+}
+
+// DBListWebhooksOptions is options for fetching webhooks from database.
+type DBListWebhooksOptions struct {
+	// Filter webhooks of these accounts, zero being the instance webhooks. Empty means any account
+	AccountIDs []DBID
+}
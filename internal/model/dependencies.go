@@ -17,6 +17,10 @@ type Dependencies interface {
 type DomainDependencies interface {
 	Auth() AuthDomain
 	SetAuth(auth AuthDomain)
+	OIDC() OIDCDomain
+	SetOIDC(oidc OIDCDomain)
+	TOTP() TOTPDomain
+	SetTOTP(totp TOTPDomain)
 	Accounts() AccountsDomain
 	SetAccounts(accounts AccountsDomain)
 	Bookmarks() BookmarksDomain
@@ -27,4 +31,22 @@ type DomainDependencies interface {
 	SetStorage(storage StorageDomain)
 	Tags() TagsDomain
 	SetTags(tags TagsDomain)
+	Jobs() JobsDomain
+	SetJobs(jobs JobsDomain)
+	LinkChecker() LinkCheckerDomain
+	SetLinkChecker(linkChecker LinkCheckerDomain)
+	Subscriptions() SubscriptionsDomain
+	SetSubscriptions(subscriptions SubscriptionsDomain)
+	Webhooks() WebhooksDomain
+	SetWebhooks(webhooks WebhooksDomain)
+	Collections() CollectionsDomain
+	SetCollections(collections CollectionsDomain)
+	Rules() RulesDomain
+	SetRules(rules RulesDomain)
+	SavedSearches() SavedSearchesDomain
+	SetSavedSearches(savedSearches SavedSearchesDomain)
+	Highlights() HighlightsDomain
+	SetHighlights(highlights HighlightsDomain)
+	Backup() BackupDomain
+	SetBackup(backup BackupDomain)
 }
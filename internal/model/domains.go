@@ -2,6 +2,7 @@ package model
 
 import (
 	"context"
+	"io"
 	"io/fs"
 	"os"
 	"time"
@@ -14,19 +15,57 @@ type BookmarksDomain interface {
 	HasEbook(b *BookmarkDTO) bool
 	HasArchive(b *BookmarkDTO) bool
 	HasThumbnail(b *BookmarkDTO) bool
-	GetBookmark(ctx context.Context, id DBID) (*BookmarkDTO, error)
-	GetBookmarks(ctx context.Context, ids []int) ([]BookmarkDTO, error)
+	// Methods taking an accountID only operate on bookmarks owned by that account,
+	// a zero accountID disables the ownership check.
+	GetBookmark(ctx context.Context, id DBID, accountID DBID) (*BookmarkDTO, error)
+	GetBookmarks(ctx context.Context, ids []int, accountID DBID) ([]BookmarkDTO, error)
+	ListBookmarks(ctx context.Context, opts ListBookmarksOptions) ([]BookmarkDTO, error)
+	CountBookmarks(ctx context.Context, opts ListBookmarksOptions) (int, error)
+	ExportBookmarks(ctx context.Context, exporter BookmarkExporter, opts ListBookmarksOptions) error
+	CreateBookmark(ctx context.Context, bookmark BookmarkDTO) (*BookmarkDTO, error)
+	UpdateBookmark(ctx context.Context, bookmark BookmarkDTO, accountID DBID) (*BookmarkDTO, error)
+	DeleteBookmarks(ctx context.Context, ids []int, accountID DBID) error
 	UpdateBookmarkCache(ctx context.Context, bookmark BookmarkDTO, keepMetadata bool, skipExist bool) (*BookmarkDTO, error)
-	BulkUpdateBookmarkTags(ctx context.Context, bookmarkIDs []int, tagIDs []int) error
-	AddTagToBookmark(ctx context.Context, bookmarkID int, tagID int) error
-	RemoveTagFromBookmark(ctx context.Context, bookmarkID int, tagID int) error
-	BookmarkExists(ctx context.Context, id int) (bool, error)
+	BulkUpdateBookmarkTags(ctx context.Context, bookmarkIDs []int, tagIDs []int, accountID DBID) error
+	AddTagToBookmark(ctx context.Context, bookmarkID int, tagID int, accountID DBID) error
+	RemoveTagFromBookmark(ctx context.Context, bookmarkID int, tagID int, accountID DBID) error
+	BookmarkExists(ctx context.Context, id int, accountID DBID) (bool, error)
 }
 
 type AuthDomain interface {
 	CheckToken(ctx context.Context, userJWT string) (*AccountDTO, error)
 	GetAccountFromCredentials(ctx context.Context, username, password string) (*AccountDTO, error)
-	CreateTokenForAccount(account *AccountDTO, expiration time.Time) (string, error)
+	CreateTokenForAccount(ctx context.Context, account *AccountDTO, expiration time.Time, userAgent string) (string, error)
+	CheckSession(ctx context.Context, userJWT string) (*AccountDTO, *Session, error)
+	ListSessions(ctx context.Context, accountID DBID) ([]Session, error)
+	RevokeSession(ctx context.Context, accountID DBID, id string) error
+	CreateAPIToken(ctx context.Context, account *AccountDTO, name string, scopes APITokenScopes, expiresAt *time.Time) (*APIToken, string, error)
+	ListAPITokens(ctx context.Context, accountID DBID) ([]APIToken, error)
+	RevokeAPIToken(ctx context.Context, accountID DBID, id DBID) error
+	CheckAPIToken(ctx context.Context, token string) (*AccountDTO, *APIToken, error)
+	CreateFeedToken(ctx context.Context, accountID DBID) (string, error)
+	GetFeedToken(ctx context.Context, accountID DBID) (*FeedToken, error)
+	RevokeFeedToken(ctx context.Context, accountID DBID) error
+	CheckFeedToken(ctx context.Context, token string) (*AccountDTO, error)
+}
+
+type TOTPDomain interface {
+	GetStatus(ctx context.Context, account *AccountDTO) (*TOTPStatus, error)
+	IsRequired(ctx context.Context, account *AccountDTO) (bool, error)
+	SetOwnersRequired(ctx context.Context, account *AccountDTO, required bool) error
+	Enroll(ctx context.Context, account *AccountDTO) (*TOTPEnrollment, error)
+	Enable(ctx context.Context, accountID DBID, code string) ([]string, error)
+	Disable(ctx context.Context, account *AccountDTO, code string) error
+	Verify(ctx context.Context, accountID DBID, code string) error
+	CreateLoginChallenge(account *AccountDTO, rememberMe bool) (string, error)
+	CheckLoginChallenge(ctx context.Context, challenge string) (*AccountDTO, bool, error)
+}
+
+type OIDCDomain interface {
+	AuthorizationURL(ctx context.Context, linkAccountID DBID) (string, string, error)
+	Login(ctx context.Context, code, state, authRequest string) (*AccountDTO, bool, error)
+	ListIdentities(ctx context.Context, accountID DBID) ([]AccountIdentity, error)
+	UnlinkIdentity(ctx context.Context, accountID DBID, id DBID) error
 }
 
 type AccountsDomain interface {
@@ -40,6 +79,14 @@ type AccountsDomain interface {
 type ArchiverDomain interface {
 	DownloadBookmarkArchive(book BookmarkDTO) (*BookmarkDTO, error)
 	GetBookmarkArchive(book *BookmarkDTO) (*warc.Archive, error)
+	ListSnapshots(ctx context.Context, book *BookmarkDTO) ([]ArchiveSnapshot, error)
+	GetSnapshot(ctx context.Context, book *BookmarkDTO, id DBID) (*ArchiveSnapshot, error)
+	GetSnapshotArchive(snapshot *ArchiveSnapshot) (*warc.Archive, error)
+	DeleteSnapshot(ctx context.Context, book *BookmarkDTO, id DBID) error
+	PreserveArchive(ctx context.Context, book *BookmarkDTO) error
+	RecordArchive(ctx context.Context, book *BookmarkDTO) error
+	IndexArchive(ctx context.Context, book *BookmarkDTO) error
+	IndexArchives(ctx context.Context, ids []int) error
 }
 
 type StorageDomain interface {
@@ -58,4 +105,95 @@ type TagsDomain interface {
 	UpdateTag(ctx context.Context, tag TagDTO) (TagDTO, error)
 	DeleteTag(ctx context.Context, id int) error
 	TagExists(ctx context.Context, id int) (bool, error)
+	MergeTags(ctx context.Context, targetID int, sourceIDs []int) (TagDTO, error)
+	ListTagAliases(ctx context.Context, tagID int) ([]TagAlias, error)
+	CreateTagAlias(ctx context.Context, alias TagAlias) (TagAlias, error)
+	DeleteTagAlias(ctx context.Context, tagID int, aliasID int) error
+}
+
+type LinkCheckerDomain interface {
+	CheckBookmark(ctx context.Context, book *BookmarkDTO) (*LinkCheck, error)
+	CheckBookmarks(ctx context.Context, ids []int) error
+	ListChecks(ctx context.Context, bookmarkID int) ([]LinkCheck, error)
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+}
+
+type SubscriptionsDomain interface {
+	CreateSubscription(ctx context.Context, subscription Subscription) (*Subscription, error)
+	GetSubscription(ctx context.Context, id DBID, accountID DBID) (*Subscription, error)
+	ListSubscriptions(ctx context.Context, accountID DBID) ([]Subscription, error)
+	DeleteSubscription(ctx context.Context, id DBID, accountID DBID) error
+	Poll(ctx context.Context, subscription Subscription) (*PollResult, error)
+	PollSubscriptions(ctx context.Context, ids []DBID) ([]PollResult, error)
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+}
+
+type WebhooksDomain interface {
+	CreateWebhook(ctx context.Context, account *AccountDTO, webhook Webhook) (*Webhook, error)
+	ListWebhooks(ctx context.Context, account *AccountDTO) ([]Webhook, error)
+	GetWebhook(ctx context.Context, account *AccountDTO, id DBID) (*Webhook, error)
+	DeleteWebhook(ctx context.Context, account *AccountDTO, id DBID) error
+	ListDeliveries(ctx context.Context, account *AccountDTO, webhookID DBID) ([]WebhookDelivery, error)
+	Redeliver(ctx context.Context, account *AccountDTO, webhookID DBID, deliveryID DBID) (*WebhookDelivery, error)
+	Emit(ctx context.Context, event WebhookEvent, accountID DBID, data any)
+	EmitCreatedTags(ctx context.Context, bookmarks ...BookmarkDTO)
+	Deliver(ctx context.Context, deliveryID DBID) error
+}
+
+type CollectionsDomain interface {
+	ListCollections(ctx context.Context, account *AccountDTO) ([]Collection, error)
+	GetCollection(ctx context.Context, account *AccountDTO, id DBID) (*Collection, error)
+	CreateCollection(ctx context.Context, account *AccountDTO, collection Collection) (*Collection, error)
+	RenameCollection(ctx context.Context, account *AccountDTO, id DBID, name string) (*Collection, error)
+	MoveCollection(ctx context.Context, account *AccountDTO, id DBID, parentID *DBID, position int) (*Collection, error)
+	DeleteCollection(ctx context.Context, account *AccountDTO, id DBID) error
+	MoveBookmarks(ctx context.Context, account *AccountDTO, collectionID *DBID, bookmarkIDs []int) error
+}
+
+type RulesDomain interface {
+	ListRules(ctx context.Context, account *AccountDTO) ([]BookmarkRule, error)
+	GetRule(ctx context.Context, account *AccountDTO, id DBID) (*BookmarkRule, error)
+	CreateRule(ctx context.Context, account *AccountDTO, rule BookmarkRule) (*BookmarkRule, error)
+	UpdateRule(ctx context.Context, account *AccountDTO, rule BookmarkRule) (*BookmarkRule, error)
+	DeleteRule(ctx context.Context, account *AccountDTO, id DBID) error
+	Evaluate(ctx context.Context, accountID DBID, input RuleInput) (RuleActions, error)
+	Apply(ctx context.Context, bookmark *BookmarkDTO, contentType string) error
+	DryRun(ctx context.Context, account *AccountDTO, rule BookmarkRule) ([]BookmarkDTO, error)
+}
+
+type SavedSearchesDomain interface {
+	ListSearches(ctx context.Context, account *AccountDTO) ([]SavedSearch, error)
+	GetSearch(ctx context.Context, account *AccountDTO, id DBID) (*SavedSearch, error)
+	CreateSearch(ctx context.Context, account *AccountDTO, search SavedSearch) (*SavedSearch, error)
+	UpdateSearch(ctx context.Context, account *AccountDTO, search SavedSearch) (*SavedSearch, error)
+	DeleteSearch(ctx context.Context, account *AccountDTO, id DBID) error
+	ApplySearch(ctx context.Context, account *AccountDTO, id DBID, opts *ListBookmarksOptions) error
+}
+
+type HighlightsDomain interface {
+	ListHighlights(ctx context.Context, account *AccountDTO, bookmark *BookmarkDTO) ([]Highlight, error)
+	GetHighlight(ctx context.Context, account *AccountDTO, bookmark *BookmarkDTO, id DBID) (*Highlight, error)
+	CreateHighlight(ctx context.Context, account *AccountDTO, bookmark *BookmarkDTO, highlight Highlight) (*Highlight, error)
+	UpdateHighlight(ctx context.Context, account *AccountDTO, bookmark *BookmarkDTO, highlight Highlight) (*Highlight, error)
+	DeleteHighlight(ctx context.Context, account *AccountDTO, bookmark *BookmarkDTO, id DBID) error
+	ExportHighlights(ctx context.Context, account *AccountDTO) ([]BookmarkHighlights, error)
+}
+
+// JobHandler runs a job from the queue, returning an error schedules a retry
+type JobHandler func(ctx context.Context, job Job) error
+
+type JobsDomain interface {
+	Enqueue(ctx context.Context, jobType JobType, payload any) (*Job, error)
+	ListJobs(ctx context.Context, opts ListJobsOptions) ([]Job, error)
+	RegisterHandler(jobType JobType, handler JobHandler)
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+}
+
+type BackupDomain interface {
+	Backup(ctx context.Context, w io.Writer, passphrase string) (*BackupManifest, error)
+	Verify(ctx context.Context, r io.Reader, passphrase string) (*BackupManifest, error)
+	Restore(ctx context.Context, r io.ReadSeeker, passphrase string) (*BackupManifest, error)
 }
@@ -6,6 +6,7 @@ var (
 	ErrBookmarkNotFound  = errors.New("bookmark not found")
 	ErrBookmarkInvalidID = errors.New("invalid bookmark ID")
 	ErrTagNotFound       = errors.New("tag not found")
+	ErrBookmarkRejected  = errors.New("bookmark rejected by a rule")
 
 	ErrUnauthorized  = errors.New("unauthorized user")
 	ErrNotFound      = errors.New("not found")
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ExportFormat is a file format bookmarks can be exported to
+type ExportFormat string
+
+const (
+	// ExportFormatNetscape is the HTML bookmark file understood by browsers
+	ExportFormatNetscape ExportFormat = "netscape"
+	// ExportFormatJSON is a lossless dump of the bookmarks, content included
+	ExportFormatJSON ExportFormat = "json"
+	// ExportFormatCSV is a spreadsheet of the bookmarks metadata
+	ExportFormatCSV ExportFormat = "csv"
+	// ExportFormatMarkdown is one Markdown file per bookmark with its readable content
+	ExportFormatMarkdown ExportFormat = "markdown"
+)
+
+var exportFormats = []ExportFormat{ExportFormatNetscape, ExportFormatJSON, ExportFormatCSV, ExportFormatMarkdown}
+
+// IsValid checks that the format is a known one
+func (f ExportFormat) IsValid() error {
+	if !slices.Contains(exportFormats, f) {
+		return fmt.Errorf("invalid export format: %s", f)
+	}
+	return nil
+}
+
+// BookmarkExporter writes bookmarks into an export, one at a time.
+type BookmarkExporter interface {
+	WriteBookmark(bookmark BookmarkDTO) error
+	// Close finishes the export, it must be called once every bookmark has been written.
+	Close() error
+}
+
+// CollectionsExporter is implemented by the exporters keeping the collections of the
+// bookmarks. They are given the collections before the first bookmark is written.
+type CollectionsExporter interface {
+	SetCollections(collections []Collection)
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+)
+
+// FeedTokenPrefix starts every feed token, telling them apart from API tokens
+const FeedTokenPrefix = "shiori_feed_"
+
+// FeedFormat is a syndication format bookmarks can be subscribed to
+type FeedFormat string
+
+const (
+	// FeedFormatAtom is the Atom 1.0 format
+	FeedFormatAtom FeedFormat = "atom"
+	// FeedFormatRSS is the RSS 2.0 format
+	FeedFormatRSS FeedFormat = "rss"
+)
+
+var feedFormats = []FeedFormat{FeedFormatAtom, FeedFormatRSS}
+
+// IsValid checks that the format is a known one
+func (f FeedFormat) IsValid() error {
+	if !slices.Contains(feedFormats, f) {
+		return fmt.Errorf("invalid feed format: %s", f)
+	}
+	return nil
+}
+
+// FeedToken is the secret giving feed readers access to the private feed of an account,
+// only the hash of the token is stored
+type FeedToken struct {
+	AccountID DBID   `db:"account_id" json:"-"`
+	TokenHash string `db:"token_hash" json:"-"`
+	CreatedAt string `db:"created_at" json:"created_at"`
+}
+
+// Feed is the metadata of a feed of bookmarks
+type Feed struct {
+	Title string
+	// Absolute URL of the feed itself
+	SelfURL string
+	// Absolute URL of the Shiori instance, ending with a slash
+	BaseURL string
+	// Use the readable content of the bookmarks instead of their excerpt
+	FullContent bool
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Highlight is a passage of the readable content of a bookmark highlighted by an account,
+// with an optional note.
+type Highlight struct {
+	ID         DBID              `db:"id"          json:"id"`
+	BookmarkID int               `db:"bookmark_id" json:"bookmark_id"`
+	AccountID  DBID              `db:"account_id"  json:"account_id"`
+	Selector   HighlightSelector `db:"selector"    json:"selector"`
+	Note       string            `db:"note"        json:"note"`
+	CreatedAt  string            `db:"created_at"  json:"created_at"`
+	ModifiedAt string            `db:"modified_at" json:"modified_at"`
+}
+
+// IsValid checks the highlight has a valid selector
+func (h Highlight) IsValid() error {
+	return h.Selector.IsValid()
+}
+
+// HighlightSelector locates the highlighted passage in the text of the readable content, the
+// text of its HTML without the tags. It follows the selectors of W3C Web Annotations: the
+// quote is what is highlighted, the position tells apart the passages quoting the same text.
+type HighlightSelector struct {
+	Quote    TextQuoteSelector     `json:"quote"`
+	Position *TextPositionSelector `json:"position,omitempty"`
+}
+
+// TextQuoteSelector is the highlighted text, with some of the text before and after it.
+type TextQuoteSelector struct {
+	Exact  string `json:"exact"`
+	Prefix string `json:"prefix,omitempty"`
+	Suffix string `json:"suffix,omitempty"`
+}
+
+// TextPositionSelector is the range of the highlighted text, counted in characters from the
+// start of the text. The end is excluded.
+type TextPositionSelector struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// IsValid checks the selector quotes some text and its position is a range
+func (s HighlightSelector) IsValid() error {
+	if strings.TrimSpace(s.Quote.Exact) == "" {
+		return NewValidationError("selector", "highlighted text should not be empty")
+	}
+	if s.Position != nil && (s.Position.Start < 0 || s.Position.End <= s.Position.Start) {
+		return NewValidationError("selector", "position should start before its end")
+	}
+	return nil
+}
+
+func (s *HighlightSelector) Scan(value interface{}) error {
+	return scanJSON(value, s)
+}
+
+func (s HighlightSelector) Value() (driver.Value, error) {
+	return valueJSON(s)
+}
+
+// ListHighlightsOptions filters the highlights, the zero fields don't filter anything.
+type ListHighlightsOptions struct {
+	BookmarkID int
+	AccountID  DBID
+}
+
+// BookmarkHighlights are the highlights of an account on one of the bookmarks, as exported.
+type BookmarkHighlights struct {
+	ID         int         `json:"id"`
+	URL        string      `json:"url"`
+	Title      string      `json:"title"`
+	Highlights []Highlight `json:"highlights"`
+}
+
+// highlightsExportFormats are the export formats highlights can be exported to
+var highlightsExportFormats = []ExportFormat{ExportFormatMarkdown, ExportFormatJSON}
+
+// ValidateHighlightsExportFormat checks highlights can be exported to the format
+func ValidateHighlightsExportFormat(format ExportFormat) error {
+	if !slices.Contains(highlightsExportFormats, format) {
+		return fmt.Errorf("invalid highlights export format: %s", format)
+	}
+	return nil
+}
@@ -1,6 +1,9 @@
 package model
 
-import "net/http"
+import (
+	"errors"
+	"net/http"
+)
 
 const (
 	// ContextAccountKey is the key used to store the account model in the gin context.
@@ -12,6 +15,10 @@ const (
 	AuthorizationTokenType = "Bearer"
 )
 
+// ErrRequestHandled is returned by middlewares that already sent the response, like a rejected
+// request, so the handler is skipped.
+var ErrRequestHandled = errors.New("request handled by middleware")
+
 // WebContext represents the context of an HTTP request
 type WebContext interface {
 	Request() *http.Request
@@ -20,6 +27,8 @@ type WebContext interface {
 	GetAccount() *AccountDTO
 	SetAccount(*AccountDTO)
 	UserIsLogged() bool
+	GetSessionID() string
+	SetSessionID(id string)
 	GetRequestID() string
 	SetRequestID(id string)
 }
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+)
+
+// JobType identifies the handler that runs a job
+type JobType string
+
+const (
+	// JobTypeProcessBookmark downloads a bookmark and runs it through the processing pipeline:
+	// readability, thumbnail, ebook and offline archive.
+	JobTypeProcessBookmark JobType = "process_bookmark"
+
+	// JobTypeCheckLinks requests the URL of bookmarks and stores the result of each check.
+	JobTypeCheckLinks JobType = "check_links"
+
+	// JobTypePollSubscriptions fetches subscribed feeds and saves their new items as bookmarks.
+	JobTypePollSubscriptions JobType = "poll_subscriptions"
+
+	// JobTypeDeliverWebhook sends a webhook delivery, failed attempts are retried with the job.
+	JobTypeDeliverWebhook JobType = "deliver_webhook"
+
+	// JobTypeIndexArchives extracts the text of bookmark archives and indexes it for search.
+	JobTypeIndexArchives JobType = "index_archives"
+)
+
+// JobStatus is the state of a job in the queue
+type JobStatus string
+
+const (
+	JobStatusPending   JobStatus = "pending"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusCompleted JobStatus = "completed"
+	JobStatusFailed    JobStatus = "failed"
+)
+
+var jobStatuses = []JobStatus{JobStatusPending, JobStatusRunning, JobStatusCompleted, JobStatusFailed}
+
+// IsValid checks that the status is a known one
+func (s JobStatus) IsValid() error {
+	if !slices.Contains(jobStatuses, s) {
+		return fmt.Errorf("invalid job status: %s", s)
+	}
+	return nil
+}
+
+// Job is a unit of background work stored in the database
+type Job struct {
+	ID          DBID      `db:"id"           json:"id"`
+	Type        JobType   `db:"type"         json:"type"`
+	Payload     string    `db:"payload"      json:"payload"`
+	Status      JobStatus `db:"status"       json:"status"`
+	Attempts    int       `db:"attempts"     json:"attempts"`
+	MaxAttempts int       `db:"max_attempts" json:"max_attempts"`
+	LastError   string    `db:"last_error"   json:"last_error"`
+	RunAt       string    `db:"run_at"       json:"run_at"`
+	CreatedAt   string    `db:"created_at"   json:"created_at"`
+	UpdatedAt   string    `db:"updated_at"   json:"updated_at"`
+}
+
+// ProcessBookmarkJobPayload is the payload of a JobTypeProcessBookmark job
+type ProcessBookmarkJobPayload struct {
+	BookmarkID    int  `json:"bookmark_id"`
+	KeepTitle     bool `json:"keep_title"`
+	KeepExcerpt   bool `json:"keep_excerpt"`
+	CreateArchive bool `json:"create_archive"`
+	CreateEbook   bool `json:"create_ebook"`
+}
+
+// ListJobsOptions is options for listing jobs
+type ListJobsOptions struct {
+	Status []JobStatus
+	Limit  int
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+)
+
+// LinkStatus is the health of a bookmark URL according to its latest check
+type LinkStatus string
+
+const (
+	// LinkStatusOK is a URL answering with a successful status
+	LinkStatusOK LinkStatus = "ok"
+	// LinkStatusBroken is a URL that can't be reached or answers with a 4xx/5xx status
+	LinkStatusBroken LinkStatus = "broken"
+	// LinkStatusRedirected is a URL answering successfully from a different location
+	LinkStatusRedirected LinkStatus = "redirected"
+	// LinkStatusUnchecked is a URL that has never been checked
+	LinkStatusUnchecked LinkStatus = "unchecked"
+)
+
+var linkStatuses = []LinkStatus{LinkStatusOK, LinkStatusBroken, LinkStatusRedirected, LinkStatusUnchecked}
+
+// IsValid checks that the status is a known one
+func (s LinkStatus) IsValid() error {
+	if !slices.Contains(linkStatuses, s) {
+		return fmt.Errorf("invalid link status: %s", s)
+	}
+	return nil
+}
+
+// LinkCheck is the result of requesting the URL of a bookmark
+type LinkCheck struct {
+	ID         DBID   `db:"id"          json:"id"`
+	BookmarkID int    `db:"bookmark_id" json:"bookmark_id"`
+	URL        string `db:"url"         json:"url"`
+	// StatusCode is zero when no response was received
+	StatusCode int    `db:"status_code" json:"status_code"`
+	FinalURL   string `db:"final_url"   json:"final_url"`
+	LatencyMs  int64  `db:"latency_ms"  json:"latency_ms"`
+	Error      string `db:"error"       json:"error"`
+	CheckedAt  string `db:"checked_at"  json:"checked_at"`
+}
+
+// Status returns the link status the check represents
+func (c LinkCheck) Status() LinkStatus {
+	switch {
+	case c.StatusCode == 0 || c.StatusCode >= 400:
+		return LinkStatusBroken
+	case c.FinalURL != "" && c.FinalURL != c.URL:
+		return LinkStatusRedirected
+	default:
+		return LinkStatusOK
+	}
+}
+
+// CheckLinksJobPayload is the payload of a JobTypeCheckLinks job
+type CheckLinksJobPayload struct {
+	// Bookmarks to check, empty checks every bookmark
+	BookmarkIDs []int `json:"bookmark_ids"`
+}
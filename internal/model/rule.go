@@ -0,0 +1,242 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// BookmarkRule is a rule of an account checked when one of its bookmarks is saved or its
+// content is downloaded. The actions are applied when every condition matches.
+type BookmarkRule struct {
+	ID         DBID           `db:"id"          json:"id"`
+	AccountID  DBID           `db:"account_id"  json:"account_id"`
+	Name       string         `db:"name"        json:"name"`
+	Enabled    bool           `db:"enabled"     json:"enabled"`
+	Conditions RuleConditions `db:"conditions"  json:"conditions"`
+	Actions    RuleActions    `db:"actions"     json:"actions"`
+	CreatedAt  string         `db:"created_at"  json:"created_at"`
+	ModifiedAt string         `db:"modified_at" json:"modified_at"`
+}
+
+// IsValid checks the rule has a name, conditions and actions, and that its URL pattern compiles
+func (r BookmarkRule) IsValid() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return NewValidationError("name", "rule name should not be empty")
+	}
+	if err := r.Conditions.IsValid(); err != nil {
+		return err
+	}
+	if r.Actions.IsEmpty() {
+		return NewValidationError("actions", "rule should have at least one action")
+	}
+	return nil
+}
+
+// RuleInput is what the conditions of a rule are checked against. The content type is only
+// known while the content of the bookmark is downloaded.
+type RuleInput struct {
+	URL         string
+	Title       string
+	Excerpt     string
+	Content     string
+	ContentType string
+}
+
+// NewRuleInput returns the input of a bookmark, with the content type it was downloaded with
+func NewRuleInput(bookmark BookmarkDTO, contentType string) RuleInput {
+	return RuleInput{
+		URL:         bookmark.URL,
+		Title:       bookmark.Title,
+		Excerpt:     bookmark.Excerpt,
+		Content:     bookmark.Content,
+		ContentType: contentType,
+	}
+}
+
+// RuleConditions are the conditions of a rule, the empty ones are ignored. Text is compared
+// ignoring case.
+type RuleConditions struct {
+	// Host of the URL, its subdomains match too
+	Host string `json:"host,omitempty"`
+	// URLPattern is a regular expression matched against the whole URL
+	URLPattern string `json:"url_pattern,omitempty"`
+	// Title should contain this text
+	Title string `json:"title,omitempty"`
+	// Keywords should all appear in the title, excerpt or content
+	Keywords []string `json:"keywords,omitempty"`
+	// ContentType is the prefix of the media type, like application/pdf or image/
+	ContentType string `json:"content_type,omitempty"`
+}
+
+// IsEmpty reports whether there is no condition to check
+func (c RuleConditions) IsEmpty() bool {
+	return c.Host == "" && c.URLPattern == "" && c.Title == "" && len(c.Keywords) == 0 && c.ContentType == ""
+}
+
+// IsValid checks there is at least one condition and that the URL pattern compiles
+func (c RuleConditions) IsValid() error {
+	if c.IsEmpty() {
+		return NewValidationError("conditions", "rule should have at least one condition")
+	}
+	if c.URLPattern != "" {
+		if _, err := regexp.Compile(c.URLPattern); err != nil {
+			return NewValidationError("conditions", "invalid URL pattern: "+err.Error())
+		}
+	}
+	return nil
+}
+
+// Match reports whether every condition matches the input
+func (c RuleConditions) Match(input RuleInput) bool {
+	if c.Host != "" {
+		parsed, err := url.Parse(input.URL)
+		if err != nil {
+			return false
+		}
+		host, expected := strings.ToLower(parsed.Hostname()), strings.ToLower(c.Host)
+		if host != expected && !strings.HasSuffix(host, "."+expected) {
+			return false
+		}
+	}
+
+	if c.URLPattern != "" {
+		pattern, err := regexp.Compile(c.URLPattern)
+		if err != nil || !pattern.MatchString(input.URL) {
+			return false
+		}
+	}
+
+	if c.Title != "" && !strings.Contains(strings.ToLower(input.Title), strings.ToLower(c.Title)) {
+		return false
+	}
+
+	if len(c.Keywords) > 0 {
+		text := strings.ToLower(input.Title + "\n" + input.Excerpt + "\n" + input.Content)
+		for _, keyword := range c.Keywords {
+			if !strings.Contains(text, strings.ToLower(keyword)) {
+				return false
+			}
+		}
+	}
+
+	if c.ContentType != "" && !strings.HasPrefix(strings.ToLower(input.ContentType), strings.ToLower(c.ContentType)) {
+		return false
+	}
+
+	return true
+}
+
+func (c *RuleConditions) Scan(value interface{}) error {
+	return scanJSON(value, c)
+}
+
+func (c RuleConditions) Value() (driver.Value, error) {
+	return valueJSON(c)
+}
+
+// RuleActions are applied to the bookmarks matching a rule. The options left empty keep the
+// value of the bookmark.
+type RuleActions struct {
+	// AddTags are added to the tags of the bookmark
+	AddTags       []string `json:"add_tags,omitempty"`
+	Public        *bool    `json:"public,omitempty"`
+	CreateArchive *bool    `json:"create_archive,omitempty"`
+	CreateEbook   *bool    `json:"create_ebook,omitempty"`
+	// Reject refuses to save the bookmark
+	Reject bool `json:"reject,omitempty"`
+}
+
+// IsEmpty reports whether there is no action to apply
+func (a RuleActions) IsEmpty() bool {
+	return len(a.AddTags) == 0 && a.Public == nil && a.CreateArchive == nil && a.CreateEbook == nil && !a.Reject
+}
+
+// Merge returns the actions followed by the other ones, whose options win
+func (a RuleActions) Merge(other RuleActions) RuleActions {
+	merged := a
+	merged.AddTags = append(append([]string{}, a.AddTags...), other.AddTags...)
+	if other.Public != nil {
+		merged.Public = other.Public
+	}
+	if other.CreateArchive != nil {
+		merged.CreateArchive = other.CreateArchive
+	}
+	if other.CreateEbook != nil {
+		merged.CreateEbook = other.CreateEbook
+	}
+	merged.Reject = a.Reject || other.Reject
+	return merged
+}
+
+// ApplyTo adds the tags the bookmark doesn't have yet and sets its options
+func (a RuleActions) ApplyTo(bookmark *BookmarkDTO) {
+	for _, name := range a.AddTags {
+		name = NormalizeTagName(name)
+		if name == "" {
+			continue
+		}
+
+		exists := false
+		for _, tag := range bookmark.Tags {
+			if NormalizeTagName(tag.Name) == name && !tag.Deleted {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			bookmark.Tags = append(bookmark.Tags, TagDTO{Tag: Tag{Name: name}})
+		}
+	}
+
+	if a.Public != nil {
+		bookmark.Public = 0
+		if *a.Public {
+			bookmark.Public = 1
+		}
+	}
+	if a.CreateArchive != nil {
+		bookmark.CreateArchive = *a.CreateArchive
+	}
+	if a.CreateEbook != nil {
+		bookmark.CreateEbook = *a.CreateEbook
+	}
+}
+
+func (a *RuleActions) Scan(value interface{}) error {
+	return scanJSON(value, a)
+}
+
+func (a RuleActions) Value() (driver.Value, error) {
+	return valueJSON(a)
+}
+
+// scanJSON decodes a column holding JSON into dst
+func scanJSON(value interface{}, dst any) error {
+	var raw []byte
+	switch v := value.(type) {
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type: %T", v)
+	}
+
+	if len(raw) == 0 {
+		return nil
+	}
+	return json.Unmarshal(raw, dst)
+}
+
+// valueJSON encodes a value into a JSON column
+func valueJSON(value any) (driver.Value, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return string(raw), nil
+}
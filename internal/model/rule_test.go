@@ -0,0 +1,73 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleConditions_Match(t *testing.T) {
+	input := RuleInput{
+		URL:         "https://blog.golang.org/generics?ref=feed",
+		Title:       "An Introduction To Generics",
+		Excerpt:     "Type parameters",
+		Content:     "Go 1.18 adds generics and fuzzing.",
+		ContentType: "text/html; charset=utf-8",
+	}
+
+	tests := []struct {
+		name       string
+		conditions RuleConditions
+		want       bool
+	}{
+		{name: "host", conditions: RuleConditions{Host: "blog.golang.org"}, want: true},
+		{name: "parent host", conditions: RuleConditions{Host: "GOLANG.org"}, want: true},
+		{name: "other host", conditions: RuleConditions{Host: "lang.org"}, want: false},
+		{name: "url pattern", conditions: RuleConditions{URLPattern: `\?ref=`}, want: true},
+		{name: "title ignoring case", conditions: RuleConditions{Title: "introduction to"}, want: true},
+		{name: "keywords in excerpt and content", conditions: RuleConditions{Keywords: []string{"type parameters", "FUZZING"}}, want: true},
+		{name: "missing keyword", conditions: RuleConditions{Keywords: []string{"generics", "modules"}}, want: false},
+		{name: "content type prefix", conditions: RuleConditions{ContentType: "text/html"}, want: true},
+		{name: "every condition", conditions: RuleConditions{Host: "golang.org", ContentType: "application/pdf"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.conditions.Match(input))
+		})
+	}
+
+	t.Run("unknown content type", func(t *testing.T) {
+		assert.False(t, RuleConditions{ContentType: "text/"}.Match(RuleInput{URL: input.URL}))
+	})
+}
+
+func TestBookmarkRule_IsValid(t *testing.T) {
+	actions := RuleActions{AddTags: []string{"go"}}
+
+	require.NoError(t, BookmarkRule{Name: "Go", Conditions: RuleConditions{Host: "go.dev"}, Actions: actions}.IsValid())
+	require.Error(t, BookmarkRule{Name: " ", Conditions: RuleConditions{Host: "go.dev"}, Actions: actions}.IsValid())
+	require.Error(t, BookmarkRule{Name: "Go", Actions: actions}.IsValid())
+	require.Error(t, BookmarkRule{Name: "Go", Conditions: RuleConditions{URLPattern: "("}, Actions: actions}.IsValid())
+	require.Error(t, BookmarkRule{Name: "Go", Conditions: RuleConditions{Host: "go.dev"}}.IsValid())
+}
+
+func TestRuleActions_MergeAndApply(t *testing.T) {
+	enabled, disabled := true, false
+
+	actions := RuleActions{AddTags: []string{"Go"}, Public: &enabled, CreateArchive: &enabled}.
+		Merge(RuleActions{AddTags: []string{"news", "go"}, CreateArchive: &disabled})
+	assert.Equal(t, []string{"Go", "news", "go"}, actions.AddTags)
+	assert.False(t, *actions.CreateArchive)
+	assert.False(t, actions.Reject)
+	assert.True(t, actions.Merge(RuleActions{Reject: true}).Reject)
+
+	bookmark := BookmarkDTO{CreateArchive: true, Tags: []TagDTO{{Tag: Tag{Name: "go"}}}}
+	actions.ApplyTo(&bookmark)
+	require.Len(t, bookmark.Tags, 2)
+	assert.Equal(t, "news", bookmark.Tags[1].Name)
+	assert.Equal(t, 1, bookmark.Public)
+	assert.False(t, bookmark.CreateArchive)
+	assert.False(t, bookmark.CreateEbook)
+}
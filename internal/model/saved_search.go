@@ -0,0 +1,86 @@
+package model
+
+import (
+	"database/sql/driver"
+	"strings"
+	"time"
+)
+
+// SavedSearch is a named bookmark search of an account, listed like a collection whose
+// bookmarks are the ones matching its filter.
+type SavedSearch struct {
+	ID         DBID         `db:"id"          json:"id"`
+	AccountID  DBID         `db:"account_id"  json:"account_id"`
+	Name       string       `db:"name"        json:"name"`
+	Filter     SearchFilter `db:"filter"      json:"filter"`
+	CreatedAt  string       `db:"created_at"  json:"created_at"`
+	ModifiedAt string       `db:"modified_at" json:"modified_at"`
+}
+
+// IsValid checks the saved search has a name and a valid filter
+func (s SavedSearch) IsValid() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return NewValidationError("name", "saved search name should not be empty")
+	}
+	return s.Filter.IsValid()
+}
+
+// SearchFilter is the filter of a saved search, the empty fields don't filter anything.
+type SearchFilter struct {
+	Keyword      string     `json:"keyword,omitempty"`
+	Tags         []string   `json:"tags,omitempty"`
+	ExcludedTags []string   `json:"excluded_tags,omitempty"`
+	LinkStatus   LinkStatus `json:"link_status,omitempty"`
+	// Bookmarks directly in this collection, zero for the ones in none
+	CollectionID *DBID `json:"collection_id,omitempty"`
+	// Bookmarks added in the last days, counted from the time the search is run
+	AddedWithinDays int `json:"added_within_days,omitempty"`
+}
+
+// IsValid checks the keyword is a valid search query, the link status is known and the
+// number of days isn't negative
+func (f SearchFilter) IsValid() error {
+	if _, err := ParseSearchQuery(f.Keyword); err != nil {
+		return err
+	}
+	if f.LinkStatus != "" {
+		if err := f.LinkStatus.IsValid(); err != nil {
+			return NewValidationError("link_status", err.Error())
+		}
+	}
+	if f.AddedWithinDays < 0 {
+		return NewValidationError("added_within_days", "added_within_days should not be negative")
+	}
+	return nil
+}
+
+// ApplyTo narrows the listing options down to the bookmarks matching the filter at the given
+// time. The tags and the terms of the keyword are added to the ones of the options, the link
+// status and collection of the options are kept if they are set.
+func (f SearchFilter) ApplyTo(opts *ListBookmarksOptions, now time.Time) {
+	opts.Tags = append(opts.Tags, f.Tags...)
+	opts.ExcludedTags = append(opts.ExcludedTags, f.ExcludedTags...)
+
+	opts.Keyword = strings.TrimSpace(f.Keyword + " " + opts.Keyword)
+	if opts.LinkStatus == "" {
+		opts.LinkStatus = f.LinkStatus
+	}
+	if opts.CollectionID == nil {
+		opts.CollectionID = f.CollectionID
+	}
+
+	if f.AddedWithinDays > 0 {
+		after := now.UTC().AddDate(0, 0, -f.AddedWithinDays)
+		if after.After(opts.AddedAfter) {
+			opts.AddedAfter = after
+		}
+	}
+}
+
+func (f *SearchFilter) Scan(value interface{}) error {
+	return scanJSON(value, f)
+}
+
+func (f SearchFilter) Value() (driver.Value, error) {
+	return valueJSON(f)
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchFilter_ApplyTo(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	collectionID := DBID(3)
+
+	t.Run("empty options", func(t *testing.T) {
+		filter := SearchFilter{
+			Keyword:         "go",
+			Tags:            []string{"golang"},
+			ExcludedTags:    []string{"draft"},
+			LinkStatus:      LinkStatusBroken,
+			CollectionID:    &collectionID,
+			AddedWithinDays: 7,
+		}
+
+		opts := ListBookmarksOptions{}
+		filter.ApplyTo(&opts, now)
+		require.Equal(t, "go", opts.Keyword)
+		require.Equal(t, []string{"golang"}, opts.Tags)
+		require.Equal(t, []string{"draft"}, opts.ExcludedTags)
+		require.Equal(t, LinkStatusBroken, opts.LinkStatus)
+		require.Equal(t, &collectionID, opts.CollectionID)
+		require.Equal(t, time.Date(2024, 3, 3, 12, 0, 0, 0, time.UTC), opts.AddedAfter)
+	})
+
+	t.Run("options are kept", func(t *testing.T) {
+		filter := SearchFilter{Keyword: "go", Tags: []string{"golang"}, AddedWithinDays: 7}
+
+		after := time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+		opts := ListBookmarksOptions{Keyword: "rust", Tags: []string{"blog"}, AddedAfter: after}
+		filter.ApplyTo(&opts, now)
+		require.Equal(t, "go rust", opts.Keyword)
+		require.Equal(t, []string{"blog", "golang"}, opts.Tags)
+		require.Equal(t, after, opts.AddedAfter)
+	})
+}
+
+func TestSearchFilter_IsValid(t *testing.T) {
+	require.NoError(t, SearchFilter{}.IsValid())
+	require.Error(t, SearchFilter{LinkStatus: "unknown"}.IsValid())
+	require.Error(t, SearchFilter{AddedWithinDays: -1}.IsValid())
+}
@@ -0,0 +1,286 @@
+package model
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SearchField is the field a search term filters on
+type SearchField string
+
+const (
+	// SearchFieldText matches the text of the bookmarks
+	SearchFieldText SearchField = ""
+	// SearchFieldSite matches the host of the URL or one of its parents
+	SearchFieldSite SearchField = "site"
+	// SearchFieldTag matches a tag, its aliases and its children, `*` for any tag
+	SearchFieldTag SearchField = "tag"
+	// SearchFieldIs matches the visibility, `public` or `private`
+	SearchFieldIs SearchField = "is"
+	// SearchFieldHas matches what was saved, `archive`, `content` or `tags`
+	SearchFieldHas SearchField = "has"
+	// SearchFieldBefore matches the bookmarks added before a day
+	SearchFieldBefore SearchField = "before"
+	// SearchFieldAfter matches the bookmarks added on a day or later
+	SearchFieldAfter SearchField = "after"
+	// SearchFieldIn widens the text searched, `archive` searches the text of the archives too
+	SearchFieldIn SearchField = "in"
+)
+
+// SearchDateFormat is the format of the days of the before and after terms
+const SearchDateFormat = "2006-01-02"
+
+const (
+	// SnippetHighlightStart and SnippetHighlightEnd surround the matches in snippets
+	SnippetHighlightStart = "<mark>"
+	SnippetHighlightEnd   = "</mark>"
+	// snippetLength is the length of snippets in bytes, about 30 words
+	snippetLength = 200
+	// snippetLead is the length of the text kept before the first match of a snippet
+	snippetLead = 60
+)
+
+var searchFields = []SearchField{SearchFieldSite, SearchFieldTag, SearchFieldIs, SearchFieldHas, SearchFieldBefore, SearchFieldAfter, SearchFieldIn}
+
+var searchFieldValues = map[SearchField][]string{
+	SearchFieldIs:  {"public", "private"},
+	SearchFieldHas: {"archive", "content", "tags"},
+	SearchFieldIn:  {"archive"},
+}
+
+// SearchTerm is a term of a search query
+type SearchTerm struct {
+	Field SearchField
+	// Value is the text, or the value after the field name, lowercased except for the text
+	Value string
+	// Negated terms match the bookmarks the term doesn't match
+	Negated bool
+	// Phrase is set for quoted text, matched as a whole
+	Phrase bool
+	// Date is the day of before and after terms
+	Date time.Time
+}
+
+// SearchQuery is a parsed bookmark search, matching the bookmarks every term matches
+type SearchQuery struct {
+	Terms []SearchTerm
+}
+
+// IsEmpty returns true if the query has no term and matches every bookmark
+func (q SearchQuery) IsEmpty() bool {
+	return len(q.Terms) == 0
+}
+
+// InArchive returns true if the text terms match the text of the archives as well
+func (q SearchQuery) InArchive() bool {
+	for _, term := range q.Terms {
+		if term.Field == SearchFieldIn && term.Value == "archive" {
+			return true
+		}
+	}
+	return false
+}
+
+// TextTerms returns the terms matching text, without the negated ones
+func (q SearchQuery) TextTerms() []SearchTerm {
+	terms := []SearchTerm{}
+	for _, term := range q.Terms {
+		if term.Field == SearchFieldText && !term.Negated {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// Snippet returns the extract of the first text with a match of the text terms, around its
+// first match. The text is escaped as HTML and the matches are surrounded by
+// SnippetHighlightStart and SnippetHighlightEnd. Empty if no text matches.
+func (q SearchQuery) Snippet(texts ...string) string {
+	terms := q.TextTerms()
+	if len(terms) == 0 {
+		return ""
+	}
+
+	patterns := make([]string, len(terms))
+	for i, term := range terms {
+		patterns[i] = regexp.QuoteMeta(term.Value)
+	}
+	rx := regexp.MustCompile(`(?i)` + strings.Join(patterns, "|"))
+
+	for _, text := range texts {
+		text = strings.Join(strings.Fields(text), " ")
+		first := rx.FindStringIndex(text)
+		if first == nil {
+			continue
+		}
+
+		// Cut the text at spaces around the first match
+		start, end := 0, len(text)
+		prefix, suffix := "", ""
+		if first[0] > snippetLead {
+			start = first[0] - snippetLead
+			if space := strings.IndexByte(text[start:first[0]], ' '); space >= 0 {
+				start += space + 1
+			}
+			for !utf8.RuneStart(text[start]) {
+				start++
+			}
+			prefix = "…"
+		}
+		if end-start > snippetLength {
+			end = max(start+snippetLength, first[1])
+			if space := strings.LastIndexByte(text[first[1]:end], ' '); space >= 0 {
+				end = first[1] + space
+			}
+			// A long match can reach the end of the text, nothing is cut then
+			if end < len(text) {
+				for !utf8.RuneStart(text[end]) {
+					end--
+				}
+				suffix = "…"
+			}
+		}
+		text = text[start:end]
+
+		snippet := strings.Builder{}
+		snippet.WriteString(prefix)
+		last := 0
+		for _, match := range rx.FindAllStringIndex(text, -1) {
+			snippet.WriteString(html.EscapeString(text[last:match[0]]))
+			snippet.WriteString(SnippetHighlightStart)
+			snippet.WriteString(html.EscapeString(text[match[0]:match[1]]))
+			snippet.WriteString(SnippetHighlightEnd)
+			last = match[1]
+		}
+		snippet.WriteString(html.EscapeString(text[last:]))
+		snippet.WriteString(suffix)
+		return snippet.String()
+	}
+
+	return ""
+}
+
+// ParseSearchQuery parses a search like `site:github.com tag:go -tag:old is:public
+// before:2024-01-01 "exact phrase"`. Terms are separated by spaces and negated by a leading
+// dash, quoted text is kept as one term. Words with an unknown field name, like URLs, are
+// searched as text.
+func ParseSearchQuery(query string) (SearchQuery, error) {
+	parsed := SearchQuery{}
+
+	runes := []rune(query)
+	for pos := 0; pos < len(runes); {
+		if unicode.IsSpace(runes[pos]) {
+			pos++
+			continue
+		}
+
+		term := SearchTerm{}
+		if runes[pos] == '-' && pos+1 < len(runes) && !unicode.IsSpace(runes[pos+1]) {
+			term.Negated = true
+			pos++
+		}
+
+		if isSearchQuote(runes[pos]) {
+			term.Value, pos = readSearchQuoted(runes, pos)
+			term.Phrase = true
+			if term.Value != "" {
+				parsed.Terms = append(parsed.Terms, term)
+			}
+			continue
+		}
+
+		start := pos
+		for pos < len(runes) && !unicode.IsSpace(runes[pos]) && runes[pos] != ':' {
+			pos++
+		}
+
+		field := SearchField(strings.ToLower(string(runes[start:pos])))
+		if pos < len(runes) && runes[pos] == ':' && slices.Contains(searchFields, field) {
+			pos++
+			term.Field = field
+			if pos < len(runes) && isSearchQuote(runes[pos]) {
+				term.Value, pos = readSearchQuoted(runes, pos)
+			} else {
+				term.Value, pos = readSearchWord(runes, pos)
+			}
+
+			if err := term.parseValue(); err != nil {
+				return SearchQuery{}, err
+			}
+			parsed.Terms = append(parsed.Terms, term)
+			continue
+		}
+
+		term.Value, pos = readSearchWord(runes, start)
+		parsed.Terms = append(parsed.Terms, term)
+	}
+
+	return parsed, nil
+}
+
+// parseValue checks the value of a field term is known, and normalizes it
+func (t *SearchTerm) parseValue() error {
+	t.Value = strings.TrimSpace(t.Value)
+	if t.Value == "" {
+		return NewValidationError("keyword", fmt.Sprintf("%s: should be followed by a value", t.Field))
+	}
+
+	switch t.Field {
+	case SearchFieldTag:
+		return nil
+	case SearchFieldIn:
+		if t.Negated {
+			return NewValidationError("keyword", "in: can't be negated")
+		}
+	case SearchFieldBefore, SearchFieldAfter:
+		date, err := time.Parse(SearchDateFormat, t.Value)
+		if err != nil {
+			return NewValidationError("keyword", fmt.Sprintf("%s:%s should be a day like 2024-01-31", t.Field, t.Value))
+		}
+		t.Date = date
+		return nil
+	}
+
+	t.Value = strings.ToLower(t.Value)
+	values, limited := searchFieldValues[t.Field]
+	if limited && !slices.Contains(values, t.Value) {
+		return NewValidationError("keyword", fmt.Sprintf("%s:%s should be one of %s", t.Field, t.Value, strings.Join(values, ", ")))
+	}
+
+	return nil
+}
+
+func isSearchQuote(r rune) bool {
+	return r == '"' || r == '\''
+}
+
+// readSearchQuoted reads the text between the quote at pos and the next one, or the end of
+// the query if it isn't closed.
+func readSearchQuoted(runes []rune, pos int) (string, int) {
+	quote := runes[pos]
+	start := pos + 1
+	end := start
+	for end < len(runes) && runes[end] != quote {
+		end++
+	}
+
+	if end < len(runes) {
+		return string(runes[start:end]), end + 1
+	}
+	return string(runes[start:end]), end
+}
+
+// readSearchWord reads the text from pos to the next space
+func readSearchWord(runes []rune, pos int) (string, int) {
+	start := pos
+	for pos < len(runes) && !unicode.IsSpace(runes[pos]) {
+		pos++
+	}
+	return string(runes[start:pos]), pos
+}
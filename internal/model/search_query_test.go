@@ -0,0 +1,119 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []SearchTerm
+	}{
+		{query: "  ", want: nil},
+		{query: "go generics", want: []SearchTerm{{Value: "go"}, {Value: "generics"}}},
+		{query: `"exact phrase" -'other phrase'`, want: []SearchTerm{
+			{Value: "exact phrase", Phrase: true},
+			{Value: "other phrase", Phrase: true, Negated: true},
+		}},
+		{query: "site:GitHub.com -site:gist.github.com", want: []SearchTerm{
+			{Field: SearchFieldSite, Value: "github.com"},
+			{Field: SearchFieldSite, Value: "gist.github.com", Negated: true},
+		}},
+		{query: `Tag:Go -tag:"to read" tag:*`, want: []SearchTerm{
+			{Field: SearchFieldTag, Value: "Go"},
+			{Field: SearchFieldTag, Value: "to read", Negated: true},
+			{Field: SearchFieldTag, Value: "*"},
+		}},
+		{query: "is:PUBLIC has:archive", want: []SearchTerm{
+			{Field: SearchFieldIs, Value: "public"},
+			{Field: SearchFieldHas, Value: "archive"},
+		}},
+		{query: "before:2024-01-01", want: []SearchTerm{
+			{Field: SearchFieldBefore, Value: "2024-01-01", Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		}},
+		{query: "https://go.dev/blog - foo:bar", want: []SearchTerm{
+			{Value: "https://go.dev/blog"},
+			{Value: "-"},
+			{Value: "foo:bar"},
+		}},
+		{query: `"unclosed phrase`, want: []SearchTerm{{Value: "unclosed phrase", Phrase: true}}},
+		{query: "gopher in:Archive", want: []SearchTerm{
+			{Value: "gopher"},
+			{Field: SearchFieldIn, Value: "archive"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			query, err := ParseSearchQuery(tt.query)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, query.Terms)
+		})
+	}
+}
+
+func TestParseSearchQuery_Invalid(t *testing.T) {
+	for _, query := range []string{"tag:", `tag:""`, "is:hidden", "has:ebook", "before:yesterday", "after:2024-13-01", "in:ebook", "-in:archive"} {
+		t.Run(query, func(t *testing.T) {
+			_, err := ParseSearchQuery(query)
+			require.ErrorAs(t, err, &ValidationError{})
+		})
+	}
+}
+
+func TestSearchQuery_InArchive(t *testing.T) {
+	query, err := ParseSearchQuery("gopher in:archive")
+	require.NoError(t, err)
+	require.True(t, query.InArchive())
+	require.Len(t, query.TextTerms(), 1)
+
+	query, err = ParseSearchQuery("gopher")
+	require.NoError(t, err)
+	require.False(t, query.InArchive())
+}
+
+func TestSearchQuery_Snippet(t *testing.T) {
+	parse := func(query string) SearchQuery {
+		parsed, err := ParseSearchQuery(query)
+		require.NoError(t, err)
+		return parsed
+	}
+
+	t.Run("highlights every match", func(t *testing.T) {
+		snippet := parse(`go "type parameters" -generics tag:go`).Snippet("Go 1.18 adds <b>type\nparameters</b> to GO.")
+		require.Equal(t, "<mark>Go</mark> 1.18 adds &lt;b&gt;<mark>type parameters</mark>&lt;/b&gt; to <mark>GO</mark>.", snippet)
+	})
+
+	t.Run("first text with a match", func(t *testing.T) {
+		require.Equal(t, "A <mark>gopher</mark>", parse("gopher").Snippet("", "No match", "A gopher"))
+		require.Empty(t, parse("gopher").Snippet("No match"))
+		require.Empty(t, parse("tag:gopher").Snippet("A gopher"))
+	})
+
+	t.Run("cut around the first match", func(t *testing.T) {
+		text := strings.Repeat("before ", 20) + "gopher" + strings.Repeat(" after", 50)
+		snippet := parse("gopher").Snippet(text)
+		require.True(t, strings.HasPrefix(snippet, "…before "), snippet)
+		require.True(t, strings.HasSuffix(snippet, " after…"), snippet)
+		require.Contains(t, snippet, "<mark>gopher</mark>")
+		require.LessOrEqual(t, len(snippet), snippetLength+len("……<mark></mark>"))
+	})
+
+	t.Run("long match at the end", func(t *testing.T) {
+		phrase := strings.TrimSpace(strings.Repeat("gopher ", 40))
+		snippet := parse(`"` + phrase + `"`).Snippet("A " + phrase)
+		require.Equal(t, "A <mark>"+phrase+"</mark>", snippet)
+	})
+
+	t.Run("cut between runes", func(t *testing.T) {
+		text := strings.Repeat("日本語", 40) + "gopher" + strings.Repeat("日本語", 100)
+		snippet := parse("gopher").Snippet(text)
+		require.True(t, utf8.ValidString(snippet))
+		require.Contains(t, snippet, "<mark>gopher</mark>")
+	})
+}
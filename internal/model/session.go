@@ -0,0 +1,12 @@
+package model
+
+// Session is a login of an account. Its ID is the jti claim of the JWT handed to the
+// client, so removing the session revokes the token.
+type Session struct {
+	ID         string  `db:"id"           json:"id"`
+	AccountID  DBID    `db:"account_id"   json:"account_id"`
+	UserAgent  string  `db:"user_agent"   json:"user_agent"`
+	ExpiresAt  string  `db:"expires_at"   json:"expires_at"`
+	LastUsedAt *string `db:"last_used_at" json:"last_used_at"`
+	CreatedAt  string  `db:"created_at"   json:"created_at"`
+}
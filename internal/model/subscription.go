@@ -0,0 +1,111 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// SubscriptionTags is the list of tags given to the bookmarks of a subscription, stored as
+// a comma separated string
+type SubscriptionTags []string
+
+func (t *SubscriptionTags) Scan(value interface{}) error {
+	var raw string
+	switch v := value.(type) {
+	case []byte:
+		raw = string(v)
+	case string:
+		raw = v
+	default:
+		return fmt.Errorf("unsupported type: %T", v)
+	}
+
+	*t = SubscriptionTags{}
+	for _, tag := range strings.Split(raw, ",") {
+		if tag != "" {
+			*t = append(*t, tag)
+		}
+	}
+	return nil
+}
+
+func (t SubscriptionTags) Value() (driver.Value, error) {
+	return strings.Join(t, ","), nil
+}
+
+// Subscription is a RSS, Atom or JSON feed whose new items are saved as bookmarks of an account
+type Subscription struct {
+	ID        DBID   `db:"id"         json:"id"`
+	AccountID DBID   `db:"account_id" json:"account_id"`
+	URL       string `db:"url"        json:"url"`
+	// Title of the feed, taken from the feed itself when it is polled if left empty
+	Title         string           `db:"title"          json:"title"`
+	Tags          SubscriptionTags `db:"tags"           json:"tags"`
+	CreateArchive bool             `db:"create_archive" json:"create_archive"`
+	CreateEbook   bool             `db:"create_ebook"   json:"create_ebook"`
+	// LastError is the reason the last poll failed, empty if it succeeded
+	LastError    string  `db:"last_error"     json:"last_error"`
+	LastPolledAt *string `db:"last_polled_at" json:"last_polled_at"`
+	CreatedAt    string  `db:"created_at"     json:"created_at"`
+}
+
+// IsValid checks that the subscription can be polled
+func (s Subscription) IsValid() error {
+	feedURL, err := url.Parse(s.URL)
+	if err != nil || (feedURL.Scheme != "http" && feedURL.Scheme != "https") || feedURL.Host == "" {
+		return NewValidationError("url", "feed URL must be an absolute http or https URL")
+	}
+
+	for _, tag := range s.Tags {
+		if strings.Contains(tag, ",") {
+			return NewValidationError("tags", "tags can't contain commas")
+		}
+	}
+
+	return nil
+}
+
+// SubscriptionItem is an item of a subscription already seen, so it isn't saved twice
+type SubscriptionItem struct {
+	SubscriptionID DBID   `db:"subscription_id" json:"subscription_id"`
+	GUID           string `db:"guid"            json:"guid"`
+	// BookmarkID is the bookmark saved for the item, or the existing one if the account had it bookmarked already,
+	// zero if a rule rejected it
+	BookmarkID int    `db:"bookmark_id" json:"bookmark_id"`
+	CreatedAt  string `db:"created_at"  json:"created_at"`
+}
+
+// ParsedFeed is a feed as read from its URL, in any of the supported formats
+type ParsedFeed struct {
+	Title string
+	Items []ParsedFeedItem
+}
+
+// ParsedFeedItem is an entry of a parsed feed
+type ParsedFeedItem struct {
+	// GUID identifies the item in the feed, the item URL when the feed doesn't provide one
+	GUID    string
+	URL     string
+	Title   string
+	Excerpt string
+}
+
+// PollResult is the outcome of polling a subscription
+type PollResult struct {
+	SubscriptionID DBID `json:"subscription_id"`
+	// Bookmarks created from new items
+	Created []int `json:"created"`
+	// Items skipped because the account had their URL bookmarked already
+	Skipped int `json:"skipped"`
+	// Items that couldn't be saved, they are tried again on the next poll
+	Failed int    `json:"failed"`
+	Error  string `json:"error,omitempty"`
+}
+
+// PollSubscriptionsJobPayload is the payload of a JobTypePollSubscriptions job
+type PollSubscriptionsJobPayload struct {
+	// Subscriptions to poll, empty polls every subscription
+	SubscriptionIDs []DBID `json:"subscription_ids"`
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"errors"
+	"strings"
 )
 
 // BookmarkTag is the relationship between a bookmark and a tag.
@@ -14,6 +15,21 @@ type BookmarkTag struct {
 type Tag struct {
 	ID   int    `db:"id"          json:"id"`
 	Name string `db:"name"        json:"name"`
+	// ParentID is set for child tags, searching a tag includes the bookmarks of its children
+	ParentID *int `db:"parent_id" json:"parent_id,omitempty"`
+}
+
+// TagAlias is another name of a tag. Aliases are replaced by their tag when bookmarks are
+// tagged or searched.
+type TagAlias struct {
+	ID    int    `db:"id"     json:"id"`
+	Name  string `db:"name"   json:"name"`
+	TagID int    `db:"tag_id" json:"tag_id"`
+}
+
+// NormalizeTagName returns the name a tag is saved with: lowercase with single spaces.
+func NormalizeTagName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
 }
 
 // TagDTO represents a tag in the application
@@ -21,26 +37,30 @@ type TagDTO struct {
 	Tag
 	BookmarkCount int64 `db:"bookmark_count" json:"bookmark_count"` // Number of bookmarks with this tag
 	Deleted       bool  `json:"deleted"`                            // Marks when a tag is deleted from a bookmark
+	Created       bool  `json:"-"`                                  // Marks the tags created when saving a bookmark
 }
 
 func (t *Tag) ToDTO() TagDTO {
 	return TagDTO{
 		Tag: Tag{
-			ID:   t.ID,
-			Name: t.Name,
+			ID:       t.ID,
+			Name:     t.Name,
+			ParentID: t.ParentID,
 		},
 	}
 }
 
 func (t *TagDTO) ToTag() Tag {
 	return Tag{
-		ID:   t.ID,
-		Name: t.Name,
+		ID:       t.ID,
+		Name:     t.Name,
+		ParentID: t.ParentID,
 	}
 }
 
 // ListTagsOptions is options for fetching tags from database.
 type ListTagsOptions struct {
+	AccountID         DBID
 	BookmarkID        int
 	WithBookmarkCount bool
 	OrderBy           DBTagOrderBy
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// SettingOwnerTOTPRequired is the setting making the TOTP second factor mandatory for owners
+const SettingOwnerTOTPRequired = "owner_totp_required"
+
+// TOTPRecoveryCodes are the hashes of the unused recovery codes, stored as a comma separated string
+type TOTPRecoveryCodes []string
+
+func (c *TOTPRecoveryCodes) Scan(value interface{}) error {
+	var raw string
+	switch v := value.(type) {
+	case []byte:
+		raw = string(v)
+	case string:
+		raw = v
+	default:
+		return fmt.Errorf("unsupported type: %T", v)
+	}
+
+	*c = TOTPRecoveryCodes{}
+	for _, code := range strings.Split(raw, ",") {
+		if code != "" {
+			*c = append(*c, code)
+		}
+	}
+	return nil
+}
+
+func (c TOTPRecoveryCodes) Value() (driver.Value, error) {
+	return strings.Join(c, ","), nil
+}
+
+// AccountTOTP is the TOTP second factor of an account. The secret is encrypted with the
+// server secret key, it isn't used to log in until the account confirms a first code.
+type AccountTOTP struct {
+	AccountID     DBID              `db:"account_id"`
+	Secret        string            `db:"secret"`
+	Enabled       bool              `db:"enabled"`
+	LastUsedStep  int64             `db:"last_used_step"`
+	RecoveryCodes TOTPRecoveryCodes `db:"recovery_codes"`
+	CreatedAt     string            `db:"created_at"`
+}
+
+// TOTPStatus is the second factor state of an account
+type TOTPStatus struct {
+	Enabled bool `json:"enabled"`
+	// Required is set for owners once owners must use a second factor
+	Required          bool `json:"required"`
+	RecoveryCodesLeft int  `json:"recovery_codes_left"`
+}
+
+// TOTPEnrollment is the secret of a TOTP second factor being set up, the URI is meant to be
+// shown as a QR code to authenticator apps.
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
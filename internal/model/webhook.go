@@ -0,0 +1,198 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// WebhookSecretPrefix starts every webhook signing secret
+const WebhookSecretPrefix = "shiori_whsec_"
+
+// WebhookSignatureHeader holds the HMAC-SHA256 of the timestamp and the delivered body, as
+// "sha256=<hex>"
+const WebhookSignatureHeader = "X-Shiori-Signature"
+
+// WebhookTimestampHeader holds the Unix time the delivery was sent at, receivers reject old
+// ones so a captured delivery can't be replayed
+const WebhookTimestampHeader = "X-Shiori-Timestamp"
+
+// WebhookEvent is a change webhooks are notified of
+type WebhookEvent string
+
+const (
+	WebhookEventBookmarkCreated WebhookEvent = "bookmark.created"
+	WebhookEventBookmarkUpdated WebhookEvent = "bookmark.updated"
+	WebhookEventBookmarkDeleted WebhookEvent = "bookmark.deleted"
+	// WebhookEventBookmarkArchived is sent once the offline archive of a bookmark is created
+	WebhookEventBookmarkArchived WebhookEvent = "bookmark.archived"
+	// Tags are shared by every account, their events are only sent to instance webhooks
+	WebhookEventTagCreated WebhookEvent = "tag.created"
+	WebhookEventTagDeleted WebhookEvent = "tag.deleted"
+)
+
+var webhookEvents = []WebhookEvent{
+	WebhookEventBookmarkCreated,
+	WebhookEventBookmarkUpdated,
+	WebhookEventBookmarkDeleted,
+	WebhookEventBookmarkArchived,
+	WebhookEventTagCreated,
+	WebhookEventTagDeleted,
+}
+
+// IsValid checks that the event is a known one
+func (e WebhookEvent) IsValid() error {
+	if !slices.Contains(webhookEvents, e) {
+		return fmt.Errorf("invalid webhook event: %s", e)
+	}
+	return nil
+}
+
+// WebhookEvents is the list of events of a webhook, stored as a comma separated string.
+// An empty list subscribes to every event.
+type WebhookEvents []WebhookEvent
+
+// Includes reports whether the webhook is notified of the event
+func (e WebhookEvents) Includes(event WebhookEvent) bool {
+	return len(e) == 0 || slices.Contains(e, event)
+}
+
+func (e *WebhookEvents) Scan(value interface{}) error {
+	var raw string
+	switch v := value.(type) {
+	case []byte:
+		raw = string(v)
+	case string:
+		raw = v
+	default:
+		return fmt.Errorf("unsupported type: %T", v)
+	}
+
+	*e = WebhookEvents{}
+	for _, event := range strings.Split(raw, ",") {
+		if event != "" {
+			*e = append(*e, WebhookEvent(event))
+		}
+	}
+	return nil
+}
+
+func (e WebhookEvents) Value() (driver.Value, error) {
+	events := make([]string, len(e))
+	for i, event := range e {
+		events[i] = string(event)
+	}
+	return strings.Join(events, ","), nil
+}
+
+// Webhook is an URL notified of the changes to the bookmarks of an account, or of every
+// account for instance webhooks
+type Webhook struct {
+	ID DBID `db:"id" json:"id"`
+	// AccountID is zero for instance webhooks, which only owners manage
+	AccountID DBID          `db:"account_id" json:"account_id"`
+	URL       string        `db:"url"        json:"url"`
+	Events    WebhookEvents `db:"events"     json:"events"`
+	// Secret signs the deliveries, it is only returned when the webhook is created
+	Secret    string `db:"secret"     json:"secret,omitempty"`
+	CreatedAt string `db:"created_at" json:"created_at"`
+}
+
+// IsValid checks that the webhook can be delivered to
+func (w Webhook) IsValid() error {
+	target, err := url.Parse(w.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return NewValidationError("url", "webhook URL must be an absolute http or https URL")
+	}
+
+	for _, event := range w.Events {
+		if err := event.IsValid(); err != nil {
+			return NewValidationError("events", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// WebhookDeliveryStatus is the state of a webhook delivery
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryStatusPending deliveries are waiting for their first attempt
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "succeeded"
+	// WebhookDeliveryStatusFailed deliveries failed their last attempt, they may be retried
+	WebhookDeliveryStatusFailed WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery is a notification of an event sent, or to be sent, to a webhook
+type WebhookDelivery struct {
+	ID        DBID         `db:"id"         json:"id"`
+	WebhookID DBID         `db:"webhook_id" json:"webhook_id"`
+	Event     WebhookEvent `db:"event"      json:"event"`
+	// Payload is the JSON body sent to the webhook
+	Payload  string                `db:"payload"  json:"payload"`
+	Status   WebhookDeliveryStatus `db:"status"   json:"status"`
+	Attempts int                   `db:"attempts" json:"attempts"`
+	// ResponseStatus and ResponseBody are the answer to the last attempt, the body is truncated
+	ResponseStatus int     `db:"response_status" json:"response_status"`
+	ResponseBody   string  `db:"response_body"   json:"response_body"`
+	LastError      string  `db:"last_error"      json:"last_error"`
+	CreatedAt      string  `db:"created_at"      json:"created_at"`
+	DeliveredAt    *string `db:"delivered_at"    json:"delivered_at"`
+}
+
+// WebhookPayload is the body of a webhook delivery
+type WebhookPayload struct {
+	Event     WebhookEvent `json:"event"`
+	CreatedAt string       `json:"created_at"`
+	Data      any          `json:"data"`
+}
+
+// WebhookBookmark is the bookmark data sent in bookmark events, without its content
+type WebhookBookmark struct {
+	ID         int      `json:"id"`
+	AccountID  DBID     `json:"account_id"`
+	URL        string   `json:"url"`
+	Title      string   `json:"title"`
+	Excerpt    string   `json:"excerpt"`
+	Author     string   `json:"author"`
+	Public     bool     `json:"public"`
+	Tags       []string `json:"tags"`
+	HasArchive bool     `json:"has_archive"`
+	HasEbook   bool     `json:"has_ebook"`
+	CreatedAt  string   `json:"created_at"`
+	ModifiedAt string   `json:"modified_at"`
+}
+
+// NewWebhookBookmark returns the webhook data of a bookmark
+func NewWebhookBookmark(bookmark BookmarkDTO) WebhookBookmark {
+	tags := make([]string, 0, len(bookmark.Tags))
+	for _, tag := range bookmark.Tags {
+		if !tag.Deleted {
+			tags = append(tags, tag.Name)
+		}
+	}
+
+	return WebhookBookmark{
+		ID:         bookmark.ID,
+		AccountID:  bookmark.AccountID,
+		URL:        bookmark.URL,
+		Title:      bookmark.Title,
+		Excerpt:    bookmark.Excerpt,
+		Author:     bookmark.Author,
+		Public:     bookmark.Public == 1,
+		Tags:       tags,
+		HasArchive: bookmark.HasArchive,
+		HasEbook:   bookmark.HasEbook,
+		CreatedAt:  bookmark.CreatedAt,
+		ModifiedAt: bookmark.ModifiedAt,
+	}
+}
+
+// DeliverWebhookJobPayload is the payload of a JobTypeDeliverWebhook job
+type DeliverWebhookJobPayload struct {
+	DeliveryID DBID `json:"delivery_id"`
+}
@@ -21,7 +21,7 @@ func NewAdminUser(deps model.Dependencies) (*model.AccountDTO, string, error) {
 		return nil, "", err
 	}
 
-	token, err := deps.Domains().Auth().CreateTokenForAccount(account, time.Now().Add(time.Hour*24*365))
+	token, err := deps.Domains().Auth().CreateTokenForAccount(context.TODO(), account, time.Now().Add(time.Hour*24*365), "")
 	if err != nil {
 		return nil, "", err
 	}
@@ -41,6 +41,13 @@ func WithHeader(name, value string) Option {
 	}
 }
 
+// WithRemoteAddr sets the address the request comes from
+func WithRemoteAddr(addr string) Option {
+	return func(c model.WebContext) {
+		c.Request().RemoteAddr = addr
+	}
+}
+
 // WithAuthToken adds an authorization token to the request
 func WithAuthToken(token string) Option {
 	return func(c model.WebContext) {
@@ -54,6 +61,13 @@ func WithAccount(account *model.AccountDTO) Option {
 	}
 }
 
+// WithSessionID sets the session the request is authenticated with
+func WithSessionID(id string) Option {
+	return func(c model.WebContext) {
+		c.SetSessionID(id)
+	}
+}
+
 // WithFakeAccount adds a fake account to the request context
 func WithFakeAccount(isAdmin bool) Option {
 	return func(c model.WebContext) {
@@ -101,10 +115,13 @@ func PerformRequestOnRecorder(deps model.Dependencies, w *httptest.ResponseRecor
 	handler(deps, c)
 }
 
+// FakeAccountID is the ID used by the fake accounts set in the request context
+const FakeAccountID model.DBID = 1
+
 // FakeAccount creates a fake account for testing
 func FakeAccount(isAdmin bool) *model.AccountDTO {
 	return &model.AccountDTO{
-		ID:       1,
+		ID:       FakeAccountID,
 		Username: "user",
 		Owner:    model.Ptr(isAdmin),
 	}
@@ -113,7 +130,7 @@ func FakeAccount(isAdmin bool) *model.AccountDTO {
 // SetFakeUser sets a fake user account in the WebContext
 func SetFakeUser(c model.WebContext) {
 	c.SetAccount(&model.AccountDTO{
-		ID:       1,
+		ID:       FakeAccountID,
 		Username: "user",
 		Owner:    model.Ptr(false),
 	})
@@ -122,7 +139,7 @@ func SetFakeUser(c model.WebContext) {
 // SetFakeAdmin sets a fake admin account in the WebContext
 func SetFakeAdmin(c model.WebContext) {
 	c.SetAccount(&model.AccountDTO{
-		ID:       1,
+		ID:       FakeAccountID,
 		Username: "user",
 		Owner:    model.Ptr(true),
 	})
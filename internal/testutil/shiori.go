@@ -41,18 +41,31 @@ func GetTestConfigurationAndDependencies(t *testing.T, ctx context.Context, logg
 	deps.Domains().SetAccounts(domains.NewAccountsDomain(deps))
 	deps.Domains().SetArchiver(domains.NewArchiverDomain(deps))
 	deps.Domains().SetAuth(domains.NewAuthDomain(deps))
+	deps.Domains().SetOIDC(domains.NewOIDCDomain(deps))
+	deps.Domains().SetTOTP(domains.NewTOTPDomain(deps))
 	deps.Domains().SetBookmarks(domains.NewBookmarksDomain(deps))
 	deps.Domains().SetStorage(domains.NewStorageDomain(deps, afero.NewBasePathFs(afero.NewOsFs(), cfg.Storage.DataDir)))
 	deps.Domains().SetTags(domains.NewTagsDomain(deps))
+	deps.Domains().SetJobs(domains.NewJobsDomain(deps))
+	deps.Domains().SetLinkChecker(domains.NewLinkCheckerDomain(deps))
+	deps.Domains().SetSubscriptions(domains.NewSubscriptionsDomain(deps))
+	deps.Domains().SetWebhooks(domains.NewWebhooksDomain(deps))
+	deps.Domains().SetCollections(domains.NewCollectionsDomain(deps))
+	deps.Domains().SetRules(domains.NewRulesDomain(deps))
+	deps.Domains().SetSavedSearches(domains.NewSavedSearchesDomain(deps))
+	deps.Domains().SetHighlights(domains.NewHighlightsDomain(deps))
+	deps.Domains().SetBackup(domains.NewBackupDomain(deps))
 
 	return cfg, deps
 }
 
+// GetValidBookmark returns a valid bookmark owned by the fake account
 func GetValidBookmark() *model.BookmarkDTO {
 	uuidV4, _ := uuid.NewV4()
 	return &model.BookmarkDTO{
-		URL:   "https://github.com/go-shiori/shiori#" + uuidV4.String(),
-		Title: "Shiori repository",
+		AccountID: FakeAccountID,
+		URL:       "https://github.com/go-shiori/shiori#" + uuidV4.String(),
+		Title:     "Shiori repository",
 	}
 }
 
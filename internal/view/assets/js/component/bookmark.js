@@ -11,7 +11,8 @@ var template = `
 			<i v-if="hasArchive" class="fas fa-archive"></i>
 			<i v-if="public" class="fas fa-eye"></i>
 		</p>
-		<p class="excerpt" v-if="excerptVisible">{{excerpt}}</p>
+		<p class="excerpt" v-if="snippet" v-html="snippet"></p>
+		<p class="excerpt" v-else-if="excerptVisible">{{excerpt}}</p>
 		<p class="id" v-show="ShowId">{{id}}</p>
 	</a>
 	<div class="bookmark-tags" v-if="tags.length > 0">
@@ -46,6 +47,7 @@ export default {
 		url: String,
 		title: String,
 		excerpt: String,
+		snippet: String,
 		public: Number,
 		imageURL: String,
 		hasContent: Boolean,
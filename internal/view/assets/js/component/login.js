@@ -4,14 +4,25 @@ const template = `
 <div id="login-scene">
     <p class="error-message" v-if="error !== ''">{{error}}</p>
     <div id="login-box">
-        <form @submit.prevent="login">
+        <form @submit.prevent="challenge === '' ? login() : verifyCode()">
             <div id="logo-area">
                 <p id="logo">
                     <span>栞</span>shiori
                 </p>
                 <p id="tagline">simple bookmark manager</p>
             </div>
-            <div id="input-area">
+            <div id="input-area" v-if="recoveryCodes.length > 0">
+                <p>Two-factor authentication is set up. Keep these recovery codes somewhere safe, each of them can be used once instead of a code:</p>
+                <pre>{{recoveryCodes.join("\n")}}</pre>
+            </div>
+            <div id="input-area" v-else-if="challenge !== ''">
+                <template v-if="totpSetup">
+                    <p>Two-factor authentication is required for this account. Add it to your authenticator app with <a :href="totpSetup.uri">this link</a> or the secret <code>{{totpSetup.secret}}</code>, then enter the code it shows.</p>
+                </template>
+                <label for="totp-code">Code: </label>
+                <input id="totp-code" type="text" name="code" placeholder="Code or recovery code" autocomplete="one-time-code" tabindex="1">
+            </div>
+            <div id="input-area" v-else>
                 <label for="username">Username: </label>
                 <input id="username" type="text" name="username" placeholder="Username" tabindex="1" autofocus />
                 <label for="password">Password: </label>
@@ -24,7 +35,10 @@ const template = `
                 <a v-if="loading">
                     <i class="fas fa-fw fa-spinner fa-spin"></i>
                 </a>
+                <a v-else-if="recoveryCodes.length > 0" class="button" tabindex="2" @click="finishLogin">Continue</a>
+                <a v-else-if="challenge !== ''" class="button" tabindex="2" @click="verifyCode" @keyup.enter="verifyCode">Verify</a>
                 <a v-else class="button" tabindex="4" @click="login" @keyup.enter="login">Log In</a>
+                <a v-if="oidcEnabled && !loading && challenge === ''" class="button" tabindex="5" href="api/v1/auth/oidc/login">Log In with SSO</a>
             </div>
         </form>
     </div>
@@ -34,6 +48,12 @@ const template = `
 export default {
 	name: "login-view",
 	template,
+	props: {
+		oidcEnabled: {
+			type: Boolean,
+			default: false,
+		},
+	},
 	data() {
 		return {
 			error: "",
@@ -41,6 +61,9 @@ export default {
 			username: "",
 			password: "",
 			remember: false,
+			challenge: "", // Second login step of accounts with two-factor authentication
+			totpSetup: null,
+			recoveryCodes: [],
 			destination: "/", // Default destination
 		};
 	},
@@ -70,14 +93,6 @@ export default {
 			}
 		},
 
-		parseJWT(token) {
-			try {
-				return JSON.parse(atob(token.split(".")[1]));
-			} catch (e) {
-				return null;
-			}
-		},
-
 		async login() {
 			// Get values directly from the form
 			const usernameInput = document.querySelector("#username");
@@ -112,23 +127,70 @@ export default {
 					},
 				);
 
-				// Save session id
-				document.cookie = `token=${json.token}; Path=${
-					new URL(document.baseURI).pathname
-				}; Expires=${new Date(json.expires * 1000).toUTCString()}`;
+				if (json.totp_required) {
+					await this.startChallenge(json.challenge, json.totp_setup_required);
+					return;
+				}
+
+				// Save session and account data
+				await this.saveSession(json.token, json.expires);
+				this.finishLogin();
+			} catch (err) {
+				this.error = err.message;
+			} finally {
+				this.loading = false;
+			}
+		},
+
+		// startChallenge asks for the code of the second login step
+		async startChallenge(challenge, setupRequired) {
+			this.challenge = challenge;
+			if (setupRequired) {
+				this.totpSetup = await apiRequest(
+					new URL("api/v1/auth/login/totp/setup", document.baseURI),
+					{
+						method: "post",
+						body: JSON.stringify({ challenge: this.challenge }),
+					},
+				);
+			}
+			this.error = "";
+			this.$nextTick(() => {
+				const codeInput = document.querySelector("#totp-code");
+				if (codeInput) codeInput.focus();
+			});
+		},
+
+		// verifyCode completes the login of accounts with two-factor authentication
+		async verifyCode() {
+			const codeInput = document.querySelector("#totp-code");
+			const code = codeInput ? codeInput.value.trim() : "";
+			if (code === "") {
+				this.error = "Code must not be empty";
+				return;
+			}
+
+			this.loading = true;
 
-				// Save account data
-				localStorage.setItem("shiori-token", json.token);
-				localStorage.setItem(
-					"shiori-account",
-					JSON.stringify(this.parseJWT(json.token).account),
+			try {
+				const json = await apiRequest(
+					new URL("api/v1/auth/login/totp", document.baseURI),
+					{
+						method: "post",
+						body: JSON.stringify({ challenge: this.challenge, code }),
+					},
 				);
 
-				this.visible = false;
-				this.$emit("login-success");
+				await this.saveSession(json.token, json.expires);
+				this.error = "";
+
+				// Codes of a second factor set up while logging in are shown before going on
+				if (json.recovery_codes) {
+					this.recoveryCodes = json.recovery_codes;
+					return;
+				}
 
-				// Redirect to sanitized destination
-				if (this.destination !== "/") window.location.href = this.destination;
+				this.finishLogin();
 			} catch (err) {
 				this.error = err.message;
 			} finally {
@@ -136,6 +198,59 @@ export default {
 			}
 		},
 
+		finishLogin() {
+			this.visible = false;
+			this.$emit("login-success");
+
+			// Redirect to sanitized destination
+			if (this.destination !== "/") window.location.href = this.destination;
+		},
+
+		// saveSession stores the session token and the account it belongs to
+		async saveSession(token, expires) {
+			document.cookie = `token=${token}; Path=${
+				new URL(document.baseURI).pathname
+			}; Expires=${new Date(expires * 1000).toUTCString()}`;
+
+			localStorage.setItem("shiori-token", token);
+			const account = await apiRequest(
+				new URL("api/v1/auth/me", document.baseURI),
+			);
+			localStorage.setItem("shiori-account", JSON.stringify(account));
+		},
+
+		// oidcLogin completes a login with the token the OIDC callback puts in the URL fragment,
+		// or asks for the second factor when the callback sent a challenge instead
+		async oidcLogin() {
+			const params = new URLSearchParams(window.location.hash.substring(1));
+			const token = params.get("oidc_token");
+			const challenge = params.get("oidc_challenge");
+			if (!token && !challenge) return false;
+
+			// Remove the token from the address bar and the history
+			history.replaceState(
+				null,
+				"",
+				window.location.pathname + window.location.search,
+			);
+
+			try {
+				if (challenge) {
+					await this.startChallenge(
+						challenge,
+						params.get("totp_setup_required") === "true",
+					);
+					return false;
+				}
+
+				await this.saveSession(token, Number(params.get("expires")));
+				return true;
+			} catch (err) {
+				this.error = err.message;
+				return false;
+			}
+		},
+
 		async checkSession() {
 			const token = localStorage.getItem("shiori-token");
 			if (!token) return false;
@@ -155,7 +270,7 @@ export default {
 		this.destination = dst ? this.sanitizeDestination(dst) : "/";
 
 		// Check if there's a valid session
-		if (await this.checkSession()) {
+		if ((await this.oidcLogin()) || (await this.checkSession())) {
 			this.$emit("login-success");
 			return;
 		}
@@ -1,7 +1,7 @@
 var template = `
 <div id="page-home">
     <div class="page-header">
-        <input type="text" placeholder="Search url, keyword or tags" v-model.trim="search" @focus="$event.target.select()" @keyup.enter="searchBookmarks"/>
+        <input type="text" placeholder="Search, like site:github.com tag:go -tag:old is:public" v-model.trim="search" @focus="$event.target.select()" @keyup.enter="searchBookmarks"/>
         <a title="Refresh storage" @click="reloadData">
             <i class="fas fa-fw fa-sync-alt" :class="loading && 'fa-spin'"></i>
         </a>
@@ -46,6 +46,7 @@ var template = `
             :url="book.url"
             :title="book.title"
             :excerpt="book.excerpt"
+            :snippet="book.snippet"
             :public="book.public"
             :imageURL="book.imageURL"
             :modifiedAt="book.modifiedAt"
@@ -175,47 +176,13 @@ export default {
 			saveState = typeof saveState === "boolean" ? saveState : true;
 			fetchTags = typeof fetchTags === "boolean" ? fetchTags : false;
 
-			// Parse search query
-			var keyword = this.search,
-				rxExcludeTagA = /(^|\s)-tag:["']([^"']+)["']/i, // -tag:"with space"
-				rxExcludeTagB = /(^|\s)-tag:(\S+)/i, // -tag:without-space
-				rxIncludeTagA = /(^|\s)tag:["']([^"']+)["']/i, // tag:"with space"
-				rxIncludeTagB = /(^|\s)tag:(\S+)/i, // tag:without-space
-				tags = [],
-				excludedTags = [],
-				rxResult;
-
-			// Get excluded tag first, while also removing it from keyword
-			while ((rxResult = rxExcludeTagA.exec(keyword))) {
-				keyword = keyword.replace(rxResult[0], "");
-				excludedTags.push(rxResult[2]);
-			}
-
-			while ((rxResult = rxExcludeTagB.exec(keyword))) {
-				keyword = keyword.replace(rxResult[0], "");
-				excludedTags.push(rxResult[2]);
-			}
-
-			// Get included tags
-			while ((rxResult = rxIncludeTagA.exec(keyword))) {
-				keyword = keyword.replace(rxResult[0], "");
-				tags.push(rxResult[2]);
-			}
-
-			while ((rxResult = rxIncludeTagB.exec(keyword))) {
-				keyword = keyword.replace(rxResult[0], "");
-				tags.push(rxResult[2]);
-			}
-
-			// Trim keyword
-			keyword = keyword.trim().replace(/\s+/g, " ");
+			// The search query, tags included, is parsed by the server
+			var keyword = this.search.trim().replace(/\s+/g, " ");
 
 			// Prepare URL for API
 			var url = new URL("api/bookmarks", document.baseURI);
 			url.search = new URLSearchParams({
 				keyword: keyword,
-				tags: tags.join(","),
-				exclude: excludedTags.join(","),
 				page: this.page,
 			});
 
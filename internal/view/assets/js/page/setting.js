@@ -84,6 +84,55 @@ var template = `
             <div class="setting-group-footer">
                 <a @click="showDialogChangePassword(this.activeAccount)" title="Change password">Change password</a>
             </div>
+        </details>
+        <details open class="setting-group" id="setting-totp">
+            <summary>Two-factor authentication</summary>
+            <p v-if="totp.enabled">Enabled, {{totp.recovery_codes_left}} recovery codes left.</p>
+            <p v-else>Disabled.</p>
+            <template v-if="totpEnrollment">
+                <p>Add Shiori to your authenticator app with <a :href="totpEnrollment.uri">this link</a> or the secret <code>{{totpEnrollment.secret}}</code>, then enter the code it shows:</p>
+                <input type="text" v-model="totpCode" placeholder="Code" autocomplete="one-time-code" @keyup.enter="enableTOTP">
+            </template>
+            <template v-if="totpRecoveryCodes.length > 0">
+                <p>Keep these recovery codes somewhere safe, each of them can be used once instead of a code:</p>
+                <pre>{{totpRecoveryCodes.join("\n")}}</pre>
+            </template>
+            <label v-if="activeAccount.owner">
+                <input type="checkbox" :checked="totp.required" @change="updateTOTPPolicy($event.target.checked)">
+                Require two-factor authentication for owners
+            </label>
+            <div class="setting-group-footer">
+                <a v-if="totpEnrollment" @click="enableTOTP" title="Confirm code">Confirm code</a>
+                <a v-else-if="!totp.enabled" @click="enrollTOTP" title="Enable two-factor authentication">Enable</a>
+                <a v-else @click="showDialogDisableTOTP" title="Disable two-factor authentication">Disable</a>
+            </div>
+        </details>
+        <details open class="setting-group" id="setting-feeds">
+            <summary>Feeds</summary>
+            <p>Public bookmarks: <a :href="feedURL('atom')" target="_blank">Atom</a> · <a :href="feedURL('rss')" target="_blank">RSS</a></p>
+            <template v-if="feedTokenValue !== ''">
+                <p>All your bookmarks, keep these links private as they are only shown once: <a :href="feedURL('atom', feedTokenValue)" target="_blank">Atom</a> · <a :href="feedURL('rss', feedTokenValue)" target="_blank">RSS</a></p>
+            </template>
+            <p v-else-if="feedToken">Private feed created on {{feedToken.created_at}}.</p>
+            <div class="setting-group-footer">
+                <a @click="createFeedToken" :title="feedToken ? 'Replace the links of the private feed' : 'Create a feed of all your bookmarks'">{{feedToken ? "Reset private feed" : "Create private feed"}}</a>
+                <a v-if="feedToken" @click="revokeFeedToken" title="Revoke the private feed">Revoke private feed</a>
+            </div>
+        </details>
+        <details v-if="oidcIdentities !== null" open class="setting-group" id="setting-sso">
+            <summary>Single sign-on</summary>
+            <p v-if="oidcIdentities.length === 0">No identity of the SSO provider is linked, link one to log in to this account with SSO.</p>
+            <ul v-else class="accounts-list">
+                <li v-for="identity in oidcIdentities">
+                    <p>Linked on {{identity.created_at}}</p>
+                    <a title="Unlink identity" @click="unlinkOIDCIdentity(identity)">
+                        <i class="fa fas fa-fw fa-unlink"></i>
+                    </a>
+                </li>
+            </ul>
+            <div class="setting-group-footer">
+                <a @click="linkOIDCIdentity" title="Link an identity of the SSO provider">Link identity</a>
+            </div>
         </details>
 		<details v-if="activeAccount.owner" class="setting-group" id="setting-system-info">
 			<summary>System info</summary>
@@ -113,6 +162,13 @@ export default {
 			loading: false,
 			accounts: [],
 			system: {},
+			totp: {},
+			totpEnrollment: null,
+			totpCode: "",
+			totpRecoveryCodes: [],
+			feedToken: null,
+			feedTokenValue: "",
+			oidcIdentities: null,
 		};
 	},
 	methods: {
@@ -188,6 +244,169 @@ export default {
 				this.showErrorDialog(err.message);
 			}
 		},
+		async loadTOTP() {
+			try {
+				this.totp = await apiRequest(
+					new URL("api/v1/auth/totp", document.baseURI),
+				);
+			} catch (err) {
+				this.showErrorDialog(err.message);
+			}
+		},
+		async enrollTOTP() {
+			try {
+				this.totpRecoveryCodes = [];
+				this.totpEnrollment = await apiRequest(
+					new URL("api/v1/auth/totp", document.baseURI),
+					{ method: "POST" },
+				);
+			} catch (err) {
+				this.showErrorDialog(err.message);
+			}
+		},
+		async enableTOTP() {
+			try {
+				const json = await apiRequest(
+					new URL("api/v1/auth/totp/enable", document.baseURI),
+					{
+						method: "POST",
+						body: JSON.stringify({ code: this.totpCode.trim() }),
+					},
+				);
+
+				this.totpEnrollment = null;
+				this.totpCode = "";
+				this.totpRecoveryCodes = json.recovery_codes;
+				this.loadTOTP();
+			} catch (err) {
+				this.showErrorDialog(err.message);
+			}
+		},
+		async updateTOTPPolicy(required) {
+			try {
+				await apiRequest(
+					new URL("api/v1/auth/totp/policy", document.baseURI),
+					{
+						method: "PUT",
+						body: JSON.stringify({ owners_required: required }),
+					},
+				);
+			} catch (err) {
+				this.showErrorDialog(err.message);
+			}
+			this.loadTOTP();
+		},
+		showDialogDisableTOTP() {
+			this.showDialog({
+				title: "Disable Two-Factor Authentication",
+				content: "Enter a code from your authenticator app or a recovery code :",
+				fields: [
+					{
+						name: "code",
+						label: "Code",
+						value: "",
+					},
+				],
+				mainText: "Disable",
+				secondText: "Cancel",
+				mainClick: async (data) => {
+					this.dialog.loading = true;
+					try {
+						await apiRequest(
+							new URL("api/v1/auth/totp/disable", document.baseURI),
+							{
+								method: "POST",
+								body: JSON.stringify({ code: data.code.trim() }),
+							},
+						);
+
+						this.dialog.loading = false;
+						this.dialog.visible = false;
+						this.totpRecoveryCodes = [];
+						this.loadTOTP();
+					} catch (err) {
+						this.dialog.loading = false;
+						this.showErrorDialog(err.message);
+					}
+				},
+			});
+		},
+		feedURL(format, token) {
+			const url = new URL("feeds/" + format, document.baseURI);
+			if (token) url.searchParams.set("token", token);
+			return url.href;
+		},
+		async loadFeedToken() {
+			try {
+				this.feedToken = await apiRequest(
+					new URL("api/v1/auth/feed-token", document.baseURI),
+				);
+			} catch (err) {
+				// Accounts without a private feed get a not found error
+				this.feedToken = null;
+			}
+		},
+		async createFeedToken() {
+			try {
+				const json = await apiRequest(
+					new URL("api/v1/auth/feed-token", document.baseURI),
+					{ method: "POST" },
+				);
+
+				this.feedTokenValue = json.token;
+				this.loadFeedToken();
+		this.loadOIDCIdentities();
+			} catch (err) {
+				this.showErrorDialog(err.message);
+			}
+		},
+		async revokeFeedToken() {
+			try {
+				await apiRequest(new URL("api/v1/auth/feed-token", document.baseURI), {
+					method: "DELETE",
+				});
+
+				this.feedToken = null;
+				this.feedTokenValue = "";
+			} catch (err) {
+				this.showErrorDialog(err.message);
+			}
+		},
+		async loadOIDCIdentities() {
+			try {
+				this.oidcIdentities = await apiRequest(
+					new URL("api/v1/auth/oidc/identities", document.baseURI),
+				);
+			} catch (err) {
+				// Instances without SSO get a not found error
+				this.oidcIdentities = null;
+			}
+		},
+		async linkOIDCIdentity() {
+			try {
+				const json = await apiRequest(
+					new URL("api/v1/auth/oidc/link", document.baseURI),
+					{ method: "POST" },
+				);
+
+				// The provider sends the browser back to the web interface once linked
+				window.location.href = json.url;
+			} catch (err) {
+				this.showErrorDialog(err.message);
+			}
+		},
+		async unlinkOIDCIdentity(identity) {
+			try {
+				await apiRequest(
+					new URL("api/v1/auth/oidc/identities/" + identity.id, document.baseURI),
+					{ method: "DELETE" },
+				);
+
+				this.loadOIDCIdentities();
+			} catch (err) {
+				this.showErrorDialog(err.message);
+			}
+		},
 		showDialogNewAccount() {
 			this.showDialog({
 				title: "New Account",
@@ -379,6 +598,9 @@ export default {
 		},
 	},
 	mounted() {
+		this.loadTOTP();
+		this.loadFeedToken();
+
 		if (this.activeAccount.owner) {
 			this.loadAccounts();
 			this.loadSystemInfo();
@@ -21,7 +21,7 @@ func (h *Handler) ApiInsertViaExtension(w http.ResponseWriter, r *http.Request,
 	ctx := r.Context()
 
 	// Make sure session still valid
-	err := h.validateSession(r)
+	account, err := h.validateSession(r)
 	checkError(err)
 
 	// Decode request
@@ -36,7 +36,7 @@ func (h *Handler) ApiInsertViaExtension(w http.ResponseWriter, r *http.Request,
 	}
 
 	// Check if bookmark already exists.
-	book, exist, err := h.DB.GetBookmark(ctx, 0, request.URL)
+	book, exist, err := h.DB.GetBookmark(ctx, 0, request.URL, account.ID)
 	if err != nil {
 		panic(fmt.Errorf("failed to get bookmark, URL: %v", err))
 	}
@@ -44,6 +44,7 @@ func (h *Handler) ApiInsertViaExtension(w http.ResponseWriter, r *http.Request,
 	// If it already exists, we need to set ID and tags.
 	if exist {
 		book.HTML = request.HTML
+		book.CreateArchive = true
 
 		mapOldTags := map[string]model.TagDTO{}
 		for _, oldTag := range book.Tags {
@@ -55,8 +56,12 @@ func (h *Handler) ApiInsertViaExtension(w http.ResponseWriter, r *http.Request,
 				book.Tags = append(book.Tags, newTag)
 			}
 		}
-	} else if request.Title == "" {
-		request.Title = request.URL
+	} else {
+		request.AccountID = account.ID
+		request.CreateArchive = true
+		if request.Title == "" {
+			request.Title = request.URL
+		}
 	}
 
 	// Since we are using extension, the extension might send the HTML content
@@ -76,18 +81,22 @@ func (h *Handler) ApiInsertViaExtension(w http.ResponseWriter, r *http.Request,
 	// since we need the ID in order to download the archive
 	// Only when old bookmark is not exists.
 	if !exist {
+		// Apply the rules of the account, they can reject the bookmark
+		checkError(h.dependencies.Domains().Rules().Apply(ctx, &request, contentType))
+
 		books, err := h.DB.SaveBookmarks(ctx, true, request)
 		if err != nil {
 			log.Printf("error saving bookmark before downloading content: %s", err)
 			return
 		}
 		book = books[0]
+		h.dependencies.Domains().Webhooks().EmitCreatedTags(ctx, book)
+		h.dependencies.Domains().Webhooks().Emit(ctx, model.WebhookEventBookmarkCreated, book.AccountID, model.NewWebhookBookmark(book))
 	}
 
 	// At this point the web page already downloaded.
 	// Time to process it.
 	if contentBuffer != nil {
-		book.CreateArchive = true
 		request := core.ProcessRequest{
 			DataDir:     h.DataDir,
 			Bookmark:    book,
@@ -108,6 +117,8 @@ func (h *Handler) ApiInsertViaExtension(w http.ResponseWriter, r *http.Request,
 			log.Printf("failed to process bookmark: %v", err)
 		} else if _, err := h.DB.SaveBookmarks(ctx, false, book); err != nil {
 			log.Printf("error saving bookmark after downloading content: %s", err)
+		} else if book.HasArchive {
+			h.dependencies.Domains().Webhooks().Emit(ctx, model.WebhookEventBookmarkArchived, book.AccountID, model.NewWebhookBookmark(book))
 		}
 	}
 
@@ -122,7 +133,7 @@ func (h *Handler) ApiDeleteViaExtension(w http.ResponseWriter, r *http.Request,
 	ctx := r.Context()
 
 	// Make sure session still valid
-	err := h.validateSession(r)
+	account, err := h.validateSession(r)
 	checkError(err)
 
 	// Decode request
@@ -131,21 +142,24 @@ func (h *Handler) ApiDeleteViaExtension(w http.ResponseWriter, r *http.Request,
 	checkError(err)
 
 	// Check if bookmark already exists.
-	book, exist, err := h.DB.GetBookmark(ctx, 0, request.URL)
+	book, exist, err := h.DB.GetBookmark(ctx, 0, request.URL, account.ID)
 	checkError(err)
 
 	if exist {
 		// Delete bookmarks
-		err = h.DB.DeleteBookmarks(ctx, book.ID)
+		err = h.DB.DeleteBookmarks(ctx, account.ID, book.ID)
 		checkError(err)
+		h.dependencies.Domains().Webhooks().Emit(ctx, model.WebhookEventBookmarkDeleted, book.AccountID, model.NewWebhookBookmark(book))
 
 		// Delete thumbnail image and archives from local disk
 		strID := strconv.Itoa(book.ID)
 		imgPath := fp.Join(h.DataDir, "thumb", strID)
 		archivePath := fp.Join(h.DataDir, "archive", strID)
+		snapshotsDir := fp.Join(h.DataDir, model.GetArchiveSnapshotsDir(&book))
 
 		os.Remove(imgPath)
 		os.Remove(archivePath)
+		os.RemoveAll(snapshotsDir)
 	}
 
 	fmt.Fprint(w, 1)
@@ -1,7 +1,6 @@
 package webserver
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -48,7 +47,7 @@ func (h *Handler) ApiGetBookmarks(w http.ResponseWriter, r *http.Request, ps htt
 	ctx := r.Context()
 
 	// Make sure session still valid
-	err := h.validateSession(r)
+	account, err := h.validateSession(r)
 	checkError(err)
 
 	// Get URL queries
@@ -73,19 +72,21 @@ func (h *Handler) ApiGetBookmarks(w http.ResponseWriter, r *http.Request, ps htt
 	}
 
 	// Prepare filter for database
+	pageSize := h.dependencies.Config().Http.BookmarksPageSize
 	searchOptions := model.DBGetBookmarksOptions{
+		AccountID:    account.ID,
 		Tags:         tags,
 		ExcludedTags: excludedTags,
 		Keyword:      keyword,
-		Limit:        30,
-		Offset:       (page - 1) * 30,
-		OrderMethod:  model.ByLastAdded,
+		Limit:        pageSize,
+		Offset:       (page - 1) * pageSize,
+		OrderMethod:  model.ByRelevance,
 	}
 
 	// Calculate max page
 	nBookmarks, err := h.DB.GetBookmarksCount(ctx, searchOptions)
 	checkError(err)
-	maxPage := int(math.Ceil(float64(nBookmarks) / 30))
+	maxPage := int(math.Ceil(float64(nBookmarks) / float64(pageSize)))
 
 	// Fetch all matching bookmarks
 	bookmarks, err := h.DB.GetBookmarks(ctx, searchOptions)
@@ -127,11 +128,12 @@ func (h *Handler) ApiGetTags(w http.ResponseWriter, r *http.Request, ps httprout
 	ctx := r.Context()
 
 	// Make sure session still valid
-	err := h.validateSession(r)
+	account, err := h.validateSession(r)
 	checkError(err)
 
 	// Fetch all tags
 	tags, err := h.DB.GetTags(ctx, model.DBListTagsOptions{
+		AccountID:         account.ID,
 		WithBookmarkCount: true,
 	})
 	checkError(err)
@@ -146,9 +148,14 @@ func (h *Handler) ApiRenameTag(w http.ResponseWriter, r *http.Request, ps httpro
 	ctx := r.Context()
 
 	// Make sure session still valid
-	err := h.validateSession(r)
+	account, err := h.validateSession(r)
 	checkError(err)
 
+	// Tags are shared by every account, so only owners can rename them
+	if !account.IsOwner() {
+		panic(fmt.Errorf("account level is not sufficient"))
+	}
+
 	// Decode request
 	tag := model.Tag{}
 	err = json.NewDecoder(r.Body).Decode(&tag)
@@ -188,7 +195,7 @@ func (h *Handler) ApiInsertBookmark(w http.ResponseWriter, r *http.Request, ps h
 	ctx := r.Context()
 
 	// Make sure session still valid
-	err := h.validateSession(r)
+	account, err := h.validateSession(r)
 	checkError(err)
 
 	// Decode request
@@ -197,6 +204,7 @@ func (h *Handler) ApiInsertBookmark(w http.ResponseWriter, r *http.Request, ps h
 	checkError(err)
 
 	book := &model.BookmarkDTO{
+		AccountID:     account.ID,
 		URL:           payload.URL,
 		Title:         payload.Title,
 		Excerpt:       payload.Excerpt,
@@ -222,6 +230,9 @@ func (h *Handler) ApiInsertBookmark(w http.ResponseWriter, r *http.Request, ps h
 		book.Title = book.URL
 	}
 
+	// Apply the rules of the account, they can reject the bookmark
+	checkError(h.dependencies.Domains().Rules().Apply(ctx, book, ""))
+
 	// Save bookmark to database
 	results, err := h.DB.SaveBookmarks(ctx, true, *book)
 	if err != nil || len(results) == 0 {
@@ -229,18 +240,21 @@ func (h *Handler) ApiInsertBookmark(w http.ResponseWriter, r *http.Request, ps h
 	}
 
 	book = &results[0]
+	webhooks := h.dependencies.Domains().Webhooks()
+	webhooks.EmitCreatedTags(ctx, *book)
+	webhooks.Emit(ctx, model.WebhookEventBookmarkCreated, book.AccountID, model.NewWebhookBookmark(*book))
 
 	if payload.Async {
-		go func() {
-			bookmark, err := downloadBookmarkContent(h.dependencies, book, h.DataDir, r, userHasDefinedTitle, book.Excerpt != "")
-			if err != nil {
-				log.Printf("error downloading boorkmark: %s", err)
-				return
-			}
-			if _, err := h.DB.SaveBookmarks(context.Background(), false, *bookmark); err != nil {
-				log.Printf("failed to save bookmark: %s", err)
-			}
-		}()
+		_, err := h.dependencies.Domains().Jobs().Enqueue(ctx, model.JobTypeProcessBookmark, model.ProcessBookmarkJobPayload{
+			BookmarkID:    book.ID,
+			KeepTitle:     userHasDefinedTitle,
+			KeepExcerpt:   book.Excerpt != "",
+			CreateArchive: book.CreateArchive,
+			CreateEbook:   book.CreateEbook,
+		})
+		if err != nil {
+			log.Printf("failed to queue bookmark processing: %s", err)
+		}
 	} else {
 		// Workaround. Download content after saving the bookmark so we have the proper database
 		// id already set in the object regardless of the database engine.
@@ -249,6 +263,8 @@ func (h *Handler) ApiInsertBookmark(w http.ResponseWriter, r *http.Request, ps h
 			log.Printf("error downloading boorkmark: %s", err)
 		} else if _, err := h.DB.SaveBookmarks(ctx, false, *book); err != nil {
 			log.Printf("failed to save bookmark: %s", err)
+		} else if book.CreateArchive && book.HasArchive {
+			webhooks.Emit(ctx, model.WebhookEventBookmarkArchived, book.AccountID, model.NewWebhookBookmark(*book))
 		}
 	}
 
@@ -263,7 +279,7 @@ func (h *Handler) ApiDeleteBookmark(w http.ResponseWriter, r *http.Request, ps h
 	ctx := r.Context()
 
 	// Make sure session still valid
-	err := h.validateSession(r)
+	account, err := h.validateSession(r)
 	checkError(err)
 
 	// Decode request
@@ -271,20 +287,46 @@ func (h *Handler) ApiDeleteBookmark(w http.ResponseWriter, r *http.Request, ps h
 	err = json.NewDecoder(r.Body).Decode(&ids)
 	checkError(err)
 
+	// Only keep the bookmarks owned by the account, so we don't remove files of other accounts
+	var owned []model.BookmarkDTO
+	if len(ids) > 0 {
+		owned, err = h.DB.GetBookmarks(ctx, model.DBGetBookmarksOptions{
+			AccountID: account.ID,
+			IDs:       ids,
+		})
+		checkError(err)
+
+		if len(owned) == 0 {
+			fmt.Fprint(w, 1)
+			return
+		}
+
+		ids = make([]int, 0, len(owned))
+		for _, book := range owned {
+			ids = append(ids, book.ID)
+		}
+	}
+
 	// Delete bookmarks
-	err = h.DB.DeleteBookmarks(ctx, ids...)
+	err = h.DB.DeleteBookmarks(ctx, account.ID, ids...)
 	checkError(err)
 
+	for _, book := range owned {
+		h.dependencies.Domains().Webhooks().Emit(ctx, model.WebhookEventBookmarkDeleted, book.AccountID, model.NewWebhookBookmark(book))
+	}
+
 	// Delete thumbnail image and archives from local disk
 	for _, id := range ids {
 		strID := strconv.Itoa(id)
 		imgPath := fp.Join(h.DataDir, "thumb", strID)
 		archivePath := fp.Join(h.DataDir, "archive", strID)
 		ebookPath := fp.Join(h.DataDir, "ebook", strID+".epub")
+		snapshotsDir := fp.Join(h.DataDir, model.GetArchiveSnapshotsDir(&model.BookmarkDTO{ID: id}))
 
 		os.Remove(imgPath)
 		os.Remove(archivePath)
 		os.Remove(ebookPath)
+		os.RemoveAll(snapshotsDir)
 	}
 
 	fmt.Fprint(w, 1)
@@ -295,7 +337,7 @@ func (h *Handler) ApiUpdateBookmark(w http.ResponseWriter, r *http.Request, ps h
 	ctx := r.Context()
 
 	// Make sure session still valid
-	err := h.validateSession(r)
+	account, err := h.validateSession(r)
 	checkError(err)
 
 	// Decode request
@@ -310,6 +352,7 @@ func (h *Handler) ApiUpdateBookmark(w http.ResponseWriter, r *http.Request, ps h
 
 	// Get existing bookmark from database
 	filter := model.DBGetBookmarksOptions{
+		AccountID:   account.ID,
 		IDs:         []int{request.ID},
 		WithContent: true,
 	}
@@ -358,6 +401,8 @@ func (h *Handler) ApiUpdateBookmark(w http.ResponseWriter, r *http.Request, ps h
 	// Update database
 	res, err := h.DB.SaveBookmarks(ctx, false, book)
 	checkError(err)
+	h.dependencies.Domains().Webhooks().EmitCreatedTags(ctx, res...)
+	h.dependencies.Domains().Webhooks().Emit(ctx, model.WebhookEventBookmarkUpdated, res[0].AccountID, model.NewWebhookBookmark(res[0]))
 
 	// Add thumbnail image to the saved bookmarks again
 	newBook := res[0]
@@ -375,7 +420,7 @@ func (h *Handler) ApiUpdateBookmarkTags(w http.ResponseWriter, r *http.Request,
 	ctx := r.Context()
 
 	// Make sure session still valid
-	err := h.validateSession(r)
+	account, err := h.validateSession(r)
 	checkError(err)
 
 	// Decode request
@@ -394,6 +439,7 @@ func (h *Handler) ApiUpdateBookmarkTags(w http.ResponseWriter, r *http.Request,
 
 	// Get existing bookmark from database
 	filter := model.DBGetBookmarksOptions{
+		AccountID:   account.ID,
 		IDs:         request.IDs,
 		WithContent: true,
 	}
@@ -426,6 +472,11 @@ func (h *Handler) ApiUpdateBookmarkTags(w http.ResponseWriter, r *http.Request,
 	bookmarks, err = h.DB.SaveBookmarks(ctx, false, bookmarks...)
 	checkError(err)
 
+	h.dependencies.Domains().Webhooks().EmitCreatedTags(ctx, bookmarks...)
+	for _, book := range bookmarks {
+		h.dependencies.Domains().Webhooks().Emit(ctx, model.WebhookEventBookmarkUpdated, book.AccountID, model.NewWebhookBookmark(book))
+	}
+
 	// Get image URL for each bookmark
 	for i := range bookmarks {
 		strID := strconv.Itoa(bookmarks[i].ID)
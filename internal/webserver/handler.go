@@ -46,8 +46,9 @@ func (h *Handler) PrepareSessionCache() {
 	// })
 }
 
-// validateSession checks whether user session is still valid or not
-func (h *Handler) validateSession(r *http.Request) error {
+// validateSession checks whether user session is still valid or not and returns
+// the account that owns it
+func (h *Handler) validateSession(r *http.Request) (*model.AccountDTO, error) {
 	var account *model.AccountDTO
 	var err error
 
@@ -61,22 +62,17 @@ func (h *Handler) validateSession(r *http.Request) error {
 	if account == nil {
 		account, err = h.tokenAccount(r)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	if r.Method != "" && r.Method != "GET" && account.Owner != nil && !*account.Owner {
-		return fmt.Errorf("account level is not sufficient")
-	}
-
 	h.dependencies.Logger().WithFields(logrus.Fields{
 		"username": account.Username,
 		"method":   r.Method,
 		"path":     r.URL.Path,
 	}).Info("allowing legacy api access using JWT token")
 
-	return nil
-
+	return account, nil
 }
 
 func (h *Handler) tokenAccount(r *http.Request) (*model.AccountDTO, error) {
@@ -180,13 +180,14 @@ func IsIPValidAndPublic(ipAddr string) bool {
 
 // GetUserRealIP Get User Real IP from headers of request `r`
 //  1. First, determine whether the remote addr of request is a private address.
-//     If it is a public network address, return it directly;
-//  2. Otherwise, get and check the real IP from X-REAL-IP and X-Forwarded-For headers in turn.
+//     If it is a public network address, return it directly, the headers it sends can't be trusted;
+//  2. Otherwise the request comes from a reverse proxy (container or internal), get and check the
+//     real IP from X-REAL-IP and X-Forwarded-For headers in turn.
 //     if the header value contains multiple IP addresses separated by commas, that is,
-//     the request may pass through multiple reverse proxies, we just keep the first one,
-//     which imply it is the user connecting IP.
-//     then we check the value is a valid public IP address using the `IsIPValidAndPublic` function.
-//     If it is, the function returns the value as the client's real IP address.
+//     the request passed through multiple reverse proxies, the addresses are read from the last
+//     one: private addresses are proxies and are skipped, and the first public address is the
+//     user connecting IP. The addresses before it are sent by the user and can be anything.
+//     An invalid address stops the search, as the proxies only add valid ones.
 //  3. Finally, If the above headers do not exist or are invalid, the remote addr is returned as is.
 func GetUserRealIP(r *http.Request) string {
 	fallbackAddr := r.RemoteAddr
@@ -199,18 +200,35 @@ func GetUserRealIP(r *http.Request) string {
 	}
 	// in case that remote address is private(container or internal)
 	for _, hd := range userRealIpHeaderCandidates {
-		val := r.Header.Get(hd)
-		if val == "" {
-			continue
-		}
-		// remove leading or tailing comma, tab, space
-		ipAddr := strings.Trim(val, ",\t ")
-		if idxFirstIP := strings.Index(ipAddr, ","); idxFirstIP >= 0 {
-			ipAddr = ipAddr[:idxFirstIP]
-		}
-		if IsIPValidAndPublic(ipAddr) {
+		if ipAddr := lastPublicIP(r.Header.Values(hd)); ipAddr != "" {
 			return ipAddr
 		}
 	}
 	return fallbackAddr
 }
+
+// lastPublicIP returns the last public address of the comma separated header values, skipping
+// the private addresses of the proxies after it. It is empty when there is none, or when an
+// invalid address comes before it.
+func lastPublicIP(values []string) string {
+	hops := []string{}
+	for _, value := range values {
+		for _, hop := range strings.Split(value, ",") {
+			// remove leading or tailing tab, space
+			if hop = strings.Trim(hop, "\t "); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip := net.ParseIP(hops[i])
+		if ip == nil {
+			return ""
+		}
+		if !IsPrivateIP(ip) {
+			return hops[i]
+		}
+	}
+	return ""
+}
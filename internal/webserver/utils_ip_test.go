@@ -187,8 +187,8 @@ func TestGetUserRealIPWithXRealIpListHeader(t *testing.T) {
 	// Test Real IP List
 	for _, name := range userRealIpHeaderCandidates {
 		ipList := []string{"34.23.123.122", "34.23.123.123"}
-		// should equal first ip in list
-		wantIP := ipList[0]
+		// should equal last ip in list, the first one is sent by the user
+		wantIP := ipList[1]
 		// test private ip in header
 		m := map[string]string{
 			name: strings.Join(ipList, ", "),
@@ -212,6 +212,24 @@ func TestGetUserRealIPWithXRealIpHeaderIgnoreComma(t *testing.T) {
 	}
 }
 
+func TestGetUserRealIPBehindProxies(t *testing.T) {
+	wantIP := "34.23.123.124"
+	for _, name := range userRealIpHeaderCandidates {
+		// the private addresses of the proxies are skipped
+		m := map[string]string{name: "8.8.8.8, " + wantIP + ", 10.0.0.3, 192.168.1.2"}
+		testIsPublicHttpRequestAddressHelper(t, wantIP, m, true)
+
+		// a spoofed address can't hide behind an invalid one
+		m = map[string]string{name: "8.8.8.8, garbage, 10.0.0.3"}
+		testIsPublicHttpRequestAddressHelper(t, "", m, false)
+	}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Add("X-Forwarded-For", "8.8.8.8")
+	r.Header.Add("X-Forwarded-For", wantIP+", 10.0.0.3")
+	assert.Equal(t, wantIP, GetUserRealIP(r))
+}
+
 func TestGetUserRealIPWithDifferentHeaderOrder(t *testing.T) {
 	var m map[string]string
 	wantIP := "34.23.123.124"